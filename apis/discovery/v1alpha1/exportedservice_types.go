@@ -0,0 +1,85 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	mcsv1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
+)
+
+// ExportedEndpointSlice contains a set of endpoints backing an exported service, sharing the same ports.
+type ExportedEndpointSlice struct {
+	// Endpoints is the list of endpoints, whose addresses are already translated to be reachable from the destination cluster.
+	// +listType=atomic
+	Endpoints []discoveryv1.Endpoint `json:"endpoints,omitempty"`
+	// Ports is the list of ports exposed by the endpoints.
+	// +listType=atomic
+	Ports []discoveryv1.EndpointPort `json:"ports,omitempty"`
+}
+
+// ExportedServiceSpec defines the desired state of ExportedService.
+type ExportedServiceSpec struct {
+	// Namespace is the namespace of the exported service.
+	Namespace string `json:"namespace"`
+	// Name is the name of the exported service.
+	Name string `json:"name"`
+	// Type defines the type of the ServiceImport to be created in the destination cluster.
+	// +kubebuilder:validation:Enum=ClusterSetIP;Headless
+	Type mcsv1alpha1.ServiceImportType `json:"type"`
+	// Ports is the list of ports exposed by the exported service.
+	// +listType=atomic
+	Ports []mcsv1alpha1.ServicePort `json:"ports,omitempty"`
+	// SessionAffinity is the session affinity of the exported service.
+	// +optional
+	SessionAffinity corev1.ServiceAffinity `json:"sessionAffinity,omitempty"`
+	// SessionAffinityConfig contains the configuration of the session affinity of the exported service.
+	// +optional
+	SessionAffinityConfig *corev1.SessionAffinityConfig `json:"sessionAffinityConfig,omitempty"`
+	// EndpointSlices contains the (IPv4) endpoints backing the exported service.
+	// +listType=atomic
+	EndpointSlices []ExportedEndpointSlice `json:"endpointSlices,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=liqo
+
+// ExportedService is the Schema for the ExportedServices API.
+// It carries the information about a service exported through a ServiceExport towards a given remote cluster.
+// +kubebuilder:printcolumn:name="Namespace",type=string,JSONPath=`.spec.namespace`
+// +kubebuilder:printcolumn:name="Service",type=string,JSONPath=`.spec.name`
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+// +kubebuilder:printcolumn:name="Local",type=string,JSONPath=`.metadata.labels.liqo\.io/replication`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type ExportedService struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ExportedServiceSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ExportedServiceList contains a list of ExportedService.
+type ExportedServiceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ExportedService `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ExportedService{}, &ExportedServiceList{})
+}
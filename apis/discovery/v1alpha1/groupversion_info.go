@@ -49,4 +49,13 @@ var (
 
 	// ResourceRequestGroupResource is the group resource used to register ResourceRequest CRD.
 	ResourceRequestGroupResource = schema.GroupResource{Group: GroupVersion.Group, Resource: ResourceRequestResource}
+
+	// ExportedServiceResource is the resource name used to register the ExportedService CRD.
+	ExportedServiceResource = "exportedservices"
+
+	// ExportedServiceGroupVersionResource is the group version resource used to register ExportedService CRD.
+	ExportedServiceGroupVersionResource = GroupVersion.WithResource(ExportedServiceResource)
+
+	// ExportedServiceGroupResource is the group resource used to register ExportedService CRD.
+	ExportedServiceGroupResource = schema.GroupResource{Group: GroupVersion.Group, Resource: ExportedServiceResource}
 )
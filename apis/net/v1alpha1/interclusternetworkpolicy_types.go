@@ -0,0 +1,89 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// InterClusterNetworkPolicySpec defines the desired state of InterClusterNetworkPolicy.
+type InterClusterNetworkPolicySpec struct {
+	// PodSelector selects the local pods, in the namespace of the policy, the policy applies to.
+	// An empty selector selects all the pods in the namespace.
+	// Selected pods are isolated towards the remote clusters, and they can reach only the destinations
+	// allowed by the union of the egress rules of the policies selecting them.
+	PodSelector metav1.LabelSelector `json:"podSelector"`
+	// Egress is the list of the destinations in the remote clusters the selected pods are allowed to reach.
+	// +optional
+	Egress []InterClusterEgressRule `json:"egress,omitempty"`
+}
+
+// InterClusterEgressRule describes the destinations in a remote cluster the selected pods are allowed to reach.
+type InterClusterEgressRule struct {
+	// ClusterID is the ID of the remote cluster the rule refers to.
+	// +kubebuilder:validation:MinLength=1
+	ClusterID string `json:"clusterID"`
+	// CIDRs is the list of the allowed destination networks, as seen from the local cluster
+	// (i.e., possibly remapped). If empty, all the pods and external CIDRs of the remote cluster are allowed.
+	// +optional
+	CIDRs []string `json:"cidrs,omitempty"`
+	// Ports is the list of the allowed destination ports. If empty, all ports are allowed.
+	// +optional
+	Ports []InterClusterPolicyPort `json:"ports,omitempty"`
+}
+
+// InterClusterPolicyPort describes a destination port allowed by an inter-cluster network policy.
+type InterClusterPolicyPort struct {
+	// Protocol is the protocol (TCP, UDP or SCTP) of the allowed traffic.
+	// +kubebuilder:validation:Enum=TCP;UDP;SCTP
+	// +kubebuilder:default=TCP
+	Protocol corev1.Protocol `json:"protocol,omitempty"`
+	// Port is the allowed destination port. If not set, all ports of the given protocol are allowed.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+}
+
+// InterClusterNetworkPolicyStatus defines the observed state of InterClusterNetworkPolicy.
+type InterClusterNetworkPolicyStatus struct{}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:categories=liqo,shortName=icnp
+
+// InterClusterNetworkPolicy is the Schema for the interclusternetworkpolicies API,
+// which restricts the traffic from the local pods towards the remote clusters.
+type InterClusterNetworkPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   InterClusterNetworkPolicySpec   `json:"spec,omitempty"`
+	Status InterClusterNetworkPolicyStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// InterClusterNetworkPolicyList contains a list of InterClusterNetworkPolicy.
+type InterClusterNetworkPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []InterClusterNetworkPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&InterClusterNetworkPolicy{}, &InterClusterNetworkPolicyList{})
+}
@@ -34,6 +34,27 @@ type Subnets struct {
 	LocalNATExternalCIDR string `json:"localNATExternalCIDR"`
 	// Network used in local cluster for remote service endpoints.
 	RemoteExternalCIDR string `json:"remoteExternalCIDR"`
+	// IPv6 network used in the remote cluster for local Pods, in case of dual-stack clusters.
+	// Default is "None": this means remote cluster uses local cluster IPv6 PodCIDR.
+	LocalNATPodCIDRv6 string `json:"localNATPodCIDRv6,omitempty"`
+	// IPv6 network used for Pods in the remote cluster, in case of dual-stack clusters.
+	RemotePodCIDRv6 string `json:"remotePodCIDRv6,omitempty"`
+	// IPv6 network used in remote cluster for local service endpoints, in case of dual-stack clusters.
+	// Default is "None": this means remote cluster uses local cluster IPv6 ExternalCIDR.
+	LocalNATExternalCIDRv6 string `json:"localNATExternalCIDRv6,omitempty"`
+	// IPv6 network used in local cluster for remote service endpoints, in case of dual-stack clusters.
+	RemoteExternalCIDRv6 string `json:"remoteExternalCIDRv6,omitempty"`
+	// Networks used in local cluster for the pods of the clusters reachable through the remote one, acting as a transit
+	// cluster. Key is the ID of the cluster the network belongs to.
+	TransitSubnets map[string]TransitSubnet `json:"transitSubnets,omitempty"`
+}
+
+// TransitSubnet contains the networks related to a cluster reachable through a transit cluster.
+type TransitSubnet struct {
+	// Network used for the pods of the cluster, as seen by the transit cluster.
+	PodCIDR string `json:"podCIDR"`
+	// Network used in local cluster for the pods of the cluster.
+	MappedPodCIDR string `json:"mappedPodCIDR"`
 }
 
 // ClusterMapping is an empty struct.
@@ -80,6 +101,12 @@ type IpamSpec struct {
 	PodCIDR string `json:"podCIDR"`
 	// ServiceCIDR
 	ServiceCIDR string `json:"serviceCIDR"`
+	// Cluster IPv6 ExternalCIDR, set in case of dual-stack clusters.
+	ExternalCIDRv6 string `json:"externalCIDRv6,omitempty"`
+	// Cluster IPv6 PodCIDR, set in case of dual-stack clusters.
+	PodCIDRv6 string `json:"podCIDRv6,omitempty"`
+	// IPv6 ServiceCIDR, set in case of dual-stack clusters.
+	ServiceCIDRv6 string `json:"serviceCIDRv6,omitempty"`
 }
 
 // +kubebuilder:object:root=true
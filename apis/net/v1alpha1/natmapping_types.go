@@ -34,6 +34,12 @@ type NatMappingSpec struct {
 	// ExternalCIDR is the ExternalCIDR used in the remote cluster for local exported resource.
 	// It can be either the LocalExternalCIDR or the LocalNATExternalCIDR.
 	ExternalCIDR string `json:"externalCIDR"`
+	// PodCIDRv6 is the IPv6 network used for remote pods in the local cluster, in case of dual-stack clusters.
+	// It can be either the RemotePodCIDRv6 or the RemoteNATPodCIDRv6.
+	PodCIDRv6 string `json:"podCIDRv6,omitempty"`
+	// ExternalCIDRv6 is the IPv6 ExternalCIDR used in the remote cluster for local exported resource, in case of dual-stack clusters.
+	// It can be either the LocalExternalCIDRv6 or the LocalNATExternalCIDRv6.
+	ExternalCIDRv6 string `json:"externalCIDRv6,omitempty"`
 	// ClusterMappings is the set of NAT mappings currently active.
 	ClusterMappings Mappings `json:"clusterMappings"`
 }
@@ -37,12 +37,29 @@ type NetworkConfigSpec struct {
 	PodCIDR string `json:"podCIDR"`
 	// Network used for local service endpoints.
 	ExternalCIDR string `json:"externalCIDR"`
+	// IPv6 network used in the local cluster for the pod IPs, in case of dual-stack clusters.
+	// +kubebuilder:validation:Optional
+	PodCIDRv6 string `json:"podCIDRv6,omitempty"`
+	// IPv6 network used for local service endpoints, in case of dual-stack clusters.
+	// +kubebuilder:validation:Optional
+	ExternalCIDRv6 string `json:"externalCIDRv6,omitempty"`
 	// Public IP of the node where the VPN tunnel is created.
 	EndpointIP string `json:"endpointIP"`
 	// Vpn technology used to interconnect two clusters.
 	BackendType string `json:"backendType"`
 	// Connection parameters
 	BackendConfig map[string]string `json:"backend_config"`
+	// Networks of the other clusters reachable through the local one, acting as a transit (hub) cluster.
+	// +kubebuilder:validation:Optional
+	TransitNetworks []TransitNetwork `json:"transitNetworks,omitempty"`
+}
+
+// TransitNetwork defines a network of a cluster reachable through a transit (hub) cluster.
+type TransitNetwork struct {
+	// The identity of the cluster the network belongs to.
+	ClusterIdentity discoveryv1alpha1.ClusterIdentity `json:"clusterIdentity"`
+	// Network used for the pods of the given cluster, as seen by the transit cluster.
+	PodCIDR string `json:"podCIDR"`
 }
 
 // NetworkConfigStatus defines the observed state of NetworkConfig.
@@ -59,6 +76,13 @@ type NetworkConfigStatus struct {
 	// The new subnet used to NAT the externalCIDR of the remote cluster. The original ExternalCIDR may have been mapped
 	// to this network by the remote cluster.
 	ExternalCIDRNAT string `json:"externalCIDRNAT,omitempty"`
+	// The new subnet used to NAT the IPv6 podCidr of the remote cluster, in case of dual-stack clusters.
+	PodCIDRNATv6 string `json:"podCIDRNATv6,omitempty"`
+	// The new subnet used to NAT the IPv6 externalCIDR of the remote cluster, in case of dual-stack clusters.
+	ExternalCIDRNATv6 string `json:"externalCIDRNATv6,omitempty"`
+	// The subnets used to NAT the transit networks announced by the remote cluster. Key is the ID of the cluster
+	// the network belongs to, value is the new subnet, or "None" in case the network has not been remapped.
+	TransitNetworksNAT map[string]string `json:"transitNetworksNAT,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -57,12 +57,57 @@ type TunnelEndpointSpec struct {
 	// +kubebuilder:validation:Optional
 	RemoteNATExternalCIDR string `json:"remoteNATExternalCIDR"`
 
+	// IPv6 PodCIDR of local cluster, in case of dual-stack clusters.
+	// +kubebuilder:validation:Optional
+	LocalPodCIDRv6 string `json:"localPodCIDRv6,omitempty"`
+	// IPv6 network used in the remote cluster to map the local IPv6 PodCIDR, in case of conflicts (in the remote cluster).
+	// +kubebuilder:validation:Optional
+	LocalNATPodCIDRv6 string `json:"localNATPodCIDRv6,omitempty"`
+	// IPv6 ExternalCIDR of local cluster, in case of dual-stack clusters.
+	// +kubebuilder:validation:Optional
+	LocalExternalCIDRv6 string `json:"localExternalCIDRv6,omitempty"`
+	// IPv6 network used in the remote cluster to map the local IPv6 ExternalCIDR, in case of conflicts (in the remote cluster).
+	// +kubebuilder:validation:Optional
+	LocalNATExternalCIDRv6 string `json:"localNATExternalCIDRv6,omitempty"`
+
+	// IPv6 PodCIDR of remote cluster, in case of dual-stack clusters.
+	// +kubebuilder:validation:Optional
+	RemotePodCIDRv6 string `json:"remotePodCIDRv6,omitempty"`
+	// IPv6 network used in the local cluster to map the remote cluster IPv6 PodCIDR, in case of conflicts with RemotePodCIDRv6.
+	// +kubebuilder:validation:Optional
+	RemoteNATPodCIDRv6 string `json:"remoteNATPodCIDRv6,omitempty"`
+	// IPv6 ExternalCIDR of remote cluster, in case of dual-stack clusters.
+	// +kubebuilder:validation:Optional
+	RemoteExternalCIDRv6 string `json:"remoteExternalCIDRv6,omitempty"`
+	// IPv6 network used in the local cluster to map the remote cluster IPv6 ExternalCIDR, in case of conflicts with RemoteExternalCIDRv6.
+	// +kubebuilder:validation:Optional
+	RemoteNATExternalCIDRv6 string `json:"remoteNATExternalCIDRv6,omitempty"`
+
 	// Public IP of the node where the VPN tunnel is created.
 	EndpointIP string `json:"endpointIP"`
 	// Vpn technology used to interconnect two clusters.
 	BackendType string `json:"backendType"`
 	// Connection parameters.
 	BackendConfig map[string]string `json:"backend_config"`
+
+	// Networks of other clusters reachable through the remote cluster, acting as a transit (hub) cluster.
+	// +kubebuilder:validation:Optional
+	RemoteTransitNetworks []TransitNetworkMapping `json:"remoteTransitNetworks,omitempty"`
+	// Networks of other clusters reachable through the local cluster, acting as a transit (hub) cluster for the remote one.
+	// +kubebuilder:validation:Optional
+	LocalTransitNetworks []TransitNetworkMapping `json:"localTransitNetworks,omitempty"`
+}
+
+// TransitNetworkMapping defines a network of a cluster reachable through a transit (hub) cluster, along with its remapping.
+type TransitNetworkMapping struct {
+	// The identity of the cluster the network belongs to.
+	ClusterIdentity discv1alpha1.ClusterIdentity `json:"clusterIdentity"`
+	// Network used for the pods of the given cluster, as seen by the transit cluster.
+	PodCIDR string `json:"podCIDR"`
+	// Network used in the spoke cluster to map the PodCIDR, in case of conflicts (in the spoke cluster).
+	// +kubebuilder:default="None"
+	// +kubebuilder:validation:Optional
+	NATPodCIDR string `json:"natPodCIDR"`
 }
 
 // TunnelEndpointStatus defines the observed state of TunnelEndpoint.
@@ -73,12 +118,40 @@ type TunnelEndpointStatus struct {
 	VethIFaceName    string     `json:"vethIFaceName,omitempty"`
 	VethIP           string     `json:"vethIP,omitempty"`
 	GatewayIP        string     `json:"gatewayIP,omitempty"`
+	GatewayIPv6      string     `json:"gatewayIPv6,omitempty"`
 	Connection       Connection `json:"connection,omitempty"`
+	// The status of each gateway replica, in case the gateway is executed in hot-standby mode.
+	// In this case, the other fields refer to the replica in charge of the tunnel, which is preferably a connected one.
+	Replicas []GatewayReplicaStatus `json:"replicas,omitempty"`
+}
+
+// GatewayReplicaStatus defines the observed state of a single replica of the gateway, in case of hot-standby mode.
+type GatewayReplicaStatus struct {
+	// The name of the gateway pod.
+	Name string `json:"name"`
+	// The IP address of the gateway pod.
+	GatewayIP string `json:"gatewayIP"`
+	// The IPv6 address of the gateway pod, in case of dual-stack clusters.
+	GatewayIPv6 string `json:"gatewayIPv6,omitempty"`
+	// The index of the veth interface connecting the host to the gateway network namespace.
+	VethIFaceIndex int `json:"vethIFaceIndex,omitempty"`
+	// The name of the veth interface connecting the host to the gateway network namespace.
+	VethIFaceName string `json:"vethIFaceName,omitempty"`
+	// The status of the connection towards the remote cluster established by the replica.
+	Connection Connection `json:"connection,omitempty"`
 }
 
 // ConnectionLatency represents the latency between two clusters.
 type ConnectionLatency struct {
-	Value     string      `json:"value,omitempty"`
+	Value string `json:"value,omitempty"`
+	// Jitter is the mean variation of the latency over the most recent pings.
+	Jitter string `json:"jitter,omitempty"`
+	// P50 is the median latency over the most recent pings.
+	P50 string `json:"p50,omitempty"`
+	// P90 is the 90th percentile of the latency over the most recent pings.
+	P90 string `json:"p90,omitempty"`
+	// P99 is the 99th percentile of the latency over the most recent pings.
+	P99       string      `json:"p99,omitempty"`
 	Timestamp metav1.Time `json:"timestamp,omitempty"`
 }
 
@@ -88,6 +161,11 @@ type Connection struct {
 	StatusMessage     string            `json:"statusMessage,omitempty"`
 	PeerConfiguration map[string]string `json:"peerConfiguration,omitempty"`
 	Latency           ConnectionLatency `json:"latency,omitempty"`
+	// PacketLoss is the percentage of pings lost over the most recent ones.
+	PacketLoss string `json:"packetLoss,omitempty"`
+	// PathMTU is the MTU discovered on the path towards the remote cluster, enforced on the routes towards it.
+	// It is not set if the path MTU discovery is disabled or failed, in which case the MTU of the tunnel interface applies.
+	PathMTU int `json:"pathMTU,omitempty"`
 }
 
 // ConnectionStatus type that describes the status of vpn connection with a remote cluster.
@@ -117,6 +195,7 @@ const (
 // +kubebuilder:printcolumn:name="Endpoint IP",type=string,JSONPath=`.spec.endpointIP`,priority=1
 // +kubebuilder:printcolumn:name="Backend type",type=string,JSONPath=`.spec.backendType`
 // +kubebuilder:printcolumn:name="Latency",type=string,JSONPath=`.status.connection.latency.value`,priority=1
+// +kubebuilder:printcolumn:name="Packet loss",type=string,JSONPath=`.status.connection.packetLoss`,priority=1
 // +kubebuilder:printcolumn:name="Connection status",type=string,JSONPath=`.status.connection.status`
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 type TunnelEndpoint struct {
@@ -113,6 +113,159 @@ func (in *EndpointMapping) DeepCopy() *EndpointMapping {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayReplicaStatus) DeepCopyInto(out *GatewayReplicaStatus) {
+	*out = *in
+	in.Connection.DeepCopyInto(&out.Connection)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayReplicaStatus.
+func (in *GatewayReplicaStatus) DeepCopy() *GatewayReplicaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayReplicaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterClusterEgressRule) DeepCopyInto(out *InterClusterEgressRule) {
+	*out = *in
+	if in.CIDRs != nil {
+		in, out := &in.CIDRs, &out.CIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]InterClusterPolicyPort, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterClusterEgressRule.
+func (in *InterClusterEgressRule) DeepCopy() *InterClusterEgressRule {
+	if in == nil {
+		return nil
+	}
+	out := new(InterClusterEgressRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterClusterNetworkPolicy) DeepCopyInto(out *InterClusterNetworkPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterClusterNetworkPolicy.
+func (in *InterClusterNetworkPolicy) DeepCopy() *InterClusterNetworkPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(InterClusterNetworkPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InterClusterNetworkPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterClusterNetworkPolicyList) DeepCopyInto(out *InterClusterNetworkPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]InterClusterNetworkPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterClusterNetworkPolicyList.
+func (in *InterClusterNetworkPolicyList) DeepCopy() *InterClusterNetworkPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(InterClusterNetworkPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InterClusterNetworkPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterClusterNetworkPolicySpec) DeepCopyInto(out *InterClusterNetworkPolicySpec) {
+	*out = *in
+	in.PodSelector.DeepCopyInto(&out.PodSelector)
+	if in.Egress != nil {
+		in, out := &in.Egress, &out.Egress
+		*out = make([]InterClusterEgressRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterClusterNetworkPolicySpec.
+func (in *InterClusterNetworkPolicySpec) DeepCopy() *InterClusterNetworkPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InterClusterNetworkPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterClusterNetworkPolicyStatus) DeepCopyInto(out *InterClusterNetworkPolicyStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterClusterNetworkPolicyStatus.
+func (in *InterClusterNetworkPolicyStatus) DeepCopy() *InterClusterNetworkPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InterClusterNetworkPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterClusterPolicyPort) DeepCopyInto(out *InterClusterPolicyPort) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterClusterPolicyPort.
+func (in *InterClusterPolicyPort) DeepCopy() *InterClusterPolicyPort {
+	if in == nil {
+		return nil
+	}
+	out := new(InterClusterPolicyPort)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IpamSpec) DeepCopyInto(out *IpamSpec) {
 	*out = *in
@@ -146,7 +299,7 @@ func (in *IpamSpec) DeepCopyInto(out *IpamSpec) {
 		in, out := &in.ClusterSubnets, &out.ClusterSubnets
 		*out = make(map[string]Subnets, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = *val.DeepCopy()
 		}
 	}
 	if in.EndpointMappings != nil {
@@ -356,7 +509,7 @@ func (in *NetworkConfig) DeepCopyInto(out *NetworkConfig) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfig.
@@ -420,6 +573,11 @@ func (in *NetworkConfigSpec) DeepCopyInto(out *NetworkConfigSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.TransitNetworks != nil {
+		in, out := &in.TransitNetworks, &out.TransitNetworks
+		*out = make([]TransitNetwork, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfigSpec.
@@ -435,6 +593,13 @@ func (in *NetworkConfigSpec) DeepCopy() *NetworkConfigSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkConfigStatus) DeepCopyInto(out *NetworkConfigStatus) {
 	*out = *in
+	if in.TransitNetworksNAT != nil {
+		in, out := &in.TransitNetworksNAT, &out.TransitNetworksNAT
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfigStatus.
@@ -450,6 +615,13 @@ func (in *NetworkConfigStatus) DeepCopy() *NetworkConfigStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Subnets) DeepCopyInto(out *Subnets) {
 	*out = *in
+	if in.TransitSubnets != nil {
+		in, out := &in.TransitSubnets, &out.TransitSubnets
+		*out = make(map[string]TransitSubnet, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Subnets.
@@ -462,6 +634,53 @@ func (in *Subnets) DeepCopy() *Subnets {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransitNetwork) DeepCopyInto(out *TransitNetwork) {
+	*out = *in
+	out.ClusterIdentity = in.ClusterIdentity
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransitNetwork.
+func (in *TransitNetwork) DeepCopy() *TransitNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(TransitNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransitNetworkMapping) DeepCopyInto(out *TransitNetworkMapping) {
+	*out = *in
+	out.ClusterIdentity = in.ClusterIdentity
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransitNetworkMapping.
+func (in *TransitNetworkMapping) DeepCopy() *TransitNetworkMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(TransitNetworkMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransitSubnet) DeepCopyInto(out *TransitSubnet) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransitSubnet.
+func (in *TransitSubnet) DeepCopy() *TransitSubnet {
+	if in == nil {
+		return nil
+	}
+	out := new(TransitSubnet)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TunnelEndpoint) DeepCopyInto(out *TunnelEndpoint) {
 	*out = *in
@@ -532,6 +751,16 @@ func (in *TunnelEndpointSpec) DeepCopyInto(out *TunnelEndpointSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.RemoteTransitNetworks != nil {
+		in, out := &in.RemoteTransitNetworks, &out.RemoteTransitNetworks
+		*out = make([]TransitNetworkMapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.LocalTransitNetworks != nil {
+		in, out := &in.LocalTransitNetworks, &out.LocalTransitNetworks
+		*out = make([]TransitNetworkMapping, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TunnelEndpointSpec.
@@ -548,6 +777,13 @@ func (in *TunnelEndpointSpec) DeepCopy() *TunnelEndpointSpec {
 func (in *TunnelEndpointStatus) DeepCopyInto(out *TunnelEndpointStatus) {
 	*out = *in
 	in.Connection.DeepCopyInto(&out.Connection)
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]GatewayReplicaStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TunnelEndpointStatus.
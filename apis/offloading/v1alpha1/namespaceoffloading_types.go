@@ -62,6 +62,9 @@ const (
 	// LocalAndRemotePodOffloadingStrategyType -> the pods in this namespace can be scheduled on both the local
 	// and remote clusters, the latter possibly filtered through the ClusterSelector field.
 	LocalAndRemotePodOffloadingStrategyType PodOffloadingStrategyType = "LocalAndRemote"
+	// WeightedPodOffloadingStrategyType -> the pods in this namespace are spread across the local cluster and the
+	// remote ones (the latter possibly filtered through the ClusterSelector field), according to the configured weights.
+	WeightedPodOffloadingStrategyType PodOffloadingStrategyType = "Weighted"
 )
 
 // RemoteNamespaceConditionType represents different conditions that a remote namespace could assume.
@@ -102,11 +105,11 @@ type NamespaceOffloadingSpec struct {
 	// +kubebuilder:validation:Optional
 	NamespaceMappingStrategy NamespaceMappingStrategyType `json:"namespaceMappingStrategy"`
 
-	// PodOffloadingStrategy allows users to configure how pods in this namespace are offloaded, according to three
+	// PodOffloadingStrategy allows users to configure how pods in this namespace are offloaded, according to four
 	// different strategies: "Local" (i.e. no pod offloading is performed), "Remote" (i.e. all pods are offloaded
 	// in remote clusters), "LocalAndRemote" (i.e. no constraints are enforced besides the ones
-	// specified by the ClusterSelector).
-	// +kubebuilder:validation:Enum="Local";"Remote";"LocalAndRemote"
+	// specified by the ClusterSelector) and "Weighted" (i.e. pods are spread according to the Weights field).
+	// +kubebuilder:validation:Enum="Local";"Remote";"LocalAndRemote";"Weighted"
 	// +kubebuilder:default="LocalAndRemote"
 	// +kubebuilder:validation:Optional
 	PodOffloadingStrategy PodOffloadingStrategyType `json:"podOffloadingStrategy"`
@@ -116,6 +119,51 @@ type NamespaceOffloadingSpec struct {
 	// (https://kubernetes.io/docs/concepts/scheduling-eviction/assign-pod-node/#node-affinity).
 	// A cluster selector with no NodeSelectorTerms matches all clusters.
 	ClusterSelector corev1.NodeSelector `json:"clusterSelector,omitempty"`
+
+	// Quota optionally limits the resources the pods of this namespace may consume in the remote clusters,
+	// both overall and per cluster. Resources are accounted based on the container limits, hence pods
+	// are allowed to be offloaded only if they specify a limit for all the resources subject to a quota.
+	// +kubebuilder:validation:Optional
+	Quota *OffloadingQuota `json:"quota,omitempty"`
+
+	// Weights defines the relative share of the pods of this namespace to be executed in the local cluster and in
+	// each remote cluster, when the "Weighted" PodOffloadingStrategy is selected. Clusters not assigned a positive
+	// weight are not selected as targets for pod offloading.
+	// +kubebuilder:validation:Optional
+	Weights *OffloadingWeights `json:"weights,omitempty"`
+}
+
+// OffloadingWeights defines the relative share of pods to be executed in the local cluster and in each remote cluster.
+type OffloadingWeights struct {
+	// Local is the weight of the local cluster.
+	// +kubebuilder:validation:Minimum=0
+	Local int32 `json:"local,omitempty"`
+	// Remote are the weights of the remote clusters, keyed by cluster ID.
+	Remote map[string]int32 `json:"remote,omitempty"`
+}
+
+// OffloadingDistribution reports the number of pods executed in the local cluster and in each remote cluster.
+type OffloadingDistribution struct {
+	// Local is the number of pods executed in the local cluster.
+	Local int32 `json:"local"`
+	// Remote is the number of pods executed in each remote cluster, keyed by cluster ID.
+	Remote map[string]int32 `json:"remote,omitempty"`
+}
+
+// OffloadingQuota defines the maximum amount of resources the pods of a namespace may consume in the remote clusters.
+type OffloadingQuota struct {
+	// Aggregate is the maximum amount of resources which may be consumed overall, across all remote clusters.
+	Aggregate corev1.ResourceList `json:"aggregate,omitempty"`
+	// PerCluster is the maximum amount of resources which may be consumed in each remote cluster, keyed by cluster ID.
+	PerCluster map[string]corev1.ResourceList `json:"perCluster,omitempty"`
+}
+
+// OffloadingQuotaUsage reports the amount of resources currently consumed in the remote clusters by the pods of a namespace.
+type OffloadingQuotaUsage struct {
+	// Aggregate is the amount of resources consumed overall, across all remote clusters.
+	Aggregate corev1.ResourceList `json:"aggregate,omitempty"`
+	// PerCluster is the amount of resources consumed in each remote cluster, keyed by cluster ID.
+	PerCluster map[string]corev1.ResourceList `json:"perCluster,omitempty"`
 }
 
 // NamespaceOffloadingStatus defines the observed state of NamespaceOffloading.
@@ -133,6 +181,12 @@ type NamespaceOffloadingStatus struct {
 	// RemoteNamespacesConditions -> allows user to verify remote Namespaces' presence and status on all remote
 	// clusters through RemoteNamespaceCondition.
 	RemoteNamespacesConditions map[string]RemoteNamespaceConditions `json:"remoteNamespacesConditions,omitempty"`
+	// QuotaUsage reports the resources currently consumed in the remote clusters by the pods of this namespace,
+	// accounted according to the quota (if specified).
+	QuotaUsage *OffloadingQuotaUsage `json:"quotaUsage,omitempty"`
+	// Distribution reports the number of running pods of this namespace executed in the local cluster and
+	// in each remote cluster, in case the "Weighted" PodOffloadingStrategy is selected.
+	Distribution *OffloadingDistribution `json:"distribution,omitempty"`
 	// The generation observed by the NamespaceOffloading controller.
 	// This field allows external tools (e.g., liqoctl) to detect whether a spec modification has already been processed
 	// or not (i.e., whether the status should be expected to be up-to-date or not), and thus act accordingly.
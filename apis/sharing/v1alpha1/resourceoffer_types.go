@@ -47,6 +47,8 @@ type ResourceOfferSpec struct {
 	ResourceQuota corev1.ResourceQuotaSpec `json:"resourceQuota,omitempty"`
 	// Labels contains the label to be added to the virtual node.
 	Labels map[string]string `json:"labels,omitempty"`
+	// Taints contains the taints to be added to the virtual node.
+	Taints []corev1.Taint `json:"taints,omitempty"`
 	// Prices contains the possible prices for every kind of resource (cpu, memory, image).
 	Prices corev1.ResourceList `json:"prices,omitempty"`
 	// WithdrawalTimestamp is set when a graceful deletion is requested by the user.
@@ -0,0 +1,93 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ReflectionPolicyAction defines whether the selected objects shall be reflected or not.
+type ReflectionPolicyAction string
+
+const (
+	// ReflectionPolicyActionReflect indicates that the selected objects shall be reflected.
+	ReflectionPolicyActionReflect ReflectionPolicyAction = "Reflect"
+	// ReflectionPolicyActionSkip indicates that the selected objects shall not be reflected.
+	ReflectionPolicyActionSkip ReflectionPolicyAction = "Skip"
+)
+
+// KeyRename defines the rename of a label (or annotation) key when reflected to the remote cluster.
+type KeyRename struct {
+	// From is the key in the local cluster.
+	From string `json:"from"`
+	// To is the key in the remote cluster.
+	To string `json:"to"`
+}
+
+// MetadataRules defines the rules concerning the reflection of labels (or annotations).
+type MetadataRules struct {
+	// Skip is the list of keys which are not reflected to the remote cluster.
+	Skip []string `json:"skip,omitempty"`
+	// Rename is the list of keys which are renamed when reflected to the remote cluster.
+	Rename []KeyRename `json:"rename,omitempty"`
+}
+
+// ReflectionPolicySpec defines the desired state of ReflectionPolicy.
+type ReflectionPolicySpec struct {
+	// Resources is the list of resources (e.g., service, configmap, secret) the policy applies to.
+	// Custom resources are identified by their group resource (e.g., certificates.cert-manager.io).
+	// If empty, the policy applies to all the reflected resources.
+	Resources []string `json:"resources,omitempty"`
+	// Selector selects the objects the policy applies to, based on their labels.
+	// If not specified, the policy applies to all the objects in the namespace.
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Action defines whether the selected objects shall be reflected or not, overriding the reflection type
+	// configured for the given resource. Objects featuring the explicit skip or allow annotations are not affected.
+	// If not specified, the reflection type configured for the given resource applies.
+	// +kubebuilder:validation:Enum="Reflect";"Skip"
+	Action ReflectionPolicyAction `json:"action,omitempty"`
+	// Labels defines the rules concerning the reflection of the labels of the selected objects.
+	Labels MetadataRules `json:"labels,omitempty"`
+	// Annotations defines the rules concerning the reflection of the annotations of the selected objects.
+	Annotations MetadataRules `json:"annotations,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=liqo,shortName="rpol"
+// +genclient
+
+// ReflectionPolicy is the Schema for the ReflectionPolicies API.
+// +kubebuilder:printcolumn:name="Action",type=string,JSONPath=`.spec.action`
+// +kubebuilder:printcolumn:name="Resources",type=string,JSONPath=`.spec.resources`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type ReflectionPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ReflectionPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ReflectionPolicyList contains a list of ReflectionPolicy.
+type ReflectionPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ReflectionPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ReflectionPolicy{}, &ReflectionPolicyList{})
+}
@@ -0,0 +1,117 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main contains the entrypoint of the clusterset DNS server, resolving the names
+// of the services available across the peered clusters.
+package main
+
+import (
+	"flag"
+	"os"
+
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	mcsv1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	"github.com/liqotech/liqo/pkg/clustersetdns"
+	serviceexportctrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/serviceexport-controller"
+	"github.com/liqotech/liqo/pkg/utils/mapper"
+	"github.com/liqotech/liqo/pkg/utils/restcfg"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+)
+
+// cluster-role
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
+// +kubebuilder:rbac:groups=multicluster.x-k8s.io,resources=serviceimports,verbs=get;list;watch
+// +kubebuilder:rbac:groups=discovery.liqo.io,resources=foreignclusters,verbs=get;list;watch
+
+func main() {
+	address := flag.String("address", ":5353", "The address the DNS server listens on (both UDP and TCP)")
+	domain := flag.String("domain", clustersetdns.DefaultDomain, "The clusterset domain the DNS server is authoritative for")
+	ttl := flag.Uint("ttl", 5, "The time-to-live (in seconds) of the returned records")
+	probeAddr := flag.String("health-probe-bind-address", ":8081", "The address the health probe endpoint binds to")
+
+	klog.InitFlags(nil)
+	restcfg.InitFlags(nil)
+	flag.Parse()
+
+	log.SetLogger(klog.NewKlogr())
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(discoveryv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(mcsv1alpha1.AddToScheme(scheme))
+
+	// Cache only the EndpointSlices originated from the remote clusters.
+	reqRemoteEndpointSlices, err := labels.NewRequirement(discoveryv1.LabelManagedBy, selection.In,
+		[]string{serviceexportctrl.EndpointSliceManagedBy, forge.EndpointSliceManagedBy})
+	utilruntime.Must(err)
+
+	mgr, err := ctrl.NewManager(restcfg.SetRateLimiter(ctrl.GetConfigOrDie()), ctrl.Options{
+		MapperProvider:         mapper.LiqoMapperProvider(scheme),
+		Scheme:                 scheme,
+		MetricsBindAddress:     "0",
+		HealthProbeBindAddress: *probeAddr,
+		NewCache: func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+			opts.ByObject = map[client.Object]cache.ByObject{
+				&discoveryv1.EndpointSlice{}: {
+					Label: labels.NewSelector().Add(*reqRemoteEndpointSlices),
+				},
+			}
+			return cache.New(config, opts)
+		},
+	})
+	if err != nil {
+		klog.Errorf("Unable to create the manager: %v", err)
+		os.Exit(1)
+	}
+
+	server := &clustersetdns.Server{
+		Resolver: &clustersetdns.Resolver{Client: mgr.GetClient()},
+		Address:  *address,
+		Domain:   *domain,
+		TTL:      uint32(*ttl),
+	}
+	if err := mgr.Add(server); err != nil {
+		klog.Errorf("Unable to add the DNS server to the manager: %v", err)
+		os.Exit(1)
+	}
+
+	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
+		klog.Errorf("Unable to set up the health check: %v", err)
+		os.Exit(1)
+	}
+	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+		klog.Errorf("Unable to set up the ready check: %v", err)
+		os.Exit(1)
+	}
+
+	klog.Info("Starting the clusterset DNS server")
+	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+		klog.Errorf("Manager exited with error: %v", err)
+		os.Exit(1)
+	}
+}
@@ -16,6 +16,7 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
 	"fmt"
@@ -25,6 +26,8 @@ import (
 	"sync"
 	"time"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 	certificates "k8s.io/api/certificates/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/fields"
@@ -45,6 +48,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	mcsv1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
 	"sigs.k8s.io/sig-storage-lib-external-provisioner/v7/controller"
 
 	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
@@ -63,6 +67,7 @@ import (
 	resourceRequestOperator "github.com/liqotech/liqo/pkg/liqo-controller-manager/resource-request-controller"
 	resourcemonitors "github.com/liqotech/liqo/pkg/liqo-controller-manager/resource-request-controller/resource-monitors"
 	resourceoffercontroller "github.com/liqotech/liqo/pkg/liqo-controller-manager/resourceoffer-controller"
+	serviceexportctrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/serviceexport-controller"
 	shadowepsctrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/shadowendpointslice-controller"
 	shadowpodctrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/shadowpod-controller"
 	liqostorageprovisioner "github.com/liqotech/liqo/pkg/liqo-controller-manager/storageprovisioner"
@@ -72,6 +77,7 @@ import (
 	podwh "github.com/liqotech/liqo/pkg/liqo-controller-manager/webhooks/pod"
 	shadowpodswh "github.com/liqotech/liqo/pkg/liqo-controller-manager/webhooks/shadowpod"
 	virtualnodewh "github.com/liqotech/liqo/pkg/liqo-controller-manager/webhooks/virtualnode"
+	"github.com/liqotech/liqo/pkg/liqonet/ipam"
 	peeringroles "github.com/liqotech/liqo/pkg/peering-roles"
 	tenantnamespace "github.com/liqotech/liqo/pkg/tenantNamespace"
 	argsutils "github.com/liqotech/liqo/pkg/utils/args"
@@ -80,6 +86,7 @@ import (
 	"github.com/liqotech/liqo/pkg/utils/indexer"
 	"github.com/liqotech/liqo/pkg/utils/mapper"
 	"github.com/liqotech/liqo/pkg/utils/restcfg"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/custom"
 	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/generic"
 	"github.com/liqotech/liqo/pkg/vkMachinery"
 	"github.com/liqotech/liqo/pkg/vkMachinery/forge"
@@ -97,6 +104,7 @@ func init() {
 	_ = discoveryv1alpha1.AddToScheme(scheme)
 	_ = offloadingv1alpha1.AddToScheme(scheme)
 	_ = virtualkubeletv1alpha1.AddToScheme(scheme)
+	_ = mcsv1alpha1.AddToScheme(scheme)
 }
 
 func main() {
@@ -110,6 +118,7 @@ func main() {
 	var kubeletMetricsEnabled bool
 	var labelsNotReflected argsutils.StringList
 	var annotationsNotReflected argsutils.StringList
+	var customResourcesReflection custom.ResourceConfigList
 
 	webhookPort := flag.Uint("webhook-port", 9443, "The port the webhook server binds to")
 	metricsAddr := flag.String("metrics-address", ":8080", "The address the metric endpoint binds to")
@@ -121,6 +130,14 @@ func main() {
 	refreshInterval := flag.Duration("resource-validator-refresh-interval",
 		5*time.Minute, "The interval at which the resource validator cache is refreshed")
 
+	// Pods webhook
+	podDryRunMode := argsutils.NewEnum(podwh.DryRunModes(), string(podwh.DryRunModeDisabled))
+	flag.Var(podDryRunMode, "pod-remote-dry-run-mode",
+		"Whether to dry-run the offloading of pods against the remote clusters at creation time, and how to handle rejections "+
+			"(Disabled, Annotate or Reject)")
+	podDryRunTimeout := flag.Duration("pod-remote-dry-run-timeout", 3*time.Second,
+		"The maximum duration of the dry-run of the offloading of a pod towards each remote cluster")
+
 	// Leader election
 	leaderElection := flag.Bool("enable-leader-election", false, "Enable leader election for controller manager")
 
@@ -150,6 +167,9 @@ func main() {
 	resourceSharingPercentage := argsutils.Percentage{Val: 50}
 	flag.Var(&resourceSharingPercentage, "resource-sharing-percentage",
 		"The amount (in percentage) of cluster resources possibly shared with foreign clusters (ignored when using an external resource monitor)")
+	resourcePolicyConfigMap := flag.String("resource-policy-configmap", "",
+		"The name of the ConfigMap, in the liqo namespace, defining the time-windowed and per-cluster resource sharing policy "+
+			"(ignored when using an external resource monitor)")
 	enableIncomingPeering := flag.Bool("enable-incoming-peering", true,
 		"Enable remote clusters to establish an incoming peering with the local cluster (can be overwritten on a per foreign cluster basis)")
 	offerDisableAutoAccept := flag.Bool("offer-disable-auto-accept", false, "Disable the automatic acceptance of resource offers")
@@ -176,6 +196,9 @@ func main() {
 
 	flag.Var(&labelsNotReflected, "labels-not-reflected", "List of labels (key) that must not be reflected")
 	flag.Var(&annotationsNotReflected, "annotations-not-reflected", "List of annotations (key) that must not be reflected")
+	flag.Var(&customResourcesReflection, "custom-resource-reflection",
+		"The configuration of a custom resource to be reflected by the virtual kubelet, in the form "+
+			"<resource>.<version>.<group>[;spec=<path>,...][;status=<path>,...][;type=<type>][;workers=<workers>] (can be repeated)")
 	kubeletIpamServer := flag.String("kubelet-ipam-server", "",
 		"The address of the IPAM server to use for the virtual kubelet (set to empty string to disable IPAM)")
 
@@ -188,6 +211,12 @@ func main() {
 	// Node failure controller parameter
 	enableNodeFailureController := flag.Bool("enable-node-failure-controller", false, "Enable the node failure controller")
 
+	// Multi-cluster services parameters
+	enableMulticlusterServices := flag.Bool("enable-multicluster-services", false,
+		"Enable the support for the Multi-Cluster Services API (ServiceExport and ServiceImport resources)")
+	multiclusterServicesWorkers := flag.Int("multicluster-services-workers", 10,
+		"The number of workers used to reconcile ServiceExport and ServiceImport resources")
+
 	liqoerrors.InitFlags(nil)
 	restcfg.InitFlags(nil)
 	klog.InitFlags(nil)
@@ -197,23 +226,24 @@ func main() {
 
 	// Options for the virtual kubelet.
 	virtualKubeletOpts := &forge.VirtualKubeletOpts{
-		ContainerImage:          *kubeletImage,
-		ExtraAnnotations:        kubeletExtraAnnotations.StringMap,
-		ExtraLabels:             kubeletExtraLabels.StringMap,
-		ExtraArgs:               kubeletExtraArgs.StringList,
-		NodeExtraAnnotations:    nodeExtraAnnotations,
-		NodeExtraLabels:         nodeExtraLabels,
-		RequestsCPU:             kubeletCPURequests.Quantity,
-		RequestsRAM:             kubeletRAMRequests.Quantity,
-		LimitsCPU:               kubeletCPULimits.Quantity,
-		LimitsRAM:               kubeletRAMLimits.Quantity,
-		IpamEndpoint:            *kubeletIpamServer,
-		MetricsAddress:          kubeletMetricsAddress,
-		MetricsEnabled:          kubeletMetricsEnabled,
-		ReflectorsWorkers:       reflectorsWorkers,
-		ReflectorsType:          reflectorsType,
-		LabelsNotReflected:      labelsNotReflected.StringList,
-		AnnotationsNotReflected: annotationsNotReflected.StringList,
+		ContainerImage:            *kubeletImage,
+		ExtraAnnotations:          kubeletExtraAnnotations.StringMap,
+		ExtraLabels:               kubeletExtraLabels.StringMap,
+		ExtraArgs:                 kubeletExtraArgs.StringList,
+		NodeExtraAnnotations:      nodeExtraAnnotations,
+		NodeExtraLabels:           nodeExtraLabels,
+		RequestsCPU:               kubeletCPURequests.Quantity,
+		RequestsRAM:               kubeletRAMRequests.Quantity,
+		LimitsCPU:                 kubeletCPULimits.Quantity,
+		LimitsRAM:                 kubeletRAMLimits.Quantity,
+		IpamEndpoint:              *kubeletIpamServer,
+		MetricsAddress:            kubeletMetricsAddress,
+		MetricsEnabled:            kubeletMetricsEnabled,
+		ReflectorsWorkers:         reflectorsWorkers,
+		ReflectorsType:            reflectorsType,
+		LabelsNotReflected:        labelsNotReflected.StringList,
+		AnnotationsNotReflected:   annotationsNotReflected.StringList,
+		CustomResourcesReflection: customResourcesReflection.Strings(),
 	}
 
 	clusterIdentity := clusterIdentityFlags.ReadOrDie()
@@ -305,6 +335,31 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Create a label selector to filter only the events for the pods admitted by the pod webhook.
+	reqAdmittedPods, err := labels.NewRequirement(consts.AdmittedPodLabelKey, selection.Equals, []string{consts.AdmittedPodLabelValue})
+	utilruntime.Must(err)
+
+	// Create an accessory manager that cache only the pods admitted by the pod webhook.
+	// This manager caches the pods of the namespaces enabled for offloading, including the ones not yet scheduled.
+	auxmgrAdmittedPods, err := ctrl.NewManager(config, ctrl.Options{
+		MapperProvider:     mapper.LiqoMapperProvider(scheme),
+		Scheme:             scheme,
+		MetricsBindAddress: "0", // Disable the metrics of the auxiliary manager to prevent conflicts.
+		NewCache: func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+			opts.ByObject = map[client.Object]cache.ByObject{
+				&corev1.Pod{}: {
+					Label: labels.NewSelector().Add(*reqAdmittedPods),
+				},
+			}
+			return cache.New(config, opts)
+		},
+	})
+
+	if err != nil {
+		klog.Errorf("Unable to create auxiliary manager: %w", err)
+		os.Exit(1)
+	}
+
 	if err := mgr.Add(auxmgrLocalPods); err != nil {
 		klog.Errorf("Unable to add the auxiliary manager to the main one: %w", err)
 		os.Exit(1)
@@ -315,6 +370,11 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := mgr.Add(auxmgrAdmittedPods); err != nil {
+		klog.Errorf("Unable to add the auxiliary manager to the main one: %w", err)
+		os.Exit(1)
+	}
+
 	// Register the healthiness probes.
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		klog.Errorf("Unable to set up healthz probe: %v", err)
@@ -332,7 +392,6 @@ func main() {
 	mgr.GetWebhookServer().Register("/mutate/foreign-cluster", fcwh.NewMutator())
 	mgr.GetWebhookServer().Register("/validate/shadowpods", &webhook.Admission{Handler: spv})
 	mgr.GetWebhookServer().Register("/validate/namespace-offloading", nsoffwh.New())
-	mgr.GetWebhookServer().Register("/mutate/pod", podwh.New(mgr.GetClient()))
 	mgr.GetWebhookServer().Register("/mutate/virtualnodes", virtualnodewh.New(mgr.GetClient(), &clusterIdentity, virtualKubeletOpts))
 
 	if err := indexer.IndexField(ctx, mgr, &corev1.Pod{}, indexer.FieldNodeNameFromPod, indexer.ExtractNodeName); err != nil {
@@ -350,6 +409,18 @@ func main() {
 	namespaceManager := tenantnamespace.NewCachedManager(ctx, clientset)
 	idManager := identitymanager.NewCertificateIdentityManager(clientset, clusterIdentity, namespaceManager)
 
+	podDryRunner := &podwh.DryRunner{
+		Mode:           podwh.DryRunMode(podDryRunMode.Value),
+		LocalClusterID: clusterIdentity.ClusterID,
+		RemoteClients:  podwh.NewRemoteClientGetter(mgr.GetClient(), idManager),
+		Timeout:        *podDryRunTimeout,
+	}
+	if err := podDryRunner.ConfigureFromVirtualKubeletArgs(kubeletExtraArgs.StringList); err != nil {
+		klog.Errorf("Unable to configure the pod remote dry-run: %v", err)
+		os.Exit(1)
+	}
+	mgr.GetWebhookServer().Register("/mutate/pod", podwh.New(mgr.GetClient(), auxmgrLocalPods.GetClient(), auxmgrAdmittedPods.GetClient(), podDryRunner))
+
 	// populate the lists of ClusterRoles to bind in the different peering states
 	permissions, err := peeringroles.GetPeeringPermission(ctx, clientset)
 	if err != nil {
@@ -399,9 +470,15 @@ func main() {
 		monitor = externalMonitor
 	} else {
 		localMonitor := resourcemonitors.NewLocalMonitor(ctx, clientset, *resyncPeriod)
-		monitor = &resourcemonitors.ResourceScaler{
-			Provider: localMonitor,
-			Factor:   float32(resourceSharingPercentage.Val) / 100.,
+		if *resourcePolicyConfigMap != "" {
+			policyScaler := resourcemonitors.NewResourcePolicyScaler(localMonitor, float32(resourceSharingPercentage.Val)/100.)
+			policyScaler.WatchPolicyConfigMap(ctx, clientset, *liqoNamespace, *resourcePolicyConfigMap, *resyncPeriod)
+			monitor = policyScaler
+		} else {
+			monitor = &resourcemonitors.ResourceScaler{
+				Provider: localMonitor,
+				Factor:   float32(resourceSharingPercentage.Val) / 100.,
+			}
 		}
 	}
 	offerUpdater := resourceRequestOperator.NewOfferUpdater(ctx, mgr.GetClient(), clusterIdentity,
@@ -454,6 +531,8 @@ func main() {
 		Client:       mgr.GetClient(),
 		Recorder:     mgr.GetEventRecorderFor("namespaceoffloading-controller"),
 		LocalCluster: clusterIdentity,
+		LocalPods:    auxmgrLocalPods,
+		AdmittedPods: auxmgrAdmittedPods,
 	}
 
 	if err = namespaceOffloadingReconciler.SetupWithManager(mgr); err != nil {
@@ -537,6 +616,39 @@ func main() {
 		}
 	}
 
+	if *enableMulticlusterServices {
+		var ipamClient ipam.IpamClient
+		if *kubeletIpamServer != "" {
+			dialctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			connection, err := grpc.DialContext(dialctx, *kubeletIpamServer,
+				grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+			cancel()
+			if err != nil {
+				klog.Errorf("Failed to establish a connection to the IPAM %q: %v", *kubeletIpamServer, err)
+				os.Exit(1)
+			}
+			ipamClient = ipam.NewIpamClient(connection)
+		}
+
+		serviceExportReconciler := &serviceexportctrl.ServiceExportReconciler{
+			Client:     mgr.GetClient(),
+			IpamClient: ipamClient,
+		}
+		if err = serviceExportReconciler.SetupWithManager(mgr, *multiclusterServicesWorkers); err != nil {
+			klog.Errorf("Unable to start the serviceexport reconciler: %v", err)
+			os.Exit(1)
+		}
+
+		serviceImportReconciler := &serviceexportctrl.ServiceImportReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+		}
+		if err = serviceImportReconciler.SetupWithManager(mgr, *multiclusterServicesWorkers); err != nil {
+			klog.Errorf("Unable to start the serviceimport reconciler: %v", err)
+			os.Exit(1)
+		}
+	}
+
 	klog.Info("starting manager as controller manager")
 	if err := mgr.Start(ctx); err != nil {
 		klog.Error(err)
@@ -0,0 +1,112 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/liqotech/liqo/pkg/liqoctl/completion"
+	"github.com/liqotech/liqo/pkg/liqoctl/factory"
+	"github.com/liqotech/liqo/pkg/liqoctl/ipam"
+	"github.com/liqotech/liqo/pkg/liqoctl/output"
+)
+
+const liqoctlIPAMLongHelp = `Inspect the configuration of the Liqo IPAM.
+
+The IPAM module, embedded in the Liqo network manager, keeps track of the
+network pools, of the reserved subnets, of the networks assigned to each remote
+cluster and of the IPs of the local ExternalCIDR mapped to service endpoints.
+These commands retrieve such information through the IPAM gRPC service, in a
+read-only fashion, to ease the troubleshooting of NAT-related issues.
+`
+
+const liqoctlIPAMInfoLongHelp = `Show the configuration of the Liqo IPAM.
+
+This command shows the network pools, the reserved subnets, the networks
+configured for each remote cluster and the current endpoint mappings.
+
+Examples:
+  $ {{ .Executable }} ipam info
+or
+  $ {{ .Executable }} ipam info --remote-cluster-id 4d6e3d8a-5a3b-4f2c-8b32-3a5c6d0e1f2a
+`
+
+const liqoctlIPAMOwnerLongHelp = `Show which networks and endpoint mappings an IP belongs to.
+
+This command looks up the given IP among the networks and the endpoint mappings
+managed by the IPAM, and reports every match (e.g., the remote cluster whose
+PodCIDR contains the IP, or the endpoint a natted IP has been assigned to).
+
+Examples:
+  $ {{ .Executable }} ipam owner 10.71.0.12
+`
+
+func newIPAMCommand(ctx context.Context, f *factory.Factory) *cobra.Command {
+	options := &ipam.Options{Factory: f}
+	cmd := &cobra.Command{
+		Use:   "ipam",
+		Short: "Inspect the configuration of the Liqo IPAM",
+		Long:  WithTemplate(liqoctlIPAMLongHelp),
+		Args:  cobra.NoArgs,
+	}
+
+	f.AddLiqoNamespaceFlag(cmd.PersistentFlags())
+	f.Printer.CheckErr(cmd.RegisterFlagCompletionFunc(factory.FlagNamespace, completion.Namespaces(ctx, f, completion.NoLimit)))
+
+	cmd.PersistentFlags().DurationVar(&options.Timeout, "timeout", 30*time.Second, "The timeout for the retrieval of the IPAM information")
+
+	cmd.AddCommand(newIPAMInfoCommand(ctx, options))
+	cmd.AddCommand(newIPAMOwnerCommand(ctx, options))
+	return cmd
+}
+
+func newIPAMInfoCommand(ctx context.Context, options *ipam.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "info",
+		Short: "Show the configuration of the Liqo IPAM",
+		Long:  WithTemplate(liqoctlIPAMInfoLongHelp),
+		Args:  cobra.NoArgs,
+
+		Run: func(cmd *cobra.Command, args []string) {
+			output.ExitOnErr(options.RunInfo(ctx))
+		},
+	}
+
+	cmd.Flags().StringVar(&options.ClusterID, "remote-cluster-id", "",
+		"Restrict the output to the networks and mappings of the given remote cluster")
+	options.Printer.CheckErr(cmd.RegisterFlagCompletionFunc("remote-cluster-id",
+		completion.ClusterIDs(ctx, options.Factory, completion.NoLimit)))
+
+	return cmd
+}
+
+func newIPAMOwnerCommand(ctx context.Context, options *ipam.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "owner ip",
+		Short: "Show which networks and endpoint mappings an IP belongs to",
+		Long:  WithTemplate(liqoctlIPAMOwnerLongHelp),
+		Args:  cobra.ExactArgs(1),
+
+		Run: func(cmd *cobra.Command, args []string) {
+			options.IP = args[0]
+			output.ExitOnErr(options.RunOwner(ctx))
+		},
+	}
+
+	return cmd
+}
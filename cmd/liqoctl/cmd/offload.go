@@ -41,7 +41,8 @@ Namespace offloading can be tuned in terms of:
 * Clusters: select the target clusters through virtual node labels.
 * Pod offloading: whether pods should be scheduled on physical nodes only,
   virtual nodes only, or both. Forcing all pods to be scheduled locally enables
-  the consumption of services from remote clusters.
+  the consumption of services from remote clusters. Alternatively, pods can be
+  spread across the local and remote clusters according to the given weights.
 * Naming: whether remote namespaces have the same name or a suffix is added to
   prevent conflicts.
 
@@ -59,6 +60,9 @@ or (cluster labels in logical AND)
 or (cluster labels in logical OR)
   $ {{ .Executable }} offload namespace foo --namespace-mapping-strategy EnforceSameName \
       --selector 'region in (europe,us-west)' --selector '!staging'
+or (70% of pods executed locally, and 30% in the given remote cluster)
+  $ {{ .Executable }} offload namespace foo --pod-offloading-strategy Weighted \
+      --local-weight 70 --remote-weights cluster-id=30
 or (output the NamespaceOffloading resource as a yaml manifest, without applying it)
   $ {{ .Executable }} offload namespace foo --output yaml
 `
@@ -77,11 +81,14 @@ func newOffloadCommand(ctx context.Context, f *factory.Factory) *cobra.Command {
 
 func newOffloadNamespaceCommand(ctx context.Context, f *factory.Factory) *cobra.Command {
 	var selectors []string
+	var localWeight int
+	var remoteWeights map[string]int
 
 	podOffloadingStrategy := args.NewEnum([]string{
 		string(offloadingv1alpha1.LocalAndRemotePodOffloadingStrategyType),
 		string(offloadingv1alpha1.RemotePodOffloadingStrategyType),
-		string(offloadingv1alpha1.LocalPodOffloadingStrategyType)},
+		string(offloadingv1alpha1.LocalPodOffloadingStrategyType),
+		string(offloadingv1alpha1.WeightedPodOffloadingStrategyType)},
 		string(offloadingv1alpha1.LocalAndRemotePodOffloadingStrategyType))
 
 	namespaceMappingStrategy := args.NewEnum([]string{
@@ -106,6 +113,7 @@ func newOffloadNamespaceCommand(ctx context.Context, f *factory.Factory) *cobra.
 			options.NamespaceMappingStrategy = offloadingv1alpha1.NamespaceMappingStrategyType(namespaceMappingStrategy.Value)
 			options.OutputFormat = outputFormat.Value
 			options.Printer.CheckErr(options.ParseClusterSelectors(selectors))
+			options.Printer.CheckErr(options.ParseWeights(localWeight, remoteWeights))
 		},
 
 		Run: func(cmd *cobra.Command, args []string) {
@@ -115,7 +123,7 @@ func newOffloadNamespaceCommand(ctx context.Context, f *factory.Factory) *cobra.
 	}
 
 	cmd.Flags().Var(podOffloadingStrategy, "pod-offloading-strategy",
-		"The constraints regarding pods scheduling in this namespace, among Local, Remote and LocalAndRemote")
+		"The constraints regarding pods scheduling in this namespace, among Local, Remote, LocalAndRemote and Weighted")
 	cmd.Flags().Var(namespaceMappingStrategy, "namespace-mapping-strategy",
 		"The naming strategy adopted for the creation of remote namespaces, among DefaultName and EnforceSameName")
 	cmd.Flags().DurationVar(&options.Timeout, "timeout", 20*time.Second, "The timeout for the offloading process")
@@ -123,6 +131,11 @@ func newOffloadNamespaceCommand(ctx context.Context, f *factory.Factory) *cobra.
 	cmd.Flags().StringArrayVarP(&selectors, "selector", "l", []string{},
 		"The selector to filter the target clusters. Can be specified multiple times, defining alternative requirements (i.e., in logical OR)")
 
+	cmd.Flags().IntVar(&localWeight, "local-weight", 0,
+		"The weight of the local cluster, determining the share of pods executed locally (Weighted strategy only)")
+	cmd.Flags().StringToIntVar(&remoteWeights, "remote-weights", map[string]int{},
+		"The weights of the remote clusters, in the form cluster-id=weight (Weighted strategy only)")
+
 	cmd.Flags().VarP(outputFormat, "output", "o",
 		"Output the resulting NamespaceOffloading resource, instead of applying it. Supported formats: json, yaml")
 
@@ -19,11 +19,14 @@ import (
 	"fmt"
 
 	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqonet/firewall"
+	"github.com/liqotech/liqo/pkg/utils/args"
 )
 
 type liqonetCommonFlags struct {
-	metricsAddr string
-	runAs       string
+	metricsAddr     string
+	runAs           string
+	firewallBackend *args.StringEnum
 }
 
 func addCommonFlags(liqonet *liqonetCommonFlags) {
@@ -31,4 +34,7 @@ func addCommonFlags(liqonet *liqonetCommonFlags) {
 	flag.StringVar(&liqonet.runAs, "run-as", liqoconst.LiqoGatewayOperatorName,
 		fmt.Sprintf("The accepted values are: %q, %q, %q.",
 			liqoconst.LiqoGatewayOperatorName, liqoconst.LiqoRouteOperatorName, liqoconst.LiqoNetworkManagerName))
+	liqonet.firewallBackend = args.NewEnum(firewall.Backends(), string(firewall.IPTablesBackend))
+	flag.Var(liqonet.firewallBackend, "firewall-backend",
+		"firewall-backend is the backend used to configure the firewall and NAT rules (one of: iptables, nftables)")
 }
@@ -36,42 +36,67 @@ import (
 	liqoconst "github.com/liqotech/liqo/pkg/consts"
 	"github.com/liqotech/liqo/pkg/liqonet/conncheck"
 	liqonetns "github.com/liqotech/liqo/pkg/liqonet/netns"
+	tunnelwg "github.com/liqotech/liqo/pkg/liqonet/tunnel/wireguard"
 	liqonetutils "github.com/liqotech/liqo/pkg/liqonet/utils"
 	"github.com/liqotech/liqo/pkg/liqonet/utils/links"
 	liqonetsignals "github.com/liqotech/liqo/pkg/liqonet/utils/signals"
+	"github.com/liqotech/liqo/pkg/utils/args"
 	"github.com/liqotech/liqo/pkg/utils/mapper"
 	"github.com/liqotech/liqo/pkg/utils/restcfg"
 )
 
 type gatewayOperatorFlags struct {
 	enableLeaderElection bool
+	hotStandby           bool
 	leaseDuration        time.Duration
 	renewDeadline        time.Duration
 	retryPeriod          time.Duration
 	tunnelMTU            uint
 	tunnelListeningPort  uint
+	tunnelDriver         *args.StringEnum
 	updateStatusInterval time.Duration
 }
 
 func addGatewayOperatorFlags(liqonet *gatewayOperatorFlags) {
 	flag.BoolVar(&liqonet.enableLeaderElection, "gateway.leader-elect", false,
 		"leader-elect enables leader election for controller manager.")
+	flag.BoolVar(&liqonet.hotStandby, "gateway.hot-standby", false,
+		"hot-standby keeps all the gateway replicas running, with each tunnel established by the one holding the corresponding lease "+
+			"(it excludes leader election)")
 	flag.DurationVar(&liqonet.leaseDuration, "gateway.lease-duration", 7*time.Second,
-		"lease-duration is the duration that non-leader candidates will wait to force acquire leadership")
+		"lease-duration is the duration that non-leader candidates will wait to force acquire leadership (or the tunnel leases, in hot-standby mode)")
 	flag.DurationVar(&liqonet.renewDeadline, "gateway.renew-deadline", 5*time.Second,
-		"renew-deadline is the duration that the acting control plane will retry refreshing leadership before giving up")
+		"renew-deadline is the duration that the acting control plane will retry refreshing leadership (or the tunnel leases) before giving up")
 	flag.DurationVar(&liqonet.retryPeriod, "gateway.retry-period", 2*time.Second,
 		"retry-period is the duration the LeaderElector clients should wait between tries of actions")
 	flag.UintVar(&liqonet.tunnelMTU, "gateway.mtu", liqoconst.DefaultMTU,
 		"mtu is the maximum transmission unit for interfaces managed by the gateway operator")
 	flag.UintVar(&liqonet.tunnelListeningPort, "gateway.listening-port", liqoconst.GatewayListeningPort,
 		"listening-port is the port used by the vpn tunnel")
+	liqonet.tunnelDriver = args.NewEnum([]string{liqoconst.DriverName, liqoconst.WgTCPDriverName, liqoconst.IPsecDriverName}, liqoconst.DriverName)
+	flag.Var(liqonet.tunnelDriver, "gateway.tunnel-driver",
+		"tunnel-driver is the driver used to establish the vpn tunnel (one of: wireguard, wireguard-tcp, ipsec)")
+	flag.StringVar(&tunnelwg.TunnelProxyURL, "gateway.tunnel-proxy", "",
+		"tunnel-proxy is the URL of the HTTP(S) proxy used to reach the remote gateways with the wireguard-tcp driver "+
+			"(defaults to the one configured through the HTTPS_PROXY environment variable, if any)")
 	flag.DurationVar(&liqonet.updateStatusInterval, "gateway.ping-latency-update-interval", 30*time.Second,
 		"ping-latency-update-interval is the interval at which the gateway operator updates the latency value in the status of the tunnel-endpoint")
 	flag.UintVar(&conncheck.PingLossThreshold, "gateway.ping-loss-threshold", 5,
 		"ping-loss-threshold is the number of lost packets after which the connection check is considered as failed.")
 	flag.DurationVar(&conncheck.PingInterval, "gateway.ping-interval", 2*time.Second,
 		"ping-interval is the interval between two connection checks")
+	flag.UintVar(&conncheck.PingStatsWindowSize, "gateway.ping-stats-window-size", 30,
+		"ping-stats-window-size is the number of most recent pings used to compute packet loss, jitter and latency percentiles")
+	flag.BoolVar(&conncheck.PMTUDiscovery, "gateway.pmtu-discovery", false,
+		"pmtu-discovery enables the discovery of the path MTU towards each peer, bounded by the tunnel mtu")
+	flag.IntVar(&conncheck.PMTUMin, "gateway.pmtu-min", 1280,
+		"pmtu-min is the minimum path MTU probed during the path MTU discovery")
+	flag.DurationVar(&conncheck.PMTURenegotiationInterval, "gateway.pmtu-renegotiation-interval", 10*time.Minute,
+		"pmtu-renegotiation-interval is the interval after which the path MTU is discovered again, to detect path changes")
+	flag.DurationVar(&tunnelwg.KeyRotationInterval, "gateway.wireguard-key-rotation-interval", 0,
+		"wireguard-key-rotation-interval is the interval after which the wireguard key pair is rotated (0 to disable the rotation)")
+	flag.DurationVar(&tunnelwg.KeyRotationGracePeriod, "gateway.wireguard-key-rotation-grace-period", 2*time.Minute,
+		"wireguard-key-rotation-grace-period is the time between the publication of a new wireguard public key and its adoption")
 }
 
 func runGatewayOperator(commonFlags *liqonetCommonFlags, gatewayFlags *gatewayOperatorFlags) {
@@ -79,6 +104,10 @@ func runGatewayOperator(commonFlags *liqonetCommonFlags, gatewayFlags *gatewayOp
 	wg := sync.WaitGroup{}
 	metricsAddr := commonFlags.metricsAddr
 	enableLeaderElection := gatewayFlags.enableLeaderElection
+	if gatewayFlags.hotStandby && enableLeaderElection {
+		klog.Warning("leader election is not supported in hot-standby mode, and it will be disabled")
+		enableLeaderElection = false
+	}
 	leaseDuration := gatewayFlags.leaseDuration
 	renewDeadLine := gatewayFlags.renewDeadline
 	retryPeriod := gatewayFlags.retryPeriod
@@ -91,6 +120,8 @@ func runGatewayOperator(commonFlags *liqonetCommonFlags, gatewayFlags *gatewayOp
 	port := gatewayFlags.tunnelListeningPort
 	MTU := gatewayFlags.tunnelMTU
 	updateStatusInterval := gatewayFlags.updateStatusInterval
+	// The MTU of the tunnel interface is the upper bound of the path MTU discovered towards each peer.
+	conncheck.PMTUMax = int(MTU)
 
 	// Get the pod ip and parse to net.IP.
 	podIP, err := liqonetutils.GetPodIP()
@@ -98,11 +129,24 @@ func runGatewayOperator(commonFlags *liqonetCommonFlags, gatewayFlags *gatewayOp
 		klog.Errorf("unable to get podIP: %v", err)
 		os.Exit(1)
 	}
+	// The IPv6 address of the pod, if any, is used as next hop for the IPv6 networks of dual-stack peerings.
+	var podIPv6 string
+	if ip := liqonetutils.GetPodIPv6(); ip != nil {
+		podIPv6 = ip.String()
+	}
 	podNamespace, err := liqonetutils.GetPodNamespace()
 	if err != nil {
 		klog.Errorf("unable to get pod namespace: %v", err)
 		os.Exit(1)
 	}
+	// The name of the pod identifies the replica in the status of the tunnel endpoints, in case of hot-standby mode.
+	var podName string
+	if gatewayFlags.hotStandby {
+		if podName, err = liqonetutils.GetPodName(); err != nil {
+			klog.Errorf("unable to get pod name: %v", err)
+			os.Exit(1)
+		}
+	}
 	main, err := ctrl.NewManager(restcfg.SetRateLimiter(ctrl.GetConfigOrDie()), ctrl.Options{
 		MapperProvider:                mapper.LiqoMapperProvider(scheme),
 		Scheme:                        scheme,
@@ -128,10 +172,24 @@ func runGatewayOperator(commonFlags *liqonetCommonFlags, gatewayFlags *gatewayOp
 		klog.Errorf("unable to get main manager: %s", err)
 		os.Exit(1)
 	}
+	// Create an accessory manager caching the pods in all namespaces, which are selected by the inter-cluster network policies.
+	auxmgrPods, err := ctrl.NewManager(main.GetConfig(), ctrl.Options{
+		MapperProvider:     mapper.LiqoMapperProvider(scheme),
+		Scheme:             scheme,
+		MetricsBindAddress: "0", // Disable the metrics of the auxiliary manager to prevent conflicts.
+	})
+	if err != nil {
+		klog.Errorf("unable to create the auxiliary manager: %s", err)
+		os.Exit(1)
+	}
+	if err := main.Add(auxmgrPods); err != nil {
+		klog.Errorf("unable to add the auxiliary manager to the main one: %s", err)
+		os.Exit(1)
+	}
 	clientset := kubernetes.NewForConfigOrDie(main.GetConfig())
 	eventRecorder := main.GetEventRecorderFor(liqoconst.LiqoGatewayOperatorName + "." + podIP.String())
 	// This map is updated by the tunnel operator after a successful tunnel creation
-	// and is consumed by the natmapping operator to check whether the tunnel is ready or not.
+	// and is consumed by the natmapping and network policy operators to check whether the tunnel is ready or not.
 	var readyClustersMutex sync.Mutex
 	readyClusters := make(map[string]struct{})
 	// Create new network namespace for the gateway (gatewayNetns).
@@ -151,13 +209,14 @@ func runGatewayOperator(commonFlags *liqonetCommonFlags, gatewayFlags *gatewayOp
 	}
 	klog.Infof("created custom network namespace {%s}", liqoconst.GatewayNetnsName)
 
-	labelController := tunneloperator.NewLabelerController(podIP.String(), main.GetClient())
+	labelController := tunneloperator.NewLabelerController(podIP.String(), main.GetClient(), gatewayFlags.hotStandby)
 	if err = labelController.SetupWithManager(main); err != nil {
 		klog.Errorf("unable to setup labeler controller: %s", err)
 		os.Exit(1)
 	}
-	tunnelController, err := tunneloperator.NewTunnelController(ctx, &wg, podIP.String(), podNamespace, eventRecorder,
-		clientset, main.GetClient(), &readyClustersMutex, readyClusters, gatewayNetns, hostNetns, int(MTU), int(port), updateStatusInterval)
+	tunnelController, err := tunneloperator.NewTunnelController(ctx, &wg, podName, podIP.String(), podIPv6, podNamespace, eventRecorder,
+		clientset, main.GetClient(), &readyClustersMutex, readyClusters, gatewayNetns, hostNetns, int(MTU), int(port), gatewayFlags.tunnelDriver.Value,
+		updateStatusInterval, gatewayFlags.hotStandby, leaseDuration, renewDeadLine, retryPeriod)
 	// If something goes wrong while creating and configuring the tunnel controller
 	// then make sure that we remove all the resources created during the create process.
 	if err != nil {
@@ -166,9 +225,9 @@ func runGatewayOperator(commonFlags *liqonetCommonFlags, gatewayFlags *gatewayOp
 		if err := liqonetns.DeleteNetns(liqoconst.GatewayNetnsName); err != nil {
 			klog.Errorf("an error occurred while deleting netns {%s}: %v", liqoconst.GatewayNetnsName, err)
 		}
-		klog.Info("cleaning up wireguard tunnel interface")
+		klog.Info("cleaning up tunnel interface")
 		if err := links.DeleteIFaceByName(liqoconst.DeviceName); err != nil {
-			klog.Errorf("an error occurred while deleting iface {%s}: %v", liqoconst.DriverName, err)
+			klog.Errorf("an error occurred while deleting iface {%s}: %v", liqoconst.DeviceName, err)
 		}
 		os.Exit(1)
 	}
@@ -187,6 +246,17 @@ func runGatewayOperator(commonFlags *liqonetCommonFlags, gatewayFlags *gatewayOp
 		os.Exit(1)
 	}
 
+	networkPolicyController, err := tunneloperator.NewNetworkPolicyController(main.GetClient(), auxmgrPods, &readyClustersMutex,
+		readyClusters, gatewayNetns)
+	if err != nil {
+		klog.Errorf("an error occurred while creating the network policy controller: %v", err)
+		os.Exit(1)
+	}
+	if err = networkPolicyController.SetupWithManager(main); err != nil {
+		klog.Errorf("unable to setup network policy controller: %s", err)
+		os.Exit(1)
+	}
+
 	klog.Info("Starting manager as Tunnel-Operator")
 	if err := main.Start(tunnelController.SetupSignalHandlerForTunnelOperator(ctx, &wg)); err != nil {
 		klog.Errorf("unable to start tunnel controller: %s", err)
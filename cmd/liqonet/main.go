@@ -27,6 +27,7 @@ import (
 	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
 	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
 	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqonet/firewall"
 	"github.com/liqotech/liqo/pkg/utils/restcfg"
 )
 
@@ -66,6 +67,7 @@ func main() {
 	flag.Parse()
 
 	log.SetLogger(klog.NewKlogr())
+	firewall.SelectedBackend = firewall.Backend(commonFlags.firewallBackend.Value)
 
 	switch commonFlags.runAs {
 	case liqoconst.LiqoRouteOperatorName:
@@ -16,6 +16,7 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"os"
 
 	corev1 "k8s.io/api/core/v1"
@@ -41,17 +42,38 @@ type networkManagerFlags struct {
 	podCIDR     args.CIDR
 	serviceCIDR args.CIDR
 
+	podCIDRv6     args.CIDR
+	serviceCIDRv6 args.CIDR
+
 	additionalPools args.CIDRList
 	reservedPools   args.CIDRList
+
+	tunnelDriver    *args.StringEnum
+	tunnelTransport *args.StringEnum
+
+	transitRouting bool
 }
 
 func addNetworkManagerFlags(managerFlags *networkManagerFlags) {
 	flag.Var(&managerFlags.podCIDR, "manager.pod-cidr", "The subnet used by the cluster for the pods, in CIDR notation")
 	flag.Var(&managerFlags.serviceCIDR, "manager.service-cidr", "The subnet used by the cluster for the pods, in services notation")
+	flag.Var(&managerFlags.podCIDRv6, "manager.pod-cidr-v6",
+		"The IPv6 subnet used by the cluster for the pods, in CIDR notation (dual-stack clusters only)")
+	flag.Var(&managerFlags.serviceCIDRv6, "manager.service-cidr-v6",
+		"The IPv6 subnet used by the cluster for the services, in CIDR notation (dual-stack clusters only)")
 	flag.Var(&managerFlags.reservedPools, "manager.reserved-pools",
 		"Private CIDRs slices used by the Kubernetes infrastructure, in addition to the pod and service CIDR (e.g., the node subnet).")
 	flag.Var(&managerFlags.additionalPools, "manager.additional-pools",
 		"Network pools used to map a cluster network into another one in order to prevent conflicts, in addition to standard private CIDRs.")
+	managerFlags.tunnelDriver = args.NewEnum([]string{liqoconst.DriverName, liqoconst.WgTCPDriverName, liqoconst.IPsecDriverName},
+		liqoconst.DriverName)
+	flag.Var(managerFlags.tunnelDriver, "manager.tunnel-driver",
+		"The driver used by the gateway to establish the vpn tunnel, advertised to the remote clusters (one of: wireguard, wireguard-tcp, ipsec)")
+	managerFlags.tunnelTransport = args.NewEnum([]string{liqoconst.WgTransportWebSocket, liqoconst.WgTransportTCP}, liqoconst.WgTransportWebSocket)
+	flag.Var(managerFlags.tunnelTransport, "manager.tunnel-transport",
+		"The transport the remote clusters shall use to reach the gateway, with the wireguard-tcp driver only (one of: websocket, tcp)")
+	flag.BoolVar(&managerFlags.transitRouting, "manager.transit-routing", false,
+		"Enable the forwarding of the traffic between the remote clusters, announcing to each of them the pod CIDRs of the others")
 }
 
 func runNetworkManager(commonFlags *liqonetCommonFlags, managerFlags *networkManagerFlags) {
@@ -95,10 +117,20 @@ func runNetworkManager(commonFlags *liqonetCommonFlags, managerFlags *networkMan
 		os.Exit(1)
 	}
 
+	var externalCIDRv6 string
+	if managerFlags.dualStack() {
+		externalCIDRv6, err = ipam.GetExternalCIDRv6(liqonetutils.GetMask(managerFlags.podCIDRv6.String()))
+		if err != nil {
+			klog.Errorf("Failed to initialize the IPv6 external CIDR: %s", err)
+			os.Exit(1)
+		}
+	}
+
 	tec := &tunnelendpointcreator.TunnelEndpointCreator{
 		Client:    mgr.GetClient(),
 		Scheme:    mgr.GetScheme(),
 		IPManager: ipam,
+		DualStack: managerFlags.dualStack(),
 	}
 
 	ncc := &netcfgcreator.NetworkConfigCreator{
@@ -107,6 +139,16 @@ func runNetworkManager(commonFlags *liqonetCommonFlags, managerFlags *networkMan
 
 		PodCIDR:      managerFlags.podCIDR.String(),
 		ExternalCIDR: externalCIDR,
+		BackendType:  managerFlags.tunnelDriver.Value,
+
+		TransitRouting: managerFlags.transitRouting,
+	}
+	if managerFlags.tunnelDriver.Value == liqoconst.WgTCPDriverName {
+		ncc.BackendTransport = managerFlags.tunnelTransport.Value
+	}
+	if managerFlags.dualStack() {
+		ncc.PodCIDRv6 = managerFlags.podCIDRv6.String()
+		ncc.ExternalCIDRv6 = externalCIDRv6
 	}
 
 	if err = tec.SetupWithManager(mgr); err != nil {
@@ -129,7 +171,15 @@ func runNetworkManager(commonFlags *liqonetCommonFlags, managerFlags *networkMan
 func initializeIPAM(client dynamic.Interface, managerFlags *networkManagerFlags) (*liqonetIpam.IPAM, error) {
 	ipam := liqonetIpam.NewIPAM()
 
-	if err := ipam.Init(liqonetIpam.Pools, client, liqoconst.NetworkManagerIpamPort); err != nil {
+	pools := liqonetIpam.Pools
+	if managerFlags.dualStack() {
+		if err := managerFlags.validateIPv6(); err != nil {
+			return nil, err
+		}
+		pools = append(append([]string{}, liqonetIpam.Pools...), liqonetIpam.PoolsV6...)
+	}
+
+	if err := ipam.Init(pools, client, liqoconst.NetworkManagerIpamPort); err != nil {
 		return nil, err
 	}
 
@@ -139,6 +189,14 @@ func initializeIPAM(client dynamic.Interface, managerFlags *networkManagerFlags)
 	if err := ipam.SetServiceCIDR(managerFlags.serviceCIDR.String()); err != nil {
 		return nil, err
 	}
+	if managerFlags.dualStack() {
+		if err := ipam.SetPodCIDR(managerFlags.podCIDRv6.String()); err != nil {
+			return nil, err
+		}
+		if err := ipam.SetServiceCIDR(managerFlags.serviceCIDRv6.String()); err != nil {
+			return nil, err
+		}
+	}
 
 	for _, pool := range managerFlags.additionalPools.StringList.StringList {
 		if err := ipam.AddNetworkPool(pool); err != nil {
@@ -152,3 +210,20 @@ func initializeIPAM(client dynamic.Interface, managerFlags *networkManagerFlags)
 
 	return ipam, nil
 }
+
+// dualStack returns whether the IPv6 networks of the cluster have been configured.
+func (managerFlags *networkManagerFlags) dualStack() bool {
+	return managerFlags.podCIDRv6.IsSet()
+}
+
+// validateIPv6 checks that the IPv6 networks of the cluster are consistently configured.
+func (managerFlags *networkManagerFlags) validateIPv6() error {
+	if !managerFlags.serviceCIDRv6.IsSet() {
+		return fmt.Errorf("the IPv6 service CIDR must be set in case of dual-stack clusters")
+	}
+	if !liqonetutils.IsIPv6CIDR(managerFlags.podCIDRv6.String()) || !liqonetutils.IsIPv6CIDR(managerFlags.serviceCIDRv6.String()) {
+		return fmt.Errorf("the IPv6 pod CIDR %s and service CIDR %s must be IPv6 networks",
+			managerFlags.podCIDRv6.String(), managerFlags.serviceCIDRv6.String())
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main provides the main entrypoint for the reference external resource monitor,
+// to be configured in the liqo-controller-manager through the --resource-plugin-address flag.
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/liqotech/liqo/pkg/resourcemonitorserver"
+	"github.com/liqotech/liqo/pkg/utils/restcfg"
+)
+
+// cluster-role
+// +kubebuilder:rbac:groups=core,resources=nodes;pods,verbs=get;list;watch
+
+func main() {
+	address := flag.String("address", ":6001", "The address the gRPC server listens on")
+	resyncPeriod := flag.Duration("resync-period", 10*time.Hour, "The resync period for the informers")
+
+	nodesEnabled := flag.Bool("nodes.enabled", false, "Publish the resources available in the physical nodes matching the selector")
+	nodesSelector := flag.String("nodes.selector", "", "The label selector identifying the nodes whose resources are published (default: all nodes)")
+	nodesPoolName := flag.String("nodes.pool-name", "", "The name of the pool the node resources are published as")
+	nodesPoolPrefix := flag.String("nodes.pool-prefix", "", "The prefix of the pool the node resources are published as")
+
+	staticFile := flag.String("static.file", "", "The path of a YAML file defining static resource pools (disabled if empty)")
+	staticInterval := flag.Duration("static.interval", 30*time.Second, "The interval the static file is checked for changes")
+
+	prometheusAddress := flag.String("prometheus.address", "", "The address of the Prometheus server queried for resources (disabled if empty)")
+	prometheusInterval := flag.Duration("prometheus.interval", time.Minute, "The interval the Prometheus queries are executed")
+	prometheusPoolName := flag.String("prometheus.pool-name", "", "The name of the pool the Prometheus resources are published as")
+	prometheusPoolPrefix := flag.String("prometheus.pool-prefix", "", "The prefix of the pool the Prometheus resources are published as")
+	prometheusQueries := map[corev1.ResourceName]*string{
+		corev1.ResourceCPU:              flag.String("prometheus.cpu-query", "", "The query returning the available CPU cores"),
+		corev1.ResourceMemory:           flag.String("prometheus.memory-query", "", "The query returning the available memory, in bytes"),
+		corev1.ResourcePods:             flag.String("prometheus.pods-query", "", "The query returning the available number of pods"),
+		corev1.ResourceEphemeralStorage: flag.String("prometheus.ephemeral-storage-query", "", "The query returning the available ephemeral storage, in bytes"),
+	}
+
+	klog.InitFlags(nil)
+	restcfg.InitFlags(nil)
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	var sources []resourcemonitorserver.Source
+
+	if *nodesEnabled {
+		selector, err := labels.Parse(*nodesSelector)
+		if err != nil {
+			klog.Fatalf("Invalid node selector %q: %v", *nodesSelector, err)
+		}
+		clientset := kubernetes.NewForConfigOrDie(restcfg.SetRateLimiter(ctrl.GetConfigOrDie()))
+		pool := resourcemonitorserver.Pool{Name: *nodesPoolName, Prefix: *nodesPoolPrefix}
+		sources = append(sources, resourcemonitorserver.NewNodeSource(ctx, clientset, selector, pool, *resyncPeriod))
+	}
+
+	if *staticFile != "" {
+		source, err := resourcemonitorserver.NewStaticSource(*staticFile, *staticInterval)
+		if err != nil {
+			klog.Fatalf("Failed to load the static resource pools: %v", err)
+		}
+		sources = append(sources, source)
+	}
+
+	if *prometheusAddress != "" {
+		queries := map[corev1.ResourceName]string{}
+		for name, query := range prometheusQueries {
+			if *query != "" {
+				queries[name] = *query
+			}
+		}
+		if len(queries) == 0 {
+			klog.Fatal("At least one Prometheus query shall be specified")
+		}
+		pool := resourcemonitorserver.Pool{Name: *prometheusPoolName, Prefix: *prometheusPoolPrefix}
+		source, err := resourcemonitorserver.NewPrometheusSource(*prometheusAddress, queries, pool, *prometheusInterval)
+		if err != nil {
+			klog.Fatal(err)
+		}
+		sources = append(sources, source)
+	}
+
+	if len(sources) == 0 {
+		klog.Fatal("No resource source enabled, please configure at least one of nodes, static file and Prometheus")
+	}
+
+	if err := resourcemonitorserver.New(sources...).Start(ctx, *address); err != nil {
+		klog.Fatal(err)
+	}
+}
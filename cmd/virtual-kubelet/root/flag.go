@@ -65,6 +65,9 @@ func InstallFlags(flags *pflag.FlagSet, o *Opts) {
 
 	flags.Var(&o.LabelsNotReflected, "labels-not-reflected", "List of labels (key) that must not be reflected")
 	flags.Var(&o.AnnotationsNotReflected, "annotations-not-reflected", "List of annotations (key) that must not be reflected")
+	flags.Var(&o.CustomResourcesReflection, "custom-resource-reflection",
+		"The configuration of a custom resource to be reflected, in the form <resource>.<version>.<group>[;spec=<path>,...][;status=<path>,...]"+
+			"[;type=<type>][;workers=<workers>] (can be repeated)")
 
 	flags.BoolVar(&o.EnableAPIServerSupport, "enable-apiserver-support", false,
 		"Enable offloaded pods to interact back with the local Kubernetes API server")
@@ -25,6 +25,7 @@ import (
 	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
 	"github.com/liqotech/liqo/pkg/consts"
 	argsutils "github.com/liqotech/liqo/pkg/utils/args"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/custom"
 	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/generic"
 )
 
@@ -57,6 +58,8 @@ var DefaultReflectorsWorkers = map[generic.ResourceReflected]uint{
 	generic.ServiceAccount:        3,
 	generic.PersistentVolumeClaim: 3,
 	generic.Event:                 3,
+	generic.StatefulSet:           3,
+	generic.NetworkPolicy:         3,
 }
 
 // DefaultReflectorsTypes contains the default type of reflection for each reflected resource.
@@ -70,6 +73,8 @@ var DefaultReflectorsTypes = map[generic.ResourceReflected]consts.ReflectionType
 	generic.ServiceAccount:        consts.CustomLiqo,
 	generic.PersistentVolumeClaim: consts.CustomLiqo,
 	generic.Event:                 consts.DenyList,
+	generic.StatefulSet:           consts.DenyList,
+	generic.NetworkPolicy:         consts.DenyList,
 }
 
 // Opts stores all the options for configuring the root virtual-kubelet command.
@@ -105,6 +110,9 @@ type Opts struct {
 	LabelsNotReflected      argsutils.StringList
 	AnnotationsNotReflected argsutils.StringList
 
+	// Configuration of the custom resources to be reflected
+	CustomResourcesReflection custom.ResourceConfigList
+
 	NodeLeaseDuration time.Duration
 	NodePingInterval  time.Duration
 	NodePingTimeout   time.Duration
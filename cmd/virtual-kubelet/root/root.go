@@ -117,7 +117,8 @@ func runRootCommand(ctx context.Context, c *Opts) error {
 		DisableIPReflection:  c.DisableIPReflection,
 		InformerResyncPeriod: c.InformerResyncPeriod,
 
-		ReflectorsConfigs: reflectorsConfigs,
+		ReflectorsConfigs:      reflectorsConfigs,
+		CustomResourcesConfigs: c.CustomResourcesReflection.Configs,
 
 		EnableAPIServerSupport:     c.EnableAPIServerSupport,
 		EnableStorage:              c.EnableStorage,
@@ -58,5 +58,10 @@ func GetResourcesToReplicate() []Resource {
 			PeeringPhase:         consts.PeeringPhaseOutgoing,
 			Ownership:            consts.OwnershipShared,
 		},
+		{
+			GroupVersionResource: discoveryv1alpha1.ExportedServiceGroupVersionResource,
+			PeeringPhase:         consts.PeeringPhaseEstablished,
+			Ownership:            consts.OwnershipShared,
+		},
 	}
 }
@@ -28,6 +28,8 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
@@ -51,11 +53,22 @@ type NetworkConfigCreator struct {
 
 	PodCIDR      string
 	ExternalCIDR string
+	// PodCIDRv6 and ExternalCIDRv6 are the IPv6 networks of the local cluster, set only in case of dual-stack clusters.
+	PodCIDRv6      string
+	ExternalCIDRv6 string
+	// BackendType is the tunnel driver advertised to the remote clusters.
+	BackendType string
+	// BackendTransport is the transport advertised to the remote clusters, if supported by the tunnel driver.
+	BackendTransport string
+	// TransitRouting enables the announcement to each remote cluster of the networks of the other ones,
+	// which can be reached in transit through the local cluster.
+	TransitRouting bool
 }
 
 // cluster-roles
 // +kubebuilder:rbac:groups=discovery.liqo.io,resources=foreignclusters,verbs=get;list;watch
 // +kubebuilder:rbac:groups=net.liqo.io,resources=networkconfigs,verbs=get;list;watch;create;update;patch;delete;deletecollection
+// +kubebuilder:rbac:groups=net.liqo.io,resources=tunnelendpoints,verbs=get;list;watch
 // roles
 // +kubebuilder:rbac:groups=core,namespace="do-not-care",resources=secrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,namespace="do-not-care",resources=services,verbs=get;list;watch
@@ -115,17 +128,27 @@ func (ncc *NetworkConfigCreator) SetupWithManager(mgr ctrl.Manager) error {
 	}
 
 	ncc.foreignClusters = syncset.New()
-	ncc.secretWatcher = NewSecretWatcher(enqueuefn)
+	ncc.secretWatcher = NewSecretWatcher(ncc.BackendType, enqueuefn)
 	ncc.serviceWatcher = NewServiceWatcher(enqueuefn)
 
 	localNetcfg, err := predicate.LabelSelectorPredicate(reflection.LocalResourcesLabelSelector())
 	utilruntime.Must(err)
 
-	return ctrl.NewControllerManagedBy(mgr).
+	bldr := ctrl.NewControllerManagedBy(mgr).
 		For(&discoveryv1alpha1.ForeignCluster{}).
 		Owns(&netv1alpha1.NetworkConfig{}, builder.WithPredicates(
 			predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}), localNetcfg)).
 		Watches(&corev1.Secret{}, ncc.secretWatcher.Handlers(), builder.WithPredicates(ncc.secretWatcher.Predicates())).
-		Watches(&corev1.Service{}, ncc.serviceWatcher.Handlers(), builder.WithPredicates(ncc.serviceWatcher.Predicates())).
-		Complete(ncc)
+		Watches(&corev1.Service{}, ncc.serviceWatcher.Handlers(), builder.WithPredicates(ncc.serviceWatcher.Predicates()))
+
+	if ncc.TransitRouting {
+		// Enqueue all foreign clusters when a TunnelEndpoint changes, to update the announced transit networks.
+		bldr = bldr.Watches(&netv1alpha1.TunnelEndpoint{}, handler.Funcs{
+			CreateFunc: func(_ context.Context, _ event.CreateEvent, rli workqueue.RateLimitingInterface) { enqueuefn(rli) },
+			UpdateFunc: func(_ context.Context, _ event.UpdateEvent, rli workqueue.RateLimitingInterface) { enqueuefn(rli) },
+			DeleteFunc: func(_ context.Context, _ event.DeleteEvent, rli workqueue.RateLimitingInterface) { enqueuefn(rli) },
+		}, builder.WithPredicates(predicate.GenerationChangedPredicate{}))
+	}
+
+	return bldr.Complete(ncc)
 }
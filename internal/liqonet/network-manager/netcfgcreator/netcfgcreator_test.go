@@ -71,11 +71,12 @@ var _ = Describe("NetworkConfigCreator Controller", func() {
 
 			PodCIDR:      "192.168.0.0/24",
 			ExternalCIDR: "192.168.1.0/24",
+			BackendType:  consts.DriverName,
 
 			foreignClusters: syncset.New(),
 			secretWatcher: &SecretWatcher{
-				wiregardPublicKey: "public-key",
-				configured:        true,
+				publicKey:  "public-key",
+				configured: true,
 			},
 			serviceWatcher: &ServiceWatcher{
 				endpointIP:   "1.1.1.1",
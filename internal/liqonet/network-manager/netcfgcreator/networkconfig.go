@@ -31,6 +31,7 @@ import (
 	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
 	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
 	"github.com/liqotech/liqo/pkg/consts"
+	liqonetutils "github.com/liqotech/liqo/pkg/liqonet/utils"
 	foreignclusterutils "github.com/liqotech/liqo/pkg/utils/foreignCluster"
 )
 
@@ -125,24 +126,66 @@ func (ncc *NetworkConfigCreator) EnforceNetworkConfigPresence(ctx context.Contex
 		return err
 	}
 
-	// Create the resource if not already present (if the error is not nil, then at this point is a not found one)
+	// Retrieve the networks the remote cluster can reach in transit through the local one
+	transitNetworks, err := ncc.getTransitNetworks(ctx, clusterID)
 	if err != nil {
-		return ncc.createNetworkConfig(ctx, fc)
+		return err
+	}
+
+	// Create the resource if not already present
+	if netcfg == nil {
+		return ncc.createNetworkConfig(ctx, fc, transitNetworks)
 	}
 
 	// Otherwise, update the resource to ensure it is up-to-date
-	return ncc.updateNetworkConfig(ctx, netcfg, fc)
+	return ncc.updateNetworkConfig(ctx, netcfg, fc, transitNetworks)
+}
+
+// getTransitNetworks returns the networks of the clusters the given remote cluster can reach in transit through
+// the local one, that is the PodCIDRs (as seen by the local cluster) of all the other clusters connected to it.
+// Only the IPv4 networks of the directly connected clusters are announced, hence limiting the path to a single hop.
+func (ncc *NetworkConfigCreator) getTransitNetworks(ctx context.Context, clusterID string) ([]netv1alpha1.TransitNetwork, error) {
+	if !ncc.TransitRouting {
+		return nil, nil
+	}
+
+	var teps netv1alpha1.TunnelEndpointList
+	if err := ncc.List(ctx, &teps); err != nil {
+		klog.Errorf("An error occurred while listing TunnelEndpoints: %v", err)
+		return nil, err
+	}
+
+	var transitNetworks []netv1alpha1.TransitNetwork
+	for i := range teps.Items {
+		tep := &teps.Items[i]
+		if tep.Spec.ClusterIdentity.ClusterID == clusterID || tep.Spec.RemotePodCIDR == "" {
+			continue
+		}
+
+		_, remotePodCIDR := liqonetutils.GetPodCIDRS(tep)
+		transitNetworks = append(transitNetworks, netv1alpha1.TransitNetwork{
+			ClusterIdentity: tep.Spec.ClusterIdentity,
+			PodCIDR:         remotePodCIDR,
+		})
+	}
+
+	// Sort the networks, to prevent unnecessary updates
+	sort.Slice(transitNetworks, func(i, j int) bool {
+		return transitNetworks[i].ClusterIdentity.ClusterID < transitNetworks[j].ClusterIdentity.ClusterID
+	})
+	return transitNetworks, nil
 }
 
 // createNetworkConfig creates a new local NetworkConfig associated with the given ForeignCluster.
-func (ncc *NetworkConfigCreator) createNetworkConfig(ctx context.Context, fc *discoveryv1alpha1.ForeignCluster) error {
+func (ncc *NetworkConfigCreator) createNetworkConfig(ctx context.Context, fc *discoveryv1alpha1.ForeignCluster,
+	transitNetworks []netv1alpha1.TransitNetwork) error {
 	netcfg := netv1alpha1.NetworkConfig{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      foreignclusterutils.UniqueName(&fc.Spec.ClusterIdentity),
 			Namespace: fc.Status.TenantNamespace.Local,
 		},
 	}
-	utilruntime.Must(ncc.populateNetworkConfig(&netcfg, fc))
+	utilruntime.Must(ncc.populateNetworkConfig(&netcfg, fc, transitNetworks))
 
 	if err := ncc.Create(ctx, &netcfg); err != nil {
 		klog.Errorf("An error occurred while creating NetworkConfig: %v", err)
@@ -154,10 +197,10 @@ func (ncc *NetworkConfigCreator) createNetworkConfig(ctx context.Context, fc *di
 
 // updateNetworkConfig ensures the local NetworkConfig associated with the given ForeignCluster is up-to-date.
 func (ncc *NetworkConfigCreator) updateNetworkConfig(ctx context.Context, netcfg *netv1alpha1.NetworkConfig,
-	fc *discoveryv1alpha1.ForeignCluster) error {
+	fc *discoveryv1alpha1.ForeignCluster, transitNetworks []netv1alpha1.TransitNetwork) error {
 	original := netcfg.DeepCopy()
 
-	if err := ncc.populateNetworkConfig(netcfg, fc); err != nil {
+	if err := ncc.populateNetworkConfig(netcfg, fc, transitNetworks); err != nil {
 		klog.Errorf("An error occurred while updating NetworkConfig %q: %v", klog.KObj(netcfg), err)
 		return err
 	}
@@ -176,7 +219,8 @@ func (ncc *NetworkConfigCreator) updateNetworkConfig(ctx context.Context, netcfg
 }
 
 // populateNetworkConfig sets the correct parameters of the NetworkConfig.
-func (ncc *NetworkConfigCreator) populateNetworkConfig(netcfg *netv1alpha1.NetworkConfig, fc *discoveryv1alpha1.ForeignCluster) error {
+func (ncc *NetworkConfigCreator) populateNetworkConfig(netcfg *netv1alpha1.NetworkConfig, fc *discoveryv1alpha1.ForeignCluster,
+	transitNetworks []netv1alpha1.TransitNetwork) error {
 	clusterIdentity := fc.Spec.ClusterIdentity
 
 	if netcfg.Labels == nil {
@@ -191,14 +235,31 @@ func (ncc *NetworkConfigCreator) populateNetworkConfig(netcfg *netv1alpha1.Netwo
 	netcfg.Spec.RemoteCluster = fc.Spec.ClusterIdentity
 	netcfg.Spec.PodCIDR = ncc.PodCIDR
 	netcfg.Spec.ExternalCIDR = ncc.ExternalCIDR
+	netcfg.Spec.PodCIDRv6 = ncc.PodCIDRv6
+	netcfg.Spec.ExternalCIDRv6 = ncc.ExternalCIDRv6
 	netcfg.Spec.EndpointIP = wgEndpointIP
-	netcfg.Spec.BackendType = consts.DriverName
+	netcfg.Spec.BackendType = ncc.BackendType
+	netcfg.Spec.TransitNetworks = transitNetworks
 
 	if netcfg.Spec.BackendConfig == nil {
 		netcfg.Spec.BackendConfig = map[string]string{}
 	}
-	netcfg.Spec.BackendConfig[consts.PublicKey] = ncc.secretWatcher.WiregardPublicKey()
+	netcfg.Spec.BackendConfig[consts.PublicKey] = ncc.secretWatcher.PublicKey()
 	netcfg.Spec.BackendConfig[consts.ListeningPort] = wgEndpointPort
+	if ncc.BackendTransport != "" {
+		netcfg.Spec.BackendConfig[consts.WgTransport] = ncc.BackendTransport
+	} else {
+		delete(netcfg.Spec.BackendConfig, consts.WgTransport)
+	}
+
+	// Publish the public key which is going to replace the current one, so that the peer can switch at the same time.
+	if nextPubKey, switchTime := ncc.secretWatcher.PendingPublicKey(); nextPubKey != "" {
+		netcfg.Spec.BackendConfig[consts.WgNextPublicKey] = nextPubKey
+		netcfg.Spec.BackendConfig[consts.WgKeySwitchTime] = switchTime
+	} else {
+		delete(netcfg.Spec.BackendConfig, consts.WgNextPublicKey)
+		delete(netcfg.Spec.BackendConfig, consts.WgKeySwitchTime)
+	}
 
 	return controllerutil.SetControllerReference(fc, netcfg, ncc.Scheme)
 }
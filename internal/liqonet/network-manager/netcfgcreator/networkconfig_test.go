@@ -59,10 +59,13 @@ var _ = Describe("Network config functions", func() {
 			Client: clientBuilder.Build(),
 			Scheme: scheme.Scheme,
 
-			PodCIDR:      "192.168.0.0/24",
-			ExternalCIDR: "192.168.1.0/24",
+			PodCIDR:        "192.168.0.0/24",
+			ExternalCIDR:   "192.168.1.0/24",
+			PodCIDRv6:      "fd00:1::/112",
+			ExternalCIDRv6: "fd00:2::/112",
+			BackendType:    consts.DriverName,
 
-			secretWatcher:  &SecretWatcher{wiregardPublicKey: "public-key"},
+			secretWatcher:  &SecretWatcher{publicKey: "public-key"},
 			serviceWatcher: &ServiceWatcher{endpointIP: "1.1.1.1", endpointPort: "9999"},
 		}
 	})
@@ -255,10 +258,14 @@ var _ = Describe("Network config functions", func() {
 				Expect(netcfg.Spec.RemoteCluster.ClusterID).To(BeIdenticalTo(clusterID))
 				Expect(netcfg.Spec.PodCIDR).To(BeIdenticalTo("192.168.0.0/24"))
 				Expect(netcfg.Spec.ExternalCIDR).To(BeIdenticalTo("192.168.1.0/24"))
+				Expect(netcfg.Spec.PodCIDRv6).To(BeIdenticalTo("fd00:1::/112"))
+				Expect(netcfg.Spec.ExternalCIDRv6).To(BeIdenticalTo("fd00:2::/112"))
 				Expect(netcfg.Spec.EndpointIP).To(BeIdenticalTo("1.1.1.1"))
 				Expect(netcfg.Spec.BackendType).To(BeIdenticalTo(consts.DriverName))
 				Expect(netcfg.Spec.BackendConfig).To(HaveKeyWithValue(consts.PublicKey, "public-key"))
 				Expect(netcfg.Spec.BackendConfig).To(HaveKeyWithValue(consts.ListeningPort, "9999"))
+				Expect(netcfg.Spec.BackendConfig).ToNot(HaveKey(consts.WgNextPublicKey))
+				Expect(netcfg.Spec.BackendConfig).ToNot(HaveKey(consts.WgKeySwitchTime))
 			}
 
 			When("the network config associated with the given foreign cluster does not exist", func() {
@@ -314,6 +321,49 @@ var _ = Describe("Network config functions", func() {
 					AssertNetworkConfigSpec(netcfg)
 				})
 			})
+			When("transit routing is enabled", func() {
+				tunnelEndpoint := func(id, podCIDR, natPodCIDR string) *netv1alpha1.TunnelEndpoint {
+					return &netv1alpha1.TunnelEndpoint{
+						ObjectMeta: metav1.ObjectMeta{Name: id, Namespace: "liqo-tenant-" + id},
+						Spec: netv1alpha1.TunnelEndpointSpec{
+							ClusterIdentity:  discoveryv1alpha1.ClusterIdentity{ClusterID: id, ClusterName: id},
+							RemotePodCIDR:    podCIDR,
+							RemoteNATPodCIDR: natPodCIDR,
+						},
+					}
+				}
+
+				BeforeEach(func() {
+					clientBuilder.WithObjects(
+						tunnelEndpoint(clusterID, "10.0.0.0/16", consts.DefaultCIDRValue),
+						tunnelEndpoint("spoke-2", "10.2.0.0/16", "10.202.0.0/16"),
+						tunnelEndpoint("spoke-1", "10.1.0.0/16", consts.DefaultCIDRValue),
+					)
+				})
+
+				JustBeforeEach(func() {
+					fcw.TransitRouting = true
+					err = fcw.EnforceNetworkConfigPresence(ctx, fc)
+				})
+
+				It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+				It("should announce the networks of the other clusters, as seen by the local one", func() {
+					netcfg, err := GetLocalNetworkConfig(ctx, fcw.Client, labels, clusterID, namespace)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(netcfg.Spec.TransitNetworks).To(Equal([]netv1alpha1.TransitNetwork{
+						{ClusterIdentity: discoveryv1alpha1.ClusterIdentity{ClusterID: "spoke-1", ClusterName: "spoke-1"}, PodCIDR: "10.1.0.0/16"},
+						{ClusterIdentity: discoveryv1alpha1.ClusterIdentity{ClusterID: "spoke-2", ClusterName: "spoke-2"}, PodCIDR: "10.202.0.0/16"},
+					}))
+				})
+			})
+
+			When("transit routing is disabled", func() {
+				It("should not announce any transit network", func() {
+					netcfg, err := GetLocalNetworkConfig(ctx, fcw.Client, labels, clusterID, namespace)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(netcfg.Spec.TransitNetworks).To(BeEmpty())
+				})
+			})
 		})
 
 		Describe("The EnforceNetworkConfigAbsence function", func() {
@@ -16,7 +16,9 @@ package netcfgcreator
 
 import (
 	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -27,14 +29,19 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqonet/tunnel/ipsec"
 	"github.com/liqotech/liqo/pkg/utils/getters"
 	liqolabels "github.com/liqotech/liqo/pkg/utils/labels"
 )
 
-// SecretWatcher reconciles Secret objects to retrieve the Wireguard public key.
+// SecretWatcher reconciles Secret objects to retrieve the public key of the tunnel driver.
 type SecretWatcher struct {
 	sync.RWMutex
-	wiregardPublicKey string
+	backendType string
+	publicKey   string
+	// nextPublicKey and keySwitchTime describe the pending key rotation, if any.
+	nextPublicKey string
+	keySwitchTime string
 
 	configured bool
 	wait       chan struct{}
@@ -42,22 +49,32 @@ type SecretWatcher struct {
 	enqueuefn func(workqueue.RateLimitingInterface)
 }
 
-// NewSecretWatcher returns a new initialized SecretWatcher instance.
-func NewSecretWatcher(enqueuefn func(workqueue.RateLimitingInterface)) *SecretWatcher {
+// NewSecretWatcher returns a new initialized SecretWatcher instance, retrieving the public key of the given tunnel driver.
+func NewSecretWatcher(backendType string, enqueuefn func(workqueue.RateLimitingInterface)) *SecretWatcher {
 	return &SecretWatcher{
-		configured: false,
-		wait:       make(chan struct{}),
+		backendType: backendType,
+		configured:  false,
+		wait:        make(chan struct{}),
 
 		enqueuefn: enqueuefn,
 	}
 }
 
-// WiregardPublicKey returns the retrieved Wireguard public key.
-func (sw *SecretWatcher) WiregardPublicKey() string {
+// PublicKey returns the retrieved public key of the tunnel driver.
+func (sw *SecretWatcher) PublicKey() string {
 	sw.RLock()
 	defer sw.RUnlock()
 
-	return sw.wiregardPublicKey
+	return sw.publicKey
+}
+
+// PendingPublicKey returns the public key which is going to replace the current one, along with the switch time.
+// Both values are empty if no key rotation is pending.
+func (sw *SecretWatcher) PendingPublicKey() (publicKey, switchTime string) {
+	sw.RLock()
+	defer sw.RUnlock()
+
+	return sw.nextPublicKey, sw.keySwitchTime
 }
 
 // WaitForConfigured waits until a valid key is retrieved for the first time.
@@ -98,7 +115,7 @@ func (sw *SecretWatcher) Handlers() handler.EventHandler {
 
 // Predicates returns the set of predicates used for the Watch configuration.
 func (sw *SecretWatcher) Predicates() predicate.Predicate {
-	secretsPredicate, err := predicate.LabelSelectorPredicate(liqolabels.WireGuardSecretLabelSelector)
+	secretsPredicate, err := predicate.LabelSelectorPredicate(liqolabels.TunnelKeysSecretLabelSelector(keysDriverName(sw.backendType)))
 	utilruntime.Must(err)
 
 	return secretsPredicate
@@ -111,20 +128,31 @@ func (sw *SecretWatcher) handle(secret *corev1.Secret, rli workqueue.RateLimitin
 	sw.Lock()
 	defer sw.Unlock()
 
-	pubKey, err := getters.RetrieveWGPubKeyFromSecret(secret, consts.PublicKey)
+	pubKey, err := retrievePublicKey(secret, sw.backendType)
+	if err != nil {
+		klog.Error(err)
+		return
+	}
+
+	nextPubKey, switchTime, err := retrievePendingPublicKey(secret, sw.backendType)
 	if err != nil {
 		klog.Error(err)
 		return
 	}
 
-	// The key did not change, nothing to do
-	if pubKey.String() == sw.wiregardPublicKey {
+	// The keys did not change, nothing to do
+	if pubKey == sw.publicKey && nextPubKey == sw.nextPublicKey && switchTime == sw.keySwitchTime {
 		return
 	}
 
-	// Configure the new key, and set as configured if not yet done
-	klog.Infof("Wiregard public key correctly retrieved")
-	sw.wiregardPublicKey = pubKey.String()
+	// Configure the new keys, and set as configured if not yet done
+	klog.Infof("Public key of the %s tunnel driver correctly retrieved", sw.backendType)
+	if nextPubKey != "" {
+		klog.Infof("Public key of the %s tunnel driver is going to be rotated at %s", sw.backendType, switchTime)
+	}
+	sw.publicKey = pubKey
+	sw.nextPublicKey = nextPubKey
+	sw.keySwitchTime = switchTime
 	if !sw.configured {
 		close(sw.wait)
 		sw.configured = true
@@ -133,3 +161,54 @@ func (sw *SecretWatcher) handle(secret *corev1.Secret, rli workqueue.RateLimitin
 	// Enqueue all foreign clusters for update (which in turn update the respective network configs)
 	sw.enqueuefn(rli)
 }
+
+// retrievePublicKey retrieves and validates the public key of the given tunnel driver from a secret.
+func retrievePublicKey(secret *corev1.Secret, backendType string) (string, error) {
+	switch backendType {
+	case consts.IPsecDriverName:
+		pubKey, found := secret.Data[consts.PublicKey]
+		if !found {
+			return "", fmt.Errorf("no data with key %s found in secret %q", consts.PublicKey, klog.KObj(secret))
+		}
+		if _, err := ipsec.ParsePublicKey(string(pubKey)); err != nil {
+			return "", fmt.Errorf("secret %q: invalid public key: %w", klog.KObj(secret), err)
+		}
+		return string(pubKey), nil
+	default:
+		pubKey, err := getters.RetrieveWGPubKeyFromSecret(secret, consts.PublicKey)
+		if err != nil {
+			return "", err
+		}
+		return pubKey.String(), nil
+	}
+}
+
+// retrievePendingPublicKey retrieves and validates the public key which is going to replace the current one,
+// along with the switch time, in case a key rotation is pending. Key rotation is supported by the WireGuard driver only.
+func retrievePendingPublicKey(secret *corev1.Secret, backendType string) (pubKey, switchTime string, err error) {
+	if keysDriverName(backendType) != consts.DriverName {
+		return "", "", nil
+	}
+	if _, found := secret.Data[consts.WgNextPublicKey]; !found {
+		return "", "", nil
+	}
+
+	key, err := getters.RetrieveWGPubKeyFromSecret(secret, consts.WgNextPublicKey)
+	if err != nil {
+		return "", "", err
+	}
+	switchTime = string(secret.Data[consts.WgKeySwitchTime])
+	if _, err := time.Parse(time.RFC3339, switchTime); err != nil {
+		return "", "", fmt.Errorf("secret %q: invalid key switch time: %w", klog.KObj(secret), err)
+	}
+	return key.String(), switchTime, nil
+}
+
+// keysDriverName returns the name of the driver the secret containing the keys of the given tunnel driver refers to.
+// The WireGuard over TCP driver leverages the same keys of the standard WireGuard driver.
+func keysDriverName(backendType string) string {
+	if backendType == consts.WgTCPDriverName {
+		return consts.DriverName
+	}
+	return backendType
+}
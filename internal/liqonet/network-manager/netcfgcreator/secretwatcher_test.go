@@ -25,6 +25,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 
 	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqonet/tunnel/ipsec"
 )
 
 var _ = Describe("Secret Watcher functions", func() {
@@ -37,7 +38,7 @@ var _ = Describe("Secret Watcher functions", func() {
 
 	BeforeEach(func() {
 		handled = make(chan struct{})
-		sw = NewSecretWatcher(func(rli workqueue.RateLimitingInterface) { close(handled) })
+		sw = NewSecretWatcher(consts.DriverName, func(rli workqueue.RateLimitingInterface) { close(handled) })
 		secret = corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}
 	})
 
@@ -52,32 +53,110 @@ var _ = Describe("Secret Watcher functions", func() {
 			})
 
 			When("not yet initialized", func() {
-				It("should retrieve the correct public key", func() { Expect(sw.WiregardPublicKey()).To(BeIdenticalTo(key)) })
+				It("should retrieve the correct public key", func() { Expect(sw.PublicKey()).To(BeIdenticalTo(key)) })
 				It("should execute the handle function", func() { Expect(handled).To(BeClosed()) })
 				It("should be initialized", func() { Expect(sw.configured).To(BeTrue()) })
 			})
 
 			When("already initialized", func() {
 				BeforeEach(func() {
-					sw.wiregardPublicKey = "previous-public-key"
+					sw.publicKey = "previous-public-key"
 					sw.configured = true
 				})
 
-				It("should retrieve the correct public key", func() { Expect(sw.WiregardPublicKey()).To(BeIdenticalTo(key)) })
+				It("should retrieve the correct public key", func() { Expect(sw.PublicKey()).To(BeIdenticalTo(key)) })
 				It("should execute the handle function", func() { Expect(handled).To(BeClosed()) })
 				It("should be initialized", func() { Expect(sw.configured).To(BeTrue()) })
 			})
 		})
 
+		When("given a secret with a pending key rotation", func() {
+			const (
+				nextKey    = "bmV4dC1wdWJsaWMta2V5LW9mLXRoZS1yaWdodC1sZW4="
+				switchTime = "2023-01-01T10:00:00Z"
+			)
+
+			BeforeEach(func() {
+				secret.Data = map[string][]byte{consts.PublicKey: []byte(key),
+					consts.WgNextPublicKey: []byte(nextKey), consts.WgKeySwitchTime: []byte(switchTime)}
+				sw.publicKey = key
+				sw.configured = true
+			})
+
+			It("should retrieve the correct public key", func() { Expect(sw.PublicKey()).To(BeIdenticalTo(key)) })
+			It("should retrieve the correct pending public key", func() {
+				pending, at := sw.PendingPublicKey()
+				Expect(pending).To(BeIdenticalTo(nextKey))
+				Expect(at).To(BeIdenticalTo(switchTime))
+			})
+			It("should execute the handle function", func() { Expect(handled).To(BeClosed()) })
+		})
+
+		When("given a secret with an invalid key switch time", func() {
+			BeforeEach(func() {
+				secret.Data = map[string][]byte{consts.PublicKey: []byte(key),
+					consts.WgNextPublicKey: []byte(key), consts.WgKeySwitchTime: []byte("invalid")}
+			})
+
+			It("should leave the public key unmodified", func() { Expect(sw.PublicKey()).To(BeIdenticalTo("")) })
+			It("should not execute the handle function", func() { Expect(handled).ToNot(BeClosed()) })
+		})
+
 		When("given an invalid secret", func() {
 			BeforeEach(func() {
 				secret.Data = map[string][]byte{"incorrect-key": []byte(key)}
 			})
 
-			It("should leave the public key unmodified", func() { Expect(sw.WiregardPublicKey()).To(BeIdenticalTo("")) })
+			It("should leave the public key unmodified", func() { Expect(sw.PublicKey()).To(BeIdenticalTo("")) })
 			It("should not execute the handle function", func() { Expect(handled).ToNot(BeClosed()) })
 			It("should not be initialized", func() { Expect(sw.configured).To(BeFalse()) })
 		})
+
+		When("the backend type is wireguard-tcp", func() {
+			const nextKey = "bmV4dC1wdWJsaWMta2V5LW9mLXRoZS1yaWdodC1sZW4="
+
+			BeforeEach(func() {
+				sw.backendType = consts.WgTCPDriverName
+				secret.Data = map[string][]byte{consts.PublicKey: []byte(key),
+					consts.WgNextPublicKey: []byte(nextKey), consts.WgKeySwitchTime: []byte("2023-01-01T10:00:00Z")}
+			})
+
+			It("should retrieve the correct public key", func() { Expect(sw.PublicKey()).To(BeIdenticalTo(key)) })
+			It("should retrieve the correct pending public key", func() {
+				pending, _ := sw.PendingPublicKey()
+				Expect(pending).To(BeIdenticalTo(nextKey))
+			})
+			It("should execute the handle function", func() { Expect(handled).To(BeClosed()) })
+		})
+
+		When("the backend type is ipsec", func() {
+			BeforeEach(func() { sw.backendType = consts.IPsecDriverName })
+
+			When("given a valid secret", func() {
+				var ipsecKey string
+
+				BeforeEach(func() {
+					priv, err := ipsec.GeneratePrivateKey()
+					Expect(err).ToNot(HaveOccurred())
+					ipsecKey = ipsec.EncodePublicKey(priv.PublicKey())
+					secret.Data = map[string][]byte{consts.PublicKey: []byte(ipsecKey)}
+				})
+
+				It("should retrieve the correct public key", func() { Expect(sw.PublicKey()).To(BeIdenticalTo(ipsecKey)) })
+				It("should execute the handle function", func() { Expect(handled).To(BeClosed()) })
+				It("should be initialized", func() { Expect(sw.configured).To(BeTrue()) })
+			})
+
+			When("given a secret with an invalid key", func() {
+				BeforeEach(func() {
+					secret.Data = map[string][]byte{consts.PublicKey: []byte(key)}
+				})
+
+				It("should leave the public key unmodified", func() { Expect(sw.PublicKey()).To(BeIdenticalTo("")) })
+				It("should not execute the handle function", func() { Expect(handled).ToNot(BeClosed()) })
+				It("should not be initialized", func() { Expect(sw.configured).To(BeFalse()) })
+			})
+		})
 	})
 
 	Describe("The WaitForConfigured function", func() {
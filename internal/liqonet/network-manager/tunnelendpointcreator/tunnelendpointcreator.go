@@ -28,15 +28,20 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/utils/trace"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
 	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
 	"github.com/liqotech/liqo/internal/liqonet/network-manager/netcfgcreator"
 	liqoconst "github.com/liqotech/liqo/pkg/consts"
 	liqonetIpam "github.com/liqotech/liqo/pkg/liqonet/ipam"
+	liqonetutils "github.com/liqotech/liqo/pkg/liqonet/utils"
 	liqonetsignals "github.com/liqotech/liqo/pkg/liqonet/utils/signals"
 	"github.com/liqotech/liqo/pkg/utils"
 	foreignclusterutils "github.com/liqotech/liqo/pkg/utils/foreignCluster"
@@ -56,6 +61,18 @@ type networkParam struct {
 	localPodCIDR          string
 	localExternalCIDR     string
 	localNatExternalCIDR  string
+	// IPv6 networks, set only in case both clusters are dual-stack.
+	remotePodCIDRv6         string
+	remoteNatPodCIDRv6      string
+	remoteExternalCIDRv6    string
+	remoteNatExternalCIDRv6 string
+	localPodCIDRv6          string
+	localNatPodCIDRv6       string
+	localExternalCIDRv6     string
+	localNatExternalCIDRv6  string
+	// Networks of the clusters reachable in transit through the remote cluster, and vice versa.
+	remoteTransitNetworks []netv1alpha1.TransitNetworkMapping
+	localTransitNetworks  []netv1alpha1.TransitNetworkMapping
 	backendType           string
 	backendConfig         map[string]string
 }
@@ -65,6 +82,8 @@ type TunnelEndpointCreator struct {
 	client.Client
 	Scheme    *runtime.Scheme
 	IPManager liqonetIpam.Ipam
+	// DualStack enables the configuration of the IPv6 networks, for the remote clusters which are dual-stack as well.
+	DualStack bool
 }
 
 // rbac for the net.liqo.io api
@@ -167,9 +186,41 @@ func (tec *TunnelEndpointCreator) SetupWithManager(mgr ctrl.Manager) error {
 		For(&netv1alpha1.NetworkConfig{}).
 		Watches(&netv1alpha1.TunnelEndpoint{},
 			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &netv1alpha1.NetworkConfig{})).
+		// Reconcile the NetworkConfigs announcing transit networks when a direct peering is established or torn down,
+		// as the clusters directly peered are not reached in transit through a third one.
+		Watches(&netv1alpha1.NetworkConfig{}, handler.EnqueueRequestsFromMapFunc(tec.transitNetworkConfigsMapper),
+			builder.WithPredicates(predicate.Funcs{
+				UpdateFunc:  func(event.UpdateEvent) bool { return false },
+				GenericFunc: func(event.GenericEvent) bool { return false },
+			})).
 		Complete(tec)
 }
 
+// transitNetworkConfigsMapper returns the remote NetworkConfigs announcing the cluster the given NetworkConfig refers to as reachable in transit.
+func (tec *TunnelEndpointCreator) transitNetworkConfigsMapper(ctx context.Context, obj client.Object) []reconcile.Request {
+	clusterID, found := obj.GetLabels()[liqoconst.ReplicationOriginLabel]
+	if !found {
+		return nil
+	}
+
+	var netcfgs netv1alpha1.NetworkConfigList
+	if err := tec.List(ctx, &netcfgs, client.HasLabels{liqoconst.ReplicationOriginLabel}); err != nil {
+		klog.Errorf("Failed to list remote NetworkConfigs: %v", err)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range netcfgs.Items {
+		for j := range netcfgs.Items[i].Spec.TransitNetworks {
+			if netcfgs.Items[i].Spec.TransitNetworks[j].ClusterIdentity.ClusterID == clusterID {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&netcfgs.Items[i])})
+				break
+			}
+		}
+	}
+	return requests
+}
+
 // SetupSignalHandlerForTunEndCreator registers for SIGTERM, SIGINT, SIGKILL. A stop channel is returned
 // which is closed on one of these signals.
 func (tec *TunnelEndpointCreator) SetupSignalHandlerForTunEndCreator() context.Context {
@@ -241,6 +292,13 @@ func (tec *TunnelEndpointCreator) processNetworkConfig(ctx context.Context, clus
 		klog.Errorf("Failed to add local subnets to IPAM for cluster %s: %v", local.Spec.RemoteCluster, err)
 		return err
 	}
+	// The IPv6 networks are configured only if the remote cluster remapped them, that is if both clusters are dual-stack.
+	if local.Status.PodCIDRNATv6 != "" {
+		if err := tec.IPManager.AddLocalIPv6SubnetsPerCluster(local.Status.PodCIDRNATv6, local.Status.ExternalCIDRNATv6, clusterID); err != nil {
+			klog.Errorf("Failed to add local IPv6 subnets to IPAM for cluster %s: %v", local.Spec.RemoteCluster, err)
+			return err
+		}
+	}
 	tracer.Step("IPAM configuration")
 
 	// If we reached this point, then it is possible to enforce the TunnelEndpoint resource
@@ -285,7 +343,6 @@ func (tec *TunnelEndpointCreator) enforceRemoteNetConfigStatus(ctx context.Conte
 		klog.Errorf("An error occurred while getting a new subnet for resource %q: %v", klog.KObj(netcfg), err)
 		return err
 	}
-	tracer.Step("CIDR remappings retrieval")
 
 	// Set the default values in case the CIDRs have not been remapped
 	if podCIDR == netcfg.Spec.PodCIDR {
@@ -295,11 +352,38 @@ func (tec *TunnelEndpointCreator) enforceRemoteNetConfigStatus(ctx context.Conte
 		externalCIDR = liqoconst.DefaultCIDRValue
 	}
 
+	// Get the IPv6 CIDR remappings, in case both clusters are dual-stack
+	var podCIDRv6, externalCIDRv6 string
+	if tec.DualStack && netcfg.Spec.PodCIDRv6 != "" && netcfg.Spec.ExternalCIDRv6 != "" {
+		podCIDRv6, externalCIDRv6, err = tec.IPManager.GetSubnetsPerCluster(netcfg.Spec.PodCIDRv6, netcfg.Spec.ExternalCIDRv6, clusterID)
+		if err != nil {
+			klog.Errorf("An error occurred while getting a new IPv6 subnet for resource %q: %v", klog.KObj(netcfg), err)
+			return err
+		}
+		if podCIDRv6 == netcfg.Spec.PodCIDRv6 {
+			podCIDRv6 = liqoconst.DefaultCIDRValue
+		}
+		if externalCIDRv6 == netcfg.Spec.ExternalCIDRv6 {
+			externalCIDRv6 = liqoconst.DefaultCIDRValue
+		}
+	}
+
+	// Get the remappings of the networks reachable in transit through the remote cluster
+	transitNetworksNAT, err := tec.getTransitNetworksNAT(ctx, netcfg, clusterID)
+	if err != nil {
+		klog.Errorf("An error occurred while getting the transit subnets for resource %q: %v", klog.KObj(netcfg), err)
+		return err
+	}
+	tracer.Step("CIDR remappings retrieval")
+
 	// Update the status fields
 	original := netcfg.Status.DeepCopy()
 	netcfg.Status.Processed = true
 	netcfg.Status.PodCIDRNAT = podCIDR
 	netcfg.Status.ExternalCIDRNAT = externalCIDR
+	netcfg.Status.PodCIDRNATv6 = podCIDRv6
+	netcfg.Status.ExternalCIDRNATv6 = externalCIDRv6
+	netcfg.Status.TransitNetworksNAT = transitNetworksNAT
 
 	// Avoid performing updates in case it is not necessary
 	if !reflect.DeepEqual(original, netcfg.Status) {
@@ -312,6 +396,64 @@ func (tec *TunnelEndpointCreator) enforceRemoteNetConfigStatus(ctx context.Conte
 	return nil
 }
 
+// getTransitNetworksNAT returns the networks used to reach the clusters announced as reachable in transit by the given
+// remote NetworkConfig, keyed by cluster ID. The clusters directly peered with the local one are skipped, as well as
+// the IPv6 networks, since not supported.
+func (tec *TunnelEndpointCreator) getTransitNetworksNAT(ctx context.Context, netcfg *netv1alpha1.NetworkConfig,
+	clusterID string) (map[string]string, error) {
+	transitNetworks := make(map[string]string, len(netcfg.Spec.TransitNetworks))
+	for i := range netcfg.Spec.TransitNetworks {
+		tn := &netcfg.Spec.TransitNetworks[i]
+		if liqonetutils.IsIPv6CIDR(tn.PodCIDR) {
+			continue
+		}
+
+		_, err := netcfgcreator.GetRemoteNetworkConfig(ctx, tec.Client, tn.ClusterIdentity.ClusterID, "")
+		if client.IgnoreNotFound(err) != nil {
+			return nil, err
+		}
+		if err == nil {
+			klog.V(4).Infof("Cluster %v is directly peered, skipping the transit network announced by cluster %v", tn.ClusterIdentity, clusterID)
+			continue
+		}
+		transitNetworks[tn.ClusterIdentity.ClusterID] = tn.PodCIDR
+	}
+
+	// Invoked also when no transit networks are announced, to free the ones possibly previously assigned
+	mapped, err := tec.IPManager.GetTransitSubnetsPerCluster(transitNetworks, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(mapped) == 0 {
+		return nil, nil
+	}
+	for transitClusterID, podCIDR := range mapped {
+		// Set the default value in case the CIDR has not been remapped
+		if podCIDR == transitNetworks[transitClusterID] {
+			mapped[transitClusterID] = liqoconst.DefaultCIDRValue
+		}
+	}
+	return mapped, nil
+}
+
+// getTransitNetworkMappings returns the transit networks announced by the given NetworkConfig, along with the
+// corresponding remappings performed by the destination cluster. Networks not yet processed are skipped.
+func getTransitNetworkMappings(netcfg *netv1alpha1.NetworkConfig) []netv1alpha1.TransitNetworkMapping {
+	var mappings []netv1alpha1.TransitNetworkMapping
+	for i := range netcfg.Spec.TransitNetworks {
+		tn := &netcfg.Spec.TransitNetworks[i]
+		if natPodCIDR, found := netcfg.Status.TransitNetworksNAT[tn.ClusterIdentity.ClusterID]; found {
+			mappings = append(mappings, netv1alpha1.TransitNetworkMapping{
+				ClusterIdentity: tn.ClusterIdentity,
+				PodCIDR:         tn.PodCIDR,
+				NATPodCIDR:      natPodCIDR,
+			})
+		}
+	}
+	return mappings
+}
+
 func (tec *TunnelEndpointCreator) enforceTunnelEndpoint(ctx context.Context, local, remote *netv1alpha1.NetworkConfig) error {
 	tracer := trace.FromContext(ctx)
 
@@ -328,9 +470,22 @@ func (tec *TunnelEndpointCreator) enforceTunnelEndpoint(ctx context.Context, loc
 		localPodCIDR:          local.Spec.PodCIDR,
 		localExternalCIDR:     local.Spec.ExternalCIDR,
 		localNatExternalCIDR:  local.Status.ExternalCIDRNAT,
+		remoteTransitNetworks: getTransitNetworkMappings(remote),
+		localTransitNetworks:  getTransitNetworkMappings(local),
 		backendType:           remote.Spec.BackendType,
 		backendConfig:         remote.Spec.BackendConfig,
 	}
+	// The IPv6 networks are used only once both clusters have remapped them, that is if both clusters are dual-stack.
+	if local.Status.PodCIDRNATv6 != "" && remote.Status.PodCIDRNATv6 != "" {
+		param.remotePodCIDRv6 = remote.Spec.PodCIDRv6
+		param.remoteNatPodCIDRv6 = remote.Status.PodCIDRNATv6
+		param.remoteExternalCIDRv6 = remote.Spec.ExternalCIDRv6
+		param.remoteNatExternalCIDRv6 = remote.Status.ExternalCIDRNATv6
+		param.localPodCIDRv6 = local.Spec.PodCIDRv6
+		param.localNatPodCIDRv6 = local.Status.PodCIDRNATv6
+		param.localExternalCIDRv6 = local.Spec.ExternalCIDRv6
+		param.localNatExternalCIDRv6 = local.Status.ExternalCIDRNATv6
+	}
 
 	// Try to get the tunnelEndpoint, which may not exist
 	_, err := getters.GetTunnelEndpoint(ctx, tec.Client, &param.remoteCluster, local.GetNamespace())
@@ -425,6 +580,16 @@ func (tec *TunnelEndpointCreator) fillTunnelEndpointSpec(tep *netv1alpha1.Tunnel
 	tep.Spec.RemoteNATPodCIDR = param.remoteNatPodCIDR
 	tep.Spec.RemoteExternalCIDR = param.remoteExternalCIDR
 	tep.Spec.RemoteNATExternalCIDR = param.remoteNatExternalCIDR
+	tep.Spec.LocalPodCIDRv6 = param.localPodCIDRv6
+	tep.Spec.LocalExternalCIDRv6 = param.localExternalCIDRv6
+	tep.Spec.LocalNATPodCIDRv6 = param.localNatPodCIDRv6
+	tep.Spec.LocalNATExternalCIDRv6 = param.localNatExternalCIDRv6
+	tep.Spec.RemotePodCIDRv6 = param.remotePodCIDRv6
+	tep.Spec.RemoteNATPodCIDRv6 = param.remoteNatPodCIDRv6
+	tep.Spec.RemoteExternalCIDRv6 = param.remoteExternalCIDRv6
+	tep.Spec.RemoteNATExternalCIDRv6 = param.remoteNatExternalCIDRv6
+	tep.Spec.RemoteTransitNetworks = param.remoteTransitNetworks
+	tep.Spec.LocalTransitNetworks = param.localTransitNetworks
 	tep.Spec.EndpointIP = param.remoteEndpointIP
 	tep.Spec.BackendType = param.backendType
 	tep.Spec.BackendConfig = param.backendConfig
@@ -18,7 +18,7 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/coreos/go-iptables/iptables"
+	"github.com/liqotech/liqo/pkg/liqonet/firewall"
 )
 
 const (
@@ -40,6 +40,8 @@ func (fr *firewallRule) String() string {
 }
 
 // generateRules generates the firewall rules for the given overlay interface.
+// When leveraging the nftables backend, the accept verdicts do not override the drop verdicts of the base chains
+// configured on the host by other tables, which need to explicitly accept the traffic from/to the overlay interface.
 func generateRules(ifaceName string) []firewallRule {
 	comment := fmt.Sprintf("LIQO accept traffic from/to overlay interface %s", ifaceName)
 	return []firewallRule{
@@ -62,11 +64,11 @@ func generateRules(ifaceName string) []firewallRule {
 }
 
 // addRule appends the rule if it does not exist.
-func addRule(ipt *iptables.IPTables, rule *firewallRule) error {
+func addRule(ipt firewall.Firewall, rule *firewallRule) error {
 	return ipt.AppendUnique(rule.table, rule.chain, rule.rule...)
 }
 
 // deleteRule removes the rule if it exists.
-func deleteRule(ipt *iptables.IPTables, rule *firewallRule) error {
+func deleteRule(ipt firewall.Firewall, rule *firewallRule) error {
 	return ipt.DeleteIfExists(rule.table, rule.chain, rule.rule...)
 }
@@ -160,7 +160,7 @@ func (rc *RouteController) ConfigureFirewall() error {
 	if err != nil {
 		return err
 	}
-	iptHandler := &ipt.Ipt
+	iptHandler := ipt.Ipt
 	rc.firewallChan = make(chan bool)
 	fwRules := generateRules(rc.vxlanDev.Link.Name)
 
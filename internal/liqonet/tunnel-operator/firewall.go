@@ -21,11 +21,11 @@ import (
 	"sync"
 	"time"
 
-	"github.com/coreos/go-iptables/iptables"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 
+	"github.com/liqotech/liqo/pkg/liqonet/firewall"
 	liqonetsignals "github.com/liqotech/liqo/pkg/liqonet/utils/signals"
 )
 
@@ -44,7 +44,7 @@ func (fr *firewallRule) String() string {
 	return strings.Join([]string{fr.table, fr.chain, ruleString}, " ")
 }
 
-func enforceFirewallRules(ctx context.Context, wg *sync.WaitGroup, ipt *iptables.IPTables, ifaceName string) {
+func enforceFirewallRules(ctx context.Context, wg *sync.WaitGroup, ipt firewall.Firewall, ifaceName string) {
 	wg.Add(1)
 	defer wg.Done()
 	err := wait.PollUntilContextCancel(ctx, 5*time.Second, false, func(ctx context.Context) (done bool, err error) {
@@ -63,6 +63,8 @@ func enforceFirewallRules(ctx context.Context, wg *sync.WaitGroup, ipt *iptables
 }
 
 // generateRules generates the firewall rules for the given overlay interface.
+// When leveraging the nftables backend, the accept verdicts do not override the drop verdicts of the base chains
+// configured on the host by other tables, which need to explicitly accept the traffic from/to the overlay interface.
 func generateForwardingRules(ifaceName string) []firewallRule {
 	comment := fmt.Sprintf("LIQO accept traffic from/to overlay interface %s", ifaceName)
 	return []firewallRule{
@@ -85,11 +87,11 @@ func generateForwardingRules(ifaceName string) []firewallRule {
 }
 
 // addRule appends the rule if it does not exist.
-func addRule(ipt *iptables.IPTables, rule *firewallRule) error {
+func addRule(ipt firewall.Firewall, rule *firewallRule) error {
 	return ipt.AppendUnique(rule.table, rule.chain, rule.rule...)
 }
 
 // deleteRule removes the rule if it exists.
-func deleteRule(ipt *iptables.IPTables, rule *firewallRule) error {
+func deleteRule(ipt firewall.Firewall, rule *firewallRule) error {
 	return ipt.DeleteIfExists(rule.table, rule.chain, rule.rule...)
 }
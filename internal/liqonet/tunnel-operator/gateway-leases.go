@@ -0,0 +1,289 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tunneloperator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	liqolabels "github.com/liqotech/liqo/pkg/utils/labels"
+)
+
+// tunnelLease tracks the lease designating the gateway replica in charge of the tunnel towards a remote cluster (hot-standby mode).
+type tunnelLease struct {
+	tep types.NamespacedName
+	// holder and renewTime are the last observed values of the lease, and observedTime is the time (according to the local clock)
+	// at which they have been observed. Expirations are computed from the latter, to be robust to clock skews between the replicas.
+	holder       string
+	renewTime    time.Time
+	observedTime time.Time
+	// renewedTime is the time of the last successful renewal of the lease by the current replica, or zero if not held.
+	renewedTime time.Time
+	// failedOver is true if the current replica released the lease since it failed to connect to the remote cluster,
+	// until another replica acquires it.
+	failedOver bool
+	// disconnectedTime is the time since which the current replica reports the remote cluster as disconnected, if any.
+	disconnectedTime time.Time
+}
+
+// observe updates the tracked status of the lease, given its current content.
+func (tl *tunnelLease) observe(lease *coordinationv1.Lease, replica string, now time.Time) {
+	holder := pointer.StringDeref(lease.Spec.HolderIdentity, "")
+	var renewTime time.Time
+	if lease.Spec.RenewTime != nil {
+		renewTime = lease.Spec.RenewTime.Time
+	}
+
+	if tl.observedTime.IsZero() || holder != tl.holder || !renewTime.Equal(tl.renewTime) {
+		tl.holder, tl.renewTime, tl.observedTime = holder, renewTime, now
+	}
+	if holder != "" && holder != replica {
+		tl.failedOver = false
+	}
+}
+
+// canAcquire returns whether the given replica can acquire (or renew) the lease. A lease is available if it has been released,
+// or it has not been renewed for leaseDuration since last observed. The preferred replica (i.e., the one selected by the gateway
+// service) acquires the available leases immediately, while the other ones wait for an additional leaseDuration (twice as much
+// if they failed over), so that the tunnels are moved away from the preferred replica only if it fails to take them over.
+func (tl *tunnelLease) canAcquire(replica string, preferred bool, leaseDuration time.Duration, now time.Time) bool {
+	if tl.holder == replica {
+		return true
+	}
+
+	availableTime := tl.observedTime
+	if tl.holder != "" {
+		availableTime = availableTime.Add(leaseDuration)
+	}
+	switch {
+	case tl.failedOver:
+		availableTime = availableTime.Add(2 * leaseDuration)
+	case !preferred:
+		availableTime = availableTime.Add(leaseDuration)
+	}
+	return !now.Before(availableTime)
+}
+
+// tunnelLeaseName returns the name of the lease designating the gateway replica in charge of the tunnel towards the given cluster.
+func tunnelLeaseName(clusterID string) string {
+	return liqoconst.GatewayTunnelLeasePrefix + clusterID
+}
+
+// getTunnelLease returns the tracked status of the lease corresponding to the given TunnelEndpoint, initializing it if necessary.
+// The tunnelLeasesMutex shall be held.
+func (tc *TunnelController) getTunnelLease(tep *netv1alpha1.TunnelEndpoint) *tunnelLease {
+	tl, found := tc.tunnelLeases[tep.Spec.ClusterIdentity.ClusterID]
+	if !found {
+		tl = &tunnelLease{tep: client.ObjectKeyFromObject(tep)}
+		tc.tunnelLeases[tep.Spec.ClusterIdentity.ClusterID] = tl
+	}
+	return tl
+}
+
+// acquireTunnelLease acquires (or renews) the lease designating the current replica as the one in charge of the tunnel towards the
+// remote cluster described by the given TunnelEndpoint, and returns whether it is held (hot-standby mode). All the replicas share
+// the same keys, hence at most one of them shall be connected to a given remote cluster at a time. In case of errors, a lease
+// previously acquired is considered still held for renewDeadline after the last renewal, which is shorter than leaseDuration
+// to ensure the tunnel is torn down before any other replica can take over.
+func (tc *TunnelController) acquireTunnelLease(ctx context.Context, tep *netv1alpha1.TunnelEndpoint) (bool, error) {
+	tc.tunnelLeasesMutex.Lock()
+	defer tc.tunnelLeasesMutex.Unlock()
+
+	now := time.Now()
+	tl := tc.getTunnelLease(tep)
+	held := !tl.renewedTime.IsZero() && now.Sub(tl.renewedTime) < tc.renewDeadline
+	if !held {
+		tl.renewedTime = time.Time{}
+	}
+
+	leases := tc.k8sClient.CoordinationV1().Leases(tc.namespace)
+	name := tunnelLeaseName(tep.Spec.ClusterIdentity.ClusterID)
+	lease, err := leases.Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case k8sApiErrors.IsNotFound(err):
+		lease = &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: tc.namespace}}
+	case err != nil:
+		return held, fmt.Errorf("failed to retrieve lease %s: %w", name, err)
+	}
+	tl.observe(lease, tc.podName, now)
+
+	designated, err := getDesignatedReplica(ctx, tc.Client, tc.namespace)
+	if err != nil {
+		return held, fmt.Errorf("failed to retrieve the designated gateway replica: %w", err)
+	}
+	if !tl.canAcquire(tc.podName, designated == tc.podName, tc.leaseDuration, now) {
+		tl.renewedTime = time.Time{}
+		return false, nil
+	}
+
+	if pointer.StringDeref(lease.Spec.HolderIdentity, "") != tc.podName {
+		lease.Spec.AcquireTime = &metav1.MicroTime{Time: now}
+		lease.Spec.LeaseTransitions = pointer.Int32(pointer.Int32Deref(lease.Spec.LeaseTransitions, 0) + 1)
+		klog.Infof("%s -> gateway replica %s acquiring the tunnel lease", tep.Spec.ClusterIdentity, tc.podName)
+	}
+	lease.Spec.HolderIdentity = pointer.String(tc.podName)
+	lease.Spec.LeaseDurationSeconds = pointer.Int32(int32(tc.leaseDuration.Seconds()))
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: now}
+
+	if lease.ResourceVersion == "" {
+		lease, err = leases.Create(ctx, lease, metav1.CreateOptions{})
+	} else {
+		lease, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+	}
+	if k8sApiErrors.IsConflict(err) || k8sApiErrors.IsAlreadyExists(err) {
+		// The lease has been concurrently acquired by another replica.
+		klog.V(4).Infof("%s -> failed to acquire lease %s: %v", tep.Spec.ClusterIdentity, name, err)
+		tl.renewedTime = time.Time{}
+		return false, nil
+	}
+	if err != nil {
+		return held, fmt.Errorf("failed to acquire lease %s: %w", name, err)
+	}
+
+	tl.observe(lease, tc.podName, now)
+	tl.renewedTime = now
+	return true, nil
+}
+
+// releaseTunnelLease releases the lease corresponding to the given TunnelEndpoint, if held by the current replica (hot-standby mode),
+// so that another replica can immediately take over. If failover is true, the current replica refrains from acquiring it again
+// until the other replicas had a chance to do it.
+func (tc *TunnelController) releaseTunnelLease(ctx context.Context, tep *netv1alpha1.TunnelEndpoint, failover bool) error {
+	tc.tunnelLeasesMutex.Lock()
+	defer tc.tunnelLeasesMutex.Unlock()
+
+	tl := tc.getTunnelLease(tep)
+	leases := tc.k8sClient.CoordinationV1().Leases(tc.namespace)
+	name := tunnelLeaseName(tep.Spec.ClusterIdentity.ClusterID)
+	lease, err := leases.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if pointer.StringDeref(lease.Spec.HolderIdentity, "") != tc.podName {
+		return nil
+	}
+
+	now := time.Now()
+	lease.Spec.HolderIdentity = nil
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: now}
+	if lease, err = leases.Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to release lease %s: %w", name, err)
+	}
+
+	klog.Infof("%s -> gateway replica %s released the tunnel lease", tep.Spec.ClusterIdentity, tc.podName)
+	tl.observe(lease, tc.podName, now)
+	tl.renewedTime = time.Time{}
+	tl.failedOver = failover
+	return nil
+}
+
+// deleteTunnelLease deletes the lease corresponding to the given TunnelEndpoint, which is being deleted (hot-standby mode).
+func (tc *TunnelController) deleteTunnelLease(ctx context.Context, tep *netv1alpha1.TunnelEndpoint) error {
+	tc.tunnelLeasesMutex.Lock()
+	defer tc.tunnelLeasesMutex.Unlock()
+
+	name := tunnelLeaseName(tep.Spec.ClusterIdentity.ClusterID)
+	err := tc.k8sClient.CoordinationV1().Leases(tc.namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !k8sApiErrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete lease %s: %w", name, err)
+	}
+	delete(tc.tunnelLeases, tep.Spec.ClusterIdentity.ClusterID)
+	return nil
+}
+
+// shouldFailover returns whether the current replica shall release the lease of the tunnel towards the given remote cluster,
+// as it reports the cluster as disconnected for longer than leaseDuration, and other replicas are available to take over.
+// The failover is performed per remote cluster, hence the tunnels towards the other clusters are not affected. It is supported
+// by the WireGuard driver only, since the replicas not selected by the gateway service leverage the WireGuard roaming to receive
+// the traffic of the remote clusters, through the endpoint the tunnel is initiated from.
+func (tc *TunnelController) shouldFailover(ctx context.Context, tep *netv1alpha1.TunnelEndpoint) (bool, error) {
+	if tc.driverName != liqoconst.DriverName {
+		return false, nil
+	}
+
+	tc.tunnelLeasesMutex.Lock()
+	disconnectedTime := tc.getTunnelLease(tep).disconnectedTime
+	tc.tunnelLeasesMutex.Unlock()
+	if disconnectedTime.IsZero() || time.Since(disconnectedTime) < tc.leaseDuration {
+		return false, nil
+	}
+
+	var pods corev1.PodList
+	if err := tc.List(ctx, &pods, client.InNamespace(tc.namespace),
+		client.MatchingLabelsSelector{Selector: liqolabels.GatewayLabelSelector()}); err != nil {
+		return false, fmt.Errorf("failed retrieving liqo gateway pods: %w", err)
+	}
+	for i := range pods.Items {
+		if pods.Items[i].Name != tc.podName && pods.Items[i].DeletionTimestamp.IsZero() && pods.Items[i].Status.PodIP != "" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// setTunnelDisconnected records whether the current replica reports the remote cluster described by the given TunnelEndpoint
+// as disconnected, according to the connection checker (hot-standby mode).
+func (tc *TunnelController) setTunnelDisconnected(tep *netv1alpha1.TunnelEndpoint, disconnected bool) {
+	tc.tunnelLeasesMutex.Lock()
+	defer tc.tunnelLeasesMutex.Unlock()
+
+	tl := tc.getTunnelLease(tep)
+	switch {
+	case !disconnected:
+		tl.disconnectedTime = time.Time{}
+	case tl.disconnectedTime.IsZero():
+		tl.disconnectedTime = time.Now()
+	}
+}
+
+// runTunnelLeasesWatchdog periodically enqueues the TunnelEndpoints whose lease has not been renewed by the current replica within
+// renewDeadline (e.g., since the API server is unreachable), until the given context is canceled (hot-standby mode). This way, the
+// corresponding tunnels are torn down before any other replica can take over, regardless of the backoff of failed reconciliations.
+func (tc *TunnelController) runTunnelLeasesWatchdog(ctx context.Context) {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		tc.tunnelLeasesMutex.Lock()
+		expired := make([]types.NamespacedName, 0)
+		for _, tl := range tc.tunnelLeases {
+			if !tl.renewedTime.IsZero() && time.Since(tl.renewedTime) >= tc.renewDeadline {
+				expired = append(expired, tl.tep)
+			}
+		}
+		tc.tunnelLeasesMutex.Unlock()
+
+		for _, key := range expired {
+			klog.Warningf("the tunnel lease of tunnel endpoint %q has not been renewed within the deadline", key)
+			tep := &netv1alpha1.TunnelEndpoint{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}}
+			select {
+			case tc.tunnelLeasesExpirations <- event.GenericEvent{Object: tep}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}, tc.retryPeriod)
+}
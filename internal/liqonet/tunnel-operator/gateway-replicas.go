@@ -0,0 +1,374 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tunneloperator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqonet/conncheck"
+	liqolabels "github.com/liqotech/liqo/pkg/utils/labels"
+)
+
+// replicaStandbyMessage is the status message of the gateway replicas in standby, in hot-standby mode.
+const replicaStandbyMessage = "The gateway replica is in standby"
+
+// updateReplicaStatus updates the status of the current gateway replica in the given TunnelEndpoint (hot-standby mode),
+// removing also the status of the replicas which no longer exist.
+func (tc *TunnelController) updateReplicaStatus(ctx context.Context, con *netv1alpha1.Connection, tep *netv1alpha1.TunnelEndpoint) error {
+	original := tep.Status.DeepCopy()
+	if err := tc.pruneReplicasStatus(ctx, tep); err != nil {
+		klog.Errorf("%s -> an error occurred while removing the status of leftover gateway replicas: %v", tep.Spec.ClusterIdentity, err)
+		return err
+	}
+
+	setReplicaStatus(tep, tc.forgeReplicaStatus(con))
+	if reflect.DeepEqual(original, &tep.Status) {
+		return nil
+	}
+
+	if err := tc.Status().Update(ctx, tep); err != nil {
+		if k8sApiErrors.IsConflict(err) {
+			klog.V(4).Infof("%s -> unable to update status for resource %s: %v", tep.Spec.ClusterIdentity, tep.Name, err)
+			return err
+		}
+		klog.Errorf("%s -> an error occurred while updating status for resource %s: %v", tep.Spec.ClusterIdentity, tep.Name, err)
+		return err
+	}
+	return nil
+}
+
+// updateReplicaConnectionStatus updates the connection status of the current gateway replica (hot-standby mode),
+// according to the outcome of the connection checker.
+func (tc *TunnelController) updateReplicaConnectionStatus(ctx context.Context, tep *netv1alpha1.TunnelEndpoint,
+	connected bool, stats *conncheck.ConnectionStats, timestamp time.Time) error {
+	var conn netv1alpha1.Connection
+	if current := getReplicaStatus(tep, tc.podName); current != nil {
+		conn = current.Connection
+	}
+
+	tc.setTunnelDisconnected(tep, !connected)
+	previous := conn
+	if connected {
+		conn.Status = netv1alpha1.Connected
+		conn.StatusMessage = netv1alpha1.ConnectedMessage
+	} else {
+		conn.Status = netv1alpha1.ConnectionError
+		conn.StatusMessage = netv1alpha1.ConnectionErrorMessage
+	}
+	statusChanged := previous.Status != conn.Status || previous.StatusMessage != conn.StatusMessage
+	if !statusChanged && previous.PathMTU == stats.PathMTU && timestamp.Sub(previous.Latency.Timestamp.Time) <= tc.updateStatusInterval {
+		return nil
+	}
+	if statusChanged {
+		klog.Infof("%s -> changing status of gateway replica %s to %s %q",
+			tep.Spec.ClusterIdentity, tc.podName, conn.Status, conn.StatusMessage)
+	}
+
+	setConnectionStats(&conn, stats, timestamp)
+	setReplicaStatus(tep, tc.forgeReplicaStatus(&conn))
+	if err := tc.Client.Status().Update(ctx, tep); err != nil {
+		return fmt.Errorf("unable to update resource %s: %w", client.ObjectKeyFromObject(tep), err)
+	}
+	return nil
+}
+
+// pruneReplicasStatus removes from the given TunnelEndpoint the status of the gateway replicas which are no longer running.
+func (tc *TunnelController) pruneReplicasStatus(ctx context.Context, tep *netv1alpha1.TunnelEndpoint) error {
+	var pods corev1.PodList
+	if err := tc.List(ctx, &pods, client.InNamespace(tc.namespace),
+		client.MatchingLabelsSelector{Selector: liqolabels.GatewayLabelSelector()}); err != nil {
+		return fmt.Errorf("failed retrieving liqo gateway pods: %w", err)
+	}
+
+	running := make(map[string]struct{})
+	for i := range pods.Items {
+		if pods.Items[i].DeletionTimestamp.IsZero() {
+			running[pods.Items[i].Name] = struct{}{}
+		}
+	}
+
+	replicas := make([]netv1alpha1.GatewayReplicaStatus, 0, len(tep.Status.Replicas))
+	for i := range tep.Status.Replicas {
+		if _, ok := running[tep.Status.Replicas[i].Name]; ok {
+			replicas = append(replicas, tep.Status.Replicas[i])
+		} else {
+			klog.V(4).Infof("detected leftover gateway replica %q to be removed from tunnel endpoint %q", tep.Status.Replicas[i].Name, klog.KObj(tep))
+		}
+	}
+	tep.Status.Replicas = replicas
+	aggregateReplicasStatus(tep)
+	return nil
+}
+
+// releaseTunnelEndpoints removes the finalizer and the status of the current gateway replica from all the tunnel endpoints,
+// and releases the corresponding leases, so that the other replicas can immediately take over.
+// It is called at exit time in hot-standby mode, and errors are only logged, since we can not recover from them.
+func (tc *TunnelController) releaseTunnelEndpoints(ctx context.Context) {
+	var teps netv1alpha1.TunnelEndpointList
+	if err := tc.List(ctx, &teps); err != nil {
+		klog.Errorf("an error occurred while listing tunnel endpoints: %v", err)
+		return
+	}
+
+	for i := range teps.Items {
+		tep := &teps.Items[i]
+		if err := tc.releaseTunnelLease(ctx, tep, false); err != nil {
+			klog.Errorf("%s -> unable to release the tunnel lease: %v", tep.Spec.ClusterIdentity, err)
+		}
+		original := tep.DeepCopy()
+		if controllerutil.RemoveFinalizer(tep, tc.finalizer) {
+			// Using patch instead of update, to prevent issues in case of conflicts.
+			if err := tc.Patch(ctx, tep, client.MergeFrom(original)); err != nil {
+				klog.Errorf("%s -> unable to remove finalizer from tunnel endpoint %q: %v", tep.Spec.ClusterIdentity, klog.KObj(tep), err)
+			}
+		}
+
+		if getReplicaStatus(tep, tc.podName) == nil {
+			continue
+		}
+		replicas := make([]netv1alpha1.GatewayReplicaStatus, 0, len(tep.Status.Replicas))
+		for j := range tep.Status.Replicas {
+			if tep.Status.Replicas[j].Name != tc.podName {
+				replicas = append(replicas, tep.Status.Replicas[j])
+			}
+		}
+		tep.Status.Replicas = replicas
+		aggregateReplicasStatus(tep)
+		if err := tc.Status().Update(ctx, tep); err != nil {
+			klog.Errorf("%s -> unable to remove the status of gateway replica %s from tunnel endpoint %q: %v",
+				tep.Spec.ClusterIdentity, tc.podName, klog.KObj(tep), err)
+		}
+	}
+}
+
+// standbyReplica closes the tunnel established by the current gateway replica towards the given remote cluster, if any,
+// since it does not hold the corresponding lease (hot-standby mode).
+func (tc *TunnelController) standbyReplica(tep *netv1alpha1.TunnelEndpoint) error {
+	tc.readyClustersMutex.Lock()
+	delete(tc.readyClusters, tep.Spec.ClusterIdentity.ClusterID)
+	tc.readyClustersMutex.Unlock()
+
+	if current := getReplicaStatus(tep, tc.podName); current == nil || current.Connection.StatusMessage == replicaStandbyMessage {
+		return nil
+	}
+	klog.Infof("%s -> gateway replica %s moving to standby, as it does not hold the tunnel lease", tep.Spec.ClusterIdentity, tc.podName)
+	if err := tc.disconnectFromPeer(tc.replicaTunnelEndpoint(tep)); err != nil {
+		return err
+	}
+	if _, err := tc.RemoveRoutesPerCluster(tep); err != nil {
+		klog.Errorf("%s -> unable to remove the routes towards the remote cluster: %v", tep.Spec.ClusterIdentity, err)
+		tc.Eventf(tep, "Warning", "Processing", "unable to remove route: %s", err.Error())
+		return err
+	}
+	return nil
+}
+
+// getDesignatedReplica returns the name of the gateway replica selected by the gateway service (hot-standby mode).
+func getDesignatedReplica(ctx context.Context, cl client.Client, namespace string) (string, error) {
+	var pods corev1.PodList
+	if err := cl.List(ctx, &pods, client.InNamespace(namespace),
+		client.MatchingLabelsSelector{Selector: liqolabels.GatewayLabelSelector()}); err != nil {
+		return "", fmt.Errorf("failed retrieving liqo gateway pods: %w", err)
+	}
+	var teps netv1alpha1.TunnelEndpointList
+	if err := cl.List(ctx, &teps); err != nil {
+		return "", fmt.Errorf("failed retrieving tunnel endpoints: %w", err)
+	}
+
+	replicas := make([]string, 0, len(pods.Items))
+	for i := range pods.Items {
+		if pods.Items[i].DeletionTimestamp.IsZero() && pods.Items[i].Status.PodIP != "" {
+			replicas = append(replicas, pods.Items[i].Name)
+		}
+	}
+	return designatedReplica(replicas, teps.Items), nil
+}
+
+// designatedReplica returns the gateway replica selected by the gateway service, among the given running ones, which is
+// preferred when acquiring the tunnel leases, so that the traffic initiated by the remote clusters reaches the replica
+// configured with the corresponding peers. The replicas are ranked according to their status in the TunnelEndpoints,
+// preferring (in order) the one which is connected to at least one remote cluster, the one which is establishing the tunnels,
+// those in standby (or just started) and, finally, those which report a connection error towards all the remote clusters.
+// Ties are broken in alphabetical order, so that all the replicas converge to the same choice.
+func designatedReplica(replicas []string, teps []netv1alpha1.TunnelEndpoint) string {
+	const (
+		rankConnected = iota
+		rankConnecting
+		rankStandby
+		rankFailed
+	)
+
+	ranks := make(map[string]int, len(replicas))
+	failed := make(map[string]bool, len(replicas))
+	for i := range teps {
+		for j := range teps[i].Status.Replicas {
+			name, conn := teps[i].Status.Replicas[j].Name, &teps[i].Status.Replicas[j].Connection
+			if _, ok := failed[name]; !ok {
+				failed[name] = true
+				ranks[name] = rankStandby
+			}
+			switch {
+			case conn.Status == netv1alpha1.ConnectionError:
+				continue
+			case conn.Status == netv1alpha1.Connected:
+				ranks[name] = rankConnected
+			case conn.StatusMessage != replicaStandbyMessage && ranks[name] != rankConnected:
+				ranks[name] = rankConnecting
+			}
+			failed[name] = false
+		}
+	}
+
+	var designated string
+	designatedRank := rankFailed + 1
+	sort.Strings(replicas)
+	for _, replica := range replicas {
+		rank, ok := ranks[replica]
+		switch {
+		case !ok:
+			rank = rankStandby
+		case failed[replica]:
+			rank = rankFailed
+		}
+		if rank < designatedRank {
+			designated, designatedRank = replica, rank
+		}
+	}
+	return designated
+}
+
+// replicasConnectionChangedPredicate returns a predicate matching the changes of the TunnelEndpoints which might affect
+// the designated replica, that is the updates of the connection status of any replica, as well as deletions.
+func replicasConnectionChangedPredicate() predicate.Predicate {
+	connections := func(o client.Object) map[string]string {
+		tep, ok := o.(*netv1alpha1.TunnelEndpoint)
+		if !ok {
+			return nil
+		}
+		output := make(map[string]string, len(tep.Status.Replicas))
+		for i := range tep.Status.Replicas {
+			output[tep.Status.Replicas[i].Name] = string(tep.Status.Replicas[i].Connection.Status) + tep.Status.Replicas[i].Connection.StatusMessage
+		}
+		return output
+	}
+
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return !reflect.DeepEqual(connections(e.ObjectOld), connections(e.ObjectNew))
+		},
+	}
+}
+
+// enqueueAllTunnelEndpoints returns the reconcile requests for all the existing tunnel endpoints.
+func (tc *TunnelController) enqueueAllTunnelEndpoints(ctx context.Context, _ client.Object) []reconcile.Request {
+	var teps netv1alpha1.TunnelEndpointList
+	if err := tc.List(ctx, &teps); err != nil {
+		klog.Errorf("an error occurred while listing tunnel endpoints: %v", err)
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(teps.Items))
+	for i := range teps.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&teps.Items[i])})
+	}
+	return requests
+}
+
+// replicaTunnelEndpoint returns a copy of the given TunnelEndpoint whose connection status refers to the current gateway replica,
+// so that the tunnel drivers are not affected by the status of the other replicas in hot-standby mode.
+func (tc *TunnelController) replicaTunnelEndpoint(tep *netv1alpha1.TunnelEndpoint) *netv1alpha1.TunnelEndpoint {
+	if !tc.hotStandby {
+		return tep
+	}
+
+	replicaTep := tep.DeepCopy()
+	replicaTep.Status.Connection = netv1alpha1.Connection{}
+	if current := getReplicaStatus(tep, tc.podName); current != nil {
+		replicaTep.Status.Connection = current.Connection
+	}
+	return replicaTep
+}
+
+// forgeReplicaStatus returns the status of the current gateway replica, given the status of its connection.
+func (tc *TunnelController) forgeReplicaStatus(con *netv1alpha1.Connection) *netv1alpha1.GatewayReplicaStatus {
+	return &netv1alpha1.GatewayReplicaStatus{
+		Name:           tc.podName,
+		GatewayIP:      tc.podIP,
+		GatewayIPv6:    tc.podIPv6,
+		VethIFaceIndex: tc.hostVeth.Index,
+		VethIFaceName:  tc.hostVeth.Name,
+		Connection:     *con,
+	}
+}
+
+// getReplicaStatus returns the status of the given gateway replica, or nil if not present.
+func getReplicaStatus(tep *netv1alpha1.TunnelEndpoint, name string) *netv1alpha1.GatewayReplicaStatus {
+	for i := range tep.Status.Replicas {
+		if tep.Status.Replicas[i].Name == name {
+			return &tep.Status.Replicas[i]
+		}
+	}
+	return nil
+}
+
+// setReplicaStatus sets the status of the given gateway replica in the TunnelEndpoint, and updates the top-level fields accordingly.
+func setReplicaStatus(tep *netv1alpha1.TunnelEndpoint, replica *netv1alpha1.GatewayReplicaStatus) {
+	if current := getReplicaStatus(tep, replica.Name); current != nil {
+		*current = *replica
+	} else {
+		tep.Status.Replicas = append(tep.Status.Replicas, *replica)
+	}
+	sort.Slice(tep.Status.Replicas, func(i, j int) bool { return tep.Status.Replicas[i].Name < tep.Status.Replicas[j].Name })
+	aggregateReplicasStatus(tep)
+}
+
+// aggregateReplicasStatus configures the top-level fields of the TunnelEndpoint status to refer to the preferred replica,
+// selected with the same criteria of the designated one, but considering the given TunnelEndpoint only. This ensures
+// that the traffic is routed towards the replica in charge of the tunnel, and that the consumers unaware of the
+// hot-standby mode keep working.
+func aggregateReplicasStatus(tep *netv1alpha1.TunnelEndpoint) {
+	if len(tep.Status.Replicas) == 0 {
+		return
+	}
+
+	replicas := make([]string, 0, len(tep.Status.Replicas))
+	for i := range tep.Status.Replicas {
+		replicas = append(replicas, tep.Status.Replicas[i].Name)
+	}
+	preferred := getReplicaStatus(tep, designatedReplica(replicas, []netv1alpha1.TunnelEndpoint{*tep}))
+	tep.Status.Connection = preferred.Connection
+	tep.Status.GatewayIP = preferred.GatewayIP
+	tep.Status.GatewayIPv6 = preferred.GatewayIPv6
+	tep.Status.VethIFaceIndex = preferred.VethIFaceIndex
+	tep.Status.VethIFaceName = preferred.VethIFaceName
+	tep.Status.VethIP = liqoconst.GatewayVethIPAddr
+}
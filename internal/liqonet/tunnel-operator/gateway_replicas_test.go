@@ -0,0 +1,188 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tunneloperator
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+var _ = Describe("Gateway replicas", func() {
+	replica := func(name, ip string, status netv1alpha1.ConnectionStatus) *netv1alpha1.GatewayReplicaStatus {
+		return &netv1alpha1.GatewayReplicaStatus{
+			Name: name, GatewayIP: ip, VethIFaceIndex: 10, VethIFaceName: "liqo.host",
+			Connection: netv1alpha1.Connection{Status: status},
+		}
+	}
+
+	standby := func(name, ip string) *netv1alpha1.GatewayReplicaStatus {
+		status := replica(name, ip, netv1alpha1.Connecting)
+		status.Connection.StatusMessage = replicaStandbyMessage
+		return status
+	}
+
+	Describe("setting the status of a replica", func() {
+		var tep *netv1alpha1.TunnelEndpoint
+
+		BeforeEach(func() {
+			tep = &netv1alpha1.TunnelEndpoint{}
+		})
+
+		It("should add the replica and select it as preferred", func() {
+			setReplicaStatus(tep, replica("gateway-b", "10.0.0.2", netv1alpha1.Connecting))
+			Expect(tep.Status.Replicas).To(HaveLen(1))
+			Expect(tep.Status.GatewayIP).To(Equal("10.0.0.2"))
+			Expect(tep.Status.Connection.Status).To(Equal(netv1alpha1.Connecting))
+			Expect(tep.Status.VethIP).To(Equal(consts.GatewayVethIPAddr))
+		})
+
+		It("should prefer the first connected replica in alphabetical order", func() {
+			setReplicaStatus(tep, replica("gateway-c", "10.0.0.3", netv1alpha1.Connected))
+			setReplicaStatus(tep, replica("gateway-a", "10.0.0.1", netv1alpha1.ConnectionError))
+			setReplicaStatus(tep, replica("gateway-b", "10.0.0.2", netv1alpha1.Connected))
+			Expect(tep.Status.Replicas).To(HaveLen(3))
+			Expect(tep.Status.Replicas[0].Name).To(Equal("gateway-a"))
+			Expect(tep.Status.GatewayIP).To(Equal("10.0.0.2"))
+			Expect(tep.Status.Connection.Status).To(Equal(netv1alpha1.Connected))
+
+			// The preferred replica disconnects.
+			setReplicaStatus(tep, replica("gateway-b", "10.0.0.2", netv1alpha1.ConnectionError))
+			Expect(tep.Status.Replicas).To(HaveLen(3))
+			Expect(tep.Status.GatewayIP).To(Equal("10.0.0.3"))
+
+			// All the replicas are disconnected.
+			setReplicaStatus(tep, replica("gateway-c", "10.0.0.3", netv1alpha1.ConnectionError))
+			Expect(tep.Status.GatewayIP).To(Equal("10.0.0.1"))
+			Expect(tep.Status.Connection.Status).To(Equal(netv1alpha1.ConnectionError))
+		})
+
+		It("should prefer the replica establishing the tunnel over those in standby", func() {
+			setReplicaStatus(tep, standby("gateway-a", "10.0.0.1"))
+			setReplicaStatus(tep, replica("gateway-b", "10.0.0.2", netv1alpha1.Connecting))
+			setReplicaStatus(tep, standby("gateway-c", "10.0.0.3"))
+			Expect(tep.Status.GatewayIP).To(Equal("10.0.0.2"))
+			Expect(tep.Status.Connection.StatusMessage).NotTo(Equal(replicaStandbyMessage))
+		})
+	})
+
+	DescribeTable("selecting the designated replica",
+		func(replicas []string, statuses [][]*netv1alpha1.GatewayReplicaStatus, expected string) {
+			teps := make([]netv1alpha1.TunnelEndpoint, len(statuses))
+			for i := range statuses {
+				for _, r := range statuses[i] {
+					setReplicaStatus(&teps[i], r)
+				}
+			}
+			Expect(designatedReplica(replicas, teps)).To(Equal(expected))
+		},
+		Entry("no running replicas", nil, nil, ""),
+		Entry("no tunnel endpoints", []string{"gateway-b", "gateway-a"}, nil, "gateway-a"),
+		Entry("the replicas just started", []string{"gateway-b", "gateway-a", "gateway-c"}, [][]*netv1alpha1.GatewayReplicaStatus{
+			{replica("gateway-a", "10.0.0.1", netv1alpha1.ConnectionError), replica("gateway-b", "10.0.0.2", netv1alpha1.ConnectionError)},
+		}, "gateway-c"),
+		Entry("a replica is connected to one of the clusters", []string{"gateway-a", "gateway-b"}, [][]*netv1alpha1.GatewayReplicaStatus{
+			{replica("gateway-a", "10.0.0.1", netv1alpha1.ConnectionError), replica("gateway-b", "10.0.0.2", netv1alpha1.Connected)},
+			{replica("gateway-a", "10.0.0.1", netv1alpha1.Connecting), replica("gateway-b", "10.0.0.2", netv1alpha1.ConnectionError)},
+		}, "gateway-b"),
+		Entry("a replica is establishing the tunnels, while the others are in standby", []string{"gateway-a", "gateway-b"},
+			[][]*netv1alpha1.GatewayReplicaStatus{
+				{standby("gateway-a", "10.0.0.1"), replica("gateway-b", "10.0.0.2", netv1alpha1.Connecting)},
+			}, "gateway-b"),
+		Entry("the designated replica failed to connect to all the clusters", []string{"gateway-a", "gateway-b"},
+			[][]*netv1alpha1.GatewayReplicaStatus{
+				{replica("gateway-a", "10.0.0.1", netv1alpha1.ConnectionError), standby("gateway-b", "10.0.0.2")},
+				{replica("gateway-a", "10.0.0.1", netv1alpha1.ConnectionError), standby("gateway-b", "10.0.0.2")},
+			}, "gateway-b"),
+		Entry("all the replicas failed to connect", []string{"gateway-b", "gateway-a"}, [][]*netv1alpha1.GatewayReplicaStatus{
+			{replica("gateway-a", "10.0.0.1", netv1alpha1.ConnectionError), replica("gateway-b", "10.0.0.2", netv1alpha1.ConnectionError)},
+		}, "gateway-a"),
+		Entry("the connected replica is no longer running", []string{"gateway-b"}, [][]*netv1alpha1.GatewayReplicaStatus{
+			{replica("gateway-a", "10.0.0.1", netv1alpha1.Connected), standby("gateway-b", "10.0.0.2")},
+		}, "gateway-b"),
+	)
+
+	Describe("acquiring a tunnel lease", func() {
+		const leaseDuration = 10 * time.Second
+		var (
+			tl  tunnelLease
+			now time.Time
+		)
+
+		lease := func(holder string, renewTime time.Time) *coordinationv1.Lease {
+			return &coordinationv1.Lease{Spec: coordinationv1.LeaseSpec{
+				HolderIdentity: pointer.String(holder), RenewTime: &metav1.MicroTime{Time: renewTime}}}
+		}
+
+		BeforeEach(func() {
+			tl = tunnelLease{}
+			now = time.Now()
+		})
+
+		When("the lease does not exist", func() {
+			BeforeEach(func() { tl.observe(&coordinationv1.Lease{}, "gateway-a", now) })
+
+			It("should be acquired immediately by the preferred replica only", func() {
+				Expect(tl.canAcquire("gateway-a", true, leaseDuration, now)).To(BeTrue())
+				Expect(tl.canAcquire("gateway-b", false, leaseDuration, now)).To(BeFalse())
+				Expect(tl.canAcquire("gateway-b", false, leaseDuration, now.Add(leaseDuration))).To(BeTrue())
+			})
+		})
+
+		When("the lease is held by another replica", func() {
+			BeforeEach(func() { tl.observe(lease("gateway-b", now.Add(-time.Hour)), "gateway-a", now) })
+
+			It("should be renewed by the holder", func() {
+				Expect(tl.canAcquire("gateway-b", false, leaseDuration, now)).To(BeTrue())
+			})
+
+			It("should not be acquired until expired, regardless of the clock of the holder", func() {
+				Expect(tl.canAcquire("gateway-a", true, leaseDuration, now.Add(leaseDuration/2))).To(BeFalse())
+				Expect(tl.canAcquire("gateway-a", true, leaseDuration, now.Add(leaseDuration))).To(BeTrue())
+				Expect(tl.canAcquire("gateway-c", false, leaseDuration, now.Add(leaseDuration))).To(BeFalse())
+				Expect(tl.canAcquire("gateway-c", false, leaseDuration, now.Add(2*leaseDuration))).To(BeTrue())
+			})
+
+			It("should not be acquired if renewed in the meanwhile", func() {
+				tl.observe(lease("gateway-b", now.Add(-time.Hour).Add(time.Second)), "gateway-a", now.Add(leaseDuration/2))
+				Expect(tl.canAcquire("gateway-a", true, leaseDuration, now.Add(leaseDuration))).To(BeFalse())
+			})
+		})
+
+		When("the current replica failed over", func() {
+			BeforeEach(func() {
+				tl.observe(lease("", now), "gateway-a", now)
+				tl.failedOver = true
+			})
+
+			It("should leave room to the other replicas first", func() {
+				Expect(tl.canAcquire("gateway-a", true, leaseDuration, now.Add(leaseDuration))).To(BeFalse())
+				Expect(tl.canAcquire("gateway-a", true, leaseDuration, now.Add(2*leaseDuration))).To(BeTrue())
+			})
+
+			It("should be reset once another replica acquires the lease", func() {
+				tl.observe(lease("gateway-b", now), "gateway-a", now)
+				Expect(tl.failedOver).To(BeFalse())
+			})
+		})
+	})
+})
@@ -24,14 +24,18 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
 	liqonetutils "github.com/liqotech/liqo/pkg/liqonet/utils"
 	liqolabels "github.com/liqotech/liqo/pkg/utils/labels"
 )
 
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=core,resources=services,verbs=list;watch;update;patch
+// +kubebuilder:rbac:groups=net.liqo.io,resources=tunnelendpoints,verbs=get;list;watch
 
 const (
 	// These labels are the ones set during the deployment of liqo using the helm chart.
@@ -46,13 +50,17 @@ const (
 type LabelerController struct {
 	client.Client
 	PodIP string
+	// HotStandby is true if all the replicas of the gateway are running, with each tunnel established by the one holding the
+	// corresponding lease.
+	HotStandby bool
 }
 
 // NewLabelerController  returns a new controller ready to be setup and started with the controller manager.
-func NewLabelerController(podIP string, cl client.Client) *LabelerController {
+func NewLabelerController(podIP string, cl client.Client, hotStandby bool) *LabelerController {
 	return &LabelerController{
-		Client: cl,
-		PodIP:  podIP,
+		Client:     cl,
+		PodIP:      podIP,
+		HotStandby: hotStandby,
 	}
 }
 
@@ -67,6 +75,9 @@ func (lbc *LabelerController) Reconcile(ctx context.Context, req ctrl.Request) (
 		klog.Errorf("an error occurred while getting pod {%s}: %v", req.NamespacedName, err)
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	if lbc.HotStandby {
+		return ctrl.Result{}, lbc.reconcileHotStandby(ctx, pod)
+	}
 	// If it is our pod/current pod then ensure that the labels values is set to "active".
 	if lbc.PodIP == pod.Status.PodIP {
 		if liqonetutils.AddLabelToObj(pod, gatewayLabelKey, gatewayStatusActive) {
@@ -98,21 +109,61 @@ func (lbc *LabelerController) Reconcile(ctx context.Context, req ctrl.Request) (
 	return ctrl.Result{}, nil
 }
 
-func (lbc *LabelerController) annotateGatewayService(ctx context.Context) error {
+// reconcileHotStandby handles the labeling of the gateway pods in hot-standby mode. Each replica is in charge of its
+// own pod only, which is labeled as active (i.e., selected by the gateway service) if it is the designated replica,
+// which is preferred when acquiring the tunnel leases. This way, the traffic coming from the remote clusters is steered
+// towards the replica configured with the corresponding peers.
+func (lbc *LabelerController) reconcileHotStandby(ctx context.Context, pod *corev1.Pod) error {
+	if lbc.PodIP != pod.Status.PodIP {
+		return nil
+	}
+
+	designated, err := getDesignatedReplica(ctx, lbc.Client, pod.Namespace)
+	if err != nil {
+		klog.Errorf("an error occurred while retrieving the designated gateway replica: %v", err)
+		return err
+	}
+	status := gatewayStatusStandby
+	if designated == pod.Name {
+		status = gatewayStatusActive
+	}
+
+	if liqonetutils.AddLabelToObj(pod, gatewayLabelKey, status) {
+		if err := lbc.Update(ctx, pod); err != nil {
+			klog.Errorf("an error occurred while updating value of label {%s} to {%s} for pod {%s}: %v",
+				gatewayLabelKey, status, klog.KObj(pod), err)
+			return err
+		}
+		klog.Infof("successfully updated label {%s: %s} for pod {%s}", gatewayLabelKey, status, klog.KObj(pod))
+	}
+	if status != gatewayStatusActive {
+		return nil
+	}
+	return lbc.annotateGatewayService(ctx)
+}
+
+func (lbc *LabelerController) getGatewayService(ctx context.Context) (*corev1.Service, error) {
 	const expectedNumOfServices = 1
 	svcList := new(corev1.ServiceList)
 	err := lbc.List(ctx, svcList, client.MatchingLabelsSelector{Selector: liqolabels.GatewayLabelSelector()})
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if len(svcList.Items) != expectedNumOfServices {
 		klog.Errorf("an error occurred while getting gateway service: expected number of services for the gateway is {%d}, "+
 			"instead we found {%d}", expectedNumOfServices, len(svcList.Items))
-		return fmt.Errorf("expected number of services for the gateway is {%d}, instead we found {%d}",
+		return nil, fmt.Errorf("expected number of services for the gateway is {%d}, instead we found {%d}",
 			expectedNumOfServices, len(svcList.Items))
 	}
 	// We come here only if one service has been found.
-	svc := &svcList.Items[0]
+	return &svcList.Items[0], nil
+}
+
+func (lbc *LabelerController) annotateGatewayService(ctx context.Context) error {
+	svc, err := lbc.getGatewayService(ctx)
+	if err != nil {
+		return err
+	}
 	if liqonetutils.AddAnnotationToObj(svc, serviceAnnotationKey, lbc.PodIP) {
 		if err := lbc.Update(ctx, svc); err != nil {
 			klog.Errorf("an error occurred while annotating gateway service {%s/%s}: %v",
@@ -132,7 +183,29 @@ func (lbc *LabelerController) SetupWithManager(mgr ctrl.Manager) error {
 		return selector.Matches(labels.Set(o.GetLabels()))
 	})
 
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&corev1.Pod{}, builder.WithPredicates(filter)).
-		Complete(lbc)
+	ctrlBuilder := ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Pod{}, builder.WithPredicates(filter))
+	if lbc.HotStandby {
+		// In hot-standby mode, the labels depend on the running replicas and on the status of their connections.
+		ctrlBuilder = ctrlBuilder.
+			Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(lbc.enqueueCurrentPod), builder.WithPredicates(filter)).
+			Watches(&netv1alpha1.TunnelEndpoint{}, handler.EnqueueRequestsFromMapFunc(lbc.enqueueCurrentPod),
+				builder.WithPredicates(replicasConnectionChangedPredicate()))
+	}
+	return ctrlBuilder.Complete(lbc)
+}
+
+// enqueueCurrentPod returns the reconcile request for the pod hosting the current replica of the gateway.
+func (lbc *LabelerController) enqueueCurrentPod(ctx context.Context, _ client.Object) []reconcile.Request {
+	var pods corev1.PodList
+	if err := lbc.List(ctx, &pods, client.MatchingLabelsSelector{Selector: liqolabels.GatewayLabelSelector()}); err != nil {
+		klog.Errorf("an error occurred while listing the gateway pods: %v", err)
+		return nil
+	}
+	for i := range pods.Items {
+		if pods.Items[i].Status.PodIP == lbc.PodIP {
+			return []reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(&pods.Items[i])}}
+		}
+	}
+	return nil
 }
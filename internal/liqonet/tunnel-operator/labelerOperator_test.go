@@ -136,7 +136,7 @@ var _ = Describe("LabelerOperator", func() {
 	Describe("testing NewOverlayOperator function", func() {
 		Context("when input parameters are correct", func() {
 			It("should return labeler controller ", func() {
-				lbc1 := NewLabelerController(labelerCurrentPodIP, k8sClient)
+				lbc1 := NewLabelerController(labelerCurrentPodIP, k8sClient, false)
 				Expect(lbc1).ShouldNot(BeNil())
 			})
 		})
@@ -32,6 +32,8 @@ import (
 type NatMappingController struct {
 	client.Client
 	iptables.IPTHandler
+	// ip6tHandler enforces the IPv6 mappings of dual-stack peerings. It is nil if IPv6 is not supported.
+	ip6tHandler        *iptables.IPTHandler
 	readyClustersMutex *sync.Mutex
 	readyClusters      map[string]struct{}
 	gatewayNetns       ns.NetNS
@@ -64,6 +66,13 @@ func (npc *NatMappingController) Reconcile(ctx context.Context, req ctrl.Request
 			return fmt.Errorf("unable to ensure prerouting rules for cluster {%s}: %w",
 				nm.Spec.ClusterID, err)
 		}
+		// IPv6 mappings exist only in case of dual-stack peerings.
+		if nm.Spec.PodCIDRv6 != "" && npc.ip6tHandler != nil {
+			if err := npc.ip6tHandler.EnsurePreroutingRulesPerNatMapping(&nm); err != nil {
+				return fmt.Errorf("unable to ensure IPv6 prerouting rules for cluster {%s}: %w",
+					nm.Spec.ClusterID, err)
+			}
+		}
 		return nil
 	}); err != nil {
 		klog.Error(err)
@@ -86,9 +95,16 @@ func NewNatMappingController(cl client.Client, readyClustersMutex *sync.Mutex,
 	if err != nil {
 		return nil, err
 	}
+	var ip6tablesHandler *iptables.IPTHandler
+	if handler, err := iptables.NewIPv6IPTHandler(); err != nil {
+		klog.Warningf("unable to create ip6tables handler, IPv6 mappings will not be enforced: %v", err)
+	} else {
+		ip6tablesHandler = &handler
+	}
 	return &NatMappingController{
 		Client:             cl,
 		IPTHandler:         iptablesHandler,
+		ip6tHandler:        ip6tablesHandler,
 		readyClustersMutex: readyClustersMutex,
 		readyClusters:      readyClusters,
 		gatewayNetns:       gatewayNetns,
@@ -0,0 +1,197 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tunneloperator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+	"github.com/liqotech/liqo/pkg/liqonet/iptables"
+	liqonetutils "github.com/liqotech/liqo/pkg/liqonet/utils"
+)
+
+// NetworkPolicyController reconciles the TunnelEndpoint objects, enforcing in the gateway
+// the InterClusterNetworkPolicies restricting the traffic towards the corresponding remote clusters.
+type NetworkPolicyController struct {
+	client.Client
+	iptables.IPTHandler
+	// ip6tHandler enforces the policies for the IPv6 networks of dual-stack peerings. It is nil if IPv6 is not supported.
+	ip6tHandler        *iptables.IPTHandler
+	pods               cluster.Cluster
+	readyClustersMutex *sync.Mutex
+	readyClusters      map[string]struct{}
+	gatewayNetns       ns.NetNS
+}
+
+//+kubebuilder:rbac:groups=net.liqo.io,resources=interclusternetworkpolicies,verbs=get;list;watch
+//+kubebuilder:rbac:groups=net.liqo.io,resources=tunnelendpoints,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+
+// Reconcile function handles requests made on TunnelEndpoint resources
+// by guaranteeing the filtering rules enforcing the inter-cluster network policies are updated.
+func (npc *NetworkPolicyController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var tep netv1alpha1.TunnelEndpoint
+	if err := npc.Get(ctx, req.NamespacedName, &tep); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// There's no need of a pre-delete logic since the policy chains for cluster are removed by the
+	// tunnel-operator after the un-peer, along with the other IPTables rules.
+	if !tep.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	targets, err := policyTargets(ctx, npc.Client, npc.pods.GetClient())
+	if err != nil {
+		klog.Error(err)
+		return ctrl.Result{}, err
+	}
+
+	clusterID := tep.Spec.ClusterIdentity.ClusterID
+	if err := npc.gatewayNetns.Do(func(netNamespace ns.NetNS) error {
+		// Is the remote cluster tunnel ready? If not, do nothing
+		npc.readyClustersMutex.Lock()
+		defer npc.readyClustersMutex.Unlock()
+		if _, ready := npc.readyClusters[clusterID]; !ready {
+			return fmt.Errorf("tunnel for cluster {%s} is not ready", clusterID)
+		}
+		if err := npc.IPTHandler.EnsurePolicyRulesPerCluster(&tep, targets); err != nil {
+			return fmt.Errorf("unable to ensure network policy rules for cluster {%s}: %w", clusterID, err)
+		}
+		// IPv6 rules exist only in case of dual-stack peerings.
+		if tepv6, ok := liqonetutils.GetIPv6TunnelEndpoint(&tep); ok && npc.ip6tHandler != nil {
+			if err := npc.ip6tHandler.EnsurePolicyRulesPerCluster(tepv6, targets); err != nil {
+				return fmt.Errorf("unable to ensure IPv6 network policy rules for cluster {%s}: %w", clusterID, err)
+			}
+		}
+		return nil
+	}); err != nil {
+		klog.Error(err)
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// policyTargets retrieves the InterClusterNetworkPolicies, along with the IPs of the pods they select.
+func policyTargets(ctx context.Context, cl, podsClient client.Client) ([]iptables.PolicyTarget, error) {
+	var policies netv1alpha1.InterClusterNetworkPolicyList
+	if err := cl.List(ctx, &policies); err != nil {
+		return nil, fmt.Errorf("unable to list inter-cluster network policies: %w", err)
+	}
+
+	targets := make([]iptables.PolicyTarget, 0, len(policies.Items))
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.PodSelector)
+		if err != nil {
+			klog.Warningf("Skipping inter-cluster network policy %q: invalid pod selector: %v", klog.KObj(policy), err)
+			continue
+		}
+
+		var pods corev1.PodList
+		if err := podsClient.List(ctx, &pods, client.InNamespace(policy.Namespace),
+			client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, fmt.Errorf("unable to list the pods selected by policy %q: %w", klog.KObj(policy), err)
+		}
+
+		target := iptables.PolicyTarget{Policy: policy}
+		for j := range pods.Items {
+			pod := &pods.Items[j]
+			// Host network pods share the IPs of the node, hence they cannot be isolated.
+			if pod.Spec.HostNetwork || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+				continue
+			}
+			for _, podIP := range pod.Status.PodIPs {
+				target.PodIPs = append(target.PodIPs, podIP.IP)
+			}
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (npc *NetworkPolicyController) SetupWithManager(mgr ctrl.Manager) error {
+	// Any change concerning policies or pods may affect the rules towards all remote clusters.
+	enqueueAll := handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, _ client.Object) []reconcile.Request {
+		var teps netv1alpha1.TunnelEndpointList
+		if err := npc.List(ctx, &teps); err != nil {
+			klog.Errorf("Failed to list tunnel endpoints: %v", err)
+			return nil
+		}
+		requests := make([]reconcile.Request, 0, len(teps.Items))
+		for i := range teps.Items {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&teps.Items[i])})
+		}
+		return requests
+	})
+
+	// Pod updates are relevant only in case they affect the policy selectors or the resulting IPs.
+	podChanged := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldPod, newPod := e.ObjectOld.(*corev1.Pod), e.ObjectNew.(*corev1.Pod)
+			return !reflect.DeepEqual(oldPod.Labels, newPod.Labels) || !reflect.DeepEqual(oldPod.Status.PodIPs, newPod.Status.PodIPs) ||
+				oldPod.Status.Phase != newPod.Status.Phase
+		},
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("networkpolicy").
+		For(&netv1alpha1.TunnelEndpoint{}).
+		Watches(&netv1alpha1.InterClusterNetworkPolicy{}, enqueueAll).
+		WatchesRawSource(source.Kind(npc.pods.GetCache(), &corev1.Pod{}), enqueueAll, builder.WithPredicates(podChanged)).
+		Complete(npc)
+}
+
+// NewNetworkPolicyController returns a network policy controller istance.
+// Pods are retrieved through the given cluster, which is expected to cache the pods in all namespaces.
+func NewNetworkPolicyController(cl client.Client, pods cluster.Cluster, readyClustersMutex *sync.Mutex,
+	readyClusters map[string]struct{}, gatewayNetns ns.NetNS) (*NetworkPolicyController, error) {
+	iptablesHandler, err := iptables.NewIPTHandler()
+	if err != nil {
+		return nil, err
+	}
+	var ip6tablesHandler *iptables.IPTHandler
+	if handler, err := iptables.NewIPv6IPTHandler(); err != nil {
+		klog.Warningf("unable to create ip6tables handler, IPv6 network policies will not be enforced: %v", err)
+	} else {
+		ip6tablesHandler = &handler
+	}
+	return &NetworkPolicyController{
+		Client:             cl,
+		IPTHandler:         iptablesHandler,
+		ip6tHandler:        ip6tablesHandler,
+		pods:               pods,
+		readyClustersMutex: readyClustersMutex,
+		readyClusters:      readyClusters,
+		gatewayNetns:       gatewayNetns,
+	}, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tunneloperator
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+)
+
+var _ = Describe("NetworkPolicyOperator", func() {
+	pod := func(name, namespace, app, ip string, mutators ...func(*corev1.Pod)) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app": app}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIPs: []corev1.PodIP{{IP: ip}}},
+		}
+		for _, mutator := range mutators {
+			mutator(p)
+		}
+		return p
+	}
+
+	policy := func(name string, selector metav1.LabelSelector) *netv1alpha1.InterClusterNetworkPolicy {
+		return &netv1alpha1.InterClusterNetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "foo"},
+			Spec:       netv1alpha1.InterClusterNetworkPolicySpec{PodSelector: selector},
+		}
+	}
+
+	It("should resolve the IPs of the pods selected by the policies", func() {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(corev1.AddToScheme(scheme))
+		utilruntime.Must(netv1alpha1.AddToScheme(scheme))
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			policy("frontend", metav1.LabelSelector{MatchLabels: map[string]string{"app": "frontend"}}),
+			policy("all", metav1.LabelSelector{}),
+			pod("frontend", "foo", "frontend", "10.0.0.1"),
+			pod("backend", "foo", "backend", "10.0.0.2"),
+			pod("completed", "foo", "frontend", "10.0.0.3", func(p *corev1.Pod) { p.Status.Phase = corev1.PodSucceeded }),
+			pod("host", "foo", "frontend", "172.16.0.1", func(p *corev1.Pod) { p.Spec.HostNetwork = true }),
+			pod("other", "bar", "frontend", "10.0.0.4"),
+		).Build()
+
+		targets, err := policyTargets(ctx, cl, cl)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(targets).To(HaveLen(2))
+
+		ips := map[string][]string{}
+		for _, target := range targets {
+			ips[target.Policy.Name] = target.PodIPs
+		}
+		Expect(ips).To(HaveKeyWithValue("frontend", ConsistOf("10.0.0.1")))
+		Expect(ips).To(HaveKeyWithValue("all", ConsistOf("10.0.0.1", "10.0.0.2")))
+	})
+})
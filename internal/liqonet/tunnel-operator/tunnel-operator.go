@@ -24,20 +24,26 @@ import (
 	"time"
 
 	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/vishvananda/netlink"
 	"golang.org/x/sys/unix"
 	corev1 "k8s.io/api/core/v1"
 	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	k8s "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
 	liqoconst "github.com/liqotech/liqo/pkg/consts"
@@ -46,6 +52,8 @@ import (
 	liqonetns "github.com/liqotech/liqo/pkg/liqonet/netns"
 	liqorouting "github.com/liqotech/liqo/pkg/liqonet/routing"
 	"github.com/liqotech/liqo/pkg/liqonet/tunnel"
+	tunnelipsec "github.com/liqotech/liqo/pkg/liqonet/tunnel/ipsec"
+	tunnelmetrics "github.com/liqotech/liqo/pkg/liqonet/tunnel/metrics"
 	tunnelwg "github.com/liqotech/liqo/pkg/liqonet/tunnel/wireguard"
 	liqonetutils "github.com/liqotech/liqo/pkg/liqonet/utils"
 	liqolabels "github.com/liqotech/liqo/pkg/utils/labels"
@@ -58,10 +66,15 @@ type TunnelController struct {
 	tunnel.Driver
 	liqorouting.Routing
 	iptables.IPTHandler
+	// ip6tHandler manages the ip6tables rules for dual-stack peerings. It is nil if IPv6 is not supported.
+	ip6tHandler          *iptables.IPTHandler
 	k8sClient            k8s.Interface
 	drivers              map[string]tunnel.Driver
+	driverName           string
 	namespace            string
+	podName              string
 	podIP                string
+	podIPv6              string
 	finalizer            string
 	hostNetns            ns.NetNS
 	gatewayNetns         ns.NetNS
@@ -70,6 +83,18 @@ type TunnelController struct {
 	readyClustersMutex   *sync.Mutex
 	readyClusters        map[string]struct{}
 	updateStatusInterval time.Duration
+	// hotStandby is true if all the replicas of the gateway are running, with the tunnel towards each remote cluster
+	// established by the replica holding the corresponding lease.
+	hotStandby bool
+	// leaseDuration, renewDeadline and retryPeriod configure the tunnel leases, in hot-standby mode.
+	leaseDuration time.Duration
+	renewDeadline time.Duration
+	retryPeriod   time.Duration
+	// tunnelLeases key is a clusterID, and the values track the lease of the tunnel towards that cluster.
+	tunnelLeases      map[string]*tunnelLease
+	tunnelLeasesMutex sync.Mutex
+	// tunnelLeasesExpirations triggers the reconciliation of the TunnelEndpoints whose lease has not been renewed in time.
+	tunnelLeasesExpirations chan event.GenericEvent
 }
 
 // cluster-role
@@ -77,28 +102,43 @@ type TunnelController struct {
 // +kubebuilder:rbac:groups=net.liqo.io,resources=tunnelendpoints/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
 // role
-// +kubebuilder:rbac:groups=coordination.k8s.io,namespace="do-not-care",resources=leases,verbs=get;create;update
+// +kubebuilder:rbac:groups=coordination.k8s.io,namespace="do-not-care",resources=leases,verbs=get;create;update;delete
 // +kubebuilder:rbac:groups=core,namespace="do-not-care",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
 
 // NewTunnelController instantiates and initializes the tunnel controller.
 func NewTunnelController(ctx context.Context, wg *sync.WaitGroup,
-	podIP, namespace string, er record.EventRecorder, k8sClient k8s.Interface, cl client.Client,
+	podName, podIP, podIPv6, namespace string, er record.EventRecorder, k8sClient k8s.Interface, cl client.Client,
 	readyClustersMutex *sync.Mutex, readyClusters map[string]struct{}, gatewayNetns, hostNetns ns.NetNS, mtu, port int,
-	updateStatusInterval time.Duration) (*TunnelController, error) {
+	driverName string, updateStatusInterval time.Duration, hotStandby bool,
+	leaseDuration, renewDeadline, retryPeriod time.Duration) (*TunnelController, error) {
 	tunnelEndpointFinalizer := liqoconst.LiqoGatewayOperatorName + "." + liqoconst.FinalizersSuffix
+	if hotStandby {
+		// Each replica is in charge of tearing down its own tunnels, hence it registers a dedicated finalizer.
+		tunnelEndpointFinalizer = liqoconst.LiqoGatewayReplicaFinalizer(podIP)
+	}
 	tc := &TunnelController{
 		Client:               cl,
 		EventRecorder:        er,
 		k8sClient:            k8sClient,
+		podName:              podName,
 		podIP:                podIP,
+		podIPv6:              podIPv6,
 		namespace:            namespace,
 		finalizer:            tunnelEndpointFinalizer,
 		readyClustersMutex:   readyClustersMutex,
 		readyClusters:        readyClusters,
 		gatewayNetns:         gatewayNetns,
 		hostNetns:            hostNetns,
+		driverName:           driverName,
 		updateStatusInterval: updateStatusInterval,
+		hotStandby:           hotStandby,
+		leaseDuration:        leaseDuration,
+		renewDeadline:        renewDeadline,
+		retryPeriod:          retryPeriod,
+		tunnelLeases:         make(map[string]*tunnelLease),
+		// The channel is buffered, not to block the watchdog while a reconciliation is in progress.
+		tunnelLeasesExpirations: make(chan event.GenericEvent, 10),
 	}
 
 	err := tc.SetUpTunnelDrivers(tunnel.Config{
@@ -115,13 +155,14 @@ func NewTunnelController(ctx context.Context, wg *sync.WaitGroup,
 	if err = tc.setUpGWNetns(ctx, wg, liqoconst.HostVethName, liqoconst.GatewayVethName, mtu); err != nil {
 		return nil, fmt.Errorf("failed to setup gateway netns: %w", err)
 	}
-	// Move wireguard interface in the gateway network namespace.
+	// Move tunnel interface in the gateway network namespace.
 	if err = netlink.LinkSetNsFd(link, int(tc.gatewayNetns.Fd())); err != nil {
-		return nil, fmt.Errorf("failed to move wireguard iface from host netns to gateway netns: %w", err)
+		return nil, fmt.Errorf("failed to move tunnel iface from host netns to gateway netns: %w", err)
 	}
-	// After the wireguard device has been moved to the new netns we need to:
+	// After the tunnel device has been moved to the new netns we need to:
 	// 1) set it up;
-	// 2) replace the wgctl.Client with a new client spawned in the new netns.
+	// 2) replace the wgctl.Client with a new client spawned in the new netns (wireguard only);
+	// 3) configure the connection checker.
 	var configureWg = func(netnsNamespace ns.NetNS) error {
 		link, err = netlink.LinkByName(liqoconst.DeviceName)
 		if err != nil {
@@ -129,25 +170,31 @@ func NewTunnelController(ctx context.Context, wg *sync.WaitGroup,
 		}
 		err = netlink.LinkSetUp(link)
 		if err != nil {
-			return fmt.Errorf("failed to set wireguard iface up in gateway netns: %w", err)
-		}
-		w := tc.drivers[liqoconst.DriverName]
-		wg := w.(*tunnelwg.Wireguard)
-		if err := wg.SetNewClient(); err != nil {
-			return fmt.Errorf("an error occurred while setting new client in tunnel driver")
+			return fmt.Errorf("failed to set tunnel iface up in gateway netns: %w", err)
 		}
 		err = EnforceIP(link, liqoconst.WgTunnelIP)
 		if err != nil {
 			return fmt.Errorf("unable to enforce tunnel IP: %w", err)
 		}
 
-		wg.Connchecker, err = conncheck.NewConnChecker()
+		connchecker, err := conncheck.NewConnChecker()
 		if err != nil {
 			return fmt.Errorf("failed to create connchecker: %w", err)
 		}
 
-		go wg.Connchecker.RunReceiver()
-		go wg.Connchecker.RunReceiverDisconnectObserver()
+		switch d := tc.drivers[tc.driverName].(type) {
+		case *tunnelwg.Wireguard:
+			if err := d.SetNewClient(); err != nil {
+				return fmt.Errorf("an error occurred while setting new client in tunnel driver")
+			}
+			d.Connchecker = connchecker
+		case *tunnelipsec.IPsec:
+			// The xfrm states and policies are still managed in the host netns, hence no need to reconfigure the driver.
+			d.Connchecker = connchecker
+		}
+
+		go connchecker.RunReceiver()
+		go connchecker.RunReceiverDisconnectObserver()
 
 		return nil
 	}
@@ -165,6 +212,7 @@ func NewTunnelController(ctx context.Context, wg *sync.WaitGroup,
 	for _, d := range tc.drivers {
 		metrics.Registry.MustRegister(d)
 	}
+	metrics.Registry.MustRegister(tunnelmetrics.PeerRTT, tunnelmetrics.PeerJitter, tunnelmetrics.PeerPacketLoss)
 
 	return tc, nil
 }
@@ -180,7 +228,7 @@ func (tc *TunnelController) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		if err = tc.EnsureIPTablesRulesPerCluster(tep); err != nil {
 			return err
 		}
-		con, err = tc.connectToPeer(tep, tc.forgeConncheckUpdateStatus(ctx, req))
+		con, err = tc.connectToPeer(tc.replicaTunnelEndpoint(tep), tc.forgeConncheckUpdateStatus(ctx, req))
 		if err != nil {
 			return err
 		}
@@ -188,7 +236,8 @@ func (tc *TunnelController) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		tc.readyClustersMutex.Lock()
 		defer tc.readyClustersMutex.Unlock()
 		tc.readyClusters[tep.Spec.ClusterIdentity.ClusterID] = struct{}{}
-		added, err := tc.EnsureRoutesPerCluster(tep)
+		// The routes leverage the path MTU discovered by the current replica.
+		added, err := tc.EnsureRoutesPerCluster(tc.replicaTunnelEndpoint(tep))
 		if err != nil {
 			klog.Errorf("%s -> unable to configure route '%s': %s", tep.Spec.ClusterIdentity, remotePodCIDR, err)
 			tc.Eventf(tep, "Warning", "Processing", "unable to remove outdated route: %s", err.Error())
@@ -206,6 +255,11 @@ func (tc *TunnelController) Reconcile(ctx context.Context, req ctrl.Request) (ct
 				tep.Spec.ClusterIdentity, err.Error())
 			return err
 		}
+		if err := tc.removeIP6TablesConfigurationPerCluster(tep); err != nil {
+			klog.Errorf("%s -> unable to remove ip6tables configuration: %s",
+				tep.Spec.ClusterIdentity, err.Error())
+			return err
+		}
 		if err := tc.disconnectFromPeer(tep); err != nil {
 			return err
 		}
@@ -261,6 +315,16 @@ func (tc *TunnelController) Reconcile(ctx context.Context, req ctrl.Request) (ct
 				klog.Errorf("an error occurred while attempting to cleanup possible leftover finalizers from resource %s: %s", tep.Name, err)
 				return ctrl.Result{}, err
 			}
+			if err := tc.cleanupGatewayFinalizers(ctx, tep); err != nil {
+				klog.Errorf("an error occurred while attempting to cleanup possible leftover finalizers from resource %s: %s", tep.Name, err)
+				return ctrl.Result{}, err
+			}
+			if tc.hotStandby {
+				if err := tc.deleteTunnelLease(ctx, tep); err != nil {
+					klog.Errorf("%s -> an error occurred while deleting the tunnel lease: %v", tep.Spec.ClusterIdentity, err)
+					return ctrl.Result{}, err
+				}
+			}
 
 			if err := tc.Update(ctx, tep); err != nil {
 				if k8sApiErrors.IsConflict(err) {
@@ -274,11 +338,43 @@ func (tc *TunnelController) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		// If object is being deleted and does not have a finalizer we just return.
 		return ctrl.Result{}, nil
 	}
+	if tc.hotStandby {
+		// In hot-standby mode, only the replica holding the lease establishes the tunnel, since all of them share the same keys.
+		// The reconciliation is periodically repeated, to renew the lease (or to acquire it, in case it is no longer held).
+		held, err := tc.acquireTunnelLease(ctx, tep)
+		if err != nil {
+			klog.Errorf("%s -> an error occurred while acquiring the tunnel lease: %v", tep.Spec.ClusterIdentity, err)
+		}
+		if held {
+			failover, err := tc.shouldFailover(ctx, tep)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if failover {
+				klog.Warningf("%s -> gateway replica %s failing over, as disconnected from the remote cluster", tep.Spec.ClusterIdentity, tc.podName)
+				if err := tc.releaseTunnelLease(ctx, tep, true); err != nil {
+					return ctrl.Result{}, err
+				}
+				held = false
+			}
+		}
+		if !held {
+			if err := tc.gatewayNetns.Do(func(_ ns.NetNS) error { return tc.standbyReplica(tep) }); err != nil {
+				return ctrl.Result{}, err
+			}
+			con = &netv1alpha1.Connection{Status: netv1alpha1.Connecting, StatusMessage: replicaStandbyMessage}
+			return ctrl.Result{RequeueAfter: tc.retryPeriod}, tc.updateStatus(ctx, con, tep)
+		}
+	}
 	if err := tc.gatewayNetns.Do(configGWNetns); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, tc.updateStatus(con, tep)
+	var result ctrl.Result
+	if tc.hotStandby {
+		result.RequeueAfter = tc.retryPeriod
+	}
+	return result, tc.updateStatus(ctx, con, tep)
 }
 
 // EnforceIP enforce the presence of an ip on an interface.
@@ -331,15 +427,20 @@ func (tc *TunnelController) disconnectFromPeer(ep *netv1alpha1.TunnelEndpoint) e
 	}
 	tc.Event(ep, "Normal", "Processing", "connection closed")
 	klog.Infof("%s -> vpn connection correctly closed", ep.Spec.ClusterIdentity)
+	deleteConnectionStatsMetrics(ep)
 	return nil
 }
 
 func (tc *TunnelController) forgeConncheckUpdateStatus(ctx context.Context, req ctrl.Request) conncheck.UpdateFunc {
-	return func(connected bool, latency time.Duration, timestamp time.Time) error {
+	return func(connected bool, stats conncheck.ConnectionStats, timestamp time.Time) error {
 		var tep = new(netv1alpha1.TunnelEndpoint)
 		if err := tc.Get(ctx, req.NamespacedName, tep); err != nil && !k8sApiErrors.IsNotFound(err) {
 			return fmt.Errorf("unable to fetch resource %s: %w", req.String(), err)
 		}
+		observeConnectionStats(tep, connected, &stats)
+		if tc.hotStandby {
+			return tc.updateReplicaConnectionStatus(ctx, tep, connected, &stats, timestamp)
+		}
 		conn := tep.Status.Connection
 		if connected {
 			conn.Status = netv1alpha1.Connected
@@ -348,16 +449,15 @@ func (tc *TunnelController) forgeConncheckUpdateStatus(ctx context.Context, req
 			conn.Status = netv1alpha1.ConnectionError
 			conn.StatusMessage = netv1alpha1.ConnectionErrorMessage
 		}
+		// A change of the path MTU is immediately reflected in the status, to trigger the reconfiguration of the routes.
 		if tep.Status.Connection.Status != conn.Status || tep.Status.Connection.StatusMessage != conn.StatusMessage ||
+			tep.Status.Connection.PathMTU != stats.PathMTU ||
 			timestamp.Sub(tep.Status.Connection.Latency.Timestamp.Time) > tc.updateStatusInterval {
 			if tep.Status.Connection.Status != conn.Status || tep.Status.Connection.StatusMessage != conn.StatusMessage {
 				klog.Infof("%s -> changing status to %s %q",
 					tep.Spec.ClusterIdentity, conn.Status, conn.StatusMessage)
 			}
-			conn.Latency = netv1alpha1.ConnectionLatency{
-				Value:     liqonetutils.FormatLatency(latency),
-				Timestamp: metav1.Time{Time: timestamp},
-			}
+			setConnectionStats(&conn, &stats, timestamp)
 			tep.Status.Connection = conn
 			if err := tc.Client.Status().Update(ctx, tep); err != nil {
 				return fmt.Errorf("unable to update resource %s: %w", req.String(), err)
@@ -367,6 +467,42 @@ func (tc *TunnelController) forgeConncheckUpdateStatus(ctx context.Context, req
 	}
 }
 
+// setConnectionStats sets the latency and packet loss fields of the given connection according to the given statistics.
+func setConnectionStats(conn *netv1alpha1.Connection, stats *conncheck.ConnectionStats, timestamp time.Time) {
+	conn.Latency = netv1alpha1.ConnectionLatency{
+		Value:     liqonetutils.FormatLatency(stats.Latency),
+		Jitter:    liqonetutils.FormatLatency(stats.Jitter),
+		P50:       liqonetutils.FormatLatency(stats.P50),
+		P90:       liqonetutils.FormatLatency(stats.P90),
+		P99:       liqonetutils.FormatLatency(stats.P99),
+		Timestamp: metav1.Time{Time: timestamp},
+	}
+	conn.PacketLoss = liqonetutils.FormatPacketLoss(stats.PacketLoss)
+	conn.PathMTU = stats.PathMTU
+}
+
+// observeConnectionStats records the given connection statistics in the corresponding prometheus metrics.
+func observeConnectionStats(tep *netv1alpha1.TunnelEndpoint, connected bool, stats *conncheck.ConnectionStats) {
+	if tep.Spec.ClusterIdentity.ClusterID == "" {
+		return
+	}
+	labels := prometheus.Labels{"cluster_id": tep.Spec.ClusterIdentity.ClusterID, "cluster_name": tep.Spec.ClusterIdentity.ClusterName}
+	tunnelmetrics.PeerPacketLoss.With(labels).Set(stats.PacketLoss)
+	// The round-trip time and jitter are observed only upon the reception of a PONG, to avoid skewing the distributions.
+	if connected {
+		tunnelmetrics.PeerRTT.With(labels).Observe(stats.Latency.Seconds())
+		tunnelmetrics.PeerJitter.With(labels).Observe(stats.Jitter.Seconds())
+	}
+}
+
+// deleteConnectionStatsMetrics removes the connection statistics metrics concerning the given peer.
+func deleteConnectionStatsMetrics(tep *netv1alpha1.TunnelEndpoint) {
+	labels := prometheus.Labels{"cluster_id": tep.Spec.ClusterIdentity.ClusterID, "cluster_name": tep.Spec.ClusterIdentity.ClusterName}
+	tunnelmetrics.PeerPacketLoss.Delete(labels)
+	tunnelmetrics.PeerRTT.Delete(labels)
+	tunnelmetrics.PeerJitter.Delete(labels)
+}
+
 // RemoveAllTunnels used to remove all the tunnel interfaces when the controller is closed.
 // It does not return an error, but just logs them, cause we can not recover from
 // them at exit time.
@@ -384,35 +520,62 @@ func (tc *TunnelController) RemoveAllTunnels() {
 // EnsureIPTablesRulesPerCluster ensures the iptables rules needed to configure the network for
 // a given remote cluster.
 func (tc *TunnelController) EnsureIPTablesRulesPerCluster(tep *netv1alpha1.TunnelEndpoint) error {
-	if err := tc.EnsureChainsPerCluster(tep.Spec.ClusterIdentity.ClusterID); err != nil {
+	if err := tc.ensureIPTablesRulesPerFamily(&tc.IPTHandler, tep); err != nil {
+		return err
+	}
+	// In case of dual-stack peerings, the same rules are enforced for the IPv6 networks through ip6tables.
+	if tepv6, ok := liqonetutils.GetIPv6TunnelEndpoint(tep); ok {
+		if tc.ip6tHandler == nil {
+			err := fmt.Errorf("ip6tables is not available in the gateway network namespace")
+			tc.Eventf(tep, "Warning", "Processing", "unable to insert ip6tables rules: %v", err)
+			return err
+		}
+		if err := tc.ensureIPTablesRulesPerFamily(tc.ip6tHandler, tepv6); err != nil {
+			return err
+		}
+	}
+	tc.Event(tep, "Normal", "Processing", "iptables rules correctly inserted")
+	return nil
+}
+
+func (tc *TunnelController) ensureIPTablesRulesPerFamily(handler *iptables.IPTHandler, tep *netv1alpha1.TunnelEndpoint) error {
+	if err := handler.EnsureChainsPerCluster(tep.Spec.ClusterIdentity.ClusterID); err != nil {
 		klog.Errorf("%s -> an error occurred while creating iptables chains for the remote peer: %s", tep.Spec.ClusterIdentity, err.Error())
 		tc.Eventf(tep, "Warning", "Processing", "unable to insert iptables rules: %v", err)
 		return err
 	}
-	if err := tc.EnsureChainRulesPerCluster(tep); err != nil {
+	if err := handler.EnsureChainRulesPerCluster(tep); err != nil {
 		klog.Errorf("%s -> an error occurred while inserting iptables chain rules for the remote peer: %s", tep.Spec.ClusterIdentity, err.Error())
 		tc.Eventf(tep, "Warning", "Processing", "unable to insert iptables rules: %v", err)
 		return err
 	}
-	if err := tc.EnsureForwardExtRules(tep); err != nil {
+	if err := handler.EnsureForwardExtRules(tep); err != nil {
 		klog.Errorf("%s -> an error occurred while inserting iptables forwarding rules for the remote peer: %s", tep.Spec.ClusterIdentity, err.Error())
 		tc.Eventf(tep, "Warning", "Processing", "unable to insert iptables rules: %v", err)
 		return err
 	}
-	if err := tc.EnsurePostroutingRules(tep); err != nil {
+	if err := handler.EnsurePostroutingRules(tep); err != nil {
 		klog.Errorf("%s -> an error occurred while inserting iptables postrouting rules for the remote peer: %s", tep.Spec.ClusterIdentity, err.Error())
 		tc.Eventf(tep, "Warning", "Processing", "unable to insert iptables rules: %v", err)
 		return err
 	}
-	if err := tc.EnsurePreroutingRulesPerTunnelEndpoint(tep); err != nil {
+	if err := handler.EnsurePreroutingRulesPerTunnelEndpoint(tep); err != nil {
 		klog.Errorf("%s -> an error occurred while inserting iptables prerouting rules for the remote peer: %s", tep.Spec.ClusterIdentity, err.Error())
 		tc.Eventf(tep, "Warning", "Processing", "unable to insert iptables rules: %v", err)
 		return err
 	}
-	tc.Event(tep, "Normal", "Processing", "iptables rules correctly inserted")
 	return nil
 }
 
+// removeIP6TablesConfigurationPerCluster removes the ip6tables rules configured for a dual-stack peering.
+func (tc *TunnelController) removeIP6TablesConfigurationPerCluster(tep *netv1alpha1.TunnelEndpoint) error {
+	tepv6, ok := liqonetutils.GetIPv6TunnelEndpoint(tep)
+	if !ok || tc.ip6tHandler == nil {
+		return nil
+	}
+	return tc.ip6tHandler.RemoveIPTablesConfigurationPerCluster(tepv6)
+}
+
 // SetupSignalHandlerForTunnelOperator registers for SIGTERM, SIGINT, SIGKILL. A context is returned
 // which is closed on one of these signals.
 func (tc *TunnelController) SetupSignalHandlerForTunnelOperator(ctx context.Context, wg *sync.WaitGroup) context.Context {
@@ -436,28 +599,50 @@ func (tc *TunnelController) SetupWithManager(mgr ctrl.Manager) error {
 			return false
 		},
 	}
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&netv1alpha1.TunnelEndpoint{}).WithEventFilter(resourceToBeProccesedPredicate).
-		Complete(tc)
+	ctrlBuilder := ctrl.NewControllerManagedBy(mgr).
+		For(&netv1alpha1.TunnelEndpoint{}, builder.WithPredicates(resourceToBeProccesedPredicate))
+	if tc.hotStandby {
+		// In hot-standby mode, the designated replica depends on the running replicas and on the status of all the tunnel endpoints.
+		// Additionally, the status of the replicas which are terminated shall be removed from all the tunnel endpoints, and the
+		// tunnel endpoints whose lease has not been renewed in time shall be reconciled to tear down the corresponding tunnels.
+		selector := liqolabels.GatewayLabelSelector()
+		ctrlBuilder = ctrlBuilder.Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(tc.enqueueAllTunnelEndpoints),
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(o client.Object) bool {
+				return selector.Matches(labels.Set(o.GetLabels()))
+			}))).
+			Watches(&netv1alpha1.TunnelEndpoint{}, handler.EnqueueRequestsFromMapFunc(tc.enqueueAllTunnelEndpoints),
+				builder.WithPredicates(replicasConnectionChangedPredicate())).
+			WatchesRawSource(&source.Channel{Source: tc.tunnelLeasesExpirations}, &handler.EnqueueRequestForObject{})
+		if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			tc.runTunnelLeasesWatchdog(ctx)
+			return nil
+		})); err != nil {
+			return err
+		}
+	}
+	return ctrlBuilder.Complete(tc)
 }
 
-// SetUpTunnelDrivers for each registered tunnel implementation it creates and initializes the driver.
+// SetUpTunnelDrivers creates and initializes the driver selected for the gateway among the registered tunnel implementations.
+// A single driver is set up, since all of them leverage the same tunnel interface.
 func (tc *TunnelController) SetUpTunnelDrivers(config tunnel.Config) error {
 	tc.drivers = make(map[string]tunnel.Driver)
-	for tunnelType, createDriverFunc := range tunnel.Drivers {
-		klog.V(3).Infof("Creating driver for tunnel of type %s", tunnelType)
-		d, err := createDriverFunc(tc.k8sClient, tc.namespace, config)
-		if err != nil {
-			return err
-		}
-		klog.V(3).Infof("Initializing driver for %s tunnel", tunnelType)
-		err = d.Init()
-		if err != nil {
-			return err
-		}
-		klog.V(3).Infof("Driver for %s tunnel created and initialized", tunnelType)
-		tc.drivers[tunnelType] = d
+	createDriverFunc, ok := tunnel.Drivers[tc.driverName]
+	if !ok {
+		return fmt.Errorf("no registered driver of type %s found", tc.driverName)
+	}
+	klog.V(3).Infof("Creating driver for tunnel of type %s", tc.driverName)
+	d, err := createDriverFunc(tc.k8sClient, tc.namespace, config)
+	if err != nil {
+		return err
+	}
+	klog.V(3).Infof("Initializing driver for %s tunnel", tc.driverName)
+	err = d.Init()
+	if err != nil {
+		return err
 	}
+	klog.V(3).Infof("Driver for %s tunnel created and initialized", tc.driverName)
+	tc.drivers[tc.driverName] = d
 	return nil
 }
 
@@ -478,14 +663,32 @@ func (tc *TunnelController) SetUpIPTablesHandler() error {
 		return err
 	}
 	tc.IPTHandler = iptHandler
+	tc.setUpIP6TablesHandler()
 	return nil
 }
 
+// setUpIP6TablesHandler initializes the ip6tables handler of TunnelController, used for dual-stack peerings.
+// Failures are not fatal, since IPv6 might not be supported by the node: in that case, dual-stack peerings are not supported.
+func (tc *TunnelController) setUpIP6TablesHandler() {
+	ip6tHandler, err := iptables.NewIPv6IPTHandler()
+	if err != nil {
+		klog.Warningf("unable to create ip6tables handler, dual-stack peerings will not be supported: %v", err)
+		return
+	}
+	if err := tc.gatewayNetns.Do(func(netNamespace ns.NetNS) error {
+		return ip6tHandler.Init()
+	}); err != nil {
+		klog.Warningf("unable to initialize ip6tables handler, dual-stack peerings will not be supported: %v", err)
+		return
+	}
+	tc.ip6tHandler = &ip6tHandler
+}
+
 // SetUpRouteManager initializes the Route manager of TunnelController.
 func (tc *TunnelController) SetUpRouteManager() error {
 	// Todo make the gateway routing manager to support more than one vpn technology at the same time.
 	// Todo it should use the right tunnel based on the backend type set inside the tep.
-	grm, err := liqorouting.NewGatewayRoutingManager(unix.RT_TABLE_MAIN, tc.drivers[liqoconst.DriverName].GetLink())
+	grm, err := liqorouting.NewGatewayRoutingManager(unix.RT_TABLE_MAIN, tc.drivers[tc.driverName].GetLink())
 	if err != nil {
 		return err
 	}
@@ -517,32 +720,56 @@ func (tc *TunnelController) setUpGWNetns(ctx context.Context, wg *sync.WaitGroup
 		return err
 	}
 
+	// Configure the veth pair for IPv6 as well, to support dual-stack peerings.
+	// Failures are not fatal, since IPv6 might not be supported by the node.
+	ipv6Enabled := true
+	if err = liqonetns.ConfigureVeth(&hostVeth, liqoconst.GatewayVethIPv6Addr, tc.hostNetns); err == nil {
+		err = liqonetns.ConfigureVeth(&gatewayVeth, liqoconst.HostVethIPv6Addr, tc.gatewayNetns)
+	}
+	if err != nil {
+		klog.Warningf("unable to configure veth pair for IPv6, dual-stack peerings will not be supported: %v", err)
+		ipv6Enabled = false
+	}
+
 	// Configure forwarding rule from hostveth to vxlan.
-	go enforceFirewallRules(ctx, wg, &tc.Ipt, hostVeth.Name)
+	go enforceFirewallRules(ctx, wg, tc.Ipt, hostVeth.Name)
 
 	// Configure the static neighbor entries, and subscribe to the events to perform updates in case the veth MAC address changes.
 	return liqonetns.RegisterOnVethHwAddrChangeHandler(tc.hostNetns, hostVethName, func(hwaddr net.HardwareAddr) error {
 		if err := liqonetns.ConfigureVethNeigh(&hostVeth, liqoconst.GatewayVethIPAddr, gatewayVeth.HardwareAddr, tc.hostNetns); err != nil {
 			return err
 		}
+		if err := liqonetns.ConfigureVethNeigh(&gatewayVeth, liqoconst.HostVethIPAddr, hwaddr, tc.gatewayNetns); err != nil {
+			return err
+		}
+		if !ipv6Enabled {
+			return nil
+		}
 
-		return liqonetns.ConfigureVethNeigh(&gatewayVeth, liqoconst.HostVethIPAddr, hwaddr, tc.gatewayNetns)
+		if err := liqonetns.ConfigureVethNeigh(&hostVeth, liqoconst.GatewayVethIPv6Addr, gatewayVeth.HardwareAddr, tc.hostNetns); err != nil {
+			return err
+		}
+		return liqonetns.ConfigureVethNeigh(&gatewayVeth, liqoconst.HostVethIPv6Addr, hwaddr, tc.gatewayNetns)
 	})
 }
 
-func (tc *TunnelController) updateStatus(con *netv1alpha1.Connection, tep *netv1alpha1.TunnelEndpoint) error {
-	if reflect.DeepEqual(*con, tep.Status.Connection) && tep.Status.GatewayIP == tc.podIP &&
+func (tc *TunnelController) updateStatus(ctx context.Context, con *netv1alpha1.Connection, tep *netv1alpha1.TunnelEndpoint) error {
+	if tc.hotStandby {
+		return tc.updateReplicaStatus(ctx, con, tep)
+	}
+	if reflect.DeepEqual(*con, tep.Status.Connection) && tep.Status.GatewayIP == tc.podIP && tep.Status.GatewayIPv6 == tc.podIPv6 &&
 		tep.Status.VethIFaceIndex == tc.hostVeth.Index && tep.Status.VethIP == liqoconst.GatewayVethIPAddr {
 		return nil
 	}
 
 	tep.Status.Connection = *con
 	tep.Status.GatewayIP = tc.podIP
+	tep.Status.GatewayIPv6 = tc.podIPv6
 	tep.Status.VethIFaceIndex = tc.hostVeth.Index
 	tep.Status.VethIFaceName = tc.hostVeth.Name
 	tep.Status.VethIP = liqoconst.GatewayVethIPAddr
 
-	if err := tc.Status().Update(context.Background(), tep); err != nil {
+	if err := tc.Status().Update(ctx, tep); err != nil {
 		if k8sApiErrors.IsConflict(err) {
 			klog.V(4).Infof("%s -> unable to update status for resource %s: %v", tep.Spec.ClusterIdentity, tep.Name, err)
 			return err
@@ -554,6 +781,37 @@ func (tc *TunnelController) updateStatus(con *netv1alpha1.Connection, tep *netv1
 	return nil
 }
 
+// cleanupGatewayFinalizers removes possible leftover finalizers of the gateway replicas, which might have not been deleted
+// in case a gateway pod is tore down ungracefully, or the gateway has been switched from/to the hot-standby mode.
+func (tc *TunnelController) cleanupGatewayFinalizers(ctx context.Context, tep *netv1alpha1.TunnelEndpoint) error {
+	sharedFinalizer := liqoconst.LiqoGatewayOperatorName + "." + liqoconst.FinalizersSuffix
+	existing := make(map[string]struct{})
+	if tc.hotStandby {
+		var pods corev1.PodList
+		if err := tc.List(ctx, &pods, client.InNamespace(tc.namespace),
+			client.MatchingLabelsSelector{Selector: liqolabels.GatewayLabelSelector()}); err != nil {
+			return fmt.Errorf("failed retrieving liqo gateway pods: %w", err)
+		}
+		for i := range pods.Items {
+			existing[liqoconst.LiqoGatewayReplicaFinalizer(pods.Items[i].Status.PodIP)] = struct{}{}
+		}
+	} else {
+		existing[sharedFinalizer] = struct{}{}
+	}
+
+	finalizers := make([]string, 0, len(tep.GetFinalizers()))
+	for _, finalizer := range tep.GetFinalizers() {
+		isGatewayFinalizer := strings.HasPrefix(finalizer, liqoconst.LiqoGatewayOperatorName+".")
+		if _, ok := existing[finalizer]; !isGatewayFinalizer || finalizer == tc.finalizer || ok {
+			finalizers = append(finalizers, finalizer)
+		} else {
+			klog.V(4).Infof("detected leftover finalizer %q to be removed from tunnel endpoint %q", finalizer, klog.KObj(tep))
+		}
+	}
+	tep.SetFinalizers(finalizers)
+	return nil
+}
+
 // cleanupRouteFinalizers removes possible leftover route controller finalizers,
 // which might have not been deleted in case a node is tore down ungracefully.
 func (tc *TunnelController) cleanupRouteFinalizers(ctx context.Context, tep *netv1alpha1.TunnelEndpoint) error {
@@ -585,10 +843,14 @@ func (tc *TunnelController) cleanupRouteFinalizers(ctx context.Context, tep *net
 func (tc *TunnelController) CleanUpConfiguration(netnsName string) {
 	klog.Infof("cleaning up...")
 
+	if tc.hotStandby {
+		tc.releaseTunnelEndpoints(context.Background())
+	}
+
 	klog.V(4).Infof("deleting iptables rules for cluster {%s}", tc.namespace)
 	fwRules := generateForwardingRules(tc.hostVeth.Name)
 	for i := range fwRules {
-		if err := deleteRule(&tc.Ipt, &fwRules[i]); err != nil {
+		if err := deleteRule(tc.Ipt, &fwRules[i]); err != nil {
 			klog.Errorf("an error occurred while deleting iptables rule {%s}: %v", &fwRules[i], err)
 		}
 	}
@@ -0,0 +1,129 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+
+	v1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
+)
+
+// FakeReflectionPolicies implements ReflectionPolicyInterface
+type FakeReflectionPolicies struct {
+	Fake *FakeVirtualkubeletV1alpha1
+	ns   string
+}
+
+var reflectionpoliciesResource = schema.GroupVersionResource{Group: "virtualkubelet.liqo.io", Version: "v1alpha1", Resource: "reflectionpolicies"}
+
+var reflectionpoliciesKind = schema.GroupVersionKind{Group: "virtualkubelet.liqo.io", Version: "v1alpha1", Kind: "ReflectionPolicy"}
+
+// Get takes name of the reflectionPolicy, and returns the corresponding reflectionPolicy object, and an error if there is any.
+func (c *FakeReflectionPolicies) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.ReflectionPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(reflectionpoliciesResource, c.ns, name), &v1alpha1.ReflectionPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ReflectionPolicy), err
+}
+
+// List takes label and field selectors, and returns the list of ReflectionPolicies that match those selectors.
+func (c *FakeReflectionPolicies) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.ReflectionPolicyList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(reflectionpoliciesResource, reflectionpoliciesKind, c.ns, opts), &v1alpha1.ReflectionPolicyList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.ReflectionPolicyList{ListMeta: obj.(*v1alpha1.ReflectionPolicyList).ListMeta}
+	for _, item := range obj.(*v1alpha1.ReflectionPolicyList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested reflectionPolicies.
+func (c *FakeReflectionPolicies) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(reflectionpoliciesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a reflectionPolicy and creates it.  Returns the server's representation of the reflectionPolicy, and an error, if there is any.
+func (c *FakeReflectionPolicies) Create(ctx context.Context, reflectionPolicy *v1alpha1.ReflectionPolicy, opts v1.CreateOptions) (result *v1alpha1.ReflectionPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(reflectionpoliciesResource, c.ns, reflectionPolicy), &v1alpha1.ReflectionPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ReflectionPolicy), err
+}
+
+// Update takes the representation of a reflectionPolicy and updates it. Returns the server's representation of the reflectionPolicy, and an error, if there is any.
+func (c *FakeReflectionPolicies) Update(ctx context.Context, reflectionPolicy *v1alpha1.ReflectionPolicy, opts v1.UpdateOptions) (result *v1alpha1.ReflectionPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(reflectionpoliciesResource, c.ns, reflectionPolicy), &v1alpha1.ReflectionPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ReflectionPolicy), err
+}
+
+// Delete takes name of the reflectionPolicy and deletes it. Returns an error if one occurs.
+func (c *FakeReflectionPolicies) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(reflectionpoliciesResource, c.ns, name, opts), &v1alpha1.ReflectionPolicy{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeReflectionPolicies) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(reflectionpoliciesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.ReflectionPolicyList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched reflectionPolicy.
+func (c *FakeReflectionPolicies) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ReflectionPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(reflectionpoliciesResource, c.ns, name, pt, data, subresources...), &v1alpha1.ReflectionPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ReflectionPolicy), err
+}
@@ -18,6 +18,8 @@ package v1alpha1
 
 type NamespaceMapExpansion interface{}
 
+type ReflectionPolicyExpansion interface{}
+
 type ShadowEndpointSliceExpansion interface{}
 
 type ShadowPodExpansion interface{}
@@ -0,0 +1,177 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+
+	v1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
+	scheme "github.com/liqotech/liqo/pkg/client/clientset/versioned/scheme"
+)
+
+// ReflectionPoliciesGetter has a method to return a ReflectionPolicyInterface.
+// A group's client should implement this interface.
+type ReflectionPoliciesGetter interface {
+	ReflectionPolicies(namespace string) ReflectionPolicyInterface
+}
+
+// ReflectionPolicyInterface has methods to work with ReflectionPolicy resources.
+type ReflectionPolicyInterface interface {
+	Create(ctx context.Context, reflectionPolicy *v1alpha1.ReflectionPolicy, opts v1.CreateOptions) (*v1alpha1.ReflectionPolicy, error)
+	Update(ctx context.Context, reflectionPolicy *v1alpha1.ReflectionPolicy, opts v1.UpdateOptions) (*v1alpha1.ReflectionPolicy, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.ReflectionPolicy, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.ReflectionPolicyList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ReflectionPolicy, err error)
+	ReflectionPolicyExpansion
+}
+
+// reflectionPolicies implements ReflectionPolicyInterface
+type reflectionPolicies struct {
+	client rest.Interface
+	ns     string
+}
+
+// newReflectionPolicies returns a ReflectionPolicies
+func newReflectionPolicies(c *VirtualkubeletV1alpha1Client, namespace string) *reflectionPolicies {
+	return &reflectionPolicies{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the reflectionPolicy, and returns the corresponding reflectionPolicy object, and an error if there is any.
+func (c *reflectionPolicies) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.ReflectionPolicy, err error) {
+	result = &v1alpha1.ReflectionPolicy{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("reflectionpolicies").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of ReflectionPolicies that match those selectors.
+func (c *reflectionPolicies) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.ReflectionPolicyList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.ReflectionPolicyList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("reflectionpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested reflectionPolicies.
+func (c *reflectionPolicies) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("reflectionpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a reflectionPolicy and creates it.  Returns the server's representation of the reflectionPolicy, and an error, if there is any.
+func (c *reflectionPolicies) Create(ctx context.Context, reflectionPolicy *v1alpha1.ReflectionPolicy, opts v1.CreateOptions) (result *v1alpha1.ReflectionPolicy, err error) {
+	result = &v1alpha1.ReflectionPolicy{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("reflectionpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(reflectionPolicy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a reflectionPolicy and updates it. Returns the server's representation of the reflectionPolicy, and an error, if there is any.
+func (c *reflectionPolicies) Update(ctx context.Context, reflectionPolicy *v1alpha1.ReflectionPolicy, opts v1.UpdateOptions) (result *v1alpha1.ReflectionPolicy, err error) {
+	result = &v1alpha1.ReflectionPolicy{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("reflectionpolicies").
+		Name(reflectionPolicy.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(reflectionPolicy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the reflectionPolicy and deletes it. Returns an error if one occurs.
+func (c *reflectionPolicies) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("reflectionpolicies").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *reflectionPolicies) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("reflectionpolicies").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched reflectionPolicy.
+func (c *reflectionPolicies) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ReflectionPolicy, err error) {
+	result = &v1alpha1.ReflectionPolicy{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("reflectionpolicies").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
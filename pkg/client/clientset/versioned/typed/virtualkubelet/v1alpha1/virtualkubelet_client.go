@@ -28,6 +28,7 @@ import (
 type VirtualkubeletV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	NamespaceMapsGetter
+	ReflectionPoliciesGetter
 	ShadowEndpointSlicesGetter
 	ShadowPodsGetter
 	VirtualNodesGetter
@@ -42,6 +43,10 @@ func (c *VirtualkubeletV1alpha1Client) NamespaceMaps(namespace string) Namespace
 	return newNamespaceMaps(c, namespace)
 }
 
+func (c *VirtualkubeletV1alpha1Client) ReflectionPolicies(namespace string) ReflectionPolicyInterface {
+	return newReflectionPolicies(c, namespace)
+}
+
 func (c *VirtualkubeletV1alpha1Client) ShadowEndpointSlices(namespace string) ShadowEndpointSliceInterface {
 	return newShadowEndpointSlices(c, namespace)
 }
@@ -24,6 +24,8 @@ import (
 type Interface interface {
 	// NamespaceMaps returns a NamespaceMapInformer.
 	NamespaceMaps() NamespaceMapInformer
+	// ReflectionPolicies returns a ReflectionPolicyInformer.
+	ReflectionPolicies() ReflectionPolicyInformer
 	// ShadowEndpointSlices returns a ShadowEndpointSliceInformer.
 	ShadowEndpointSlices() ShadowEndpointSliceInformer
 	// ShadowPods returns a ShadowPodInformer.
@@ -48,6 +50,11 @@ func (v *version) NamespaceMaps() NamespaceMapInformer {
 	return &namespaceMapInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// ReflectionPolicies returns a ReflectionPolicyInformer.
+func (v *version) ReflectionPolicies() ReflectionPolicyInformer {
+	return &reflectionPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // ShadowEndpointSlices returns a ShadowEndpointSliceInformer.
 func (v *version) ShadowEndpointSlices() ShadowEndpointSliceInformer {
 	return &shadowEndpointSliceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
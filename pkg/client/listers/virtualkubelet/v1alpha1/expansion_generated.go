@@ -24,6 +24,14 @@ type NamespaceMapListerExpansion interface{}
 // NamespaceMapNamespaceLister.
 type NamespaceMapNamespaceListerExpansion interface{}
 
+// ReflectionPolicyListerExpansion allows custom methods to be added to
+// ReflectionPolicyLister.
+type ReflectionPolicyListerExpansion interface{}
+
+// ReflectionPolicyNamespaceListerExpansion allows custom methods to be added to
+// ReflectionPolicyNamespaceLister.
+type ReflectionPolicyNamespaceListerExpansion interface{}
+
 // ShadowEndpointSliceListerExpansion allows custom methods to be added to
 // ShadowEndpointSliceLister.
 type ShadowEndpointSliceListerExpansion interface{}
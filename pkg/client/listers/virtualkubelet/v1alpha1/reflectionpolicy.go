@@ -0,0 +1,98 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	v1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
+)
+
+// ReflectionPolicyLister helps list ReflectionPolicies.
+// All objects returned here must be treated as read-only.
+type ReflectionPolicyLister interface {
+	// List lists all ReflectionPolicies in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.ReflectionPolicy, err error)
+	// ReflectionPolicies returns an object that can list and get ReflectionPolicies.
+	ReflectionPolicies(namespace string) ReflectionPolicyNamespaceLister
+	ReflectionPolicyListerExpansion
+}
+
+// reflectionPolicyLister implements the ReflectionPolicyLister interface.
+type reflectionPolicyLister struct {
+	indexer cache.Indexer
+}
+
+// NewReflectionPolicyLister returns a new ReflectionPolicyLister.
+func NewReflectionPolicyLister(indexer cache.Indexer) ReflectionPolicyLister {
+	return &reflectionPolicyLister{indexer: indexer}
+}
+
+// List lists all ReflectionPolicies in the indexer.
+func (s *reflectionPolicyLister) List(selector labels.Selector) (ret []*v1alpha1.ReflectionPolicy, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.ReflectionPolicy))
+	})
+	return ret, err
+}
+
+// ReflectionPolicies returns an object that can list and get ReflectionPolicies.
+func (s *reflectionPolicyLister) ReflectionPolicies(namespace string) ReflectionPolicyNamespaceLister {
+	return reflectionPolicyNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// ReflectionPolicyNamespaceLister helps list and get ReflectionPolicies.
+// All objects returned here must be treated as read-only.
+type ReflectionPolicyNamespaceLister interface {
+	// List lists all ReflectionPolicies in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.ReflectionPolicy, err error)
+	// Get retrieves the ReflectionPolicy from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.ReflectionPolicy, error)
+	ReflectionPolicyNamespaceListerExpansion
+}
+
+// reflectionPolicyNamespaceLister implements the ReflectionPolicyNamespaceLister
+// interface.
+type reflectionPolicyNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all ReflectionPolicies in the indexer for a given namespace.
+func (s reflectionPolicyNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.ReflectionPolicy, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.ReflectionPolicy))
+	})
+	return ret, err
+}
+
+// Get retrieves the ReflectionPolicy from the indexer for a given namespace and name.
+func (s reflectionPolicyNamespaceLister) Get(name string) (*v1alpha1.ReflectionPolicy, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("reflectionpolicy"), name)
+	}
+	return obj.(*v1alpha1.ReflectionPolicy), nil
+}
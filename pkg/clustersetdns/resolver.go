@@ -0,0 +1,208 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustersetdns
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/miekg/dns"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	mcsv1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	serviceexportctrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/serviceexport-controller"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+)
+
+// servicesLabel is the label separating the service name from the domain, as in <svc>.<ns>.svc.<domain>.
+const servicesLabel = "svc"
+
+// Query represents a parsed DNS query targeting the clusterset domain, in the form
+// [<hostname>.][<cluster>.]<service>.<namespace>.svc.<domain>.
+type Query struct {
+	Hostname  string
+	Cluster   string
+	Service   string
+	Namespace string
+}
+
+// ParseQuery parses the given fully qualified name, returning false if it does not belong to the given domain
+// or it does not match the expected format.
+func ParseQuery(name, domain string) (*Query, bool) {
+	name, domain = strings.ToLower(dns.Fqdn(name)), strings.ToLower(dns.Fqdn(domain))
+	if !strings.HasSuffix(name, "."+domain) {
+		return nil, false
+	}
+
+	labels := dns.SplitDomainName(strings.TrimSuffix(name, "."+domain))
+	if len(labels) < 3 || len(labels) > 5 || labels[len(labels)-1] != servicesLabel {
+		return nil, false
+	}
+
+	query := &Query{Namespace: labels[len(labels)-2], Service: labels[len(labels)-3]}
+	switch len(labels) {
+	case 5:
+		query.Hostname, query.Cluster = labels[0], labels[1]
+	case 4:
+		query.Cluster = labels[0]
+	}
+	return query, true
+}
+
+// Resolver resolves the queries targeting the clusterset domain, based on the ServiceImports
+// and the EndpointSlices imported or reflected from the peered clusters.
+type Resolver struct {
+	client.Client
+}
+
+// endpointSliceSource describes a source of EndpointSlices originated from remote clusters.
+type endpointSliceSource struct {
+	// managedBy is the value of the managed-by label of the EndpointSlices.
+	managedBy string
+	// serviceLabel is the key of the label identifying the service the EndpointSlices belong to.
+	serviceLabel string
+	// clusterLabel is the key of the label identifying the cluster the EndpointSlices originate from.
+	clusterLabel string
+}
+
+var endpointSliceSources = []endpointSliceSource{
+	{
+		// EndpointSlices imported through the Multi-Cluster Services API.
+		managedBy:    serviceexportctrl.EndpointSliceManagedBy,
+		serviceLabel: mcsv1alpha1.LabelServiceName,
+		clusterLabel: serviceexportctrl.SourceClusterLabel,
+	},
+	{
+		// EndpointSlices reflected as part of namespace offloading.
+		managedBy:    forge.EndpointSliceManagedBy,
+		serviceLabel: discoveryv1.LabelServiceName,
+		clusterLabel: forge.LiqoOriginClusterIDKey,
+	},
+}
+
+// Resolve returns the IPv4 addresses corresponding to the given query, and whether the name exists.
+// Service-level queries are resolved to the ClusterSetIP of the corresponding ServiceImport, if any,
+// and to the addresses of the ready endpoints in all remote clusters otherwise. Cluster-level queries
+// are resolved to the addresses of the ready endpoints in the given cluster (optionally, matching the hostname).
+func (r *Resolver) Resolve(ctx context.Context, query *Query) ([]net.IP, bool, error) {
+	if query.Cluster != "" {
+		clusterID, found, err := r.clusterID(ctx, query.Cluster)
+		if err != nil || !found {
+			return nil, false, err
+		}
+		return r.endpointAddresses(ctx, query, clusterID)
+	}
+
+	var serviceImport mcsv1alpha1.ServiceImport
+	err := r.Get(ctx, types.NamespacedName{Namespace: query.Namespace, Name: query.Service}, &serviceImport)
+	if client.IgnoreNotFound(err) != nil {
+		klog.Errorf("Failed to retrieve serviceimport %s/%s: %v", query.Namespace, query.Service, err)
+		return nil, false, err
+	}
+	imported := err == nil
+
+	if imported && serviceImport.Spec.Type == mcsv1alpha1.ClusterSetIP && len(serviceImport.Spec.IPs) > 0 {
+		return parseIPs(serviceImport.Spec.IPs), true, nil
+	}
+
+	addresses, found, err := r.endpointAddresses(ctx, query, "")
+	return addresses, found || imported, err
+}
+
+// endpointAddresses returns the addresses of the ready endpoints matching the given query (and originating from the given cluster, if set),
+// and whether the name exists (i.e., at least one matching EndpointSlice, or endpoint in case of hostname queries, is present).
+func (r *Resolver) endpointAddresses(ctx context.Context, query *Query, clusterID string) ([]net.IP, bool, error) {
+	found := false
+	addresses := map[string]net.IP{}
+
+	for i := range endpointSliceSources {
+		source := &endpointSliceSources[i]
+		selector := client.MatchingLabels{discoveryv1.LabelManagedBy: source.managedBy, source.serviceLabel: query.Service}
+		if clusterID != "" {
+			selector[source.clusterLabel] = clusterID
+		}
+
+		var slices discoveryv1.EndpointSliceList
+		if err := r.List(ctx, &slices, client.InNamespace(query.Namespace), selector); err != nil {
+			klog.Errorf("Failed to list the endpointslices of service %s/%s: %v", query.Namespace, query.Service, err)
+			return nil, false, err
+		}
+
+		for j := range slices.Items {
+			slice := &slices.Items[j]
+			if slice.AddressType != discoveryv1.AddressTypeIPv4 {
+				continue
+			}
+			found = found || query.Hostname == ""
+
+			for k := range slice.Endpoints {
+				endpoint := &slice.Endpoints[k]
+				if query.Hostname != "" && (endpoint.Hostname == nil || !strings.EqualFold(*endpoint.Hostname, query.Hostname)) {
+					continue
+				}
+
+				found = true
+				if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
+					continue
+				}
+				for _, ip := range parseIPs(endpoint.Addresses) {
+					addresses[ip.String()] = ip
+				}
+			}
+		}
+	}
+
+	output := make([]net.IP, 0, len(addresses))
+	for _, ip := range addresses {
+		output = append(output, ip)
+	}
+	sort.Slice(output, func(i, j int) bool { return bytes.Compare(output[i], output[j]) < 0 })
+	return output, found, nil
+}
+
+// clusterID returns the ID of the peered cluster with the given name or ID, and whether it has been found.
+func (r *Resolver) clusterID(ctx context.Context, cluster string) (string, bool, error) {
+	var fcs discoveryv1alpha1.ForeignClusterList
+	if err := r.List(ctx, &fcs); err != nil {
+		klog.Errorf("Failed to list foreignclusters: %v", err)
+		return "", false, err
+	}
+
+	for i := range fcs.Items {
+		identity := &fcs.Items[i].Spec.ClusterIdentity
+		if strings.EqualFold(identity.ClusterName, cluster) || strings.EqualFold(identity.ClusterID, cluster) {
+			return identity.ClusterID, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// parseIPs parses the given IPv4 addresses, skipping the invalid ones.
+func parseIPs(addresses []string) []net.IP {
+	var output []net.IP
+	for _, address := range addresses {
+		if ip := net.ParseIP(address).To4(); ip != nil {
+			output = append(output, ip)
+		}
+	}
+	return output
+}
@@ -0,0 +1,113 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustersetdns
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	mcsv1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
+)
+
+var _ = Describe("Resolver", func() {
+	Describe("The ParseQuery function", func() {
+		DescribeTable("should correctly parse the query",
+			func(name string, expected *Query) {
+				query, ok := ParseQuery(name, DefaultDomain)
+				if expected == nil {
+					Expect(ok).To(BeFalse())
+					return
+				}
+				Expect(ok).To(BeTrue())
+				Expect(query).To(Equal(expected))
+			},
+			Entry("service query", "bar.foo.svc.clusterset.local.", &Query{Service: "bar", Namespace: "foo"}),
+			Entry("service query (not fully qualified, uppercase)", "Bar.Foo.svc.clusterset.local", &Query{Service: "bar", Namespace: "foo"}),
+			Entry("cluster query", "cl.bar.foo.svc.clusterset.local.", &Query{Cluster: "cl", Service: "bar", Namespace: "foo"}),
+			Entry("hostname query", "host.cl.bar.foo.svc.clusterset.local.", &Query{Hostname: "host", Cluster: "cl", Service: "bar", Namespace: "foo"}),
+			Entry("different domain", "bar.foo.svc.cluster.local.", nil),
+			Entry("missing svc label", "bar.foo.clusterset.local.", nil),
+			Entry("too few labels", "foo.svc.clusterset.local.", nil),
+			Entry("too many labels", "a.b.c.bar.foo.svc.clusterset.local.", nil),
+		)
+	})
+
+	Describe("The Resolve function", func() {
+		var (
+			resolver *Resolver
+			query    Query
+		)
+
+		BeforeEach(func() {
+			resolver = &Resolver{Client: newFakeClient(
+				foreignCluster(clusterA, "cluster-a"),
+				foreignCluster(clusterB, "cluster-b"),
+				serviceImport("clusterset", mcsv1alpha1.ClusterSetIP, "10.96.0.10"),
+				importedSlice("clusterset-a", "clusterset", clusterA, endpoint{address: "20.0.0.1", ready: true}),
+				serviceImport("headless", mcsv1alpha1.Headless),
+				importedSlice("headless-a", "headless", clusterA,
+					endpoint{address: "20.0.0.2", hostname: "web-0", ready: true}, endpoint{address: "20.0.0.3", ready: false}),
+				importedSlice("headless-b", "headless", clusterB, endpoint{address: "30.0.0.2", hostname: "web-0", ready: true}),
+				serviceImport("empty", mcsv1alpha1.Headless),
+				reflectedSlice("reflected-a", "reflected", clusterA, endpoint{address: "20.0.0.4", ready: true}),
+			)}
+		})
+
+		type testcase struct {
+			query    Query
+			expected []string
+			found    bool
+		}
+
+		DescribeTable("should return the expected addresses",
+			func(c testcase) {
+				query = c.query
+				ips, found, err := resolver.Resolve(context.Background(), &query)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(found).To(Equal(c.found))
+
+				addresses := make([]string, 0, len(ips))
+				for _, ip := range ips {
+					addresses = append(addresses, ip.String())
+				}
+				Expect(addresses).To(ConsistOf(c.expected))
+			},
+			Entry("clusterset IP service", testcase{
+				query: Query{Service: "clusterset", Namespace: testNamespace}, expected: []string{"10.96.0.10"}, found: true}),
+			Entry("clusterset IP service, per-cluster query", testcase{
+				query: Query{Cluster: "cluster-a", Service: "clusterset", Namespace: testNamespace}, expected: []string{"20.0.0.1"}, found: true}),
+			Entry("headless service, ready endpoints of all clusters", testcase{
+				query: Query{Service: "headless", Namespace: testNamespace}, expected: []string{"20.0.0.2", "30.0.0.2"}, found: true}),
+			Entry("headless service, per-cluster query by cluster ID", testcase{
+				query: Query{Cluster: clusterB, Service: "headless", Namespace: testNamespace}, expected: []string{"30.0.0.2"}, found: true}),
+			Entry("headless service, hostname query", testcase{
+				query:    Query{Hostname: "web-0", Cluster: "cluster-a", Service: "headless", Namespace: testNamespace},
+				expected: []string{"20.0.0.2"}, found: true}),
+			Entry("headless service, non-existing hostname", testcase{
+				query: Query{Hostname: "web-1", Cluster: "cluster-a", Service: "headless", Namespace: testNamespace}, found: false}),
+			Entry("service without endpoints", testcase{
+				query: Query{Service: "empty", Namespace: testNamespace}, expected: []string{}, found: true}),
+			Entry("reflected service", testcase{
+				query: Query{Service: "reflected", Namespace: testNamespace}, expected: []string{"20.0.0.4"}, found: true}),
+			Entry("reflected service, per-cluster query", testcase{
+				query: Query{Cluster: "cluster-b", Service: "reflected", Namespace: testNamespace}, expected: []string{}, found: false}),
+			Entry("non-existing cluster", testcase{
+				query: Query{Cluster: "cluster-c", Service: "headless", Namespace: testNamespace}, found: false}),
+			Entry("non-existing service", testcase{
+				query: Query{Service: "nonexisting", Namespace: testNamespace}, expected: []string{}, found: false}),
+		)
+	})
+})
@@ -0,0 +1,158 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustersetdns
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultDomain is the default clusterset domain.
+	DefaultDomain = "clusterset.local"
+	// resolveTimeout is the maximum time allowed to resolve a query.
+	resolveTimeout = 5 * time.Second
+)
+
+// Server is a DNS server answering the queries for the clusterset domain, over both UDP and TCP.
+type Server struct {
+	Resolver *Resolver
+	// Address is the address the server listens on.
+	Address string
+	// Domain is the clusterset domain the server is authoritative for.
+	Domain string
+	// TTL is the time-to-live of the returned records, in seconds.
+	TTL uint32
+}
+
+// Start starts the DNS server, and blocks until the context is canceled. It implements the manager.Runnable interface.
+func (s *Server) Start(ctx context.Context) error {
+	servers := []*dns.Server{
+		{Addr: s.Address, Net: "udp", Handler: s},
+		{Addr: s.Address, Net: "tcp", Handler: s},
+	}
+
+	errs := make(chan error, len(servers))
+	for _, server := range servers {
+		go func(server *dns.Server) {
+			klog.Infof("Starting the clusterset DNS server for domain %q, listening on %s/%s", s.Domain, s.Address, server.Net)
+			errs <- server.ListenAndServe()
+		}(server)
+	}
+
+	var err error
+	select {
+	case <-ctx.Done():
+	case err = <-errs:
+		klog.Errorf("The clusterset DNS server failed: %v", err)
+	}
+
+	for _, server := range servers {
+		if shutdownErr := server.Shutdown(); shutdownErr != nil && !strings.Contains(shutdownErr.Error(), "server not started") {
+			klog.Errorf("Failed to shutdown the clusterset DNS server (%s): %v", server.Net, shutdownErr)
+		}
+	}
+	return err
+}
+
+// ServeDNS answers the given DNS request. It implements the dns.Handler interface.
+func (s *Server) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	msg := new(dns.Msg)
+	msg.SetReply(req)
+	msg.Authoritative = true
+
+	if len(req.Question) != 1 {
+		msg.SetRcode(req, dns.RcodeFormatError)
+		s.write(w, msg)
+		return
+	}
+
+	question := req.Question[0]
+	if !dns.IsSubDomain(dns.Fqdn(s.Domain), question.Name) {
+		// Queries outside the clusterset domain are not answered, as the server is not recursive.
+		msg.SetRcode(req, dns.RcodeRefused)
+		msg.Authoritative = false
+		s.write(w, msg)
+		return
+	}
+
+	query, ok := ParseQuery(question.Name, s.Domain)
+	if !ok {
+		klog.V(4).Infof("Query for %q (%s) does not match any service", question.Name, dns.TypeToString[question.Qtype])
+		s.writeNegative(w, msg, req, dns.RcodeNameError)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+	defer cancel()
+
+	ips, found, err := s.Resolver.Resolve(ctx, query)
+	switch {
+	case err != nil:
+		klog.Errorf("Failed to resolve %q: %v", question.Name, err)
+		msg.SetRcode(req, dns.RcodeServerFailure)
+		s.write(w, msg)
+		return
+	case !found:
+		klog.V(4).Infof("Query for %q (%s) does not match any service", question.Name, dns.TypeToString[question.Qtype])
+		s.writeNegative(w, msg, req, dns.RcodeNameError)
+		return
+	case question.Qtype != dns.TypeA && question.Qtype != dns.TypeANY:
+		// The name exists, but only IPv4 addresses are supported.
+		s.writeNegative(w, msg, req, dns.RcodeSuccess)
+		return
+	}
+
+	for _, ip := range ips {
+		msg.Answer = append(msg.Answer, &dns.A{
+			Hdr: dns.RR_Header{Name: question.Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: s.TTL},
+			A:   ip,
+		})
+	}
+	klog.V(4).Infof("Query for %q (%s) resolved to %d addresses", question.Name, dns.TypeToString[question.Qtype], len(ips))
+	s.write(w, msg)
+}
+
+// writeNegative writes a negative response (either NXDOMAIN or NODATA), including the SOA record of the domain.
+func (s *Server) writeNegative(w dns.ResponseWriter, msg, req *dns.Msg, rcode int) {
+	msg.SetRcode(req, rcode)
+	msg.Ns = append(msg.Ns, s.soa())
+	s.write(w, msg)
+}
+
+// soa returns the SOA record of the clusterset domain.
+func (s *Server) soa() dns.RR {
+	domain := dns.Fqdn(s.Domain)
+	return &dns.SOA{
+		Hdr:     dns.RR_Header{Name: domain, Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: s.TTL},
+		Ns:      "ns.dns." + domain,
+		Mbox:    "hostmaster." + domain,
+		Serial:  uint32(time.Now().Unix()),
+		Refresh: 7200,
+		Retry:   1800,
+		Expire:  86400,
+		Minttl:  s.TTL,
+	}
+}
+
+func (s *Server) write(w dns.ResponseWriter, msg *dns.Msg) {
+	if err := w.WriteMsg(msg); err != nil {
+		klog.Warningf("Failed to write the DNS response: %v", err)
+	}
+}
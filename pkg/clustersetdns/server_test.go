@@ -0,0 +1,97 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustersetdns
+
+import (
+	"net"
+
+	"github.com/miekg/dns"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	mcsv1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
+)
+
+// fakeResponseWriter is a dns.ResponseWriter capturing the written message.
+type fakeResponseWriter struct {
+	dns.ResponseWriter
+	msg *dns.Msg
+}
+
+func (f *fakeResponseWriter) WriteMsg(msg *dns.Msg) error {
+	f.msg = msg
+	return nil
+}
+
+var _ = Describe("Server", func() {
+	var (
+		server *Server
+		writer *fakeResponseWriter
+	)
+
+	BeforeEach(func() {
+		server = &Server{
+			Resolver: &Resolver{Client: newFakeClient(
+				foreignCluster(clusterA, "cluster-a"),
+				serviceImport("clusterset", mcsv1alpha1.ClusterSetIP, "10.96.0.10"),
+			)},
+			Domain: DefaultDomain,
+			TTL:    10,
+		}
+		writer = &fakeResponseWriter{}
+	})
+
+	serve := func(name string, qtype uint16) *dns.Msg {
+		req := new(dns.Msg)
+		req.SetQuestion(name, qtype)
+		server.ServeDNS(writer, req)
+		Expect(writer.msg).ToNot(BeNil())
+		return writer.msg
+	}
+
+	It("should answer A queries for existing services", func() {
+		msg := serve("clusterset.foo.svc.clusterset.local.", dns.TypeA)
+		Expect(msg.Rcode).To(Equal(dns.RcodeSuccess))
+		Expect(msg.Authoritative).To(BeTrue())
+		Expect(msg.Answer).To(HaveLen(1))
+		Expect(msg.Answer[0]).To(BeAssignableToTypeOf(&dns.A{}))
+		Expect(msg.Answer[0].(*dns.A).A.Equal(net.ParseIP("10.96.0.10"))).To(BeTrue())
+		Expect(msg.Answer[0].Header().Ttl).To(BeNumerically("==", 10))
+	})
+
+	It("should return NODATA for unsupported query types of existing services", func() {
+		msg := serve("clusterset.foo.svc.clusterset.local.", dns.TypeAAAA)
+		Expect(msg.Rcode).To(Equal(dns.RcodeSuccess))
+		Expect(msg.Answer).To(BeEmpty())
+		Expect(msg.Ns).To(HaveLen(1))
+	})
+
+	It("should return NXDOMAIN for non-existing services", func() {
+		msg := serve("nonexisting.foo.svc.clusterset.local.", dns.TypeA)
+		Expect(msg.Rcode).To(Equal(dns.RcodeNameError))
+		Expect(msg.Ns).To(HaveLen(1))
+		Expect(msg.Ns[0]).To(BeAssignableToTypeOf(&dns.SOA{}))
+	})
+
+	It("should return NXDOMAIN for malformed names within the domain", func() {
+		msg := serve("foo.bar.clusterset.local.", dns.TypeA)
+		Expect(msg.Rcode).To(Equal(dns.RcodeNameError))
+	})
+
+	It("should refuse queries outside the domain", func() {
+		msg := serve("kubernetes.default.svc.cluster.local.", dns.TypeA)
+		Expect(msg.Rcode).To(Equal(dns.RcodeRefused))
+		Expect(msg.Authoritative).To(BeFalse())
+	})
+})
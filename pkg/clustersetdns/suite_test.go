@@ -0,0 +1,114 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustersetdns
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	mcsv1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	serviceexportctrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/serviceexport-controller"
+	"github.com/liqotech/liqo/pkg/utils/testutil"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+)
+
+func TestClusterSetDNS(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ClusterSet DNS Suite")
+}
+
+var _ = BeforeSuite(func() {
+	testutil.LogsToGinkgoWriter()
+
+	Expect(corev1.AddToScheme(scheme.Scheme)).To(Succeed())
+	Expect(discoveryv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	Expect(discoveryv1alpha1.AddToScheme(scheme.Scheme)).To(Succeed())
+	Expect(mcsv1alpha1.AddToScheme(scheme.Scheme)).To(Succeed())
+})
+
+const (
+	testNamespace = "foo"
+	clusterA      = "cluster-a-id"
+	clusterB      = "cluster-b-id"
+)
+
+type endpoint struct {
+	address  string
+	hostname string
+	ready    bool
+}
+
+// importedSlice returns an EndpointSlice imported through the Multi-Cluster Services API.
+func importedSlice(name, service, clusterID string, endpoints ...endpoint) *discoveryv1.EndpointSlice {
+	return newSlice(name, map[string]string{
+		discoveryv1.LabelManagedBy:           serviceexportctrl.EndpointSliceManagedBy,
+		mcsv1alpha1.LabelServiceName:         service,
+		serviceexportctrl.SourceClusterLabel: clusterID,
+	}, endpoints...)
+}
+
+// reflectedSlice returns an EndpointSlice reflected as part of namespace offloading.
+func reflectedSlice(name, service, clusterID string, endpoints ...endpoint) *discoveryv1.EndpointSlice {
+	return newSlice(name, map[string]string{
+		discoveryv1.LabelManagedBy:   forge.EndpointSliceManagedBy,
+		discoveryv1.LabelServiceName: service,
+		forge.LiqoOriginClusterIDKey: clusterID,
+	}, endpoints...)
+}
+
+func newSlice(name string, labels map[string]string, endpoints ...endpoint) *discoveryv1.EndpointSlice {
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta:  metav1.ObjectMeta{Name: name, Namespace: testNamespace, Labels: labels},
+		AddressType: discoveryv1.AddressTypeIPv4,
+	}
+	for _, ep := range endpoints {
+		e := discoveryv1.Endpoint{Addresses: []string{ep.address}, Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(ep.ready)}}
+		if ep.hostname != "" {
+			e.Hostname = pointer.String(ep.hostname)
+		}
+		slice.Endpoints = append(slice.Endpoints, e)
+	}
+	return slice
+}
+
+func foreignCluster(clusterID, clusterName string) *discoveryv1alpha1.ForeignCluster {
+	return &discoveryv1alpha1.ForeignCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterName},
+		Spec: discoveryv1alpha1.ForeignClusterSpec{
+			ClusterIdentity: discoveryv1alpha1.ClusterIdentity{ClusterID: clusterID, ClusterName: clusterName},
+		},
+	}
+}
+
+func serviceImport(name string, svcType mcsv1alpha1.ServiceImportType, ips ...string) *mcsv1alpha1.ServiceImport {
+	return &mcsv1alpha1.ServiceImport{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Spec:       mcsv1alpha1.ServiceImportSpec{Type: svcType, IPs: ips},
+	}
+}
+
+func newFakeClient(objects ...client.Object) client.Client {
+	return fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objects...).Build()
+}
@@ -35,4 +35,27 @@ const (
 	// in the remote cluster. This annotation requires the API server support to be "remote" for the pod and the
 	// remote service account to be created.
 	RemoteServiceAccountNameAnnotation = "liqo.io/remote-service-account-name"
+
+	// RemoteDryRunRejectionsAnnotation is the annotation set by the pod webhook to report the reasons why the
+	// remote clusters would reject the offloading of the pod, as detected through a server-side dry-run.
+	RemoteDryRunRejectionsAnnotation = "liqo.io/remote-dry-run-rejections"
+
+	// SpreadingTargetAnnotation is the annotation set by the pod webhook to record the target (i.e., the local
+	// cluster or a remote cluster ID) the pod has been assigned to, according to the weighted offloading strategy.
+	SpreadingTargetAnnotation = "liqo.io/spreading-target"
+	// LocalSpreadingTarget is the value of the SpreadingTargetAnnotation identifying the local cluster.
+	LocalSpreadingTarget = "local"
+
+	// QuotaTargetsAnnotation is the annotation set by the pod webhook to record the comma-separated IDs of the remote
+	// clusters the pod may be offloaded to, to account for it in the offloading quotas until actually scheduled.
+	QuotaTargetsAnnotation = "liqo.io/quota-targets"
+
+	// ManagedTaintsAnnotation is the annotation set by the virtual kubelet on the virtual node to record the taints
+	// it applied according to the VirtualNode, so that they can be removed once no longer requested (also across restarts).
+	ManagedTaintsAnnotation = "liqo.io/managed-taints"
+
+	// RemoteClaimNotProvisionedAnnotation is the annotation set by the virtual kubelet on the PersistentVolumeClaims of
+	// StatefulSet pods bound to the virtual node, to record that they have been reported as not yet provisioned in the
+	// remote cluster (whose ID is the value). It is removed once the remote claim appears.
+	RemoteClaimNotProvisionedAnnotation = "liqo.io/remote-claim-not-provisioned"
 )
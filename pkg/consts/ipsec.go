@@ -0,0 +1,26 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consts
+
+const (
+	// IPsecDriverName is the name of the IPsec (kernel XFRM) tunnel driver.
+	IPsecDriverName = "ipsec"
+	// IPsecKeysName is the name of the secret that contains the public key used by the IPsec driver.
+	IPsecKeysName = "ipsec-pubkey"
+	// IPsecEndpointIP is the key of the endpointIP entry in the peer configuration of the IPsec driver.
+	IPsecEndpointIP = "endpointIP"
+	// IPsecRemoteCIDRs is the key of the remoteCIDRs entry in the peer configuration of the IPsec driver.
+	IPsecRemoteCIDRs = "remoteCIDRs"
+)
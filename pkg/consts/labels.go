@@ -54,4 +54,11 @@ const (
 	// IpamStorageResourceLabelValue is the constant representing
 	// the value of the label assigned to all IpamStorage resources.
 	IpamStorageResourceLabelValue = "true"
+
+	// ExportedServiceNamespaceLabelKey is the key of the label assigned to ExportedService resources
+	// to identify the namespace of the corresponding ServiceExport.
+	ExportedServiceNamespaceLabelKey = "discovery.liqo.io/exported-service-namespace"
+	// ExportedServiceNameLabelKey is the key of the label assigned to ExportedService resources
+	// to identify the name of the corresponding ServiceExport.
+	ExportedServiceNameLabelKey = "discovery.liqo.io/exported-service-name"
 )
@@ -41,6 +41,9 @@ const (
 	LiqoNetworkManagerName = "liqo-network-manager"
 	// GatewayLeaderElectionID used as name for the lease.coordination.k8s.io resource.
 	GatewayLeaderElectionID = "1d5hml1.gateway.net.liqo.io"
+	// GatewayTunnelLeasePrefix is the prefix of the name of the lease.coordination.k8s.io resources designating
+	// the gateway replica in charge of the tunnel towards each remote cluster, in hot-standby mode.
+	GatewayTunnelLeasePrefix = "liqo-gateway-tunnel-"
 	// GatewayNetnsName name of the custom network namespace used by liqo-gateway.
 	GatewayNetnsName = "liqo-netns"
 	// HostVethName name of the veth device living in the host network namespace,
@@ -57,6 +60,10 @@ const (
 	// from the host namespace. A trick to prevent arp requests for the traffic going
 	// through the veth pair.
 	GatewayVethIPAddr = "169.254.100.1"
+	// HostVethIPv6Addr is the IPv6 counterpart of HostVethIPAddr, used in case of dual-stack clusters.
+	HostVethIPv6Addr = "fd00:169:254:100::2"
+	// GatewayVethIPv6Addr is the IPv6 counterpart of GatewayVethIPAddr, used in case of dual-stack clusters.
+	GatewayVethIPv6Addr = "fd00:169:254:100::1"
 	// VxlanDeviceName name used for the vxlan devices created on each node by the instances
 	// of liqo-route.
 	VxlanDeviceName = "liqo.vxlan"
@@ -64,6 +71,10 @@ const (
 	OverlayNetworkPrefix = "240"
 	// OverlayNetworkMask size of the overlay network.
 	OverlayNetworkMask = "/8"
+	// OverlayNetworkIPv6Prefix prefix used for the IPv6 overlay network, in case of dual-stack clusters.
+	OverlayNetworkIPv6Prefix = "fd00:240::"
+	// OverlayNetworkIPv6Mask size of the IPv6 overlay network.
+	OverlayNetworkIPv6Mask = "/104"
 	// PodCIDR is a field of the TunnelEndpoint resource.
 	PodCIDR = "PodCIDR"
 	// ExternalCIDR is a field of the TunnelEndpoint resource.
@@ -109,3 +120,9 @@ const (
 func LiqoRouteFinalizer(podIP string) string {
 	return strings.Join([]string{LiqoRouteOperatorName, podIP, FinalizersSuffix}, ".")
 }
+
+// LiqoGatewayReplicaFinalizer returns the finalizer used by a given replica of the gateway operator in hot-standby mode,
+// based on its pod IP.
+func LiqoGatewayReplicaFinalizer(podIP string) string {
+	return strings.Join([]string{LiqoGatewayOperatorName, podIP, FinalizersSuffix}, ".")
+}
@@ -51,6 +51,11 @@ const (
 	LocalPodLabelKey = "liqo.io/shadowPod"
 	// LocalPodLabelValue value of the label added to the local pods that have been offloaded/replicated to a remote cluster.
 	LocalPodLabelValue = "true"
+	// AdmittedPodLabelKey label key added by the pod webhook to all the pods admitted in the namespaces enabled for offloading,
+	// to account for them before they are scheduled (i.e., possibly offloaded and labeled with LocalPodLabelKey).
+	AdmittedPodLabelKey = "liqo.io/offloading-admitted"
+	// AdmittedPodLabelValue value of the label added by the pod webhook to the pods admitted in the namespaces enabled for offloading.
+	AdmittedPodLabelValue = "true"
 
 	// ManagedByLabelKey is the label key used to indicate that a given resource is managed by another one.
 	ManagedByLabelKey = "liqo.io/managed-by"
@@ -74,6 +79,11 @@ const (
 	// AllowReflectionAnnotationKey is the annotation key used to indicate that a given object should be reflected into a remote cluster.
 	AllowReflectionAnnotationKey = "liqo.io/allow-reflection"
 
+	// ReflectStatusAnnotationKey is the annotation key used to indicate that the load balancer status of a given object
+	// (i.e., services and ingresses) should be propagated back from the remote cluster. The value is either "true", or
+	// the ID (or name) of the remote cluster the status shall be retrieved from, in case of multiple ones.
+	ReflectStatusAnnotationKey = "liqo.io/reflect-status"
+
 	// PodAntiAffinityPresetKey is the annotation key used to express an anti-affinity preset to apply to offloaded pods.
 	PodAntiAffinityPresetKey = "liqo.io/anti-affinity-preset"
 
@@ -35,4 +35,24 @@ const (
 	WgAllowedIPs = "allowedIPs"
 	// WgKeysName is the name of the secret that contains the public key used by wireguard.
 	WgKeysName = "wireguard-pubkey"
+	// WgNextPublicKey is the key of the entry, both in the back-end map and in the secret containing the wireguard keys,
+	// holding the public key which replaces the current one at the time specified by WgKeySwitchTime.
+	WgNextPublicKey = "nextPublicKey"
+	// WgNextPrivateKey is the key of the next private key entry for the secret containing the wireguard keys.
+	WgNextPrivateKey = "nextPrivateKey"
+	// WgKeySwitchTime is the key of the entry, both in the back-end map and in the secret containing the wireguard keys,
+	// holding the time (RFC3339 format) at which the next key replaces the current one.
+	WgKeySwitchTime = "keySwitchTime"
+	// WgKeyCreationTime is the key of the entry holding the time (RFC3339 format) at which the current key has been
+	// adopted, in the secret containing the wireguard keys.
+	WgKeyCreationTime = "keyCreationTime"
+	// WgTCPDriverName is the name of the driver carrying the WireGuard traffic over TCP connections,
+	// to traverse networks filtering UDP traffic. It leverages the same keys of the standard WireGuard driver.
+	WgTCPDriverName = "wireguard-tcp"
+	// WgTransport is the key of the transport entry in the back-end map of the WireGuard over TCP driver.
+	WgTransport = "transport"
+	// WgTransportTCP is the transport carrying the WireGuard datagrams over raw TCP connections.
+	WgTransportTCP = "tcp"
+	// WgTransportWebSocket is the transport carrying the WireGuard datagrams over WebSocket connections.
+	WgTransportWebSocket = "websocket"
 )
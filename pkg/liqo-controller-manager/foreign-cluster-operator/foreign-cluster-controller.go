@@ -124,6 +124,8 @@ type ForeignClusterReconciler struct {
 // +kubebuilder:rbac:groups=sharing.liqo.io,resources=resourceoffers/status,verbs=create;delete;deletecollection;list;watch
 // +kubebuilder:rbac:groups=net.liqo.io,resources=networkconfigs,verbs=*
 // +kubebuilder:rbac:groups=net.liqo.io,resources=networkconfigs/status,verbs=*
+// +kubebuilder:rbac:groups=discovery.liqo.io,resources=exportedservices,verbs=*
+// +kubebuilder:rbac:groups=discovery.liqo.io,resources=exportedservices/status,verbs=*
 // +kubebuilder:rbac:groups=net.liqo.io,resources=tunnelendpoints,verbs=get;list;watch
 // +kubebuilder:rbac:groups=net.liqo.io,resources=tunnelendpoints/status,verbs=get;watch;update
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
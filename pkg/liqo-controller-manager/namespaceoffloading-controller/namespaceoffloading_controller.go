@@ -16,9 +16,11 @@ package nsoffctrl
 
 import (
 	"context"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
@@ -26,10 +28,12 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
 	offv1alpha1 "github.com/liqotech/liqo/apis/offloading/v1alpha1"
@@ -43,6 +47,13 @@ type NamespaceOffloadingReconciler struct {
 	client.Client
 	Recorder     record.EventRecorder
 	LocalCluster discoveryv1alpha1.ClusterIdentity
+	// LocalPods provides access to the local offloaded pods, to account the resources subject to the offloading quota.
+	// It defaults to the manager the controller is registered to, if not set.
+	LocalPods cluster.Cluster
+	// AdmittedPods provides access to the pods admitted by the pod webhook, to account the ones not yet offloaded
+	// in the offloading quota, and to retrieve the distribution of the pods subject to the weighted offloading strategy.
+	// It defaults to the manager the controller is registered to, if not set.
+	AdmittedPods cluster.Cluster
 
 	// namespaces tracks the set of namespaces for which a NamespaceOffloading resource exists.
 	namespaces *syncset.SyncSet
@@ -50,6 +61,9 @@ type NamespaceOffloadingReconciler struct {
 
 const (
 	namespaceOffloadingControllerFinalizer = "namespaceoffloading-controller.liqo.io/finalizer"
+
+	// distributionResyncPeriod is the period the distribution of the pods subject to the weighted offloading strategy is refreshed.
+	distributionResyncPeriod = 1 * time.Minute
 )
 
 // cluster-role
@@ -60,6 +74,7 @@ const (
 // +kubebuilder:rbac:groups=virtualkubelet.liqo.io,resources=virtualnode, verbs=get;list;watch;patch;update
 // +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile implements the NamespaceOffloading reconciliation logic.
@@ -115,6 +130,9 @@ func (r *NamespaceOffloadingReconciler) Reconcile(ctx context.Context, req ctrl.
 	}
 
 	switch nsoff.Spec.PodOffloadingStrategy {
+	case offv1alpha1.WeightedPodOffloadingStrategyType:
+		// The distribution of the pods is periodically refreshed, as not all pod events are watched.
+		return ctrl.Result{RequeueAfter: distributionResyncPeriod}, r.enforceSchedulingLabelPresence(ctx, nsoff.Namespace)
 	case offv1alpha1.LocalAndRemotePodOffloadingStrategyType, offv1alpha1.RemotePodOffloadingStrategyType:
 		// If the offloading policy includes remote clusters, then ensure the corresponding namespace has the liqo scheduling label.
 		return ctrl.Result{}, r.enforceSchedulingLabelPresence(ctx, nsoff.Namespace)
@@ -127,16 +145,38 @@ func (r *NamespaceOffloadingReconciler) Reconcile(ctx context.Context, req ctrl.
 // SetupWithManager reconciles NamespaceOffloading Resources.
 func (r *NamespaceOffloadingReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.namespaces = syncset.New()
+	if r.LocalPods == nil {
+		r.LocalPods = mgr
+	}
+	if r.AdmittedPods == nil {
+		r.AdmittedPods = mgr
+	}
 
 	filter := predicate.NewPredicateFuncs(func(object client.Object) bool {
 		return object.GetName() == liqoconst.DefaultNamespaceOffloadingName
 	})
 
+	offloadedPods, err := predicate.LabelSelectorPredicate(metav1.LabelSelector{
+		MatchLabels: map[string]string{liqoconst.LocalPodLabelKey: liqoconst.LocalPodLabelValue},
+	})
+	if err != nil {
+		return err
+	}
+
+	admittedPods, err := predicate.LabelSelectorPredicate(metav1.LabelSelector{
+		MatchLabels: map[string]string{liqoconst.AdmittedPodLabelKey: liqoconst.AdmittedPodLabelValue},
+	})
+	if err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&offv1alpha1.NamespaceOffloading{}, builder.WithPredicates(filter)).
 		Watches(&mapsv1alpha1.NamespaceMap{}, r.namespaceMapHandlers()).
 		Watches(&mapsv1alpha1.VirtualNode{}, r.enqueueAll()).
 		Watches(&corev1.Node{}, r.enqueueAll()).
+		WatchesRawSource(source.Kind(r.LocalPods.GetCache(), &corev1.Pod{}), r.enqueueForPod(), builder.WithPredicates(offloadedPods)).
+		WatchesRawSource(source.Kind(r.AdmittedPods.GetCache(), &corev1.Pod{}), r.enqueueForPod(), builder.WithPredicates(admittedPods)).
 		Complete(r)
 }
 
@@ -178,6 +218,16 @@ func (r *NamespaceOffloadingReconciler) namespaceMapHandlers() handler.EventHand
 	}
 }
 
+// enqueueForPod enqueues the NamespaceOffloading of the namespace the given pod belongs to, to update the quota usage.
+func (r *NamespaceOffloadingReconciler) enqueueForPod() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(_ context.Context, o client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{
+			Name:      liqoconst.DefaultNamespaceOffloadingName,
+			Namespace: o.GetNamespace(),
+		}}}
+	})
+}
+
 func (r *NamespaceOffloadingReconciler) enqueueAll() handler.EventHandler {
 	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
 		var nsolist offv1alpha1.NamespaceOffloadingList
@@ -25,6 +25,7 @@ import (
 	offv1alpha1 "github.com/liqotech/liqo/apis/offloading/v1alpha1"
 	mapsv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
 	foreignclusterutils "github.com/liqotech/liqo/pkg/utils/foreignCluster"
+	offloadingutils "github.com/liqotech/liqo/pkg/utils/offloading"
 )
 
 // enforceStatus realigns the status of the NamespaceOffloading, depending on that of the NamespaceMaps.
@@ -44,6 +45,16 @@ func (r *NamespaceOffloadingReconciler) enforceStatus(ctx context.Context, nsoff
 	// Configure the global status given the conditions.
 	setNamespaceOffloadingStatus(nsoff, required, ready, failed)
 
+	// Account the resources consumed in the remote clusters, in case a quota is configured.
+	if err := r.setQuotaUsage(ctx, nsoff); err != nil {
+		return err
+	}
+
+	// Report the distribution of the pods, in case the weighted offloading strategy is selected.
+	if err := r.setDistribution(ctx, nsoff); err != nil {
+		return err
+	}
+
 	// Update the status just once at the end of the logic.
 	if err := r.Status().Update(ctx, nsoff); err != nil {
 		return fmt.Errorf("failed to update status: %w", err)
@@ -52,6 +63,36 @@ func (r *NamespaceOffloadingReconciler) enforceStatus(ctx context.Context, nsoff
 	return nil
 }
 
+// setQuotaUsage configures the resources currently consumed in the remote clusters, in case a quota is configured.
+func (r *NamespaceOffloadingReconciler) setQuotaUsage(ctx context.Context, nsoff *offv1alpha1.NamespaceOffloading) error {
+	if nsoff.Spec.Quota == nil {
+		nsoff.Status.QuotaUsage = nil
+		return nil
+	}
+
+	usage, err := offloadingutils.QuotaUsage(ctx, r.LocalPods.GetClient(), r.AdmittedPods.GetClient(), nsoff.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to account the quota usage: %w", err)
+	}
+	nsoff.Status.QuotaUsage = usage
+	return nil
+}
+
+// setDistribution configures the number of pods executed in the local and remote clusters, in case the weighted offloading strategy is selected.
+func (r *NamespaceOffloadingReconciler) setDistribution(ctx context.Context, nsoff *offv1alpha1.NamespaceOffloading) error {
+	if nsoff.Spec.PodOffloadingStrategy != offv1alpha1.WeightedPodOffloadingStrategyType {
+		nsoff.Status.Distribution = nil
+		return nil
+	}
+
+	distribution, err := offloadingutils.Distribution(ctx, r.Client, r.AdmittedPods.GetClient(), nsoff.Namespace, false)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve the pod distribution: %w", err)
+	}
+	nsoff.Status.Distribution = distribution
+	return nil
+}
+
 // remoteNamespaceName returns the remapped name corresponding to a given namespace.
 func (r *NamespaceOffloadingReconciler) remoteNamespaceName(nsoff *offv1alpha1.NamespaceOffloading) string {
 	if nsoff.Spec.NamespaceMappingStrategy == offv1alpha1.EnforceSameNameMappingStrategyType {
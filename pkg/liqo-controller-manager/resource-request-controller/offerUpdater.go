@@ -22,7 +22,9 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -33,6 +35,7 @@ import (
 	"github.com/liqotech/liqo/pkg/consts"
 	"github.com/liqotech/liqo/pkg/discovery"
 	resourcemonitors "github.com/liqotech/liqo/pkg/liqo-controller-manager/resource-request-controller/resource-monitors"
+	liqolabels "github.com/liqotech/liqo/pkg/utils/labels"
 )
 
 // OfferUpdater is a component that responds to ResourceRequests with the cluster's resources read from ResourceReader.
@@ -108,6 +111,8 @@ func (u *OfferUpdater) CreateOrUpdateOffer(cluster discoveryv1alpha1.ClusterIden
 	u.currentResources[cluster.ClusterID] = resources
 	u.clusterIdentityCache[cluster.ClusterID] = cluster
 
+	// Each resource pool is offered through a dedicated ResourceOffer, hence leading to a separate virtual node.
+	offerNames := make(map[string]struct{}, len(resources))
 	for i := range resources {
 		offer := &sharingv1alpha1.ResourceOffer{
 			ObjectMeta: metav1.ObjectMeta{
@@ -115,6 +120,7 @@ func (u *OfferUpdater) CreateOrUpdateOffer(cluster discoveryv1alpha1.ClusterIden
 				Name:      getOfferName(u.homeCluster, resources[i]),
 			},
 		}
+		offerNames[offer.Name] = struct{}{}
 
 		op, err := controllerutil.CreateOrUpdate(ctx, u.client, offer, func() error {
 			if offer.Labels != nil {
@@ -129,7 +135,8 @@ func (u *OfferUpdater) CreateOrUpdateOffer(cluster discoveryv1alpha1.ClusterIden
 				}
 			}
 			offer.Spec.ClusterID = u.homeCluster.ClusterID
-			offer.Spec.Labels = u.clusterLabels
+			offer.Spec.Labels = labels.Merge(u.clusterLabels, resources[i].Labels)
+			offer.Spec.Taints = getTaints(resources[i])
 			offer.Spec.NodeName = resources[i].PoolName
 			offer.Spec.NodeNamePrefix = resources[i].PoolPrefix
 
@@ -152,9 +159,39 @@ func (u *OfferUpdater) CreateOrUpdateOffer(cluster discoveryv1alpha1.ClusterIden
 		}
 		klog.Infof("%s -> %s Offer: %s/%s", u.homeCluster.ClusterName, op, offer.Namespace, offer.Name)
 	}
+
+	// An empty list is likely due to a transient issue of the ResourceReader, hence we do not withdraw the existing offers.
+	if len(resources) > 0 {
+		if err := u.withdrawStaleOffers(ctx, request, offerNames); err != nil {
+			return true, fmt.Errorf("error while withdrawing the offers of the removed resource pools: %w", err)
+		}
+	}
 	return false, nil
 }
 
+// withdrawStaleOffers withdraws the ResourceOffers issued for the given ResourceRequest,
+// which refer to resource pools no longer returned by the ResourceReader.
+func (u *OfferUpdater) withdrawStaleOffers(ctx context.Context, request *discoveryv1alpha1.ResourceRequest,
+	offerNames map[string]struct{}) error {
+	var offers sharingv1alpha1.ResourceOfferList
+	if err := u.client.List(ctx, &offers, client.InNamespace(request.GetNamespace()),
+		client.MatchingLabelsSelector{Selector: liqolabels.LocalLabelSelectorForCluster(request.Spec.ClusterIdentity.ClusterID)}); err != nil {
+		return err
+	}
+
+	for i := range offers.Items {
+		offer := &offers.Items[i]
+		if _, found := offerNames[offer.Name]; found || !metav1.IsControlledBy(offer, request) {
+			continue
+		}
+		klog.Infof("%s -> Resource pool of Offer %s/%s no longer available", u.homeCluster.ClusterName, offer.Namespace, offer.Name)
+		if err := invalidateResourceOffer(ctx, u.client, u.homeCluster, offer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // NotifyChange is used by the ResourceReader to notify that resources were changed for a single cluster
 // identified by clusterID or for all clusters by passing resourcemonitors.AllClusterIDs.
 func (u *OfferUpdater) NotifyChange(clusterID string) {
@@ -206,7 +243,8 @@ func (u *OfferUpdater) SetThreshold(updateThresholdPercentage uint) {
 	u.NotifyChange(resourcemonitors.AllClusterIDs)
 }
 
-// shouldUpdate checks if the resources have changed by at least updateThresholdPercentage since the last update.
+// shouldUpdate checks if the resources have changed by at least updateThresholdPercentage since the last update,
+// or the resource pools (along with their labels and taints) have changed.
 // checks are skipped if u.updateThresholdPercentage is 0.
 func (u *OfferUpdater) shouldUpdate(clusterID string) bool {
 	if u.updateThresholdPercentage == 0 {
@@ -215,34 +253,49 @@ func (u *OfferUpdater) shouldUpdate(clusterID string) bool {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	for i := range u.currentResources[clusterID] {
-		oldResources := u.currentResources[clusterID][i]
-		newResources, err := u.ResourceReader.ReadResources(ctx, clusterID)
-		if err != nil {
-			klog.Errorf("Error while reading resources from external monitor: %w", err)
-			// returns true when an error occurs in order to enqueue again the offer update, otherwise the update will be lost.
+	newResources, err := u.ResourceReader.ReadResources(ctx, clusterID)
+	if err != nil {
+		klog.Errorf("Error while reading resources from external monitor: %s", err)
+		// returns true when an error occurs in order to enqueue again the offer update, otherwise the update will be lost.
+		return true
+	}
+
+	// Check for any resource pool added or removed
+	if len(newResources) != len(u.currentResources[clusterID]) {
+		return true
+	}
+	oldPools := make(map[string]*resourcemonitors.ResourceList, len(u.currentResources[clusterID]))
+	for _, oldResources := range u.currentResources[clusterID] {
+		oldPools[getOfferName(u.homeCluster, oldResources)] = oldResources
+	}
+
+	for _, newResources := range newResources {
+		oldResources, found := oldPools[getOfferName(u.homeCluster, newResources)]
+		if !found {
+			return true
+		}
+		if !labels.Equals(oldResources.Labels, newResources.Labels) ||
+			!equality.Semantic.DeepEqual(getTaints(oldResources), getTaints(newResources)) {
 			return true
 		}
 
-		for j := range newResources {
-			// Check for any resources removed
-			for oldResourceName := range oldResources.Resources {
-				if _, exists := newResources[j].Resources[oldResourceName]; !exists {
-					return true
-				}
+		// Check for any resources removed
+		for oldResourceName := range oldResources.Resources {
+			if _, exists := newResources.Resources[oldResourceName]; !exists {
+				return true
 			}
-			// Check for any resources added
-			for newResourceName := range newResources[j].Resources {
-				if _, exists := oldResources.Resources[newResourceName]; !exists {
-					return true
-				}
+		}
+		// Check for any resources added
+		for newResourceName := range newResources.Resources {
+			if _, exists := oldResources.Resources[newResourceName]; !exists {
+				return true
 			}
-			for resourceName, newValue := range newResources[j].Resources {
-				oldValue := oldResources.Resources[resourceName]
-				absDiff := math.Abs(float64(newValue.Value() - oldValue.Value()))
-				if int64(absDiff) > oldValue.Value()*int64(u.updateThresholdPercentage)/100 {
-					return true
-				}
+		}
+		for resourceName, newValue := range newResources.Resources {
+			oldValue := oldResources.Resources[resourceName]
+			absDiff := math.Abs(float64(newValue.Value() - oldValue.Value()))
+			if int64(absDiff) > oldValue.Value()*int64(u.updateThresholdPercentage)/100 {
+				return true
 			}
 		}
 	}
@@ -250,6 +303,22 @@ func (u *OfferUpdater) shouldUpdate(clusterID string) bool {
 	return false
 }
 
+// getTaints returns the taints to be added to the virtual node corresponding to the given resource pool.
+func getTaints(resources *resourcemonitors.ResourceList) []corev1.Taint {
+	if len(resources.GetTaints()) == 0 {
+		return nil
+	}
+	taints := make([]corev1.Taint, 0, len(resources.GetTaints()))
+	for _, taint := range resources.GetTaints() {
+		taints = append(taints, corev1.Taint{
+			Key:    taint.GetKey(),
+			Value:  taint.GetValue(),
+			Effect: corev1.TaintEffect(taint.GetEffect()),
+		})
+	}
+	return taints
+}
+
 // GetResourceRequest returns ResourceRequest for the given cluster.
 func GetResourceRequest(ctx context.Context, k8sClient client.Client, clusterID string) (
 	*discoveryv1alpha1.ResourceRequest, error) {
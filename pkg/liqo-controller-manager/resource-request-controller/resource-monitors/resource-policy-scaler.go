@@ -0,0 +1,198 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcemonitors
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+var _ ResourceReader = &ResourcePolicyScaler{}
+
+// ResourcePolicyScaler scales the resources of a ResourceReader according to a ResourcePolicy, which
+// may offer different amounts of resources depending on the time of the day, on the consumer cluster
+// and on the priority tier it belongs to. Differently from the ResourceScaler, the remote clusters are
+// notified of a change whenever a time window boundary is crossed, or the policy is updated.
+type ResourcePolicyScaler struct {
+	Provider ResourceReader
+	// DefaultFactor is the factor applied if no policy is configured, or the policy does not specify a default one.
+	DefaultFactor float32
+
+	clock    clock.Clock
+	mutex    sync.RWMutex
+	policy   *ResourcePolicy
+	notifier ResourceUpdateNotifier
+	changed  chan struct{}
+}
+
+// NewResourcePolicyScaler creates a new ResourcePolicyScaler, applying the default factor until a policy is set.
+func NewResourcePolicyScaler(provider ResourceReader, defaultFactor float32) *ResourcePolicyScaler {
+	return newResourcePolicyScaler(provider, defaultFactor, clock.RealClock{})
+}
+
+func newResourcePolicyScaler(provider ResourceReader, defaultFactor float32, clk clock.Clock) *ResourcePolicyScaler {
+	return &ResourcePolicyScaler{
+		Provider:      provider,
+		DefaultFactor: defaultFactor,
+		clock:         clk,
+		changed:       make(chan struct{}, 1),
+	}
+}
+
+// Register sets an update notifier, and starts notifying it when a time window boundary is crossed.
+func (s *ResourcePolicyScaler) Register(ctx context.Context, notifier ResourceUpdateNotifier) {
+	s.mutex.Lock()
+	s.notifier = notifier
+	s.mutex.Unlock()
+
+	s.Provider.Register(ctx, notifier)
+	go s.runScheduler(ctx)
+}
+
+// ReadResources returns the provider's resources scaled according to the current policy.
+func (s *ResourcePolicyScaler) ReadResources(ctx context.Context, clusterID string) ([]*ResourceList, error) {
+	resources, err := s.Provider.ReadResources(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	factorOf := s.policy.factors(s.clock.Now(), clusterID, s.DefaultFactor)
+	s.mutex.RUnlock()
+
+	for i := range resources {
+		for resourceName, quantity := range resources[i].Resources {
+			scaled := quantity
+			ScaleResources(corev1.ResourceName(resourceName), scaled, factorOf(corev1.ResourceName(resourceName)))
+			resources[i].Resources[resourceName] = scaled
+		}
+	}
+	return resources, nil
+}
+
+// RemoveClusterID removes the given clusterID from the provider.
+func (s *ResourcePolicyScaler) RemoveClusterID(ctx context.Context, clusterID string) error {
+	return s.Provider.RemoveClusterID(ctx, clusterID)
+}
+
+// SetPolicy replaces the current policy (nil to restore the default factor), and notifies all the clusters of the change.
+func (s *ResourcePolicyScaler) SetPolicy(policy *ResourcePolicy) {
+	s.mutex.Lock()
+	s.policy = policy
+	s.mutex.Unlock()
+
+	// Wake up the scheduler, to take into account the new time windows.
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+	s.notify()
+}
+
+// WatchPolicyConfigMap configures the scaler to retrieve the policy from the given ConfigMap, and to update it whenever the ConfigMap changes.
+func (s *ResourcePolicyScaler) WatchPolicyConfigMap(ctx context.Context, clientset kubernetes.Interface,
+	namespace, name string, resyncPeriod time.Duration) {
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, resyncPeriod, informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		}))
+
+	informer := factory.Core().V1().ConfigMaps().Informer()
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    s.onConfigMapChange,
+		UpdateFunc: func(_, obj interface{}) { s.onConfigMapChange(obj) },
+		DeleteFunc: func(interface{}) {
+			klog.Infof("Resource policy ConfigMap %s/%s deleted, restoring the default factor", namespace, name)
+			s.SetPolicy(nil)
+		},
+	})
+	utilruntime.Must(err)
+
+	factory.Start(ctx.Done())
+	factory.WaitForCacheSync(ctx.Done())
+}
+
+func (s *ResourcePolicyScaler) onConfigMapChange(obj interface{}) {
+	cm, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		return
+	}
+
+	policy, err := ParseResourcePolicy([]byte(cm.Data[ResourcePolicyConfigMapKey]))
+	if err != nil {
+		klog.Errorf("Failed to load the resource policy from ConfigMap %q, keeping the previous one: %v", klog.KObj(cm), err)
+		return
+	}
+	klog.Infof("Resource policy loaded from ConfigMap %q", klog.KObj(cm))
+	s.SetPolicy(policy)
+}
+
+// runScheduler notifies all the clusters whenever the set of active time windows changes.
+func (s *ResourcePolicyScaler) runScheduler(ctx context.Context) {
+	for {
+		s.mutex.RLock()
+		policy := s.policy
+		s.mutex.RUnlock()
+
+		now := s.clock.Now()
+		active := policy.activeWindows(now)
+
+		var timer clock.Timer
+		var expired <-chan time.Time
+		if next := policy.nextBoundary(now); !next.IsZero() {
+			timer = s.clock.NewTimer(next.Sub(now))
+			expired = timer.C()
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-s.changed:
+			// The policy has been replaced, and the clusters already notified.
+		case <-expired:
+			if current := policy.activeWindows(s.clock.Now()); current != active {
+				klog.V(4).Infof("Active resource policy windows changed from %q to %q", active, current)
+				s.notify()
+			}
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (s *ResourcePolicyScaler) notify() {
+	s.mutex.RLock()
+	notifier := s.notifier
+	s.mutex.RUnlock()
+
+	if notifier != nil {
+		notifier.NotifyChange(AllClusterIDs)
+	}
+}
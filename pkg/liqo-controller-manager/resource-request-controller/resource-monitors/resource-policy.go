@@ -0,0 +1,320 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcemonitors
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ResourcePolicyConfigMapKey is the key of the ConfigMap data containing the resource policy.
+	ResourcePolicyConfigMapKey = "policy.yaml"
+
+	windowTimeLayout = "15:04"
+	day              = 24 * time.Hour
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ResourcePolicy defines how the resources offered to remote clusters vary depending on the time of the day,
+// on the consumer cluster and on the priority tier it belongs to.
+type ResourcePolicy struct {
+	// DefaultFactor is the factor applied to the resources when no time window is active.
+	// If unset, the default factor of the ResourcePolicyScaler is used.
+	DefaultFactor *float32 `json:"defaultFactor,omitempty"`
+	// Timezone is the IANA time zone the time windows refer to (defaults to UTC).
+	Timezone string `json:"timezone,omitempty"`
+	// Windows are the time windows during which specific factors apply.
+	// In case more windows are active at the same time, the first one matching the consumer cluster is used.
+	Windows []TimeWindow `json:"windows,omitempty"`
+	// Tiers are the priority tiers consumer clusters may belong to, indexed by name.
+	Tiers map[string]PriorityTier `json:"tiers,omitempty"`
+	// DefaultTier is the tier of the consumer clusters not explicitly configured.
+	DefaultTier string `json:"defaultTier,omitempty"`
+	// Clusters are the policies specific to each consumer cluster, indexed by cluster ID.
+	Clusters map[string]ClusterPolicy `json:"clusters,omitempty"`
+
+	location *time.Location
+}
+
+// TimeWindow is a recurring time interval during which specific factors are applied to the offered resources.
+type TimeWindow struct {
+	// Name is a human-readable identifier of the window.
+	Name string `json:"name"`
+	// Start is the time of the day the window starts at, in the HH:MM format.
+	Start string `json:"start"`
+	// End is the time of the day the window ends at, in the HH:MM format.
+	// If End is before Start, the window spans across midnight.
+	End string `json:"end"`
+	// Days are the days of the week (e.g., Mon, Tue) the window starts on. If empty, the window applies every day.
+	Days []string `json:"days,omitempty"`
+	// MinPriority is the minimum priority of the tier consumer clusters shall belong to for the window to apply.
+	MinPriority int32 `json:"minPriority,omitempty"`
+	// ClusterIDs, if set, restricts the window to the given consumer clusters.
+	ClusterIDs []string `json:"clusterIDs,omitempty"`
+	// Factor is the factor applied to all the resources while the window is active.
+	Factor float32 `json:"factor"`
+	// ResourceFactors overrides the factor for specific resources (e.g., cpu).
+	ResourceFactors map[corev1.ResourceName]float32 `json:"resourceFactors,omitempty"`
+
+	start    time.Duration
+	duration time.Duration
+	days     map[time.Weekday]struct{}
+}
+
+// PriorityTier is a class of consumer clusters sharing the same priority.
+type PriorityTier struct {
+	// Priority is the priority of the tier, compared against the MinPriority of the time windows.
+	Priority int32 `json:"priority,omitempty"`
+	// Factor is multiplied to the factor of the active window (or to the default one). If unset, it defaults to 1.
+	Factor *float32 `json:"factor,omitempty"`
+}
+
+// ClusterPolicy is the policy specific to a given consumer cluster.
+type ClusterPolicy struct {
+	// Tier is the priority tier the cluster belongs to.
+	Tier string `json:"tier,omitempty"`
+	// Factor, if set, overrides the factor of the tier.
+	Factor *float32 `json:"factor,omitempty"`
+}
+
+// ParseResourcePolicy parses and validates a ResourcePolicy in YAML format.
+func ParseResourcePolicy(data []byte) (*ResourcePolicy, error) {
+	var policy ResourcePolicy
+	if err := yaml.UnmarshalStrict(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse resource policy: %w", err)
+	}
+	if err := policy.complete(); err != nil {
+		return nil, fmt.Errorf("invalid resource policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// complete validates the policy and initializes the internal fields.
+func (p *ResourcePolicy) complete() error {
+	var err error
+	if p.DefaultFactor != nil && *p.DefaultFactor < 0 {
+		return fmt.Errorf("the default factor must not be negative")
+	}
+	if p.location, err = time.LoadLocation(p.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q: %w", p.Timezone, err)
+	}
+
+	for name, tier := range p.Tiers {
+		if tier.Factor != nil && *tier.Factor < 0 {
+			return fmt.Errorf("the factor of tier %q must not be negative", name)
+		}
+	}
+	if _, found := p.Tiers[p.DefaultTier]; p.DefaultTier != "" && !found {
+		return fmt.Errorf("the default tier %q is not defined", p.DefaultTier)
+	}
+	for clusterID, cluster := range p.Clusters {
+		if _, found := p.Tiers[cluster.Tier]; cluster.Tier != "" && !found {
+			return fmt.Errorf("the tier %q of cluster %q is not defined", cluster.Tier, clusterID)
+		}
+		if cluster.Factor != nil && *cluster.Factor < 0 {
+			return fmt.Errorf("the factor of cluster %q must not be negative", clusterID)
+		}
+	}
+
+	for i := range p.Windows {
+		if err := p.Windows[i].complete(); err != nil {
+			return fmt.Errorf("window %q: %w", p.Windows[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// complete validates the window and initializes the internal fields.
+func (w *TimeWindow) complete() error {
+	start, err := time.Parse(windowTimeLayout, w.Start)
+	if err != nil {
+		return fmt.Errorf("invalid start time %q, expected format HH:MM", w.Start)
+	}
+	end, err := time.Parse(windowTimeLayout, w.End)
+	if err != nil {
+		return fmt.Errorf("invalid end time %q, expected format HH:MM", w.End)
+	}
+
+	w.start = time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute
+	w.duration = end.Sub(start)
+	if w.duration <= 0 {
+		// The window spans across midnight (or the whole day, if start and end coincide).
+		w.duration += day
+	}
+
+	w.days = make(map[time.Weekday]struct{}, len(w.Days))
+	for _, d := range w.Days {
+		weekday, found := weekdays[strings.ToLower(d)]
+		if !found {
+			return fmt.Errorf("invalid day %q, expected one of Mon, Tue, Wed, Thu, Fri, Sat, Sun", d)
+		}
+		w.days[weekday] = struct{}{}
+	}
+
+	if w.Factor < 0 {
+		return fmt.Errorf("the factor must not be negative")
+	}
+	for resourceName, factor := range w.ResourceFactors {
+		if factor < 0 {
+			return fmt.Errorf("the factor of resource %q must not be negative", resourceName)
+		}
+	}
+	return nil
+}
+
+// startsOn returns whether the window starts on the given day of the week.
+func (w *TimeWindow) startsOn(weekday time.Weekday) bool {
+	if len(w.days) == 0 {
+		return true
+	}
+	_, found := w.days[weekday]
+	return found
+}
+
+// occurrences returns the start times of the occurrences of the window which started in the day before
+// the given time, or that start in the following week.
+func (w *TimeWindow) occurrences(t time.Time) []time.Time {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	occurrences := make([]time.Time, 0, 9)
+	for offset := -1; offset <= 7; offset++ {
+		if start := midnight.AddDate(0, 0, offset).Add(w.start); w.startsOn(start.Weekday()) {
+			occurrences = append(occurrences, start)
+		}
+	}
+	return occurrences
+}
+
+// isActive returns whether the window is active at the given time.
+func (w *TimeWindow) isActive(t time.Time) bool {
+	for _, start := range w.occurrences(t) {
+		if !t.Before(start) && t.Before(start.Add(w.duration)) {
+			return true
+		}
+	}
+	return false
+}
+
+// appliesTo returns whether the window applies to the given consumer cluster, given the priority of its tier.
+func (w *TimeWindow) appliesTo(clusterID string, priority int32) bool {
+	if priority < w.MinPriority {
+		return false
+	}
+	if len(w.ClusterIDs) == 0 {
+		return true
+	}
+	for _, id := range w.ClusterIDs {
+		if id == clusterID {
+			return true
+		}
+	}
+	return false
+}
+
+// factor returns the factor applied by the window to the given resource.
+func (w *TimeWindow) factor(resourceName corev1.ResourceName) float32 {
+	if factor, found := w.ResourceFactors[resourceName]; found {
+		return factor
+	}
+	return w.Factor
+}
+
+// tierOf returns the priority and the factor associated with the given consumer cluster.
+func (p *ResourcePolicy) tierOf(clusterID string) (priority int32, factor float32) {
+	cluster := p.Clusters[clusterID]
+	tierName := cluster.Tier
+	if tierName == "" {
+		tierName = p.DefaultTier
+	}
+
+	factor = 1
+	if tier, found := p.Tiers[tierName]; found {
+		priority = tier.Priority
+		if tier.Factor != nil {
+			factor = *tier.Factor
+		}
+	}
+	if cluster.Factor != nil {
+		factor = *cluster.Factor
+	}
+	return priority, factor
+}
+
+// factors returns a function computing the factor to be applied to each resource offered to the given
+// consumer cluster, at the given time. The defaultFactor is used if not overridden by the policy.
+func (p *ResourcePolicy) factors(t time.Time, clusterID string, defaultFactor float32) func(corev1.ResourceName) float32 {
+	if p == nil {
+		return func(corev1.ResourceName) float32 { return defaultFactor }
+	}
+	if p.DefaultFactor != nil {
+		defaultFactor = *p.DefaultFactor
+	}
+
+	t = t.In(p.location)
+	priority, tierFactor := p.tierOf(clusterID)
+	for i := range p.Windows {
+		window := &p.Windows[i]
+		if window.appliesTo(clusterID, priority) && window.isActive(t) {
+			return func(resourceName corev1.ResourceName) float32 { return window.factor(resourceName) * tierFactor }
+		}
+	}
+	return func(corev1.ResourceName) float32 { return defaultFactor * tierFactor }
+}
+
+// activeWindows returns a string identifying the set of windows active at the given time.
+func (p *ResourcePolicy) activeWindows(t time.Time) string {
+	if p == nil {
+		return ""
+	}
+	t = t.In(p.location)
+	var active []string
+	for i := range p.Windows {
+		if p.Windows[i].isActive(t) {
+			active = append(active, fmt.Sprintf("%d/%s", i, p.Windows[i].Name))
+		}
+	}
+	return strings.Join(active, ",")
+}
+
+// nextBoundary returns the first time, after the given one, a window starts or ends at.
+// It returns the zero time in case the policy does not define any window.
+func (p *ResourcePolicy) nextBoundary(t time.Time) time.Time {
+	if p == nil {
+		return time.Time{}
+	}
+	t = t.In(p.location)
+	var boundaries []time.Time
+	for i := range p.Windows {
+		for _, start := range p.Windows[i].occurrences(t) {
+			boundaries = append(boundaries, start, start.Add(p.Windows[i].duration))
+		}
+	}
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i].Before(boundaries[j]) })
+	for _, boundary := range boundaries {
+		if boundary.After(t) {
+			return boundary
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,209 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcemonitors
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+var _ ResourceUpdateNotifier = &FakeNotifier{}
+
+type FakeNotifier struct {
+	mutex      sync.Mutex
+	clusterIDs []string
+}
+
+func (n *FakeNotifier) NotifyChange(clusterID string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.clusterIDs = append(n.clusterIDs, clusterID)
+}
+
+func (n *FakeNotifier) Notifications() int {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return len(n.clusterIDs)
+}
+
+const testPolicy = `
+defaultFactor: 0.5
+windows:
+- name: night
+  start: "22:00"
+  end: "06:00"
+  factor: 0.9
+  resourceFactors:
+    memory: 0.7
+- name: weekend
+  start: "08:00"
+  end: "20:00"
+  days: [Sat, Sun]
+  minPriority: 10
+  factor: 0.8
+tiers:
+  gold:
+    priority: 10
+  bronze:
+    factor: 0.5
+defaultTier: bronze
+clusters:
+  gold-cluster:
+    tier: gold
+  custom-cluster:
+    tier: gold
+    factor: 0.25
+`
+
+var _ = Describe("ResourcePolicy", func() {
+	// 2024-01-01 is a Monday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, time.January, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	DescribeTable("Parsing a resource policy",
+		func(data string, shouldFail bool) {
+			_, err := ParseResourcePolicy([]byte(data))
+			if shouldFail {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).ToNot(HaveOccurred())
+			}
+		},
+		Entry("valid policy", testPolicy, false),
+		Entry("empty policy", "", false),
+		Entry("valid timezone", "timezone: Europe/Rome", false),
+		Entry("unknown field", "unknown: true", true),
+		Entry("unknown timezone", "timezone: Invalid/Zone", true),
+		Entry("invalid start time", `windows: [{name: w, start: "25:00", end: "06:00", factor: 1}]`, true),
+		Entry("invalid end time", `windows: [{name: w, start: "22:00", end: "6", factor: 1}]`, true),
+		Entry("invalid day", `windows: [{name: w, start: "22:00", end: "06:00", days: [Funday], factor: 1}]`, true),
+		Entry("negative factor", `windows: [{name: w, start: "22:00", end: "06:00", factor: -1}]`, true),
+		Entry("undefined default tier", "defaultTier: gold", true),
+		Entry("undefined cluster tier", "clusters: {foo: {tier: gold}}", true),
+	)
+
+	Describe("Computing the factors", func() {
+		var policy *ResourcePolicy
+
+		BeforeEach(func() {
+			var err error
+			policy, err = ParseResourcePolicy([]byte(testPolicy))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		DescribeTable("for a given cluster and time",
+			func(t time.Time, clusterID string, resourceName corev1.ResourceName, expected float32) {
+				Expect(policy.factors(t, clusterID, 1)(resourceName)).To(BeNumerically("~", expected, 1e-6))
+			},
+			Entry("default factor and tier during the day", at(1, 12, 0), "foo", corev1.ResourceCPU, float32(0.25)),
+			Entry("night window before midnight", at(1, 23, 0), "gold-cluster", corev1.ResourceCPU, float32(0.9)),
+			Entry("night window after midnight", at(2, 5, 59), "gold-cluster", corev1.ResourceCPU, float32(0.9)),
+			Entry("night window end", at(2, 6, 0), "gold-cluster", corev1.ResourceCPU, float32(0.5)),
+			Entry("night window resource factor", at(1, 23, 0), "gold-cluster", corev1.ResourceMemory, float32(0.7)),
+			Entry("night window tier factor", at(1, 23, 0), "foo", corev1.ResourceCPU, float32(0.45)),
+			Entry("night window cluster factor", at(1, 23, 0), "custom-cluster", corev1.ResourceCPU, float32(0.225)),
+			Entry("weekend window on saturday", at(6, 12, 0), "gold-cluster", corev1.ResourceCPU, float32(0.8)),
+			Entry("weekend window on weekdays", at(5, 12, 0), "gold-cluster", corev1.ResourceCPU, float32(0.5)),
+			Entry("weekend window below min priority", at(6, 12, 0), "foo", corev1.ResourceCPU, float32(0.25)),
+		)
+
+		It("should use the given default factor if the policy is not set", func() {
+			var empty *ResourcePolicy
+			Expect(empty.factors(at(1, 23, 0), "foo", 0.3)(corev1.ResourceCPU)).To(BeNumerically("~", 0.3, 1e-6))
+		})
+
+		It("should honor the configured timezone", func() {
+			var err error
+			policy, err = ParseResourcePolicy([]byte(`
+timezone: Asia/Tokyo
+windows: [{name: night, start: "22:00", end: "06:00", factor: 1}]`))
+			Expect(err).ToNot(HaveOccurred())
+			// 13:00 UTC is 22:00 in Tokyo.
+			Expect(policy.factors(at(1, 13, 0), "foo", 0.5)(corev1.ResourceCPU)).To(BeNumerically("~", 1, 1e-6))
+			Expect(policy.factors(at(1, 23, 0), "foo", 0.5)(corev1.ResourceCPU)).To(BeNumerically("~", 0.5, 1e-6))
+		})
+
+		It("should compute the next window boundary", func() {
+			Expect(policy.nextBoundary(at(1, 12, 0))).To(Equal(at(1, 22, 0)))
+			Expect(policy.nextBoundary(at(1, 22, 0))).To(Equal(at(2, 6, 0)))
+			Expect(policy.nextBoundary(at(5, 23, 0))).To(Equal(at(6, 6, 0)))
+			Expect(policy.nextBoundary(at(6, 6, 0))).To(Equal(at(6, 8, 0)))
+		})
+	})
+
+	Describe("The ResourcePolicyScaler", func() {
+		var (
+			ctx      context.Context
+			cancel   context.CancelFunc
+			clk      *clocktesting.FakeClock
+			notifier *FakeNotifier
+			scaler   *ResourcePolicyScaler
+		)
+
+		BeforeEach(func() {
+			ctx, cancel = context.WithCancel(context.Background())
+			clk = clocktesting.NewFakeClock(at(1, 12, 0))
+			notifier = &FakeNotifier{}
+			provider := FakeResourceReader{corev1.ResourceList{
+				"cpu":    resource.MustParse("1000m"),
+				"memory": resource.MustParse("8G"),
+			}}
+			scaler = newResourcePolicyScaler(provider, .5, clk)
+			scaler.Register(ctx, notifier)
+		})
+
+		AfterEach(func() { cancel() })
+
+		It("should scale the resources according to the policy", func() {
+			scaled, err := scaler.ReadResources(ctx, "gold-cluster")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(scaled[0].Resources["cpu"].Equal(resource.MustParse("500m"))).To(BeTrue())
+
+			policy, err := ParseResourcePolicy([]byte(testPolicy))
+			Expect(err).ToNot(HaveOccurred())
+			scaler.SetPolicy(policy)
+			Expect(notifier.Notifications()).To(Equal(1))
+
+			clk.SetTime(at(1, 23, 0))
+			scaled, err = scaler.ReadResources(ctx, "gold-cluster")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(scaled[0].Resources["cpu"].Equal(resource.MustParse("900m"))).To(BeTrue())
+			Expect(scaled[0].Resources["memory"].Equal(resource.MustParse("5600M"))).To(BeTrue())
+		})
+
+		It("should notify the clusters when a window boundary is crossed", func() {
+			policy, err := ParseResourcePolicy([]byte(testPolicy))
+			Expect(err).ToNot(HaveOccurred())
+			scaler.SetPolicy(policy)
+			Expect(notifier.Notifications()).To(Equal(1))
+
+			// Wait for the scheduler to set the timer up to the next boundary (22:00).
+			Eventually(func() bool { return clk.HasWaiters() }).Should(BeTrue())
+			Consistently(notifier.Notifications, 100*time.Millisecond).Should(Equal(1))
+
+			clk.Step(10 * time.Hour)
+			Eventually(notifier.Notifications).Should(Equal(2))
+			Expect(notifier.clusterIDs).To(HaveEach(AllClusterIDs))
+		})
+	})
+})
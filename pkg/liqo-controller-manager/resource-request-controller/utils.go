@@ -120,7 +120,7 @@ func (r *ResourceRequestReconciler) invalidateResourceOffers(ctx context.Context
 	aggregatedStatus := sharingv1alpha1.VirtualKubeletStatusUnknown
 	for i := range resourceOfferList.Items {
 		offer := &resourceOfferList.Items[i]
-		err := r.invalidateResourceOffer(ctx, offer)
+		err := invalidateResourceOffer(ctx, r.Client, r.HomeCluster, offer)
 		if err != nil {
 			return err
 		}
@@ -144,27 +144,30 @@ func aggregateStatus(oldStatus, newStatus sharingv1alpha1.VirtualKubeletStatus)
 	return oldStatus
 }
 
-func (r *ResourceRequestReconciler) invalidateResourceOffer(ctx context.Context, offer *sharingv1alpha1.ResourceOffer) error {
+// invalidateResourceOffer requests the graceful withdrawal of the given ResourceOffer,
+// or deletes it in case no virtual kubelet has been created.
+func invalidateResourceOffer(ctx context.Context, cl client.Client, homeCluster discoveryv1alpha1.ClusterIdentity,
+	offer *sharingv1alpha1.ResourceOffer) error {
 	switch offer.Status.VirtualKubeletStatus {
 	case sharingv1alpha1.VirtualKubeletStatusDeleting, sharingv1alpha1.VirtualKubeletStatusCreated:
 		if offer.Spec.WithdrawalTimestamp.IsZero() {
 			now := metav1.Now()
 			offer.Spec.WithdrawalTimestamp = &now
 		}
-		err := client.IgnoreNotFound(r.Client.Update(ctx, offer))
+		err := client.IgnoreNotFound(cl.Update(ctx, offer))
 		if err != nil {
 			return err
 		}
-		klog.Infof("%s -> Offer: %s/%s", r.HomeCluster.ClusterName, offer.Namespace, offer.Name)
+		klog.Infof("%s -> Offer: %s/%s", homeCluster.ClusterName, offer.Namespace, offer.Name)
 		return nil
 	case sharingv1alpha1.VirtualKubeletStatusNone, sharingv1alpha1.VirtualKubeletStatusUnknown:
 		// The unknown status might occur in case we never succeeded in reflecting the resource offer
 		// to the remote cluster, e.g., due to an authentication issue.
-		err := client.IgnoreNotFound(r.Client.Delete(ctx, offer))
+		err := client.IgnoreNotFound(cl.Delete(ctx, offer))
 		if err != nil {
 			return err
 		}
-		klog.Infof("%s -> Deleted Offer: %s/%s", r.HomeCluster.ClusterName, offer.Namespace, offer.Name)
+		klog.Infof("%s -> Deleted Offer: %s/%s", homeCluster.ClusterName, offer.Namespace, offer.Name)
 		return nil
 	default:
 		return fmt.Errorf("unknown VirtualKubeletStatus %v", offer.Status.VirtualKubeletStatus)
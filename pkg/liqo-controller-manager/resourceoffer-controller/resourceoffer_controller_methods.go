@@ -123,6 +123,7 @@ func (r *ResourceOfferReconciler) getVirtualNodeMutator(fc *discoveryv1alpha1.Fo
 		virtualNode.Spec.Images = resourceOffer.Spec.Images
 		virtualNode.Spec.ResourceQuota = resourceOffer.Spec.ResourceQuota
 		virtualNode.Spec.Labels = resourceOffer.Spec.Labels
+		virtualNode.Spec.Taints = resourceOffer.Spec.Taints
 		virtualNode.Spec.StorageClasses = resourceOffer.Spec.StorageClasses
 		return controllerutil.SetControllerReference(resourceOffer, virtualNode, r.Scheme)
 	}
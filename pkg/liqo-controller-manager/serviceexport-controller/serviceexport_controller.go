@@ -0,0 +1,401 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serviceexportctrl
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	mcsv1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	liqoconsts "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqonet/ipam"
+	"github.com/liqotech/liqo/pkg/utils"
+	foreigncluster "github.com/liqotech/liqo/pkg/utils/foreignCluster"
+)
+
+const (
+	// ReasonServiceNotFound is the reason of the Valid condition when the exported service does not exist.
+	ReasonServiceNotFound = "ServiceNotFound"
+	// ReasonServiceTypeInvalid is the reason of the Valid condition when the exported service is of type ExternalName.
+	ReasonServiceTypeInvalid = "ServiceTypeInvalid"
+	// ReasonServiceExported is the reason of the Valid condition when the service has been correctly exported.
+	ReasonServiceExported = "ServiceExported"
+)
+
+// ServiceExportReconciler reconciles ServiceExport objects, propagating the information
+// about the exported service towards the peered clusters through ExportedService resources.
+type ServiceExportReconciler struct {
+	client.Client
+
+	// IpamClient is used to translate the endpoint addresses for each remote cluster.
+	// If nil, addresses are exported as they are.
+	IpamClient ipam.IpamClient
+}
+
+// +kubebuilder:rbac:groups=multicluster.x-k8s.io,resources=serviceexports,verbs=get;list;watch
+// +kubebuilder:rbac:groups=multicluster.x-k8s.io,resources=serviceexports/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
+// +kubebuilder:rbac:groups=discovery.liqo.io,resources=exportedservices,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=discovery.liqo.io,resources=foreignclusters,verbs=get;list;watch
+
+// Reconcile ServiceExport objects.
+func (r *ServiceExportReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	klog.V(4).Infof("reconcile serviceexport %q", req.NamespacedName)
+
+	var export mcsv1alpha1.ServiceExport
+	if err := r.Get(ctx, req.NamespacedName, &export); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.V(4).Infof("serviceexport %q not found, ensuring the corresponding exported services are absent", req.NamespacedName)
+			return ctrl.Result{}, r.enforceExportedServices(ctx, req.NamespacedName, nil)
+		}
+		klog.Errorf("an error occurred while getting serviceexport %q: %v", req.NamespacedName, err)
+		return ctrl.Result{}, err
+	}
+
+	if !export.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.enforceExportedServices(ctx, req.NamespacedName, nil)
+	}
+
+	var svc corev1.Service
+	if err := r.Get(ctx, req.NamespacedName, &svc); err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.Errorf("an error occurred while getting service %q: %v", req.NamespacedName, err)
+			return ctrl.Result{}, err
+		}
+
+		klog.Warningf("service %q selected by serviceexport %q not found", req.NamespacedName, req.NamespacedName)
+		return ctrl.Result{}, r.invalidate(ctx, &export, ReasonServiceNotFound, "the exported service does not exist")
+	}
+
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		klog.Warningf("service %q selected by serviceexport %q is of type %s", req.NamespacedName, req.NamespacedName, svc.Spec.Type)
+		return ctrl.Result{}, r.invalidate(ctx, &export, ReasonServiceTypeInvalid,
+			fmt.Sprintf("services of type %s cannot be exported", svc.Spec.Type))
+	}
+
+	slices, err := r.localEndpointSlices(ctx, &svc)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	spec := forgeExportedServiceSpec(&svc)
+	if err := r.enforceExportedServices(ctx, req.NamespacedName, func(clusterID string) (*discoveryv1alpha1.ExportedServiceSpec, error) {
+		translated, err := r.translateEndpointSlices(ctx, clusterID, slices)
+		if err != nil {
+			return nil, err
+		}
+
+		output := spec.DeepCopy()
+		output.EndpointSlices = translated
+		return output, nil
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if setServiceExportCondition(&export, mcsv1alpha1.ServiceExportValid, corev1.ConditionTrue,
+		ReasonServiceExported, "the service has been exported to the peered clusters") {
+		if err := r.Status().Update(ctx, &export); err != nil {
+			klog.Errorf("failed to update the status of serviceexport %q: %v", req.NamespacedName, err)
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// invalidate marks the ServiceExport as not valid, and ensures the corresponding ExportedServices are absent.
+func (r *ServiceExportReconciler) invalidate(ctx context.Context, export *mcsv1alpha1.ServiceExport, reason, message string) error {
+	if err := r.enforceExportedServices(ctx, client.ObjectKeyFromObject(export), nil); err != nil {
+		return err
+	}
+
+	if setServiceExportCondition(export, mcsv1alpha1.ServiceExportValid, corev1.ConditionFalse, reason, message) {
+		if err := r.Status().Update(ctx, export); err != nil {
+			klog.Errorf("failed to update the status of serviceexport %q: %v", klog.KObj(export), err)
+			return err
+		}
+	}
+	return nil
+}
+
+// localEndpointSlices returns the IPv4 EndpointSlices associated with the given service,
+// excluding those reflected from remote clusters.
+func (r *ServiceExportReconciler) localEndpointSlices(ctx context.Context, svc *corev1.Service) ([]discoveryv1.EndpointSlice, error) {
+	var slices discoveryv1.EndpointSliceList
+	if err := r.List(ctx, &slices, client.InNamespace(svc.GetNamespace()),
+		client.MatchingLabels{discoveryv1.LabelServiceName: svc.GetName()}); err != nil {
+		klog.Errorf("failed to list the endpointslices of service %q: %v", klog.KObj(svc), err)
+		return nil, err
+	}
+
+	var output []discoveryv1.EndpointSlice
+	for i := range slices.Items {
+		slice := &slices.Items[i]
+		if slice.AddressType != discoveryv1.AddressTypeIPv4 || slice.Labels[liqoconsts.ManagedByLabelKey] != "" {
+			continue
+		}
+		output = append(output, *slice)
+	}
+	return output, nil
+}
+
+// translateEndpointSlices returns the endpoint slices to be exported towards the given cluster.
+// Only the endpoints backed by local pods are exported, with their addresses translated to be reachable from the remote cluster.
+func (r *ServiceExportReconciler) translateEndpointSlices(ctx context.Context, clusterID string,
+	slices []discoveryv1.EndpointSlice) ([]discoveryv1alpha1.ExportedEndpointSlice, error) {
+	var output []discoveryv1alpha1.ExportedEndpointSlice
+
+	for i := range slices {
+		var endpoints []discoveryv1.Endpoint
+		for j := range slices[i].Endpoints {
+			endpoint := &slices[i].Endpoints[j]
+
+			var addresses []string
+			for _, address := range endpoint.Addresses {
+				translated, err := r.translateAddress(ctx, clusterID, address)
+				if err != nil {
+					return nil, err
+				}
+				if translated != "" {
+					addresses = append(addresses, translated)
+				}
+			}
+
+			if len(addresses) > 0 {
+				endpoints = append(endpoints, exportedEndpoint(endpoint, addresses))
+			}
+		}
+
+		if len(endpoints) > 0 {
+			output = append(output, discoveryv1alpha1.ExportedEndpointSlice{Endpoints: endpoints, Ports: slices[i].Ports})
+		}
+	}
+
+	return output, nil
+}
+
+// translateAddress translates the given address to be reachable from the given cluster.
+// An empty string is returned in case the address does not belong to the local pod CIDR,
+// since the corresponding endpoint mappings would not be released when the export is removed.
+func (r *ServiceExportReconciler) translateAddress(ctx context.Context, clusterID, address string) (string, error) {
+	if r.IpamClient == nil {
+		return address, nil
+	}
+
+	belongs, err := r.IpamClient.BelongsToPodCIDR(ctx, &ipam.BelongsRequest{Ip: address})
+	if err != nil {
+		klog.Errorf("failed to check whether address %q belongs to the pod CIDR: %v", address, err)
+		return "", err
+	}
+	if !belongs.GetBelongs() {
+		klog.V(4).Infof("skipping address %q, as not belonging to the pod CIDR", address)
+		return "", nil
+	}
+
+	response, err := r.IpamClient.MapEndpointIP(ctx, &ipam.MapRequest{ClusterID: clusterID, Ip: address})
+	if err != nil {
+		klog.Errorf("failed to translate address %q for remote cluster %q: %v", address, clusterID, err)
+		return "", err
+	}
+	return response.GetIp(), nil
+}
+
+// enforceExportedServices ensures that an ExportedService exists for each peered cluster with established networking,
+// with the specification returned by the given function, and removes the stale ones. A nil function removes them all.
+func (r *ServiceExportReconciler) enforceExportedServices(ctx context.Context, service types.NamespacedName,
+	forgeSpec func(clusterID string) (*discoveryv1alpha1.ExportedServiceSpec, error)) error {
+	desired := map[string]string{}
+	if forgeSpec != nil {
+		var fcs discoveryv1alpha1.ForeignClusterList
+		if err := r.List(ctx, &fcs); err != nil {
+			klog.Errorf("failed to list foreignclusters: %v", err)
+			return err
+		}
+
+		for i := range fcs.Items {
+			fc := &fcs.Items[i]
+			if !foreigncluster.IsNetworkingEstablishedOrExternal(fc) || fc.Status.TenantNamespace.Local == "" {
+				continue
+			}
+			desired[fc.Spec.ClusterIdentity.ClusterID] = fc.Status.TenantNamespace.Local
+		}
+	}
+
+	// Only the local ExportedServices are considered, excluding those replicated by the peered clusters.
+	var existing discoveryv1alpha1.ExportedServiceList
+	if err := r.List(ctx, &existing, client.MatchingLabels(exportedServiceLabels(service)),
+		client.MatchingLabels{liqoconsts.ReplicationRequestedLabel: liqoconsts.ReplicationRequestedLabelValue}); err != nil {
+		klog.Errorf("failed to list the exported services for %q: %v", service, err)
+		return err
+	}
+
+	for i := range existing.Items {
+		exported := &existing.Items[i]
+		if namespace, found := desired[exported.Labels[liqoconsts.ReplicationDestinationLabel]]; found && namespace == exported.GetNamespace() {
+			continue
+		}
+
+		if err := r.Delete(ctx, exported); client.IgnoreNotFound(err) != nil {
+			klog.Errorf("failed to delete exportedservice %q: %v", klog.KObj(exported), err)
+			return err
+		}
+		klog.Infof("exportedservice %q successfully deleted", klog.KObj(exported))
+	}
+
+	for clusterID, namespace := range desired {
+		spec, err := forgeSpec(clusterID)
+		if err != nil {
+			return err
+		}
+
+		exported := discoveryv1alpha1.ExportedService{ObjectMeta: metav1.ObjectMeta{
+			Name: ExportedServiceName(service.Namespace, service.Name), Namespace: namespace}}
+		result, err := controllerutil.CreateOrUpdate(ctx, r.Client, &exported, func() error {
+			exported.Labels = labels.Merge(exported.Labels, exportedServiceLabels(service))
+			exported.Labels[liqoconsts.ReplicationRequestedLabel] = liqoconsts.ReplicationRequestedLabelValue
+			exported.Labels[liqoconsts.ReplicationDestinationLabel] = clusterID
+			exported.Spec = *spec
+			return nil
+		})
+		if err != nil {
+			klog.Errorf("failed to enforce exportedservice %q: %v", klog.KObj(&exported), err)
+			return err
+		}
+		klog.V(utils.FromResult(result)).Infof("exportedservice %q successfully enforced (with %v operation)", klog.KObj(&exported), result)
+	}
+
+	return nil
+}
+
+// forgeExportedServiceSpec forges the cluster-independent part of the ExportedService spec, given the exported service.
+func forgeExportedServiceSpec(svc *corev1.Service) *discoveryv1alpha1.ExportedServiceSpec {
+	spec := &discoveryv1alpha1.ExportedServiceSpec{
+		Namespace:             svc.GetNamespace(),
+		Name:                  svc.GetName(),
+		Type:                  mcsv1alpha1.ClusterSetIP,
+		SessionAffinity:       svc.Spec.SessionAffinity,
+		SessionAffinityConfig: svc.Spec.SessionAffinityConfig,
+	}
+
+	if svc.Spec.ClusterIP == corev1.ClusterIPNone {
+		spec.Type = mcsv1alpha1.Headless
+	}
+
+	for i := range svc.Spec.Ports {
+		port := &svc.Spec.Ports[i]
+		spec.Ports = append(spec.Ports, mcsv1alpha1.ServicePort{
+			Name: port.Name, Protocol: port.Protocol, AppProtocol: port.AppProtocol, Port: port.Port,
+		})
+	}
+
+	return spec
+}
+
+// exportedServiceLabels returns the labels identifying the ExportedServices corresponding to the given service.
+func exportedServiceLabels(service types.NamespacedName) map[string]string {
+	return map[string]string{
+		liqoconsts.ExportedServiceNamespaceLabelKey: service.Namespace,
+		liqoconsts.ExportedServiceNameLabelKey:      service.Name,
+	}
+}
+
+// exportedServiceToServiceExport maps the local ExportedServices to the corresponding ServiceExport.
+func exportedServiceToServiceExport(_ context.Context, obj client.Object) []reconcile.Request {
+	namespace, nsFound := obj.GetLabels()[liqoconsts.ExportedServiceNamespaceLabelKey]
+	name, nameFound := obj.GetLabels()[liqoconsts.ExportedServiceNameLabelKey]
+	if !nsFound || !nameFound {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}}
+}
+
+// endpointSliceToServiceExport maps the EndpointSlices to the ServiceExport with the same name of the corresponding service.
+func endpointSliceToServiceExport(_ context.Context, obj client.Object) []reconcile.Request {
+	name, found := obj.GetLabels()[discoveryv1.LabelServiceName]
+	if !found {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: name}}}
+}
+
+// foreignClusterToServiceExports maps the ForeignClusters to all the ServiceExports.
+func (r *ServiceExportReconciler) foreignClusterToServiceExports(ctx context.Context, _ client.Object) []reconcile.Request {
+	var exports mcsv1alpha1.ServiceExportList
+	if err := r.List(ctx, &exports); err != nil {
+		klog.Errorf("failed to list serviceexports: %v", err)
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(exports.Items))
+	for i := range exports.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&exports.Items[i])})
+	}
+	return requests
+}
+
+// SetupWithManager monitors ServiceExports and the corresponding services, endpointslices and exportedservices.
+func (r *ServiceExportReconciler) SetupWithManager(mgr ctrl.Manager, workers int) error {
+	// Reconcile all ServiceExports when the networking with a remote cluster is established or torn down.
+	fcPredicates := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool { return true },
+		DeleteFunc: func(e event.DeleteEvent) bool { return true },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldFc, oldOk := e.ObjectOld.(*discoveryv1alpha1.ForeignCluster)
+			newFc, newOk := e.ObjectNew.(*discoveryv1alpha1.ForeignCluster)
+			return oldOk && newOk && (foreigncluster.IsNetworkingEstablishedOrExternal(oldFc) != foreigncluster.IsNetworkingEstablishedOrExternal(newFc) ||
+				oldFc.Status.TenantNamespace.Local != newFc.Status.TenantNamespace.Local)
+		},
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	}
+
+	// Local ExportedServices are those not created through the replication process.
+	localExportedServices, err := predicate.LabelSelectorPredicate(metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: liqoconsts.ReplicationStatusLabel, Operator: metav1.LabelSelectorOpDoesNotExist}},
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("serviceexport").
+		For(&mcsv1alpha1.ServiceExport{}).
+		Watches(&corev1.Service{}, &handler.EnqueueRequestForObject{}).
+		Watches(&discoveryv1.EndpointSlice{}, handler.EnqueueRequestsFromMapFunc(endpointSliceToServiceExport)).
+		Watches(&discoveryv1alpha1.ExportedService{}, handler.EnqueueRequestsFromMapFunc(exportedServiceToServiceExport),
+			builder.WithPredicates(localExportedServices)).
+		Watches(&discoveryv1alpha1.ForeignCluster{}, handler.EnqueueRequestsFromMapFunc(r.foreignClusterToServiceExports),
+			builder.WithPredicates(fcPredicates)).
+		WithOptions(controller.Options{MaxConcurrentReconciles: workers}).
+		Complete(r)
+}
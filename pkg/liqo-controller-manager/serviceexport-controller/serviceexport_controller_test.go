@@ -0,0 +1,221 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serviceexportctrl
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	mcsv1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	liqoconsts "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqonet/ipam"
+	"github.com/liqotech/liqo/pkg/utils/testutil"
+)
+
+var _ = Describe("ServiceExport Controller", func() {
+	const (
+		namespace       = "foo"
+		name            = "bar"
+		clusterID       = "remote-cluster-id"
+		tenantNamespace = "liqo-tenant-remote"
+	)
+
+	var (
+		ctx        context.Context
+		err        error
+		objects    []client.Object
+		ipamClient ipam.IpamClient
+		fakeClient client.Client
+
+		req = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}
+
+		export = func() *mcsv1alpha1.ServiceExport {
+			return &mcsv1alpha1.ServiceExport{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		}
+
+		service = func(clusterIP string) *corev1.Service {
+			return &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Spec: corev1.ServiceSpec{
+					ClusterIP: clusterIP,
+					Ports:     []corev1.ServicePort{{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80}},
+				},
+			}
+		}
+
+		slice = func(sliceName string, addressType discoveryv1.AddressType, addresses ...string) *discoveryv1.EndpointSlice {
+			eps := &discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{Name: sliceName, Namespace: namespace,
+					Labels: map[string]string{discoveryv1.LabelServiceName: name}},
+				AddressType: addressType,
+				Ports:       []discoveryv1.EndpointPort{{Name: pointer.String("http"), Port: pointer.Int32(8080)}},
+			}
+			for _, address := range addresses {
+				eps.Endpoints = append(eps.Endpoints, discoveryv1.Endpoint{
+					Addresses:  []string{address},
+					Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(true)},
+					NodeName:   pointer.String("node"),
+					TargetRef:  &corev1.ObjectReference{Kind: "Pod", Name: "pod"},
+				})
+			}
+			return eps
+		}
+
+		exportedServiceKey = types.NamespacedName{Namespace: tenantNamespace, Name: ExportedServiceName(namespace, name)}
+
+		getExport = func() *mcsv1alpha1.ServiceExport {
+			var output mcsv1alpha1.ServiceExport
+			Expect(fakeClient.Get(ctx, req.NamespacedName, &output)).To(Succeed())
+			return &output
+		}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		objects = []client.Object{newForeignCluster(clusterID, tenantNamespace, discoveryv1alpha1.PeeringConditionStatusEstablished)}
+		ipamClient = &fakeIpamClient{}
+	})
+
+	JustBeforeEach(func() {
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objects...).
+			WithStatusSubresource(&mcsv1alpha1.ServiceExport{}).Build()
+		r := &ServiceExportReconciler{Client: fakeClient, IpamClient: ipamClient}
+		_, err = r.Reconcile(ctx, req)
+	})
+
+	When("the service exists", func() {
+		BeforeEach(func() {
+			objects = append(objects, export(), service("10.96.0.10"),
+				slice("slice-v4", discoveryv1.AddressTypeIPv4, "10.0.0.1", "10.0.1.2", "192.168.0.1"),
+				slice("slice-v6", discoveryv1.AddressTypeIPv6, "fd00::1"))
+		})
+
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+
+		It("should create the exported service in the tenant namespace of the remote cluster", func() {
+			var exported discoveryv1alpha1.ExportedService
+			Expect(fakeClient.Get(ctx, exportedServiceKey, &exported)).To(Succeed())
+			Expect(exported.Labels).To(HaveKeyWithValue(liqoconsts.ReplicationRequestedLabel, liqoconsts.ReplicationRequestedLabelValue))
+			Expect(exported.Labels).To(HaveKeyWithValue(liqoconsts.ReplicationDestinationLabel, clusterID))
+			Expect(exported.Labels).To(HaveKeyWithValue(liqoconsts.ExportedServiceNamespaceLabelKey, namespace))
+			Expect(exported.Labels).To(HaveKeyWithValue(liqoconsts.ExportedServiceNameLabelKey, name))
+
+			Expect(exported.Spec.Namespace).To(Equal(namespace))
+			Expect(exported.Spec.Name).To(Equal(name))
+			Expect(exported.Spec.Type).To(Equal(mcsv1alpha1.ClusterSetIP))
+			Expect(exported.Spec.Ports).To(ConsistOf(mcsv1alpha1.ServicePort{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80}))
+		})
+
+		It("should export only the translated IPv4 endpoints belonging to the pod CIDR", func() {
+			var exported discoveryv1alpha1.ExportedService
+			Expect(fakeClient.Get(ctx, exportedServiceKey, &exported)).To(Succeed())
+			Expect(exported.Spec.EndpointSlices).To(HaveLen(1))
+			Expect(exported.Spec.EndpointSlices[0].Ports).To(Equal(slice("", "").Ports))
+			Expect(exported.Spec.EndpointSlices[0].Endpoints).To(ConsistOf(
+				discoveryv1.Endpoint{Addresses: []string{"20.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(true)}},
+				discoveryv1.Endpoint{Addresses: []string{"20.0.1.2"}, Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(true)}},
+			))
+		})
+
+		It("should mark the service export as valid", func() {
+			Expect(getExport().Status.Conditions).To(ConsistOf(HaveField("Status", corev1.ConditionTrue)))
+			Expect(getExport().Status.Conditions).To(ConsistOf(HaveField("Type", mcsv1alpha1.ServiceExportValid)))
+		})
+
+		When("the ipam client is not configured", func() {
+			BeforeEach(func() { ipamClient = nil })
+
+			It("should export the addresses as they are", func() {
+				var exported discoveryv1alpha1.ExportedService
+				Expect(fakeClient.Get(ctx, exportedServiceKey, &exported)).To(Succeed())
+				Expect(exported.Spec.EndpointSlices).To(HaveLen(1))
+				Expect(exported.Spec.EndpointSlices[0].Endpoints).To(HaveLen(3))
+			})
+		})
+
+		When("the service is headless", func() {
+			BeforeEach(func() { objects[2] = service(corev1.ClusterIPNone) })
+
+			It("should export it as headless", func() {
+				var exported discoveryv1alpha1.ExportedService
+				Expect(fakeClient.Get(ctx, exportedServiceKey, &exported)).To(Succeed())
+				Expect(exported.Spec.Type).To(Equal(mcsv1alpha1.Headless))
+			})
+		})
+
+		When("the networking with the remote cluster is not established", func() {
+			BeforeEach(func() {
+				objects[0] = newForeignCluster(clusterID, tenantNamespace, discoveryv1alpha1.PeeringConditionStatusNone)
+			})
+
+			It("should not create the exported service", func() {
+				var exported discoveryv1alpha1.ExportedService
+				Expect(fakeClient.Get(ctx, exportedServiceKey, &exported)).To(testutil.BeNotFound())
+			})
+		})
+	})
+
+	When("the service does not exist", func() {
+		BeforeEach(func() { objects = append(objects, export()) })
+
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+		It("should mark the service export as not valid", func() {
+			Expect(getExport().Status.Conditions).To(ConsistOf(HaveField("Status", corev1.ConditionFalse)))
+			Expect(*getExport().Status.Conditions[0].Reason).To(Equal(ReasonServiceNotFound))
+		})
+	})
+
+	When("the service is of type ExternalName", func() {
+		BeforeEach(func() {
+			svc := service("")
+			svc.Spec.Type = corev1.ServiceTypeExternalName
+			objects = append(objects, export(), svc)
+		})
+
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+		It("should mark the service export as not valid", func() {
+			Expect(getExport().Status.Conditions).To(ConsistOf(HaveField("Status", corev1.ConditionFalse)))
+			Expect(*getExport().Status.Conditions[0].Reason).To(Equal(ReasonServiceTypeInvalid))
+		})
+	})
+
+	When("the service export has been deleted", func() {
+		BeforeEach(func() {
+			labels := exportedServiceLabels(req.NamespacedName)
+			labels[liqoconsts.ReplicationRequestedLabel] = liqoconsts.ReplicationRequestedLabelValue
+			labels[liqoconsts.ReplicationDestinationLabel] = clusterID
+			objects = append(objects, service("10.96.0.10"), &discoveryv1alpha1.ExportedService{
+				ObjectMeta: metav1.ObjectMeta{Name: exportedServiceKey.Name, Namespace: exportedServiceKey.Namespace, Labels: labels}})
+		})
+
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+		It("should delete the corresponding exported services", func() {
+			var exported discoveryv1alpha1.ExportedService
+			Expect(fakeClient.Get(ctx, exportedServiceKey, &exported)).To(testutil.BeNotFound())
+		})
+	})
+})
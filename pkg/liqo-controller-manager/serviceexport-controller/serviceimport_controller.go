@@ -0,0 +1,399 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serviceexportctrl
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	mcsv1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	liqoconsts "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils"
+	foreigncluster "github.com/liqotech/liqo/pkg/utils/foreignCluster"
+)
+
+// SourceClusterLabel is the key of the label assigned to the imported EndpointSlices to identify the cluster they originate from.
+const SourceClusterLabel = "multicluster.kubernetes.io/source-cluster"
+
+// ServiceImportReconciler reconciles ServiceImport objects, aggregating the ExportedServices
+// replicated by the peered clusters into the corresponding ServiceImports, services and EndpointSlices.
+type ServiceImportReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=multicluster.x-k8s.io,resources=serviceimports,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=multicluster.x-k8s.io,resources=serviceimports/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=discovery.liqo.io,resources=exportedservices,verbs=get;list;watch
+// +kubebuilder:rbac:groups=discovery.liqo.io,resources=foreignclusters,verbs=get;list;watch
+
+// Reconcile ServiceImport objects.
+func (r *ServiceImportReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	klog.V(4).Infof("reconcile serviceimport %q", req.NamespacedName)
+
+	exported, err := r.remoteExportedServices(ctx, req.NamespacedName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(exported) == 0 {
+		// The ServiceImport is no longer exported by any peered cluster. The owned resources are garbage collected.
+		serviceImport := mcsv1alpha1.ServiceImport{ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: req.Namespace}}
+		if err := r.Delete(ctx, &serviceImport); client.IgnoreNotFound(err) != nil {
+			klog.Errorf("failed to delete serviceimport %q: %v", req.NamespacedName, err)
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Services are imported only in the namespaces already existing in the local cluster (namespace sameness).
+	if err := r.Get(ctx, types.NamespacedName{Name: req.Namespace}, &corev1.Namespace{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.V(4).Infof("namespace %q not found, skipping the import of service %q", req.Namespace, req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		klog.Errorf("an error occurred while getting namespace %q: %v", req.Namespace, err)
+		return ctrl.Result{}, err
+	}
+
+	serviceImport, err := r.enforceServiceImport(ctx, req.NamespacedName, exported)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	derivedName := ""
+	if serviceImport.Spec.Type == mcsv1alpha1.ClusterSetIP {
+		derivedName = DerivedServiceName(req.Name)
+	}
+
+	if err := r.enforceDerivedService(ctx, serviceImport, derivedName); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.enforceEndpointSlices(ctx, serviceImport, derivedName, exported); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// remoteExportedServices returns the ExportedServices replicated by the peered clusters for the given service,
+// sorted from the oldest to the newest one.
+func (r *ServiceImportReconciler) remoteExportedServices(ctx context.Context, service types.NamespacedName) (
+	[]discoveryv1alpha1.ExportedService, error) {
+	var exported discoveryv1alpha1.ExportedServiceList
+	if err := r.List(ctx, &exported, client.MatchingLabels(exportedServiceLabels(service)),
+		client.HasLabels{liqoconsts.ReplicationStatusLabel, liqoconsts.ReplicationOriginLabel}); err != nil {
+		klog.Errorf("failed to list the exported services for %q: %v", service, err)
+		return nil, err
+	}
+
+	output := exported.Items
+	sort.SliceStable(output, func(i, j int) bool {
+		if !output[i].CreationTimestamp.Equal(&output[j].CreationTimestamp) {
+			return output[i].CreationTimestamp.Before(&output[j].CreationTimestamp)
+		}
+		return output[i].Labels[liqoconsts.ReplicationOriginLabel] < output[j].Labels[liqoconsts.ReplicationOriginLabel]
+	})
+	return output, nil
+}
+
+// enforceServiceImport ensures the ServiceImport reflects the given ExportedServices.
+// In case of conflicting properties, the oldest export takes precedence, while ports are merged by name.
+func (r *ServiceImportReconciler) enforceServiceImport(ctx context.Context, service types.NamespacedName,
+	exported []discoveryv1alpha1.ExportedService) (*mcsv1alpha1.ServiceImport, error) {
+	reference := &exported[0].Spec
+
+	var ports []mcsv1alpha1.ServicePort
+	names := map[string]struct{}{}
+	clusters := make([]mcsv1alpha1.ClusterStatus, 0, len(exported))
+	for i := range exported {
+		for j := range exported[i].Spec.Ports {
+			port := exported[i].Spec.Ports[j]
+			if _, found := names[port.Name]; !found {
+				names[port.Name] = struct{}{}
+				ports = append(ports, port)
+			}
+		}
+		clusters = append(clusters, mcsv1alpha1.ClusterStatus{Cluster: exported[i].Labels[liqoconsts.ReplicationOriginLabel]})
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].Cluster < clusters[j].Cluster })
+
+	serviceImport := mcsv1alpha1.ServiceImport{ObjectMeta: metav1.ObjectMeta{Name: service.Name, Namespace: service.Namespace}}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, &serviceImport, func() error {
+		if serviceImport.Spec.Type != reference.Type {
+			serviceImport.Spec.IPs = nil
+		}
+		serviceImport.Spec.Type = reference.Type
+		serviceImport.Spec.Ports = ports
+		serviceImport.Spec.SessionAffinity = reference.SessionAffinity
+		serviceImport.Spec.SessionAffinityConfig = reference.SessionAffinityConfig
+		return nil
+	})
+	if err != nil {
+		klog.Errorf("failed to enforce serviceimport %q: %v", service, err)
+		return nil, err
+	}
+	klog.V(utils.FromResult(result)).Infof("serviceimport %q successfully enforced (with %v operation)", service, result)
+
+	if !reflect.DeepEqual(serviceImport.Status.Clusters, clusters) {
+		serviceImport.Status.Clusters = clusters
+		if err := r.Status().Update(ctx, &serviceImport); err != nil {
+			klog.Errorf("failed to update the status of serviceimport %q: %v", service, err)
+			return nil, err
+		}
+	}
+
+	return &serviceImport, nil
+}
+
+// enforceDerivedService ensures the service backing the ClusterSetIP ServiceImport exists (or not, if the name is empty),
+// and that the corresponding IP is reported in the ServiceImport.
+func (r *ServiceImportReconciler) enforceDerivedService(ctx context.Context, serviceImport *mcsv1alpha1.ServiceImport, name string) error {
+	if name == "" {
+		derived := corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: DerivedServiceName(serviceImport.Name), Namespace: serviceImport.Namespace}}
+		if err := r.Delete(ctx, &derived); client.IgnoreNotFound(err) != nil {
+			klog.Errorf("failed to delete service %q: %v", klog.KObj(&derived), err)
+			return err
+		}
+		return nil
+	}
+
+	derived := corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: serviceImport.Namespace}}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, &derived, func() error {
+		derived.Labels = labels.Merge(derived.Labels, labels.Set{mcsv1alpha1.LabelServiceName: serviceImport.Name})
+		derived.Spec.Type = corev1.ServiceTypeClusterIP
+		derived.Spec.Selector = nil
+		derived.Spec.SessionAffinity = serviceImport.Spec.SessionAffinity
+		derived.Spec.SessionAffinityConfig = serviceImport.Spec.SessionAffinityConfig
+
+		derived.Spec.Ports = nil
+		for i := range serviceImport.Spec.Ports {
+			port := &serviceImport.Spec.Ports[i]
+			derived.Spec.Ports = append(derived.Spec.Ports, corev1.ServicePort{
+				Name: port.Name, Protocol: port.Protocol, AppProtocol: port.AppProtocol, Port: port.Port,
+			})
+		}
+		return controllerutil.SetControllerReference(serviceImport, &derived, r.Scheme)
+	})
+	if err != nil {
+		klog.Errorf("failed to enforce service %q: %v", klog.KObj(&derived), err)
+		return err
+	}
+	klog.V(utils.FromResult(result)).Infof("service %q successfully enforced (with %v operation)", klog.KObj(&derived), result)
+
+	if derived.Spec.ClusterIP != "" && !reflect.DeepEqual(serviceImport.Spec.IPs, []string{derived.Spec.ClusterIP}) {
+		serviceImport.Spec.IPs = []string{derived.Spec.ClusterIP}
+		if err := r.Update(ctx, serviceImport); err != nil {
+			klog.Errorf("failed to update the IPs of serviceimport %q: %v", klog.KObj(serviceImport), err)
+			return err
+		}
+	}
+	return nil
+}
+
+// enforceEndpointSlices ensures an EndpointSlice exists for each endpoint slice exported by the peered clusters,
+// and removes the stale ones. Endpoints are marked as not ready if the networking with the source cluster is not established.
+func (r *ServiceImportReconciler) enforceEndpointSlices(ctx context.Context, serviceImport *mcsv1alpha1.ServiceImport,
+	derivedName string, exported []discoveryv1alpha1.ExportedService) error {
+	desired := map[string]struct{}{}
+
+	for i := range exported {
+		clusterID := exported[i].Labels[liqoconsts.ReplicationOriginLabel]
+		networkReady, err := r.isNetworkReady(ctx, clusterID)
+		if err != nil {
+			return err
+		}
+
+		for j := range exported[i].Spec.EndpointSlices {
+			source := &exported[i].Spec.EndpointSlices[j]
+			slice := discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{
+				Name: ImportedEndpointSliceName(serviceImport.Name, clusterID, j), Namespace: serviceImport.Namespace}}
+			desired[slice.Name] = struct{}{}
+
+			result, err := controllerutil.CreateOrUpdate(ctx, r.Client, &slice, func() error {
+				slice.Labels = labels.Merge(slice.Labels, labels.Set{
+					mcsv1alpha1.LabelServiceName: serviceImport.Name,
+					discoveryv1.LabelManagedBy:   EndpointSliceManagedBy,
+					SourceClusterLabel:           clusterID,
+				})
+				if derivedName != "" {
+					slice.Labels[discoveryv1.LabelServiceName] = derivedName
+				} else {
+					delete(slice.Labels, discoveryv1.LabelServiceName)
+				}
+
+				slice.AddressType = discoveryv1.AddressTypeIPv4
+				slice.Ports = source.Ports
+				slice.Endpoints = make([]discoveryv1.Endpoint, len(source.Endpoints))
+				for k := range source.Endpoints {
+					source.Endpoints[k].DeepCopyInto(&slice.Endpoints[k])
+					if !networkReady {
+						slice.Endpoints[k].Conditions.Ready = pointer.Bool(false)
+					}
+				}
+				return controllerutil.SetControllerReference(serviceImport, &slice, r.Scheme)
+			})
+			if err != nil {
+				klog.Errorf("failed to enforce endpointslice %q: %v", klog.KObj(&slice), err)
+				return err
+			}
+			klog.V(utils.FromResult(result)).Infof("endpointslice %q successfully enforced (with %v operation)", klog.KObj(&slice), result)
+		}
+	}
+
+	var existing discoveryv1.EndpointSliceList
+	if err := r.List(ctx, &existing, client.InNamespace(serviceImport.Namespace), client.MatchingLabels{
+		mcsv1alpha1.LabelServiceName: serviceImport.Name, discoveryv1.LabelManagedBy: EndpointSliceManagedBy}); err != nil {
+		klog.Errorf("failed to list the endpointslices of serviceimport %q: %v", klog.KObj(serviceImport), err)
+		return err
+	}
+
+	for i := range existing.Items {
+		slice := &existing.Items[i]
+		if _, found := desired[slice.Name]; found {
+			continue
+		}
+
+		if err := r.Delete(ctx, slice); client.IgnoreNotFound(err) != nil {
+			klog.Errorf("failed to delete endpointslice %q: %v", klog.KObj(slice), err)
+			return err
+		}
+		klog.Infof("endpointslice %q successfully deleted", klog.KObj(slice))
+	}
+
+	return nil
+}
+
+// isNetworkReady returns whether the networking with the given cluster is established.
+func (r *ServiceImportReconciler) isNetworkReady(ctx context.Context, clusterID string) (bool, error) {
+	fc, err := foreigncluster.GetForeignClusterByID(ctx, r.Client, clusterID)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		klog.Errorf("an error occurred while getting foreigncluster %q: %v", clusterID, err)
+		return false, err
+	}
+	return foreigncluster.IsNetworkingEstablishedOrExternal(fc), nil
+}
+
+// exportedServiceToServiceImport maps the replicated ExportedServices to the corresponding ServiceImport.
+func exportedServiceToServiceImport(_ context.Context, obj client.Object) []reconcile.Request {
+	exported, ok := obj.(*discoveryv1alpha1.ExportedService)
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: exported.Spec.Namespace, Name: exported.Spec.Name}}}
+}
+
+// enqueueFromExportedServices returns a map function enqueueing the ServiceImports corresponding to
+// the replicated ExportedServices matching the labels derived from the given object (if any).
+func (r *ServiceImportReconciler) enqueueFromExportedServices(selector func(client.Object) client.MatchingLabels) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		matchingLabels := selector(obj)
+		if matchingLabels == nil {
+			return nil
+		}
+
+		var exported discoveryv1alpha1.ExportedServiceList
+		if err := r.List(ctx, &exported, matchingLabels, client.HasLabels{liqoconsts.ReplicationStatusLabel}); err != nil {
+			klog.Errorf("failed to list exportedservices: %v", err)
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(exported.Items))
+		for i := range exported.Items {
+			requests = append(requests, exportedServiceToServiceImport(ctx, &exported.Items[i])...)
+		}
+		return requests
+	}
+}
+
+// SetupWithManager monitors ServiceImports and the ExportedServices replicated by the peered clusters.
+func (r *ServiceImportReconciler) SetupWithManager(mgr ctrl.Manager, workers int) error {
+	remoteExportedServices, err := predicate.LabelSelectorPredicate(metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: liqoconsts.ReplicationStatusLabel, Operator: metav1.LabelSelectorOpExists}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Reconcile the ServiceImports when the networking with the source cluster changes, to update the endpoints readiness.
+	fcPredicates := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool { return false },
+		DeleteFunc: func(e event.DeleteEvent) bool { return true },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldFc, oldOk := e.ObjectOld.(*discoveryv1alpha1.ForeignCluster)
+			newFc, newOk := e.ObjectNew.(*discoveryv1alpha1.ForeignCluster)
+			return oldOk && newOk && foreigncluster.IsNetworkingEstablishedOrExternal(oldFc) != foreigncluster.IsNetworkingEstablishedOrExternal(newFc)
+		},
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	}
+
+	// Reconcile the ServiceImports when the corresponding namespace is created.
+	nsPredicates := predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return true },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("serviceimport").
+		For(&mcsv1alpha1.ServiceImport{}).
+		Owns(&corev1.Service{}).
+		Owns(&discoveryv1.EndpointSlice{}).
+		Watches(&discoveryv1alpha1.ExportedService{}, handler.EnqueueRequestsFromMapFunc(exportedServiceToServiceImport),
+			builder.WithPredicates(remoteExportedServices)).
+		Watches(&discoveryv1alpha1.ForeignCluster{}, handler.EnqueueRequestsFromMapFunc(
+			r.enqueueFromExportedServices(func(obj client.Object) client.MatchingLabels {
+				fc, ok := obj.(*discoveryv1alpha1.ForeignCluster)
+				if !ok {
+					return nil
+				}
+				return client.MatchingLabels{liqoconsts.ReplicationOriginLabel: fc.Spec.ClusterIdentity.ClusterID}
+			})), builder.WithPredicates(fcPredicates)).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(
+			r.enqueueFromExportedServices(func(obj client.Object) client.MatchingLabels {
+				return client.MatchingLabels{liqoconsts.ExportedServiceNamespaceLabelKey: obj.GetName()}
+			})), builder.WithPredicates(nsPredicates)).
+		WithOptions(controller.Options{MaxConcurrentReconciles: workers}).
+		Complete(r)
+}
@@ -0,0 +1,195 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serviceexportctrl
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	mcsv1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	liqoconsts "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils/testutil"
+)
+
+var _ = Describe("ServiceImport Controller", func() {
+	const (
+		namespace = "foo"
+		name      = "bar"
+		clusterA  = "cluster-a"
+		clusterB  = "cluster-b"
+	)
+
+	var (
+		ctx        context.Context
+		err        error
+		objects    []client.Object
+		fakeClient client.Client
+
+		req = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}
+
+		exported = func(clusterID string, age time.Duration, svcType mcsv1alpha1.ServiceImportType,
+			ports []mcsv1alpha1.ServicePort, addresses ...string) *discoveryv1alpha1.ExportedService {
+			labels := exportedServiceLabels(req.NamespacedName)
+			labels[liqoconsts.ReplicationStatusLabel] = "true"
+			labels[liqoconsts.ReplicationOriginLabel] = clusterID
+
+			es := &discoveryv1alpha1.ExportedService{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: ExportedServiceName(namespace, name), Namespace: "liqo-tenant-" + clusterID, Labels: labels,
+					CreationTimestamp: metav1.NewTime(time.Now().Add(-age).Truncate(time.Second)),
+				},
+				Spec: discoveryv1alpha1.ExportedServiceSpec{Namespace: namespace, Name: name, Type: svcType, Ports: ports},
+			}
+
+			slice := discoveryv1alpha1.ExportedEndpointSlice{Ports: []discoveryv1.EndpointPort{{Name: pointer.String("http"), Port: pointer.Int32(8080)}}}
+			for _, address := range addresses {
+				slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{
+					Addresses: []string{address}, Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(true)}})
+			}
+			es.Spec.EndpointSlices = []discoveryv1alpha1.ExportedEndpointSlice{slice}
+			return es
+		}
+
+		httpPort  = mcsv1alpha1.ServicePort{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80}
+		httpsPort = mcsv1alpha1.ServicePort{Name: "https", Protocol: corev1.ProtocolTCP, Port: 443}
+
+		getServiceImport = func() *mcsv1alpha1.ServiceImport {
+			var output mcsv1alpha1.ServiceImport
+			Expect(fakeClient.Get(ctx, req.NamespacedName, &output)).To(Succeed())
+			return &output
+		}
+
+		getEndpointSlice = func(clusterID string) *discoveryv1.EndpointSlice {
+			var output discoveryv1.EndpointSlice
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: namespace,
+				Name: ImportedEndpointSliceName(name, clusterID, 0)}, &output)).To(Succeed())
+			return &output
+		}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		objects = []client.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}},
+			newForeignCluster(clusterA, "liqo-tenant-"+clusterA, discoveryv1alpha1.PeeringConditionStatusEstablished),
+			newForeignCluster(clusterB, "liqo-tenant-"+clusterB, discoveryv1alpha1.PeeringConditionStatusNone),
+		}
+	})
+
+	JustBeforeEach(func() {
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objects...).
+			WithStatusSubresource(&mcsv1alpha1.ServiceImport{}).Build()
+		r := &ServiceImportReconciler{Client: fakeClient, Scheme: scheme.Scheme}
+		_, err = r.Reconcile(ctx, req)
+	})
+
+	When("the service is exported by multiple clusters", func() {
+		BeforeEach(func() {
+			objects = append(objects,
+				exported(clusterA, time.Hour, mcsv1alpha1.ClusterSetIP, []mcsv1alpha1.ServicePort{httpPort}, "20.0.0.1"),
+				exported(clusterB, time.Minute, mcsv1alpha1.Headless, []mcsv1alpha1.ServicePort{httpPort, httpsPort}, "30.0.0.1"))
+		})
+
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+
+		It("should create the service import, with the type of the oldest export and the union of the ports", func() {
+			serviceImport := getServiceImport()
+			Expect(serviceImport.Spec.Type).To(Equal(mcsv1alpha1.ClusterSetIP))
+			Expect(serviceImport.Spec.Ports).To(ConsistOf(httpPort, httpsPort))
+			Expect(serviceImport.Status.Clusters).To(Equal([]mcsv1alpha1.ClusterStatus{{Cluster: clusterA}, {Cluster: clusterB}}))
+		})
+
+		It("should create the derived service", func() {
+			var derived corev1.Service
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: DerivedServiceName(name)}, &derived)).To(Succeed())
+			Expect(derived.Labels).To(HaveKeyWithValue(mcsv1alpha1.LabelServiceName, name))
+			Expect(derived.Spec.Selector).To(BeEmpty())
+			Expect(derived.Spec.Ports).To(HaveLen(2))
+			Expect(derived.OwnerReferences).To(ConsistOf(HaveField("Name", name)))
+		})
+
+		It("should create an endpointslice for each cluster", func() {
+			for _, clusterID := range []string{clusterA, clusterB} {
+				slice := getEndpointSlice(clusterID)
+				Expect(slice.Labels).To(HaveKeyWithValue(discoveryv1.LabelServiceName, DerivedServiceName(name)))
+				Expect(slice.Labels).To(HaveKeyWithValue(mcsv1alpha1.LabelServiceName, name))
+				Expect(slice.Labels).To(HaveKeyWithValue(discoveryv1.LabelManagedBy, EndpointSliceManagedBy))
+				Expect(slice.Labels).To(HaveKeyWithValue(SourceClusterLabel, clusterID))
+				Expect(slice.AddressType).To(Equal(discoveryv1.AddressTypeIPv4))
+				Expect(slice.Endpoints).To(HaveLen(1))
+			}
+		})
+
+		It("should mark as not ready the endpoints of the clusters without established networking", func() {
+			Expect(getEndpointSlice(clusterA).Endpoints[0].Conditions.Ready).To(PointTo(BeTrue()))
+			Expect(getEndpointSlice(clusterB).Endpoints[0].Conditions.Ready).To(PointTo(BeFalse()))
+		})
+	})
+
+	When("the service is headless", func() {
+		BeforeEach(func() {
+			objects = append(objects, exported(clusterA, time.Hour, mcsv1alpha1.Headless, []mcsv1alpha1.ServicePort{httpPort}, "20.0.0.1"))
+		})
+
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+		It("should not create the derived service", func() {
+			var derived corev1.Service
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: DerivedServiceName(name)}, &derived)).
+				To(testutil.BeNotFound())
+		})
+		It("should create the endpointslice not associated with any service", func() {
+			Expect(getEndpointSlice(clusterA).Labels).ToNot(HaveKey(discoveryv1.LabelServiceName))
+			Expect(getEndpointSlice(clusterA).Labels).To(HaveKeyWithValue(mcsv1alpha1.LabelServiceName, name))
+		})
+	})
+
+	When("the namespace does not exist", func() {
+		BeforeEach(func() {
+			objects = append(objects[1:], exported(clusterA, time.Hour, mcsv1alpha1.ClusterSetIP, []mcsv1alpha1.ServicePort{httpPort}))
+		})
+
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+		It("should not create the service import", func() {
+			Expect(fakeClient.Get(ctx, req.NamespacedName, &mcsv1alpha1.ServiceImport{})).To(testutil.BeNotFound())
+		})
+	})
+
+	When("the service is no longer exported", func() {
+		BeforeEach(func() {
+			objects = append(objects, &mcsv1alpha1.ServiceImport{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}},
+				&discoveryv1alpha1.ExportedService{ObjectMeta: metav1.ObjectMeta{Name: ExportedServiceName(namespace, name),
+					Namespace: "liqo-tenant-local", Labels: exportedServiceLabels(req.NamespacedName)}})
+		})
+
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+		It("should delete the service import, ignoring the local exported services", func() {
+			Expect(fakeClient.Get(ctx, req.NamespacedName, &mcsv1alpha1.ServiceImport{})).To(testutil.BeNotFound())
+		})
+	})
+})
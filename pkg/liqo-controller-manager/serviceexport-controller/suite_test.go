@@ -0,0 +1,83 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serviceexportctrl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	mcsv1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	liqodiscovery "github.com/liqotech/liqo/pkg/discovery"
+	"github.com/liqotech/liqo/pkg/liqonet/ipam"
+	"github.com/liqotech/liqo/pkg/utils/testutil"
+)
+
+func TestServiceExportController(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Service Export Controller Suite")
+}
+
+var _ = BeforeSuite(func() {
+	testutil.LogsToGinkgoWriter()
+
+	Expect(corev1.AddToScheme(scheme.Scheme)).To(Succeed())
+	Expect(discoveryv1.AddToScheme(scheme.Scheme)).To(Succeed())
+	Expect(discoveryv1alpha1.AddToScheme(scheme.Scheme)).To(Succeed())
+	Expect(mcsv1alpha1.AddToScheme(scheme.Scheme)).To(Succeed())
+})
+
+// fakeIpamClient translates the addresses belonging to the 10.0.0.0/16 pod CIDR by replacing the first octet with 20.
+type fakeIpamClient struct {
+	ipam.IpamClient
+}
+
+func (c *fakeIpamClient) BelongsToPodCIDR(_ context.Context, req *ipam.BelongsRequest, _ ...grpc.CallOption) (*ipam.BelongsResponse, error) {
+	var a, b, x, y int
+	_, err := fmt.Sscanf(req.GetIp(), "%d.%d.%d.%d", &a, &b, &x, &y)
+	return &ipam.BelongsResponse{Belongs: err == nil && a == 10 && b == 0}, nil
+}
+
+func (c *fakeIpamClient) MapEndpointIP(_ context.Context, req *ipam.MapRequest, _ ...grpc.CallOption) (*ipam.MapResponse, error) {
+	var a, b, x, y int
+	if _, err := fmt.Sscanf(req.GetIp(), "%d.%d.%d.%d", &a, &b, &x, &y); err != nil {
+		return nil, err
+	}
+	return &ipam.MapResponse{Ip: fmt.Sprintf("20.%d.%d.%d", b, x, y)}, nil
+}
+
+func newForeignCluster(clusterID, tenantNamespace string, networkStatus discoveryv1alpha1.PeeringConditionStatusType) *discoveryv1alpha1.ForeignCluster {
+	return &discoveryv1alpha1.ForeignCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterID, Labels: map[string]string{liqodiscovery.ClusterIDLabel: clusterID}},
+		Spec: discoveryv1alpha1.ForeignClusterSpec{
+			ClusterIdentity: discoveryv1alpha1.ClusterIdentity{ClusterID: clusterID, ClusterName: clusterID},
+		},
+		Status: discoveryv1alpha1.ForeignClusterStatus{
+			TenantNamespace: discoveryv1alpha1.TenantNamespaceType{Local: tenantNamespace},
+			PeeringConditions: []discoveryv1alpha1.PeeringCondition{
+				{Type: discoveryv1alpha1.NetworkStatusCondition, Status: networkStatus},
+			},
+		},
+	}
+}
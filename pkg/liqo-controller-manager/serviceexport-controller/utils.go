@@ -0,0 +1,105 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serviceexportctrl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	mcsv1alpha1 "sigs.k8s.io/mcs-api/pkg/apis/v1alpha1"
+)
+
+const (
+	// derivedServicePrefix is the prefix of the name of the services backing the ClusterSetIP ServiceImports.
+	derivedServicePrefix = "derived-"
+	// hashLength is the number of characters of the hashes included in the generated names.
+	hashLength = 10
+
+	// EndpointSliceManagedBy is the value of the managed-by label assigned to the imported EndpointSlices.
+	EndpointSliceManagedBy = "serviceimport-controller.liqo.io"
+)
+
+// shortHash returns a short hash of the given strings.
+func shortHash(values ...string) string {
+	h := sha256.New()
+	for _, value := range values {
+		h.Write([]byte(value))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:hashLength]
+}
+
+// ExportedServiceName returns the name of the ExportedService corresponding to the given service.
+// Dots are not allowed in service and namespace names, hence the result is guaranteed to be unique.
+func ExportedServiceName(namespace, name string) string {
+	return fmt.Sprintf("%s.%s", name, namespace)
+}
+
+// DerivedServiceName returns the name of the service backing the ClusterSetIP ServiceImport with the given name.
+func DerivedServiceName(name string) string {
+	return derivedServicePrefix + shortHash(name)
+}
+
+// ImportedEndpointSliceName returns the name of the index-th EndpointSlice imported from the given cluster.
+func ImportedEndpointSliceName(name, clusterID string, index int) string {
+	return fmt.Sprintf("%s-%s-%d", name, shortHash(clusterID), index)
+}
+
+// setServiceExportCondition sets the given condition on the ServiceExport, and returns whether it has been modified.
+func setServiceExportCondition(export *mcsv1alpha1.ServiceExport, conditionType mcsv1alpha1.ServiceExportConditionType,
+	status corev1.ConditionStatus, reason, message string) bool {
+	for i := range export.Status.Conditions {
+		condition := &export.Status.Conditions[i]
+		if condition.Type != conditionType {
+			continue
+		}
+
+		if condition.Status == status && ptrEqual(condition.Reason, reason) && ptrEqual(condition.Message, message) {
+			return false
+		}
+
+		if condition.Status != status {
+			now := metav1.Now()
+			condition.LastTransitionTime = &now
+		}
+		condition.Status, condition.Reason, condition.Message = status, &reason, &message
+		return true
+	}
+
+	now := metav1.Now()
+	export.Status.Conditions = append(export.Status.Conditions, mcsv1alpha1.ServiceExportCondition{
+		Type: conditionType, Status: status, LastTransitionTime: &now, Reason: &reason, Message: &message,
+	})
+	return true
+}
+
+func ptrEqual(ptr *string, value string) bool {
+	return ptr != nil && *ptr == value
+}
+
+// exportedEndpoint returns the representation of an endpoint to be exported, given the local one and the translated addresses.
+// Fields referring to local objects (i.e., the target pod and the hosting node) are not propagated.
+func exportedEndpoint(endpoint *discoveryv1.Endpoint, addresses []string) discoveryv1.Endpoint {
+	return discoveryv1.Endpoint{
+		Addresses:  addresses,
+		Conditions: endpoint.Conditions,
+		Hostname:   endpoint.Hostname,
+		Zone:       endpoint.Zone,
+	}
+}
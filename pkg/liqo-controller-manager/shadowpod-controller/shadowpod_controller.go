@@ -35,6 +35,7 @@ import (
 	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
 	"github.com/liqotech/liqo/pkg/consts"
 	clientutils "github.com/liqotech/liqo/pkg/utils/clients"
+	"github.com/liqotech/liqo/pkg/utils/pod"
 )
 
 // Reconciler reconciles a ShadowPod object.
@@ -46,6 +47,7 @@ type Reconciler struct {
 // +kubebuilder:rbac:groups=virtualkubelet.liqo.io,resources=shadowpods,verbs=get;list;watch;update;patch;delete
 // +kubebuilder:rbac:groups=virtualkubelet.liqo.io,resources=shadowpods/finalizers,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods/ephemeralcontainers,verbs=get;update;patch
 // +kubebuilder:rbac:groups=virtualkubelet.liqo.io,resources=shadowpods/status,verbs=get;update;patch
 
 // Reconcile ShadowPods objects.
@@ -93,6 +95,17 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			return ctrl.Result{}, err
 		}
 
+		// Propagate the ephemeral containers (e.g., added through kubectl debug) which are not yet present in the pod.
+		// They can be added only through the corresponding subresource, and never modified or removed once added.
+		if missing := pod.MissingEphemeralContainers(existingPod.Spec.EphemeralContainers, shadowPod.Spec.Pod.EphemeralContainers); len(missing) > 0 {
+			existingPod.Spec.EphemeralContainers = append(existingPod.Spec.EphemeralContainers, missing...)
+			if err := r.SubResource("ephemeralcontainers").Update(ctx, &existingPod); err != nil {
+				klog.Errorf("unable to add ephemeral containers to pod %q: %v", klog.KObj(&existingPod), err)
+				return ctrl.Result{}, err
+			}
+			klog.Infof("added %d ephemeral container(s) to pod %q", len(missing), klog.KObj(&existingPod))
+		}
+
 		// Update ShadowPod status same as Pod status
 		shadowPod.Status.Phase = existingPod.Status.DeepCopy().Phase
 		if newErr := r.Client.Status().Update(ctx, &shadowPod); newErr != nil {
@@ -116,6 +129,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		Spec: shadowPod.Spec.Pod,
 	}
 
+	// Ephemeral containers cannot be specified at creation time, and they are added afterwards through the corresponding subresource.
+	newPod.Spec.EphemeralContainers = nil
+
 	utilruntime.Must(ctrl.SetControllerReference(&shadowPod, &newPod, r.Scheme))
 
 	if err := r.Create(ctx, &newPod, client.FieldOwner("shadow-pod")); err != nil {
@@ -173,6 +173,30 @@ var _ = Describe("Reconcile", func() {
 		})
 	})
 
+	When("ephemeral containers have been added to the shadowpod", func() {
+		BeforeEach(func() {
+			Expect(k8sClient.Create(ctx, &testPod)).To(Succeed())
+			testShadowPod.Spec.Pod.EphemeralContainers = []corev1.EphemeralContainer{{
+				EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger", Image: "busybox"},
+				TargetContainerName:      "nginx",
+			}}
+			Expect(k8sClient.Create(ctx, &testShadowPod)).To(Succeed())
+		})
+
+		It("should not error", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(BeZero())
+		})
+
+		It("should add the ephemeral containers to the pod", func() {
+			pod := corev1.Pod{}
+			Expect(k8sClient.Get(ctx, req.NamespacedName, &pod)).To(Succeed())
+			Expect(pod.Spec.EphemeralContainers).To(HaveLen(1))
+			Expect(pod.Spec.EphemeralContainers[0].Name).To(Equal("debugger"))
+			Expect(pod.Spec.EphemeralContainers[0].TargetContainerName).To(Equal("nginx"))
+		})
+	})
+
 	When("create pod", func() {
 		BeforeEach(func() {
 			Expect(k8sClient.Create(ctx, &testShadowPod)).To(Succeed())
@@ -16,6 +16,7 @@ package nsoffwh
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	admissionv1 "k8s.io/api/admission/v1"
@@ -63,6 +64,10 @@ func (w *nsoffwh) Handle(ctx context.Context, req admission.Request) admission.R
 		return admission.Denied("NamespaceOffloading name must match " + consts.DefaultNamespaceOffloadingName)
 	}
 
+	if err := validateWeights(nsoff); err != nil {
+		return admission.Denied(err.Error())
+	}
+
 	if req.Operation != admissionv1.Update {
 		return admission.Allowed("")
 	}
@@ -86,3 +91,29 @@ func (w *nsoffwh) Handle(ctx context.Context, req admission.Request) admission.R
 
 	return admission.Allowed("").WithWarnings(warnings...)
 }
+
+// validateWeights checks that at least one cluster is assigned a positive weight in case of weighted pod offloading strategy,
+// and that no weight is negative.
+func validateWeights(nsoff *offv1alpha1.NamespaceOffloading) error {
+	weights := nsoff.Spec.Weights
+	if nsoff.Spec.PodOffloadingStrategy != offv1alpha1.WeightedPodOffloadingStrategyType {
+		return nil
+	}
+
+	if weights == nil {
+		return fmt.Errorf("the Weights field must be set in case of %s PodOffloadingStrategy", offv1alpha1.WeightedPodOffloadingStrategyType)
+	}
+
+	positive := weights.Local > 0
+	for clusterID, weight := range weights.Remote {
+		if weight < 0 {
+			return fmt.Errorf("the weight of cluster %q must not be negative", clusterID)
+		}
+		positive = positive || weight > 0
+	}
+
+	if !positive {
+		return fmt.Errorf("at least one cluster must be assigned a positive weight")
+	}
+	return nil
+}
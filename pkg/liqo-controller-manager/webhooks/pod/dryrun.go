@@ -0,0 +1,308 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	offv1alpha1 "github.com/liqotech/liqo/apis/offloading/v1alpha1"
+	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
+	"github.com/liqotech/liqo/internal/crdReplicator/reflection"
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	identitymanager "github.com/liqotech/liqo/pkg/identityManager"
+	argsutils "github.com/liqotech/liqo/pkg/utils/args"
+	foreignclusterutils "github.com/liqotech/liqo/pkg/utils/foreignCluster"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+)
+
+// DryRunMode defines how the outcome of the remote dry-run of the offloading of a pod is handled.
+type DryRunMode string
+
+const (
+	// DryRunModeDisabled disables the remote dry-run.
+	DryRunModeDisabled DryRunMode = "Disabled"
+	// DryRunModeAnnotate annotates the pod with the reasons of the remote rejections, if any.
+	DryRunModeAnnotate DryRunMode = "Annotate"
+	// DryRunModeReject annotates the pod with the reasons of the remote rejections, if any,
+	// and rejects its creation in case it can be executed only remotely, and no remote cluster would accept it.
+	DryRunModeReject DryRunMode = "Reject"
+)
+
+// DryRunModes returns the list of supported dry-run modes.
+func DryRunModes() []string {
+	return []string{string(DryRunModeDisabled), string(DryRunModeAnnotate), string(DryRunModeReject)}
+}
+
+// RemoteClientGetter returns a client interacting with the given remote cluster.
+type RemoteClientGetter func(ctx context.Context, clusterID string) (client.Client, error)
+
+// DryRunner performs the server-side dry-run of the ShadowPods which would be created in the remote clusters
+// upon offloading a pod, to detect up front whether they would be rejected (e.g., due to quotas). The rejections of the
+// remote pods themselves (e.g., by the pod security admission) are not detected, as created asynchronously afterwards.
+type DryRunner struct {
+	// Mode is the behavior in case of remote rejections.
+	Mode DryRunMode
+	// LocalClusterID is the cluster ID of the local cluster.
+	LocalClusterID string
+	// RemoteClients returns the clients to interact with the remote clusters.
+	RemoteClients RemoteClientGetter
+	// Timeout is the maximum duration of the dry-run towards each remote cluster.
+	Timeout time.Duration
+
+	// APIServerSupport, HomeAPIServerHost and HomeAPIServerPort mirror the configuration of the virtual kubelets,
+	// so that the ShadowPods are forged with the same mutations concerning the interaction with the local API server.
+	APIServerSupport  forge.APIServerSupportType
+	HomeAPIServerHost string
+	HomeAPIServerPort string
+	// ForgingOpts mirrors the labels and annotations not reflected by the virtual kubelets.
+	ForgingOpts forge.ForgingOpts
+}
+
+// dryRunKubernetesServiceIP is the placeholder for the remapped IP of the local kubernetes service, which is retrieved
+// by the virtual kubelets at runtime, and does not affect the admission of the ShadowPods.
+const dryRunKubernetesServiceIP = "127.0.0.1"
+
+// ConfigureFromVirtualKubeletArgs configures the forging of the ShadowPods according to the given arguments
+// of the virtual kubelets, so that they mirror the ones actually created upon offloading.
+func (dr *DryRunner) ConfigureFromVirtualKubeletArgs(args []string) error {
+	var enableAPIServerSupport bool
+	var labelsNotReflected, annotationsNotReflected argsutils.StringList
+
+	flags := pflag.NewFlagSet("virtual-kubelet", pflag.ContinueOnError)
+	flags.ParseErrorsWhitelist.UnknownFlags = true
+	flags.BoolVar(&enableAPIServerSupport, "enable-apiserver-support", false, "")
+	flags.StringVar(&dr.HomeAPIServerHost, "home-api-server-host", "", "")
+	flags.StringVar(&dr.HomeAPIServerPort, "home-api-server-port", "", "")
+	flags.Var(&labelsNotReflected, "labels-not-reflected", "")
+	flags.Var(&annotationsNotReflected, "annotations-not-reflected", "")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("failed parsing the virtual kubelet arguments: %w", err)
+	}
+
+	// The legacy mode (selected by the virtual kubelets if the TokenRequest API is not available) only
+	// differs in the names of the service account secrets, which do not affect the admission of the ShadowPods.
+	dr.APIServerSupport = forge.APIServerSupportDisabled
+	if enableAPIServerSupport {
+		dr.APIServerSupport = forge.APIServerSupportTokenAPI
+	}
+	dr.ForgingOpts = forge.NewForgingOpts(labelsNotReflected.StringList, annotationsNotReflected.StringList)
+	return nil
+}
+
+// Enabled returns whether the remote dry-run is enabled.
+func (dr *DryRunner) Enabled() bool {
+	return dr != nil && dr.Mode != "" && dr.Mode != DryRunModeDisabled
+}
+
+// Run performs the dry-run of the offloading of the given pod towards the given remote clusters.
+// It returns the reasons of the rejections, keyed by cluster ID.
+// Errors not originated by the remote API servers (e.g., connection errors) are logged and not considered rejections.
+func (dr *DryRunner) Run(ctx context.Context, clusterIDs []string, remoteNamespace string, pod *corev1.Pod) (rejections map[string]string) {
+	rejections = make(map[string]string)
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	for _, clusterID := range clusterIDs {
+		wg.Add(1)
+		go func(clusterID string) {
+			defer wg.Done()
+			if reason, rejected := dr.runForCluster(ctx, clusterID, remoteNamespace, pod); rejected {
+				mutex.Lock()
+				defer mutex.Unlock()
+				rejections[clusterID] = reason
+			}
+		}(clusterID)
+	}
+	wg.Wait()
+
+	return rejections
+}
+
+// runForCluster performs the dry-run towards the given cluster, and returns whether the ShadowPod has been rejected, along with the reason.
+func (dr *DryRunner) runForCluster(ctx context.Context, clusterID, remoteNamespace string, pod *corev1.Pod) (reason string, rejected bool) {
+	if dr.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, dr.Timeout)
+		defer cancel()
+	}
+
+	cl, err := dr.RemoteClients(ctx, clusterID)
+	if err != nil {
+		klog.Warningf("Skipping dry-run of pod %q towards cluster %q: failed retrieving the client: %v", podName(pod), clusterID, err)
+		return "", false
+	}
+
+	shadowpod := dr.forgeShadowPod(pod, clusterID, remoteNamespace)
+	err = cl.Create(ctx, shadowpod, client.DryRunAll)
+	switch {
+	case err == nil:
+		klog.V(4).Infof("Dry-run of pod %q towards cluster %q succeeded", podName(pod), clusterID)
+		return "", false
+	case isRemoteRejection(err):
+		klog.Infof("Dry-run of pod %q towards cluster %q failed: %v", podName(pod), clusterID, err)
+		return err.Error(), true
+	default:
+		klog.Warningf("Skipping dry-run of pod %q towards cluster %q: %v", podName(pod), clusterID, err)
+		return "", false
+	}
+}
+
+// candidateClusters returns the sorted list of the IDs of the remote clusters the given pod might be offloaded to,
+// i.e., those where the remote namespace is ready, possibly restricted by the pod node selector.
+func candidateClusters(ctx context.Context, cl client.Client, nsoff *offv1alpha1.NamespaceOffloading, pod *corev1.Pod) ([]string, error) {
+	// The remote namespace conditions are keyed by the name of the corresponding NamespaceMap.
+	metals := reflection.LocalResourcesLabelSelector()
+	selector, err := metav1.LabelSelectorAsSelector(&metals)
+	utilruntime.Must(err)
+
+	var nsmaps vkv1alpha1.NamespaceMapList
+	if err := cl.List(ctx, &nsmaps, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("failed retrieving NamespaceMaps: %w", err)
+	}
+
+	var clusterIDs []string
+	for i := range nsmaps.Items {
+		clusterID := nsmaps.Items[i].Labels[liqoconst.RemoteClusterID]
+		if target, found := pod.Spec.NodeSelector[liqoconst.RemoteClusterID]; found && target != clusterID {
+			continue
+		}
+
+		conditions := nsoff.Status.RemoteNamespacesConditions[nsmaps.Items[i].Name]
+		for j := range conditions {
+			if conditions[j].Type == offv1alpha1.NamespaceReady && conditions[j].Status == corev1.ConditionTrue {
+				clusterIDs = append(clusterIDs, clusterID)
+				break
+			}
+		}
+	}
+	sort.Strings(clusterIDs)
+	return clusterIDs, nil
+}
+
+// forgeShadowPod forges the ShadowPod which would be created in the given remote cluster upon offloading the given pod,
+// through the same logic of the virtual kubelet. The information it retrieves at runtime (i.e., the names of the service
+// account secrets and the remapped IP of the kubernetes service) is replaced by placeholders, not affecting the admission.
+func (dr *DryRunner) forgeShadowPod(pod *corev1.Pod, remoteClusterID, remoteNamespace string) *vkv1alpha1.ShadowPod {
+	apiServerSupport := dr.APIServerSupport
+	if apiServerSupport == "" {
+		apiServerSupport = forge.APIServerSupportDisabled
+	}
+
+	mutators := forge.APIServerSupportMutators(pod, apiServerSupport,
+		func(string) string { return forge.ServiceAccountSecretName(podName(pod)) },
+		func() string { return dryRunKubernetesServiceIP },
+		dr.HomeAPIServerHost, dr.HomeAPIServerPort)
+
+	shadowpod := forge.RemoteShadowPod(pod, nil, remoteNamespace, &dr.ForgingOpts, mutators...)
+	shadowpod.SetGenerateName(pod.GetGenerateName())
+
+	// The reflection labels are set according to the given clusters, as the virtual node name is not known here.
+	delete(shadowpod.Labels, forge.LiqoOriginClusterNodeName)
+	shadowpod.Labels[forge.LiqoOriginClusterIDKey] = dr.LocalClusterID
+	shadowpod.Labels[forge.LiqoDestinationClusterIDKey] = remoteClusterID
+	return shadowpod
+}
+
+// isRemoteRejection returns whether the given error corresponds to the remote API server rejecting the pod itself
+// (e.g., due to quotas, admission policies or validation). Other errors (e.g., the remote namespace not existing yet)
+// are not related to the pod, and they shall not prevent its admission.
+func isRemoteRejection(err error) bool {
+	return apierrors.IsForbidden(err) || apierrors.IsInvalid(err)
+}
+
+// formatRejections returns a human readable representation of the given rejections.
+func formatRejections(rejections map[string]string) string {
+	clusterIDs := make([]string, 0, len(rejections))
+	for clusterID := range rejections {
+		clusterIDs = append(clusterIDs, clusterID)
+	}
+	sort.Strings(clusterIDs)
+
+	messages := make([]string, 0, len(clusterIDs))
+	for _, clusterID := range clusterIDs {
+		messages = append(messages, fmt.Sprintf("%s: %s", clusterID, rejections[clusterID]))
+	}
+	return strings.Join(messages, "; ")
+}
+
+// podName returns the name of the pod for logging purposes, falling back to the generate name if not yet set.
+func podName(pod *corev1.Pod) string {
+	if pod.GetName() != "" {
+		return pod.GetName()
+	}
+	return pod.GetGenerateName()
+}
+
+// NewRemoteClientGetter returns a RemoteClientGetter retrieving the identities to interact with the remote clusters
+// through the given identity reader. Clients are cached, and recreated only in case the corresponding identity changes.
+func NewRemoteClientGetter(cl client.Client, idReader identitymanager.IdentityReader) RemoteClientGetter {
+	type cachedClient struct {
+		config *rest.Config
+		client client.Client
+	}
+
+	var mutex sync.Mutex
+	cache := make(map[string]cachedClient)
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(vkv1alpha1.AddToScheme(scheme))
+
+	return func(ctx context.Context, clusterID string) (client.Client, error) {
+		fc, err := foreignclusterutils.GetForeignClusterByID(ctx, cl, clusterID)
+		if err != nil {
+			return nil, fmt.Errorf("failed retrieving foreign cluster: %w", err)
+		}
+
+		config, err := idReader.GetConfig(fc.Spec.ClusterIdentity, fc.Status.TenantNamespace.Local)
+		if err != nil {
+			return nil, fmt.Errorf("failed retrieving identity: %w", err)
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		if cached, found := cache[clusterID]; found && sameIdentity(cached.config, config) {
+			return cached.client, nil
+		}
+
+		remote, err := client.New(config, client.Options{Scheme: scheme})
+		if err != nil {
+			return nil, fmt.Errorf("failed creating client: %w", err)
+		}
+		cache[clusterID] = cachedClient{config: config, client: remote}
+		return remote, nil
+	}
+}
+
+// sameIdentity returns whether the two configurations refer to the same server with the same credentials.
+func sameIdentity(first, second *rest.Config) bool {
+	return first.Host == second.Host && first.BearerToken == second.BearerToken &&
+		reflect.DeepEqual(first.TLSClientConfig, second.TLSClientConfig)
+}
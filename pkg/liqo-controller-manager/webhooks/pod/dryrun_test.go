@@ -0,0 +1,249 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	offv1alpha1 "github.com/liqotech/liqo/apis/offloading/v1alpha1"
+	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+)
+
+var _ = Describe("Remote dry-run", func() {
+	const (
+		localClusterID  = "local-cluster"
+		remoteNamespace = "remote-namespace"
+	)
+
+	var (
+		ctx       context.Context
+		cl        client.Client
+		nsoff     *offv1alpha1.NamespaceOffloading
+		pod       *corev1.Pod
+		outcomes  map[string]error
+		received  map[string]*vkv1alpha1.ShadowPod
+		mutex     sync.Mutex
+		dryRunner *DryRunner
+	)
+
+	readyCondition := func(status corev1.ConditionStatus) offv1alpha1.RemoteNamespaceConditions {
+		return offv1alpha1.RemoteNamespaceConditions{{Type: offv1alpha1.NamespaceReady, Status: status}}
+	}
+
+	forbidden := func(reason string) error {
+		return apierrors.NewForbidden(schema.GroupResource{Resource: "shadowpods"}, "", errors.New(reason))
+	}
+
+	namespaceMap := func(clusterID string) *vkv1alpha1.NamespaceMap {
+		return &vkv1alpha1.NamespaceMap{ObjectMeta: metav1.ObjectMeta{
+			Name: clusterID + "-map", Namespace: "liqo-tenant-" + clusterID,
+			Labels: map[string]string{
+				liqoconst.RemoteClusterID:             clusterID,
+				liqoconst.ReplicationRequestedLabel:   liqoconst.ReplicationRequestedLabelValue,
+				liqoconst.ReplicationDestinationLabel: clusterID,
+			},
+		}}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		utilruntime.Must(vkv1alpha1.AddToScheme(scheme))
+		cl = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			namespaceMap("cluster-1"), namespaceMap("cluster-2"), namespaceMap("cluster-3"), namespaceMap("unreachable"),
+		).Build()
+
+		nsoff = &offv1alpha1.NamespaceOffloading{
+			Spec: offv1alpha1.NamespaceOffloadingSpec{PodOffloadingStrategy: offv1alpha1.RemotePodOffloadingStrategyType},
+			Status: offv1alpha1.NamespaceOffloadingStatus{
+				RemoteNamespaceName: remoteNamespace,
+				RemoteNamespacesConditions: map[string]offv1alpha1.RemoteNamespaceConditions{
+					"cluster-1-map": readyCondition(corev1.ConditionTrue),
+					"cluster-2-map": readyCondition(corev1.ConditionTrue),
+					"cluster-3-map": readyCondition(corev1.ConditionFalse),
+				},
+			},
+		}
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "pod-",
+				Labels:       map[string]string{"app": "foo", liqoconst.LocalPodLabelKey: "true"},
+			},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "foo", Image: "foo"}}},
+		}
+
+		outcomes = map[string]error{}
+		received = map[string]*vkv1alpha1.ShadowPod{}
+		dryRunner = &DryRunner{
+			Mode:           DryRunModeReject,
+			LocalClusterID: localClusterID,
+			RemoteClients: func(_ context.Context, clusterID string) (client.Client, error) {
+				if clusterID == "unreachable" {
+					return nil, errors.New("unreachable")
+				}
+				scheme := runtime.NewScheme()
+				utilruntime.Must(vkv1alpha1.AddToScheme(scheme))
+				return fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+					Create: func(_ context.Context, _ client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+						options := client.CreateOptions{}
+						options.ApplyOptions(opts)
+						if len(options.DryRun) != 1 || options.DryRun[0] != metav1.DryRunAll {
+							return errors.New("not a dry-run request")
+						}
+
+						mutex.Lock()
+						defer mutex.Unlock()
+						received[clusterID] = obj.(*vkv1alpha1.ShadowPod)
+						return outcomes[clusterID]
+					},
+				}).Build(), nil
+			},
+		}
+	})
+
+	It("should select the clusters where the remote namespace is ready", func() {
+		Expect(candidateClusters(ctx, cl, nsoff, pod)).To(Equal([]string{"cluster-1", "cluster-2"}))
+	})
+
+	It("should restrict the candidates to the cluster selected by the pod", func() {
+		pod.Spec.NodeSelector = map[string]string{liqoconst.RemoteClusterID: "cluster-2"}
+		Expect(candidateClusters(ctx, cl, nsoff, pod)).To(Equal([]string{"cluster-2"}))
+	})
+
+	It("should dry-run the ShadowPod in the given clusters", func() {
+		rejections := dryRunner.Run(ctx, []string{"cluster-1", "cluster-2"}, remoteNamespace, pod)
+		Expect(rejections).To(BeEmpty())
+		Expect(received).To(HaveLen(2))
+
+		shadowpod := received["cluster-1"]
+		Expect(shadowpod.GenerateName).To(Equal("pod-"))
+		Expect(shadowpod.Namespace).To(Equal(remoteNamespace))
+		Expect(shadowpod.Labels).To(Equal(map[string]string{
+			"app":                             "foo",
+			forge.LiqoOriginClusterIDKey:      localClusterID,
+			forge.LiqoDestinationClusterIDKey: "cluster-1",
+		}))
+		Expect(shadowpod.Spec.Pod.Containers).To(Equal(pod.Spec.Containers))
+	})
+
+	It("should configure the forging according to the virtual kubelet arguments", func() {
+		Expect(dryRunner.ConfigureFromVirtualKubeletArgs([]string{"--enable-apiserver-support=true", "--home-api-server-host=foo.bar",
+			"--labels-not-reflected=app", "--certificate-type=aws"})).To(Succeed())
+		Expect(dryRunner.APIServerSupport).To(Equal(forge.APIServerSupportTokenAPI))
+		Expect(dryRunner.HomeAPIServerHost).To(Equal("foo.bar"))
+		Expect(dryRunner.ForgingOpts.LabelsNotReflected).To(ConsistOf("app"))
+
+		Expect(dryRunner.ConfigureFromVirtualKubeletArgs([]string{"--enable-apiserver-support=false"})).To(Succeed())
+		Expect(dryRunner.APIServerSupport).To(Equal(forge.APIServerSupportDisabled))
+	})
+
+	It("should forge the ShadowPod with the API server support mutations of the virtual kubelet", func() {
+		dryRunner.APIServerSupport = forge.APIServerSupportTokenAPI
+		pod.Spec.ServiceAccountName = "foo"
+		pod.Spec.Volumes = []corev1.Volume{{Name: forge.ServiceAccountVolumeName + "abcde", VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{
+				{ServiceAccountToken: &corev1.ServiceAccountTokenProjection{Path: "token"}},
+			}},
+		}}}
+
+		Expect(dryRunner.Run(ctx, []string{"cluster-1"}, remoteNamespace, pod)).To(BeEmpty())
+		shadowpod := received["cluster-1"]
+		Expect(shadowpod.Spec.Pod.ServiceAccountName).To(BeEmpty())
+		Expect(shadowpod.Spec.Pod.Volumes).To(HaveLen(1))
+		Expect(shadowpod.Spec.Pod.Volumes[0].Projected.Sources[0].ServiceAccountToken).To(BeNil())
+		Expect(shadowpod.Spec.Pod.Volumes[0].Projected.Sources[0].Secret).ToNot(BeNil())
+		Expect(shadowpod.Spec.Pod.HostAliases).ToNot(BeEmpty())
+	})
+
+	It("should report only the rejections originated by the remote API servers", func() {
+		outcomes["cluster-1"] = forbidden("exceeded quota")
+		outcomes["cluster-2"] = errors.New("connection refused")
+
+		rejections := dryRunner.Run(ctx, []string{"cluster-1", "cluster-2", "unreachable"}, remoteNamespace, pod)
+		Expect(rejections).To(HaveLen(1))
+		Expect(rejections).To(HaveKeyWithValue("cluster-1", ContainSubstring("exceeded quota")))
+	})
+
+	It("should not report the errors unrelated to the pod itself", func() {
+		outcomes["cluster-1"] = apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, remoteNamespace)
+		outcomes["cluster-2"] = apierrors.NewAlreadyExists(schema.GroupResource{Resource: "shadowpods"}, "pod-foo")
+
+		rejections := dryRunner.Run(ctx, []string{"cluster-1", "cluster-2"}, remoteNamespace, pod)
+		Expect(rejections).To(BeEmpty())
+	})
+
+	It("should report the validation errors", func() {
+		outcomes["cluster-1"] = apierrors.NewInvalid(schema.GroupKind{Kind: "ShadowPod"}, "pod-foo", nil)
+
+		rejections := dryRunner.Run(ctx, []string{"cluster-1"}, remoteNamespace, pod)
+		Expect(rejections).To(HaveKey("cluster-1"))
+	})
+
+	Describe("The webhook", func() {
+		var wh *podwh
+
+		BeforeEach(func() { wh = &podwh{client: cl, dryRunner: dryRunner} })
+
+		It("should annotate the pod in case only some clusters reject it", func() {
+			outcomes["cluster-1"] = forbidden("exceeded quota")
+			denied, _ := wh.dryRun(ctx, nsoff, pod)
+			Expect(denied).To(BeFalse())
+			Expect(pod.Annotations).To(HaveKeyWithValue(liqoconst.RemoteDryRunRejectionsAnnotation,
+				And(HavePrefix("cluster-1: "), ContainSubstring("exceeded quota"))))
+		})
+
+		It("should reject the pod in case all clusters reject it", func() {
+			outcomes["cluster-1"] = forbidden("exceeded quota")
+			outcomes["cluster-2"] = forbidden("violates PodSecurity")
+			denied, reason := wh.dryRun(ctx, nsoff, pod)
+			Expect(denied).To(BeTrue())
+			Expect(reason).To(And(ContainSubstring("exceeded quota"), ContainSubstring("violates PodSecurity")))
+		})
+
+		It("should only annotate the pod if it can be executed locally", func() {
+			nsoff.Spec.PodOffloadingStrategy = offv1alpha1.LocalAndRemotePodOffloadingStrategyType
+			outcomes["cluster-1"] = forbidden("exceeded quota")
+			outcomes["cluster-2"] = forbidden("violates PodSecurity")
+			denied, _ := wh.dryRun(ctx, nsoff, pod)
+			Expect(denied).To(BeFalse())
+			Expect(pod.Annotations).To(HaveKey(liqoconst.RemoteDryRunRejectionsAnnotation))
+		})
+
+		It("should only annotate the pod in annotate mode", func() {
+			dryRunner.Mode = DryRunModeAnnotate
+			outcomes["cluster-1"] = forbidden("exceeded quota")
+			outcomes["cluster-2"] = forbidden("violates PodSecurity")
+			denied, _ := wh.dryRun(ctx, nsoff, pod)
+			Expect(denied).To(BeFalse())
+			Expect(pod.Annotations).To(HaveKey(liqoconst.RemoteDryRunRejectionsAnnotation))
+		})
+	})
+})
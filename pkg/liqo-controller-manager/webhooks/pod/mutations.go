@@ -16,6 +16,7 @@ package pod
 
 import (
 	"fmt"
+	"sort"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
@@ -34,12 +35,13 @@ func getVirtualNodeToleration() corev1.Toleration {
 	}
 }
 
-// createTolerationFromNamespaceOffloading creates a new virtualNodeToleration in case of LocalAndRemotePodOffloadingStrategyType
-// or RemotePodOffloadingStrategyType. In case of PodOffloadingStrategyType not recognized, returns an error.
+// createTolerationFromNamespaceOffloading creates a new virtualNodeToleration in case of LocalAndRemotePodOffloadingStrategyType,
+// RemotePodOffloadingStrategyType or WeightedPodOffloadingStrategyType. In case of PodOffloadingStrategyType not recognized, returns an error.
 func createTolerationFromNamespaceOffloading(strategy offv1alpha1.PodOffloadingStrategyType) (corev1.Toleration, error) {
 	var toleration corev1.Toleration
 	switch {
-	case strategy == offv1alpha1.LocalAndRemotePodOffloadingStrategyType, strategy == offv1alpha1.RemotePodOffloadingStrategyType:
+	case strategy == offv1alpha1.LocalAndRemotePodOffloadingStrategyType, strategy == offv1alpha1.RemotePodOffloadingStrategyType,
+		strategy == offv1alpha1.WeightedPodOffloadingStrategyType:
 		// The virtual-node toleration must be added.
 		toleration = getVirtualNodeToleration()
 	default:
@@ -87,6 +89,9 @@ func createNodeSelectorFromNamespaceOffloading(nsoff *offv1alpha1.NamespaceOfflo
 		}
 		nodeSelector.NodeSelectorTerms = append(nodeSelector.NodeSelectorTerms, newNodeSelectorTerm)
 
+	case nsoff.Spec.PodOffloadingStrategy == offv1alpha1.WeightedPodOffloadingStrategyType:
+		return createWeightedNodeSelector(nsoff)
+
 	default:
 		err := fmt.Errorf("PodOffloadingStrategyType '%s' not recognized", nsoff.Spec.PodOffloadingStrategy)
 		klog.Error(err)
@@ -95,6 +100,60 @@ func createNodeSelectorFromNamespaceOffloading(nsoff *offv1alpha1.NamespaceOfflo
 	return &nodeSelector, nil
 }
 
+// createWeightedNodeSelector creates the NodeSelector allowing pods to be scheduled only on the local nodes and on the virtual
+// nodes matching the ClusterSelector, in both cases provided that the corresponding cluster has been assigned a positive weight.
+func createWeightedNodeSelector(nsoff *offv1alpha1.NamespaceOffloading) (*corev1.NodeSelector, error) {
+	weights := nsoff.Spec.Weights
+	if weights == nil {
+		weights = &offv1alpha1.OffloadingWeights{}
+	}
+
+	var clusterIDs []string
+	for clusterID, weight := range weights.Remote {
+		if weight > 0 {
+			clusterIDs = append(clusterIDs, clusterID)
+		}
+	}
+	sort.Strings(clusterIDs)
+
+	if weights.Local <= 0 && len(clusterIDs) == 0 {
+		err := fmt.Errorf("no cluster assigned a positive weight")
+		klog.Error(err)
+		return nil, err
+	}
+
+	nodeSelector := &corev1.NodeSelector{}
+	if len(clusterIDs) > 0 {
+		// Every NodeSelectorTerm is restricted to the remote clusters with a positive weight. The requirement on the
+		// cluster ID label implicitly excludes the local nodes, which are allowed by the dedicated term below.
+		nodeSelector.NodeSelectorTerms = nsoff.Spec.ClusterSelector.DeepCopy().NodeSelectorTerms
+		if len(nodeSelector.NodeSelectorTerms) == 0 {
+			nodeSelector.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+		}
+
+		for i := range nodeSelector.NodeSelectorTerms {
+			nodeSelector.NodeSelectorTerms[i].MatchExpressions = append(nodeSelector.NodeSelectorTerms[i].MatchExpressions,
+				corev1.NodeSelectorRequirement{
+					Key:      liqoconst.RemoteClusterID,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   clusterIDs,
+				})
+		}
+	}
+
+	if weights.Local > 0 {
+		nodeSelector.NodeSelectorTerms = append(nodeSelector.NodeSelectorTerms, corev1.NodeSelectorTerm{
+			MatchExpressions: []corev1.NodeSelectorRequirement{{
+				Key:      liqoconst.TypeLabel,
+				Operator: corev1.NodeSelectorOpNotIn,
+				Values:   []string{liqoconst.TypeNode},
+			}},
+		})
+	}
+
+	return nodeSelector, nil
+}
+
 // fillPodWithTheNewNodeSelector gets the previously computed NodeSelector imposed by the PodOffloadingStrategy and
 // merges it with the Pod NodeSelector if it is already present. It simply adds it to the Pod if previously unset.
 func fillPodWithTheNewNodeSelector(imposedNodeSelector *corev1.NodeSelector, pod *corev1.Pod) {
@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 
 	corev1 "k8s.io/api/core/v1"
@@ -34,17 +35,29 @@ import (
 
 // cluster-role
 // +kubebuilder:rbac:groups=offloading.liqo.io,resources=namespaceoffloadings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=virtualkubelet.liqo.io,resources=namespacemaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=pods;nodes,verbs=get;list;watch
 
 type podwh struct {
-	client  client.Client
-	decoder *admission.Decoder
+	client          client.Client
+	localPodsClient client.Client
+	admittedPods    client.Reader
+	decoder         *admission.Decoder
+	dryRunner       *DryRunner
 }
 
-// New returns a new PodWebhook instance.
-func New(cl client.Client) *webhook.Admission {
+// New returns a new PodWebhook instance. The local pods client and the admitted pods reader are leveraged to account
+// the resources consumed by the offloaded pods and by the ones admitted but not yet offloaded, to enforce the offloading
+// quotas. The latter is additionally leveraged to retrieve the pods of a namespace, to spread them according to the
+// configured weights, and it is expected to be backed by a cache. The dry-runner, if not nil, is leveraged to
+// verify up front whether the remote clusters would accept the offloading of the pods.
+func New(cl, localPodsClient client.Client, admittedPods client.Reader, dryRunner *DryRunner) *webhook.Admission {
 	return &webhook.Admission{Handler: &podwh{
-		client:  cl,
-		decoder: admission.NewDecoder(runtime.NewScheme()),
+		client:          cl,
+		localPodsClient: localPodsClient,
+		admittedPods:    admittedPods,
+		decoder:         admission.NewDecoder(runtime.NewScheme()),
+		dryRunner:       dryRunner,
 	}}
 }
 
@@ -91,5 +104,63 @@ func (w *podwh) Handle(ctx context.Context, req admission.Request) admission.Res
 		return admission.Errored(http.StatusInternalServerError, errors.New("failed constructing pod mutation"))
 	}
 
+	// Label the pod as admitted, to account for it in the upcoming decisions until actually offloaded.
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	pod.Labels[liqoconst.AdmittedPodLabelKey] = liqoconst.AdmittedPodLabelValue
+
+	if nsoff.Spec.PodOffloadingStrategy == offv1alpha1.WeightedPodOffloadingStrategyType {
+		// Spreading is best-effort, hence the pod is admitted even if it fails.
+		if err := w.spreadPod(ctx, nsoff, pod); err != nil {
+			klog.Warningf("Failed spreading pod %q according to the configured weights: %v", podName(pod), err)
+		}
+	}
+
+	if nsoff.Spec.Quota != nil && nsoff.Spec.PodOffloadingStrategy != offv1alpha1.LocalPodOffloadingStrategyType {
+		denied, reason, err := w.enforceQuota(ctx, nsoff, pod)
+		if err != nil {
+			klog.Errorf("Failed enforcing the offloading quota for pod %q: %v", podName(pod), err)
+			return admission.Errored(http.StatusInternalServerError, errors.New("failed enforcing the offloading quota"))
+		}
+		if denied {
+			return admission.Denied(reason)
+		}
+	}
+
+	if w.dryRunner.Enabled() && nsoff.Spec.PodOffloadingStrategy != offv1alpha1.LocalPodOffloadingStrategyType {
+		if denied, reason := w.dryRun(ctx, nsoff, pod); denied {
+			return admission.Denied(reason)
+		}
+	}
+
 	return w.CreatePatchResponse(&req, pod)
 }
+
+// dryRun performs the remote dry-run of the offloading of the pod, and annotates it with the reasons of the rejections, if any.
+// It returns whether the pod shall be denied, i.e., the reject mode is enabled, the pod can be executed only remotely,
+// and all candidate remote clusters rejected it.
+func (w *podwh) dryRun(ctx context.Context, nsoff *offv1alpha1.NamespaceOffloading, pod *corev1.Pod) (denied bool, reason string) {
+	candidates, err := candidateClusters(ctx, w.client, nsoff, pod)
+	if err != nil {
+		klog.Warningf("Skipping dry-run of pod %q: %v", podName(pod), err)
+		return false, ""
+	}
+
+	rejections := w.dryRunner.Run(ctx, candidates, nsoff.Status.RemoteNamespaceName, pod)
+	if len(rejections) == 0 {
+		return false, ""
+	}
+
+	reason = formatRejections(rejections)
+	if w.dryRunner.Mode == DryRunModeReject && len(rejections) == len(candidates) &&
+		nsoff.Spec.PodOffloadingStrategy == offv1alpha1.RemotePodOffloadingStrategyType {
+		return true, fmt.Sprintf("the pod would be rejected by all remote clusters: %s", reason)
+	}
+
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[liqoconst.RemoteDryRunRejectionsAnnotation] = reason
+	return false, ""
+}
@@ -64,6 +64,7 @@ var _ = Describe("Webhook", func() {
 			Entry("LocalPodOffloadingStrategyType", offv1alpha1.LocalPodOffloadingStrategyType, emptyToleration),
 			Entry("RemotePodOffloadingStrategyType", offv1alpha1.RemotePodOffloadingStrategyType, virtualNodeToleration),
 			Entry("LocalAndRemotePodOffloadingStrategyType", offv1alpha1.LocalAndRemotePodOffloadingStrategyType, virtualNodeToleration),
+			Entry("WeightedPodOffloadingStrategyType", offv1alpha1.WeightedPodOffloadingStrategyType, virtualNodeToleration),
 		)
 	})
 
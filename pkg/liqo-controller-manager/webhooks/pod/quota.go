@@ -0,0 +1,132 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	offv1alpha1 "github.com/liqotech/liqo/apis/offloading/v1alpha1"
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	offloadingutils "github.com/liqotech/liqo/pkg/utils/offloading"
+	podutils "github.com/liqotech/liqo/pkg/utils/pod"
+)
+
+// enforceQuota verifies whether offloading the pod would exceed the quota configured in the NamespaceOffloading,
+// and restricts its node affinity to exclude the remote clusters which would be exceeded. It returns whether the
+// pod shall be denied, i.e., it can be executed only remotely, and no candidate remote cluster has enough quota left.
+// Admitted pods are annotated with the remote clusters they may be offloaded to, to account for them until scheduled.
+func (w *podwh) enforceQuota(ctx context.Context, nsoff *offv1alpha1.NamespaceOffloading, pod *corev1.Pod) (denied bool, reason string, err error) {
+	candidates, err := candidateClusters(ctx, w.client, nsoff, pod)
+	if err != nil {
+		return false, "", err
+	}
+
+	usage, err := offloadingutils.QuotaUsage(ctx, w.localPodsClient, w.admittedPods, nsoff.Namespace)
+	if err != nil {
+		return false, "", err
+	}
+
+	// Limits are not validated, as CheckQuota verifies that all the resources subject to a quota are specified.
+	requested, err := podutils.QuotaFromPodSpec(&pod.Spec, false)
+	if err != nil {
+		return false, "", err
+	}
+
+	exceeded := make(map[string]string)
+	if err := offloadingutils.CheckQuota(nsoff.Spec.Quota.Aggregate, usage.Aggregate, requested); err != nil {
+		// The aggregate quota is exceeded, hence the pod cannot be offloaded to any remote cluster.
+		for _, clusterID := range candidates {
+			exceeded[clusterID] = err.Error()
+		}
+	} else {
+		for _, clusterID := range candidates {
+			limits, found := nsoff.Spec.Quota.PerCluster[clusterID]
+			if !found {
+				continue
+			}
+			if err := offloadingutils.CheckQuota(limits, usage.PerCluster[clusterID], requested); err != nil {
+				exceeded[clusterID] = err.Error()
+			}
+		}
+	}
+
+	targets := make([]string, 0, len(candidates))
+	for _, clusterID := range candidates {
+		if _, found := exceeded[clusterID]; !found {
+			targets = append(targets, clusterID)
+		}
+	}
+	if len(targets) > 0 {
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[liqoconst.QuotaTargetsAnnotation] = strings.Join(targets, ",")
+	}
+
+	if len(exceeded) == 0 {
+		return false, "", nil
+	}
+
+	reason = formatRejections(exceeded)
+	klog.Infof("Pod %q would exceed the offloading quota: %s", podName(pod), reason)
+	if len(exceeded) == len(candidates) {
+		if nsoff.Spec.PodOffloadingStrategy == offv1alpha1.RemotePodOffloadingStrategyType {
+			return true, fmt.Sprintf("the pod would exceed the offloading quota of all remote clusters: %s", reason), nil
+		}
+
+		// The pod can still be executed locally, hence let exclude all virtual nodes.
+		excludeFromNodeAffinity(pod, liqoconst.TypeLabel, []string{liqoconst.TypeNode})
+		return false, "", nil
+	}
+
+	clusterIDs := make([]string, 0, len(exceeded))
+	for clusterID := range exceeded {
+		clusterIDs = append(clusterIDs, clusterID)
+	}
+	sort.Strings(clusterIDs)
+	excludeFromNodeAffinity(pod, liqoconst.RemoteClusterID, clusterIDs)
+	return false, "", nil
+}
+
+// excludeFromNodeAffinity adds to every required NodeSelectorTerm of the pod a NodeSelectorRequirement
+// excluding the nodes whose label with the given key matches any of the given values.
+func excludeFromNodeAffinity(pod *corev1.Pod, key string, values []string) {
+	requirement := corev1.NodeSelectorRequirement{Key: key, Operator: corev1.NodeSelectorOpNotIn, Values: values}
+	klog.V(5).Infof("Restricting the node affinity of pod %q with requirement %s NotIn %s", podName(pod), key, strings.Join(values, ","))
+
+	if pod.Spec.Affinity == nil {
+		pod.Spec.Affinity = &corev1.Affinity{}
+	}
+	if pod.Spec.Affinity.NodeAffinity == nil {
+		pod.Spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	if pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+
+	selector := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(selector.NodeSelectorTerms) == 0 {
+		selector.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+	}
+	for i := range selector.NodeSelectorTerms {
+		selector.NodeSelectorTerms[i].MatchExpressions = append(selector.NodeSelectorTerms[i].MatchExpressions, requirement)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	offv1alpha1 "github.com/liqotech/liqo/apis/offloading/v1alpha1"
+	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+)
+
+var _ = Describe("Offloading quota", func() {
+	const namespace = "foo"
+
+	var (
+		ctx   context.Context
+		wh    *podwh
+		nsoff *offv1alpha1.NamespaceOffloading
+		pod   *corev1.Pod
+	)
+
+	cpu := func(quantity string) corev1.ResourceList {
+		return corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(quantity)}
+	}
+
+	namespaceMap := func(clusterID string) *vkv1alpha1.NamespaceMap {
+		return &vkv1alpha1.NamespaceMap{ObjectMeta: metav1.ObjectMeta{
+			Name: clusterID + "-map", Namespace: "liqo-tenant-" + clusterID,
+			Labels: map[string]string{
+				liqoconst.RemoteClusterID:             clusterID,
+				liqoconst.ReplicationRequestedLabel:   liqoconst.ReplicationRequestedLabelValue,
+				liqoconst.ReplicationDestinationLabel: clusterID,
+			},
+		}}
+	}
+
+	virtualNode := func(clusterID string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "liqo-" + clusterID, Labels: map[string]string{
+			liqoconst.TypeLabel: liqoconst.TypeNode, liqoconst.RemoteClusterID: clusterID,
+		}}}
+	}
+
+	offloadedPod := func(name, clusterID, limit string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace,
+				Labels: map[string]string{liqoconst.LocalPodLabelKey: liqoconst.LocalPodLabelValue}},
+			Spec: corev1.PodSpec{NodeName: "liqo-" + clusterID, Containers: []corev1.Container{
+				{Name: "foo", Resources: corev1.ResourceRequirements{Limits: cpu(limit)}},
+			}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+
+	requirements := func() []corev1.NodeSelectorRequirement {
+		return pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		utilruntime.Must(vkv1alpha1.AddToScheme(scheme))
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(namespaceMap("cluster-1"), namespaceMap("cluster-2")).Build()
+		localPodsClient := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(
+			virtualNode("cluster-1"), virtualNode("cluster-2"),
+			offloadedPod("first", "cluster-1", "2"), offloadedPod("second", "cluster-2", "1"),
+		).Build()
+		wh = &podwh{client: cl, localPodsClient: localPodsClient, admittedPods: localPodsClient}
+
+		ready := offv1alpha1.RemoteNamespaceConditions{{Type: offv1alpha1.NamespaceReady, Status: corev1.ConditionTrue}}
+		nsoff = &offv1alpha1.NamespaceOffloading{
+			ObjectMeta: metav1.ObjectMeta{Name: liqoconst.DefaultNamespaceOffloadingName, Namespace: namespace},
+			Spec: offv1alpha1.NamespaceOffloadingSpec{
+				PodOffloadingStrategy: offv1alpha1.LocalAndRemotePodOffloadingStrategyType,
+				Quota: &offv1alpha1.OffloadingQuota{
+					Aggregate:  cpu("5"),
+					PerCluster: map[string]corev1.ResourceList{"cluster-1": cpu("3")},
+				},
+			},
+			Status: offv1alpha1.NamespaceOffloadingStatus{
+				RemoteNamespacesConditions: map[string]offv1alpha1.RemoteNamespaceConditions{"cluster-1-map": ready, "cluster-2-map": ready},
+			},
+		}
+		pod = &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: "foo", Resources: corev1.ResourceRequirements{Limits: cpu("1")}},
+		}}}
+	})
+
+	It("should not modify the pod if within the quota", func() {
+		denied, _, err := wh.enforceQuota(ctx, nsoff, pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(denied).To(BeFalse())
+		Expect(pod.Spec.Affinity).To(BeNil())
+		Expect(pod.Annotations).To(HaveKeyWithValue(liqoconst.QuotaTargetsAnnotation, "cluster-1,cluster-2"))
+	})
+
+	It("should account the admitted pods not yet offloaded", func() {
+		pending := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: namespace,
+				Labels:      map[string]string{liqoconst.AdmittedPodLabelKey: liqoconst.AdmittedPodLabelValue},
+				Annotations: map[string]string{liqoconst.QuotaTargetsAnnotation: "cluster-1"}},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: "foo", Resources: corev1.ResourceRequirements{Limits: cpu("1")}},
+			}},
+			Status: corev1.PodStatus{Phase: corev1.PodPending},
+		}
+		Expect(wh.localPodsClient.Create(ctx, pending)).To(Succeed())
+
+		denied, _, err := wh.enforceQuota(ctx, nsoff, pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(denied).To(BeFalse())
+		Expect(requirements()).To(ConsistOf(corev1.NodeSelectorRequirement{
+			Key: liqoconst.RemoteClusterID, Operator: corev1.NodeSelectorOpNotIn, Values: []string{"cluster-1"},
+		}))
+		Expect(pod.Annotations).To(HaveKeyWithValue(liqoconst.QuotaTargetsAnnotation, "cluster-2"))
+	})
+
+	It("should exclude the clusters whose quota would be exceeded", func() {
+		pod.Spec.Containers[0].Resources.Limits = cpu("2")
+		denied, _, err := wh.enforceQuota(ctx, nsoff, pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(denied).To(BeFalse())
+		Expect(requirements()).To(ConsistOf(corev1.NodeSelectorRequirement{
+			Key: liqoconst.RemoteClusterID, Operator: corev1.NodeSelectorOpNotIn, Values: []string{"cluster-1"},
+		}))
+	})
+
+	It("should exclude all virtual nodes if the aggregate quota would be exceeded", func() {
+		pod.Spec.Containers[0].Resources.Limits = cpu("3")
+		denied, _, err := wh.enforceQuota(ctx, nsoff, pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(denied).To(BeFalse())
+		Expect(requirements()).To(ConsistOf(corev1.NodeSelectorRequirement{
+			Key: liqoconst.TypeLabel, Operator: corev1.NodeSelectorOpNotIn, Values: []string{liqoconst.TypeNode},
+		}))
+		Expect(pod.Annotations).ToNot(HaveKey(liqoconst.QuotaTargetsAnnotation))
+	})
+
+	It("should deny the pod if it cannot be executed locally, and no cluster has enough quota left", func() {
+		nsoff.Spec.PodOffloadingStrategy = offv1alpha1.RemotePodOffloadingStrategyType
+		pod.Spec.Containers[0].Resources.Limits = cpu("3")
+		denied, reason, err := wh.enforceQuota(ctx, nsoff, pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(denied).To(BeTrue())
+		Expect(reason).To(ContainSubstring("cpu offloading quota exceeded"))
+	})
+
+	It("should exclude the clusters subject to a quota if the pod does not specify the corresponding limits", func() {
+		pod.Spec.Containers[0].Resources.Limits = nil
+		nsoff.Spec.Quota.Aggregate = nil
+		denied, _, err := wh.enforceQuota(ctx, nsoff, pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(denied).To(BeFalse())
+		Expect(requirements()).To(ConsistOf(corev1.NodeSelectorRequirement{
+			Key: liqoconst.RemoteClusterID, Operator: corev1.NodeSelectorOpNotIn, Values: []string{"cluster-1"},
+		}))
+	})
+
+	It("should preserve the existing node selector terms", func() {
+		pod.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "foo", Operator: corev1.NodeSelectorOpExists}}},
+				{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "bar", Operator: corev1.NodeSelectorOpExists}}},
+			}},
+		}}
+		excludeFromNodeAffinity(pod, liqoconst.RemoteClusterID, []string{"cluster-1"})
+		for _, term := range pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+			Expect(term.MatchExpressions).To(HaveLen(2))
+			Expect(term.MatchExpressions[1].Key).To(Equal(liqoconst.RemoteClusterID))
+		}
+	})
+})
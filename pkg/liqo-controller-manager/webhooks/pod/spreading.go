@@ -0,0 +1,117 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	offv1alpha1 "github.com/liqotech/liqo/apis/offloading/v1alpha1"
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	offloadingutils "github.com/liqotech/liqo/pkg/utils/offloading"
+)
+
+// spreadingPreferenceWeight is the weight of the preferred scheduling term steering the pod towards the selected target.
+const spreadingPreferenceWeight = 100
+
+// spreadPod selects the target (i.e., the local cluster or a remote one) the pod should be executed in, to converge
+// towards the distribution defined by the weights configured in the NamespaceOffloading. The pod is steered towards
+// the selected target through a preferred node affinity, to still allow its scheduling elsewhere if not feasible,
+// and annotated to account for it in the upcoming decisions, until actually scheduled.
+func (w *podwh) spreadPod(ctx context.Context, nsoff *offv1alpha1.NamespaceOffloading, pod *corev1.Pod) error {
+	if nsoff.Spec.Weights == nil {
+		return nil
+	}
+
+	candidates, err := candidateClusters(ctx, w.client, nsoff, pod)
+	if err != nil {
+		return err
+	}
+
+	distribution, err := offloadingutils.Distribution(ctx, w.client, w.admittedPods, nsoff.Namespace, true)
+	if err != nil {
+		return err
+	}
+
+	target, found := selectSpreadingTarget(nsoff.Spec.Weights, candidates, distribution)
+	if !found {
+		return nil
+	}
+	klog.V(4).Infof("Pod %q assigned to spreading target %q", podName(pod), target)
+
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[liqoconst.SpreadingTargetAnnotation] = target
+
+	requirement := corev1.NodeSelectorRequirement{Key: liqoconst.RemoteClusterID, Operator: corev1.NodeSelectorOpIn, Values: []string{target}}
+	if target == liqoconst.LocalSpreadingTarget {
+		requirement = corev1.NodeSelectorRequirement{Key: liqoconst.TypeLabel, Operator: corev1.NodeSelectorOpNotIn, Values: []string{liqoconst.TypeNode}}
+	}
+
+	if pod.Spec.Affinity == nil {
+		pod.Spec.Affinity = &corev1.Affinity{}
+	}
+	if pod.Spec.Affinity.NodeAffinity == nil {
+		pod.Spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	pod.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+		pod.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution, corev1.PreferredSchedulingTerm{
+			Weight:     spreadingPreferenceWeight,
+			Preference: corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{requirement}},
+		})
+	return nil
+}
+
+// selectSpreadingTarget returns the target with the largest deficit of pods, compared to the share defined by its weight.
+// The local cluster is considered if assigned a positive weight, while remote clusters if also part of the candidates.
+// Ties are broken in favor of the local cluster, and then in alphabetical order of the (sorted) candidates.
+func selectSpreadingTarget(weights *offv1alpha1.OffloadingWeights, candidates []string,
+	distribution *offv1alpha1.OffloadingDistribution) (target string, found bool) {
+	targets := make([]string, 0, len(candidates)+1)
+	targetWeights := make(map[string]int64, len(candidates)+1)
+	counts := map[string]int64{liqoconst.LocalSpreadingTarget: int64(distribution.Local)}
+
+	if weights.Local > 0 {
+		targets = append(targets, liqoconst.LocalSpreadingTarget)
+		targetWeights[liqoconst.LocalSpreadingTarget] = int64(weights.Local)
+	}
+	for _, clusterID := range candidates {
+		if weight := weights.Remote[clusterID]; weight > 0 {
+			targets = append(targets, clusterID)
+			targetWeights[clusterID] = int64(weight)
+			counts[clusterID] = int64(distribution.Remote[clusterID])
+		}
+	}
+
+	var totalWeight, totalCount int64
+	for _, current := range targets {
+		totalWeight += targetWeights[current]
+		totalCount += counts[current]
+	}
+
+	// The deficit is the difference between the expected number of pods of each target, once the current one is added,
+	// and the actual one. Both terms are multiplied by the total weight, to avoid integer divisions.
+	var maxDeficit int64
+	for _, current := range targets {
+		deficit := targetWeights[current]*(totalCount+1) - counts[current]*totalWeight
+		if !found || deficit > maxDeficit {
+			target, maxDeficit, found = current, deficit, true
+		}
+	}
+	return target, found
+}
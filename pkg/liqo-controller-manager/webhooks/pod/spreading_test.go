@@ -0,0 +1,192 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	offv1alpha1 "github.com/liqotech/liqo/apis/offloading/v1alpha1"
+	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+)
+
+var _ = Describe("Weighted spreading", func() {
+	const local = liqoconst.LocalSpreadingTarget
+
+	Describe("The createWeightedNodeSelector function", func() {
+		var nsoff *offv1alpha1.NamespaceOffloading
+
+		localTerm := corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{
+			{Key: liqoconst.TypeLabel, Operator: corev1.NodeSelectorOpNotIn, Values: []string{liqoconst.TypeNode}},
+		}}
+		clusterRequirement := corev1.NodeSelectorRequirement{
+			Key: liqoconst.RemoteClusterID, Operator: corev1.NodeSelectorOpIn, Values: []string{"cluster-1", "cluster-2"},
+		}
+
+		BeforeEach(func() {
+			nsoff = &offv1alpha1.NamespaceOffloading{Spec: offv1alpha1.NamespaceOffloadingSpec{
+				PodOffloadingStrategy: offv1alpha1.WeightedPodOffloadingStrategyType,
+				Weights: &offv1alpha1.OffloadingWeights{
+					Local: 70, Remote: map[string]int32{"cluster-2": 20, "cluster-1": 10, "cluster-3": 0},
+				},
+			}}
+		})
+
+		It("should allow the local nodes and the virtual nodes of the clusters with a positive weight", func() {
+			selector, err := createNodeSelectorFromNamespaceOffloading(nsoff)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selector.NodeSelectorTerms).To(Equal([]corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{clusterRequirement}}, localTerm,
+			}))
+		})
+
+		It("should restrict every term of the cluster selector", func() {
+			nsoff.Spec.ClusterSelector.NodeSelectorTerms = []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "region", Operator: corev1.NodeSelectorOpExists}}},
+			}
+			selector, err := createNodeSelectorFromNamespaceOffloading(nsoff)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selector.NodeSelectorTerms).To(Equal([]corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "region", Operator: corev1.NodeSelectorOpExists}, clusterRequirement}},
+				localTerm,
+			}))
+			// The cluster selector of the NamespaceOffloading shall not be modified.
+			Expect(nsoff.Spec.ClusterSelector.NodeSelectorTerms[0].MatchExpressions).To(HaveLen(1))
+		})
+
+		It("should exclude the local nodes if the local weight is zero", func() {
+			nsoff.Spec.Weights.Local = 0
+			selector, err := createNodeSelectorFromNamespaceOffloading(nsoff)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selector.NodeSelectorTerms).To(ConsistOf(corev1.NodeSelectorTerm{
+				MatchExpressions: []corev1.NodeSelectorRequirement{clusterRequirement},
+			}))
+		})
+
+		It("should fail if no cluster has a positive weight", func() {
+			nsoff.Spec.Weights = nil
+			_, err := createNodeSelectorFromNamespaceOffloading(nsoff)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("The selectSpreadingTarget function", func() {
+		weights := &offv1alpha1.OffloadingWeights{Local: 70, Remote: map[string]int32{"cluster-1": 30, "cluster-2": 0}}
+		distribution := func(local, remote int32) *offv1alpha1.OffloadingDistribution {
+			return &offv1alpha1.OffloadingDistribution{Local: local, Remote: map[string]int32{"cluster-1": remote, "cluster-2": 5}}
+		}
+
+		DescribeTable("tests table",
+			func(candidates []string, current *offv1alpha1.OffloadingDistribution, expected string) {
+				target, found := selectSpreadingTarget(weights, candidates, current)
+				Expect(found).To(BeTrue())
+				Expect(target).To(Equal(expected))
+			},
+			Entry("no pods yet", []string{"cluster-1", "cluster-2"}, distribution(0, 0), local),
+			Entry("local share reached", []string{"cluster-1", "cluster-2"}, distribution(1, 0), "cluster-1"),
+			Entry("remote share reached", []string{"cluster-1", "cluster-2"}, distribution(2, 1), local),
+			Entry("seven to three", []string{"cluster-1", "cluster-2"}, distribution(6, 2), "cluster-1"),
+			Entry("seven to three, completed", []string{"cluster-1", "cluster-2"}, distribution(7, 3), local),
+			Entry("remote cluster not ready", []string{"cluster-2"}, distribution(7, 0), local),
+		)
+
+		It("should spread the pods according to the weights", func() {
+			current := distribution(0, 0)
+			for i := 0; i < 100; i++ {
+				target, _ := selectSpreadingTarget(weights, []string{"cluster-1"}, current)
+				if target == local {
+					current.Local++
+				} else {
+					current.Remote[target]++
+				}
+			}
+			Expect(current.Local).To(BeEquivalentTo(70))
+			Expect(current.Remote).To(HaveKeyWithValue("cluster-1", BeEquivalentTo(30)))
+		})
+
+		It("should not select any target if no candidate has a positive weight", func() {
+			_, found := selectSpreadingTarget(&offv1alpha1.OffloadingWeights{}, []string{"cluster-1"}, distribution(0, 0))
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Describe("The spreadPod function", func() {
+		var (
+			wh    *podwh
+			nsoff *offv1alpha1.NamespaceOffloading
+			pod   *corev1.Pod
+		)
+
+		existing := func(name, nodeName, target string) *corev1.Pod {
+			p := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "foo"}, Spec: corev1.PodSpec{NodeName: nodeName}}
+			if target != "" {
+				p.Annotations = map[string]string{liqoconst.SpreadingTargetAnnotation: target}
+			}
+			return p
+		}
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			utilruntime.Must(vkv1alpha1.AddToScheme(scheme))
+			utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&vkv1alpha1.NamespaceMap{ObjectMeta: metav1.ObjectMeta{Name: "cluster-1-map", Namespace: "liqo-tenant", Labels: map[string]string{
+					liqoconst.RemoteClusterID:             "cluster-1",
+					liqoconst.ReplicationRequestedLabel:   liqoconst.ReplicationRequestedLabelValue,
+					liqoconst.ReplicationDestinationLabel: "cluster-1",
+				}}},
+				&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "liqo-cluster-1", Labels: map[string]string{
+					liqoconst.TypeLabel: liqoconst.TypeNode, liqoconst.RemoteClusterID: "cluster-1",
+				}}},
+				existing("scheduled-locally", "local-node", ""),
+				existing("pending-locally", "", local),
+			).Build()
+			wh = &podwh{client: cl, admittedPods: cl}
+
+			nsoff = &offv1alpha1.NamespaceOffloading{
+				ObjectMeta: metav1.ObjectMeta{Name: liqoconst.DefaultNamespaceOffloadingName, Namespace: "foo"},
+				Spec: offv1alpha1.NamespaceOffloadingSpec{
+					PodOffloadingStrategy: offv1alpha1.WeightedPodOffloadingStrategyType,
+					Weights:               &offv1alpha1.OffloadingWeights{Local: 50, Remote: map[string]int32{"cluster-1": 50}},
+				},
+				Status: offv1alpha1.NamespaceOffloadingStatus{RemoteNamespacesConditions: map[string]offv1alpha1.RemoteNamespaceConditions{
+					"cluster-1-map": {{Type: offv1alpha1.NamespaceReady, Status: corev1.ConditionTrue}},
+				}},
+			}
+			pod = &corev1.Pod{}
+		})
+
+		It("should steer the pod towards the target with the largest deficit", func() {
+			Expect(wh.spreadPod(context.Background(), nsoff, pod)).To(Succeed())
+			Expect(pod.Annotations).To(HaveKeyWithValue(liqoconst.SpreadingTargetAnnotation, "cluster-1"))
+			Expect(pod.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(ConsistOf(
+				corev1.PreferredSchedulingTerm{Weight: spreadingPreferenceWeight, Preference: corev1.NodeSelectorTerm{
+					MatchExpressions: []corev1.NodeSelectorRequirement{
+						{Key: liqoconst.RemoteClusterID, Operator: corev1.NodeSelectorOpIn, Values: []string{"cluster-1"}},
+					},
+				}},
+			))
+		})
+	})
+})
@@ -21,11 +21,11 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
-	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 	klog "k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
+	"github.com/liqotech/liqo/pkg/utils/pod"
 )
 
 // Description is a struct that contains the main informations about a shadow pod.
@@ -98,66 +98,15 @@ func checkShadowPodExistence(ctx context.Context, spvclient client.Client, names
 }
 
 func getQuotaFromShadowPod(shadowpod *vkv1alpha1.ShadowPod, validate bool) (*corev1.ResourceList, error) {
-	conResources := corev1.ResourceList{}
-	initConResources := corev1.ResourceList{}
-
 	// At least one container is required
 	if shadowpod.Spec.Pod.Containers == nil {
 		return nil, fmt.Errorf("ShadowPod %s has no containers defined", shadowpod.GetName())
 	}
 
-	// Calculating the sum of the resources of all containers
-	for i := range shadowpod.Spec.Pod.Containers {
-		// This flags are used to check if each container in range has CPU and Memory limits defined
-		cpuFlag := false
-		memoryFlag := false
-		for key, value := range shadowpod.Spec.Pod.Containers[i].Resources.Limits {
-			if key == corev1.ResourceCPU {
-				cpuFlag = true
-			}
-			if key == corev1.ResourceMemory {
-				memoryFlag = true
-			}
-			if prevResources, ok := conResources[key]; ok {
-				prevResources.Add(value)
-				conResources[key] = prevResources
-			} else {
-				conResources[key] = value.DeepCopy()
-			}
-		}
-		// If the container has no CPU or Memory limits defined and this kind of validation is required, an error is returned
-		if (!cpuFlag || !memoryFlag) && validate {
-			return nil, fmt.Errorf("CPU and/or memory limits not set for container %s", shadowpod.Spec.Pod.Containers[i].Name)
-		}
-	}
-
-	// Calculating the max of each resource type between the init containers
-	for i := range shadowpod.Spec.Pod.InitContainers {
-		// This flags are used to check if each container in range has CPU and Memory limits defined
-		cpuFlag := false
-		memoryFlag := false
-		for key, value := range shadowpod.Spec.Pod.InitContainers[i].Resources.Limits {
-			if key == corev1.ResourceCPU {
-				cpuFlag = true
-			}
-			if key == corev1.ResourceMemory {
-				memoryFlag = true
-			}
-			if prevResources, ok := initConResources[key]; ok {
-				if prevResources.Value() < value.Value() {
-					initConResources[key] = value.DeepCopy()
-				}
-			} else {
-				initConResources[key] = value.DeepCopy()
-			}
-		}
-		// If the init container has no CPU or Memory limits defined and this kind of validation is required, an error is returned
-		if (!cpuFlag || !memoryFlag) && validate {
-			return nil, fmt.Errorf("CPU and/or memory limits not set for initContainer %s",
-				shadowpod.Spec.Pod.InitContainers[i].Name)
-		}
+	result, err := pod.QuotaFromPodSpec(&shadowpod.Spec.Pod, validate)
+	if err != nil {
+		return nil, err
 	}
-	result := quotav1.Max(conResources, initConResources)
 	return &result, nil
 }
 
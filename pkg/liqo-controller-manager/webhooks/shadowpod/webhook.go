@@ -21,6 +21,7 @@ import (
 
 	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2"
@@ -107,6 +108,15 @@ func (spv *Validator) HandleCreate(ctx context.Context, req *admission.Request)
 		return admission.Denied(err.Error())
 	}
 
+	// In case of dry-run requests (e.g., issued by the consumer to check whether the offloading would succeed),
+	// additionally dry-run the creation of the corresponding pod, to detect quota and admission policy violations.
+	if req.DryRun != nil && *req.DryRun {
+		if err := spv.dryRunPod(ctx, shadowpod); err != nil {
+			klog.Warningf("Dry-run of the pod corresponding to ShadowPod %q failed: %v", shadowpod.Name, err)
+			return admission.Denied(err.Error())
+		}
+	}
+
 	if !spv.enableResourceValidation {
 		return admission.Allowed("")
 	}
@@ -139,6 +149,21 @@ func (spv *Validator) HandleCreate(ctx context.Context, req *admission.Request)
 	return admission.Allowed("")
 }
 
+// dryRunPod performs the server-side dry-run of the creation of the pod corresponding to the given ShadowPod.
+func (spv *Validator) dryRunPod(ctx context.Context, shadowpod *vkv1alpha1.ShadowPod) error {
+	newPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:         shadowpod.Name,
+			GenerateName: shadowpod.GenerateName,
+			Namespace:    shadowpod.Namespace,
+			Labels:       shadowpod.Labels,
+			Annotations:  shadowpod.Annotations,
+		},
+		Spec: shadowpod.Spec.Pod,
+	}
+	return spv.client.Create(ctx, newPod, client.DryRunAll)
+}
+
 // HandleUpdate is the function in charge of handling Update requests.
 func (spv *Validator) HandleUpdate(ctx context.Context, req *admission.Request) admission.Response {
 	shadowpod, err := spv.DecodeShadowPod(req.Object)
@@ -170,7 +195,7 @@ func (spv *Validator) HandleUpdate(ctx context.Context, req *admission.Request)
 		return admission.Denied("shadopow Cluster ID label is changed")
 	}
 
-	if pod.CheckShadowPodUpdate(&shadowpod.Spec.Pod, &oldShadowpod.Spec.Pod) {
+	if pod.CheckShadowPodUpdate(&oldShadowpod.Spec.Pod, &shadowpod.Spec.Pod) {
 		return admission.Allowed("")
 	}
 
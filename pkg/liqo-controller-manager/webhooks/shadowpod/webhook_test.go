@@ -15,6 +15,8 @@
 package shadowpod
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -22,9 +24,12 @@ import (
 	. "github.com/onsi/gomega"
 	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -106,6 +111,88 @@ var _ = Describe("Validating webhook", func() {
 				Expect(response.Result.Code).To(BeNumerically("==", http.StatusBadRequest))
 			})
 		})
+		When("the request is a dry-run and the corresponding pod would be accepted", func() {
+			BeforeEach(func() {
+				fakeNewShadowPod = forgeShadowPodWithClusterID(clusterID, testNamespace)
+				request = forgeRequest(admissionv1.Create, fakeNewShadowPod, nil)
+				request.DryRun = pointer.Bool(true)
+			})
+			It("should admit the request, without creating the pod", func() {
+				Expect(response.Allowed).To(BeTrue())
+				var pods corev1.PodList
+				Expect(fakeClient.List(ctx, &pods)).To(Succeed())
+				Expect(pods.Items).To(BeEmpty())
+			})
+		})
+		When("the request is a dry-run and the corresponding pod would be rejected", func() {
+			BeforeEach(func() {
+				spValidator.client = interceptor.NewClient(fakeClient.(client.WithWatch), interceptor.Funcs{
+					Create: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+						return apierrors.NewForbidden(corev1.Resource("pods"), obj.GetName(), errors.New("exceeded quota"))
+					},
+				})
+				fakeNewShadowPod = forgeShadowPodWithClusterID(clusterID, testNamespace)
+				request = forgeRequest(admissionv1.Create, fakeNewShadowPod, nil)
+				request.DryRun = pointer.Bool(true)
+			})
+			It("should return a forbidden response, with the reason of the rejection", func() {
+				Expect(response.Allowed).To(BeFalse())
+				Expect(response.Result.Code).To(BeNumerically("==", http.StatusForbidden))
+				Expect(response.Result.Message).To(ContainSubstring("exceeded quota"))
+			})
+		})
+	})
+
+	Describe("Handle update ShadowPod", func() {
+		var oldShadowPod *vkv1alpha1.ShadowPod
+
+		ephemeral := corev1.EphemeralContainer{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger", Image: "busybox"}}
+
+		BeforeEach(func() {
+			oldShadowPod = forgeShadowPod(nsName.Name, nsName.Namespace, string(testShadowPodUID), clusterID)
+			fakeNewShadowPod = oldShadowPod.DeepCopy()
+		})
+
+		JustBeforeEach(func() {
+			request = forgeRequest(admissionv1.Update, fakeNewShadowPod, oldShadowPod)
+			response = spValidator.Handle(ctx, request)
+		})
+
+		When("an ephemeral container is appended", func() {
+			BeforeEach(func() {
+				fakeNewShadowPod.Spec.Pod.EphemeralContainers = []corev1.EphemeralContainer{ephemeral}
+			})
+			It("should admit the request", func() {
+				Expect(response.Allowed).To(BeTrue())
+			})
+		})
+
+		When("an ephemeral container is removed", func() {
+			BeforeEach(func() {
+				oldShadowPod.Spec.Pod.EphemeralContainers = []corev1.EphemeralContainer{ephemeral}
+			})
+			It("should deny the request", func() {
+				Expect(response.Allowed).To(BeFalse())
+			})
+		})
+
+		When("the image of a container is changed", func() {
+			BeforeEach(func() {
+				fakeNewShadowPod.Spec.Pod.Containers[0].Image = "test-image-updated"
+			})
+			It("should admit the request", func() {
+				Expect(response.Allowed).To(BeTrue())
+			})
+		})
+
+		When("the name of a container is changed", func() {
+			BeforeEach(func() {
+				fakeNewShadowPod.Spec.Pod.Containers[0].Name = "test-container-updated"
+			})
+			It("should deny the request", func() {
+				Expect(response.Allowed).To(BeFalse())
+			})
+		})
 	})
 
 	Describe("Handle creation ShadowPod with resource validation", func() {
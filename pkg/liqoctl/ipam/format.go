@@ -0,0 +1,139 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/liqotech/liqo/pkg/liqoctl/output"
+	liqoipam "github.com/liqotech/liqo/pkg/liqonet/ipam"
+	"github.com/liqotech/liqo/pkg/utils/slice"
+)
+
+// ownerDescriptions maps each kind of IP owner to a human-readable description.
+var ownerDescriptions = map[liqoipam.IPOwner_Kind]string{
+	liqoipam.IPOwner_NETWORK_POOL:            "Network pool",
+	liqoipam.IPOwner_RESERVED_SUBNET:         "Reserved subnet",
+	liqoipam.IPOwner_POD_CIDR:                "Local pod CIDR",
+	liqoipam.IPOwner_SERVICE_CIDR:            "Local service CIDR",
+	liqoipam.IPOwner_EXTERNAL_CIDR:           "Local external CIDR",
+	liqoipam.IPOwner_REMOTE_POD_CIDR:         "Remote pod CIDR",
+	liqoipam.IPOwner_REMOTE_EXTERNAL_CIDR:    "Remote external CIDR",
+	liqoipam.IPOwner_LOCAL_NAT_POD_CIDR:      "Local pod CIDR as seen by the remote cluster",
+	liqoipam.IPOwner_LOCAL_NAT_EXTERNAL_CIDR: "Local external CIDR as seen by the remote cluster",
+	liqoipam.IPOwner_ENDPOINT:                "Mapped endpoint",
+	liqoipam.IPOwner_ENDPOINT_EXTERNAL_IP:    "External CIDR IP of endpoint",
+	liqoipam.IPOwner_ENDPOINT_NATTED_IP:      "Natted IP of endpoint",
+}
+
+// infoSection retrieves the IPAM configuration and formats it as an output section.
+func infoSection(ctx context.Context, ipamClient liqoipam.IpamClient, clusterID string) (output.Section, error) {
+	pools, err := ipamClient.ListPools(ctx, &liqoipam.ListPoolsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	reserved, err := ipamClient.ListReservedSubnets(ctx, &liqoipam.ListReservedSubnetsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	clusters, err := ipamClient.ListClusterSubnets(ctx, &liqoipam.ListClusterSubnetsRequest{ClusterID: clusterID})
+	if err != nil {
+		return nil, err
+	}
+	configured, err := ipamClient.ListNatMappingsConfigured(ctx, &liqoipam.ListNatMappingsConfiguredRequest{})
+	if err != nil {
+		return nil, err
+	}
+	endpoints, err := ipamClient.ListEndpointMappings(ctx, &liqoipam.ListEndpointMappingsRequest{ClusterID: clusterID})
+	if err != nil {
+		return nil, err
+	}
+
+	root := output.NewRootSection()
+	if clusterID == "" {
+		root.AddSection("Network pools").AddEntry("Pools", pools.GetPools()...)
+		root.AddSection("Reserved subnets").AddEntry("Subnets", reserved.GetReservedSubnets()...)
+	}
+
+	clustersSection := root.AddSection("Remote clusters")
+	for _, subnets := range clusters.GetClusterSubnets() {
+		natConfigured := slice.ContainsString(configured.GetClusterIDs(), subnets.GetClusterID())
+		clusterSection := clustersSection.AddSection(subnets.GetClusterID()).
+			AddEntry("Remote pod CIDR", subnets.GetRemotePodCIDR()).
+			AddEntry("Remote external CIDR", subnets.GetRemoteExternalCIDR()).
+			AddEntry("Local NAT pod CIDR", subnets.GetLocalNATPodCIDR()).
+			AddEntry("Local NAT external CIDR", subnets.GetLocalNATExternalCIDR())
+		// The IPv6 networks are shown only in case of dual-stack peerings.
+		if subnets.GetRemotePodCIDRv6() != "" {
+			clusterSection.
+				AddEntry("Remote IPv6 pod CIDR", subnets.GetRemotePodCIDRv6()).
+				AddEntry("Remote IPv6 external CIDR", subnets.GetRemoteExternalCIDRv6()).
+				AddEntry("Local NAT IPv6 pod CIDR", subnets.GetLocalNATPodCIDRv6()).
+				AddEntry("Local NAT IPv6 external CIDR", subnets.GetLocalNATExternalCIDRv6())
+		}
+		clusterSection.AddEntry("NAT mappings configured", fmt.Sprintf("%t", natConfigured))
+	}
+
+	endpointsSection := root.AddSection("Endpoint mappings")
+	for _, endpoint := range endpoints.GetEndpointMappings() {
+		endpointSection := endpointsSection.AddSection(endpoint.GetIp()).
+			AddEntry("External CIDR IP", endpoint.GetExternalCIDROriginalIP())
+
+		clusterIDs := make([]string, 0, len(endpoint.GetClusterMappings()))
+		for id := range endpoint.GetClusterMappings() {
+			clusterIDs = append(clusterIDs, id)
+		}
+		sort.Strings(clusterIDs)
+		for _, id := range clusterIDs {
+			endpointSection.AddEntry(fmt.Sprintf("Natted IP for %s", id), endpoint.GetClusterMappings()[id])
+		}
+	}
+
+	return root, nil
+}
+
+// ownerSection retrieves the owners of the given IP and formats them as an output section.
+func ownerSection(ctx context.Context, ipamClient liqoipam.IpamClient, ip string) (output.Section, error) {
+	response, err := ipamClient.GetIPOwner(ctx, &liqoipam.GetIPOwnerRequest{Ip: ip})
+	if err != nil {
+		return nil, err
+	}
+
+	root := output.NewRootSection()
+	if len(response.GetOwners()) == 0 {
+		root.AddSectionInfo("The IP does not belong to any network managed by the IPAM")
+		return root, nil
+	}
+
+	for _, owner := range response.GetOwners() {
+		section := root.AddSection(ownerDescription(owner.GetKind())).AddEntry("Network", owner.GetNetwork())
+		if owner.GetClusterID() != "" {
+			section.AddEntry("Cluster ID", owner.GetClusterID())
+		}
+		if owner.GetEndpointIP() != "" {
+			section.AddEntry("Endpoint IP", owner.GetEndpointIP())
+		}
+	}
+	return root, nil
+}
+
+func ownerDescription(kind liqoipam.IPOwner_Kind) string {
+	if description, found := ownerDescriptions[kind]; found {
+		return description
+	}
+	return kind.String()
+}
@@ -0,0 +1,131 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pterm/pterm"
+	"google.golang.org/grpc"
+
+	"github.com/liqotech/liqo/pkg/liqoctl/output"
+	liqoipam "github.com/liqotech/liqo/pkg/liqonet/ipam"
+)
+
+// fakeIpamClient returns canned responses for the read-only IPAM RPCs.
+type fakeIpamClient struct {
+	liqoipam.IpamClient
+	owners []*liqoipam.IPOwner
+}
+
+func (c *fakeIpamClient) ListPools(context.Context, *liqoipam.ListPoolsRequest,
+	...grpc.CallOption) (*liqoipam.ListPoolsResponse, error) {
+	return &liqoipam.ListPoolsResponse{Pools: []string{"10.0.0.0/8"}}, nil
+}
+
+func (c *fakeIpamClient) ListReservedSubnets(context.Context, *liqoipam.ListReservedSubnetsRequest,
+	...grpc.CallOption) (*liqoipam.ListReservedSubnetsResponse, error) {
+	return &liqoipam.ListReservedSubnetsResponse{ReservedSubnets: []string{"10.1.0.0/16"}}, nil
+}
+
+func (c *fakeIpamClient) ListClusterSubnets(context.Context, *liqoipam.ListClusterSubnetsRequest,
+	...grpc.CallOption) (*liqoipam.ListClusterSubnetsResponse, error) {
+	return &liqoipam.ListClusterSubnetsResponse{ClusterSubnets: []*liqoipam.ClusterSubnets{{
+		ClusterID: "cluster1", RemotePodCIDR: "10.50.0.0/16", RemoteExternalCIDR: "10.60.0.0/16",
+		LocalNATPodCIDR: "None", LocalNATExternalCIDR: "None",
+	}}}, nil
+}
+
+func (c *fakeIpamClient) ListNatMappingsConfigured(context.Context, *liqoipam.ListNatMappingsConfiguredRequest,
+	...grpc.CallOption) (*liqoipam.ListNatMappingsConfiguredResponse, error) {
+	return &liqoipam.ListNatMappingsConfiguredResponse{ClusterIDs: []string{"cluster1"}}, nil
+}
+
+func (c *fakeIpamClient) ListEndpointMappings(context.Context, *liqoipam.ListEndpointMappingsRequest,
+	...grpc.CallOption) (*liqoipam.ListEndpointMappingsResponse, error) {
+	return &liqoipam.ListEndpointMappingsResponse{EndpointMappings: []*liqoipam.EndpointMapping{{
+		Ip: "20.0.0.1", ExternalCIDROriginalIP: "10.0.1.2", ClusterMappings: map[string]string{"cluster1": "10.0.1.2"},
+	}}}, nil
+}
+
+func (c *fakeIpamClient) GetIPOwner(context.Context, *liqoipam.GetIPOwnerRequest,
+	...grpc.CallOption) (*liqoipam.GetIPOwnerResponse, error) {
+	return &liqoipam.GetIPOwnerResponse{Owners: c.owners}, nil
+}
+
+var _ = Describe("Formatting the IPAM information", func() {
+	var (
+		ctx     context.Context
+		client  *fakeIpamClient
+		printer *output.Printer
+		section output.Section
+		err     error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		client = &fakeIpamClient{}
+		printer = output.NewFakePrinter(GinkgoWriter)
+		pterm.DisableStyling()
+	})
+
+	Describe("the infoSection function", func() {
+		It("should report all the IPAM configuration", func() {
+			section, err = infoSection(ctx, client, "")
+			Expect(err).ToNot(HaveOccurred())
+
+			text := section.SprintForBox(printer)
+			Expect(text).To(ContainSubstring("10.0.0.0/8"))
+			Expect(text).To(ContainSubstring("10.1.0.0/16"))
+			Expect(text).To(ContainSubstring("cluster1"))
+			Expect(text).To(MatchRegexp("Remote pod CIDR: +10.50.0.0/16"))
+			Expect(text).To(MatchRegexp("NAT mappings configured: +true"))
+			Expect(text).To(MatchRegexp("Natted IP for cluster1: +10.0.1.2"))
+		})
+
+		It("should omit pools and reserved subnets when filtering by cluster", func() {
+			section, err = infoSection(ctx, client, "cluster1")
+			Expect(err).ToNot(HaveOccurred())
+
+			text := section.SprintForBox(printer)
+			Expect(text).ToNot(ContainSubstring("Network pools"))
+			Expect(text).To(ContainSubstring("20.0.0.1"))
+		})
+	})
+
+	Describe("the ownerSection function", func() {
+		It("should report the owners of the IP", func() {
+			client.owners = []*liqoipam.IPOwner{
+				{Kind: liqoipam.IPOwner_REMOTE_POD_CIDR, Network: "10.50.0.0/16", ClusterID: "cluster1"},
+				{Kind: liqoipam.IPOwner_NETWORK_POOL, Network: "10.0.0.0/8"},
+			}
+			section, err = ownerSection(ctx, client, "10.50.0.1")
+			Expect(err).ToNot(HaveOccurred())
+
+			text := section.SprintForBox(printer)
+			Expect(text).To(ContainSubstring("Remote pod CIDR"))
+			Expect(text).To(MatchRegexp("Cluster ID: +cluster1"))
+			Expect(text).To(ContainSubstring("Network pool"))
+		})
+
+		It("should report when the IP has no owner", func() {
+			section, err = ownerSection(ctx, client, "1.2.3.4")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(section.SprintForBox(printer)).To(ContainSubstring("does not belong to any network"))
+		})
+	})
+})
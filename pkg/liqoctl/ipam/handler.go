@@ -0,0 +1,134 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	liqoconsts "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqoctl/factory"
+	"github.com/liqotech/liqo/pkg/liqoctl/inband"
+	"github.com/liqotech/liqo/pkg/liqoctl/output"
+	liqoipam "github.com/liqotech/liqo/pkg/liqonet/ipam"
+	liqolabels "github.com/liqotech/liqo/pkg/utils/labels"
+)
+
+// Options encapsulates the arguments of the ipam commands.
+type Options struct {
+	*factory.Factory
+
+	Timeout time.Duration
+
+	// ClusterID, if set, restricts the output of the info command to the given remote cluster.
+	ClusterID string
+	// IP is the address whose owner is looked up by the owner command.
+	IP string
+}
+
+// RunInfo implements the ipam info command.
+func (o *Options) RunInfo(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, o.Timeout)
+	defer cancel()
+
+	ipamClient, stop, err := o.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	s := o.Printer.StartSpinner("retrieving IPAM configuration")
+	section, err := infoSection(ctx, ipamClient, o.ClusterID)
+	if err != nil {
+		s.Fail(fmt.Sprintf("an error occurred while retrieving IPAM configuration: %v", output.PrettyErr(err)))
+		return err
+	}
+	s.Success("IPAM configuration correctly retrieved")
+
+	o.Printer.BoxSetTitle("IPAM")
+	o.Printer.BoxPrintln(section.SprintForBox(o.Printer))
+	return nil
+}
+
+// RunOwner implements the ipam owner command.
+func (o *Options) RunOwner(ctx context.Context) error {
+	if net.ParseIP(o.IP) == nil {
+		return fmt.Errorf("invalid IP address %q", o.IP)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.Timeout)
+	defer cancel()
+
+	ipamClient, stop, err := o.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	s := o.Printer.StartSpinner(fmt.Sprintf("looking up the owner of IP %q", o.IP))
+	section, err := ownerSection(ctx, ipamClient, o.IP)
+	if err != nil {
+		s.Fail(fmt.Sprintf("an error occurred while looking up the owner of IP %q: %v", o.IP, output.PrettyErr(err)))
+		return err
+	}
+	s.Success(fmt.Sprintf("owner of IP %q correctly retrieved", o.IP))
+
+	o.Printer.BoxSetTitle(fmt.Sprintf("IP %s", o.IP))
+	o.Printer.BoxPrintln(section.SprintForBox(o.Printer))
+	return nil
+}
+
+// connect port-forwards the IPAM service of the network manager and returns a client connected to it,
+// along with a function to release the associated resources.
+func (o *Options) connect(ctx context.Context) (liqoipam.IpamClient, func(), error) {
+	pfo := &inband.PortForwardOptions{
+		Namespace: o.LiqoNamespace,
+		Selector:  &liqolabels.NetworkManagerPodLabelSelector,
+		Config:    o.RESTConfig,
+		Client:    o.CRClient,
+		PortForwarder: &inband.DefaultPortForwarder{
+			IOStreams: genericclioptions.NewTestIOStreamsDiscard(),
+		},
+		RemotePort:   liqoconsts.NetworkManagerIpamPort,
+		StopChannel:  make(chan struct{}),
+		ReadyChannel: make(chan struct{}),
+	}
+
+	s := o.Printer.StartSpinner("port-forwarding IPAM service")
+	if err := pfo.RunPortForward(ctx); err != nil {
+		s.Fail(fmt.Sprintf("an error occurred while port-forwarding IPAM service: %v", output.PrettyErr(err)))
+		return nil, nil, err
+	}
+
+	connection, err := grpc.DialContext(ctx, fmt.Sprintf("localhost:%d", pfo.LocalPort),
+		grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	if err != nil {
+		pfo.StopPortForward()
+		s.Fail(fmt.Sprintf("an error occurred while creating IPAM client: %v", output.PrettyErr(err)))
+		return nil, nil, err
+	}
+	s.Success("IPAM service correctly port-forwarded")
+
+	return liqoipam.NewIpamClient(connection), func() {
+		connection.Close()
+		pfo.StopPortForward()
+	}, nil
+}
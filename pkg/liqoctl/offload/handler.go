@@ -40,12 +40,32 @@ type Options struct {
 	PodOffloadingStrategy    offloadingv1alpha1.PodOffloadingStrategyType
 	NamespaceMappingStrategy offloadingv1alpha1.NamespaceMappingStrategyType
 	ClusterSelector          [][]metav1.LabelSelectorRequirement
+	Weights                  *offloadingv1alpha1.OffloadingWeights
 
 	OutputFormat string
 
 	Timeout time.Duration
 }
 
+// ParseWeights parses the weights of the local and remote clusters, in case of weighted pod offloading strategy.
+func (o *Options) ParseWeights(local int, remote map[string]int) error {
+	if o.PodOffloadingStrategy != offloadingv1alpha1.WeightedPodOffloadingStrategyType {
+		if local != 0 || len(remote) > 0 {
+			return fmt.Errorf("weights can be specified only with the %s pod offloading strategy", offloadingv1alpha1.WeightedPodOffloadingStrategyType)
+		}
+		return nil
+	}
+
+	o.Weights = &offloadingv1alpha1.OffloadingWeights{Local: int32(local)}
+	for clusterID, weight := range remote {
+		if o.Weights.Remote == nil {
+			o.Weights.Remote = map[string]int32{}
+		}
+		o.Weights.Remote[clusterID] = int32(weight)
+	}
+	return nil
+}
+
 // ParseClusterSelectors parses the cluster selector.
 func (o *Options) ParseClusterSelectors(selectors []string) error {
 	for _, selector := range selectors {
@@ -88,6 +108,7 @@ func (o *Options) Run(ctx context.Context) error {
 		nsoff.Spec.PodOffloadingStrategy = o.PodOffloadingStrategy
 		nsoff.Spec.NamespaceMappingStrategy = o.NamespaceMappingStrategy
 		nsoff.Spec.ClusterSelector = toNodeSelector(o.ClusterSelector)
+		nsoff.Spec.Weights = o.Weights
 		return nil
 	})
 	if err != nil {
@@ -133,6 +154,7 @@ func (o *Options) output() error {
 			PodOffloadingStrategy:    o.PodOffloadingStrategy,
 			NamespaceMappingStrategy: o.NamespaceMappingStrategy,
 			ClusterSelector:          toNodeSelector(o.ClusterSelector),
+			Weights:                  o.Weights,
 		},
 	}
 
@@ -24,6 +24,8 @@ type Msg struct {
 	ClusterID string    `json:"clusterID"`
 	MsgType   MsgTypes  `json:"msgType"`
 	TimeStamp time.Time `json:"timeStamp"`
+	// Size is the size of the IP packet carrying a PMTU probe, echoed back in the corresponding acknowledgment.
+	Size int `json:"size,omitempty"`
 }
 
 func (msg Msg) String() string {
@@ -41,7 +43,11 @@ const (
 	PING MsgTypes = "PING"
 	// PONG is the type of a pong message.
 	PONG MsgTypes = "PONG"
+	// PROBE is the type of a PMTU probe message, padded to the size being probed.
+	PROBE MsgTypes = "PROBE"
+	// PROBEACK is the type of the message acknowledging the reception of a PMTU probe.
+	PROBEACK MsgTypes = "PROBEACK"
 )
 
 // UpdateFunc is a function called when a Receiver gets a PONG or when a connection is declared failed.
-type UpdateFunc func(connected bool, latency time.Duration, time time.Time) error
+type UpdateFunc func(connected bool, stats ConnectionStats, time time.Time) error
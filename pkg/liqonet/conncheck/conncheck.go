@@ -21,6 +21,7 @@ import (
 	"sync"
 	"time"
 
+	"golang.org/x/sys/unix"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 )
@@ -45,6 +46,12 @@ func NewConnChecker() (*ConnChecker, error) {
 		return nil, fmt.Errorf("failed to listen on UDP socket %s : %w", addr, err)
 	}
 	klog.V(4).Infof("conncheck socket: listening on %s", addr)
+	if PMTUDiscovery {
+		if err := setDontFragment(conn); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to configure UDP socket %s for PMTU discovery: %w", addr, err)
+		}
+	}
 	connChecker := ConnChecker{
 		receiver: NewReceiver(conn),
 		senders:  make(map[string]*Sender),
@@ -83,7 +90,10 @@ func (c *ConnChecker) AddAndRunSender(clusterID, ip string, updateCallback Updat
 
 	klog.Infof("conncheck sender %s starting", clusterID)
 	pingCallback := func(ctx context.Context) (done bool, err error) {
-		err = c.senders[clusterID].SendPing(ctx)
+		timestamp := time.Now()
+		// The PING is registered even if it could not be sent, so that it is accounted as lost.
+		c.receiver.RegisterPing(clusterID, timestamp)
+		err = c.senders[clusterID].SendPing(ctx, timestamp)
 		if err != nil {
 			klog.Warningf("failed to send ping: %s", err)
 		}
@@ -91,6 +101,10 @@ func (c *ConnChecker) AddAndRunSender(clusterID, ip string, updateCallback Updat
 	}
 	c.sm.Unlock()
 
+	if PMTUDiscovery {
+		go c.runPMTUDiscovery(ctxSender, clusterID)
+	}
+
 	// Ignore errors because only caused by context cancellation.
 	_ = wait.PollImmediateInfiniteWithContext(ctxSender, PingInterval, pingCallback)
 
@@ -131,3 +145,38 @@ func (c *ConnChecker) GetConnected(clusterID string) (bool, error) {
 	}
 	return false, fmt.Errorf("sender %s not found", clusterID)
 }
+
+// GetStats returns the connection statistics with clusterID, computed over the sliding window of the most recent pings.
+func (c *ConnChecker) GetStats(clusterID string) (ConnectionStats, error) {
+	c.receiver.m.RLock()
+	defer c.receiver.m.RUnlock()
+	if peer, ok := c.receiver.peers[clusterID]; ok {
+		return peer.stats(time.Now()), nil
+	}
+	return ConnectionStats{}, fmt.Errorf("sender %s not found", clusterID)
+}
+
+// setDontFragment configures the given socket to set the DF bit on the outgoing packets, and to ignore the path MTU
+// cached by the kernel. Hence, PMTU probes larger than the tunnel interface MTU fail, while the others reach the peer
+// only if supported by the underlying network path. IPv6 sockets are additionally configured through the IPv6 specific
+// option, which applies to the IPv6 peers, while the IPv4 one applies to the IPv4-mapped peers.
+func setDontFragment(conn *net.UDPConn) error {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var sockErr error
+	if err := raw.Control(func(fd uintptr) {
+		if sockErr = unix.SetsockoptInt(int(fd), unix.IPPROTO_IP, unix.IP_MTU_DISCOVER, unix.IP_PMTUDISC_PROBE); sockErr != nil {
+			return
+		}
+		var domain int
+		if domain, sockErr = unix.GetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_DOMAIN); sockErr != nil || domain != unix.AF_INET6 {
+			return
+		}
+		sockErr = unix.SetsockoptInt(int(fd), unix.IPPROTO_IPV6, unix.IPV6_MTU_DISCOVER, unix.IPV6_PMTUDISC_PROBE)
+	}); err != nil {
+		return err
+	}
+	return sockErr
+}
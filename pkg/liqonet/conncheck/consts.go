@@ -17,8 +17,15 @@ package conncheck
 import "time"
 
 const (
-	port     = 12345
-	buffSize = 1024
+	port = 12345
+	// buffSize is large enough to receive PMTU probes of any size.
+	buffSize = 65535
+	// probeIPv4HeadersSize is the size of the IPv4 and UDP headers of a PMTU probe.
+	probeIPv4HeadersSize = 28
+	// probeIPv6HeadersSize is the size of the IPv6 and UDP headers of a PMTU probe.
+	probeIPv6HeadersSize = 48
+	// probeAttempts is the number of times a PMTU probe is sent before considering the given size as not supported.
+	probeAttempts = 3
 )
 
 var (
@@ -26,4 +33,14 @@ var (
 	PingLossThreshold uint
 	// PingInterval is the interval at which the ping is sent.
 	PingInterval time.Duration
+	// PingStatsWindowSize is the number of most recent pings used to compute the connection statistics.
+	PingStatsWindowSize uint
+	// PMTUDiscovery enables the discovery of the path MTU towards each peer.
+	PMTUDiscovery bool
+	// PMTUMin is the minimum path MTU which is probed. If not supported, the discovery is considered failed.
+	PMTUMin int
+	// PMTUMax is the maximum path MTU which is probed, corresponding to the MTU of the tunnel interface.
+	PMTUMax int
+	// PMTURenegotiationInterval is the interval after which the path MTU is discovered again, to detect path changes.
+	PMTURenegotiationInterval time.Duration
 )
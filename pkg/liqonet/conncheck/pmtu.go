@@ -0,0 +1,120 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conncheck
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// probeFunc sends a PMTU probe of the given size, and returns whether it has been acknowledged.
+type probeFunc func(ctx context.Context, size int) bool
+
+// searchPMTU looks for the largest size in the [PMTUMin, PMTUMax] range which is acknowledged by the peer, through a binary search.
+// It returns 0 in case not even the minimum size is acknowledged (e.g., because the peer does not support PMTU discovery).
+func searchPMTU(ctx context.Context, probe probeFunc, minSize, maxSize int) int {
+	if maxSize < minSize {
+		minSize = maxSize
+	}
+	if probe(ctx, maxSize) {
+		return maxSize
+	}
+	if !probe(ctx, minSize) {
+		return 0
+	}
+
+	// Invariant: low is acknowledged, high is not.
+	low, high := minSize, maxSize
+	for high-low > 1 && ctx.Err() == nil {
+		mid := low + (high-low)/2
+		if probe(ctx, mid) {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return low
+}
+
+// runPMTUDiscovery periodically discovers the path MTU towards the given peer, until the context is canceled.
+// The discovered value is verified at every PING loss period, and a new discovery is started if the path
+// does no longer support it, as well as every PMTURenegotiationInterval, to detect a possible increase.
+func (c *ConnChecker) runPMTUDiscovery(ctx context.Context, clusterID string) {
+	probe := func(ctx context.Context, size int) bool {
+		return c.probe(ctx, clusterID, size)
+	}
+	verifyInterval := PingInterval * time.Duration(PingLossThreshold)
+
+	for ctx.Err() == nil {
+		mtu := searchPMTU(ctx, probe, PMTUMin, PMTUMax)
+		if ctx.Err() != nil {
+			return
+		}
+		if c.receiver.setPathMTU(clusterID, mtu) {
+			klog.Infof("conncheck pmtu: discovered path MTU %d towards %s", mtu, clusterID)
+		}
+
+		deadline := time.Now().Add(PMTURenegotiationInterval)
+		for time.Now().Before(deadline) {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(verifyInterval):
+			}
+			if mtu != 0 && !probe(ctx, mtu) {
+				klog.Warningf("conncheck pmtu: path MTU %d towards %s is no longer supported", mtu, clusterID)
+				break
+			}
+		}
+	}
+}
+
+// probe sends a PMTU probe of the given size to the given peer, and waits for the corresponding acknowledgment.
+func (c *ConnChecker) probe(ctx context.Context, clusterID string, size int) bool {
+	c.sm.RLock()
+	sender, ok := c.senders[clusterID]
+	c.sm.RUnlock()
+	acks := c.receiver.getProbeAcks(clusterID)
+	if !ok || acks == nil {
+		return false
+	}
+
+	for i := 0; i < probeAttempts; i++ {
+		if err := sender.SendProbe(size); err != nil {
+			// The probe cannot be sent at all (e.g., it is larger than the MTU of the tunnel interface).
+			klog.V(4).Infof("conncheck pmtu: failed to send probe of size %d: %v", size, err)
+			return false
+		}
+
+		timeout := time.After(PingInterval)
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				return false
+			case <-timeout:
+				break wait
+			case acked := <-acks:
+				// Acknowledgments of previous probes which have been received late are discarded.
+				if acked == size {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
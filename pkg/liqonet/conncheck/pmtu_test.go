@@ -0,0 +1,70 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conncheck
+
+import (
+	"context"
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PMTU discovery", func() {
+	var probed []int
+
+	// forgeProbe returns a probe function acknowledging the probes up to the given size.
+	forgeProbe := func(pmtu int) probeFunc {
+		return func(_ context.Context, size int) bool {
+			probed = append(probed, size)
+			return size <= pmtu
+		}
+	}
+
+	BeforeEach(func() { probed = nil })
+
+	DescribeTable("searching the path MTU",
+		func(pmtu, minSize, maxSize, expected int) {
+			Expect(searchPMTU(context.Background(), forgeProbe(pmtu), minSize, maxSize)).To(Equal(expected))
+		},
+		Entry("the path supports the maximum size", 9000, 1280, 1440, 1440),
+		Entry("the path supports only the minimum size", 1280, 1280, 1440, 1280),
+		Entry("the path supports an intermediate size", 1371, 1280, 1440, 1371),
+		Entry("the path does not support even the minimum size", 1000, 1280, 1440, 0),
+		Entry("the minimum is greater than the maximum", 1300, 1400, 1300, 1300),
+	)
+
+	It("should converge in a logarithmic number of probes", func() {
+		Expect(searchPMTU(context.Background(), forgeProbe(1400), 1280, 1500)).To(Equal(1400))
+		// Maximum, minimum and at most ceil(log2(220)) = 8 intermediate probes.
+		Expect(len(probed)).To(BeNumerically("<=", 10))
+	})
+
+	It("should stop when the context is canceled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		Expect(searchPMTU(ctx, forgeProbe(1400), 1280, 1500)).To(Equal(1280))
+		Expect(probed).To(Equal([]int{1500, 1280}))
+	})
+})
+
+var _ = DescribeTable("computing the size of the headers of a PMTU probe",
+	func(ip string, expected int) {
+		Expect(probeHeadersSize(net.ParseIP(ip))).To(Equal(expected))
+	},
+	Entry("IPv4 address", "169.254.0.1", probeIPv4HeadersSize),
+	Entry("IPv4-mapped IPv6 address", "::ffff:169.254.0.1", probeIPv4HeadersSize),
+	Entry("IPv6 address", "fd00::1", probeIPv6HeadersSize),
+)
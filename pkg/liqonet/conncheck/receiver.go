@@ -33,6 +33,11 @@ type Peer struct {
 	// lastReceivedTimestamp is the timestamp when the last received PING has been sent.
 	lastReceivedTimestamp time.Time
 	updateCallback        UpdateFunc
+	window                *statsWindow
+	// pathMTU is the path MTU discovered towards the peer (0 if unknown).
+	pathMTU int
+	// probeAcks receives the sizes of the acknowledged PMTU probes.
+	probeAcks chan int
 }
 
 // Receiver is a receiver for conncheck messages.
@@ -72,16 +77,18 @@ func (r *Receiver) ReceivePong(msg *Msg) error {
 	r.m.Lock()
 	defer r.m.Unlock()
 	if peer, ok := r.peers[msg.ClusterID]; ok {
+		now := time.Now()
+		// Out-of-order PONGs are accounted in the statistics window, since the corresponding PING has not been lost.
+		peer.window.addPong(msg.TimeStamp, now.Sub(msg.TimeStamp))
 		if msg.TimeStamp.Before(peer.lastReceivedTimestamp) {
 			klog.V(8).Infof("dropped a PONG message from %s because out-of-order", msg.ClusterID)
 			return nil
 		}
-		now := time.Now()
 		peer.lastReceivedTimestamp = msg.TimeStamp
 		peer.latency = now.Sub(msg.TimeStamp)
 		peer.connected = true
 
-		err := peer.updateCallback(true, peer.latency, now)
+		err := peer.updateCallback(true, peer.stats(now), now)
 		if err != nil {
 			return fmt.Errorf("failed to update peer %s: %w", msg.ClusterID, err)
 		}
@@ -99,10 +106,81 @@ func (r *Receiver) InitPeer(clusterID string, updateCallback UpdateFunc) error {
 		latency:               0,
 		lastReceivedTimestamp: time.Now(),
 		updateCallback:        updateCallback,
+		window:                newStatsWindow(PingStatsWindowSize),
+		probeAcks:             make(chan int, 1),
+	}
+	return nil
+}
+
+// RegisterPing records that a PING has been sent at the given timestamp to the given peer.
+func (r *Receiver) RegisterPing(clusterID string, timestamp time.Time) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if peer, ok := r.peers[clusterID]; ok {
+		peer.window.addPing(timestamp)
+	}
+}
+
+// stats returns the connection statistics of the peer, complemented with the latest latency.
+func (p *Peer) stats(now time.Time) ConnectionStats {
+	stats := p.window.stats(now, PingInterval*time.Duration(PingLossThreshold))
+	stats.Latency = p.latency
+	stats.PathMTU = p.pathMTU
+	return stats
+}
+
+// SendProbeAck acknowledges the reception of a PMTU probe. The acknowledgment is not padded,
+// since the path MTU in the reverse direction is discovered independently by the peer.
+func (r *Receiver) SendProbeAck(raddr *net.UDPAddr, msg *Msg) error {
+	msg.MsgType = PROBEACK
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal msg: %w", err)
+	}
+	_, err = r.conn.WriteToUDP(b, raddr)
+	if err != nil {
+		return fmt.Errorf("failed to write to %s: %w", raddr.String(), err)
+	}
+	klog.V(8).Infof("conncheck receiver: sent a PROBEACK -> %s", msg)
+	return nil
+}
+
+// ReceiveProbeAck notifies the PMTU discovery towards the given peer about the acknowledgment of a probe.
+func (r *Receiver) ReceiveProbeAck(msg *Msg) error {
+	r.m.RLock()
+	defer r.m.RUnlock()
+	if peer, ok := r.peers[msg.ClusterID]; ok {
+		select {
+		case peer.probeAcks <- msg.Size:
+		default:
+			klog.V(8).Infof("dropped a PROBEACK message from %s because no probe is pending", msg.ClusterID)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s sender has not been initialized", msg.ClusterID)
+}
+
+// getProbeAcks returns the channel receiving the acknowledgments of the PMTU probes sent to the given peer.
+func (r *Receiver) getProbeAcks(clusterID string) <-chan int {
+	r.m.RLock()
+	defer r.m.RUnlock()
+	if peer, ok := r.peers[clusterID]; ok {
+		return peer.probeAcks
 	}
 	return nil
 }
 
+// setPathMTU sets the path MTU discovered towards the given peer, and returns whether it changed.
+func (r *Receiver) setPathMTU(clusterID string, mtu int) bool {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if peer, ok := r.peers[clusterID]; ok && peer.pathMTU != mtu {
+		peer.pathMTU = mtu
+		return true
+	}
+	return false
+}
+
 // Run starts the receiver.
 func (r *Receiver) Run() {
 	klog.V(8).Infof("conncheck receiver: starting")
@@ -126,6 +204,12 @@ func (r *Receiver) Run() {
 		case PONG:
 			klog.V(8).Infof("conncheck receiver: received a PONG from %s  -> %s", raddr, msgr)
 			err = r.ReceivePong(msgr)
+		case PROBE:
+			klog.V(8).Infof("conncheck receiver: received a PROBE %s -> %s", raddr, msgr)
+			err = r.SendProbeAck(raddr, msgr)
+		case PROBEACK:
+			klog.V(8).Infof("conncheck receiver: received a PROBEACK from %s -> %s", raddr, msgr)
+			err = r.ReceiveProbeAck(msgr)
 		}
 		if err != nil {
 			klog.Errorf("conncheck receiver: %v", err)
@@ -148,7 +232,7 @@ func (r *Receiver) RunDisconnectObserver() {
 				klog.V(8).Infof("conncheck receiver: %s unreachable", id)
 				peer.connected = false
 				peer.latency = 0
-				err := peer.updateCallback(false, 0, time.Time{})
+				err := peer.updateCallback(false, peer.stats(time.Now()), time.Time{})
 				if err != nil {
 					klog.Errorf("conncheck receiver: failed to update peer %s: %s", peer.lastReceivedTimestamp, err)
 				}
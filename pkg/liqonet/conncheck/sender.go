@@ -15,6 +15,7 @@
 package conncheck
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -42,9 +43,9 @@ func NewSender(ctx context.Context, clusterID string, cancel func(), conn *net.U
 	}
 }
 
-// SendPing sends a PING message to the given address.
-func (s *Sender) SendPing(ctx context.Context) error {
-	msgOut := Msg{ClusterID: s.clusterID, MsgType: PING, TimeStamp: time.Now()}
+// SendPing sends a PING message, carrying the given timestamp, to the given address.
+func (s *Sender) SendPing(ctx context.Context, timestamp time.Time) error {
+	msgOut := Msg{ClusterID: s.clusterID, MsgType: PING, TimeStamp: timestamp}
 	b, err := json.Marshal(msgOut)
 	if err != nil {
 		return fmt.Errorf("conncheck sender: failed to marshal msg: %w", err)
@@ -56,3 +57,30 @@ func (s *Sender) SendPing(ctx context.Context) error {
 	klog.V(8).Infof("conncheck sender: sent a PING -> %s", msgOut)
 	return nil
 }
+
+// SendProbe sends a PMTU probe to the given address, padded so that the resulting IP packet has the given size.
+func (s *Sender) SendProbe(size int) error {
+	msgOut := Msg{ClusterID: s.clusterID, MsgType: PROBE, TimeStamp: time.Now(), Size: size}
+	b, err := json.Marshal(msgOut)
+	if err != nil {
+		return fmt.Errorf("conncheck sender: failed to marshal msg: %w", err)
+	}
+	// Trailing whitespaces are ignored when unmarshaling the message.
+	if padding := size - probeHeadersSize(s.raddr.IP) - len(b); padding > 0 {
+		b = append(b, bytes.Repeat([]byte(" "), padding)...)
+	}
+	_, err = s.conn.WriteToUDP(b, &s.raddr)
+	if err != nil {
+		return fmt.Errorf("conncheck sender: failed to write to %s: %w", s.raddr.String(), err)
+	}
+	klog.V(8).Infof("conncheck sender: sent a PROBE of size %d -> %s", size, msgOut)
+	return nil
+}
+
+// probeHeadersSize returns the size of the IP and UDP headers of a PMTU probe towards the given address.
+func probeHeadersSize(ip net.IP) int {
+	if ip.To4() != nil {
+		return probeIPv4HeadersSize
+	}
+	return probeIPv6HeadersSize
+}
@@ -0,0 +1,129 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conncheck
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// ConnectionStats summarizes the quality of the connection towards a peer, computed over a sliding window of pings.
+type ConnectionStats struct {
+	// Latency is the round-trip time measured by the most recent PONG.
+	Latency time.Duration
+	// Jitter is the mean absolute difference between the round-trip times of consecutive PONGs.
+	Jitter time.Duration
+	// P50 is the median of the round-trip times in the window.
+	P50 time.Duration
+	// P90 is the 90th percentile of the round-trip times in the window.
+	P90 time.Duration
+	// P99 is the 99th percentile of the round-trip times in the window.
+	P99 time.Duration
+	// PacketLoss is the ratio (between 0 and 1) of the PINGs in the window which have not been answered in time.
+	PacketLoss float64
+	// PathMTU is the path MTU discovered towards the peer, or 0 if unknown.
+	PathMTU int
+}
+
+// pingSample tracks the outcome of a single PING.
+type pingSample struct {
+	sent     time.Time
+	rtt      time.Duration
+	received bool
+}
+
+// statsWindow is a sliding window of the most recent PINGs sent to a peer.
+type statsWindow struct {
+	samples []pingSample
+	size    int
+}
+
+func newStatsWindow(size uint) *statsWindow {
+	if size == 0 {
+		size = 1
+	}
+	return &statsWindow{samples: make([]pingSample, 0, size), size: int(size)}
+}
+
+// addPing registers a new PING, evicting the oldest one if the window is full.
+func (w *statsWindow) addPing(sent time.Time) {
+	if len(w.samples) == w.size {
+		copy(w.samples, w.samples[1:])
+		w.samples = w.samples[:w.size-1]
+	}
+	w.samples = append(w.samples, pingSample{sent: sent})
+}
+
+// addPong marks the PING sent at the given timestamp as received. It returns false if no such PING is in the window.
+func (w *statsWindow) addPong(sent time.Time, rtt time.Duration) bool {
+	for i := len(w.samples) - 1; i >= 0; i-- {
+		if w.samples[i].sent.Equal(sent) {
+			w.samples[i].rtt = rtt
+			w.samples[i].received = true
+			return true
+		}
+	}
+	return false
+}
+
+// stats computes the connection statistics over the window. PINGs which have not been answered
+// within the given timeout are considered lost, while the more recent ones are not accounted yet.
+func (w *statsWindow) stats(now time.Time, timeout time.Duration) ConnectionStats {
+	var stats ConnectionStats
+	var lost, accounted int
+	rtts := make([]time.Duration, 0, len(w.samples))
+
+	for i := range w.samples {
+		switch {
+		case w.samples[i].received:
+			rtts = append(rtts, w.samples[i].rtt)
+			accounted++
+		case now.Sub(w.samples[i].sent) > timeout:
+			lost++
+			accounted++
+		}
+	}
+
+	if accounted > 0 {
+		stats.PacketLoss = float64(lost) / float64(accounted)
+	}
+	if len(rtts) == 0 {
+		return stats
+	}
+
+	var variation time.Duration
+	for i := 1; i < len(rtts); i++ {
+		variation += (rtts[i] - rtts[i-1]).Abs()
+	}
+	if len(rtts) > 1 {
+		stats.Jitter = variation / time.Duration(len(rtts)-1)
+	}
+
+	sort.Slice(rtts, func(i, j int) bool { return rtts[i] < rtts[j] })
+	stats.P50 = percentile(rtts, 50)
+	stats.P90 = percentile(rtts, 90)
+	stats.P99 = percentile(rtts, 99)
+	return stats
+}
+
+// percentile returns the p-th percentile of the given sorted values, according to the nearest-rank method.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,112 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conncheck
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Stats window", func() {
+	const timeout = 10 * time.Second
+
+	var (
+		window *statsWindow
+		now    time.Time
+	)
+
+	BeforeEach(func() {
+		window = newStatsWindow(5)
+		now = time.Now()
+	})
+
+	// sent returns the timestamp of the i-th PING, sent once per second and ending one minute ago.
+	sent := func(i int) time.Time { return now.Add(-time.Minute).Add(time.Duration(i) * time.Second) }
+
+	When("the window is empty", func() {
+		It("should return empty statistics", func() {
+			Expect(window.stats(now, timeout)).To(Equal(ConnectionStats{}))
+		})
+	})
+
+	When("all the PINGs have been answered", func() {
+		BeforeEach(func() {
+			for i, rtt := range []time.Duration{10, 30, 20, 40, 50} {
+				window.addPing(sent(i))
+				Expect(window.addPong(sent(i), rtt*time.Millisecond)).To(BeTrue())
+			}
+		})
+
+		It("should compute the correct statistics", func() {
+			Expect(window.stats(now, timeout)).To(Equal(ConnectionStats{
+				// (20 + 10 + 20 + 10) / 4
+				Jitter:     15 * time.Millisecond,
+				P50:        30 * time.Millisecond,
+				P90:        50 * time.Millisecond,
+				P99:        50 * time.Millisecond,
+				PacketLoss: 0,
+			}))
+		})
+
+		It("should evict the oldest PINGs when the window is full", func() {
+			window.addPing(sent(5))
+			Expect(window.addPong(sent(0), 10*time.Millisecond)).To(BeFalse())
+			Expect(window.addPong(sent(5), 60*time.Millisecond)).To(BeTrue())
+
+			stats := window.stats(now, timeout)
+			Expect(stats.P50).To(Equal(40 * time.Millisecond))
+			Expect(stats.Jitter).To(Equal(12500 * time.Microsecond))
+		})
+	})
+
+	When("some PINGs have not been answered", func() {
+		BeforeEach(func() {
+			for i := 0; i < 4; i++ {
+				window.addPing(sent(i))
+			}
+			Expect(window.addPong(sent(1), 10*time.Millisecond)).To(BeTrue())
+			Expect(window.addPong(sent(3), 20*time.Millisecond)).To(BeTrue())
+			// This PING has been sent recently, hence it is not yet accounted as lost.
+			window.addPing(now)
+		})
+
+		It("should compute the packet loss over the PINGs which are expired", func() {
+			stats := window.stats(now, timeout)
+			Expect(stats.PacketLoss).To(Equal(0.5))
+			Expect(stats.Jitter).To(Equal(10 * time.Millisecond))
+			Expect(stats.P50).To(Equal(10 * time.Millisecond))
+			Expect(stats.P99).To(Equal(20 * time.Millisecond))
+		})
+
+		It("should account the pending PINGs once expired", func() {
+			Expect(window.stats(now.Add(2*timeout), timeout).PacketLoss).To(Equal(0.6))
+		})
+	})
+})
+
+var _ = Describe("Percentile", func() {
+	DescribeTable("computing the percentile according to the nearest-rank method",
+		func(values []time.Duration, p float64, expected time.Duration) {
+			Expect(percentile(values, p)).To(Equal(expected))
+		},
+		Entry("single value", []time.Duration{5}, 50.0, time.Duration(5)),
+		Entry("median of an even number of values", []time.Duration{1, 2, 3, 4}, 50.0, time.Duration(2)),
+		Entry("90th percentile", []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, 90.0, time.Duration(9)),
+		Entry("99th percentile", []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, 99.0, time.Duration(10)),
+		Entry("0th percentile", []time.Duration{1, 2, 3}, 0.0, time.Duration(1)),
+	)
+})
@@ -32,6 +32,8 @@ const (
 	NotNil = "not nil"
 	// ValidCIDR used as reason of failure in WrongParameter error.
 	ValidCIDR = "a valid network CIDR"
+	// ValidIPv6CIDR used as reason of failure in WrongParameter error.
+	ValidIPv6CIDR = "a valid IPv6 network CIDR"
 	// StringNotEmpty used as reason of failure in WrongParameter error.
 	StringNotEmpty = "not empty"
 	// Initialization used as reason of failure in WrongParameter error.
@@ -0,0 +1,124 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firewall
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/coreos/go-iptables/iptables"
+	"k8s.io/klog/v2"
+)
+
+// Backend is the implementation used to configure the firewall.
+type Backend string
+
+const (
+	// IPTablesBackend configures the firewall through the iptables binaries.
+	IPTablesBackend Backend = "iptables"
+	// NFTablesBackend configures the firewall through the nftables netlink API.
+	NFTablesBackend Backend = "nftables"
+)
+
+// SelectedBackend is the backend used to configure the firewall.
+var SelectedBackend = IPTablesBackend
+
+// Backends returns the list of supported backends.
+func Backends() []string {
+	return []string{string(IPTablesBackend), string(NFTablesBackend)}
+}
+
+// Firewall abstracts the operations performed on the chains and rules of the filter and nat tables.
+// Rules are expressed through the iptables syntax, and listed in the format returned by "iptables -S".
+type Firewall interface {
+	// Proto returns the IP family configured by the firewall.
+	Proto() iptables.Protocol
+	// ListChains returns the chains in the given table.
+	ListChains(table string) ([]string, error)
+	// NewChain creates a new chain in the given table. It fails if the chain already exists.
+	NewChain(table, chain string) error
+	// ClearChain flushes the given chain, creating it if it does not exist.
+	ClearChain(table, chain string) error
+	// DeleteChain deletes the given (empty) chain.
+	DeleteChain(table, chain string) error
+	// ClearAndDeleteChain flushes and deletes the given chain, if it exists.
+	ClearAndDeleteChain(table, chain string) error
+	// List returns the rules in the given chain, prefixed by the chain declaration.
+	List(table, chain string) ([]string, error)
+	// Exists returns whether the given rule exists in the given chain.
+	Exists(table, chain string, rulespec ...string) (bool, error)
+	// Insert inserts the given rule at the given (1-based) position.
+	Insert(table, chain string, pos int, rulespec ...string) error
+	// AppendUnique appends the given rule, if it does not exist yet.
+	AppendUnique(table, chain string, rulespec ...string) error
+	// Delete removes the given rule. It fails if the rule does not exist.
+	Delete(table, chain string, rulespec ...string) error
+	// DeleteIfExists removes the given rule, if it exists.
+	DeleteIfExists(table, chain string, rulespec ...string) error
+}
+
+// New returns a firewall configuring the given IP family, through the selected backend.
+func New(proto iptables.Protocol) (Firewall, error) {
+	return NewWithBackend(SelectedBackend, proto)
+}
+
+// NewWithBackend returns a firewall configuring the given IP family, through the given backend.
+func NewWithBackend(backend Backend, proto iptables.Protocol) (Firewall, error) {
+	switch backend {
+	case IPTablesBackend:
+		ipt, err := newIPTables(proto)
+		if err != nil {
+			return nil, err
+		}
+		return ipt, nil
+	case NFTablesBackend:
+		nft := NewNFTables(proto)
+		warnDroppingChains(nft)
+		return nft, nil
+	default:
+		return nil, fmt.Errorf("unknown firewall backend %q", backend)
+	}
+}
+
+func newIPTables(proto iptables.Protocol) (*iptables.IPTables, error) {
+	selectedmode := os.Getenv("IPTABLES_MODE")
+	var ipt *iptables.IPTables
+	var err error
+	if iptables.ModeType(selectedmode) == iptables.ModeTypeNFTables || iptables.ModeType(selectedmode) == iptables.ModeTypeLegacy {
+		ipt, err = iptables.New(iptables.IPFamily(proto), iptables.Mode(iptables.ModeType(selectedmode)))
+	} else {
+		ipt, err = iptables.New(iptables.IPFamily(proto))
+	}
+	if err != nil {
+		return nil, err
+	}
+	v1, v2, v3, mode := ipt.GetIptablesVersion()
+	klog.Infof("Iptables version: %d.%d.%d, mode: %s, IPv6: %t", v1, v2, v3, mode, proto == iptables.ProtocolIPv6)
+	return ipt, nil
+}
+
+// warnDroppingChains warns about the base chains configured on the host which might drop the traffic accepted by Liqo.
+func warnDroppingChains(nft *NFTables) {
+	chains, err := nft.DroppingChains()
+	if err != nil {
+		klog.Warningf("Unable to check for nftables chains possibly dropping the Liqo traffic: %v", err)
+		return
+	}
+	if len(chains) > 0 {
+		klog.Warningf("The nftables chains %s have a drop policy, and may drop the traffic accepted by Liqo: "+
+			"make sure they explicitly accept the traffic from/to the Liqo interfaces", strings.Join(chains, ", "))
+	}
+}
@@ -0,0 +1,475 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firewall
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/coreos/go-iptables/iptables"
+	"github.com/google/nftables"
+)
+
+const (
+	// nftablesTablePrefix is the prefix of the nftables tables corresponding to the iptables ones (e.g., liqo-nat).
+	nftablesTablePrefix = "liqo-"
+	// commentUserDataType is the type of the user data attribute storing the comment of nftables rules.
+	commentUserDataType = 0
+)
+
+// baseChain describes the nftables base chain corresponding to an iptables built-in chain.
+type baseChain struct {
+	hook      nftables.ChainHook
+	priority  nftables.ChainPriority
+	chainType nftables.ChainType
+}
+
+// baseChains are the built-in chains of the supported tables, keyed by table and chain name.
+var baseChains = map[string]map[string]baseChain{
+	"filter": {
+		"INPUT":   {hook: nftables.ChainHookInput, priority: nftables.ChainPriorityFilter, chainType: nftables.ChainTypeFilter},
+		"FORWARD": {hook: nftables.ChainHookForward, priority: nftables.ChainPriorityFilter, chainType: nftables.ChainTypeFilter},
+		"OUTPUT":  {hook: nftables.ChainHookOutput, priority: nftables.ChainPriorityFilter, chainType: nftables.ChainTypeFilter},
+	},
+	"nat": {
+		"PREROUTING":  {hook: nftables.ChainHookPrerouting, priority: nftables.ChainPriorityNATDest, chainType: nftables.ChainTypeNAT},
+		"INPUT":       {hook: nftables.ChainHookInput, priority: nftables.ChainPriorityNATSource, chainType: nftables.ChainTypeNAT},
+		"OUTPUT":      {hook: nftables.ChainHookOutput, priority: nftables.ChainPriorityNATDest, chainType: nftables.ChainTypeNAT},
+		"POSTROUTING": {hook: nftables.ChainHookPostrouting, priority: nftables.ChainPriorityNATSource, chainType: nftables.ChainTypeNAT},
+	},
+}
+
+// NFTables implements the Firewall interface leveraging the nftables netlink API, without depending on any binary.
+// Each iptables table is mapped to a dedicated nftables table (e.g., nat to liqo-nat), whose built-in chains
+// are base chains attached to the same hooks, and with the same priorities, of the iptables ones.
+// Differently from iptables, the rules of these tables cannot override the drop verdicts of the other tables configured
+// on the host (e.g., by a host firewall), as all base chains attached to a given hook are evaluated (see DroppingChains).
+// The iptables specification of each rule is stored as the comment of the corresponding nftables rule,
+// hence allowing to list the rules in the same format returned by iptables.
+// Operations are performed in the network namespace of the calling thread.
+type NFTables struct {
+	proto iptables.Protocol
+}
+
+// NewNFTables returns a new nftables firewall configuring the given IP family.
+func NewNFTables(proto iptables.Protocol) *NFTables {
+	return &NFTables{proto: proto}
+}
+
+// Proto returns the IP family configured by the firewall.
+func (n *NFTables) Proto() iptables.Protocol {
+	return n.proto
+}
+
+// ListChains returns the chains in the given table.
+func (n *NFTables) ListChains(table string) ([]string, error) {
+	conn, err := n.ensureTable(table)
+	if err != nil {
+		return nil, err
+	}
+	chains, err := n.listChains(conn, table)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(chains))
+	for _, chain := range chains {
+		names = append(names, chain.Name)
+	}
+	return names, nil
+}
+
+// NewChain creates a new chain in the given table. It fails if the chain already exists.
+func (n *NFTables) NewChain(table, chain string) error {
+	conn, err := n.ensureTable(table)
+	if err != nil {
+		return err
+	}
+	if _, found, err := n.getChain(conn, table, chain); err != nil {
+		return err
+	} else if found {
+		return fmt.Errorf("chain %s already exists in table %s", chain, table)
+	}
+	conn.AddChain(&nftables.Chain{Name: chain, Table: n.table(table)})
+	return n.flush(conn, "create chain %s in table %s", chain, table)
+}
+
+// ClearChain flushes the given chain, creating it if it does not exist.
+func (n *NFTables) ClearChain(table, chain string) error {
+	conn, err := n.ensureTable(table)
+	if err != nil {
+		return err
+	}
+	existing, found, err := n.getChain(conn, table, chain)
+	if err != nil {
+		return err
+	}
+	if found {
+		conn.FlushChain(existing)
+	} else {
+		conn.AddChain(&nftables.Chain{Name: chain, Table: n.table(table)})
+	}
+	return n.flush(conn, "clear chain %s in table %s", chain, table)
+}
+
+// DeleteChain deletes the given (empty) chain.
+func (n *NFTables) DeleteChain(table, chain string) error {
+	conn, err := n.ensureTable(table)
+	if err != nil {
+		return err
+	}
+	if _, builtin := baseChains[table][chain]; builtin {
+		return fmt.Errorf("cannot delete built-in chain %s in table %s", chain, table)
+	}
+	existing, found, err := n.getChain(conn, table, chain)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("chain %s does not exist in table %s", chain, table)
+	}
+	conn.DelChain(existing)
+	return n.flush(conn, "delete chain %s in table %s", chain, table)
+}
+
+// ClearAndDeleteChain flushes and deletes the given chain, if it exists.
+func (n *NFTables) ClearAndDeleteChain(table, chain string) error {
+	conn, err := n.ensureTable(table)
+	if err != nil {
+		return err
+	}
+	existing, found, err := n.getChain(conn, table, chain)
+	if err != nil || !found {
+		return err
+	}
+	conn.FlushChain(existing)
+	conn.DelChain(existing)
+	return n.flush(conn, "delete chain %s in table %s", chain, table)
+}
+
+// List returns the rules in the given chain, prefixed by the chain declaration, in the format returned by "iptables -S".
+func (n *NFTables) List(table, chain string) ([]string, error) {
+	conn, err := n.ensureTable(table)
+	if err != nil {
+		return nil, err
+	}
+	existing, _, specs, err := n.listRules(conn, table, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]string, 0, len(specs)+1)
+	if _, builtin := baseChains[table][chain]; builtin {
+		rules = append(rules, fmt.Sprintf("-P %s ACCEPT", existing.Name))
+	} else {
+		rules = append(rules, fmt.Sprintf("-N %s", existing.Name))
+	}
+	for _, spec := range specs {
+		rules = append(rules, fmt.Sprintf("-A %s %s", existing.Name, spec))
+	}
+	return rules, nil
+}
+
+// Exists returns whether the given rule exists in the given chain.
+func (n *NFTables) Exists(table, chain string, rulespec ...string) (bool, error) {
+	conn, err := n.ensureTable(table)
+	if err != nil {
+		return false, err
+	}
+	rule, err := n.findRule(conn, table, chain, rulespec)
+	return rule != nil, err
+}
+
+// Insert inserts the given rule at the given (1-based) position.
+func (n *NFTables) Insert(table, chain string, pos int, rulespec ...string) error {
+	conn, err := n.ensureTable(table)
+	if err != nil {
+		return err
+	}
+	_, rules, _, err := n.listRules(conn, table, chain)
+	if err != nil {
+		return err
+	}
+	if pos < 1 || pos > len(rules)+1 {
+		return fmt.Errorf("invalid position %d for chain %s in table %s", pos, chain, table)
+	}
+
+	rule, err := n.forgeRule(table, chain, rulespec)
+	if err != nil {
+		return err
+	}
+	if pos == len(rules)+1 {
+		conn.AddRule(rule)
+	} else {
+		// The rule is inserted before the one currently at the given position.
+		rule.Position = rules[pos-1].Handle
+		conn.InsertRule(rule)
+	}
+	return n.flush(conn, "insert rule %q in chain %s (table %s)", specString(rulespec), chain, table)
+}
+
+// AppendUnique appends the given rule, if it does not exist yet.
+func (n *NFTables) AppendUnique(table, chain string, rulespec ...string) error {
+	conn, err := n.ensureTable(table)
+	if err != nil {
+		return err
+	}
+	if existing, err := n.findRule(conn, table, chain, rulespec); err != nil || existing != nil {
+		return err
+	}
+	rule, err := n.forgeRule(table, chain, rulespec)
+	if err != nil {
+		return err
+	}
+	conn.AddRule(rule)
+	return n.flush(conn, "append rule %q in chain %s (table %s)", specString(rulespec), chain, table)
+}
+
+// Delete removes the given rule. It fails if the rule does not exist.
+func (n *NFTables) Delete(table, chain string, rulespec ...string) error {
+	return n.delete(table, chain, rulespec, false)
+}
+
+// DeleteIfExists removes the given rule, if it exists.
+func (n *NFTables) DeleteIfExists(table, chain string, rulespec ...string) error {
+	return n.delete(table, chain, rulespec, true)
+}
+
+func (n *NFTables) delete(table, chain string, rulespec []string, ignoreMissing bool) error {
+	conn, err := n.ensureTable(table)
+	if err != nil {
+		return err
+	}
+	existing, err := n.findRule(conn, table, chain, rulespec)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		if ignoreMissing {
+			return nil
+		}
+		return fmt.Errorf("rule %q does not exist in chain %s (table %s)", specString(rulespec), chain, table)
+	}
+	if err := conn.DelRule(existing); err != nil {
+		return err
+	}
+	return n.flush(conn, "delete rule %q from chain %s (table %s)", specString(rulespec), chain, table)
+}
+
+// DroppingChains returns the base chains of the tables not managed by Liqo (in the configured or in the inet family),
+// attached to the filter hooks and with a drop policy. Indeed, as per nftables semantics, an accept verdict only
+// terminates the evaluation of the current base chain: packets accepted by the Liqo tables are still dropped by
+// these chains, unless explicitly accepted by their own rules, which need to be configured by the administrator.
+func (n *NFTables) DroppingChains() ([]string, error) {
+	conn, err := nftables.New()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create nftables connection: %w", err)
+	}
+	chains, err := conn.ListChains()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list chains: %w", err)
+	}
+	return droppingChains(chains, n.family()), nil
+}
+
+// droppingChains returns the names, in the table/chain format, of the given base chains matching the DroppingChains criteria.
+func droppingChains(chains []*nftables.Chain, family nftables.TableFamily) []string {
+	var names []string
+	for _, chain := range chains {
+		// Only base chains are characterized by a policy.
+		if chain.Table == nil || chain.Policy == nil || *chain.Policy != nftables.ChainPolicyDrop || chain.Type != nftables.ChainTypeFilter {
+			continue
+		}
+		if strings.HasPrefix(chain.Table.Name, nftablesTablePrefix) ||
+			(chain.Table.Family != family && chain.Table.Family != nftables.TableFamilyINet) {
+			continue
+		}
+		switch chain.Hooknum {
+		case nftables.ChainHookInput, nftables.ChainHookForward, nftables.ChainHookOutput:
+			names = append(names, chain.Table.Name+"/"+chain.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// family returns the nftables family corresponding to the configured IP family.
+func (n *NFTables) family() nftables.TableFamily {
+	if n.proto == iptables.ProtocolIPv6 {
+		return nftables.TableFamilyIPv6
+	}
+	return nftables.TableFamilyIPv4
+}
+
+// table returns the nftables table corresponding to the given iptables one.
+func (n *NFTables) table(table string) *nftables.Table {
+	return &nftables.Table{Name: nftablesTablePrefix + table, Family: n.family()}
+}
+
+// ensureTable makes sure the nftables table corresponding to the given iptables one exists,
+// along with its base chains, and returns the connection to perform the subsequent operations.
+func (n *NFTables) ensureTable(table string) (*nftables.Conn, error) {
+	chains, ok := baseChains[table]
+	if !ok {
+		return nil, fmt.Errorf("unsupported table %s", table)
+	}
+
+	conn, err := nftables.New()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create nftables connection: %w", err)
+	}
+
+	policy := nftables.ChainPolicyAccept
+	nftable := conn.AddTable(n.table(table))
+	for name, chain := range chains {
+		conn.AddChain(&nftables.Chain{
+			Name:     name,
+			Table:    nftable,
+			Hooknum:  chain.hook,
+			Priority: chain.priority,
+			Type:     chain.chainType,
+			Policy:   &policy,
+		})
+	}
+	if err := n.flush(conn, "ensure table %s", table); err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (n *NFTables) listChains(conn *nftables.Conn, table string) ([]*nftables.Chain, error) {
+	chains, err := conn.ListChainsOfTableFamily(n.family())
+	if err != nil {
+		return nil, fmt.Errorf("unable to list chains in table %s: %w", table, err)
+	}
+	filtered := make([]*nftables.Chain, 0, len(chains))
+	for _, chain := range chains {
+		if chain.Table.Name == n.table(table).Name {
+			filtered = append(filtered, chain)
+		}
+	}
+	return filtered, nil
+}
+
+func (n *NFTables) getChain(conn *nftables.Conn, table, chain string) (*nftables.Chain, bool, error) {
+	chains, err := n.listChains(conn, table)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, existing := range chains {
+		if existing.Name == chain {
+			return existing, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// listRules returns the rules in the given chain, along with the corresponding iptables specifications.
+func (n *NFTables) listRules(conn *nftables.Conn, table, chain string) (*nftables.Chain, []*nftables.Rule, []string, error) {
+	existing, found, err := n.getChain(conn, table, chain)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !found {
+		return nil, nil, nil, fmt.Errorf("chain %s does not exist in table %s", chain, table)
+	}
+	rules, err := conn.GetRules(n.table(table), existing)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to list rules in chain %s (table %s): %w", chain, table, err)
+	}
+	specs := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		specs = append(specs, decodeComment(rule.UserData))
+	}
+	return existing, rules, specs, nil
+}
+
+// findRule returns the rule in the given chain matching the given specification, if any.
+func (n *NFTables) findRule(conn *nftables.Conn, table, chain string, rulespec []string) (*nftables.Rule, error) {
+	_, rules, specs, err := n.listRules(conn, table, chain)
+	if err != nil {
+		return nil, err
+	}
+	spec := specString(rulespec)
+	for i := range rules {
+		if specs[i] == spec {
+			return rules[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// forgeRule translates the given iptables specification into the corresponding nftables rule.
+func (n *NFTables) forgeRule(table, chain string, rulespec []string) (*nftables.Rule, error) {
+	exprs, err := ruleExprs(n.proto, chain, rulespec)
+	if err != nil {
+		return nil, fmt.Errorf("unable to translate rule %q: %w", specString(rulespec), err)
+	}
+	userData, err := encodeComment(specString(rulespec))
+	if err != nil {
+		return nil, err
+	}
+	return &nftables.Rule{
+		Table:    n.table(table),
+		Chain:    &nftables.Chain{Name: chain, Table: n.table(table)},
+		Exprs:    exprs,
+		UserData: userData,
+	}, nil
+}
+
+func (n *NFTables) flush(conn *nftables.Conn, format string, args ...interface{}) error {
+	if err := conn.Flush(); err != nil {
+		return fmt.Errorf("unable to %s: %w", fmt.Sprintf(format, args...), err)
+	}
+	return nil
+}
+
+// specString returns the representation of the given rule specification in the format returned by "iptables -S".
+func specString(rulespec []string) string {
+	quoted := make([]string, 0, len(rulespec))
+	for _, token := range rulespec {
+		if strings.ContainsAny(token, " \t") {
+			token = fmt.Sprintf("%q", token)
+		}
+		quoted = append(quoted, token)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// encodeComment encodes the given comment as the user data of an nftables rule.
+func encodeComment(comment string) ([]byte, error) {
+	// The length includes the string terminator, and must fit in a single byte.
+	if len(comment)+1 > 255 {
+		return nil, fmt.Errorf("rule %q exceeds the maximum length", comment)
+	}
+	data := []byte{commentUserDataType, byte(len(comment) + 1)}
+	data = append(data, comment...)
+	return append(data, 0), nil
+}
+
+// decodeComment extracts the comment from the user data of an nftables rule.
+func decodeComment(data []byte) string {
+	for len(data) >= 2 {
+		attrType, attrLen := data[0], int(data[1])
+		if len(data) < 2+attrLen {
+			break
+		}
+		if attrType == commentUserDataType {
+			return strings.TrimRight(string(data[2:2+attrLen]), "\x00")
+		}
+		data = data[2+attrLen:]
+	}
+	return ""
+}
@@ -0,0 +1,343 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firewall
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-iptables/iptables"
+	"github.com/google/nftables/binaryutil"
+	"github.com/google/nftables/expr"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// ifNameSize is the size of the interface names compared by nftables (i.e., IFNAMSIZ).
+	ifNameSize = 16
+	// register is the nftables register leveraged to perform the comparisons.
+	register = 1
+)
+
+// protocols maps the protocols supported by the "-p" option to the corresponding numbers.
+var protocols = map[string]byte{
+	"icmp":   unix.IPPROTO_ICMP,
+	"tcp":    unix.IPPROTO_TCP,
+	"udp":    unix.IPPROTO_UDP,
+	"icmpv6": unix.IPPROTO_ICMPV6,
+	"sctp":   unix.IPPROTO_SCTP,
+}
+
+// ctStates maps the connection tracking states supported by the "--ctstate" option to the corresponding bits.
+var ctStates = map[string]uint32{
+	"INVALID":     expr.CtStateBitINVALID,
+	"ESTABLISHED": expr.CtStateBitESTABLISHED,
+	"RELATED":     expr.CtStateBitRELATED,
+	"NEW":         expr.CtStateBitNEW,
+	"UNTRACKED":   expr.CtStateBitUNTRACKED,
+}
+
+// ruleExprs translates the given iptables rule specification into the corresponding nftables expressions.
+// Only the subset of the iptables syntax leveraged by the network fabric is supported. The chain is used
+// to determine whether NETMAP targets translate the source (postrouting chains) or the destination address.
+func ruleExprs(proto iptables.Protocol, chain string, rulespec []string) ([]expr.Any, error) {
+	var exprs []expr.Any
+	negate := false
+
+	next := func(i *int) (string, error) {
+		*i++
+		if *i >= len(rulespec) {
+			return "", fmt.Errorf("missing value for option %s", rulespec[*i-1])
+		}
+		return rulespec[*i], nil
+	}
+
+	for i := 0; i < len(rulespec); i++ {
+		option := rulespec[i]
+		if option == "!" {
+			negate = true
+			continue
+		}
+
+		switch option {
+		case "-s", "--source", "-d", "--destination":
+			value, err := next(&i)
+			if err != nil {
+				return nil, err
+			}
+			matches, err := addressMatch(proto, option == "-s" || option == "--source", value, negate)
+			if err != nil {
+				return nil, err
+			}
+			exprs = append(exprs, matches...)
+		case "-i", "--in-interface", "-o", "--out-interface":
+			value, err := next(&i)
+			if err != nil {
+				return nil, err
+			}
+			key := expr.MetaKeyIIFNAME
+			if option == "-o" || option == "--out-interface" {
+				key = expr.MetaKeyOIFNAME
+			}
+			exprs = append(exprs,
+				&expr.Meta{Key: key, Register: register},
+				&expr.Cmp{Op: cmpOp(negate), Register: register, Data: ifName(value)})
+		case "-p", "--protocol":
+			value, err := next(&i)
+			if err != nil {
+				return nil, err
+			}
+			number, ok := protocols[strings.ToLower(value)]
+			if !ok {
+				return nil, fmt.Errorf("unsupported protocol %q", value)
+			}
+			exprs = append(exprs,
+				&expr.Meta{Key: expr.MetaKeyL4PROTO, Register: register},
+				&expr.Cmp{Op: cmpOp(negate), Register: register, Data: []byte{number}})
+		case "--sport", "--source-port", "--dport", "--destination-port":
+			value, err := next(&i)
+			if err != nil {
+				return nil, err
+			}
+			port, err := strconv.ParseUint(value, 10, 16)
+			if err != nil {
+				return nil, fmt.Errorf("invalid port %q: %w", value, err)
+			}
+			offset := uint32(0)
+			if option == "--dport" || option == "--destination-port" {
+				offset = 2
+			}
+			exprs = append(exprs,
+				&expr.Payload{DestRegister: register, Base: expr.PayloadBaseTransportHeader, Offset: offset, Len: 2},
+				&expr.Cmp{Op: cmpOp(negate), Register: register, Data: binaryutil.BigEndian.PutUint16(uint16(port))})
+		case "--ctstate":
+			value, err := next(&i)
+			if err != nil {
+				return nil, err
+			}
+			var mask uint32
+			for _, state := range strings.Split(value, ",") {
+				bit, ok := ctStates[state]
+				if !ok {
+					return nil, fmt.Errorf("unsupported connection tracking state %q", state)
+				}
+				mask |= bit
+			}
+			// The match is negated by checking whether none of the given bits is set.
+			op := expr.CmpOpNeq
+			if negate {
+				op = expr.CmpOpEq
+			}
+			exprs = append(exprs,
+				&expr.Ct{Register: register, Key: expr.CtKeySTATE},
+				&expr.Bitwise{SourceRegister: register, DestRegister: register, Len: 4,
+					Mask: binaryutil.NativeEndian.PutUint32(mask), Xor: binaryutil.NativeEndian.PutUint32(0)},
+				&expr.Cmp{Op: op, Register: register, Data: binaryutil.NativeEndian.PutUint32(0)})
+		case "-m", "--match":
+			// Matches are implicitly loaded by the corresponding options.
+			if _, err := next(&i); err != nil {
+				return nil, err
+			}
+		case "--comment":
+			// Comments are stored as part of the whole rule specification.
+			if _, err := next(&i); err != nil {
+				return nil, err
+			}
+		case "-j", "--jump":
+			target, err := next(&i)
+			if err != nil {
+				return nil, err
+			}
+			targetExprs, consumed, err := targetExprs(proto, chain, target, rulespec[i+1:])
+			if err != nil {
+				return nil, err
+			}
+			exprs = append(exprs, targetExprs...)
+			i += consumed
+		default:
+			return nil, fmt.Errorf("unsupported option %q", option)
+		}
+		negate = false
+	}
+	return exprs, nil
+}
+
+// targetExprs returns the expressions corresponding to the given target, along with the number of target options consumed.
+func targetExprs(proto iptables.Protocol, chain, target string, options []string) (exprs []expr.Any, consumed int, err error) {
+	value := func(option string) (string, error) {
+		if len(options) < 2 || options[0] != option {
+			return "", fmt.Errorf("target %s requires option %s", target, option)
+		}
+		return options[1], nil
+	}
+
+	switch target {
+	case "ACCEPT":
+		return []expr.Any{&expr.Verdict{Kind: expr.VerdictAccept}}, 0, nil
+	case "DROP":
+		return []expr.Any{&expr.Verdict{Kind: expr.VerdictDrop}}, 0, nil
+	case "RETURN":
+		return []expr.Any{&expr.Verdict{Kind: expr.VerdictReturn}}, 0, nil
+	case "MASQUERADE":
+		return []expr.Any{&expr.Masq{}}, 0, nil
+	case "SNAT", "DNAT":
+		option, natType := "--to-source", expr.NATTypeSourceNAT
+		if target == "DNAT" {
+			option, natType = "--to-destination", expr.NATTypeDestNAT
+		}
+		address, err := value(option)
+		if err != nil {
+			return nil, 0, err
+		}
+		ip, err := parseIP(proto, address)
+		if err != nil {
+			return nil, 0, err
+		}
+		return []expr.Any{
+			&expr.Immediate{Register: register, Data: ip},
+			&expr.NAT{Type: natType, Family: natFamily(proto), RegAddrMin: register},
+		}, 2, nil
+	case "NETMAP":
+		cidr, err := value("--to")
+		if err != nil {
+			return nil, 0, err
+		}
+		network, err := parseNetwork(proto, cidr)
+		if err != nil {
+			return nil, 0, err
+		}
+		// NETMAP translates the source address in the postrouting chains, and the destination one otherwise.
+		// The prefix of the original address is replaced with the target one, while preserving the host part.
+		source := isPostroutingChain(chain)
+		natType := expr.NATTypeDestNAT
+		if source {
+			natType = expr.NATTypeSourceNAT
+		}
+		hostmask := make([]byte, len(network.Mask))
+		for i := range network.Mask {
+			hostmask[i] = ^network.Mask[i]
+		}
+		return []expr.Any{
+			addressPayload(proto, source),
+			&expr.Bitwise{SourceRegister: register, DestRegister: register, Len: uint32(len(hostmask)), Mask: hostmask, Xor: network.IP},
+			&expr.NAT{Type: natType, Family: natFamily(proto), RegAddrMin: register},
+		}, 2, nil
+	default:
+		// Any other target is considered a chain to jump to.
+		return []expr.Any{&expr.Verdict{Kind: expr.VerdictJump, Chain: target}}, 0, nil
+	}
+}
+
+// addressMatch returns the expressions matching the source (or destination) address against the given IP or CIDR.
+func addressMatch(proto iptables.Protocol, source bool, value string, negate bool) ([]expr.Any, error) {
+	var network *net.IPNet
+	var err error
+	if strings.Contains(value, "/") {
+		network, err = parseNetwork(proto, value)
+	} else {
+		var ip net.IP
+		ip, err = parseIP(proto, value)
+		network = &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	exprs := []expr.Any{addressPayload(proto, source)}
+	if ones, bits := network.Mask.Size(); ones != bits {
+		exprs = append(exprs, &expr.Bitwise{SourceRegister: register, DestRegister: register,
+			Len: uint32(len(network.Mask)), Mask: network.Mask, Xor: make([]byte, len(network.Mask))})
+	}
+	return append(exprs, &expr.Cmp{Op: cmpOp(negate), Register: register, Data: network.IP}), nil
+}
+
+// addressPayload returns the expression loading the source (or destination) address in the register.
+func addressPayload(proto iptables.Protocol, source bool) *expr.Payload {
+	if proto == iptables.ProtocolIPv6 {
+		offset := uint32(24)
+		if source {
+			offset = 8
+		}
+		return &expr.Payload{DestRegister: register, Base: expr.PayloadBaseNetworkHeader, Offset: offset, Len: net.IPv6len}
+	}
+	offset := uint32(16)
+	if source {
+		offset = 12
+	}
+	return &expr.Payload{DestRegister: register, Base: expr.PayloadBaseNetworkHeader, Offset: offset, Len: net.IPv4len}
+}
+
+// parseIP parses the given IP address, checking it belongs to the given family.
+func parseIP(proto iptables.Protocol, value string) (net.IP, error) {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address %q", value)
+	}
+	return normalizeIP(proto, ip, value)
+}
+
+// parseNetwork parses the given CIDR, checking it belongs to the given family.
+func parseNetwork(proto iptables.Protocol, value string) (*net.IPNet, error) {
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CIDR %q: %w", value, err)
+	}
+	if network.IP, err = normalizeIP(proto, network.IP, value); err != nil {
+		return nil, err
+	}
+	return network, nil
+}
+
+func normalizeIP(proto iptables.Protocol, ip net.IP, value string) (net.IP, error) {
+	if proto == iptables.ProtocolIPv6 {
+		if ip.To4() != nil {
+			return nil, fmt.Errorf("%q is not an IPv6 address", value)
+		}
+		return ip.To16(), nil
+	}
+	if ip.To4() == nil {
+		return nil, fmt.Errorf("%q is not an IPv4 address", value)
+	}
+	return ip.To4(), nil
+}
+
+// ifName returns the given interface name in the format compared by nftables.
+func ifName(name string) []byte {
+	data := make([]byte, ifNameSize)
+	copy(data, name)
+	return data
+}
+
+func cmpOp(negate bool) expr.CmpOp {
+	if negate {
+		return expr.CmpOpNeq
+	}
+	return expr.CmpOpEq
+}
+
+func natFamily(proto iptables.Protocol) uint32 {
+	if proto == iptables.ProtocolIPv6 {
+		return unix.NFPROTO_IPV6
+	}
+	return unix.NFPROTO_IPV4
+}
+
+// isPostroutingChain returns whether the given chain is traversed in the postrouting hook,
+// according to the naming convention of the built-in and Liqo chains (e.g., LIQO-PSTRT-CLS-).
+func isPostroutingChain(chain string) bool {
+	return strings.Contains(chain, "POSTROUTING") || strings.Contains(chain, "PSTRT")
+}
@@ -0,0 +1,104 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firewall
+
+import (
+	"github.com/coreos/go-iptables/iptables"
+	"github.com/google/nftables/binaryutil"
+	"github.com/google/nftables/expr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/sys/unix"
+)
+
+var _ = Describe("The translation of iptables rules", func() {
+	It("should translate address and interface matches", func() {
+		exprs, err := ruleExprs(iptables.ProtocolIPv4, "LIQO-FORWARD",
+			[]string{"!", "-s", "10.0.0.0/24", "-d", "10.1.0.1", "-i", "liqo.vxlan", "-j", "LIQO-FRWD-EXT-CLS-foo"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exprs).To(Equal([]expr.Any{
+			&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseNetworkHeader, Offset: 12, Len: 4},
+			&expr.Bitwise{SourceRegister: 1, DestRegister: 1, Len: 4, Mask: []byte{255, 255, 255, 0}, Xor: []byte{0, 0, 0, 0}},
+			&expr.Cmp{Op: expr.CmpOpNeq, Register: 1, Data: []byte{10, 0, 0, 0}},
+			&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseNetworkHeader, Offset: 16, Len: 4},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{10, 1, 0, 1}},
+			&expr.Meta{Key: expr.MetaKeyIIFNAME, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte("liqo.vxlan\x00\x00\x00\x00\x00\x00")},
+			&expr.Verdict{Kind: expr.VerdictJump, Chain: "LIQO-FRWD-EXT-CLS-foo"},
+		}))
+	})
+
+	It("should translate protocol, port and connection tracking matches", func() {
+		exprs, err := ruleExprs(iptables.ProtocolIPv6, "LIQO-POL-ALW-CLS-foo",
+			[]string{"-p", "tcp", "-m", "tcp", "--dport", "80", "-m", "conntrack", "--ctstate", "RELATED,ESTABLISHED",
+				"-m", "comment", "--comment", "a comment", "-j", "ACCEPT"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exprs).To(Equal([]expr.Any{
+			&expr.Meta{Key: expr.MetaKeyL4PROTO, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{unix.IPPROTO_TCP}},
+			&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseTransportHeader, Offset: 2, Len: 2},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{0, 80}},
+			&expr.Ct{Register: 1, Key: expr.CtKeySTATE},
+			&expr.Bitwise{SourceRegister: 1, DestRegister: 1, Len: 4,
+				Mask: binaryutil.NativeEndian.PutUint32(expr.CtStateBitRELATED | expr.CtStateBitESTABLISHED),
+				Xor:  binaryutil.NativeEndian.PutUint32(0)},
+			&expr.Cmp{Op: expr.CmpOpNeq, Register: 1, Data: binaryutil.NativeEndian.PutUint32(0)},
+			&expr.Verdict{Kind: expr.VerdictAccept},
+		}))
+	})
+
+	It("should translate the SNAT target", func() {
+		exprs, err := ruleExprs(iptables.ProtocolIPv4, "LIQO-PSTRT-CLS-foo",
+			[]string{"!", "-s", "10.0.0.0/24", "-j", "SNAT", "--to-source", "10.0.0.1"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exprs[len(exprs)-2:]).To(Equal([]expr.Any{
+			&expr.Immediate{Register: 1, Data: []byte{10, 0, 0, 1}},
+			&expr.NAT{Type: expr.NATTypeSourceNAT, Family: unix.NFPROTO_IPV4, RegAddrMin: 1},
+		}))
+	})
+
+	DescribeTable("should translate the NETMAP target according to the chain",
+		func(chain string, source bool, natType expr.NATType) {
+			exprs, err := ruleExprs(iptables.ProtocolIPv4, chain, []string{"-j", "NETMAP", "--to", "10.2.0.0/16"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exprs).To(Equal([]expr.Any{
+				addressPayload(iptables.ProtocolIPv4, source),
+				&expr.Bitwise{SourceRegister: 1, DestRegister: 1, Len: 4, Mask: []byte{0, 0, 255, 255}, Xor: []byte{10, 2, 0, 0}},
+				&expr.NAT{Type: natType, Family: unix.NFPROTO_IPV4, RegAddrMin: 1},
+			}))
+		},
+		Entry("postrouting chain", "LIQO-PSTRT-CLS-foo", true, expr.NATTypeSourceNAT),
+		Entry("prerouting chain", "LIQO-PRRT-CLS-foo", false, expr.NATTypeDestNAT),
+	)
+
+	DescribeTable("should reject unsupported rules",
+		func(proto iptables.Protocol, rulespec []string) {
+			_, err := ruleExprs(proto, "LIQO-FORWARD", rulespec)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("unsupported option", iptables.ProtocolIPv4, []string{"--unknown", "-j", "ACCEPT"}),
+		Entry("missing value", iptables.ProtocolIPv4, []string{"-s"}),
+		Entry("address of the wrong family", iptables.ProtocolIPv6, []string{"-s", "10.0.0.1", "-j", "ACCEPT"}),
+		Entry("missing target option", iptables.ProtocolIPv4, []string{"-j", "DNAT"}),
+	)
+
+	It("should encode the rule specification as comment", func() {
+		spec := specString([]string{"-m", "comment", "--comment", "a comment", "-j", "ACCEPT"})
+		Expect(spec).To(Equal(`-m comment --comment "a comment" -j ACCEPT`))
+		data, err := encodeComment(spec)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(decodeComment(data)).To(Equal(spec))
+	})
+})
@@ -0,0 +1,126 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firewall
+
+import (
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/coreos/go-iptables/iptables"
+	"github.com/google/nftables"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("The nftables firewall", func() {
+	const (
+		chain = "LIQO-PSTRT-CLS-foo"
+		table = "nat"
+	)
+
+	var fw *NFTables
+
+	// inNetns executes the given function in the test network namespace.
+	inNetns := func(f func()) {
+		Expect(testNetns.Do(func(ns.NetNS) error {
+			defer GinkgoRecover()
+			f()
+			return nil
+		})).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		fw = NewNFTables(iptables.ProtocolIPv4)
+	})
+
+	AfterEach(func() {
+		inNetns(func() {
+			Expect(fw.ClearChain(table, "POSTROUTING")).To(Succeed())
+			Expect(fw.ClearAndDeleteChain(table, chain)).To(Succeed())
+		})
+	})
+
+	It("should list the built-in chains", func() {
+		inNetns(func() {
+			Expect(fw.ListChains(table)).To(ConsistOf("PREROUTING", "INPUT", "OUTPUT", "POSTROUTING"))
+			Expect(fw.ListChains("filter")).To(ConsistOf("INPUT", "FORWARD", "OUTPUT"))
+			_, err := fw.ListChains("mangle")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	It("should manage the lifecycle of chains", func() {
+		inNetns(func() {
+			Expect(fw.NewChain(table, chain)).To(Succeed())
+			Expect(fw.NewChain(table, chain)).ToNot(Succeed())
+			Expect(fw.ListChains(table)).To(ContainElement(chain))
+			Expect(fw.List(table, chain)).To(Equal([]string{"-N " + chain}))
+
+			Expect(fw.DeleteChain(table, chain)).To(Succeed())
+			Expect(fw.ListChains(table)).ToNot(ContainElement(chain))
+			Expect(fw.DeleteChain(table, chain)).ToNot(Succeed())
+			Expect(fw.DeleteChain(table, "POSTROUTING")).ToNot(Succeed())
+		})
+	})
+
+	It("should manage the lifecycle of rules", func() {
+		snat := []string{"!", "-s", "10.0.0.0/24", "-d", "10.1.0.0/24", "-j", "SNAT", "--to-source", "10.0.0.1"}
+		netmap := []string{"-s", "10.0.0.0/24", "-d", "10.1.0.0/24", "-j", "NETMAP", "--to", "10.2.0.0/24"}
+		comment := []string{"-d", "10.1.0.0/24", "-m", "comment", "--comment", "SNAT local traffic", "-j", chain}
+
+		inNetns(func() {
+			Expect(fw.ClearChain(table, chain)).To(Succeed())
+			Expect(fw.AppendUnique(table, chain, snat...)).To(Succeed())
+			Expect(fw.AppendUnique(table, chain, snat...)).To(Succeed())
+			Expect(fw.Insert(table, chain, 1, netmap...)).To(Succeed())
+			Expect(fw.List(table, chain)).To(Equal([]string{
+				"-N " + chain,
+				"-A " + chain + " -s 10.0.0.0/24 -d 10.1.0.0/24 -j NETMAP --to 10.2.0.0/24",
+				"-A " + chain + " ! -s 10.0.0.0/24 -d 10.1.0.0/24 -j SNAT --to-source 10.0.0.1",
+			}))
+
+			Expect(fw.Insert(table, "POSTROUTING", 1, comment...)).To(Succeed())
+			Expect(fw.Exists(table, "POSTROUTING", comment...)).To(BeTrue())
+			Expect(fw.List(table, "POSTROUTING")).To(Equal([]string{
+				"-P POSTROUTING ACCEPT",
+				"-A POSTROUTING -d 10.1.0.0/24 -m comment --comment \"SNAT local traffic\" -j " + chain,
+			}))
+
+			Expect(fw.Delete(table, chain, snat...)).To(Succeed())
+			Expect(fw.Delete(table, chain, snat...)).ToNot(Succeed())
+			Expect(fw.DeleteIfExists(table, chain, snat...)).To(Succeed())
+			Expect(fw.Exists(table, chain, snat...)).To(BeFalse())
+
+			Expect(fw.ClearChain(table, chain)).To(Succeed())
+			Expect(fw.List(table, chain)).To(Equal([]string{"-N " + chain}))
+		})
+	})
+
+	It("should detect the base chains of other tables with a drop policy", func() {
+		drop, accept := nftables.ChainPolicyDrop, nftables.ChainPolicyAccept
+		chain := func(table string, family nftables.TableFamily, name string, hook nftables.ChainHook,
+			policy *nftables.ChainPolicy) *nftables.Chain {
+			return &nftables.Chain{Name: name, Table: &nftables.Table{Name: table, Family: family},
+				Hooknum: hook, Type: nftables.ChainTypeFilter, Policy: policy}
+		}
+
+		Expect(droppingChains([]*nftables.Chain{
+			chain("filter", nftables.TableFamilyIPv4, "input", nftables.ChainHookInput, &drop),
+			chain("firewalld", nftables.TableFamilyINet, "forward", nftables.ChainHookForward, &drop),
+			chain("filter", nftables.TableFamilyIPv4, "output", nftables.ChainHookOutput, &accept),
+			chain("filter", nftables.TableFamilyIPv4, "custom", nftables.ChainHookInput, nil),
+			chain("filter6", nftables.TableFamilyIPv6, "input", nftables.ChainHookInput, &drop),
+			chain("liqo-filter", nftables.TableFamilyIPv4, "INPUT", nftables.ChainHookInput, &drop),
+		}, nftables.TableFamilyIPv4)).To(Equal([]string{"filter/input", "firewalld/forward"}))
+	})
+})
@@ -103,3 +103,39 @@ func (mock *IPAMClient) BelongsToPodCIDR(context.Context, *ipam.BelongsRequest,
 	...grpc.CallOption) (*ipam.BelongsResponse, error) {
 	return &ipam.BelongsResponse{Belongs: true}, nil
 }
+
+// ListPools mocks the corresponding IPAMClient function.
+func (mock *IPAMClient) ListPools(context.Context, *ipam.ListPoolsRequest,
+	...grpc.CallOption) (*ipam.ListPoolsResponse, error) {
+	return &ipam.ListPoolsResponse{}, nil
+}
+
+// ListReservedSubnets mocks the corresponding IPAMClient function.
+func (mock *IPAMClient) ListReservedSubnets(context.Context, *ipam.ListReservedSubnetsRequest,
+	...grpc.CallOption) (*ipam.ListReservedSubnetsResponse, error) {
+	return &ipam.ListReservedSubnetsResponse{}, nil
+}
+
+// ListClusterSubnets mocks the corresponding IPAMClient function.
+func (mock *IPAMClient) ListClusterSubnets(context.Context, *ipam.ListClusterSubnetsRequest,
+	...grpc.CallOption) (*ipam.ListClusterSubnetsResponse, error) {
+	return &ipam.ListClusterSubnetsResponse{}, nil
+}
+
+// ListEndpointMappings mocks the corresponding IPAMClient function.
+func (mock *IPAMClient) ListEndpointMappings(context.Context, *ipam.ListEndpointMappingsRequest,
+	...grpc.CallOption) (*ipam.ListEndpointMappingsResponse, error) {
+	return &ipam.ListEndpointMappingsResponse{}, nil
+}
+
+// ListNatMappingsConfigured mocks the corresponding IPAMClient function.
+func (mock *IPAMClient) ListNatMappingsConfigured(context.Context, *ipam.ListNatMappingsConfiguredRequest,
+	...grpc.CallOption) (*ipam.ListNatMappingsConfiguredResponse, error) {
+	return &ipam.ListNatMappingsConfiguredResponse{}, nil
+}
+
+// GetIPOwner mocks the corresponding IPAMClient function.
+func (mock *IPAMClient) GetIPOwner(context.Context, *ipam.GetIPOwnerRequest,
+	...grpc.CallOption) (*ipam.GetIPOwnerResponse, error) {
+	return &ipam.GetIPOwnerResponse{}, nil
+}
@@ -49,8 +49,15 @@ type Ipam interface {
 	- PodCIDR
 	- ExternalCIDR
 	- Both.
+	Both networks must belong to the same IP family: in case of dual-stack clusters, the function
+	has to be invoked once per family, and remapped networks are taken from pools of the same family.
 	*/
 	GetSubnetsPerCluster(podCidr, externalCIDR, clusterID string) (string, string, error)
+	/* GetTransitSubnetsPerCluster receives the PodCIDRs (keyed by cluster ID) of the clusters reachable in
+	transit through a remote cluster, as announced by the latter, and returns the networks the local cluster
+	uses to reach each of them. Networks are reserved (or remapped, in case of conflicts) as performed by
+	GetSubnetsPerCluster, while those of the clusters no longer announced are freed. */
+	GetTransitSubnetsPerCluster(transitNetworks map[string]string, clusterID string) (map[string]string, error)
 	// RemoveClusterConfig deletes the IPAM configuration of a remote cluster,
 	// by freeing networks and removing data structures related to that cluster.
 	RemoveClusterConfig(clusterID string) error
@@ -67,10 +74,15 @@ type Ipam interface {
 	this function must not reserve it. If the remote cluster has not remapped
 	a local subnet, then CIDR value should be equal to "None". */
 	AddLocalSubnetsPerCluster(podCIDR, externalCIDR, clusterID string) error
+	/* AddLocalIPv6SubnetsPerCluster is the IPv6 counterpart of AddLocalSubnetsPerCluster, used in case
+	of dual-stack clusters. It must be invoked after AddLocalSubnetsPerCluster. */
+	AddLocalIPv6SubnetsPerCluster(podCIDR, externalCIDR, clusterID string) error
 	GetExternalCIDR(mask uint8) (string, error)
-	// SetPodCIDR sets the cluster PodCIDR.
+	// GetExternalCIDRv6 chooses and returns the local cluster's IPv6 ExternalCIDR, in case of dual-stack clusters.
+	GetExternalCIDRv6(mask uint8) (string, error)
+	// SetPodCIDR sets the cluster PodCIDR of the family the given network belongs to.
 	SetPodCIDR(podCIDR string) error
-	// SetServiceCIDR sets the cluster ServiceCIDR.
+	// SetServiceCIDR sets the cluster ServiceCIDR of the family the given network belongs to.
 	SetServiceCIDR(serviceCIDR string) error
 	// Terminate function enforces a graceful termination of the IPAM module.
 	Terminate()
@@ -101,6 +113,11 @@ var Pools = []string{
 	"172.16.0.0/12",
 }
 
+// PoolsV6 is a constant slice containing private IPv6 networks, used in case of dual-stack clusters.
+var PoolsV6 = []string{
+	"fd00::/8",
+}
+
 const emptyCIDR = ""
 
 // Init uses the Ipam resource to retrieve and allocate reserved networks.
@@ -116,15 +133,30 @@ func (liqoIPAM *IPAM) Init(pools []string, dynClient dynamic.Interface, listenin
 	// Get resource
 	ipamPools := liqoIPAM.ipamStorage.getPools()
 
-	// Have network pools been already set? If not, take them from caller
-	if len(ipamPools) == 0 {
-		for _, network := range pools {
-			if _, err := liqoIPAM.ipam.NewPrefix(context.TODO(), network); err != nil {
-				return fmt.Errorf("failed to create a new prefix for network %s: %w", network, err)
-			}
-			ipamPools = append(ipamPools, network)
-			klog.Infof("Pool %s has been successfully added to the pool list", network)
+	// Have network pools been already set? If not, take them from caller.
+	// The check is performed per family, so that IPv6 pools are added also
+	// to IPAM configurations created before enabling the dual-stack support.
+	var hasPoolsV4, hasPoolsV6 bool
+	for _, pool := range ipamPools {
+		if liqonetutils.IsIPv6CIDR(pool) {
+			hasPoolsV6 = true
+		} else {
+			hasPoolsV4 = true
 		}
+	}
+	var poolsAdded bool
+	for _, network := range pools {
+		if ipv6 := liqonetutils.IsIPv6CIDR(network); (ipv6 && hasPoolsV6) || (!ipv6 && hasPoolsV4) {
+			continue
+		}
+		if _, err := liqoIPAM.ipam.NewPrefix(context.TODO(), network); err != nil {
+			return fmt.Errorf("failed to create a new prefix for network %s: %w", network, err)
+		}
+		ipamPools = append(ipamPools, network)
+		poolsAdded = true
+		klog.Infof("Pool %s has been successfully added to the pool list", network)
+	}
+	if poolsAdded {
 		err = liqoIPAM.ipamStorage.updatePools(ipamPools)
 		if err != nil {
 			return fmt.Errorf("cannot set pools: %w", err)
@@ -266,23 +298,20 @@ func (liqoIPAM *IPAM) overlapsWithNetwork(newNetwork, network string) (overlaps
 }
 
 func (liqoIPAM *IPAM) overlapsWithCluster(network string) (overlappingCluster string, overlaps bool, err error) {
-	var overlapsWithPodCIDR bool
-	var overlapsWithExternalCIDR bool
 	// Get cluster subnets
 	clusterSubnets := liqoIPAM.ipamStorage.getClusterSubnets()
 	for cluster, subnets := range clusterSubnets {
-		overlapsWithPodCIDR, err = liqoIPAM.overlapsWithNetwork(network, subnets.RemotePodCIDR)
-		if err != nil {
-			return
-		}
-		overlapsWithExternalCIDR, err = liqoIPAM.overlapsWithNetwork(network, subnets.RemoteExternalCIDR)
-		if err != nil {
-			return
-		}
-		if overlapsWithPodCIDR || overlapsWithExternalCIDR {
-			overlaps = true
-			overlappingCluster = cluster
-			return
+		remoteNetworks := []string{subnets.RemotePodCIDR, subnets.RemoteExternalCIDR,
+			subnets.RemotePodCIDRv6, subnets.RemoteExternalCIDRv6}
+		for _, remoteNetwork := range remoteNetworks {
+			overlaps, err = liqoIPAM.overlapsWithNetwork(network, remoteNetwork)
+			if err != nil {
+				return
+			}
+			if overlaps {
+				overlappingCluster = cluster
+				return
+			}
 		}
 	}
 	return overlappingCluster, overlaps, err
@@ -363,7 +392,7 @@ func (liqoIPAM *IPAM) getPoolFromNetwork(network string) (networkPool string, su
 
 func (liqoIPAM *IPAM) clusterSubnetEqualToPool(pool string) (string, error) {
 	klog.Infof("Network %s is equal to a pool, looking for a mapping..", pool)
-	mappedNetwork, err := liqoIPAM.getNetworkFromPool(liqonetutils.GetMask(pool))
+	mappedNetwork, err := liqoIPAM.getNetworkFromPool(liqonetutils.GetMask(pool), liqonetutils.IsIPv6CIDR(pool))
 	if err != nil {
 		klog.Infof("Mapping not found, acquiring the entire network pool..")
 		err = liqoIPAM.reservePoolInHalves(pool)
@@ -419,7 +448,7 @@ func (liqoIPAM *IPAM) getOrRemapNetwork(network string) (string, error) {
 		}
 	}
 	/* Network is already reserved, need a mapping */
-	mappedNetwork, err = liqoIPAM.getNetworkFromPool(liqonetutils.GetMask(network))
+	mappedNetwork, err = liqoIPAM.getNetworkFromPool(liqonetutils.GetMask(network), liqonetutils.IsIPv6CIDR(network))
 	if err != nil {
 		return "", err
 	}
@@ -430,7 +459,7 @@ func (liqoIPAM *IPAM) getOrRemapNetwork(network string) (string, error) {
 /*
 GetSubnetsPerCluster receives a PodCIDR, and a Cluster ID and returns a PodCIDR and an ExternalCIDR.
 The PodCIDR can be either the received one or a new one, if conflicts have been found.
-The same happens for ExternalCIDR.
+The same happens for ExternalCIDR. The IP family is inferred from the received PodCIDR.
 */
 func (liqoIPAM *IPAM) GetSubnetsPerCluster(
 	podCidr,
@@ -441,10 +470,17 @@ func (liqoIPAM *IPAM) GetSubnetsPerCluster(
 	// Get subnets of clusters
 	clusterSubnets := liqoIPAM.ipamStorage.getClusterSubnets()
 
-	// Check existence
+	// Select the fields of the IP family of the received networks
 	subnets, exists := clusterSubnets[clusterID]
-	if exists && subnets.RemotePodCIDR != "" && subnets.RemoteExternalCIDR != "" {
-		return subnets.RemotePodCIDR, subnets.RemoteExternalCIDR, nil
+	ipv6 := liqonetutils.IsIPv6CIDR(podCidr)
+	remotePodCIDR, remoteExternalCIDR := &subnets.RemotePodCIDR, &subnets.RemoteExternalCIDR
+	if ipv6 {
+		remotePodCIDR, remoteExternalCIDR = &subnets.RemotePodCIDRv6, &subnets.RemoteExternalCIDRv6
+	}
+
+	// Check existence
+	if exists && *remotePodCIDR != "" && *remoteExternalCIDR != "" {
+		return *remotePodCIDR, *remoteExternalCIDR, nil
 	}
 
 	// Check if podCidr is a valid CIDR
@@ -453,6 +489,11 @@ func (liqoIPAM *IPAM) GetSubnetsPerCluster(
 		return "", "", fmt.Errorf("PodCidr is an invalid CIDR: %w", err)
 	}
 
+	// Check that the networks belong to the same IP family
+	if liqonetutils.IsValidCIDR(externalCIDR) == nil && liqonetutils.IsIPv6CIDR(externalCIDR) != ipv6 {
+		return "", "", fmt.Errorf("PodCidr %s and ExternalCIDR %s belong to different IP families", podCidr, externalCIDR)
+	}
+
 	klog.Infof("Cluster networks allocation request received: %s", clusterID)
 
 	// Get PodCidr
@@ -478,19 +519,9 @@ func (liqoIPAM *IPAM) GetSubnetsPerCluster(
 
 	klog.Infof("ExternalCIDR %s has been assigned to cluster %s", mappedExternalCIDR, clusterID)
 
-	if !exists {
-		// Create cluster network configuration
-		subnets = netv1alpha1.Subnets{
-			LocalNATPodCIDR:      "",
-			RemotePodCIDR:        mappedPodCIDR,
-			RemoteExternalCIDR:   mappedExternalCIDR,
-			LocalNATExternalCIDR: "",
-		}
-	} else {
-		// Update cluster network configuration
-		subnets.RemotePodCIDR = mappedPodCIDR
-		subnets.RemoteExternalCIDR = mappedExternalCIDR
-	}
+	// Create or update cluster network configuration
+	*remotePodCIDR = mappedPodCIDR
+	*remoteExternalCIDR = mappedExternalCIDR
 	clusterSubnets[clusterID] = subnets
 
 	// Push it in clusterSubnets
@@ -502,12 +533,84 @@ func (liqoIPAM *IPAM) GetSubnetsPerCluster(
 	return mappedPodCIDR, mappedExternalCIDR, nil
 }
 
-// getNetworkFromPool returns a network with mask length equal to mask taken by a network pool.
-func (liqoIPAM *IPAM) getNetworkFromPool(mask uint8) (string, error) {
+// GetTransitSubnetsPerCluster receives the PodCIDRs of the clusters reachable in transit through the given
+// remote cluster, and returns the networks (either the received ones or new ones, in case of conflicts)
+// used to reach each of them. Only IPv4 networks are supported.
+func (liqoIPAM *IPAM) GetTransitSubnetsPerCluster(transitNetworks map[string]string, clusterID string) (map[string]string, error) {
+	// Get subnets of clusters
+	clusterSubnets := liqoIPAM.ipamStorage.getClusterSubnets()
+	subnets := clusterSubnets[clusterID]
+
+	mappedNetworks := make(map[string]string, len(transitNetworks))
+	transitSubnets := make(map[string]netv1alpha1.TransitSubnet, len(transitNetworks))
+	changed := false
+
+	// Free the networks of the clusters which are no longer announced, or whose PodCIDR changed
+	for transitClusterID, transitSubnet := range subnets.TransitSubnets {
+		if podCIDR, found := transitNetworks[transitClusterID]; found && podCIDR == transitSubnet.PodCIDR {
+			transitSubnets[transitClusterID] = transitSubnet
+			mappedNetworks[transitClusterID] = transitSubnet.MappedPodCIDR
+			continue
+		}
+
+		if err := liqoIPAM.FreeReservedSubnet(transitSubnet.MappedPodCIDR); err != nil {
+			return nil, fmt.Errorf("cannot free the network of cluster %s in transit through cluster %s: %w",
+				transitClusterID, clusterID, err)
+		}
+		klog.Infof("Network %s assigned to cluster %s in transit through cluster %s has just been freed",
+			transitSubnet.MappedPodCIDR, transitClusterID, clusterID)
+		changed = true
+	}
+
+	// Allocate the networks of the newly announced clusters
+	for transitClusterID, podCIDR := range transitNetworks {
+		if _, found := transitSubnets[transitClusterID]; found {
+			continue
+		}
+
+		if err := liqonetutils.IsValidCIDR(podCIDR); err != nil || liqonetutils.IsIPv6CIDR(podCIDR) {
+			return nil, fmt.Errorf("PodCIDR %s of cluster %s in transit through cluster %s is not a valid IPv4 CIDR",
+				podCIDR, transitClusterID, clusterID)
+		}
+
+		mappedPodCIDR, err := liqoIPAM.getOrRemapNetwork(podCIDR)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get a PodCIDR for cluster %s in transit through cluster %s: %w",
+				transitClusterID, clusterID, err)
+		}
+		klog.Infof("PodCIDR %s has been assigned to cluster %s in transit through cluster %s", mappedPodCIDR, transitClusterID, clusterID)
+
+		transitSubnets[transitClusterID] = netv1alpha1.TransitSubnet{PodCIDR: podCIDR, MappedPodCIDR: mappedPodCIDR}
+		mappedNetworks[transitClusterID] = mappedPodCIDR
+		changed = true
+	}
+
+	if !changed {
+		return mappedNetworks, nil
+	}
+
+	subnets.TransitSubnets = transitSubnets
+	if len(transitSubnets) == 0 {
+		subnets.TransitSubnets = nil
+	}
+	clusterSubnets[clusterID] = subnets
+
+	// Push it in clusterSubnets
+	if err := liqoIPAM.eventuallyDeleteClusterSubnet(clusterID, clusterSubnets); err != nil {
+		return nil, fmt.Errorf("cannot update cluster subnets: %w", err)
+	}
+	return mappedNetworks, nil
+}
+
+// getNetworkFromPool returns a network with mask length equal to mask taken by a network pool of the given IP family.
+func (liqoIPAM *IPAM) getNetworkFromPool(mask uint8, ipv6 bool) (string, error) {
 	// Get network pools
 	pools := liqoIPAM.ipamStorage.getPools()
-	// For each pool, try to get a network with mask length mask
+	// For each pool of the requested family, try to get a network with mask length mask
 	for _, pool := range pools {
+		if liqonetutils.IsIPv6CIDR(pool) != ipv6 {
+			continue
+		}
 		if mappedNetwork, err := liqoIPAM.ipam.AcquireChildPrefix(context.TODO(), pool, mask); err == nil {
 			klog.Infof("Acquired network %s", mappedNetwork)
 			return mappedNetwork.String(), nil
@@ -592,7 +695,12 @@ func (liqoIPAM *IPAM) eventuallyDeleteClusterSubnet(clusterID string,
 	if subnets.RemotePodCIDR == "" &&
 		subnets.LocalNATPodCIDR == "" &&
 		subnets.RemoteExternalCIDR == "" &&
-		subnets.LocalNATExternalCIDR == "" {
+		subnets.LocalNATExternalCIDR == "" &&
+		subnets.RemotePodCIDRv6 == "" &&
+		subnets.LocalNATPodCIDRv6 == "" &&
+		subnets.RemoteExternalCIDRv6 == "" &&
+		subnets.LocalNATExternalCIDRv6 == "" &&
+		len(subnets.TransitSubnets) == 0 {
 		// Delete entry
 		delete(clusterSubnets, clusterID)
 	}
@@ -641,6 +749,21 @@ func (liqoIPAM *IPAM) RemoveClusterConfig(clusterID string) error {
 		if err := liqoIPAM.FreeReservedSubnet(subnets.RemoteExternalCIDR); err != nil {
 			return err
 		}
+
+		// Free IPv6 networks, in case of dual-stack clusters
+		if err := liqoIPAM.FreeReservedSubnet(subnets.RemotePodCIDRv6); err != nil {
+			return err
+		}
+		if err := liqoIPAM.FreeReservedSubnet(subnets.RemoteExternalCIDRv6); err != nil {
+			return err
+		}
+
+		// Free the networks of the clusters in transit through the remote one
+		for _, transitSubnet := range subnets.TransitSubnets {
+			if err := liqoIPAM.FreeReservedSubnet(transitSubnet.MappedPodCIDR); err != nil {
+				return err
+			}
+		}
 		klog.Infof("Networks assigned to cluster %s have just been freed", clusterID)
 
 		delete(clusterSubnets, clusterID)
@@ -746,8 +869,12 @@ func (liqoIPAM *IPAM) terminateNatMappingsPerCluster(clusterID string) error {
 		delete(m.ClusterMappings, clusterID)
 
 		if len(m.ClusterMappings) == 0 {
-			// Free IP
-			err = liqoIPAM.ipam.ReleaseIPFromPrefix(context.TODO(), localExternalCIDR, m.ExternalCIDROriginalIP)
+			// Free IP, from the ExternalCIDR of the same family
+			externalCIDR := localExternalCIDR
+			if liqonetutils.IsIPv6(m.ExternalCIDROriginalIP) {
+				externalCIDR = liqoIPAM.ipamStorage.getExternalCIDRv6()
+			}
+			err = liqoIPAM.ipam.ReleaseIPFromPrefix(context.TODO(), externalCIDR, m.ExternalCIDROriginalIP)
 			if err != nil && !errors.Is(err, goipam.ErrNotFound) {
 				/*
 					ReleaseIPFromPrefix can return ErrNotFound either if the prefix
@@ -828,7 +955,7 @@ func (liqoIPAM *IPAM) RemoveNetworkPool(network string) error {
 		return fmt.Errorf("network %s is not a network pool", network)
 	}
 	// Cannot remove a default one
-	if contains := slice.ContainsString(Pools, network); contains {
+	if contains := slice.ContainsString(Pools, network) || slice.ContainsString(PoolsV6, network); contains {
 		return fmt.Errorf("cannot remove a default network pool")
 	}
 	// Check overlapping with cluster networks
@@ -931,6 +1058,63 @@ func (liqoIPAM *IPAM) AddLocalSubnetsPerCluster(podCIDR, externalCIDR, clusterID
 	return nil
 }
 
+// AddLocalIPv6SubnetsPerCluster stores how the IPv6 PodCIDR and the IPv6 ExternalCIDR of local cluster
+// has been remapped in a remote cluster, in case of dual-stack clusters. If no remapping happened,
+// then the CIDR value should be equal to "None". NAT mappings for the cluster must have already been
+// initialized by AddLocalSubnetsPerCluster.
+func (liqoIPAM *IPAM) AddLocalIPv6SubnetsPerCluster(podCIDR, externalCIDR, clusterID string) error {
+	if clusterID == "" {
+		return &liqoneterrors.WrongParameter{
+			Parameter: consts.ClusterIDLabelName,
+			Reason:    liqoneterrors.StringNotEmpty,
+		}
+	}
+
+	// Get cluster subnets
+	clusterSubnets := liqoIPAM.ipamStorage.getClusterSubnets()
+
+	// Get NatMappingsConfigured map
+	natMappingsConfigured := liqoIPAM.ipamStorage.getNatMappingsConfigured()
+
+	subnets, subnetsExist := clusterSubnets[clusterID]
+	if !subnetsExist || subnets.RemotePodCIDRv6 == "" {
+		return fmt.Errorf("remote IPv6 subnets for cluster %s do not exist yet. Call first GetSubnetsPerCluster",
+			clusterID)
+	}
+	if _, natMappingsPerClusterConfigured := natMappingsConfigured[clusterID]; !natMappingsPerClusterConfigured {
+		return fmt.Errorf("NAT mappings for cluster %s have not been initialized yet. Call first AddLocalSubnetsPerCluster",
+			clusterID)
+	}
+	if subnets.LocalNATPodCIDRv6 == podCIDR && subnets.LocalNATExternalCIDRv6 == externalCIDR {
+		return nil
+	}
+
+	// Init IPv6 NAT mappings: the inflater needs the IPv6 Pod CIDR used in home cluster for remote pods
+	// and the IPv6 ExternalCIDR used in remote cluster for local exported resources.
+	natExternalCIDR := externalCIDR
+	if externalCIDR == consts.DefaultCIDRValue {
+		if natExternalCIDR = liqoIPAM.ipamStorage.getExternalCIDRv6(); natExternalCIDR == emptyCIDR {
+			return fmt.Errorf("the local IPv6 ExternalCIDR is not set")
+		}
+	}
+	if err := liqoIPAM.natMappingInflater.InitIPv6NatMappingsPerCluster(subnets.RemotePodCIDRv6, natExternalCIDR, clusterID); err != nil {
+		return fmt.Errorf("unable to initialize IPv6 NAT mappings per cluster %s: %w", clusterID, err)
+	}
+
+	// Set networks
+	subnets.LocalNATPodCIDRv6 = podCIDR
+	subnets.LocalNATExternalCIDRv6 = externalCIDR
+	clusterSubnets[clusterID] = subnets
+	klog.Infof("Local NAT IPv6 PodCIDR of cluster %s set to %s", clusterID, podCIDR)
+	klog.Infof("Local NAT IPv6 ExternalCIDR of cluster %s set to %s", clusterID, externalCIDR)
+
+	// Push it in clusterSubnets
+	if err := liqoIPAM.ipamStorage.updateClusterSubnets(clusterSubnets); err != nil {
+		return fmt.Errorf("cannot update cluster subnets: %w", err)
+	}
+	return nil
+}
+
 // RemoveLocalSubnetsPerCluster deletes networks related to a cluster.
 func (liqoIPAM *IPAM) RemoveLocalSubnetsPerCluster(clusterID string) error {
 	var exists bool
@@ -940,13 +1124,16 @@ func (liqoIPAM *IPAM) RemoveLocalSubnetsPerCluster(clusterID string) error {
 	clusterSubnets := liqoIPAM.ipamStorage.getClusterSubnets()
 	// Check existence
 	subnets, exists = clusterSubnets[clusterID]
-	if !exists || (subnets.LocalNATPodCIDR == "" && subnets.LocalNATExternalCIDR == "") {
+	if !exists || (subnets.LocalNATPodCIDR == "" && subnets.LocalNATExternalCIDR == "" &&
+		subnets.LocalNATPodCIDRv6 == "" && subnets.LocalNATExternalCIDRv6 == "") {
 		return nil
 	}
 
 	// Unset networks
 	subnets.LocalNATPodCIDR = ""
 	subnets.LocalNATExternalCIDR = ""
+	subnets.LocalNATPodCIDRv6 = ""
+	subnets.LocalNATExternalCIDRv6 = ""
 	clusterSubnets[clusterID] = subnets
 
 	klog.Infof("Local NAT networks of cluster %s deleted", clusterID)
@@ -958,18 +1145,33 @@ func (liqoIPAM *IPAM) RemoveLocalSubnetsPerCluster(clusterID string) error {
 
 // GetExternalCIDR chooses and returns the local cluster's ExternalCIDR.
 func (liqoIPAM *IPAM) GetExternalCIDR(mask uint8) (string, error) {
+	return liqoIPAM.getExternalCIDR(mask, false)
+}
+
+// GetExternalCIDRv6 chooses and returns the local cluster's IPv6 ExternalCIDR, in case of dual-stack clusters.
+func (liqoIPAM *IPAM) GetExternalCIDRv6(mask uint8) (string, error) {
+	return liqoIPAM.getExternalCIDR(mask, true)
+}
+
+// getExternalCIDR chooses and returns the local cluster's ExternalCIDR of the given IP family.
+func (liqoIPAM *IPAM) getExternalCIDR(mask uint8, ipv6 bool) (string, error) {
 	var externalCIDR string
 	var err error
 
+	getExternalCIDR, updateExternalCIDR := liqoIPAM.ipamStorage.getExternalCIDR, liqoIPAM.ipamStorage.updateExternalCIDR
+	if ipv6 {
+		getExternalCIDR, updateExternalCIDR = liqoIPAM.ipamStorage.getExternalCIDRv6, liqoIPAM.ipamStorage.updateExternalCIDRv6
+	}
+
 	// Get cluster ExternalCIDR
-	externalCIDR = liqoIPAM.ipamStorage.getExternalCIDR()
+	externalCIDR = getExternalCIDR()
 	if externalCIDR != "" {
 		return externalCIDR, nil
 	}
-	if externalCIDR, err = liqoIPAM.getNetworkFromPool(mask); err != nil {
+	if externalCIDR, err = liqoIPAM.getNetworkFromPool(mask, ipv6); err != nil {
 		return "", fmt.Errorf("cannot allocate an ExternalCIDR: %w", err)
 	}
-	if err := liqoIPAM.ipamStorage.updateExternalCIDR(externalCIDR); err != nil {
+	if err := updateExternalCIDR(externalCIDR); err != nil {
 		_ = liqoIPAM.FreeReservedSubnet(externalCIDR)
 		return "", fmt.Errorf("cannot update ExternalCIDR: %w", err)
 	}
@@ -995,7 +1197,7 @@ func (liqoIPAM *IPAM) belongsToPodCIDRInternal(ip string) (bool, error) {
 		}
 	}
 
-	podCIDR := liqoIPAM.ipamStorage.getPodCIDR()
+	podCIDR := liqoIPAM.getLocalPodCIDR(liqonetutils.IsIPv6(ip))
 	if podCIDR == "" {
 		return false, fmt.Errorf("the pod CIDR is not set")
 	}
@@ -1024,8 +1226,11 @@ func (liqoIPAM *IPAM) mapIPToExternalCIDR(clusterID, remoteExternalCIDR, ip stri
 	// Get endpointMappings
 	endpointMappings := liqoIPAM.ipamStorage.getEndpointMappings()
 
-	// Get local ExternalCIDR
-	localExternalCIDR := liqoIPAM.ipamStorage.getExternalCIDR()
+	// Get local ExternalCIDR, of the same family of the IP
+	localExternalCIDR := liqoIPAM.getLocalExternalCIDR(liqonetutils.IsIPv6(ip))
+	if localExternalCIDR == emptyCIDR {
+		return "", fmt.Errorf("cannot get the ExternalCIDR to map endpoint %s", ip)
+	}
 
 	if remoteExternalCIDR == consts.DefaultCIDRValue {
 		externalCIDR = localExternalCIDR
@@ -1098,8 +1303,18 @@ func (liqoIPAM *IPAM) mapEndpointIPInternal(clusterID, ip string) (string, error
 		return "", fmt.Errorf("cluster %s has not a network configuration", clusterID)
 	}
 
+	// Select the networks of the same family of the IP
+	ipv6 := liqonetutils.IsIPv6(ip)
+	localNATPodCIDR, localNATExternalCIDR := subnets.LocalNATPodCIDR, subnets.LocalNATExternalCIDR
+	if ipv6 {
+		localNATPodCIDR, localNATExternalCIDR = subnets.LocalNATPodCIDRv6, subnets.LocalNATExternalCIDRv6
+		if localNATPodCIDR == emptyCIDR || localNATExternalCIDR == emptyCIDR {
+			return "", fmt.Errorf("cluster %s has not an IPv6 network configuration", clusterID)
+		}
+	}
+
 	// Get PodCIDR
-	podCIDR := liqoIPAM.ipamStorage.getPodCIDR()
+	podCIDR := liqoIPAM.getLocalPodCIDR(ipv6)
 	if podCIDR == emptyCIDR {
 		return "", fmt.Errorf("cannot get cluster PodCIDR: %w", err)
 	}
@@ -1110,12 +1325,12 @@ func (liqoIPAM *IPAM) mapEndpointIPInternal(clusterID, ip string) (string, error
 	}
 	if belongs {
 		klog.V(5).Infof("MapEndpointIP(%s, %s): ip is in pod CIDR %s, mapping to LocalNATPodCIDR %s",
-			ip, clusterID, podCIDR, subnets.LocalNATPodCIDR)
+			ip, clusterID, podCIDR, localNATPodCIDR)
 
 		/* IP belongs to local PodCIDR, this means the Pod is a local Pod and
 		the new IP should belong to the network used in the remote cluster
 		for local Pods: this can be either the cluster PodCIDR or a different network */
-		newIP, err := liqonetutils.MapIPToNetwork(subnets.LocalNATPodCIDR, ip)
+		newIP, err := liqonetutils.MapIPToNetwork(localNATPodCIDR, ip)
 		if err != nil {
 			return "", fmt.Errorf("cannot map endpoint IP %s to PodCIDR of remote cluster %s: %w", ip, clusterID, err)
 		}
@@ -1123,10 +1338,10 @@ func (liqoIPAM *IPAM) mapEndpointIPInternal(clusterID, ip string) (string, error
 	}
 	// IP does not belong to cluster PodCIDR: Pod is a reflected Pod
 	klog.V(5).Infof("MapEndpointIP(%s, %s): ip is not in pod CIDR %s, mapping to LocalNATExternalCIDR %s",
-		ip, clusterID, podCIDR, subnets.LocalNATExternalCIDR)
+		ip, clusterID, podCIDR, localNATExternalCIDR)
 
 	// Map IP to ExternalCIDR
-	newIP, err := liqoIPAM.mapIPToExternalCIDR(clusterID, subnets.LocalNATExternalCIDR, ip)
+	newIP, err := liqoIPAM.mapIPToExternalCIDR(clusterID, localNATExternalCIDR, ip)
 	if err != nil {
 		return "", fmt.Errorf("cannot map endpoint IP %s to ExternalCIDR of cluster %s: %w", ip, clusterID, err)
 	}
@@ -1203,15 +1418,19 @@ func (liqoIPAM *IPAM) getHomePodIPInternal(clusterID, ip string) (string, error)
 		return "", fmt.Errorf("cluster %s subnets are not set", clusterID)
 	}
 
-	if subnets.RemotePodCIDR == "" {
+	remotePodCIDR := subnets.RemotePodCIDR
+	if liqonetutils.IsIPv6(ip) {
+		remotePodCIDR = subnets.RemotePodCIDRv6
+	}
+	if remotePodCIDR == "" {
 		return "", &liqoneterrors.WrongParameter{
 			Reason: liqoneterrors.StringNotEmpty,
 		}
 	}
 
 	klog.V(5).Infof("GetHomePodIP(%s, %s): mapping to RemotePodCIDR %s",
-		ip, clusterID, subnets.RemotePodCIDR)
-	return liqonetutils.MapIPToNetwork(subnets.RemotePodCIDR, ip)
+		ip, clusterID, remotePodCIDR)
+	return liqonetutils.MapIPToNetwork(remotePodCIDR, ip)
 }
 
 // unmapEndpointIPInternal is the internal implementation of UnmapEndpointIP.
@@ -1248,8 +1467,12 @@ func (liqoIPAM *IPAM) unmapEndpointIPInternal(clusterID, endpointIP string) erro
 	delete(endpointMapping.ClusterMappings, clusterID)
 
 	if len(endpointMapping.ClusterMappings) == 0 {
-		// Free IP
-		err = liqoIPAM.ipam.ReleaseIPFromPrefix(context.TODO(), localExternalCIDR, endpointMapping.ExternalCIDROriginalIP)
+		// Free IP, from the ExternalCIDR of the same family
+		externalCIDR := localExternalCIDR
+		if liqonetutils.IsIPv6(endpointMapping.ExternalCIDROriginalIP) {
+			externalCIDR = liqoIPAM.ipamStorage.getExternalCIDRv6()
+		}
+		err = liqoIPAM.ipam.ReleaseIPFromPrefix(context.TODO(), externalCIDR, endpointMapping.ExternalCIDROriginalIP)
 		if err != nil && !errors.Is(err, goipam.ErrNotFound) {
 			/*
 				ReleaseIPFromPrefix can return ErrNotFound either if the prefix
@@ -1290,10 +1513,15 @@ func (liqoIPAM *IPAM) UnmapEndpointIP(ctx context.Context, unmapRequest *UnmapRe
 	return &UnmapResponse{}, nil
 }
 
-// SetPodCIDR sets the PodCIDR.
+// SetPodCIDR sets the PodCIDR, or the IPv6 PodCIDR in case an IPv6 network is received.
 func (liqoIPAM *IPAM) SetPodCIDR(podCIDR string) error {
+	getPodCIDR, updatePodCIDR := liqoIPAM.ipamStorage.getPodCIDR, liqoIPAM.ipamStorage.updatePodCIDR
+	if liqonetutils.IsIPv6CIDR(podCIDR) {
+		getPodCIDR, updatePodCIDR = liqoIPAM.ipamStorage.getPodCIDRv6, liqoIPAM.ipamStorage.updatePodCIDRv6
+	}
+
 	// Get PodCIDR
-	oldPodCIDR := liqoIPAM.ipamStorage.getPodCIDR()
+	oldPodCIDR := getPodCIDR()
 	if oldPodCIDR != "" && oldPodCIDR != podCIDR {
 		return fmt.Errorf("trying to change PodCIDR")
 	}
@@ -1305,16 +1533,21 @@ func (liqoIPAM *IPAM) SetPodCIDR(podCIDR string) error {
 		return fmt.Errorf("cannot acquire PodCIDR: %w", err)
 	}
 	// Update PodCIDR
-	if err := liqoIPAM.ipamStorage.updatePodCIDR(podCIDR); err != nil {
+	if err := updatePodCIDR(podCIDR); err != nil {
 		return fmt.Errorf("cannot set PodCIDR: %w", err)
 	}
 	return nil
 }
 
-// SetServiceCIDR sets the ServiceCIDR.
+// SetServiceCIDR sets the ServiceCIDR, or the IPv6 ServiceCIDR in case an IPv6 network is received.
 func (liqoIPAM *IPAM) SetServiceCIDR(serviceCIDR string) error {
+	getServiceCIDR, updateServiceCIDR := liqoIPAM.ipamStorage.getServiceCIDR, liqoIPAM.ipamStorage.updateServiceCIDR
+	if liqonetutils.IsIPv6CIDR(serviceCIDR) {
+		getServiceCIDR, updateServiceCIDR = liqoIPAM.ipamStorage.getServiceCIDRv6, liqoIPAM.ipamStorage.updateServiceCIDRv6
+	}
+
 	// Get ServiceCIDR
-	oldServiceCIDR := liqoIPAM.ipamStorage.getServiceCIDR()
+	oldServiceCIDR := getServiceCIDR()
 	if oldServiceCIDR != "" && oldServiceCIDR != serviceCIDR {
 		return fmt.Errorf("trying to change ServiceCIDR")
 	}
@@ -1326,7 +1559,7 @@ func (liqoIPAM *IPAM) SetServiceCIDR(serviceCIDR string) error {
 		return fmt.Errorf("cannot acquire ServiceCIDR: %w", err)
 	}
 	// Update Service CIDR
-	if err := liqoIPAM.ipamStorage.updateServiceCIDR(serviceCIDR); err != nil {
+	if err := updateServiceCIDR(serviceCIDR); err != nil {
 		return fmt.Errorf("cannot set ServiceCIDR: %w", err)
 	}
 	return nil
@@ -1419,6 +1652,26 @@ func (liqoIPAM *IPAM) reservedSubnetOverlaps(subnet string) error {
 			subnet, externalCidr)
 	}
 
+	// Check if subnet overlaps with the local IPv6 networks, in case of dual-stack clusters.
+	localsV6 := []struct {
+		name    string
+		network string
+	}{
+		{"podCIDR", liqoIPAM.ipamStorage.getPodCIDRv6()},
+		{"serviceCIDR", liqoIPAM.ipamStorage.getServiceCIDRv6()},
+		{"external CIDR", liqoIPAM.ipamStorage.getExternalCIDRv6()},
+	}
+	for _, local := range localsV6 {
+		overlaps, err = liqoIPAM.overlapsWithNetwork(subnet, local.network)
+		if err != nil {
+			return err
+		}
+		if overlaps {
+			return fmt.Errorf("network %s cannot be reserved because it overlaps with the local IPv6 %s %s",
+				subnet, local.name, local.network)
+		}
+	}
+
 	// Check if the subnet does not overlap with the existing reserved subnets.
 	overlappingNet, overlaps, err := liqoIPAM.overlapsWithReserved(subnet)
 	if err != nil {
@@ -1442,6 +1695,22 @@ func (liqoIPAM *IPAM) reservedSubnetOverlaps(subnet string) error {
 	return nil
 }
 
+// getLocalPodCIDR returns the local PodCIDR of the given IP family.
+func (liqoIPAM *IPAM) getLocalPodCIDR(ipv6 bool) string {
+	if ipv6 {
+		return liqoIPAM.ipamStorage.getPodCIDRv6()
+	}
+	return liqoIPAM.ipamStorage.getPodCIDR()
+}
+
+// getLocalExternalCIDR returns the local ExternalCIDR of the given IP family.
+func (liqoIPAM *IPAM) getLocalExternalCIDR(ipv6 bool) string {
+	if ipv6 {
+		return liqoIPAM.ipamStorage.getExternalCIDRv6()
+	}
+	return liqoIPAM.ipamStorage.getExternalCIDR()
+}
+
 // AcquireSpecificIP acquires the first IP in the given subnet and return it.
 // This function returns nil if the IP is already acquired.
 func (liqoIPAM *IPAM) AcquireSpecificIP(ip, subnet string) error {
@@ -21,6 +21,85 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type IPOwner_Kind int32
+
+const (
+	IPOwner_UNKNOWN                 IPOwner_Kind = 0
+	IPOwner_NETWORK_POOL            IPOwner_Kind = 1
+	IPOwner_RESERVED_SUBNET         IPOwner_Kind = 2
+	IPOwner_POD_CIDR                IPOwner_Kind = 3
+	IPOwner_SERVICE_CIDR            IPOwner_Kind = 4
+	IPOwner_EXTERNAL_CIDR           IPOwner_Kind = 5
+	IPOwner_REMOTE_POD_CIDR         IPOwner_Kind = 6
+	IPOwner_REMOTE_EXTERNAL_CIDR    IPOwner_Kind = 7
+	IPOwner_LOCAL_NAT_POD_CIDR      IPOwner_Kind = 8
+	IPOwner_LOCAL_NAT_EXTERNAL_CIDR IPOwner_Kind = 9
+	IPOwner_ENDPOINT                IPOwner_Kind = 10
+	IPOwner_ENDPOINT_EXTERNAL_IP    IPOwner_Kind = 11
+	IPOwner_ENDPOINT_NATTED_IP      IPOwner_Kind = 12
+)
+
+// Enum value maps for IPOwner_Kind.
+var (
+	IPOwner_Kind_name = map[int32]string{
+		0:  "UNKNOWN",
+		1:  "NETWORK_POOL",
+		2:  "RESERVED_SUBNET",
+		3:  "POD_CIDR",
+		4:  "SERVICE_CIDR",
+		5:  "EXTERNAL_CIDR",
+		6:  "REMOTE_POD_CIDR",
+		7:  "REMOTE_EXTERNAL_CIDR",
+		8:  "LOCAL_NAT_POD_CIDR",
+		9:  "LOCAL_NAT_EXTERNAL_CIDR",
+		10: "ENDPOINT",
+		11: "ENDPOINT_EXTERNAL_IP",
+		12: "ENDPOINT_NATTED_IP",
+	}
+	IPOwner_Kind_value = map[string]int32{
+		"UNKNOWN":                 0,
+		"NETWORK_POOL":            1,
+		"RESERVED_SUBNET":         2,
+		"POD_CIDR":                3,
+		"SERVICE_CIDR":            4,
+		"EXTERNAL_CIDR":           5,
+		"REMOTE_POD_CIDR":         6,
+		"REMOTE_EXTERNAL_CIDR":    7,
+		"LOCAL_NAT_POD_CIDR":      8,
+		"LOCAL_NAT_EXTERNAL_CIDR": 9,
+		"ENDPOINT":                10,
+		"ENDPOINT_EXTERNAL_IP":    11,
+		"ENDPOINT_NATTED_IP":      12,
+	}
+)
+
+func (x IPOwner_Kind) Enum() *IPOwner_Kind {
+	p := new(IPOwner_Kind)
+	*p = x
+	return p
+}
+
+func (x IPOwner_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (IPOwner_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_liqonet_ipam_ipam_proto_enumTypes[0].Descriptor()
+}
+
+func (IPOwner_Kind) Type() protoreflect.EnumType {
+	return &file_pkg_liqonet_ipam_ipam_proto_enumTypes[0]
+}
+
+func (x IPOwner_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use IPOwner_Kind.Descriptor instead.
+func (IPOwner_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_liqonet_ipam_ipam_proto_rawDescGZIP(), []int{21, 0}
+}
+
 type MapRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -412,167 +491,1127 @@ func (x *BelongsResponse) GetBelongs() bool {
 	return false
 }
 
-var File_pkg_liqonet_ipam_ipam_proto protoreflect.FileDescriptor
+type ListPoolsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
 
-var file_pkg_liqonet_ipam_ipam_proto_rawDesc = []byte{
-	0x0a, 0x1b, 0x70, 0x6b, 0x67, 0x2f, 0x6c, 0x69, 0x71, 0x6f, 0x6e, 0x65, 0x74, 0x2f, 0x69, 0x70,
-	0x61, 0x6d, 0x2f, 0x69, 0x70, 0x61, 0x6d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x3a, 0x0a,
-	0x0a, 0x4d, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x63,
-	0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
-	0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x22, 0x1d, 0x0a, 0x0b, 0x4d, 0x61, 0x70,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x22, 0x3c, 0x0a, 0x0c, 0x55, 0x6e, 0x6d, 0x61,
-	0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6c, 0x75, 0x73,
-	0x74, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6c, 0x75,
-	0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x22, 0x0f, 0x0a, 0x0d, 0x55, 0x6e, 0x6d, 0x61, 0x70, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x43, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x48, 0x6f,
-	0x6d, 0x65, 0x50, 0x6f, 0x64, 0x49, 0x50, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c,
-	0x0a, 0x09, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x09, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x12, 0x0e, 0x0a, 0x02,
-	0x69, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x22, 0x2e, 0x0a, 0x14,
-	0x47, 0x65, 0x74, 0x48, 0x6f, 0x6d, 0x65, 0x50, 0x6f, 0x64, 0x49, 0x50, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x6f, 0x6d, 0x65, 0x49, 0x50, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x68, 0x6f, 0x6d, 0x65, 0x49, 0x50, 0x22, 0x20, 0x0a, 0x0e,
-	0x42, 0x65, 0x6c, 0x6f, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e,
-	0x0a, 0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x22, 0x2b,
-	0x0a, 0x0f, 0x42, 0x65, 0x6c, 0x6f, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x65, 0x6c, 0x6f, 0x6e, 0x67, 0x73, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x07, 0x62, 0x65, 0x6c, 0x6f, 0x6e, 0x67, 0x73, 0x32, 0xd8, 0x01, 0x0a, 0x04,
-	0x69, 0x70, 0x61, 0x6d, 0x12, 0x2a, 0x0a, 0x0d, 0x4d, 0x61, 0x70, 0x45, 0x6e, 0x64, 0x70, 0x6f,
-	0x69, 0x6e, 0x74, 0x49, 0x50, 0x12, 0x0b, 0x2e, 0x4d, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x4d, 0x61, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x30, 0x0a, 0x0f, 0x55, 0x6e, 0x6d, 0x61, 0x70, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e,
-	0x74, 0x49, 0x50, 0x12, 0x0d, 0x2e, 0x55, 0x6e, 0x6d, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x55, 0x6e, 0x6d, 0x61, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x3b, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x48, 0x6f, 0x6d, 0x65, 0x50, 0x6f, 0x64,
-	0x49, 0x50, 0x12, 0x14, 0x2e, 0x47, 0x65, 0x74, 0x48, 0x6f, 0x6d, 0x65, 0x50, 0x6f, 0x64, 0x49,
-	0x50, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x47, 0x65, 0x74, 0x48, 0x6f,
-	0x6d, 0x65, 0x50, 0x6f, 0x64, 0x49, 0x50, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x35, 0x0a, 0x10, 0x42, 0x65, 0x6c, 0x6f, 0x6e, 0x67, 0x73, 0x54, 0x6f, 0x50, 0x6f, 0x64, 0x43,
-	0x49, 0x44, 0x52, 0x12, 0x0f, 0x2e, 0x42, 0x65, 0x6c, 0x6f, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x42, 0x65, 0x6c, 0x6f, 0x6e, 0x67, 0x73, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x08, 0x5a, 0x06, 0x2e, 0x2f, 0x69, 0x70, 0x61, 0x6d,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+func (x *ListPoolsRequest) Reset() {
+	*x = ListPoolsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-var (
-	file_pkg_liqonet_ipam_ipam_proto_rawDescOnce sync.Once
-	file_pkg_liqonet_ipam_ipam_proto_rawDescData = file_pkg_liqonet_ipam_ipam_proto_rawDesc
-)
+func (x *ListPoolsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
 
-func file_pkg_liqonet_ipam_ipam_proto_rawDescGZIP() []byte {
-	file_pkg_liqonet_ipam_ipam_proto_rawDescOnce.Do(func() {
-		file_pkg_liqonet_ipam_ipam_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_liqonet_ipam_ipam_proto_rawDescData)
-	})
-	return file_pkg_liqonet_ipam_ipam_proto_rawDescData
+func (*ListPoolsRequest) ProtoMessage() {}
+
+func (x *ListPoolsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var file_pkg_liqonet_ipam_ipam_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
-var file_pkg_liqonet_ipam_ipam_proto_goTypes = []interface{}{
-	(*MapRequest)(nil),           // 0: MapRequest
-	(*MapResponse)(nil),          // 1: MapResponse
-	(*UnmapRequest)(nil),         // 2: UnmapRequest
-	(*UnmapResponse)(nil),        // 3: UnmapResponse
-	(*GetHomePodIPRequest)(nil),  // 4: GetHomePodIPRequest
-	(*GetHomePodIPResponse)(nil), // 5: GetHomePodIPResponse
-	(*BelongsRequest)(nil),       // 6: BelongsRequest
-	(*BelongsResponse)(nil),      // 7: BelongsResponse
+// Deprecated: Use ListPoolsRequest.ProtoReflect.Descriptor instead.
+func (*ListPoolsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_liqonet_ipam_ipam_proto_rawDescGZIP(), []int{8}
 }
-var file_pkg_liqonet_ipam_ipam_proto_depIdxs = []int32{
-	0, // 0: ipam.MapEndpointIP:input_type -> MapRequest
-	2, // 1: ipam.UnmapEndpointIP:input_type -> UnmapRequest
-	4, // 2: ipam.GetHomePodIP:input_type -> GetHomePodIPRequest
-	6, // 3: ipam.BelongsToPodCIDR:input_type -> BelongsRequest
-	1, // 4: ipam.MapEndpointIP:output_type -> MapResponse
-	3, // 5: ipam.UnmapEndpointIP:output_type -> UnmapResponse
-	5, // 6: ipam.GetHomePodIP:output_type -> GetHomePodIPResponse
-	7, // 7: ipam.BelongsToPodCIDR:output_type -> BelongsResponse
-	4, // [4:8] is the sub-list for method output_type
-	0, // [0:4] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+
+type ListPoolsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pools []string `protobuf:"bytes,1,rep,name=pools,proto3" json:"pools,omitempty"`
 }
 
-func init() { file_pkg_liqonet_ipam_ipam_proto_init() }
-func file_pkg_liqonet_ipam_ipam_proto_init() {
-	if File_pkg_liqonet_ipam_ipam_proto != nil {
-		return
+func (x *ListPoolsResponse) Reset() {
+	*x = ListPoolsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_pkg_liqonet_ipam_ipam_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MapRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_pkg_liqonet_ipam_ipam_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MapResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+}
+
+func (x *ListPoolsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPoolsResponse) ProtoMessage() {}
+
+func (x *ListPoolsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_pkg_liqonet_ipam_ipam_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UnmapRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPoolsResponse.ProtoReflect.Descriptor instead.
+func (*ListPoolsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_liqonet_ipam_ipam_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListPoolsResponse) GetPools() []string {
+	if x != nil {
+		return x.Pools
+	}
+	return nil
+}
+
+type ListReservedSubnetsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListReservedSubnetsRequest) Reset() {
+	*x = ListReservedSubnetsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListReservedSubnetsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReservedSubnetsRequest) ProtoMessage() {}
+
+func (x *ListReservedSubnetsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_pkg_liqonet_ipam_ipam_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UnmapResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReservedSubnetsRequest.ProtoReflect.Descriptor instead.
+func (*ListReservedSubnetsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_liqonet_ipam_ipam_proto_rawDescGZIP(), []int{10}
+}
+
+type ListReservedSubnetsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ReservedSubnets []string `protobuf:"bytes,1,rep,name=reservedSubnets,proto3" json:"reservedSubnets,omitempty"`
+}
+
+func (x *ListReservedSubnetsResponse) Reset() {
+	*x = ListReservedSubnetsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListReservedSubnetsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReservedSubnetsResponse) ProtoMessage() {}
+
+func (x *ListReservedSubnetsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_pkg_liqonet_ipam_ipam_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetHomePodIPRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReservedSubnetsResponse.ProtoReflect.Descriptor instead.
+func (*ListReservedSubnetsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_liqonet_ipam_ipam_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListReservedSubnetsResponse) GetReservedSubnets() []string {
+	if x != nil {
+		return x.ReservedSubnets
+	}
+	return nil
+}
+
+type ListClusterSubnetsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// If set, only the subnets of the given cluster are returned.
+	ClusterID string `protobuf:"bytes,1,opt,name=clusterID,proto3" json:"clusterID,omitempty"`
+}
+
+func (x *ListClusterSubnetsRequest) Reset() {
+	*x = ListClusterSubnetsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListClusterSubnetsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListClusterSubnetsRequest) ProtoMessage() {}
+
+func (x *ListClusterSubnetsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_pkg_liqonet_ipam_ipam_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetHomePodIPResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListClusterSubnetsRequest.ProtoReflect.Descriptor instead.
+func (*ListClusterSubnetsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_liqonet_ipam_ipam_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListClusterSubnetsRequest) GetClusterID() string {
+	if x != nil {
+		return x.ClusterID
+	}
+	return ""
+}
+
+type ClusterSubnets struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ClusterID              string `protobuf:"bytes,1,opt,name=clusterID,proto3" json:"clusterID,omitempty"`
+	LocalNATPodCIDR        string `protobuf:"bytes,2,opt,name=localNATPodCIDR,proto3" json:"localNATPodCIDR,omitempty"`
+	RemotePodCIDR          string `protobuf:"bytes,3,opt,name=remotePodCIDR,proto3" json:"remotePodCIDR,omitempty"`
+	LocalNATExternalCIDR   string `protobuf:"bytes,4,opt,name=localNATExternalCIDR,proto3" json:"localNATExternalCIDR,omitempty"`
+	RemoteExternalCIDR     string `protobuf:"bytes,5,opt,name=remoteExternalCIDR,proto3" json:"remoteExternalCIDR,omitempty"`
+	LocalNATPodCIDRv6      string `protobuf:"bytes,6,opt,name=localNATPodCIDRv6,proto3" json:"localNATPodCIDRv6,omitempty"`
+	RemotePodCIDRv6        string `protobuf:"bytes,7,opt,name=remotePodCIDRv6,proto3" json:"remotePodCIDRv6,omitempty"`
+	LocalNATExternalCIDRv6 string `protobuf:"bytes,8,opt,name=localNATExternalCIDRv6,proto3" json:"localNATExternalCIDRv6,omitempty"`
+	RemoteExternalCIDRv6   string `protobuf:"bytes,9,opt,name=remoteExternalCIDRv6,proto3" json:"remoteExternalCIDRv6,omitempty"`
+}
+
+func (x *ClusterSubnets) Reset() {
+	*x = ClusterSubnets{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClusterSubnets) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClusterSubnets) ProtoMessage() {}
+
+func (x *ClusterSubnets) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClusterSubnets.ProtoReflect.Descriptor instead.
+func (*ClusterSubnets) Descriptor() ([]byte, []int) {
+	return file_pkg_liqonet_ipam_ipam_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ClusterSubnets) GetClusterID() string {
+	if x != nil {
+		return x.ClusterID
+	}
+	return ""
+}
+
+func (x *ClusterSubnets) GetLocalNATPodCIDR() string {
+	if x != nil {
+		return x.LocalNATPodCIDR
+	}
+	return ""
+}
+
+func (x *ClusterSubnets) GetRemotePodCIDR() string {
+	if x != nil {
+		return x.RemotePodCIDR
+	}
+	return ""
+}
+
+func (x *ClusterSubnets) GetLocalNATExternalCIDR() string {
+	if x != nil {
+		return x.LocalNATExternalCIDR
+	}
+	return ""
+}
+
+func (x *ClusterSubnets) GetRemoteExternalCIDR() string {
+	if x != nil {
+		return x.RemoteExternalCIDR
+	}
+	return ""
+}
+
+func (x *ClusterSubnets) GetLocalNATPodCIDRv6() string {
+	if x != nil {
+		return x.LocalNATPodCIDRv6
+	}
+	return ""
+}
+
+func (x *ClusterSubnets) GetRemotePodCIDRv6() string {
+	if x != nil {
+		return x.RemotePodCIDRv6
+	}
+	return ""
+}
+
+func (x *ClusterSubnets) GetLocalNATExternalCIDRv6() string {
+	if x != nil {
+		return x.LocalNATExternalCIDRv6
+	}
+	return ""
+}
+
+func (x *ClusterSubnets) GetRemoteExternalCIDRv6() string {
+	if x != nil {
+		return x.RemoteExternalCIDRv6
+	}
+	return ""
+}
+
+type ListClusterSubnetsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ClusterSubnets []*ClusterSubnets `protobuf:"bytes,1,rep,name=clusterSubnets,proto3" json:"clusterSubnets,omitempty"`
+}
+
+func (x *ListClusterSubnetsResponse) Reset() {
+	*x = ListClusterSubnetsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListClusterSubnetsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListClusterSubnetsResponse) ProtoMessage() {}
+
+func (x *ListClusterSubnetsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListClusterSubnetsResponse.ProtoReflect.Descriptor instead.
+func (*ListClusterSubnetsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_liqonet_ipam_ipam_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListClusterSubnetsResponse) GetClusterSubnets() []*ClusterSubnets {
+	if x != nil {
+		return x.ClusterSubnets
+	}
+	return nil
+}
+
+type ListEndpointMappingsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// If set, only the endpoints reflected towards the given cluster are returned.
+	ClusterID string `protobuf:"bytes,1,opt,name=clusterID,proto3" json:"clusterID,omitempty"`
+}
+
+func (x *ListEndpointMappingsRequest) Reset() {
+	*x = ListEndpointMappingsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListEndpointMappingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEndpointMappingsRequest) ProtoMessage() {}
+
+func (x *ListEndpointMappingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEndpointMappingsRequest.ProtoReflect.Descriptor instead.
+func (*ListEndpointMappingsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_liqonet_ipam_ipam_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListEndpointMappingsRequest) GetClusterID() string {
+	if x != nil {
+		return x.ClusterID
+	}
+	return ""
+}
+
+type EndpointMapping struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ip                     string `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	ExternalCIDROriginalIP string `protobuf:"bytes,2,opt,name=externalCIDROriginalIP,proto3" json:"externalCIDROriginalIP,omitempty"`
+	// Maps the ID of each cluster the endpoint is reflected to onto the corresponding natted IP.
+	ClusterMappings map[string]string `protobuf:"bytes,3,rep,name=clusterMappings,proto3" json:"clusterMappings,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *EndpointMapping) Reset() {
+	*x = EndpointMapping{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EndpointMapping) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EndpointMapping) ProtoMessage() {}
+
+func (x *EndpointMapping) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EndpointMapping.ProtoReflect.Descriptor instead.
+func (*EndpointMapping) Descriptor() ([]byte, []int) {
+	return file_pkg_liqonet_ipam_ipam_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *EndpointMapping) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *EndpointMapping) GetExternalCIDROriginalIP() string {
+	if x != nil {
+		return x.ExternalCIDROriginalIP
+	}
+	return ""
+}
+
+func (x *EndpointMapping) GetClusterMappings() map[string]string {
+	if x != nil {
+		return x.ClusterMappings
+	}
+	return nil
+}
+
+type ListEndpointMappingsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	EndpointMappings []*EndpointMapping `protobuf:"bytes,1,rep,name=endpointMappings,proto3" json:"endpointMappings,omitempty"`
+}
+
+func (x *ListEndpointMappingsResponse) Reset() {
+	*x = ListEndpointMappingsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListEndpointMappingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEndpointMappingsResponse) ProtoMessage() {}
+
+func (x *ListEndpointMappingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEndpointMappingsResponse.ProtoReflect.Descriptor instead.
+func (*ListEndpointMappingsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_liqonet_ipam_ipam_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListEndpointMappingsResponse) GetEndpointMappings() []*EndpointMapping {
+	if x != nil {
+		return x.EndpointMappings
+	}
+	return nil
+}
+
+type ListNatMappingsConfiguredRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListNatMappingsConfiguredRequest) Reset() {
+	*x = ListNatMappingsConfiguredRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListNatMappingsConfiguredRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNatMappingsConfiguredRequest) ProtoMessage() {}
+
+func (x *ListNatMappingsConfiguredRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNatMappingsConfiguredRequest.ProtoReflect.Descriptor instead.
+func (*ListNatMappingsConfiguredRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_liqonet_ipam_ipam_proto_rawDescGZIP(), []int{18}
+}
+
+type ListNatMappingsConfiguredResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ClusterIDs []string `protobuf:"bytes,1,rep,name=clusterIDs,proto3" json:"clusterIDs,omitempty"`
+}
+
+func (x *ListNatMappingsConfiguredResponse) Reset() {
+	*x = ListNatMappingsConfiguredResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListNatMappingsConfiguredResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNatMappingsConfiguredResponse) ProtoMessage() {}
+
+func (x *ListNatMappingsConfiguredResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNatMappingsConfiguredResponse.ProtoReflect.Descriptor instead.
+func (*ListNatMappingsConfiguredResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_liqonet_ipam_ipam_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListNatMappingsConfiguredResponse) GetClusterIDs() []string {
+	if x != nil {
+		return x.ClusterIDs
+	}
+	return nil
+}
+
+type GetIPOwnerRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ip string `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+}
+
+func (x *GetIPOwnerRequest) Reset() {
+	*x = GetIPOwnerRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetIPOwnerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIPOwnerRequest) ProtoMessage() {}
+
+func (x *GetIPOwnerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIPOwnerRequest.ProtoReflect.Descriptor instead.
+func (*GetIPOwnerRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_liqonet_ipam_ipam_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetIPOwnerRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+type IPOwner struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind IPOwner_Kind `protobuf:"varint,1,opt,name=kind,proto3,enum=IPOwner_Kind" json:"kind,omitempty"`
+	// The network (or the single IP, for endpoint related owners) matching the requested IP.
+	Network string `protobuf:"bytes,2,opt,name=network,proto3" json:"network,omitempty"`
+	// The remote cluster the owner refers to, if any.
+	ClusterID string `protobuf:"bytes,3,opt,name=clusterID,proto3" json:"clusterID,omitempty"`
+	// The endpoint IP the owner refers to, for endpoint related owners.
+	EndpointIP string `protobuf:"bytes,4,opt,name=endpointIP,proto3" json:"endpointIP,omitempty"`
+}
+
+func (x *IPOwner) Reset() {
+	*x = IPOwner{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IPOwner) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IPOwner) ProtoMessage() {}
+
+func (x *IPOwner) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IPOwner.ProtoReflect.Descriptor instead.
+func (*IPOwner) Descriptor() ([]byte, []int) {
+	return file_pkg_liqonet_ipam_ipam_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *IPOwner) GetKind() IPOwner_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return IPOwner_UNKNOWN
+}
+
+func (x *IPOwner) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *IPOwner) GetClusterID() string {
+	if x != nil {
+		return x.ClusterID
+	}
+	return ""
+}
+
+func (x *IPOwner) GetEndpointIP() string {
+	if x != nil {
+		return x.EndpointIP
+	}
+	return ""
+}
+
+type GetIPOwnerResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Owners []*IPOwner `protobuf:"bytes,1,rep,name=owners,proto3" json:"owners,omitempty"`
+}
+
+func (x *GetIPOwnerResponse) Reset() {
+	*x = GetIPOwnerResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetIPOwnerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIPOwnerResponse) ProtoMessage() {}
+
+func (x *GetIPOwnerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_liqonet_ipam_ipam_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIPOwnerResponse.ProtoReflect.Descriptor instead.
+func (*GetIPOwnerResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_liqonet_ipam_ipam_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetIPOwnerResponse) GetOwners() []*IPOwner {
+	if x != nil {
+		return x.Owners
+	}
+	return nil
+}
+
+var File_pkg_liqonet_ipam_ipam_proto protoreflect.FileDescriptor
+
+var file_pkg_liqonet_ipam_ipam_proto_rawDesc = []byte{
+	0x0a, 0x1b, 0x70, 0x6b, 0x67, 0x2f, 0x6c, 0x69, 0x71, 0x6f, 0x6e, 0x65, 0x74, 0x2f, 0x69, 0x70,
+	0x61, 0x6d, 0x2f, 0x69, 0x70, 0x61, 0x6d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x3a, 0x0a,
+	0x0a, 0x4d, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x63,
+	0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x22, 0x1d, 0x0a, 0x0b, 0x4d, 0x61, 0x70,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x22, 0x3c, 0x0a, 0x0c, 0x55, 0x6e, 0x6d, 0x61,
+	0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6c, 0x75, 0x73,
+	0x74, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6c, 0x75,
+	0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x22, 0x0f, 0x0a, 0x0d, 0x55, 0x6e, 0x6d, 0x61, 0x70, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x43, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x48, 0x6f,
+	0x6d, 0x65, 0x50, 0x6f, 0x64, 0x49, 0x50, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c,
+	0x0a, 0x09, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x22, 0x2e, 0x0a, 0x14,
+	0x47, 0x65, 0x74, 0x48, 0x6f, 0x6d, 0x65, 0x50, 0x6f, 0x64, 0x49, 0x50, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x6f, 0x6d, 0x65, 0x49, 0x50, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x68, 0x6f, 0x6d, 0x65, 0x49, 0x50, 0x22, 0x20, 0x0a, 0x0e,
+	0x42, 0x65, 0x6c, 0x6f, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x22, 0x2b,
+	0x0a, 0x0f, 0x42, 0x65, 0x6c, 0x6f, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x65, 0x6c, 0x6f, 0x6e, 0x67, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x62, 0x65, 0x6c, 0x6f, 0x6e, 0x67, 0x73, 0x22, 0x12, 0x0a, 0x10, 0x4c,
+	0x69, 0x73, 0x74, 0x50, 0x6f, 0x6f, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22,
+	0x29, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x6f, 0x6f, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x6f, 0x6f, 0x6c, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x05, 0x70, 0x6f, 0x6f, 0x6c, 0x73, 0x22, 0x1c, 0x0a, 0x1a, 0x4c, 0x69,
+	0x73, 0x74, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x47, 0x0a, 0x1b, 0x4c, 0x69, 0x73, 0x74,
+	0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x72, 0x65, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x64, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0f, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74,
+	0x73, 0x22, 0x39, 0x0a, 0x19, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72,
+	0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c,
+	0x0a, 0x09, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x22, 0xa6, 0x03, 0x0a,
+	0x0e, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x73, 0x12,
+	0x1c, 0x0a, 0x09, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x12, 0x28, 0x0a,
+	0x0f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x4e, 0x41, 0x54, 0x50, 0x6f, 0x64, 0x43, 0x49, 0x44, 0x52,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x4e, 0x41, 0x54,
+	0x50, 0x6f, 0x64, 0x43, 0x49, 0x44, 0x52, 0x12, 0x24, 0x0a, 0x0d, 0x72, 0x65, 0x6d, 0x6f, 0x74,
+	0x65, 0x50, 0x6f, 0x64, 0x43, 0x49, 0x44, 0x52, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d,
+	0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x50, 0x6f, 0x64, 0x43, 0x49, 0x44, 0x52, 0x12, 0x32, 0x0a,
+	0x14, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x4e, 0x41, 0x54, 0x45, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61,
+	0x6c, 0x43, 0x49, 0x44, 0x52, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x14, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x4e, 0x41, 0x54, 0x45, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x43, 0x49, 0x44,
+	0x52, 0x12, 0x2e, 0x0a, 0x12, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x45, 0x78, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x43, 0x49, 0x44, 0x52, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x72,
+	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x45, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x43, 0x49, 0x44,
+	0x52, 0x12, 0x2c, 0x0a, 0x11, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x4e, 0x41, 0x54, 0x50, 0x6f, 0x64,
+	0x43, 0x49, 0x44, 0x52, 0x76, 0x36, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x6c, 0x6f,
+	0x63, 0x61, 0x6c, 0x4e, 0x41, 0x54, 0x50, 0x6f, 0x64, 0x43, 0x49, 0x44, 0x52, 0x76, 0x36, 0x12,
+	0x28, 0x0a, 0x0f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x50, 0x6f, 0x64, 0x43, 0x49, 0x44, 0x52,
+	0x76, 0x36, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65,
+	0x50, 0x6f, 0x64, 0x43, 0x49, 0x44, 0x52, 0x76, 0x36, 0x12, 0x36, 0x0a, 0x16, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x4e, 0x41, 0x54, 0x45, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x43, 0x49, 0x44,
+	0x52, 0x76, 0x36, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x16, 0x6c, 0x6f, 0x63, 0x61, 0x6c,
+	0x4e, 0x41, 0x54, 0x45, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x43, 0x49, 0x44, 0x52, 0x76,
+	0x36, 0x12, 0x32, 0x0a, 0x14, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x45, 0x78, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x43, 0x49, 0x44, 0x52, 0x76, 0x36, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x14, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x45, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x43,
+	0x49, 0x44, 0x52, 0x76, 0x36, 0x22, 0x55, 0x0a, 0x1a, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6c, 0x75,
+	0x73, 0x74, 0x65, 0x72, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x0e, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x53, 0x75,
+	0x62, 0x6e, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x43, 0x6c,
+	0x75, 0x73, 0x74, 0x65, 0x72, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x73, 0x52, 0x0e, 0x63, 0x6c,
+	0x75, 0x73, 0x74, 0x65, 0x72, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x73, 0x22, 0x3b, 0x0a, 0x1b,
+	0x4c, 0x69, 0x73, 0x74, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x4d, 0x61, 0x70, 0x70,
+	0x69, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x63,
+	0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x22, 0xee, 0x01, 0x0a, 0x0f, 0x45, 0x6e,
+	0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x12, 0x36, 0x0a,
+	0x16, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x43, 0x49, 0x44, 0x52, 0x4f, 0x72, 0x69,
+	0x67, 0x69, 0x6e, 0x61, 0x6c, 0x49, 0x50, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x16, 0x65,
+	0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x43, 0x49, 0x44, 0x52, 0x4f, 0x72, 0x69, 0x67, 0x69,
+	0x6e, 0x61, 0x6c, 0x49, 0x50, 0x12, 0x4f, 0x0a, 0x0f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72,
+	0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25,
+	0x2e, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67,
+	0x2e, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x4d, 0x61,
+	0x70, 0x70, 0x69, 0x6e, 0x67, 0x73, 0x1a, 0x42, 0x0a, 0x14, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65,
+	0x72, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x5c, 0x0a, 0x1c, 0x4c, 0x69,
+	0x73, 0x74, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e,
+	0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a, 0x10, 0x65, 0x6e,
+	0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x4d,
+	0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x52, 0x10, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74,
+	0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x73, 0x22, 0x22, 0x0a, 0x20, 0x4c, 0x69, 0x73, 0x74,
+	0x4e, 0x61, 0x74, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x75, 0x72, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x43, 0x0a, 0x21,
+	0x4c, 0x69, 0x73, 0x74, 0x4e, 0x61, 0x74, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x73, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44,
+	0x73, 0x22, 0x23, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x49, 0x50, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x22, 0x98, 0x03, 0x0a, 0x07, 0x49, 0x50, 0x4f, 0x77, 0x6e,
+	0x65, 0x72, 0x12, 0x21, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x0d, 0x2e, 0x49, 0x50, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x2e, 0x4b, 0x69, 0x6e, 0x64, 0x52,
+	0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x12,
+	0x1c, 0x0a, 0x09, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x12, 0x1e, 0x0a,
+	0x0a, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49, 0x50, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49, 0x50, 0x22, 0x91, 0x02,
+	0x0a, 0x04, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57,
+	0x4e, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x4e, 0x45, 0x54, 0x57, 0x4f, 0x52, 0x4b, 0x5f, 0x50,
+	0x4f, 0x4f, 0x4c, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x52, 0x45, 0x53, 0x45, 0x52, 0x56, 0x45,
+	0x44, 0x5f, 0x53, 0x55, 0x42, 0x4e, 0x45, 0x54, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x50, 0x4f,
+	0x44, 0x5f, 0x43, 0x49, 0x44, 0x52, 0x10, 0x03, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x45, 0x52, 0x56,
+	0x49, 0x43, 0x45, 0x5f, 0x43, 0x49, 0x44, 0x52, 0x10, 0x04, 0x12, 0x11, 0x0a, 0x0d, 0x45, 0x58,
+	0x54, 0x45, 0x52, 0x4e, 0x41, 0x4c, 0x5f, 0x43, 0x49, 0x44, 0x52, 0x10, 0x05, 0x12, 0x13, 0x0a,
+	0x0f, 0x52, 0x45, 0x4d, 0x4f, 0x54, 0x45, 0x5f, 0x50, 0x4f, 0x44, 0x5f, 0x43, 0x49, 0x44, 0x52,
+	0x10, 0x06, 0x12, 0x18, 0x0a, 0x14, 0x52, 0x45, 0x4d, 0x4f, 0x54, 0x45, 0x5f, 0x45, 0x58, 0x54,
+	0x45, 0x52, 0x4e, 0x41, 0x4c, 0x5f, 0x43, 0x49, 0x44, 0x52, 0x10, 0x07, 0x12, 0x16, 0x0a, 0x12,
+	0x4c, 0x4f, 0x43, 0x41, 0x4c, 0x5f, 0x4e, 0x41, 0x54, 0x5f, 0x50, 0x4f, 0x44, 0x5f, 0x43, 0x49,
+	0x44, 0x52, 0x10, 0x08, 0x12, 0x1b, 0x0a, 0x17, 0x4c, 0x4f, 0x43, 0x41, 0x4c, 0x5f, 0x4e, 0x41,
+	0x54, 0x5f, 0x45, 0x58, 0x54, 0x45, 0x52, 0x4e, 0x41, 0x4c, 0x5f, 0x43, 0x49, 0x44, 0x52, 0x10,
+	0x09, 0x12, 0x0c, 0x0a, 0x08, 0x45, 0x4e, 0x44, 0x50, 0x4f, 0x49, 0x4e, 0x54, 0x10, 0x0a, 0x12,
+	0x18, 0x0a, 0x14, 0x45, 0x4e, 0x44, 0x50, 0x4f, 0x49, 0x4e, 0x54, 0x5f, 0x45, 0x58, 0x54, 0x45,
+	0x52, 0x4e, 0x41, 0x4c, 0x5f, 0x49, 0x50, 0x10, 0x0b, 0x12, 0x16, 0x0a, 0x12, 0x45, 0x4e, 0x44,
+	0x50, 0x4f, 0x49, 0x4e, 0x54, 0x5f, 0x4e, 0x41, 0x54, 0x54, 0x45, 0x44, 0x5f, 0x49, 0x50, 0x10,
+	0x0c, 0x22, 0x36, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x49, 0x50, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x20, 0x0a, 0x06, 0x6f, 0x77, 0x6e, 0x65, 0x72,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x49, 0x50, 0x4f, 0x77, 0x6e, 0x65,
+	0x72, 0x52, 0x06, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x73, 0x32, 0x9d, 0x05, 0x0a, 0x04, 0x69, 0x70,
+	0x61, 0x6d, 0x12, 0x2a, 0x0a, 0x0d, 0x4d, 0x61, 0x70, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e,
+	0x74, 0x49, 0x50, 0x12, 0x0b, 0x2e, 0x4d, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x0c, 0x2e, 0x4d, 0x61, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30,
+	0x0a, 0x0f, 0x55, 0x6e, 0x6d, 0x61, 0x70, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49,
+	0x50, 0x12, 0x0d, 0x2e, 0x55, 0x6e, 0x6d, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x0e, 0x2e, 0x55, 0x6e, 0x6d, 0x61, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x3b, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x48, 0x6f, 0x6d, 0x65, 0x50, 0x6f, 0x64, 0x49, 0x50,
+	0x12, 0x14, 0x2e, 0x47, 0x65, 0x74, 0x48, 0x6f, 0x6d, 0x65, 0x50, 0x6f, 0x64, 0x49, 0x50, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x47, 0x65, 0x74, 0x48, 0x6f, 0x6d, 0x65,
+	0x50, 0x6f, 0x64, 0x49, 0x50, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a,
+	0x10, 0x42, 0x65, 0x6c, 0x6f, 0x6e, 0x67, 0x73, 0x54, 0x6f, 0x50, 0x6f, 0x64, 0x43, 0x49, 0x44,
+	0x52, 0x12, 0x0f, 0x2e, 0x42, 0x65, 0x6c, 0x6f, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x10, 0x2e, 0x42, 0x65, 0x6c, 0x6f, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x6f, 0x6f, 0x6c,
+	0x73, 0x12, 0x11, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x6f, 0x6f, 0x6c, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x6f, 0x6f, 0x6c, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x50, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74,
+	0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x73, 0x12,
+	0x1b, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x53, 0x75,
+	0x62, 0x6e, 0x65, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x53, 0x75, 0x62, 0x6e, 0x65,
+	0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a, 0x12, 0x4c, 0x69,
+	0x73, 0x74, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x73,
+	0x12, 0x1a, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x53, 0x75,
+	0x62, 0x6e, 0x65, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x53, 0x0a, 0x14, 0x4c, 0x69, 0x73,
+	0x74, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67,
+	0x73, 0x12, 0x1c, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74,
+	0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1d, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x4d, 0x61,
+	0x70, 0x70, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x62,
+	0x0a, 0x19, 0x4c, 0x69, 0x73, 0x74, 0x4e, 0x61, 0x74, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67,
+	0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x65, 0x64, 0x12, 0x21, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x4e, 0x61, 0x74, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x73, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x75, 0x72, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4e, 0x61, 0x74, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x73,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x35, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x49, 0x50, 0x4f, 0x77, 0x6e, 0x65, 0x72,
+	0x12, 0x12, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x50, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x50, 0x4f, 0x77, 0x6e, 0x65,
+	0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x08, 0x5a, 0x06, 0x2e, 0x2f, 0x69,
+	0x70, 0x61, 0x6d, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_liqonet_ipam_ipam_proto_rawDescOnce sync.Once
+	file_pkg_liqonet_ipam_ipam_proto_rawDescData = file_pkg_liqonet_ipam_ipam_proto_rawDesc
+)
+
+func file_pkg_liqonet_ipam_ipam_proto_rawDescGZIP() []byte {
+	file_pkg_liqonet_ipam_ipam_proto_rawDescOnce.Do(func() {
+		file_pkg_liqonet_ipam_ipam_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_liqonet_ipam_ipam_proto_rawDescData)
+	})
+	return file_pkg_liqonet_ipam_ipam_proto_rawDescData
+}
+
+var file_pkg_liqonet_ipam_ipam_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pkg_liqonet_ipam_ipam_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_pkg_liqonet_ipam_ipam_proto_goTypes = []interface{}{
+	(IPOwner_Kind)(0),                         // 0: IPOwner.Kind
+	(*MapRequest)(nil),                        // 1: MapRequest
+	(*MapResponse)(nil),                       // 2: MapResponse
+	(*UnmapRequest)(nil),                      // 3: UnmapRequest
+	(*UnmapResponse)(nil),                     // 4: UnmapResponse
+	(*GetHomePodIPRequest)(nil),               // 5: GetHomePodIPRequest
+	(*GetHomePodIPResponse)(nil),              // 6: GetHomePodIPResponse
+	(*BelongsRequest)(nil),                    // 7: BelongsRequest
+	(*BelongsResponse)(nil),                   // 8: BelongsResponse
+	(*ListPoolsRequest)(nil),                  // 9: ListPoolsRequest
+	(*ListPoolsResponse)(nil),                 // 10: ListPoolsResponse
+	(*ListReservedSubnetsRequest)(nil),        // 11: ListReservedSubnetsRequest
+	(*ListReservedSubnetsResponse)(nil),       // 12: ListReservedSubnetsResponse
+	(*ListClusterSubnetsRequest)(nil),         // 13: ListClusterSubnetsRequest
+	(*ClusterSubnets)(nil),                    // 14: ClusterSubnets
+	(*ListClusterSubnetsResponse)(nil),        // 15: ListClusterSubnetsResponse
+	(*ListEndpointMappingsRequest)(nil),       // 16: ListEndpointMappingsRequest
+	(*EndpointMapping)(nil),                   // 17: EndpointMapping
+	(*ListEndpointMappingsResponse)(nil),      // 18: ListEndpointMappingsResponse
+	(*ListNatMappingsConfiguredRequest)(nil),  // 19: ListNatMappingsConfiguredRequest
+	(*ListNatMappingsConfiguredResponse)(nil), // 20: ListNatMappingsConfiguredResponse
+	(*GetIPOwnerRequest)(nil),                 // 21: GetIPOwnerRequest
+	(*IPOwner)(nil),                           // 22: IPOwner
+	(*GetIPOwnerResponse)(nil),                // 23: GetIPOwnerResponse
+	nil,                                       // 24: EndpointMapping.ClusterMappingsEntry
+}
+var file_pkg_liqonet_ipam_ipam_proto_depIdxs = []int32{
+	14, // 0: ListClusterSubnetsResponse.clusterSubnets:type_name -> ClusterSubnets
+	24, // 1: EndpointMapping.clusterMappings:type_name -> EndpointMapping.ClusterMappingsEntry
+	17, // 2: ListEndpointMappingsResponse.endpointMappings:type_name -> EndpointMapping
+	0,  // 3: IPOwner.kind:type_name -> IPOwner.Kind
+	22, // 4: GetIPOwnerResponse.owners:type_name -> IPOwner
+	1,  // 5: ipam.MapEndpointIP:input_type -> MapRequest
+	3,  // 6: ipam.UnmapEndpointIP:input_type -> UnmapRequest
+	5,  // 7: ipam.GetHomePodIP:input_type -> GetHomePodIPRequest
+	7,  // 8: ipam.BelongsToPodCIDR:input_type -> BelongsRequest
+	9,  // 9: ipam.ListPools:input_type -> ListPoolsRequest
+	11, // 10: ipam.ListReservedSubnets:input_type -> ListReservedSubnetsRequest
+	13, // 11: ipam.ListClusterSubnets:input_type -> ListClusterSubnetsRequest
+	16, // 12: ipam.ListEndpointMappings:input_type -> ListEndpointMappingsRequest
+	19, // 13: ipam.ListNatMappingsConfigured:input_type -> ListNatMappingsConfiguredRequest
+	21, // 14: ipam.GetIPOwner:input_type -> GetIPOwnerRequest
+	2,  // 15: ipam.MapEndpointIP:output_type -> MapResponse
+	4,  // 16: ipam.UnmapEndpointIP:output_type -> UnmapResponse
+	6,  // 17: ipam.GetHomePodIP:output_type -> GetHomePodIPResponse
+	8,  // 18: ipam.BelongsToPodCIDR:output_type -> BelongsResponse
+	10, // 19: ipam.ListPools:output_type -> ListPoolsResponse
+	12, // 20: ipam.ListReservedSubnets:output_type -> ListReservedSubnetsResponse
+	15, // 21: ipam.ListClusterSubnets:output_type -> ListClusterSubnetsResponse
+	18, // 22: ipam.ListEndpointMappings:output_type -> ListEndpointMappingsResponse
+	20, // 23: ipam.ListNatMappingsConfigured:output_type -> ListNatMappingsConfiguredResponse
+	23, // 24: ipam.GetIPOwner:output_type -> GetIPOwnerResponse
+	15, // [15:25] is the sub-list for method output_type
+	5,  // [5:15] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_pkg_liqonet_ipam_ipam_proto_init() }
+func file_pkg_liqonet_ipam_ipam_proto_init() {
+	if File_pkg_liqonet_ipam_ipam_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_liqonet_ipam_ipam_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MapRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_liqonet_ipam_ipam_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MapResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_liqonet_ipam_ipam_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnmapRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_liqonet_ipam_ipam_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnmapResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_liqonet_ipam_ipam_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetHomePodIPRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_liqonet_ipam_ipam_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetHomePodIPResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
 		}
 		file_pkg_liqonet_ipam_ipam_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*BelongsRequest); i {
@@ -598,19 +1637,200 @@ func file_pkg_liqonet_ipam_ipam_proto_init() {
 				return nil
 			}
 		}
+		file_pkg_liqonet_ipam_ipam_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListPoolsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_liqonet_ipam_ipam_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListPoolsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_liqonet_ipam_ipam_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListReservedSubnetsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_liqonet_ipam_ipam_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListReservedSubnetsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_liqonet_ipam_ipam_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListClusterSubnetsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_liqonet_ipam_ipam_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ClusterSubnets); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_liqonet_ipam_ipam_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListClusterSubnetsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_liqonet_ipam_ipam_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListEndpointMappingsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_liqonet_ipam_ipam_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EndpointMapping); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_liqonet_ipam_ipam_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListEndpointMappingsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_liqonet_ipam_ipam_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListNatMappingsConfiguredRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_liqonet_ipam_ipam_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListNatMappingsConfiguredResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_liqonet_ipam_ipam_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetIPOwnerRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_liqonet_ipam_ipam_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IPOwner); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_liqonet_ipam_ipam_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetIPOwnerResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pkg_liqonet_ipam_ipam_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   8,
+			NumEnums:      1,
+			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_pkg_liqonet_ipam_ipam_proto_goTypes,
 		DependencyIndexes: file_pkg_liqonet_ipam_ipam_proto_depIdxs,
+		EnumInfos:         file_pkg_liqonet_ipam_ipam_proto_enumTypes,
 		MessageInfos:      file_pkg_liqonet_ipam_ipam_proto_msgTypes,
 	}.Build()
 	File_pkg_liqonet_ipam_ipam_proto = out.File
@@ -0,0 +1,241 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/liqotech/liqo/pkg/consts"
+	liqoneterrors "github.com/liqotech/liqo/pkg/liqonet/errors"
+)
+
+// ListPools returns the network pools currently managed by the IPAM.
+func (liqoIPAM *IPAM) ListPools(ctx context.Context, _ *ListPoolsRequest) (*ListPoolsResponse, error) {
+	liqoIPAM.mutex.Lock()
+	defer liqoIPAM.mutex.Unlock()
+
+	pools := append([]string{}, liqoIPAM.ipamStorage.getPools()...)
+	sort.Strings(pools)
+	return &ListPoolsResponse{Pools: pools}, nil
+}
+
+// ListReservedSubnets returns the subnets that have been reserved and cannot be used by the IPAM.
+func (liqoIPAM *IPAM) ListReservedSubnets(ctx context.Context, _ *ListReservedSubnetsRequest) (*ListReservedSubnetsResponse, error) {
+	liqoIPAM.mutex.Lock()
+	defer liqoIPAM.mutex.Unlock()
+
+	reserved := append([]string{}, liqoIPAM.ipamStorage.getReservedSubnets()...)
+	sort.Strings(reserved)
+	return &ListReservedSubnetsResponse{ReservedSubnets: reserved}, nil
+}
+
+// ListClusterSubnets returns the subnets configured for each remote cluster,
+// optionally restricted to the cluster specified in the request.
+func (liqoIPAM *IPAM) ListClusterSubnets(ctx context.Context, request *ListClusterSubnetsRequest) (*ListClusterSubnetsResponse, error) {
+	liqoIPAM.mutex.Lock()
+	defer liqoIPAM.mutex.Unlock()
+
+	clusterSubnets := liqoIPAM.ipamStorage.getClusterSubnets()
+	response := &ListClusterSubnetsResponse{}
+	for _, clusterID := range sortedKeys(clusterSubnets) {
+		if request.GetClusterID() != "" && request.GetClusterID() != clusterID {
+			continue
+		}
+		subnets := clusterSubnets[clusterID]
+		response.ClusterSubnets = append(response.ClusterSubnets, &ClusterSubnets{
+			ClusterID:              clusterID,
+			LocalNATPodCIDR:        subnets.LocalNATPodCIDR,
+			RemotePodCIDR:          subnets.RemotePodCIDR,
+			LocalNATExternalCIDR:   subnets.LocalNATExternalCIDR,
+			RemoteExternalCIDR:     subnets.RemoteExternalCIDR,
+			LocalNATPodCIDRv6:      subnets.LocalNATPodCIDRv6,
+			RemotePodCIDRv6:        subnets.RemotePodCIDRv6,
+			LocalNATExternalCIDRv6: subnets.LocalNATExternalCIDRv6,
+			RemoteExternalCIDRv6:   subnets.RemoteExternalCIDRv6,
+		})
+	}
+
+	if request.GetClusterID() != "" && len(response.ClusterSubnets) == 0 {
+		return &ListClusterSubnetsResponse{}, fmt.Errorf("cannot list subnets of cluster %s: cluster subnets are not set",
+			request.GetClusterID())
+	}
+	return response, nil
+}
+
+// ListEndpointMappings returns the endpoints mapped on the local ExternalCIDR, along with the natted IP
+// assigned to them for each remote cluster. If a cluster is specified in the request, only the endpoints
+// reflected towards that cluster are returned.
+func (liqoIPAM *IPAM) ListEndpointMappings(ctx context.Context, request *ListEndpointMappingsRequest) (*ListEndpointMappingsResponse, error) {
+	liqoIPAM.mutex.Lock()
+	defer liqoIPAM.mutex.Unlock()
+
+	endpointMappings := liqoIPAM.ipamStorage.getEndpointMappings()
+	response := &ListEndpointMappingsResponse{}
+	for _, ip := range sortedKeys(endpointMappings) {
+		mapping := endpointMappings[ip]
+		if _, found := mapping.ClusterMappings[request.GetClusterID()]; request.GetClusterID() != "" && !found {
+			continue
+		}
+
+		clusterMappings := make(map[string]string, len(mapping.ClusterMappings))
+		for clusterID, clusterMapping := range mapping.ClusterMappings {
+			clusterMappings[clusterID] = clusterMapping.ExternalCIDRNattedIP
+		}
+		response.EndpointMappings = append(response.EndpointMappings, &EndpointMapping{
+			Ip:                     ip,
+			ExternalCIDROriginalIP: mapping.ExternalCIDROriginalIP,
+			ClusterMappings:        clusterMappings,
+		})
+	}
+	return response, nil
+}
+
+// ListNatMappingsConfigured returns the IDs of the remote clusters whose NAT mappings have been initialized.
+func (liqoIPAM *IPAM) ListNatMappingsConfigured(ctx context.Context, _ *ListNatMappingsConfiguredRequest) (
+	*ListNatMappingsConfiguredResponse, error) {
+	liqoIPAM.mutex.Lock()
+	defer liqoIPAM.mutex.Unlock()
+
+	return &ListNatMappingsConfiguredResponse{ClusterIDs: sortedKeys(liqoIPAM.ipamStorage.getNatMappingsConfigured())}, nil
+}
+
+// GetIPOwner returns every network and endpoint mapping known by the IPAM which the given IP belongs to.
+func (liqoIPAM *IPAM) GetIPOwner(ctx context.Context, request *GetIPOwnerRequest) (*GetIPOwnerResponse, error) {
+	owners, err := liqoIPAM.getIPOwnerInternal(request.GetIp())
+	if err != nil {
+		return &GetIPOwnerResponse{}, fmt.Errorf("cannot get owner of IP %s: %w", request.GetIp(), err)
+	}
+	return &GetIPOwnerResponse{Owners: owners}, nil
+}
+
+// Internal implementation of exported func GetIPOwner.
+func (liqoIPAM *IPAM) getIPOwnerInternal(ip string) ([]*IPOwner, error) {
+	if netIP := net.ParseIP(ip); netIP == nil {
+		return nil, &liqoneterrors.WrongParameter{
+			Reason:    liqoneterrors.ValidIP,
+			Parameter: ip,
+		}
+	}
+
+	liqoIPAM.mutex.Lock()
+	defer liqoIPAM.mutex.Unlock()
+
+	var owners []*IPOwner
+	// appendIfContains adds a new owner in case the network is set and contains the IP.
+	appendIfContains := func(kind IPOwner_Kind, network, clusterID string) error {
+		if network == "" || network == consts.DefaultCIDRValue {
+			return nil
+		}
+		belongs, err := ipBelongsToNetwork(ip, network)
+		if err != nil {
+			return err
+		}
+		if belongs {
+			owners = append(owners, &IPOwner{Kind: kind, Network: network, ClusterID: clusterID})
+		}
+		return nil
+	}
+
+	// Local networks.
+	locals := []struct {
+		kind    IPOwner_Kind
+		network string
+	}{
+		{IPOwner_POD_CIDR, liqoIPAM.ipamStorage.getPodCIDR()},
+		{IPOwner_SERVICE_CIDR, liqoIPAM.ipamStorage.getServiceCIDR()},
+		{IPOwner_EXTERNAL_CIDR, liqoIPAM.ipamStorage.getExternalCIDR()},
+		{IPOwner_POD_CIDR, liqoIPAM.ipamStorage.getPodCIDRv6()},
+		{IPOwner_SERVICE_CIDR, liqoIPAM.ipamStorage.getServiceCIDRv6()},
+		{IPOwner_EXTERNAL_CIDR, liqoIPAM.ipamStorage.getExternalCIDRv6()},
+	}
+	for _, local := range locals {
+		if err := appendIfContains(local.kind, local.network, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	// Networks used by remote clusters.
+	clusterSubnets := liqoIPAM.ipamStorage.getClusterSubnets()
+	for _, clusterID := range sortedKeys(clusterSubnets) {
+		subnets := clusterSubnets[clusterID]
+		remotes := []struct {
+			kind    IPOwner_Kind
+			network string
+		}{
+			{IPOwner_REMOTE_POD_CIDR, subnets.RemotePodCIDR},
+			{IPOwner_REMOTE_EXTERNAL_CIDR, subnets.RemoteExternalCIDR},
+			{IPOwner_LOCAL_NAT_POD_CIDR, subnets.LocalNATPodCIDR},
+			{IPOwner_LOCAL_NAT_EXTERNAL_CIDR, subnets.LocalNATExternalCIDR},
+			{IPOwner_REMOTE_POD_CIDR, subnets.RemotePodCIDRv6},
+			{IPOwner_REMOTE_EXTERNAL_CIDR, subnets.RemoteExternalCIDRv6},
+			{IPOwner_LOCAL_NAT_POD_CIDR, subnets.LocalNATPodCIDRv6},
+			{IPOwner_LOCAL_NAT_EXTERNAL_CIDR, subnets.LocalNATExternalCIDRv6},
+		}
+		for _, remote := range remotes {
+			if err := appendIfContains(remote.kind, remote.network, clusterID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Endpoint mappings.
+	endpointMappings := liqoIPAM.ipamStorage.getEndpointMappings()
+	for _, endpointIP := range sortedKeys(endpointMappings) {
+		mapping := endpointMappings[endpointIP]
+		if endpointIP == ip {
+			owners = append(owners, &IPOwner{Kind: IPOwner_ENDPOINT, Network: endpointIP, EndpointIP: endpointIP})
+		}
+		if mapping.ExternalCIDROriginalIP == ip {
+			owners = append(owners, &IPOwner{Kind: IPOwner_ENDPOINT_EXTERNAL_IP, Network: ip, EndpointIP: endpointIP})
+		}
+		for _, clusterID := range sortedKeys(mapping.ClusterMappings) {
+			if mapping.ClusterMappings[clusterID].ExternalCIDRNattedIP == ip {
+				owners = append(owners, &IPOwner{Kind: IPOwner_ENDPOINT_NATTED_IP, Network: ip,
+					ClusterID: clusterID, EndpointIP: endpointIP})
+			}
+		}
+	}
+
+	// Reserved subnets and network pools.
+	reserved := append([]string{}, liqoIPAM.ipamStorage.getReservedSubnets()...)
+	sort.Strings(reserved)
+	for _, network := range reserved {
+		if err := appendIfContains(IPOwner_RESERVED_SUBNET, network, ""); err != nil {
+			return nil, err
+		}
+	}
+	pools := append([]string{}, liqoIPAM.ipamStorage.getPools()...)
+	sort.Strings(pools)
+	for _, network := range pools {
+		if err := appendIfContains(IPOwner_NETWORK_POOL, network, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	return owners, nil
+}
+
+// sortedKeys returns the keys of the given map, sorted in increasing order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -46,6 +46,9 @@ const (
 	endpointMappingsUpdate      = "endpointMappings"
 	podCIDRUpdate               = "podCIDR"
 	serviceCIDRUpdate           = "serviceCIDR"
+	externalCIDRv6Update        = "externalCIDRv6"
+	podCIDRv6Update             = "podCIDRv6"
+	serviceCIDRv6Update         = "serviceCIDRv6"
 	natMappingsConfiguredUpdate = "natMappingsConfigured"
 	updateOpAdd                 = "add"
 	updateOpRemove              = "remove"
@@ -59,6 +62,9 @@ type IpamStorage interface {
 	updateEndpointMappings(endpoints map[string]netv1alpha1.EndpointMapping) error
 	updatePodCIDR(podCIDR string) error
 	updateServiceCIDR(serviceCIDR string) error
+	updateExternalCIDRv6(externalCIDR string) error
+	updatePodCIDRv6(podCIDR string) error
+	updateServiceCIDRv6(serviceCIDR string) error
 	updateReservedSubnets(subnet, operation string) error
 	updateNatMappingsConfigured(natMappingsConfigured map[string]netv1alpha1.ConfiguredCluster) error
 	getClusterSubnets() map[string]netv1alpha1.Subnets
@@ -67,6 +73,9 @@ type IpamStorage interface {
 	getEndpointMappings() map[string]netv1alpha1.EndpointMapping
 	getPodCIDR() string
 	getServiceCIDR() string
+	getExternalCIDRv6() string
+	getPodCIDRv6() string
+	getServiceCIDRv6() string
 	getReservedSubnets() []string
 	getNatMappingsConfigured() map[string]netv1alpha1.ConfiguredCluster
 	goipam.Storage
@@ -263,6 +272,18 @@ func (ipamStorage *IPAMStorage) updateServiceCIDR(serviceCIDR string) error {
 	return ipamStorage.updateConfig(serviceCIDRUpdate, serviceCIDR)
 }
 
+func (ipamStorage *IPAMStorage) updateExternalCIDRv6(externalCIDR string) error {
+	return ipamStorage.updateConfig(externalCIDRv6Update, externalCIDR)
+}
+
+func (ipamStorage *IPAMStorage) updatePodCIDRv6(podCIDR string) error {
+	return ipamStorage.updateConfig(podCIDRv6Update, podCIDR)
+}
+
+func (ipamStorage *IPAMStorage) updateServiceCIDRv6(serviceCIDR string) error {
+	return ipamStorage.updateConfig(serviceCIDRv6Update, serviceCIDR)
+}
+
 func (ipamStorage *IPAMStorage) updateNatMappingsConfigured(natMappingsConfigured map[string]netv1alpha1.ConfiguredCluster) error {
 	return ipamStorage.updateConfig(natMappingsConfiguredUpdate, natMappingsConfigured)
 }
@@ -274,9 +295,11 @@ func (ipamStorage *IPAMStorage) updateConfig(updateType string, data interface{}
 		return err
 	}
 
+	// The "add" operation replaces the value of fields already present, and sets the
+	// optional ones (e.g., the IPv6 networks) which may have not been set yet.
 	var b bytes.Buffer
 	patch := fmt.Sprintf(
-		`[{"op": "replace", "path": "/spec/%s", "value": `,
+		`[{"op": "add", "path": "/spec/%s", "value": `,
 		updateType)
 	b.WriteString(patch)
 	b.Write(jsonData)
@@ -324,6 +347,18 @@ func (ipamStorage *IPAMStorage) getServiceCIDR() string {
 	return ipamStorage.getConfig().Spec.ServiceCIDR
 }
 
+func (ipamStorage *IPAMStorage) getExternalCIDRv6() string {
+	return ipamStorage.getConfig().Spec.ExternalCIDRv6
+}
+
+func (ipamStorage *IPAMStorage) getPodCIDRv6() string {
+	return ipamStorage.getConfig().Spec.PodCIDRv6
+}
+
+func (ipamStorage *IPAMStorage) getServiceCIDRv6() string {
+	return ipamStorage.getConfig().Spec.ServiceCIDRv6
+}
+
 func (ipamStorage *IPAMStorage) getReservedSubnets() []string {
 	return ipamStorage.getConfig().Spec.ReservedSubnets
 }
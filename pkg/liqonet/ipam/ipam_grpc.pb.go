@@ -20,10 +20,16 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	Ipam_MapEndpointIP_FullMethodName    = "/ipam/MapEndpointIP"
-	Ipam_UnmapEndpointIP_FullMethodName  = "/ipam/UnmapEndpointIP"
-	Ipam_GetHomePodIP_FullMethodName     = "/ipam/GetHomePodIP"
-	Ipam_BelongsToPodCIDR_FullMethodName = "/ipam/BelongsToPodCIDR"
+	Ipam_MapEndpointIP_FullMethodName             = "/ipam/MapEndpointIP"
+	Ipam_UnmapEndpointIP_FullMethodName           = "/ipam/UnmapEndpointIP"
+	Ipam_GetHomePodIP_FullMethodName              = "/ipam/GetHomePodIP"
+	Ipam_BelongsToPodCIDR_FullMethodName          = "/ipam/BelongsToPodCIDR"
+	Ipam_ListPools_FullMethodName                 = "/ipam/ListPools"
+	Ipam_ListReservedSubnets_FullMethodName       = "/ipam/ListReservedSubnets"
+	Ipam_ListClusterSubnets_FullMethodName        = "/ipam/ListClusterSubnets"
+	Ipam_ListEndpointMappings_FullMethodName      = "/ipam/ListEndpointMappings"
+	Ipam_ListNatMappingsConfigured_FullMethodName = "/ipam/ListNatMappingsConfigured"
+	Ipam_GetIPOwner_FullMethodName                = "/ipam/GetIPOwner"
 )
 
 // IpamClient is the client API for Ipam service.
@@ -34,6 +40,12 @@ type IpamClient interface {
 	UnmapEndpointIP(ctx context.Context, in *UnmapRequest, opts ...grpc.CallOption) (*UnmapResponse, error)
 	GetHomePodIP(ctx context.Context, in *GetHomePodIPRequest, opts ...grpc.CallOption) (*GetHomePodIPResponse, error)
 	BelongsToPodCIDR(ctx context.Context, in *BelongsRequest, opts ...grpc.CallOption) (*BelongsResponse, error)
+	ListPools(ctx context.Context, in *ListPoolsRequest, opts ...grpc.CallOption) (*ListPoolsResponse, error)
+	ListReservedSubnets(ctx context.Context, in *ListReservedSubnetsRequest, opts ...grpc.CallOption) (*ListReservedSubnetsResponse, error)
+	ListClusterSubnets(ctx context.Context, in *ListClusterSubnetsRequest, opts ...grpc.CallOption) (*ListClusterSubnetsResponse, error)
+	ListEndpointMappings(ctx context.Context, in *ListEndpointMappingsRequest, opts ...grpc.CallOption) (*ListEndpointMappingsResponse, error)
+	ListNatMappingsConfigured(ctx context.Context, in *ListNatMappingsConfiguredRequest, opts ...grpc.CallOption) (*ListNatMappingsConfiguredResponse, error)
+	GetIPOwner(ctx context.Context, in *GetIPOwnerRequest, opts ...grpc.CallOption) (*GetIPOwnerResponse, error)
 }
 
 type ipamClient struct {
@@ -80,6 +92,60 @@ func (c *ipamClient) BelongsToPodCIDR(ctx context.Context, in *BelongsRequest, o
 	return out, nil
 }
 
+func (c *ipamClient) ListPools(ctx context.Context, in *ListPoolsRequest, opts ...grpc.CallOption) (*ListPoolsResponse, error) {
+	out := new(ListPoolsResponse)
+	err := c.cc.Invoke(ctx, Ipam_ListPools_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ipamClient) ListReservedSubnets(ctx context.Context, in *ListReservedSubnetsRequest, opts ...grpc.CallOption) (*ListReservedSubnetsResponse, error) {
+	out := new(ListReservedSubnetsResponse)
+	err := c.cc.Invoke(ctx, Ipam_ListReservedSubnets_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ipamClient) ListClusterSubnets(ctx context.Context, in *ListClusterSubnetsRequest, opts ...grpc.CallOption) (*ListClusterSubnetsResponse, error) {
+	out := new(ListClusterSubnetsResponse)
+	err := c.cc.Invoke(ctx, Ipam_ListClusterSubnets_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ipamClient) ListEndpointMappings(ctx context.Context, in *ListEndpointMappingsRequest, opts ...grpc.CallOption) (*ListEndpointMappingsResponse, error) {
+	out := new(ListEndpointMappingsResponse)
+	err := c.cc.Invoke(ctx, Ipam_ListEndpointMappings_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ipamClient) ListNatMappingsConfigured(ctx context.Context, in *ListNatMappingsConfiguredRequest, opts ...grpc.CallOption) (*ListNatMappingsConfiguredResponse, error) {
+	out := new(ListNatMappingsConfiguredResponse)
+	err := c.cc.Invoke(ctx, Ipam_ListNatMappingsConfigured_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ipamClient) GetIPOwner(ctx context.Context, in *GetIPOwnerRequest, opts ...grpc.CallOption) (*GetIPOwnerResponse, error) {
+	out := new(GetIPOwnerResponse)
+	err := c.cc.Invoke(ctx, Ipam_GetIPOwner_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // IpamServer is the server API for Ipam service.
 // All implementations must embed UnimplementedIpamServer
 // for forward compatibility
@@ -88,6 +154,12 @@ type IpamServer interface {
 	UnmapEndpointIP(context.Context, *UnmapRequest) (*UnmapResponse, error)
 	GetHomePodIP(context.Context, *GetHomePodIPRequest) (*GetHomePodIPResponse, error)
 	BelongsToPodCIDR(context.Context, *BelongsRequest) (*BelongsResponse, error)
+	ListPools(context.Context, *ListPoolsRequest) (*ListPoolsResponse, error)
+	ListReservedSubnets(context.Context, *ListReservedSubnetsRequest) (*ListReservedSubnetsResponse, error)
+	ListClusterSubnets(context.Context, *ListClusterSubnetsRequest) (*ListClusterSubnetsResponse, error)
+	ListEndpointMappings(context.Context, *ListEndpointMappingsRequest) (*ListEndpointMappingsResponse, error)
+	ListNatMappingsConfigured(context.Context, *ListNatMappingsConfiguredRequest) (*ListNatMappingsConfiguredResponse, error)
+	GetIPOwner(context.Context, *GetIPOwnerRequest) (*GetIPOwnerResponse, error)
 	mustEmbedUnimplementedIpamServer()
 }
 
@@ -107,6 +179,24 @@ func (UnimplementedIpamServer) GetHomePodIP(context.Context, *GetHomePodIPReques
 func (UnimplementedIpamServer) BelongsToPodCIDR(context.Context, *BelongsRequest) (*BelongsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BelongsToPodCIDR not implemented")
 }
+func (UnimplementedIpamServer) ListPools(context.Context, *ListPoolsRequest) (*ListPoolsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPools not implemented")
+}
+func (UnimplementedIpamServer) ListReservedSubnets(context.Context, *ListReservedSubnetsRequest) (*ListReservedSubnetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReservedSubnets not implemented")
+}
+func (UnimplementedIpamServer) ListClusterSubnets(context.Context, *ListClusterSubnetsRequest) (*ListClusterSubnetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListClusterSubnets not implemented")
+}
+func (UnimplementedIpamServer) ListEndpointMappings(context.Context, *ListEndpointMappingsRequest) (*ListEndpointMappingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEndpointMappings not implemented")
+}
+func (UnimplementedIpamServer) ListNatMappingsConfigured(context.Context, *ListNatMappingsConfiguredRequest) (*ListNatMappingsConfiguredResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNatMappingsConfigured not implemented")
+}
+func (UnimplementedIpamServer) GetIPOwner(context.Context, *GetIPOwnerRequest) (*GetIPOwnerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIPOwner not implemented")
+}
 func (UnimplementedIpamServer) mustEmbedUnimplementedIpamServer() {}
 
 // UnsafeIpamServer may be embedded to opt out of forward compatibility for this service.
@@ -192,6 +282,114 @@ func _Ipam_BelongsToPodCIDR_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Ipam_ListPools_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPoolsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IpamServer).ListPools(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ipam_ListPools_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IpamServer).ListPools(ctx, req.(*ListPoolsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ipam_ListReservedSubnets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReservedSubnetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IpamServer).ListReservedSubnets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ipam_ListReservedSubnets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IpamServer).ListReservedSubnets(ctx, req.(*ListReservedSubnetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ipam_ListClusterSubnets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListClusterSubnetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IpamServer).ListClusterSubnets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ipam_ListClusterSubnets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IpamServer).ListClusterSubnets(ctx, req.(*ListClusterSubnetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ipam_ListEndpointMappings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEndpointMappingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IpamServer).ListEndpointMappings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ipam_ListEndpointMappings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IpamServer).ListEndpointMappings(ctx, req.(*ListEndpointMappingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ipam_ListNatMappingsConfigured_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNatMappingsConfiguredRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IpamServer).ListNatMappingsConfigured(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ipam_ListNatMappingsConfigured_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IpamServer).ListNatMappingsConfigured(ctx, req.(*ListNatMappingsConfiguredRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ipam_GetIPOwner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIPOwnerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IpamServer).GetIPOwner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Ipam_GetIPOwner_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IpamServer).GetIPOwner(ctx, req.(*GetIPOwnerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Ipam_ServiceDesc is the grpc.ServiceDesc for Ipam service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -215,6 +413,30 @@ var Ipam_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BelongsToPodCIDR",
 			Handler:    _Ipam_BelongsToPodCIDR_Handler,
 		},
+		{
+			MethodName: "ListPools",
+			Handler:    _Ipam_ListPools_Handler,
+		},
+		{
+			MethodName: "ListReservedSubnets",
+			Handler:    _Ipam_ListReservedSubnets_Handler,
+		},
+		{
+			MethodName: "ListClusterSubnets",
+			Handler:    _Ipam_ListClusterSubnets_Handler,
+		},
+		{
+			MethodName: "ListEndpointMappings",
+			Handler:    _Ipam_ListEndpointMappings_Handler,
+		},
+		{
+			MethodName: "ListNatMappingsConfigured",
+			Handler:    _Ipam_ListNatMappingsConfigured_Handler,
+		},
+		{
+			MethodName: "GetIPOwner",
+			Handler:    _Ipam_GetIPOwner_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/liqonet/ipam/ipam.proto",
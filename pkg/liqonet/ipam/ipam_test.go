@@ -318,6 +318,77 @@ var _ = Describe("Ipam", func() {
 			})
 		})
 	})
+	Describe("GetTransitSubnetsPerCluster", func() {
+		BeforeEach(func() {
+			_, _, err := ipam.GetSubnetsPerCluster("11.0.0.0/16", "11.1.0.0/16", clusterID1)
+			Expect(err).To(BeNil())
+		})
+		Context("When the transit networks do not conflict with other networks", func() {
+			It("Should allocate them without mapping", func() {
+				mapped, err := ipam.GetTransitSubnetsPerCluster(map[string]string{clusterID2: "11.2.0.0/16"}, clusterID1)
+				Expect(err).To(BeNil())
+				Expect(mapped).To(Equal(map[string]string{clusterID2: "11.2.0.0/16"}))
+
+				ipamStorage, err := getIpamStorageResource()
+				Expect(err).To(BeNil())
+				Expect(ipamStorage.Spec.ClusterSubnets[clusterID1].TransitSubnets).To(HaveKeyWithValue(
+					clusterID2, liqonetapi.TransitSubnet{PodCIDR: "11.2.0.0/16", MappedPodCIDR: "11.2.0.0/16"}))
+			})
+		})
+		Context("When a transit network conflicts with the network of another cluster", func() {
+			It("Should map it to a new network", func() {
+				mapped, err := ipam.GetTransitSubnetsPerCluster(map[string]string{clusterID2: "11.0.0.0/16"}, clusterID1)
+				Expect(err).To(BeNil())
+				Expect(mapped[clusterID2]).ToNot(HavePrefix("11."))
+				Expect(mapped[clusterID2]).To(HaveSuffix("/16"))
+			})
+		})
+		Context("Invoking it twice", func() {
+			It("Should return the same networks", func() {
+				first, err := ipam.GetTransitSubnetsPerCluster(map[string]string{clusterID2: "11.0.0.0/16"}, clusterID1)
+				Expect(err).To(BeNil())
+				second, err := ipam.GetTransitSubnetsPerCluster(map[string]string{clusterID2: "11.0.0.0/16"}, clusterID1)
+				Expect(err).To(BeNil())
+				Expect(second).To(Equal(first))
+			})
+		})
+		Context("When a transit network is no longer announced", func() {
+			It("Should free the corresponding network", func() {
+				_, err := ipam.GetTransitSubnetsPerCluster(map[string]string{clusterID2: "11.2.0.0/16"}, clusterID1)
+				Expect(err).To(BeNil())
+				mapped, err := ipam.GetTransitSubnetsPerCluster(map[string]string{}, clusterID1)
+				Expect(err).To(BeNil())
+				Expect(mapped).To(BeEmpty())
+
+				ipamStorage, err := getIpamStorageResource()
+				Expect(err).To(BeNil())
+				Expect(ipamStorage.Spec.ClusterSubnets[clusterID1].TransitSubnets).To(BeEmpty())
+				Expect(ipamStorage.Spec.Prefixes).ToNot(HaveKey("11.2.0.0/16"))
+			})
+		})
+		Context("Passing an IPv6 transit network", func() {
+			It("Should return an error", func() {
+				_, err := ipam.GetTransitSubnetsPerCluster(map[string]string{clusterID2: "fd00::/64"}, clusterID1)
+				Expect(err).ToNot(BeNil())
+			})
+		})
+		Context("Removing the configuration of the cluster", func() {
+			It("Should free the transit networks", func() {
+				Expect(ipam.SetPodCIDR(homePodCIDR)).To(Succeed())
+				_, err := ipam.GetExternalCIDR(uint8(24))
+				Expect(err).To(BeNil())
+				_, err = ipam.GetTransitSubnetsPerCluster(map[string]string{clusterID2: "11.2.0.0/16"}, clusterID1)
+				Expect(err).To(BeNil())
+				Expect(ipam.RemoveClusterConfig(clusterID1)).To(Succeed())
+
+				ipamStorage, err := getIpamStorageResource()
+				Expect(err).To(BeNil())
+				Expect(ipamStorage.Spec.ClusterSubnets).ToNot(HaveKey(clusterID1))
+				Expect(ipamStorage.Spec.Prefixes).ToNot(HaveKey("11.2.0.0/16"))
+			})
+		})
+	})
+
 	Describe("RemoveClusterConfig", func() {
 		BeforeEach(func() {
 			err := ipam.SetPodCIDR(homePodCIDR)
@@ -1725,6 +1796,302 @@ var _ = Describe("Ipam", func() {
 			})
 		})
 	})
+
+	Describe("Listing the IPAM configuration", func() {
+		var externalCIDR, mappedIP string
+
+		BeforeEach(func() {
+			var err error
+			Expect(ipam.SetPodCIDR(homePodCIDR)).To(Succeed())
+			externalCIDR, err = ipam.GetExternalCIDR(24)
+			Expect(err).ToNot(HaveOccurred())
+			_, _, err = ipam.GetSubnetsPerCluster(remotePodCIDR, remoteExternalCIDR, clusterID1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ipam.AddLocalSubnetsPerCluster(consts.DefaultCIDRValue, consts.DefaultCIDRValue, clusterID1)).To(Succeed())
+
+			response, err := ipam.MapEndpointIP(context.Background(), &MapRequest{ClusterID: clusterID1, Ip: endpointIP})
+			Expect(err).ToNot(HaveOccurred())
+			mappedIP = response.GetIp()
+		})
+
+		Context("ListPools", func() {
+			It("should return the sorted network pools", func() {
+				response, err := ipam.ListPools(context.Background(), &ListPoolsRequest{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response.GetPools()).To(Equal([]string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"}))
+			})
+		})
+
+		Context("ListReservedSubnets", func() {
+			It("should return the reserved subnets", func() {
+				Expect(ipam.SetReservedSubnets([]string{"10.1.0.0/16"})).To(Succeed())
+				response, err := ipam.ListReservedSubnets(context.Background(), &ListReservedSubnetsRequest{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response.GetReservedSubnets()).To(ConsistOf("10.1.0.0/16"))
+			})
+		})
+
+		Context("ListClusterSubnets", func() {
+			It("should return the subnets of all the clusters", func() {
+				_, _, err := ipam.GetSubnetsPerCluster("10.70.0.0/16", "10.80.0.0/16", clusterID2)
+				Expect(err).ToNot(HaveOccurred())
+
+				response, err := ipam.ListClusterSubnets(context.Background(), &ListClusterSubnetsRequest{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response.GetClusterSubnets()).To(HaveLen(2))
+				Expect(response.GetClusterSubnets()[0].GetClusterID()).To(Equal(clusterID1))
+				Expect(response.GetClusterSubnets()[0].GetRemotePodCIDR()).To(Equal(remotePodCIDR))
+				Expect(response.GetClusterSubnets()[0].GetRemoteExternalCIDR()).To(Equal(remoteExternalCIDR))
+				Expect(response.GetClusterSubnets()[0].GetLocalNATPodCIDR()).To(Equal(consts.DefaultCIDRValue))
+				Expect(response.GetClusterSubnets()[1].GetClusterID()).To(Equal(clusterID2))
+			})
+
+			It("should return only the subnets of the requested cluster", func() {
+				response, err := ipam.ListClusterSubnets(context.Background(), &ListClusterSubnetsRequest{ClusterID: clusterID1})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response.GetClusterSubnets()).To(HaveLen(1))
+				Expect(response.GetClusterSubnets()[0].GetClusterID()).To(Equal(clusterID1))
+			})
+
+			It("should fail if the requested cluster is not configured", func() {
+				_, err := ipam.ListClusterSubnets(context.Background(), &ListClusterSubnetsRequest{ClusterID: clusterID3})
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("ListEndpointMappings", func() {
+			It("should return the endpoint mappings", func() {
+				response, err := ipam.ListEndpointMappings(context.Background(), &ListEndpointMappingsRequest{})
+				Expect(err).ToNot(HaveOccurred())
+				var mapping *EndpointMapping
+				for _, m := range response.GetEndpointMappings() {
+					if m.GetIp() == endpointIP {
+						mapping = m
+					}
+				}
+				Expect(mapping).ToNot(BeNil())
+				Expect(mapping.GetExternalCIDROriginalIP()).To(Equal(mappedIP))
+				Expect(mapping.GetClusterMappings()).To(HaveKeyWithValue(clusterID1, mappedIP))
+			})
+
+			It("should filter the endpoint mappings by cluster", func() {
+				response, err := ipam.ListEndpointMappings(context.Background(), &ListEndpointMappingsRequest{ClusterID: clusterID2})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response.GetEndpointMappings()).To(BeEmpty())
+			})
+		})
+
+		Context("ListNatMappingsConfigured", func() {
+			It("should return the clusters whose NAT mappings are configured", func() {
+				response, err := ipam.ListNatMappingsConfigured(context.Background(), &ListNatMappingsConfiguredRequest{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response.GetClusterIDs()).To(ConsistOf(clusterID1))
+			})
+		})
+
+		Context("GetIPOwner", func() {
+			It("should return the local pod CIDR and the containing pool", func() {
+				response, err := ipam.GetIPOwner(context.Background(), &GetIPOwnerRequest{Ip: localEndpointIP})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response.GetOwners()).To(HaveLen(2))
+				Expect(response.GetOwners()[0].GetKind()).To(Equal(IPOwner_POD_CIDR))
+				Expect(response.GetOwners()[0].GetNetwork()).To(Equal(homePodCIDR))
+				Expect(response.GetOwners()[1].GetKind()).To(Equal(IPOwner_NETWORK_POOL))
+				Expect(response.GetOwners()[1].GetNetwork()).To(Equal("10.0.0.0/8"))
+			})
+
+			It("should return the remote cluster owning the IP", func() {
+				response, err := ipam.GetIPOwner(context.Background(), &GetIPOwnerRequest{Ip: "10.50.0.1"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response.GetOwners()[0].GetKind()).To(Equal(IPOwner_REMOTE_POD_CIDR))
+				Expect(response.GetOwners()[0].GetClusterID()).To(Equal(clusterID1))
+			})
+
+			It("should return the endpoint an IP of the ExternalCIDR is assigned to", func() {
+				response, err := ipam.GetIPOwner(context.Background(), &GetIPOwnerRequest{Ip: mappedIP})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response.GetOwners()[0].GetKind()).To(Equal(IPOwner_EXTERNAL_CIDR))
+				Expect(response.GetOwners()[0].GetNetwork()).To(Equal(externalCIDR))
+				Expect(response.GetOwners()).To(ContainElements(
+					WithTransform(func(o *IPOwner) string { return o.GetKind().String() + "/" + o.GetEndpointIP() },
+						Equal(IPOwner_ENDPOINT_EXTERNAL_IP.String()+"/"+endpointIP)),
+					WithTransform(func(o *IPOwner) string { return o.GetKind().String() + "/" + o.GetClusterID() },
+						Equal(IPOwner_ENDPOINT_NATTED_IP.String()+"/"+clusterID1)),
+				))
+			})
+
+			It("should return the mapped endpoint", func() {
+				response, err := ipam.GetIPOwner(context.Background(), &GetIPOwnerRequest{Ip: endpointIP})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response.GetOwners()).To(HaveLen(1))
+				Expect(response.GetOwners()[0].GetKind()).To(Equal(IPOwner_ENDPOINT))
+			})
+
+			It("should fail on an invalid IP", func() {
+				_, err := ipam.GetIPOwner(context.Background(), &GetIPOwnerRequest{Ip: invalidValue})
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("Dual-stack clusters", func() {
+		var ipamDS *IPAM
+
+		BeforeEach(func() {
+			// Initialize a new IPAM on the existing configuration, as it happens
+			// when the IPv6 pools are enabled on a single-stack installation.
+			ipamDS = NewIPAM()
+			Expect(ipamDS.Init(append(Pools, PoolsV6...), dynClient, 0)).To(Succeed())
+		})
+
+		Context("Init", func() {
+			It("should add the IPv6 pools to the existing ones", func() {
+				Expect(ipamDS.ipamStorage.getPools()).To(ConsistOf(append(Pools, PoolsV6...)))
+			})
+		})
+
+		Context("GetSubnetsPerCluster", func() {
+			It("should allocate IPv6 subnets without mapping if there are no conflicts", func() {
+				p, e, err := ipamDS.GetSubnetsPerCluster("fd00:10::/64", "fd00:11::/64", clusterID1)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(p).To(Equal("fd00:10::/64"))
+				Expect(e).To(Equal("fd00:11::/64"))
+
+				subnets := ipamDS.ipamStorage.getClusterSubnets()[clusterID1]
+				Expect(subnets.RemotePodCIDRv6).To(Equal("fd00:10::/64"))
+				Expect(subnets.RemoteExternalCIDRv6).To(Equal("fd00:11::/64"))
+				Expect(subnets.RemotePodCIDR).To(BeEmpty())
+			})
+			It("should map the IPv6 subnets to IPv6 networks in case of conflicts", func() {
+				_, _, err := ipamDS.GetSubnetsPerCluster("fd00:10::/64", "fd00:11::/64", clusterID1)
+				Expect(err).ToNot(HaveOccurred())
+				p, e, err := ipamDS.GetSubnetsPerCluster("fd00:10::/64", "fd00:11::/64", clusterID2)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(p).ToNot(Equal("fd00:10::/64"))
+				Expect(e).ToNot(Equal("fd00:11::/64"))
+				Expect(liqonetutils.IsIPv6CIDR(p)).To(BeTrue())
+				Expect(liqonetutils.IsIPv6CIDR(e)).To(BeTrue())
+				Expect(p).To(HaveSuffix("/64"))
+				Expect(e).To(HaveSuffix("/64"))
+			})
+			It("should keep the subnets of both families for the same cluster", func() {
+				_, _, err := ipamDS.GetSubnetsPerCluster(remotePodCIDR, remoteExternalCIDR, clusterID1)
+				Expect(err).ToNot(HaveOccurred())
+				_, _, err = ipamDS.GetSubnetsPerCluster("fd00:10::/64", "fd00:11::/64", clusterID1)
+				Expect(err).ToNot(HaveOccurred())
+
+				subnets := ipamDS.ipamStorage.getClusterSubnets()[clusterID1]
+				Expect(subnets.RemotePodCIDR).To(Equal(remotePodCIDR))
+				Expect(subnets.RemoteExternalCIDR).To(Equal(remoteExternalCIDR))
+				Expect(subnets.RemotePodCIDRv6).To(Equal("fd00:10::/64"))
+				Expect(subnets.RemoteExternalCIDRv6).To(Equal("fd00:11::/64"))
+			})
+			It("should return an error if the networks belong to different families", func() {
+				_, _, err := ipamDS.GetSubnetsPerCluster("fd00:10::/64", remoteExternalCIDR, clusterID1)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("GetExternalCIDRv6", func() {
+			It("should return the same IPv6 network when invoked twice", func() {
+				e, err := ipamDS.GetExternalCIDRv6(64)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(liqonetutils.IsIPv6CIDR(e)).To(BeTrue())
+				Expect(e).To(HaveSuffix("/64"))
+				e2, err := ipamDS.GetExternalCIDRv6(64)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(e2).To(Equal(e))
+			})
+		})
+
+		Context("RemoveClusterConfig", func() {
+			It("should free the IPv6 networks of the cluster", func() {
+				_, err := ipamDS.GetExternalCIDR(24)
+				Expect(err).ToNot(HaveOccurred())
+				_, _, err = ipamDS.GetSubnetsPerCluster("fd00:10::/64", "fd00:11::/64", clusterID1)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(ipamDS.RemoveClusterConfig(clusterID1)).To(Succeed())
+				p, e, err := ipamDS.GetSubnetsPerCluster("fd00:10::/64", "fd00:11::/64", clusterID2)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(p).To(Equal("fd00:10::/64"))
+				Expect(e).To(Equal("fd00:11::/64"))
+			})
+		})
+
+		Context("Endpoint mappings", func() {
+			const homePodCIDRv6 = "fd00:1::/64"
+			var externalCIDRv6 string
+
+			BeforeEach(func() {
+				var err error
+				Expect(ipamDS.SetPodCIDR(homePodCIDR)).To(Succeed())
+				Expect(ipamDS.SetPodCIDR(homePodCIDRv6)).To(Succeed())
+				_, err = ipamDS.GetExternalCIDR(24)
+				Expect(err).ToNot(HaveOccurred())
+				externalCIDRv6, err = ipamDS.GetExternalCIDRv6(64)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, _, err = ipamDS.GetSubnetsPerCluster(remotePodCIDR, remoteExternalCIDR, clusterID1)
+				Expect(err).ToNot(HaveOccurred())
+				_, _, err = ipamDS.GetSubnetsPerCluster("fd00:10::/64", "fd00:11::/64", clusterID1)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should require the IPv4 NAT mappings to be initialized first", func() {
+				err := ipamDS.AddLocalIPv6SubnetsPerCluster(consts.DefaultCIDRValue, consts.DefaultCIDRValue, clusterID1)
+				Expect(err).To(HaveOccurred())
+			})
+
+			When("the local IPv6 subnets have been added", func() {
+				BeforeEach(func() {
+					Expect(ipamDS.AddLocalSubnetsPerCluster(consts.DefaultCIDRValue, consts.DefaultCIDRValue, clusterID1)).To(Succeed())
+					Expect(ipamDS.AddLocalIPv6SubnetsPerCluster("fd00:20::/64", consts.DefaultCIDRValue, clusterID1)).To(Succeed())
+				})
+
+				It("should set the IPv6 networks in the NatMapping resource", func() {
+					nm, err := getNatMappingResourcePerCluster(clusterID1)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(nm.Spec.PodCIDR).To(Equal(remotePodCIDR))
+					Expect(nm.Spec.PodCIDRv6).To(Equal("fd00:10::/64"))
+					Expect(nm.Spec.ExternalCIDRv6).To(Equal(externalCIDRv6))
+				})
+				It("should map a local IPv6 pod IP using the remapped IPv6 PodCIDR", func() {
+					response, err := ipamDS.MapEndpointIP(context.Background(), &MapRequest{ClusterID: clusterID1, Ip: "fd00:1::5"})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(response.GetIp()).To(Equal("fd00:20::5"))
+				})
+				It("should map an external IPv6 endpoint to the IPv6 ExternalCIDR, and free it when unmapped", func() {
+					response, err := ipamDS.MapEndpointIP(context.Background(), &MapRequest{ClusterID: clusterID1, Ip: "fd00:99::1"})
+					Expect(err).ToNot(HaveOccurred())
+					belongs, err := ipBelongsToNetwork(response.GetIp(), externalCIDRv6)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(belongs).To(BeTrue())
+
+					nm, err := getNatMappingResourcePerCluster(clusterID1)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(nm.Spec.ClusterMappings).To(HaveKeyWithValue("fd00:99::1", response.GetIp()))
+
+					_, err = ipamDS.UnmapEndpointIP(context.Background(), &UnmapRequest{ClusterID: clusterID1, Ip: "fd00:99::1"})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(ipamDS.ipamStorage.getEndpointMappings()).ToNot(HaveKey("fd00:99::1"))
+				})
+				It("should keep mapping IPv4 endpoints as before", func() {
+					response, err := ipamDS.MapEndpointIP(context.Background(), &MapRequest{ClusterID: clusterID1, Ip: localEndpointIP})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(response.GetIp()).To(Equal(localEndpointIP))
+				})
+				It("should return the home IPv6 pod IP and tell if it belongs to the IPv6 PodCIDR", func() {
+					home, err := ipamDS.GetHomePodIP(context.Background(), &GetHomePodIPRequest{ClusterID: clusterID1, Ip: "fd00:10::7"})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(home.GetHomeIP()).To(Equal("fd00:10::7"))
+
+					belongs, err := ipamDS.BelongsToPodCIDR(context.Background(), &BelongsRequest{Ip: "fd00:1::7"})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(belongs.GetBelongs()).To(BeTrue())
+				})
+			})
+		})
+	})
 })
 
 func checkForPrefixes(subnets []string) {
@@ -17,7 +17,9 @@ package iptables
 import (
 	"encoding/csv"
 	"fmt"
-	"os"
+	"net"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/coreos/go-iptables/iptables"
@@ -26,6 +28,7 @@ import (
 	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
 	"github.com/liqotech/liqo/pkg/consts"
 	"github.com/liqotech/liqo/pkg/liqonet/errors"
+	"github.com/liqotech/liqo/pkg/liqonet/firewall"
 	liqonetutils "github.com/liqotech/liqo/pkg/liqonet/utils"
 	"github.com/liqotech/liqo/pkg/utils/slice"
 )
@@ -45,6 +48,12 @@ const (
 	liqonetForwardingExtClusterChainPrefix = "LIQO-FRWD-EXT-CLS-"
 	// liqonetPreRoutingMappingClusterChainPrefix prefix used to name the prerouting mapping chain for a specific cluster.
 	liqonetPreRoutingMappingClusterChainPrefix = "LIQO-PRRT-MAP-CLS-"
+	// liqonetPolicyAllowClusterChainPrefix prefix used to name the chain accepting the traffic
+	// allowed by the inter-cluster network policies towards a specific cluster.
+	liqonetPolicyAllowClusterChainPrefix = "LIQO-POL-ALW-CLS-"
+	// liqonetPolicyDropClusterChainPrefix prefix used to name the chain dropping the traffic of the pods
+	// isolated by the inter-cluster network policies towards a specific cluster.
+	liqonetPolicyDropClusterChainPrefix = "LIQO-POL-DRP-CLS-"
 	// natTable constant used for the "nat" table.
 	natTable = "nat"
 	// filterTable constant used for the "filter" table.
@@ -90,28 +99,30 @@ func ParseRule(rule string) (IPTableRule, error) {
 }
 
 // IPTHandler a handler that exposes all the functions needed to configure the iptables chains and rules.
+// Chains and rules are configured through the firewall backend selected at startup (e.g., iptables or nftables).
 type IPTHandler struct {
-	Ipt iptables.IPTables
+	Ipt firewall.Firewall
 }
 
 // NewIPTHandler return the iptables handler used to configure the iptables rules.
 func NewIPTHandler() (IPTHandler, error) {
-	selectedmode := os.Getenv("IPTABLES_MODE")
-	var ipt *iptables.IPTables
-	var err error
-	if iptables.ModeType(selectedmode) == iptables.ModeTypeNFTables || iptables.ModeType(selectedmode) == iptables.ModeTypeLegacy {
-		ipt, err = iptables.New(iptables.Mode(iptables.ModeType(selectedmode)))
-	} else {
-		ipt, err = iptables.New()
-	}
+	return newIPTHandler(iptables.ProtocolIPv4)
+}
+
+// NewIPv6IPTHandler return the ip6tables handler used to configure the rules for
+// the IPv6 networks, in case of dual-stack clusters.
+func NewIPv6IPTHandler() (IPTHandler, error) {
+	return newIPTHandler(iptables.ProtocolIPv6)
+}
+
+func newIPTHandler(proto iptables.Protocol) (IPTHandler, error) {
+	fw, err := firewall.New(proto)
 	if err != nil {
 		return IPTHandler{}, err
 	}
-	v1, v2, v3, mode := ipt.GetIptablesVersion()
-	klog.Infof("Iptables version: %d.%d.%d, mode: %s", v1, v2, v3, mode)
 	return IPTHandler{
-		Ipt: *ipt,
-	}, err
+		Ipt: fw,
+	}, nil
 }
 
 // Init function is called at startup of the operator.
@@ -225,6 +236,12 @@ func (h IPTHandler) deleteLiqoChainsFromTable(liqoChains map[string]string, tabl
 		chainsToBeRemoved = append(chainsToBeRemoved,
 			getSliceContainingString(existingChains, liqonetForwardingExtClusterChainPrefix)...,
 		)
+		chainsToBeRemoved = append(chainsToBeRemoved,
+			getSliceContainingString(existingChains, liqonetPolicyAllowClusterChainPrefix)...,
+		)
+		chainsToBeRemoved = append(chainsToBeRemoved,
+			getSliceContainingString(existingChains, liqonetPolicyDropClusterChainPrefix)...,
+		)
 	}
 	// Delete chains in table
 	if err := h.deleteChainsInTable(table, existingChains, chainsToBeRemoved); err != nil {
@@ -325,6 +342,8 @@ func getChainsPerCluster(clusterID string) []string {
 		getClusterPostRoutingChain(clusterID),
 		getClusterPreRoutingChain(clusterID),
 		getClusterPreRoutingMappingChain(clusterID),
+		getClusterPolicyAllowChain(clusterID),
+		getClusterPolicyDropChain(clusterID),
 	}
 	return chains
 }
@@ -352,7 +371,9 @@ func (h IPTHandler) EnsureChainsPerCluster(clusterID string) error {
 // the table name the chain should belong to.
 func getTableFromChain(chain string) string {
 	// First manage the case the chain is a cluster chain
-	if strings.Contains(chain, liqonetForwardingExtClusterChainPrefix) {
+	if strings.Contains(chain, liqonetForwardingExtClusterChainPrefix) ||
+		strings.Contains(chain, liqonetPolicyAllowClusterChainPrefix) ||
+		strings.Contains(chain, liqonetPolicyDropClusterChainPrefix) {
 		return filterTable
 	}
 	if strings.Contains(chain, liqonetPostroutingClusterChainPrefix) ||
@@ -490,7 +511,16 @@ func (h IPTHandler) EnsurePostroutingRules(tep *netv1alpha1.TunnelEndpoint) erro
 	if err != nil {
 		return err
 	}
-	return h.updateRulesPerChain(getClusterPostRoutingChain(tep.Spec.ClusterIdentity.ClusterID), rules)
+	chain := getClusterPostRoutingChain(tep.Spec.ClusterIdentity.ClusterID)
+	// The rules concerning the traffic in transit towards the remote cluster are inserted in first position,
+	// since they must take precedence over the ones masquerading the traffic not originated by local pods.
+	transitRules := getTransitPostroutingRules(tep)
+	for _, rule := range transitRules {
+		if err := h.insertLiqoRuleIfNotExists(chain, rule); err != nil {
+			return err
+		}
+	}
+	return h.updateRulesPerChain(chain, append(transitRules, rules...))
 }
 
 // EnsurePreroutingRulesPerTunnelEndpoint makes sure that the prerouting rules extracted from a
@@ -504,16 +534,147 @@ func (h IPTHandler) EnsurePreroutingRulesPerTunnelEndpoint(tep *netv1alpha1.Tunn
 }
 
 // EnsurePreroutingRulesPerNatMapping makes sure that the prerouting rules extracted from a
-// NatMapping resource are place and updated.
+// NatMapping resource are place and updated. Only the mappings of the handler IP family are considered.
 func (h IPTHandler) EnsurePreroutingRulesPerNatMapping(nm *netv1alpha1.NatMapping) error {
 	clusterID := nm.Spec.ClusterID
-	rules, err := getPreRoutingRulesPerNatMapping(nm)
+	rules, err := getPreRoutingRulesPerNatMapping(nm, h.isIPv6())
 	if err != nil {
 		return err
 	}
 	return h.updateRulesPerChain(getClusterPreRoutingMappingChain(clusterID), rules)
 }
 
+// PolicyTarget associates an inter-cluster network policy with the IPs of the local pods it selects.
+type PolicyTarget struct {
+	Policy *netv1alpha1.InterClusterNetworkPolicy
+	PodIPs []string
+}
+
+// EnsurePolicyRulesPerCluster makes sure that the filtering rules enforcing the given inter-cluster network policies
+// towards a given cluster are in place and updated. Only the addresses of the handler IP family are considered.
+func (h IPTHandler) EnsurePolicyRulesPerCluster(tep *netv1alpha1.TunnelEndpoint, targets []PolicyTarget) error {
+	allowRules, dropRules, err := getPolicyRules(tep, targets, h.isIPv6())
+	if err != nil {
+		return err
+	}
+	clusterID := tep.Spec.ClusterIdentity.ClusterID
+	if err := h.updateRulesPerChain(getClusterPolicyAllowChain(clusterID), allowRules); err != nil {
+		return err
+	}
+	return h.updateRulesPerChain(getClusterPolicyDropChain(clusterID), dropRules)
+}
+
+// getPolicyRules compiles the given inter-cluster network policies into the rules of the chains accepting
+// the allowed traffic towards the remote cluster and dropping the rest of the traffic of the isolated pods.
+// Rules are generated in the same format returned by ListRulesInChain, to allow detecting the outdated ones.
+func getPolicyRules(tep *netv1alpha1.TunnelEndpoint, targets []PolicyTarget, ipv6 bool) (allowRules, dropRules []IPTableRule, err error) {
+	if err := liqonetutils.CheckTep(tep); err != nil {
+		return nil, nil, fmt.Errorf("invalid TunnelEndpoint resource: %w", err)
+	}
+	clusterID := tep.Spec.ClusterIdentity.ClusterID
+	_, remotePodCIDR := liqonetutils.GetPodCIDRS(tep)
+	_, remoteExternalCIDR := liqonetutils.GetExternalCIDRS(tep)
+
+	// The clusters reachable through the remote one, acting as a transit cluster, are reached through the same chains.
+	defaultCIDRs := map[string][]string{clusterID: {remotePodCIDR, remoteExternalCIDR}}
+	for i := range tep.Spec.RemoteTransitNetworks {
+		tn := &tep.Spec.RemoteTransitNetworks[i]
+		defaultCIDRs[tn.ClusterIdentity.ClusterID] = []string{liqonetutils.GetTransitPodCIDR(tn)}
+	}
+
+	hostMask := "/32"
+	if ipv6 {
+		hostMask = "/128"
+	}
+
+	allowed := make(map[string]IPTableRule)
+	isolated := make(map[string]IPTableRule)
+	for i := range targets {
+		sources := make([]string, 0, len(targets[i].PodIPs))
+		for _, podIP := range targets[i].PodIPs {
+			ip := net.ParseIP(podIP)
+			if ip == nil || liqonetutils.IsIPv6(podIP) != ipv6 {
+				continue
+			}
+			sources = append(sources, ip.String())
+			isolated[ip.String()] = IPTableRule{"-s", ip.String(), "-j", DROP}
+		}
+
+		for _, egress := range targets[i].Policy.Spec.Egress {
+			clusterCIDRs, found := defaultCIDRs[egress.ClusterID]
+			if !found {
+				continue
+			}
+
+			cidrs := egress.CIDRs
+			if len(cidrs) == 0 {
+				cidrs = clusterCIDRs
+			}
+			destinations := make([]string, 0, len(cidrs))
+			for _, cidr := range cidrs {
+				_, network, err := net.ParseCIDR(cidr)
+				if err != nil {
+					return nil, nil, fmt.Errorf("invalid CIDR %q in policy %s/%s: %w",
+						cidr, targets[i].Policy.Namespace, targets[i].Policy.Name, err)
+				}
+				if liqonetutils.IsIPv6CIDR(network.String()) != ipv6 {
+					continue
+				}
+				destinations = append(destinations, strings.TrimSuffix(network.String(), hostMask))
+			}
+
+			for _, source := range sources {
+				for _, destination := range destinations {
+					for _, rule := range getPolicyPortMatches(egress.Ports) {
+						rule = append(IPTableRule{"-s", source, "-d", destination}, rule...)
+						rule = append(rule, "-j", ACCEPT)
+						allowed[rule.String()] = rule
+					}
+				}
+			}
+		}
+	}
+
+	// The replies to the connections initiated by the remote cluster are always allowed.
+	allowRules = []IPTableRule{{"-m", "conntrack", "--ctstate", "RELATED,ESTABLISHED", "-j", ACCEPT}}
+	allowRules = append(allowRules, sortedRules(allowed)...)
+	return allowRules, sortedRules(isolated), nil
+}
+
+// getPolicyPortMatches returns the matches corresponding to the given policy ports, or a single empty match if no port is specified.
+func getPolicyPortMatches(ports []netv1alpha1.InterClusterPolicyPort) []IPTableRule {
+	if len(ports) == 0 {
+		return []IPTableRule{{}}
+	}
+	matches := make([]IPTableRule, 0, len(ports))
+	for _, port := range ports {
+		protocol := strings.ToLower(string(port.Protocol))
+		if protocol == "" {
+			protocol = "tcp"
+		}
+		match := IPTableRule{"-p", protocol}
+		if port.Port != 0 {
+			match = append(match, "-m", protocol, "--dport", strconv.Itoa(int(port.Port)))
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+// sortedRules returns the rules in the given map, sorted by their string representation.
+func sortedRules(rules map[string]IPTableRule) []IPTableRule {
+	keys := make([]string, 0, len(rules))
+	for key := range rules {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	sorted := make([]IPTableRule, 0, len(keys))
+	for _, key := range keys {
+		sorted = append(sorted, rules[key])
+	}
+	return sorted
+}
+
 func getPreRoutingRulesPerTunnelEndpoint(tep *netv1alpha1.TunnelEndpoint) ([]IPTableRule, error) {
 	// Check tep fields
 	if err := liqonetutils.CheckTep(tep); err != nil {
@@ -523,6 +684,14 @@ func getPreRoutingRulesPerTunnelEndpoint(tep *netv1alpha1.TunnelEndpoint) ([]IPT
 	localRemappedPodCIDR, remotePodCIDR := liqonetutils.GetPodCIDRS(tep)
 
 	rules := make([]IPTableRule, 0)
+	// The traffic in transit from the remote cluster is translated back to the network used in the local cluster
+	// for the destination cluster, in case it has been remapped by the remote cluster.
+	for i := range tep.Spec.LocalTransitNetworks {
+		tn := &tep.Spec.LocalTransitNetworks[i]
+		if natPodCIDR := liqonetutils.GetTransitPodCIDR(tn); natPodCIDR != tn.PodCIDR {
+			rules = append(rules, IPTableRule{"-s", remotePodCIDR, "-d", natPodCIDR, "-j", NETMAP, "--to", tn.PodCIDR})
+		}
+	}
 	if localRemappedPodCIDR == consts.DefaultCIDRValue {
 		// Remote cluster has not remapped home PodCIDR,
 		// this means there is no need to NAT
@@ -532,10 +701,17 @@ func getPreRoutingRulesPerTunnelEndpoint(tep *netv1alpha1.TunnelEndpoint) ([]IPT
 	rules = append(rules,
 		IPTableRule{"-s", remotePodCIDR, "-d", localRemappedPodCIDR, "-j", NETMAP, "--to", localPodCIDR},
 	)
+	// The same holds for the traffic originated by the clusters reachable through the remote one.
+	for i := range tep.Spec.RemoteTransitNetworks {
+		transitPodCIDR := liqonetutils.GetTransitPodCIDR(&tep.Spec.RemoteTransitNetworks[i])
+		rules = append(rules,
+			IPTableRule{"-s", transitPodCIDR, "-d", localRemappedPodCIDR, "-j", NETMAP, "--to", localPodCIDR},
+		)
+	}
 	return rules, nil
 }
 
-func getPreRoutingRulesPerNatMapping(nm *netv1alpha1.NatMapping) ([]IPTableRule, error) {
+func getPreRoutingRulesPerNatMapping(nm *netv1alpha1.NatMapping, ipv6 bool) ([]IPTableRule, error) {
 	// Check tep fields
 	if nm.Spec.ClusterID == "" {
 		return nil, &errors.WrongParameter{
@@ -547,6 +723,10 @@ func getPreRoutingRulesPerNatMapping(nm *netv1alpha1.NatMapping) ([]IPTableRule,
 	rules := make([]IPTableRule, 0, len(nm.Spec.ClusterMappings))
 
 	for oldIP, newIP := range nm.Spec.ClusterMappings {
+		// IPv4 and IPv6 mappings are enforced by different handlers.
+		if liqonetutils.IsIPv6(newIP) != ipv6 {
+			continue
+		}
 		rules = append(rules,
 			IPTableRule{"-d", newIP, "-j", DNAT, "--to-destination", oldIP},
 		)
@@ -571,11 +751,15 @@ func (h IPTHandler) ListRulesInChain(chain string) ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
+	hostMask := "/32"
+	if h.isIPv6() {
+		hostMask = "/128"
+	}
 	rules := make([]string, 0)
 	ruleToRemove := "-N " + chain
 	for _, rule := range existingRules {
 		if rule != ruleToRemove {
-			rule = strings.ReplaceAll(rule, "/32", "")
+			rule = strings.ReplaceAll(rule, hostMask, "")
 			tmp := strings.Split(rule, " ")
 			rules = append(rules, strings.Join(tmp[2:], " "))
 		}
@@ -583,6 +767,11 @@ func (h IPTHandler) ListRulesInChain(chain string) ([]string, error) {
 	return rules, nil
 }
 
+// isIPv6 returns whether the handler configures the ip6tables rules.
+func (h IPTHandler) isIPv6() bool {
+	return h.Ipt.Proto() == iptables.ProtocolIPv6
+}
+
 func (h IPTHandler) createIptablesChainIfNotExists(table, newChain string) error {
 	// get existing chains
 	chainsList, err := h.Ipt.ListChains(table)
@@ -729,6 +918,11 @@ func getPostroutingRules(tep *netv1alpha1.TunnelEndpoint) ([]IPTableRule, error)
 	localPodCIDR := tep.Spec.LocalPodCIDR
 	localRemappedPodCIDR, remotePodCIDR := liqonetutils.GetPodCIDRS(tep)
 	_, remoteExternalCIDR := liqonetutils.GetExternalCIDRS(tep)
+	// The networks reachable through the remote cluster, acting as a transit cluster, are handled as the remote PodCIDR.
+	transitPodCIDRs := make([]string, 0, len(tep.Spec.RemoteTransitNetworks))
+	for i := range tep.Spec.RemoteTransitNetworks {
+		transitPodCIDRs = append(transitPodCIDRs, liqonetutils.GetTransitPodCIDR(&tep.Spec.RemoteTransitNetworks[i]))
+	}
 	if localRemappedPodCIDR != consts.DefaultCIDRValue {
 		// Get the first IP address from the podCIDR of the local cluster
 		// in this case it is the podCIDR to which the local podCIDR has bee remapped by the remote peering cluster
@@ -738,12 +932,18 @@ func getPostroutingRules(tep *netv1alpha1.TunnelEndpoint) ([]IPTableRule, error)
 				localRemappedPodCIDR, tep.Spec.ClusterIdentity)
 			return nil, err
 		}
-		return []IPTableRule{
+		rules := []IPTableRule{
 			{"-s", localPodCIDR, "-d", remotePodCIDR, "-j", NETMAP, "--to", localRemappedPodCIDR},
 			{"-s", localPodCIDR, "-d", remoteExternalCIDR, "-j", NETMAP, "--to", localRemappedPodCIDR},
 			{"!", "-s", localPodCIDR, "-d", remotePodCIDR, "-j", SNAT, "--to-source", natIP},
 			{"!", "-s", localPodCIDR, "-d", remoteExternalCIDR, "-j", SNAT, "--to-source", natIP},
-		}, nil
+		}
+		for _, transitPodCIDR := range transitPodCIDRs {
+			rules = append(rules,
+				IPTableRule{"-s", localPodCIDR, "-d", transitPodCIDR, "-j", NETMAP, "--to", localRemappedPodCIDR},
+				IPTableRule{"!", "-s", localPodCIDR, "-d", transitPodCIDR, "-j", SNAT, "--to-source", natIP})
+		}
+		return rules, nil
 	}
 	// Get the first IP address from the podCIDR of the local cluster
 	natIP, err := liqonetutils.GetFirstIP(localPodCIDR)
@@ -752,10 +952,31 @@ func getPostroutingRules(tep *netv1alpha1.TunnelEndpoint) ([]IPTableRule, error)
 			tep.Spec.RemotePodCIDR, tep.Spec.ClusterIdentity)
 		return nil, err
 	}
-	return []IPTableRule{
+	rules := []IPTableRule{
 		{"!", "-s", localPodCIDR, "-d", remotePodCIDR, "-j", SNAT, "--to-source", natIP},
 		{"!", "-s", localPodCIDR, "-d", remoteExternalCIDR, "-j", SNAT, "--to-source", natIP},
-	}, nil
+	}
+	for _, transitPodCIDR := range transitPodCIDRs {
+		rules = append(rules, IPTableRule{"!", "-s", localPodCIDR, "-d", transitPodCIDR, "-j", SNAT, "--to-source", natIP})
+	}
+	return rules, nil
+}
+
+// getTransitPostroutingRules returns the postrouting rules for the traffic in transit towards the remote cluster, which is
+// originated by the other clusters reachable through the local one. Its source is translated to the network used in the
+// remote cluster for the origin cluster, in case it has been remapped, and it is not masqueraded otherwise.
+func getTransitPostroutingRules(tep *netv1alpha1.TunnelEndpoint) []IPTableRule {
+	_, remotePodCIDR := liqonetutils.GetPodCIDRS(tep)
+	rules := make([]IPTableRule, 0, len(tep.Spec.LocalTransitNetworks))
+	for i := range tep.Spec.LocalTransitNetworks {
+		tn := &tep.Spec.LocalTransitNetworks[i]
+		if natPodCIDR := liqonetutils.GetTransitPodCIDR(tn); natPodCIDR != tn.PodCIDR {
+			rules = append(rules, IPTableRule{"-s", tn.PodCIDR, "-d", remotePodCIDR, "-j", NETMAP, "--to", natPodCIDR})
+			continue
+		}
+		rules = append(rules, IPTableRule{"-s", tn.PodCIDR, "-d", remotePodCIDR, "-j", ACCEPT})
+	}
+	return rules
 }
 
 // Function that returns the set of rules used in Liqo chains (e.g. LIQO-PREROUTING)
@@ -793,6 +1014,14 @@ func getChainRulesPerCluster(tep *netv1alpha1.TunnelEndpoint) (map[string][]IPTa
 			"-d", localRemappedExternalCIDR,
 			"-j", getClusterForwardExtChain(clusterID)})
 
+	// The traffic towards the remote cluster is first matched against the rules allowed by the
+	// inter-cluster network policies, and then dropped in case the source pod is isolated.
+	chainRules[liqonetForwardingChain] = append(chainRules[liqonetForwardingChain],
+		IPTableRule{"-d", remotePodCIDR, "-j", getClusterPolicyAllowChain(clusterID)},
+		IPTableRule{"-d", remoteExternalCIDR, "-j", getClusterPolicyAllowChain(clusterID)},
+		IPTableRule{"-d", remotePodCIDR, "-j", getClusterPolicyDropChain(clusterID)},
+		IPTableRule{"-d", remoteExternalCIDR, "-j", getClusterPolicyDropChain(clusterID)})
+
 	chainRules[liqonetPreroutingChain] = append(chainRules[liqonetPreroutingChain],
 		IPTableRule{
 			"-s", remotePodCIDR,
@@ -811,6 +1040,43 @@ func getChainRulesPerCluster(tep *netv1alpha1.TunnelEndpoint) (map[string][]IPTa
 				"-m", "comment", "--comment", getClusterPreRoutingChainComment(tep.Spec.ClusterIdentity.ClusterName, consts.PodCIDR),
 				"-j", getClusterPreRoutingChain(clusterID)})
 	}
+
+	// The traffic towards the networks reachable through the remote cluster, acting as a transit cluster,
+	// traverses the same chains of the one towards the remote cluster itself.
+	for i := range tep.Spec.RemoteTransitNetworks {
+		tn := &tep.Spec.RemoteTransitNetworks[i]
+		transitPodCIDR := liqonetutils.GetTransitPodCIDR(tn)
+		chainRules[liqonetPostroutingChain] = append(chainRules[liqonetPostroutingChain],
+			IPTableRule{
+				"-d", transitPodCIDR,
+				"-m", "comment", "--comment", getClusterTransitChainComment("SNAT local traffic for", tep.Spec.ClusterIdentity.ClusterName, tn),
+				"-j", getClusterPostRoutingChain(clusterID)})
+		chainRules[liqonetForwardingChain] = append(chainRules[liqonetForwardingChain],
+			IPTableRule{"-d", transitPodCIDR, "-j", getClusterPolicyAllowChain(clusterID)},
+			IPTableRule{"-d", transitPodCIDR, "-j", getClusterPolicyDropChain(clusterID)})
+		if localRemappedPodCIDR != consts.DefaultCIDRValue {
+			chainRules[liqonetPreroutingChain] = append(chainRules[liqonetPreroutingChain],
+				IPTableRule{
+					"-s", transitPodCIDR,
+					"-d", localRemappedPodCIDR,
+					"-m", "comment", "--comment", getClusterTransitChainComment("DNAT traffic from", tep.Spec.ClusterIdentity.ClusterName, tn),
+					"-j", getClusterPreRoutingChain(clusterID)})
+		}
+	}
+
+	// The traffic in transit from the remote cluster towards a network remapped by the remote cluster
+	// is translated back in the prerouting chain of the remote cluster.
+	for i := range tep.Spec.LocalTransitNetworks {
+		tn := &tep.Spec.LocalTransitNetworks[i]
+		if natPodCIDR := liqonetutils.GetTransitPodCIDR(tn); natPodCIDR != tn.PodCIDR {
+			chainRules[liqonetPreroutingChain] = append(chainRules[liqonetPreroutingChain],
+				IPTableRule{
+					"-s", remotePodCIDR,
+					"-d", natPodCIDR,
+					"-m", "comment", "--comment", getClusterTransitChainComment("DNAT traffic in transit from", tep.Spec.ClusterIdentity.ClusterName, tn),
+					"-j", getClusterPreRoutingChain(clusterID)})
+		}
+	}
 	return chainRules, nil
 }
 
@@ -832,6 +1098,11 @@ func getClusterPostRoutingChainComment(clusterName, typeCIDR string) string {
 	return fmt.Sprintf("SNAT local traffic for '%s' %s", clusterName, typeCIDR)
 }
 
+func getClusterTransitChainComment(action, clusterName string, tn *netv1alpha1.TransitNetworkMapping) string {
+	// WARNING: Never use double-quotes inside the comment, otherwise IpTableRule parser will fail.
+	return fmt.Sprintf("%s '%s' %s of '%s'", action, clusterName, consts.PodCIDR, tn.ClusterIdentity.ClusterName)
+}
+
 func getClusterForwardExtChain(clusterID string) string {
 	return fmt.Sprintf("%s%s", liqonetForwardingExtClusterChainPrefix, strings.Split(clusterID, "-")[0])
 }
@@ -840,6 +1111,14 @@ func getClusterPreRoutingMappingChain(clusterID string) string {
 	return fmt.Sprintf("%s%s", liqonetPreRoutingMappingClusterChainPrefix, strings.Split(clusterID, "-")[0])
 }
 
+func getClusterPolicyAllowChain(clusterID string) string {
+	return fmt.Sprintf("%s%s", liqonetPolicyAllowClusterChainPrefix, strings.Split(clusterID, "-")[0])
+}
+
+func getClusterPolicyDropChain(clusterID string) string {
+	return fmt.Sprintf("%s%s", liqonetPolicyDropClusterChainPrefix, strings.Split(clusterID, "-")[0])
+}
+
 // Function that returns the set of Liqo default chains.
 // Value is the Liqo chain, key is the related default chain.
 // Example: key: PREROUTING, value: LIQO-PREROUTING.
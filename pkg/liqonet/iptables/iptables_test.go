@@ -22,6 +22,8 @@ import (
 	. "github.com/coreos/go-iptables/iptables"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 
 	discv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
@@ -351,7 +353,9 @@ var _ = Describe("iptables", func() {
 
 				// Check if filter chains have been created by function.
 				Expect(filterChains).To(ContainElements(
-					liqonetForwardingExtClusterChainPrefix + strings.Split(clusterID1, "-")[0],
+					liqonetForwardingExtClusterChainPrefix+strings.Split(clusterID1, "-")[0],
+					liqonetPolicyAllowClusterChainPrefix+strings.Split(clusterID1, "-")[0],
+					liqonetPolicyDropClusterChainPrefix+strings.Split(clusterID1, "-")[0],
 				))
 
 				// Check if nat chains have been created by function.
@@ -374,7 +378,9 @@ var _ = Describe("iptables", func() {
 
 				// Check if filter chains have been created by function.
 				Expect(filterChains).To(ContainElements(
-					liqonetForwardingExtClusterChainPrefix + strings.Split(clusterID1, "-")[0],
+					liqonetForwardingExtClusterChainPrefix+strings.Split(clusterID1, "-")[0],
+					liqonetPolicyAllowClusterChainPrefix+strings.Split(clusterID1, "-")[0],
+					liqonetPolicyDropClusterChainPrefix+strings.Split(clusterID1, "-")[0],
 				))
 
 				// Check if nat chains have been created by function.
@@ -1024,6 +1030,194 @@ var _ = Describe("iptables", func() {
 				))
 			})
 		})
+		Context("Call with IPv6 mappings", func() {
+			It("should insert only the rules of the handler IP family", func() {
+				nm.Spec.ClusterMappings = netv1alpha1.Mappings{
+					oldIP1:      newIP1,
+					"fd00:1::2": "fd00:2::2",
+				}
+				err := h.EnsurePreroutingRulesPerNatMapping(nm)
+				Expect(err).ToNot(HaveOccurred())
+
+				// Get inserted rules
+				preRoutingRules, err := h.ListRulesInChain(getClusterPreRoutingMappingChain(clusterID1))
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(preRoutingRules).To(ConsistOf(
+					fmt.Sprintf("-d %s -j %s --to-destination %s", newIP1, DNAT, oldIP1),
+				))
+			})
+		})
+	})
+	Describe("getPreRoutingRulesPerNatMapping", func() {
+		BeforeEach(func() {
+			nm = &netv1alpha1.NatMapping{
+				Spec: netv1alpha1.NatMappingSpec{
+					ClusterID: clusterID1,
+					ClusterMappings: netv1alpha1.Mappings{
+						oldIP1:      newIP1,
+						"fd00:1::2": "fd00:2::2",
+					},
+				},
+			}
+		})
+		DescribeTable("should return the rules of the requested IP family",
+			func(ipv6 bool, expected IPTableRule) {
+				rules, err := getPreRoutingRulesPerNatMapping(nm, ipv6)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rules).To(Equal([]IPTableRule{expected}))
+			},
+			Entry("IPv4", false, IPTableRule{"-d", newIP1, "-j", DNAT, "--to-destination", oldIP1}),
+			Entry("IPv6", true, IPTableRule{"-d", "fd00:2::2", "-j", DNAT, "--to-destination", "fd00:1::2"}),
+		)
+	})
+	Describe("getPolicyRules", func() {
+		var targets []PolicyTarget
+
+		BeforeEach(func() {
+			targets = []PolicyTarget{{
+				Policy: &netv1alpha1.InterClusterNetworkPolicy{
+					ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: "foo"},
+					Spec: netv1alpha1.InterClusterNetworkPolicySpec{
+						Egress: []netv1alpha1.InterClusterEgressRule{
+							{ClusterID: clusterID1, CIDRs: []string{"10.60.0.10/32", "fd00:1::/64"},
+								Ports: []netv1alpha1.InterClusterPolicyPort{{Protocol: corev1.ProtocolTCP, Port: 80}, {Protocol: corev1.ProtocolUDP}}},
+							{ClusterID: "another-cluster"},
+						},
+					},
+				},
+				PodIPs: []string{oldIP1, "fd00:2::2"},
+			}, {
+				Policy: &netv1alpha1.InterClusterNetworkPolicy{
+					ObjectMeta: metav1.ObjectMeta{Name: "all", Namespace: "foo"},
+					Spec: netv1alpha1.InterClusterNetworkPolicySpec{
+						Egress: []netv1alpha1.InterClusterEgressRule{{ClusterID: clusterID1}},
+					},
+				},
+				PodIPs: []string{oldIP2},
+			}, {
+				Policy: &netv1alpha1.InterClusterNetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: "isolate", Namespace: "foo"}},
+				PodIPs: []string{oldIP1, newIP1},
+			}}
+		})
+
+		It("should compile the IPv4 rules", func() {
+			allowRules, dropRules, err := getPolicyRules(validTep, targets, false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowRules).To(ConsistOf(
+				IPTableRule{"-m", "conntrack", "--ctstate", "RELATED,ESTABLISHED", "-j", ACCEPT},
+				IPTableRule{"-s", oldIP1, "-d", "10.60.0.10", "-p", "tcp", "-m", "tcp", "--dport", "80", "-j", ACCEPT},
+				IPTableRule{"-s", oldIP1, "-d", "10.60.0.10", "-p", "udp", "-j", ACCEPT},
+				IPTableRule{"-s", oldIP2, "-d", validTep.Spec.RemoteNATPodCIDR, "-j", ACCEPT},
+				IPTableRule{"-s", oldIP2, "-d", validTep.Spec.RemoteNATExternalCIDR, "-j", ACCEPT},
+			))
+			Expect(dropRules).To(ConsistOf(
+				IPTableRule{"-s", oldIP1, "-j", DROP},
+				IPTableRule{"-s", oldIP2, "-j", DROP},
+				IPTableRule{"-s", newIP1, "-j", DROP},
+			))
+		})
+
+		It("should compile the IPv6 rules", func() {
+			allowRules, dropRules, err := getPolicyRules(validTep, targets, true)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowRules).To(ConsistOf(
+				IPTableRule{"-m", "conntrack", "--ctstate", "RELATED,ESTABLISHED", "-j", ACCEPT},
+				IPTableRule{"-s", "fd00:2::2", "-d", "fd00:1::/64", "-p", "tcp", "-m", "tcp", "--dport", "80", "-j", ACCEPT},
+				IPTableRule{"-s", "fd00:2::2", "-d", "fd00:1::/64", "-p", "udp", "-j", ACCEPT},
+			))
+			Expect(dropRules).To(Equal([]IPTableRule{{"-s", "fd00:2::2", "-j", DROP}}))
+		})
+
+		It("should return an error in case of invalid CIDRs", func() {
+			targets[0].Policy.Spec.Egress[0].CIDRs = []string{invalidValue}
+			_, _, err := getPolicyRules(validTep, targets, false)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+	Describe("transit networks", func() {
+		const (
+			transitCluster   = "transitCluster"
+			transitPodCIDR   = "10.80.0.0/24"
+			transitNATCIDR   = "10.90.0.0/24"
+			transitLocalCIDR = "10.81.0.0/24"
+		)
+
+		var transitTep *netv1alpha1.TunnelEndpoint
+
+		BeforeEach(func() {
+			transitTep = validTep.DeepCopy()
+			transitTep.Spec.RemoteTransitNetworks = []netv1alpha1.TransitNetworkMapping{{
+				ClusterIdentity: discv1alpha1.ClusterIdentity{ClusterID: transitCluster, ClusterName: transitCluster},
+				PodCIDR:         transitPodCIDR, NATPodCIDR: transitNATCIDR,
+			}}
+			transitTep.Spec.LocalTransitNetworks = []netv1alpha1.TransitNetworkMapping{{
+				ClusterIdentity: discv1alpha1.ClusterIdentity{ClusterID: "local-transit", ClusterName: "local-transit"},
+				PodCIDR:         transitLocalCIDR, NATPodCIDR: transitNATCIDR,
+			}, {
+				ClusterIdentity: discv1alpha1.ClusterIdentity{ClusterID: "other-transit", ClusterName: "other-transit"},
+				PodCIDR:         transitPodCIDR, NATPodCIDR: consts.DefaultCIDRValue,
+			}}
+		})
+
+		It("should jump to the chains of the transit cluster", func() {
+			chainRules, err := getChainRulesPerCluster(transitTep)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(chainRules[liqonetPostroutingChain]).To(ContainElement(IPTableRule{"-d", transitNATCIDR,
+				"-m", "comment", "--comment", fmt.Sprintf("SNAT local traffic for '%s' %s of '%s'", clusterName1, consts.PodCIDR, transitCluster),
+				"-j", getClusterPostRoutingChain(clusterID1)}))
+			Expect(chainRules[liqonetForwardingChain]).To(ContainElements(
+				IPTableRule{"-d", transitNATCIDR, "-j", getClusterPolicyAllowChain(clusterID1)},
+				IPTableRule{"-d", transitNATCIDR, "-j", getClusterPolicyDropChain(clusterID1)}))
+			Expect(chainRules[liqonetPreroutingChain]).To(ContainElements(
+				IPTableRule{"-s", transitNATCIDR, "-d", validTep.Spec.LocalNATPodCIDR,
+					"-m", "comment", "--comment", fmt.Sprintf("DNAT traffic from '%s' %s of '%s'", clusterName1, consts.PodCIDR, transitCluster),
+					"-j", getClusterPreRoutingChain(clusterID1)},
+				IPTableRule{"-s", validTep.Spec.RemoteNATPodCIDR, "-d", transitNATCIDR,
+					"-m", "comment", "--comment", fmt.Sprintf("DNAT traffic in transit from '%s' %s of '%s'", clusterName1, consts.PodCIDR, "local-transit"),
+					"-j", getClusterPreRoutingChain(clusterID1)}))
+		})
+
+		It("should translate the traffic towards the transit networks", func() {
+			rules, err := getPostroutingRules(transitTep)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rules).To(ContainElements(
+				IPTableRule{"-s", validTep.Spec.LocalPodCIDR, "-d", transitNATCIDR, "-j", NETMAP, "--to", validTep.Spec.LocalNATPodCIDR},
+				IPTableRule{"!", "-s", validTep.Spec.LocalPodCIDR, "-d", transitNATCIDR, "-j", SNAT, "--to-source", "192.168.1.0"}))
+		})
+
+		It("should translate the source of the traffic in transit towards the remote cluster, if remapped", func() {
+			Expect(getTransitPostroutingRules(transitTep)).To(Equal([]IPTableRule{
+				{"-s", transitLocalCIDR, "-d", validTep.Spec.RemoteNATPodCIDR, "-j", NETMAP, "--to", transitNATCIDR},
+				{"-s", transitPodCIDR, "-d", validTep.Spec.RemoteNATPodCIDR, "-j", ACCEPT},
+			}))
+		})
+
+		It("should translate the destination of the traffic in transit from the remote cluster", func() {
+			rules, err := getPreRoutingRulesPerTunnelEndpoint(transitTep)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rules).To(ConsistOf(
+				IPTableRule{"-s", validTep.Spec.RemoteNATPodCIDR, "-d", transitNATCIDR, "-j", NETMAP, "--to", transitLocalCIDR},
+				IPTableRule{"-s", validTep.Spec.RemoteNATPodCIDR, "-d", validTep.Spec.LocalNATPodCIDR, "-j", NETMAP, "--to", validTep.Spec.LocalPodCIDR},
+				IPTableRule{"-s", transitNATCIDR, "-d", validTep.Spec.LocalNATPodCIDR, "-j", NETMAP, "--to", validTep.Spec.LocalPodCIDR}))
+		})
+
+		It("should enforce the inter-cluster network policies towards the transit clusters", func() {
+			targets := []PolicyTarget{{
+				Policy: &netv1alpha1.InterClusterNetworkPolicy{
+					ObjectMeta: metav1.ObjectMeta{Name: "transit", Namespace: "foo"},
+					Spec: netv1alpha1.InterClusterNetworkPolicySpec{
+						Egress: []netv1alpha1.InterClusterEgressRule{{ClusterID: transitCluster}},
+					},
+				},
+				PodIPs: []string{oldIP1},
+			}}
+			allowRules, dropRules, err := getPolicyRules(transitTep, targets, false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowRules).To(ContainElement(IPTableRule{"-s", oldIP1, "-d", transitNATCIDR, "-j", ACCEPT}))
+			Expect(allowRules).To(HaveLen(2))
+			Expect(dropRules).To(Equal([]IPTableRule{{"-s", oldIP1, "-j", DROP}}))
+		})
 	})
 	Describe("Utilities", func() {
 		var (
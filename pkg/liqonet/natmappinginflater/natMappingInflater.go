@@ -41,6 +41,9 @@ type Interface interface {
 	// externalCIDR is the ExternalCIDR used in the remote cluster for local exported resources:
 	// it can be either the LocalExternalCIDR or the LocalNATExternalCIDR.
 	InitNatMappingsPerCluster(podCIDR, externalCIDR, clusterID string) error
+	// InitIPv6NatMappingsPerCluster sets the IPv6 networks of an already initialized remote cluster,
+	// in case of dual-stack clusters. Parameters have the same meaning of InitNatMappingsPerCluster ones.
+	InitIPv6NatMappingsPerCluster(podCIDR, externalCIDR, clusterID string) error
 	// TerminateNatMappingsPerCluster frees/deletes resources allocated for remote cluster.
 	TerminateNatMappingsPerCluster(clusterID string) error
 	// GetNatMappings returns the set of mappings related to a remote cluster.
@@ -130,6 +133,50 @@ func (inflater *NatMappingInflater) InitNatMappingsPerCluster(podCIDR, externalC
 	return inflater.initResource(podCIDR, externalCIDR, clusterID)
 }
 
+// InitIPv6NatMappingsPerCluster adds the IPv6 networks to the NatMapping resource of the remote cluster.
+func (inflater *NatMappingInflater) InitIPv6NatMappingsPerCluster(podCIDR, externalCIDR, clusterID string) error {
+	// Check parameters
+	if err := checkParams(podCIDR, externalCIDR, clusterID); err != nil {
+		return err
+	}
+	for _, cidr := range []string{podCIDR, externalCIDR} {
+		if !liqonetutils.IsIPv6CIDR(cidr) {
+			return &errors.WrongParameter{
+				Reason:    errors.ValidIPv6CIDR,
+				Parameter: cidr,
+			}
+		}
+	}
+	// Check if it has been initialized
+	if _, exists := inflater.natMappingsPerCluster[clusterID]; !exists {
+		return &errors.MissingInit{
+			StructureName: fmt.Sprintf("%s for cluster %s", consts.NatMappingKind, clusterID),
+		}
+	}
+	retryError := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// Get resource for remote cluster
+		natMappings, err := inflater.getNatMappingResource(clusterID)
+		if err != nil {
+			return fmt.Errorf("cannot retrieve NatMapping resource for cluster %s: %w", clusterID, err)
+		}
+		if natMappings.Spec.PodCIDRv6 == podCIDR && natMappings.Spec.ExternalCIDRv6 == externalCIDR {
+			return nil
+		}
+		natMappings.Spec.PodCIDRv6 = podCIDR
+		natMappings.Spec.ExternalCIDRv6 = externalCIDR
+		// Update resource
+		if err := inflater.updateNatMappingResource(natMappings); err != nil {
+			return fmt.Errorf("cannot update NatMapping resource for cluster %s: %w", clusterID, err)
+		}
+		return nil
+	})
+	if retryError != nil {
+		return retryError
+	}
+	klog.Infof("IPv6 networks of NatMapping resource for cluster %s successfully set", clusterID)
+	return nil
+}
+
 func (inflater *NatMappingInflater) initResource(podCIDR, externalCIDR, clusterID string) error {
 	// Check existence of resource
 	natMappings, err := inflater.getNatMappingResource(clusterID)
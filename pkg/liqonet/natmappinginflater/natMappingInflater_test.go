@@ -220,6 +220,40 @@ var _ = Describe("NatMappingInflater", func() {
 			})
 		})
 	})
+	Describe("InitIPv6NatMappingsPerCluster", func() {
+		const (
+			podCIDRv6      = "fd00:10::/64"
+			externalCIDRv6 = "fd00:11::/64"
+		)
+		Context("Passing an IPv4 network", func() {
+			It("should return a WrongParameter error", func() {
+				err := inflater.InitIPv6NatMappingsPerCluster(podCIDR, externalCIDRv6, clusterID1)
+				Expect(err).To(MatchError(fmt.Sprintf("%s must be %s", podCIDR, liqoneterrors.ValidIPv6CIDR)))
+			})
+		})
+		Context("If the cluster has not been initialized yet", func() {
+			It("should return a MissingInit error", func() {
+				err := inflater.InitIPv6NatMappingsPerCluster(podCIDRv6, externalCIDRv6, clusterID3)
+				Expect(err).To(MatchError(fmt.Sprintf("%s for cluster %s must be %s",
+					consts.NatMappingKind, clusterID3, liqoneterrors.Initialization)))
+			})
+		})
+		Context("If the cluster has been initialized", func() {
+			It("should set the IPv6 networks in the resource", func() {
+				err := inflater.InitNatMappingsPerCluster(podCIDR, externalCIDR, clusterID1)
+				Expect(err).To(BeNil())
+				err = inflater.InitIPv6NatMappingsPerCluster(podCIDRv6, externalCIDRv6, clusterID1)
+				Expect(err).To(BeNil())
+
+				nm, err := inflater.getNatMappingResource(clusterID1)
+				Expect(err).To(BeNil())
+				Expect(nm.Spec.PodCIDR).To(Equal(podCIDR))
+				Expect(nm.Spec.ExternalCIDR).To(Equal(externalCIDR))
+				Expect(nm.Spec.PodCIDRv6).To(Equal(podCIDRv6))
+				Expect(nm.Spec.ExternalCIDRv6).To(Equal(externalCIDRv6))
+			})
+		})
+	})
 	Describe("GetNatMappings", func() {
 		Context("If the cluster has not been initialized yet", func() {
 			It("should return a WrongParameterError", func() {
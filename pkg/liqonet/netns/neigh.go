@@ -30,7 +30,7 @@ import (
 func AddNeigh(addr net.IP, lladdr net.HardwareAddr, dev *net.Interface) (bool, error) {
 	klog.V(5).Infof("calling ip neigh add %s lladdr %s dev %s state permanent", addr, lladdr.String(), dev.Name)
 	// First we list all the neighbors
-	neighbors, err := netlink.NeighList(dev.Index, syscall.AF_INET)
+	neighbors, err := netlink.NeighList(dev.Index, neighFamily(addr))
 	if err != nil {
 		return false, err
 	}
@@ -43,7 +43,7 @@ func AddNeigh(addr net.IP, lladdr net.HardwareAddr, dev *net.Interface) (bool, e
 	err = netlink.NeighSet(&netlink.Neigh{
 		LinkIndex:    dev.Index,
 		State:        netlink.NUD_PERMANENT,
-		Family:       syscall.AF_INET,
+		Family:       neighFamily(addr),
 		IP:           addr,
 		HardwareAddr: lladdr,
 	})
@@ -63,7 +63,7 @@ func DelNeigh(addr net.IP, lladdr net.HardwareAddr, dev *net.Interface) (bool, e
 	err := netlink.NeighDel(&netlink.Neigh{
 		LinkIndex:    dev.Index,
 		State:        netlink.NUD_PERMANENT,
-		Family:       syscall.AF_INET,
+		Family:       neighFamily(addr),
 		IP:           addr,
 		HardwareAddr: lladdr,
 	})
@@ -77,3 +77,11 @@ func DelNeigh(addr net.IP, lladdr net.HardwareAddr, dev *net.Interface) (bool, e
 		lladdr.String(), addr.String(), dev.Name)
 	return true, nil
 }
+
+// neighFamily returns the address family of the neighbor entry for the given IP address.
+func neighFamily(addr net.IP) int {
+	if addr.To4() == nil {
+		return syscall.AF_INET6
+	}
+	return syscall.AF_INET
+}
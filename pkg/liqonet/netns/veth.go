@@ -94,6 +94,11 @@ func ConfigureVeth(veth *net.Interface, gatewayIP string, netNS ns.NetNS) error
 	}
 
 	gwNet := gatewayIP + "/32"
+	// In case of an IPv6 gateway, the IPv6 host route and default route are configured.
+	if gwIP.To4() == nil {
+		defaultCIDR = "::/0"
+		gwNet = gatewayIP + "/128"
+	}
 	klog.V(5).Infof("configuring veth {%s} with index {%d} in namespace with path {%s}",
 		veth.Name, veth.Index, netNS.Path())
 
@@ -16,9 +16,11 @@ package routing
 
 import (
 	"errors"
+	"fmt"
 	"net"
 	"os"
 	"reflect"
+	"sync"
 
 	"github.com/vishvananda/netlink"
 	"golang.org/x/sys/unix"
@@ -34,10 +36,18 @@ const (
 	DefaultScope netlink.Scope = 0
 	// DefaultFlags is the default value for the route flags.
 	DefaultFlags int = 0
+
+	ipv6ForwardingPath = "/proc/sys/net/ipv6/conf/all/forwarding"
 )
 
 // AddRoute adds a new route on the given interface.
 func AddRoute(dstNet, gwIP string, iFaceIndex, tableID, flags int, scope netlink.Scope) (bool, error) {
+	return AddRouteWithMTU(dstNet, gwIP, iFaceIndex, tableID, flags, scope, 0)
+}
+
+// AddRouteWithMTU adds a new route on the given interface, limiting the MTU of the packets towards the destination.
+// A zero MTU means that the MTU of the interface applies.
+func AddRouteWithMTU(dstNet, gwIP string, iFaceIndex, tableID, flags int, scope netlink.Scope, mtu int) (bool, error) {
 	var route *netlink.Route
 	var gatewayIP net.IP
 	// Convert destination in *net.IPNet.
@@ -59,9 +69,10 @@ func AddRoute(dstNet, gwIP string, iFaceIndex, tableID, flags int, scope netlink
 		LinkIndex: iFaceIndex,
 		Flags:     flags,
 		Scope:     scope,
+		MTU:       mtu,
 	}
 	// Check if already exists a route for the given destination.
-	routes, err := netlink.RouteListFiltered(netlink.FAMILY_V4, route, netlink.RT_FILTER_TABLE|netlink.RT_FILTER_DST)
+	routes, err := netlink.RouteListFiltered(netFamily(destinationNet), route, netlink.RT_FILTER_TABLE|netlink.RT_FILTER_DST)
 	if err != nil {
 		return false, err
 	}
@@ -70,7 +81,7 @@ func AddRoute(dstNet, gwIP string, iFaceIndex, tableID, flags int, scope netlink
 	if len(routes) == 1 {
 		r := routes[0]
 		// Check if the existing rule is equal to the one that we want to configure.
-		if reflect.DeepEqual(r.Gw, gatewayIP.To4()) && r.LinkIndex == iFaceIndex {
+		if reflect.DeepEqual(r.Gw, normalizeIP(gatewayIP)) && r.LinkIndex == iFaceIndex && r.MTU == mtu {
 			klog.V(5).Infof("route {%s} already exists", route.String())
 			return false, nil
 		}
@@ -126,7 +137,8 @@ func flushRoutesForRoutingTable(tableID int) error {
 	route := &netlink.Route{
 		Table: tableID,
 	}
-	routes, err := netlink.RouteListFiltered(netlink.FAMILY_V4, route, netlink.RT_FILTER_TABLE)
+	// Both IPv4 and IPv6 routes are listed, since the routing table may contain routes of both families.
+	routes, err := netlink.RouteListFiltered(netlink.FAMILY_ALL, route, netlink.RT_FILTER_TABLE)
 	if err != nil {
 		return err
 	}
@@ -150,7 +162,7 @@ func AddPolicyRoutingRule(fromSubnet, toSubnet string, tableID int) (bool, error
 		return false, err
 	}
 	// Get existing rules.
-	rules, err := netlink.RuleList(netlink.FAMILY_V4)
+	rules, err := netlink.RuleList(ruleFamily(sourceNet, destinationNet))
 	if err != nil {
 		klog.Errorf("an error occurred while listing the policy routing rules: %v", err)
 		return false, err
@@ -191,7 +203,7 @@ func DelPolicyRoutingRule(fromSubnet, toSubnet string, tableID int) (bool, error
 		return false, err
 	}
 	// Get existing rules.
-	rules, err := netlink.RuleList(netlink.FAMILY_V4)
+	rules, err := netlink.RuleList(ruleFamily(sourceNet, destinationNet))
 	if err != nil {
 		klog.Errorf("an error occurred while listing the policy routing rules: %v", err)
 		return false, err
@@ -210,8 +222,8 @@ func DelPolicyRoutingRule(fromSubnet, toSubnet string, tableID int) (bool, error
 }
 
 func flushRulesForRoutingTable(routingTableID int) error {
-	// First we list all the policy routing rules.
-	rules, err := netlink.RuleList(netlink.FAMILY_V4)
+	// First we list all the policy routing rules, of both IPv4 and IPv6 families.
+	rules, err := netlink.RuleList(netlink.FAMILY_ALL)
 	if err != nil {
 		return err
 	}
@@ -229,6 +241,111 @@ func flushRulesForRoutingTable(routingTableID int) error {
 	return nil
 }
 
+// forEachFamily invokes the given function on the TunnelEndpoint of each configured IP family (i.e., on
+// its IPv6 counterpart as well, in case of dual-stack peerings), and returns true if any invocation returned true.
+// The function is invoked also on the copies describing the networks reachable through the remote (transit) cluster.
+func forEachFamily(tep *v1alpha1.TunnelEndpoint, fn func(*v1alpha1.TunnelEndpoint) (bool, error)) (bool, error) {
+	var configured bool
+	teps := append(liqonetutils.GetTunnelEndpointsPerFamily(tep), liqonetutils.GetTransitTunnelEndpoints(tep)...)
+	for _, t := range teps {
+		changed, err := fn(t)
+		configured = configured || changed
+		if err != nil {
+			return configured, err
+		}
+	}
+	return configured, nil
+}
+
+// transitNetworks keeps track of the networks reachable through each remote (transit) cluster for which the routes have
+// been configured, so that the routes towards the networks which are no longer advertised can be removed.
+// The zero value is ready to be used.
+type transitNetworks struct {
+	mutex sync.Mutex
+	// networks maps the ID of each remote cluster to the transit networks configured through it.
+	networks map[string][]v1alpha1.TransitNetworkMapping
+}
+
+// ensureForEachFamily invokes the given ensure function through forEachFamily, after invoking the remove one on the
+// transit networks configured through the same remote cluster, which are no longer listed in the given TunnelEndpoint.
+func (tn *transitNetworks) ensureForEachFamily(tep *v1alpha1.TunnelEndpoint,
+	ensure, remove func(*v1alpha1.TunnelEndpoint) (bool, error)) (bool, error) {
+	tn.mutex.Lock()
+	defer tn.mutex.Unlock()
+
+	removed, err := tn.removeStale(tep, remove)
+	if err != nil {
+		return removed, err
+	}
+	if tn.networks == nil {
+		tn.networks = make(map[string][]v1alpha1.TransitNetworkMapping)
+	}
+	// The networks are tracked before configuring them, as a failure might leave the corresponding routes partially configured.
+	tn.networks[tep.Spec.ClusterIdentity.ClusterID] = append([]v1alpha1.TransitNetworkMapping(nil), tep.Spec.RemoteTransitNetworks...)
+	configured, err := forEachFamily(tep, ensure)
+	return removed || configured, err
+}
+
+// removeForEachFamily invokes the given remove function through forEachFamily, as well as on the transit networks
+// configured through the same remote cluster which are no longer listed in the given TunnelEndpoint.
+func (tn *transitNetworks) removeForEachFamily(tep *v1alpha1.TunnelEndpoint, remove func(*v1alpha1.TunnelEndpoint) (bool, error)) (bool, error) {
+	tn.mutex.Lock()
+	defer tn.mutex.Unlock()
+
+	removed, err := tn.removeStale(tep, remove)
+	if err != nil {
+		return removed, err
+	}
+	deleted, err := forEachFamily(tep, remove)
+	if err != nil {
+		return removed || deleted, err
+	}
+	delete(tn.networks, tep.Spec.ClusterIdentity.ClusterID)
+	return removed || deleted, nil
+}
+
+// removeStale invokes the given remove function on the transit networks configured through the remote cluster
+// described by the given TunnelEndpoint, which are no longer listed in its spec. The mutex shall be held.
+func (tn *transitNetworks) removeStale(tep *v1alpha1.TunnelEndpoint, remove func(*v1alpha1.TunnelEndpoint) (bool, error)) (bool, error) {
+	listed := func(mapping *v1alpha1.TransitNetworkMapping) bool {
+		for i := range tep.Spec.RemoteTransitNetworks {
+			if reflect.DeepEqual(*mapping, tep.Spec.RemoteTransitNetworks[i]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	clusterID := tep.Spec.ClusterIdentity.ClusterID
+	var stale, remaining []v1alpha1.TransitNetworkMapping
+	for i := range tn.networks[clusterID] {
+		if listed(&tn.networks[clusterID][i]) {
+			remaining = append(remaining, tn.networks[clusterID][i])
+		} else {
+			stale = append(stale, tn.networks[clusterID][i])
+		}
+	}
+	if len(stale) == 0 {
+		return false, nil
+	}
+
+	// The stale networks are removed leveraging the current status of the TunnelEndpoint (e.g., the gateway IP).
+	staleTep := tep.DeepCopy()
+	staleTep.Spec.RemoteTransitNetworks = stale
+	var removed bool
+	for _, t := range liqonetutils.GetTransitTunnelEndpoints(staleTep) {
+		changed, err := remove(t)
+		removed = removed || changed
+		if err != nil {
+			return removed, fmt.Errorf("%s -> unable to remove the routes towards transit network %s: %w",
+				tep.Spec.ClusterIdentity, t.Spec.RemotePodCIDR, err)
+		}
+	}
+	klog.V(4).Infof("%s -> removed the routes towards %d transit networks no longer reachable", tep.Spec.ClusterIdentity, len(stale))
+	tn.networks[clusterID] = remaining
+	return removed, nil
+}
+
 func getRouteConfig(tep *v1alpha1.TunnelEndpoint, podIP string) (dstPodCIDRNet, dstExternalCIDRNet, gatewayIP string, iFaceIndex int, err error) {
 	_, dstPodCIDRNet = liqonetutils.GetPodCIDRS(tep)
 	_, dstExternalCIDRNet = liqonetutils.GetExternalCIDRS(tep)
@@ -236,6 +353,13 @@ func getRouteConfig(tep *v1alpha1.TunnelEndpoint, podIP string) (dstPodCIDRNet,
 	if tep.Status.GatewayIP != podIP {
 		// If the pod is not running on the same host then set the IP address of the Gateway as next hop.
 		gatewayIP = tep.Status.GatewayIP
+		// In case of IPv6 networks, the IPv6 address of the Gateway pod is used as next hop.
+		if liqonetutils.IsIPv6CIDR(dstPodCIDRNet) {
+			if gatewayIP = tep.Status.GatewayIPv6; gatewayIP == "" {
+				err = fmt.Errorf("the IPv6 address of the gateway for cluster %s is not yet available", tep.Spec.ClusterIdentity.ClusterID)
+				return dstPodCIDRNet, dstExternalCIDRNet, gatewayIP, iFaceIndex, err
+			}
+		}
 		// Get the iFace index for the IP address of the Gateway pod.
 		iFaceIndex, err = getIFaceIndexForIP(gatewayIP)
 		if err != nil {
@@ -256,7 +380,7 @@ func getIFaceIndexForIP(ipAddress string) (int, error) {
 			IPToBeParsed: ipAddress,
 		}
 	}
-	routes, err := netlink.RouteList(nil, netlink.FAMILY_V4)
+	routes, err := netlink.RouteList(nil, ipFamily(ip))
 	if err != nil {
 		return 0, err
 	}
@@ -308,7 +432,49 @@ func parseIP(ip string) (net.IP, error) {
 	return address, nil
 }
 
+// normalizeIP returns the 4-byte representation of IPv4 addresses, as returned by netlink, and the address as is otherwise.
+func normalizeIP(ip net.IP) net.IP {
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4
+	}
+	return ip
+}
+
+// ipFamily returns the netlink family of the given IP address.
+func ipFamily(ip net.IP) int {
+	if ip != nil && ip.To4() == nil {
+		return netlink.FAMILY_V6
+	}
+	return netlink.FAMILY_V4
+}
+
+// netFamily returns the netlink family of the given network.
+func netFamily(ipNet *net.IPNet) int {
+	if ipNet == nil {
+		return netlink.FAMILY_V4
+	}
+	return ipFamily(ipNet.IP)
+}
+
+// ruleFamily returns the netlink family of a policy routing rule, given its source and destination networks.
+func ruleFamily(sourceNet, destinationNet *net.IPNet) int {
+	if destinationNet != nil {
+		return netFamily(destinationNet)
+	}
+	return netFamily(sourceNet)
+}
+
 // EnableIPForwarding enables ipv4 forwarding in the current network namespace.
+// IPv6 forwarding is enabled as well, in case IPv6 is supported by the current network namespace.
 func EnableIPForwarding() error {
-	return os.WriteFile("/proc/sys/net/ipv4/ip_forward", []byte("1"), 0o600)
+	if err := os.WriteFile("/proc/sys/net/ipv4/ip_forward", []byte("1"), 0o600); err != nil {
+		return err
+	}
+	if _, err := os.Stat(ipv6ForwardingPath); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return os.WriteFile(ipv6ForwardingPath, []byte("1"), 0o600)
 }
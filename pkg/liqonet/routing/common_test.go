@@ -22,6 +22,8 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/vishvananda/netlink"
 
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
 	"github.com/liqotech/liqo/pkg/liqonet/errors"
 )
 
@@ -535,3 +537,57 @@ var _ = Describe("Common", func() {
 		})
 	})
 })
+
+var _ = Describe("Tracking the transit networks", func() {
+	var (
+		tracker          transitNetworks
+		tep              *netv1alpha1.TunnelEndpoint
+		ensured, removed []string
+	)
+
+	record := func(dsts *[]string) func(*netv1alpha1.TunnelEndpoint) (bool, error) {
+		return func(t *netv1alpha1.TunnelEndpoint) (bool, error) {
+			*dsts = append(*dsts, t.Spec.RemotePodCIDR)
+			return true, nil
+		}
+	}
+	transit := func(clusterID, podCIDR string) netv1alpha1.TransitNetworkMapping {
+		return netv1alpha1.TransitNetworkMapping{
+			ClusterIdentity: discoveryv1alpha1.ClusterIdentity{ClusterID: clusterID}, PodCIDR: podCIDR, NATPodCIDR: "None"}
+	}
+
+	BeforeEach(func() {
+		tracker = transitNetworks{}
+		ensured, removed = nil, nil
+		tep = &netv1alpha1.TunnelEndpoint{Spec: netv1alpha1.TunnelEndpointSpec{
+			ClusterIdentity: discoveryv1alpha1.ClusterIdentity{ClusterID: "hub"},
+			RemotePodCIDR:   "10.1.0.0/16", RemoteNATPodCIDR: "None", RemoteExternalCIDR: "10.2.0.0/16", RemoteNATExternalCIDR: "None",
+			RemoteTransitNetworks: []netv1alpha1.TransitNetworkMapping{
+				transit("spoke-1", "10.10.0.0/16"), transit("spoke-2", "10.20.0.0/16")},
+		}}
+		Expect(tracker.ensureForEachFamily(tep, record(&ensured), record(&removed))).To(BeTrue())
+		Expect(ensured).To(ConsistOf("10.1.0.0/16", "10.10.0.0/16", "10.20.0.0/16"))
+		Expect(removed).To(BeEmpty())
+		ensured = nil
+	})
+
+	It("should remove the routes towards the transit networks no longer advertised", func() {
+		tep.Spec.RemoteTransitNetworks = []netv1alpha1.TransitNetworkMapping{
+			transit("spoke-2", "10.20.0.0/16"), transit("spoke-3", "10.30.0.0/16")}
+		Expect(tracker.ensureForEachFamily(tep, record(&ensured), record(&removed))).To(BeTrue())
+		Expect(removed).To(ConsistOf("10.10.0.0/16"))
+		Expect(ensured).To(ConsistOf("10.1.0.0/16", "10.20.0.0/16", "10.30.0.0/16"))
+
+		// The stale networks are removed only once.
+		removed = nil
+		Expect(tracker.ensureForEachFamily(tep, record(&ensured), record(&removed))).To(BeTrue())
+		Expect(removed).To(BeEmpty())
+	})
+
+	It("should remove the routes towards the transit networks no longer advertised, when the peering is torn down", func() {
+		tep.Spec.RemoteTransitNetworks = nil
+		Expect(tracker.removeForEachFamily(tep, record(&removed))).To(BeTrue())
+		Expect(removed).To(ConsistOf("10.1.0.0/16", "10.10.0.0/16", "10.20.0.0/16"))
+		Expect(tracker.networks).NotTo(HaveKey("hub"))
+	})
+})
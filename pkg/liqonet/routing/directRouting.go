@@ -30,6 +30,7 @@ import (
 type DirectRoutingManager struct {
 	routingTableID int
 	podIP          string
+	transits       transitNetworks
 }
 
 // NewDirectRoutingManager accepts as input a routing table ID and the IP address of the pod.
@@ -60,6 +61,11 @@ func NewDirectRoutingManager(routingTableID int, podIP string) (Routing, error)
 // Returns true if the routes have been configured, false if the routes are already configured.
 // An error if something goes wrong and the routes can not be configured.
 func (drm *DirectRoutingManager) EnsureRoutesPerCluster(tep *netv1alpha1.TunnelEndpoint) (bool, error) {
+	return drm.transits.ensureForEachFamily(tep, drm.ensureRoutesPerFamily, drm.removeRoutesPerFamily)
+}
+
+// ensureRoutesPerFamily configures the routes for a single IP family of the given netv1alpha.tunnelendpoint.
+func (drm *DirectRoutingManager) ensureRoutesPerFamily(tep *netv1alpha1.TunnelEndpoint) (bool, error) {
 	var routePodCIDRAdd, routeExternalCIDRAdd, policyRulePodCIDRAdd, policyRuleExternalCIDRAdd, configured bool
 	clusterID := tep.Spec.ClusterIdentity.ClusterID
 	// Extract and save route information from the given tep.
@@ -102,6 +108,11 @@ func (drm *DirectRoutingManager) EnsureRoutesPerCluster(tep *netv1alpha1.TunnelE
 // Returns true if the routes exist and have been deleted, false if nothing is removed.
 // An error if something goes wrong and the routes can not be removed.
 func (drm *DirectRoutingManager) RemoveRoutesPerCluster(tep *netv1alpha1.TunnelEndpoint) (bool, error) {
+	return drm.transits.removeForEachFamily(tep, drm.removeRoutesPerFamily)
+}
+
+// removeRoutesPerFamily removes the routes for a single IP family of the given netv1alpha.tunnelendpoint.
+func (drm *DirectRoutingManager) removeRoutesPerFamily(tep *netv1alpha1.TunnelEndpoint) (bool, error) {
 	var routePodCIDRDel, routeExternalCIDRDel, policyRulePodCIDRDel, policyRuleExternalCIDRDel, configured bool
 	clusterID := tep.Spec.ClusterIdentity.ClusterID
 	// Extract and save route information from the given tep.
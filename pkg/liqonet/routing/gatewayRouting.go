@@ -30,6 +30,7 @@ import (
 type GatewayRoutingManager struct {
 	routingTableID int
 	tunnelDevice   netlink.Link
+	transits       transitNetworks
 }
 
 // NewGatewayRoutingManager returns a GatewayRoutingManager ready to be used or an error.
@@ -58,17 +59,25 @@ func NewGatewayRoutingManager(routingTableID int, tunnelDevice netlink.Link) (Ro
 // Returns true if the routes have been configured, false if the routes are already configured.
 // An error if something goes wrong and the routes can not be configured.
 func (grm *GatewayRoutingManager) EnsureRoutesPerCluster(tep *netv1alpha1.TunnelEndpoint) (bool, error) {
+	return grm.transits.ensureForEachFamily(tep, grm.ensureRoutesPerFamily, grm.removeRoutesPerFamily)
+}
+
+// ensureRoutesPerFamily configures the routes for a single IP family of the given netv1alpha.tunnelendpoint.
+func (grm *GatewayRoutingManager) ensureRoutesPerFamily(tep *netv1alpha1.TunnelEndpoint) (bool, error) {
 	var routePodCIDRAdd, routeExternalCIDRAdd, configured bool
 	var err error
 	// Extract and save route information from the given tep.
 	_, dstPodCIDRNet := liqonetutils.GetPodCIDRS(tep)
 	_, dstExternalCIDRNet := liqonetutils.GetExternalCIDRS(tep)
+	// The path MTU discovered towards the remote cluster, if any, is enforced on the routes.
+	mtu := tep.Status.Connection.PathMTU
 	// Add routes for the given cluster.
-	routePodCIDRAdd, err = AddRoute(dstPodCIDRNet, "", grm.tunnelDevice.Attrs().Index, grm.routingTableID, DefaultFlags, DefaultScope)
+	routePodCIDRAdd, err = AddRouteWithMTU(dstPodCIDRNet, "", grm.tunnelDevice.Attrs().Index, grm.routingTableID, DefaultFlags, DefaultScope, mtu)
 	if err != nil {
 		return routePodCIDRAdd, err
 	}
-	routeExternalCIDRAdd, err = AddRoute(dstExternalCIDRNet, "", grm.tunnelDevice.Attrs().Index, grm.routingTableID, DefaultFlags, DefaultScope)
+	routeExternalCIDRAdd, err = AddRouteWithMTU(dstExternalCIDRNet, "", grm.tunnelDevice.Attrs().Index,
+		grm.routingTableID, DefaultFlags, DefaultScope, mtu)
 	if err != nil {
 		return routeExternalCIDRAdd, err
 	}
@@ -83,6 +92,11 @@ func (grm *GatewayRoutingManager) EnsureRoutesPerCluster(tep *netv1alpha1.Tunnel
 // Returns true if the routes exist and have been deleted, false if nothing is removed.
 // An error if something goes wrong and the routes can not be removed.
 func (grm *GatewayRoutingManager) RemoveRoutesPerCluster(tep *netv1alpha1.TunnelEndpoint) (bool, error) {
+	return grm.transits.removeForEachFamily(tep, grm.removeRoutesPerFamily)
+}
+
+// removeRoutesPerFamily removes the routes for a single IP family of the given netv1alpha.tunnelendpoint.
+func (grm *GatewayRoutingManager) removeRoutesPerFamily(tep *netv1alpha1.TunnelEndpoint) (bool, error) {
 	var routePodCIDRDel, routeExternalCIDRDel, configured bool
 	var err error
 	// Extract and save route information from the given tep.
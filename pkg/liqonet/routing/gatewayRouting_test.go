@@ -149,6 +149,24 @@ var _ = Describe("GatewayRouting", func() {
 				Expect(routes[0].Gw).Should(BeNil())
 			})
 
+			It("route configuration should be updated when the path MTU changes", func() {
+				added, err := grm.EnsureRoutesPerCluster(&tep)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(added).Should(BeTrue())
+				tepMTU := tep.DeepCopy()
+				tepMTU.Status.Connection.PathMTU = 1300
+				added, err = grm.EnsureRoutesPerCluster(tepMTU)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(added).Should(BeTrue())
+				_, dstPodCIDRNet, err := net.ParseCIDR(tep.Spec.RemoteNATPodCIDR)
+				Expect(err).ShouldNot(HaveOccurred())
+				routes, err := netlink.RouteListFiltered(netlink.FAMILY_V4, &netlink.Route{Dst: dstPodCIDRNet,
+					Table: routingTableIDGRM}, netlink.RT_FILTER_DST|netlink.RT_FILTER_TABLE)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(routes).To(HaveLen(1))
+				Expect(routes[0].MTU).To(Equal(1300))
+			})
+
 			It("route already exists, should return false and nil", func() {
 				tepGRM.Spec.RemoteNATPodCIDR = existingRoutesGRM[0].Dst.String()
 				tepGRM.Spec.RemoteNATExternalCIDR = existingRoutesGRM[1].Dst.String()
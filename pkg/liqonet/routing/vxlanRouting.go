@@ -36,6 +36,7 @@ type VxlanRoutingManager struct {
 	podIP          string
 	vxlanNetPrefix string
 	vxlanDevice    *overlay.VxlanDevice
+	transits       transitNetworks
 }
 
 // NewVxlanRoutingManager returns a VxlanRoutingManager ready to be used or an error.
@@ -73,6 +74,12 @@ func NewVxlanRoutingManager(routingTableID int, podIP, vxlanNetPrefix string, vx
 	if err := vrm.vxlanDevice.ConfigureIPAddress(overlayIPCIDR); err != nil {
 		return nil, err
 	}
+	// Configure also the IPv6 address of the vxlan interface, used to route the traffic of dual-stack peerings.
+	overlayIPv6CIDR := liqonetutils.GetOverlayIPv6(podIP) + liqoconst.OverlayNetworkIPv6Mask
+	if err := vrm.vxlanDevice.ConfigureIPAddress(overlayIPv6CIDR); err != nil {
+		klog.Warningf("unable to configure IPv6 address %s on vxlan device %s, dual-stack peerings will not work: %v",
+			overlayIPv6CIDR, vxlanDevice.Link.Name, err)
+	}
 	return vrm, nil
 }
 
@@ -81,6 +88,11 @@ func NewVxlanRoutingManager(routingTableID int, podIP, vxlanNetPrefix string, vx
 // Returns true if the routes have been configured, false if the routes are already configured.
 // An error if something goes wrong and the routes can not be configured.
 func (vrm *VxlanRoutingManager) EnsureRoutesPerCluster(tep *netv1alpha1.TunnelEndpoint) (bool, error) {
+	return vrm.transits.ensureForEachFamily(tep, vrm.ensureRoutesPerFamily, vrm.removeRoutesPerFamily)
+}
+
+// ensureRoutesPerFamily configures the routes for a single IP family of the given netv1alpha.tunnelendpoint.
+func (vrm *VxlanRoutingManager) ensureRoutesPerFamily(tep *netv1alpha1.TunnelEndpoint) (bool, error) {
 	var routePodCIDRAdd, routeExternalCIDRAdd, policyRulePodCIDRAdd, policyRuleExternalCIDRAdd, configured bool
 	var iFaceIndex int
 	var iFaceName string
@@ -93,23 +105,25 @@ func (vrm *VxlanRoutingManager) EnsureRoutesPerCluster(tep *netv1alpha1.TunnelEn
 	_, dstExternalCIDR := liqonetutils.GetExternalCIDRS(tep)
 
 	if tep.Status.GatewayIP != vrm.podIP {
-		gatewayIP = liqonetutils.GetOverlayIP(tep.Status.GatewayIP)
+		gatewayIP = getOverlayGatewayIP(tep.Status.GatewayIP, dstPodCIDR)
 		iFaceIndex = vrm.vxlanDevice.Link.Index
 		iFaceName = vrm.vxlanDevice.Link.Name
-
-		// If we are not on the same pod as the gateway then make sure that we do not have
-		// policy routing rules for the incoming traffic from the remote cluster.
-		if config, err := vrm.removePRRForIncomingTraffic(dstPodCIDR, dstExternalCIDR, clusterID); err != nil {
-			return config, err
-		}
 	} else {
-		gatewayIP = tep.Status.VethIP
+		gatewayIP = getVethGatewayIP(tep.Status.VethIP, dstPodCIDR)
 		iFaceIndex = tep.Status.VethIFaceIndex
 		iFaceName = tep.Status.VethIFaceName
+	}
 
+	if vrm.hostsGatewayReplica(tep) {
 		if config, err := vrm.ensurePRRForIncomingTraffic(dstPodCIDR, dstExternalCIDR, clusterID); err != nil {
 			return config, err
 		}
+	} else {
+		// If we are not on the same pod as (one of the replicas of) the gateway then make sure that we do not have
+		// policy routing rules for the incoming traffic from the remote cluster.
+		if config, err := vrm.removePRRForIncomingTraffic(dstPodCIDR, dstExternalCIDR, clusterID); err != nil {
+			return config, err
+		}
 	}
 
 	// Add policy routing rules for the given cluster.
@@ -155,6 +169,11 @@ func (vrm *VxlanRoutingManager) EnsureRoutesPerCluster(tep *netv1alpha1.TunnelEn
 // Returns true if the routes exist and have been deleted, false if nothing is removed.
 // An error if something goes wrong and the routes can not be removed.
 func (vrm *VxlanRoutingManager) RemoveRoutesPerCluster(tep *netv1alpha1.TunnelEndpoint) (bool, error) {
+	return vrm.transits.removeForEachFamily(tep, vrm.removeRoutesPerFamily)
+}
+
+// removeRoutesPerFamily removes the routes for a single IP family of the given netv1alpha.tunnelendpoint.
+func (vrm *VxlanRoutingManager) removeRoutesPerFamily(tep *netv1alpha1.TunnelEndpoint) (bool, error) {
 	var routePodCIDRDel, routeExternalCIDRDel, policyRulePodCIDRDel, policyRuleExternalCIDRDel, configured bool
 	var iFaceIndex int
 	var iFaceName string
@@ -167,20 +186,27 @@ func (vrm *VxlanRoutingManager) RemoveRoutesPerCluster(tep *netv1alpha1.TunnelEn
 	_, dstExternalCIDR := liqonetutils.GetExternalCIDRS(tep)
 
 	if tep.Status.GatewayIP != vrm.podIP {
-		gatewayIP = liqonetutils.GetOverlayIP(tep.Status.GatewayIP)
+		gatewayIP = getOverlayGatewayIP(tep.Status.GatewayIP, dstPodCIDR)
 		iFaceIndex = vrm.vxlanDevice.Link.Index
 		iFaceName = vrm.vxlanDevice.Link.Name
 	} else {
-		gatewayIP = tep.Status.VethIP
+		gatewayIP = getVethGatewayIP(tep.Status.VethIP, dstPodCIDR)
 		iFaceIndex = tep.Status.VethIFaceIndex
 		iFaceName = tep.Status.VethIFaceName
+	}
 
-		// If we are on the same node as the gateway than remove also the policy routing rules
+	if vrm.hostsGatewayReplica(tep) {
+		// If we are on the same node as (one of the replicas of) the gateway than remove also the policy routing rules
 		// for the incoming traffic from the remote cluster.
 		if config, err := vrm.removePRRForIncomingTraffic(dstPodCIDR, dstExternalCIDR, clusterID); err != nil {
 			return config, err
 		}
 	}
+	// In case of hot-standby gateways, the replica in charge of the tunnels might have changed since the configuration
+	// of the routes: hence, they are removed based on the destination only.
+	if len(tep.Status.Replicas) > 0 {
+		gatewayIP, iFaceIndex = "", 0
+	}
 
 	// Delete policy routing rules for the given cluster.
 	klog.V(5).Infof("%s -> deleting policy routing rule for destination {%s} with table ID {%d}",
@@ -285,3 +311,33 @@ func (vrm *VxlanRoutingManager) removePRRForIncomingTraffic(srcPodCIDR, srcExter
 
 	return configured, nil
 }
+
+// hostsGatewayReplica returns whether the current node hosts the gateway, or one of its replicas in case of hot-standby mode.
+func (vrm *VxlanRoutingManager) hostsGatewayReplica(tep *netv1alpha1.TunnelEndpoint) bool {
+	if tep.Status.GatewayIP == vrm.podIP {
+		return true
+	}
+	for i := range tep.Status.Replicas {
+		if tep.Status.Replicas[i].GatewayIP == vrm.podIP {
+			return true
+		}
+	}
+	return false
+}
+
+// getOverlayGatewayIP returns the overlay IP of the gateway, of the same family of the given destination network.
+func getOverlayGatewayIP(gatewayIP, dstNet string) string {
+	if liqonetutils.IsIPv6CIDR(dstNet) {
+		return liqonetutils.GetOverlayIPv6(gatewayIP)
+	}
+	return liqonetutils.GetOverlayIP(gatewayIP)
+}
+
+// getVethGatewayIP returns the IP of the veth living in the gateway network namespace,
+// of the same family of the given destination network.
+func getVethGatewayIP(vethIP, dstNet string) string {
+	if liqonetutils.IsIPv6CIDR(dstNet) {
+		return liqoconst.GatewayVethIPv6Addr
+	}
+	return vethIP
+}
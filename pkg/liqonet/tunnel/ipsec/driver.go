@@ -0,0 +1,698 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"context"
+	"crypto/ecdh"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	discv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqonet/conncheck"
+	"github.com/liqotech/liqo/pkg/liqonet/tunnel"
+	"github.com/liqotech/liqo/pkg/liqonet/tunnel/metrics"
+	"github.com/liqotech/liqo/pkg/liqonet/tunnel/resolver"
+	liqonetutils "github.com/liqotech/liqo/pkg/liqonet/utils"
+)
+
+// Registering the driver as available.
+func init() {
+	tunnel.AddDriver(liqoconst.IPsecDriverName, NewDriver)
+}
+
+// ResolverFunc type of function that knows how to resolve an ip address belonging to
+// ipv4 or ipv6 family.
+type ResolverFunc func(address string) (*net.IPAddr, error)
+
+type ipsecConfig struct {
+	// listening port.
+	port int
+	// private key.
+	priKey *ecdh.PrivateKey
+	// iFaceMTU mtu of the xfrm interface.
+	iFaceMTU int
+}
+
+// connection contains the information about the tunnel towards a remote cluster.
+type connection struct {
+	*netv1alpha1.Connection
+	clusterIdentity discv1alpha1.ClusterIdentity
+	peer            *tunnelPeer
+	// rekeyed is the configuration derived with a renewed key pair, whose inbound state only is installed,
+	// until the peer starts using it (i.e., it observed the new public key).
+	rekeyed *tunnelPeer
+}
+
+// rekeyCheckInterval is the interval between the checks of whether the peers started using the renewed keys.
+const rekeyCheckInterval = time.Second
+
+// IPsec a wrapper for the xfrm interface and the associated security associations.
+type IPsec struct {
+	metrics.Metrics
+	// connections key is a clusterID.
+	connections      map[string]*connection
+	connectionsMutex sync.RWMutex
+	// handle is bound to the network namespace the driver is created in, where the
+	// xfrm states and policies live also after the interface is moved elsewhere.
+	handle      *netlink.Handle
+	socket      net.PacketConn
+	link        netlink.Link
+	conf        ipsecConfig
+	Connchecker *conncheck.ConnChecker
+
+	// k8sClient and namespace are used to publish the public key, whenever the key pair is renewed.
+	k8sClient k8s.Interface
+	namespace string
+	// derivedPeers contains the public keys of the peers the security associations have already been derived for,
+	// with the current key pair. A new key pair is generated before deriving them again towards the same peer.
+	derivedPeers map[string]struct{}
+	// stop terminates the switch of the rekeyed connections.
+	stop chan struct{}
+}
+
+// NewDriver creates a new IPsec driver.
+func NewDriver(k8sClient k8s.Interface, namespace string, config tunnel.Config) (tunnel.Driver, error) {
+	var err error
+	d := IPsec{
+		connections:  make(map[string]*connection),
+		k8sClient:    k8sClient,
+		namespace:    namespace,
+		derivedPeers: make(map[string]struct{}),
+		stop:         make(chan struct{}),
+		conf: ipsecConfig{
+			port:     config.ListeningPort,
+			iFaceMTU: config.MTU,
+		},
+	}
+
+	if d.handle, err = netlink.NewHandle(); err != nil {
+		return nil, fmt.Errorf("failed to open netlink handle: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			d.handle.Close()
+		}
+	}()
+
+	// Remove the leftovers of previous executions, which refer to outdated keys.
+	if err = flush(d.handle); err != nil {
+		return nil, err
+	}
+	if err = d.setKeys(k8sClient, namespace); err != nil {
+		return nil, err
+	}
+	if err = d.setXfrmLink(); err != nil {
+		return nil, fmt.Errorf("failed to setup %s link: %w", liqoconst.IPsecDriverName, err)
+	}
+	if d.socket, err = listenEncap(d.conf.port); err != nil {
+		return nil, fmt.Errorf("failed to open the ESP-in-UDP socket on port %d: %w", d.conf.port, err)
+	}
+
+	klog.Infof("created %s interface named %s with publicKey %s", liqoconst.IPsecDriverName, liqoconst.DeviceName,
+		EncodePublicKey(d.conf.priKey.PublicKey()))
+	return &d, nil
+}
+
+// Init initializes the xfrm interface.
+func (d *IPsec) Init() error {
+	// ip link set $DefaultDeviceName up.
+	if err := netlink.LinkSetUp(d.link); err != nil {
+		return fmt.Errorf("failed to bring up IPsec device: %w", err)
+	}
+
+	if err := netlink.LinkSetMTU(d.link, d.conf.iFaceMTU); err != nil {
+		return fmt.Errorf("failed to set MTU for interface %s: %w", liqoconst.DeviceName, err)
+	}
+
+	klog.Infof("%s interface named %s, is up on i/f number %d, listening on port :%d", liqoconst.IPsecDriverName,
+		d.link.Attrs().Name, d.link.Attrs().Index, d.conf.port)
+
+	go wait.Until(d.switchRekeyedPeers, rekeyCheckInterval, d.stop)
+	return nil
+}
+
+// ConnectToEndpoint connects to a remote cluster described by the given tep.
+// updateStatusCallback is a function used by conncheck to update TunnelEndpoint connected status.
+func (d *IPsec) ConnectToEndpoint(tep *netv1alpha1.TunnelEndpoint, updateStatus conncheck.UpdateFunc) (*netv1alpha1.Connection, error) {
+	// parse remote CIDRs.
+	cidrs, stringCIDRs, err := getRemoteCIDRs(tep)
+	if err != nil {
+		return newConnectionOnError(err.Error()), err
+	}
+
+	// parse remote public key.
+	remoteKey, err := getKey(tep)
+	if err != nil {
+		return newConnectionOnError(err.Error()), err
+	}
+
+	// parse remote endpoint.
+	endpoint, err := getEndpoint(tep, func(address string) (*net.IPAddr, error) {
+		return resolver.Resolve(context.TODO(), address)
+	})
+	if err != nil {
+		return newConnectionOnError(err.Error()), err
+	}
+
+	clusterID := tep.Spec.ClusterIdentity.ClusterID
+	d.connectionsMutex.RLock()
+	oldCon, found := d.connections[clusterID]
+	d.connectionsMutex.RUnlock()
+	if found {
+		// check if the peer configuration is updated.
+		if stringCIDRs == oldCon.PeerConfiguration[liqoconst.IPsecRemoteCIDRs] &&
+			EncodePublicKey(remoteKey) == oldCon.PeerConfiguration[liqoconst.PublicKey] &&
+			endpoint.IP.String() == oldCon.PeerConfiguration[liqoconst.IPsecEndpointIP] &&
+			strconv.Itoa(endpoint.Port) == oldCon.PeerConfiguration[liqoconst.ListeningPort] {
+			// Update connection status.
+			return &tep.Status.Connection, nil
+		}
+		// If the configuration has changed then remove the peer.
+		klog.V(4).Infof("updating peer configuration for cluster %s", tep.Spec.ClusterIdentity)
+		if err = d.removeConnection(clusterID, oldCon); err != nil {
+			return newConnectionOnError(err.Error()), fmt.Errorf("failed to configure peer with cluster %s: %w", tep.Spec.ClusterIdentity, err)
+		}
+	} else {
+		klog.V(4).Infof("Connecting cluster %s endpoint %s with publicKey %s",
+			tep.Spec.ClusterIdentity, endpoint.IP.String(), EncodePublicKey(remoteKey))
+	}
+
+	_, externalCIDR := liqonetutils.GetExternalCIDRS(tep)
+	pingIP, err := liqonetutils.GetTunnelIP(externalCIDR)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the tunnel ip: %w", err)
+	}
+
+	localIP, err := d.getLocalIP(endpoint.IP)
+	if err != nil {
+		return newConnectionOnError(err.Error()), err
+	}
+
+	// Make sure the security associations are never reinstalled with the same keys.
+	if err = d.ensureFreshKeys(remoteKey); err != nil {
+		return newConnectionOnError(err.Error()), err
+	}
+
+	out, in, err := deriveSecurityAssociations(d.conf.priKey, remoteKey)
+	if err != nil {
+		return newConnectionOnError(err.Error()), err
+	}
+
+	peer := &tunnelPeer{
+		localIP:    localIP,
+		localPort:  d.conf.port,
+		remoteIP:   endpoint.IP,
+		remotePort: endpoint.Port,
+		cidrs:      cidrs,
+		out:        out,
+		in:         in,
+	}
+	if err = configurePeer(d.handle, peer); err != nil {
+		// Make sure no partial configuration is left behind.
+		if e := unconfigurePeer(d.handle, peer); e != nil {
+			klog.Errorf("failed to clean up the configuration towards cluster %s: %v", tep.Spec.ClusterIdentity, e)
+		}
+		return newConnectionOnError(err.Error()), fmt.Errorf("failed to configure peer with cluster %s: %w", tep.Spec.ClusterIdentity, err)
+	}
+
+	c := &netv1alpha1.Connection{
+		Status:        netv1alpha1.Connecting,
+		StatusMessage: netv1alpha1.ConnectingMessage,
+		PeerConfiguration: map[string]string{liqoconst.ListeningPort: strconv.Itoa(endpoint.Port), liqoconst.IPsecEndpointIP: endpoint.IP.String(),
+			liqoconst.IPsecRemoteCIDRs: stringCIDRs, liqoconst.PublicKey: EncodePublicKey(remoteKey)},
+		Latency: netv1alpha1.ConnectionLatency{
+			Value:     liqoconst.NotApplicable,
+			Timestamp: metav1.Time{Time: time.Now()},
+		},
+	}
+	d.connectionsMutex.Lock()
+	d.connections[clusterID] = &connection{Connection: c, clusterIdentity: tep.Spec.ClusterIdentity, peer: peer}
+	d.connectionsMutex.Unlock()
+
+	klog.Infof("%s -> starting conncheck sender", tep.Spec.ClusterIdentity)
+
+	go d.Connchecker.AddAndRunSender(clusterID, pingIP, updateStatus)
+
+	klog.V(4).Infof("Done connecting cluster peer %s@%s", tep.Spec.ClusterIdentity, endpoint.String())
+	return c, nil
+}
+
+// DisconnectFromEndpoint disconnects a remote cluster described by the given tep.
+func (d *IPsec) DisconnectFromEndpoint(tep *netv1alpha1.TunnelEndpoint) error {
+	klog.V(4).Infof("Removing connection with cluster %s", tep.Spec.ClusterIdentity)
+
+	clusterID := tep.Spec.ClusterIdentity.ClusterID
+	d.connectionsMutex.RLock()
+	con, found := d.connections[clusterID]
+	d.connectionsMutex.RUnlock()
+	if !found {
+		klog.V(4).Infof("no tunnel configured for cluster %s, nothing to be removed", tep.Spec.ClusterIdentity)
+		return nil
+	}
+
+	if err := d.removeConnection(clusterID, con); err != nil {
+		return fmt.Errorf("failed to remove IPsec peer with cluster %s: %w", tep.Spec.ClusterIdentity, err)
+	}
+
+	klog.V(4).Infof("Done removing IPsec peer with cluster %s", tep.Spec.ClusterIdentity)
+	return nil
+}
+
+// removeConnection removes the xfrm configuration towards the given cluster, and stops the corresponding conncheck sender.
+func (d *IPsec) removeConnection(clusterID string, con *connection) error {
+	d.Connchecker.DelAndStopSender(clusterID)
+
+	d.connectionsMutex.Lock()
+	defer d.connectionsMutex.Unlock()
+
+	if err := unconfigurePeer(d.handle, con.peer); err != nil {
+		return err
+	}
+	if con.rekeyed != nil {
+		if err := removeInboundState(d.handle, con.rekeyed); err != nil {
+			return err
+		}
+	}
+
+	delete(d.connections, clusterID)
+	return nil
+}
+
+// ensureFreshKeys renews the local key pair in case the security associations towards the given peer have already
+// been derived with the current one. Indeed, their reinstallation restarts the ESP sequence numbers from zero, which
+// would lead to the reuse of the AES-GCM nonces under the same key. The existing connections keep their security
+// associations, until the corresponding peers start using the ones derived with the new key pair.
+func (d *IPsec) ensureFreshKeys(remote *ecdh.PublicKey) error {
+	encoded := EncodePublicKey(remote)
+
+	d.connectionsMutex.Lock()
+	if _, found := d.derivedPeers[encoded]; !found {
+		d.derivedPeers[encoded] = struct{}{}
+		d.connectionsMutex.Unlock()
+		return nil
+	}
+	d.connectionsMutex.Unlock()
+
+	klog.Infof("renewing the %s key pair, as the security associations towards peer %s have already been derived", liqoconst.IPsecDriverName, encoded)
+	priv, err := GeneratePrivateKey()
+	if err != nil {
+		return fmt.Errorf("failed to renew the key pair: %w", err)
+	}
+	// The public key is published without holding the lock, not to block the other operations on the API server.
+	if err := publishPublicKey(d.k8sClient, d.namespace, EncodePublicKey(priv.PublicKey())); err != nil {
+		return fmt.Errorf("failed to renew the key pair: %w", err)
+	}
+
+	d.connectionsMutex.Lock()
+	defer d.connectionsMutex.Unlock()
+
+	d.conf.priKey = priv
+	d.derivedPeers = map[string]struct{}{encoded: {}}
+	for _, con := range d.connections {
+		if err := d.stageRekey(con); err != nil {
+			return fmt.Errorf("failed to rekey peer with cluster %s: %w", con.clusterIdentity, err)
+		}
+	}
+	return nil
+}
+
+// stageRekey derives the security associations towards the peer of the given connection with the current key pair,
+// and installs the inbound one only. Indeed, the outbound traffic shall keep being protected with the previous keys,
+// until the peer observes the new public key, as it would not be able to decrypt it otherwise.
+// The caller is expected to hold the connections lock.
+func (d *IPsec) stageRekey(con *connection) error {
+	remoteKey, err := ParsePublicKey(con.PeerConfiguration[liqoconst.PublicKey])
+	if err != nil {
+		return fmt.Errorf("failed to parse the public key: %w", err)
+	}
+
+	peer := *con.peer
+	if peer.out, peer.in, err = deriveSecurityAssociations(d.conf.priKey, remoteKey); err != nil {
+		return err
+	}
+
+	// A configuration staged by a previous renewal, and not yet used by the peer, is superseded by the new one.
+	if con.rekeyed != nil {
+		if err := removeInboundState(d.handle, con.rekeyed); err != nil {
+			return err
+		}
+		con.rekeyed = nil
+	}
+	if err := installInboundState(d.handle, &peer); err != nil {
+		return err
+	}
+
+	con.rekeyed = &peer
+	d.derivedPeers[con.PeerConfiguration[liqoconst.PublicKey]] = struct{}{}
+	return nil
+}
+
+// switchRekeyedPeers switches the outbound traffic of the rekeyed connections to the new security associations,
+// as soon as the corresponding peers start using them, which acknowledges that they observed the new public key.
+func (d *IPsec) switchRekeyedPeers() {
+	d.connectionsMutex.Lock()
+	defer d.connectionsMutex.Unlock()
+
+	for _, con := range d.connections {
+		if con.rekeyed == nil {
+			continue
+		}
+
+		used, err := inboundStateUsed(d.handle, con.rekeyed)
+		if err != nil {
+			klog.Warningf("failed to check whether cluster %s started using the renewed keys: %v", con.clusterIdentity, err)
+			continue
+		}
+		if !used {
+			continue
+		}
+
+		if err := switchPeer(d.handle, con.peer, con.rekeyed); err != nil {
+			klog.Errorf("failed to switch to the renewed keys towards cluster %s: %v", con.clusterIdentity, err)
+			continue
+		}
+		klog.Infof("switched to the renewed keys towards cluster %s", con.clusterIdentity)
+		con.peer, con.rekeyed = con.rekeyed, nil
+	}
+}
+
+// GetLink returns the netlink.Link referred to the xfrm interface.
+func (d *IPsec) GetLink() netlink.Link {
+	return d.link
+}
+
+// Close removes the xfrm interface, and the associated states and policies, from the host.
+func (d *IPsec) Close() error {
+	defer d.handle.Close()
+	close(d.stop)
+
+	if err := d.socket.Close(); err != nil {
+		klog.Errorf("failed to close the ESP-in-UDP socket: %v", err)
+	}
+	if err := flush(d.handle); err != nil {
+		return err
+	}
+
+	link, err := netlink.LinkByName(liqoconst.DeviceName)
+	if err != nil {
+		var notFound netlink.LinkNotFoundError
+		if errors.As(err, &notFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to retrieve existing IPsec device: %w", err)
+	}
+	if err := netlink.LinkDel(link); err != nil {
+		return fmt.Errorf("failed to delete existing IPsec device: %w", err)
+	}
+	return nil
+}
+
+// Create new xfrm link.
+func (d *IPsec) setXfrmLink() error {
+	// delete existing device if needed.
+	if link, err := netlink.LinkByName(liqoconst.DeviceName); err == nil {
+		if err := netlink.LinkDel(link); err != nil {
+			return fmt.Errorf("failed to delete existing IPsec device: %w", err)
+		}
+	}
+
+	// create the xfrm device (ip link add dev $DefaultDeviceName type xfrm if_id $ifID).
+	la := netlink.NewLinkAttrs()
+	la.Name = liqoconst.DeviceName
+	la.MTU = d.conf.iFaceMTU
+	link := &netlink.Xfrmi{
+		LinkAttrs: la,
+		Ifid:      ifID,
+	}
+	if err := netlink.LinkAdd(link); err != nil {
+		return fmt.Errorf("failed to add xfrm device %q: %w", liqoconst.DeviceName, err)
+	}
+	d.link = link
+	return nil
+}
+
+// getLocalIP returns the local address used to reach the given remote endpoint.
+func (d *IPsec) getLocalIP(remote net.IP) (net.IP, error) {
+	routes, err := d.handle.RouteGet(remote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the route towards %s: %w", remote, err)
+	}
+	for i := range routes {
+		if routes[i].Src != nil {
+			return routes[i].Src, nil
+		}
+	}
+	return nil, fmt.Errorf("no source address found to reach %s", remote)
+}
+
+// listenEncap opens the UDP socket receiving the ESP-in-UDP packets, which are then consumed by the kernel.
+func listenEncap(port int) (net.PacketConn, error) {
+	lc := net.ListenConfig{Control: func(_, _ string, c syscall.RawConn) error {
+		var serr error
+		if err := c.Control(func(fd uintptr) {
+			serr = unix.SetsockoptInt(int(fd), unix.IPPROTO_UDP, unix.UDP_ENCAP, unix.UDP_ENCAP_ESPINUDP)
+		}); err != nil {
+			return err
+		}
+		return serr
+	}}
+	return lc.ListenPacket(context.Background(), "udp", fmt.Sprintf(":%d", port))
+}
+
+// Function that receives a TunnelEndpoint resource and extracts the remote CIDRs
+// to be protected by the tunnel. They are returned as []*net.IPNet and
+// as a string (to accommodate comparison/storing on TEP resource).
+func getRemoteCIDRs(tep *netv1alpha1.TunnelEndpoint) ([]*net.IPNet, string, error) {
+	var remoteCIDRs []*net.IPNet
+	var cidrs []string
+	// In case of dual-stack peerings, the IPv6 networks of the remote cluster are protected as well.
+	for _, t := range liqonetutils.GetTunnelEndpointsPerFamily(tep) {
+		_, remotePodCIDR := liqonetutils.GetPodCIDRS(t)
+		_, remoteExternalCIDR := liqonetutils.GetExternalCIDRS(t)
+
+		_, podCIDR, err := net.ParseCIDR(remotePodCIDR)
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to parse podCIDR %s for cluster %s: %w", remotePodCIDR, tep.Spec.ClusterIdentity, err)
+		}
+		_, externalCIDR, err := net.ParseCIDR(remoteExternalCIDR)
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to parse externalCIDR %s for cluster %s: %w", remoteExternalCIDR, tep.Spec.ClusterIdentity, err)
+		}
+		remoteCIDRs = append(remoteCIDRs, podCIDR, externalCIDR)
+		cidrs = append(cidrs, remotePodCIDR, remoteExternalCIDR)
+	}
+	// The networks reachable through the remote cluster, acting as a transit cluster, are protected as well.
+	for i := range tep.Spec.RemoteTransitNetworks {
+		transitPodCIDR := liqonetutils.GetTransitPodCIDR(&tep.Spec.RemoteTransitNetworks[i])
+		_, podCIDR, err := net.ParseCIDR(transitPodCIDR)
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to parse transit podCIDR %s for cluster %s: %w", transitPodCIDR, tep.Spec.ClusterIdentity, err)
+		}
+		remoteCIDRs = append(remoteCIDRs, podCIDR)
+		cidrs = append(cidrs, transitPodCIDR)
+	}
+	return remoteCIDRs, strings.Join(cidrs, ", "), nil
+}
+
+func getKey(tep *netv1alpha1.TunnelEndpoint) (*ecdh.PublicKey, error) {
+	s, found := tep.Spec.BackendConfig[liqoconst.PublicKey]
+	if !found {
+		return nil, fmt.Errorf("endpoint is missing public key")
+	}
+
+	key, err := ParsePublicKey(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key %s: %w", s, err)
+	}
+
+	return key, nil
+}
+
+func getEndpoint(tep *netv1alpha1.TunnelEndpoint, addrResolver ResolverFunc) (*net.UDPAddr, error) {
+	// Get tunnel port.
+	tunnelPort, err := getTunnelPortFromTep(tep)
+	if err != nil {
+		return nil, err
+	}
+	// Get tunnel ip.
+	tunnelAddress, err := addrResolver(tep.Spec.EndpointIP)
+	if err != nil {
+		return nil, err
+	}
+	return &net.UDPAddr{
+		IP:   tunnelAddress.IP,
+		Port: tunnelPort,
+	}, nil
+}
+
+func getTunnelPortFromTep(tep *netv1alpha1.TunnelEndpoint) (int, error) {
+	// Get port.
+	port, found := tep.Spec.BackendConfig[liqoconst.ListeningPort]
+	if !found {
+		return 0, fmt.Errorf("port not found in BackendConfig map using key {%s}", liqoconst.ListeningPort)
+	}
+	// Convert port from string to int.
+	tunnelPort, err := strconv.ParseInt(port, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse port {%s} to int: %w", port, err)
+	}
+	// If port is not in the correct range, then return an error.
+	if tunnelPort < liqoconst.UDPMinPort || tunnelPort > liqoconst.UDPMaxPort {
+		return 0, fmt.Errorf("port {%s} should be greater than {%d} and minor than {%d}", port, liqoconst.UDPMinPort, liqoconst.UDPMaxPort)
+	}
+	return int(tunnelPort), nil
+}
+
+func newConnectionOnError(msg string) *netv1alpha1.Connection {
+	return &netv1alpha1.Connection{
+		Status:            netv1alpha1.ConnectionError,
+		StatusMessage:     msg,
+		PeerConfiguration: nil,
+	}
+}
+
+// setKeys generates a new key pair, and publishes the public key through the corresponding secret.
+// Keys are renewed at every restart, so that the security associations are never reused
+// (which would lead to the reuse of the AES-GCM nonces, since sequence numbers restart from zero).
+func (d *IPsec) setKeys(c k8s.Interface, namespace string) error {
+	priv, err := GeneratePrivateKey()
+	if err != nil {
+		return fmt.Errorf("error generating private key for ipsec backend: %w", err)
+	}
+	if err := publishPublicKey(c, namespace, EncodePublicKey(priv.PublicKey())); err != nil {
+		return err
+	}
+	d.conf.priKey = priv
+	return nil
+}
+
+// publishPublicKey publishes the given public key through the corresponding secret, creating it if necessary.
+func publishPublicKey(c k8s.Interface, namespace, pub string) error {
+	s, err := c.CoreV1().Secrets(namespace).Get(context.Background(), liqoconst.IPsecKeysName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	// if the secret does not exist then it is created.
+	if apierrors.IsNotFound(err) {
+		pKey := corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      liqoconst.IPsecKeysName,
+				Namespace: namespace,
+				Labels:    map[string]string{liqoconst.KeysLabel: liqoconst.IPsecDriverName},
+			},
+			StringData: map[string]string{liqoconst.PublicKey: pub},
+		}
+		if _, err = c.CoreV1().Secrets(namespace).Create(context.Background(), &pKey, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create the secret with name %s: %w", liqoconst.IPsecKeysName, err)
+		}
+		return nil
+	}
+	// otherwise, the public key is replaced.
+	if s.Data == nil {
+		s.Data = map[string][]byte{}
+	}
+	s.Data[liqoconst.PublicKey] = []byte(pub)
+	if _, err = c.CoreV1().Secrets(namespace).Update(context.Background(), s, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update the secret with name %s: %w", liqoconst.IPsecKeysName, err)
+	}
+	return nil
+}
+
+// Describe implements prometheus.Collector.
+func (d *IPsec) Describe(ch chan<- *prometheus.Desc) {
+	d.Metrics.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (d *IPsec) Collect(ch chan<- prometheus.Metric) {
+	d.connectionsMutex.RLock()
+	defer d.connectionsMutex.RUnlock()
+
+	for _, con := range d.connections {
+		labels := []string{
+			liqoconst.IPsecDriverName, liqoconst.DeviceName,
+			con.clusterIdentity.ClusterID,
+			con.clusterIdentity.ClusterName,
+		}
+
+		connected, err := d.Connchecker.GetConnected(con.clusterIdentity.ClusterID)
+		if err != nil {
+			ch <- prometheus.NewInvalidMetric(metrics.PeerIsConnected, err)
+		} else {
+			var result float64
+			if connected {
+				result = 1
+			}
+			ch <- prometheus.MustNewConstMetric(
+				metrics.PeerIsConnected,
+				prometheus.GaugeValue,
+				result,
+				labels...,
+			)
+		}
+
+		if connected {
+			out, in := con.peer.states()
+			d.collectStateBytes(ch, metrics.PeerReceivedBytes, in, labels)
+			d.collectStateBytes(ch, metrics.PeerTransmittedBytes, out, labels)
+
+			latency, err := d.Connchecker.GetLatency(con.clusterIdentity.ClusterID)
+			if err != nil {
+				ch <- prometheus.NewInvalidMetric(metrics.PeerLatency, err)
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(
+				metrics.PeerLatency,
+				prometheus.GaugeValue,
+				float64(latency.Microseconds()),
+				labels...,
+			)
+		}
+	}
+}
+
+// collectStateBytes emits the number of bytes processed by the given xfrm state.
+func (d *IPsec) collectStateBytes(ch chan<- prometheus.Metric, desc *prometheus.Desc, state *netlink.XfrmState, labels []string) {
+	current, err := d.handle.XfrmStateGet(state)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(desc, fmt.Errorf("error collecting ipsec metrics: %w", err))
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(
+		desc,
+		prometheus.CounterValue,
+		float64(current.Statistics.Bytes),
+		labels...,
+	)
+}
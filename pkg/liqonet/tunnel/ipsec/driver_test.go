@@ -0,0 +1,372 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"context"
+	"crypto/ecdh"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	liqonetns "github.com/liqotech/liqo/pkg/liqonet/netns"
+)
+
+const (
+	ipv4Literal = "10.1.1.1"
+	ipv4Dns     = "ipv4.liqodns.resolver"
+	namespace   = "liqo"
+	netnsName   = "liqo-ipsec-test"
+)
+
+func addressResolverMock(address string) (*net.IPAddr, error) {
+	if address == ipv4Literal || address == ipv4Dns {
+		return &net.IPAddr{IP: net.ParseIP(ipv4Literal)}, nil
+	}
+	return nil, fmt.Errorf("ip not found")
+}
+
+var _ = Describe("Driver", func() {
+	var tep *netv1alpha1.TunnelEndpoint
+
+	BeforeEach(func() {
+		tep = &netv1alpha1.TunnelEndpoint{
+			Spec: netv1alpha1.TunnelEndpointSpec{
+				EndpointIP:            ipv4Dns,
+				BackendType:           liqoconst.IPsecDriverName,
+				BackendConfig:         map[string]string{liqoconst.ListeningPort: "5871"},
+				RemotePodCIDR:         "10.200.0.0/16",
+				RemoteNATPodCIDR:      liqoconst.DefaultCIDRValue,
+				RemoteExternalCIDR:    "10.201.0.0/16",
+				RemoteNATExternalCIDR: liqoconst.DefaultCIDRValue,
+			},
+		}
+	})
+
+	DescribeTable("testing getTunnelPortFromTep",
+		func(port string, expected int, shouldFail bool) {
+			tep.Spec.BackendConfig[liqoconst.ListeningPort] = port
+			result, err := getTunnelPortFromTep(tep)
+			if shouldFail {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).ToNot(HaveOccurred())
+			}
+			Expect(result).To(Equal(expected))
+		},
+		Entry("port within range", "55555", 55555, false),
+		Entry("port < than min acceptable value", "0", 0, true),
+		Entry("port > than max acceptable value", "65536", 0, true),
+		Entry("port is not a valid number", "notANumber", 0, true),
+	)
+
+	Describe("testing getEndpoint", func() {
+		It("should return the resolved endpoint", func() {
+			endpoint, err := getEndpoint(tep, addressResolverMock)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(endpoint.IP.String()).To(Equal(ipv4Literal))
+			Expect(endpoint.Port).To(Equal(5871))
+		})
+
+		It("should fail if the address cannot be resolved", func() {
+			tep.Spec.EndpointIP = "notExisting"
+			_, err := getEndpoint(tep, addressResolverMock)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("testing getRemoteCIDRs", func() {
+		It("should return the remote CIDRs", func() {
+			cidrs, str, err := getRemoteCIDRs(tep)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cidrs).To(HaveLen(2))
+			Expect(cidrs[0].String()).To(Equal("10.200.0.0/16"))
+			Expect(cidrs[1].String()).To(Equal("10.201.0.0/16"))
+			Expect(str).To(Equal("10.200.0.0/16, 10.201.0.0/16"))
+		})
+
+		It("should prefer the remapped CIDRs, if set", func() {
+			tep.Spec.RemoteNATPodCIDR = "10.202.0.0/16"
+			tep.Spec.RemoteNATExternalCIDR = "10.203.0.0/16"
+			_, str, err := getRemoteCIDRs(tep)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(str).To(Equal("10.202.0.0/16, 10.203.0.0/16"))
+		})
+
+		It("should include the IPv6 CIDRs, in case of dual-stack peerings", func() {
+			tep.Spec.LocalPodCIDRv6 = "fd00:1::/112"
+			tep.Spec.RemotePodCIDRv6 = "fd00:2::/112"
+			tep.Spec.RemoteExternalCIDRv6 = "fd00:3::/112"
+			cidrs, str, err := getRemoteCIDRs(tep)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cidrs).To(HaveLen(4))
+			Expect(str).To(Equal("10.200.0.0/16, 10.201.0.0/16, fd00:2::/112, fd00:3::/112"))
+		})
+
+		It("should include the networks reachable through the remote transit cluster", func() {
+			tep.Spec.RemoteTransitNetworks = []netv1alpha1.TransitNetworkMapping{
+				{PodCIDR: "10.210.0.0/16", NATPodCIDR: liqoconst.DefaultCIDRValue},
+				{PodCIDR: "10.200.0.0/16", NATPodCIDR: "10.211.0.0/16"},
+			}
+			cidrs, str, err := getRemoteCIDRs(tep)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cidrs).To(HaveLen(4))
+			Expect(str).To(Equal("10.200.0.0/16, 10.201.0.0/16, 10.210.0.0/16, 10.211.0.0/16"))
+		})
+
+		It("should fail if a CIDR is invalid", func() {
+			tep.Spec.RemotePodCIDR = "invalid"
+			_, _, err := getRemoteCIDRs(tep)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("testing getKey", func() {
+		It("should fail if the key is missing", func() {
+			_, err := getKey(tep)
+			Expect(err).To(MatchError("endpoint is missing public key"))
+		})
+
+		It("should return the parsed key", func() {
+			priv, err := GeneratePrivateKey()
+			Expect(err).ToNot(HaveOccurred())
+			tep.Spec.BackendConfig[liqoconst.PublicKey] = EncodePublicKey(priv.PublicKey())
+			key, err := getKey(tep)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(key.Equal(priv.PublicKey())).To(BeTrue())
+		})
+	})
+
+	Describe("testing setKeys", func() {
+		var (
+			client *fake.Clientset
+			driver IPsec
+		)
+
+		BeforeEach(func() {
+			client = fake.NewSimpleClientset()
+			driver = IPsec{}
+		})
+
+		getPublicKey := func() string {
+			secret, err := client.CoreV1().Secrets(namespace).Get(context.Background(), liqoconst.IPsecKeysName, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(secret.Labels).To(HaveKeyWithValue(liqoconst.KeysLabel, liqoconst.IPsecDriverName))
+			if key, found := secret.StringData[liqoconst.PublicKey]; found {
+				return key
+			}
+			return string(secret.Data[liqoconst.PublicKey])
+		}
+
+		It("should create the secret with the public key", func() {
+			Expect(driver.setKeys(client, namespace)).To(Succeed())
+			Expect(getPublicKey()).To(Equal(EncodePublicKey(driver.conf.priKey.PublicKey())))
+		})
+
+		It("should renew the key pair if the secret already exists", func() {
+			Expect(driver.setKeys(client, namespace)).To(Succeed())
+			previous := driver.conf.priKey
+			secret, err := client.CoreV1().Secrets(namespace).Get(context.Background(), liqoconst.IPsecKeysName, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			secret.StringData = nil
+			secret.Data = map[string][]byte{liqoconst.PublicKey: []byte(EncodePublicKey(previous.PublicKey()))}
+			_, err = client.CoreV1().Secrets(namespace).Update(context.Background(), secret, metav1.UpdateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(driver.setKeys(client, namespace)).To(Succeed())
+			Expect(driver.conf.priKey.Equal(previous)).To(BeFalse())
+			Expect(getPublicKey()).To(Equal(EncodePublicKey(driver.conf.priKey.PublicKey())))
+		})
+	})
+
+	Describe("testing ensureFreshKeys", func() {
+		var (
+			client      *fake.Clientset
+			driver      IPsec
+			first, next *ecdh.PublicKey
+		)
+
+		BeforeEach(func() {
+			client = fake.NewSimpleClientset()
+			driver = IPsec{connections: map[string]*connection{}, k8sClient: client, namespace: namespace, derivedPeers: map[string]struct{}{}}
+			Expect(driver.setKeys(client, namespace)).To(Succeed())
+
+			priv, err := GeneratePrivateKey()
+			Expect(err).ToNot(HaveOccurred())
+			first = priv.PublicKey()
+			priv, err = GeneratePrivateKey()
+			Expect(err).ToNot(HaveOccurred())
+			next = priv.PublicKey()
+		})
+
+		It("should preserve the key pair when deriving the security associations towards new peers", func() {
+			previous := driver.conf.priKey
+			Expect(driver.ensureFreshKeys(first)).To(Succeed())
+			Expect(driver.ensureFreshKeys(next)).To(Succeed())
+			Expect(driver.conf.priKey.Equal(previous)).To(BeTrue())
+		})
+
+		It("should renew the key pair when deriving again the security associations towards the same peer", func() {
+			previous := driver.conf.priKey
+			Expect(driver.ensureFreshKeys(first)).To(Succeed())
+			Expect(driver.ensureFreshKeys(first)).To(Succeed())
+			Expect(driver.conf.priKey.Equal(previous)).To(BeFalse())
+
+			// The security associations derived with the new key pair are different from the previous ones.
+			outPrev, _, err := deriveSecurityAssociations(previous, first)
+			Expect(err).ToNot(HaveOccurred())
+			outNext, _, err := deriveSecurityAssociations(driver.conf.priKey, first)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(outNext.key).ToNot(Equal(outPrev.key))
+
+			// The peers are tracked again from scratch with the new key pair.
+			renewed := driver.conf.priKey
+			Expect(driver.ensureFreshKeys(next)).To(Succeed())
+			Expect(driver.conf.priKey.Equal(renewed)).To(BeTrue())
+		})
+	})
+
+	Describe("testing the xfrm configuration", func() {
+		var (
+			netNamespace ns.NetNS
+			peer         *tunnelPeer
+		)
+
+		BeforeEach(func() {
+			var err error
+			netNamespace, err = liqonetns.CreateNetns(netnsName)
+			Expect(err).ToNot(HaveOccurred())
+
+			local, err := GeneratePrivateKey()
+			Expect(err).ToNot(HaveOccurred())
+			remote, err := GeneratePrivateKey()
+			Expect(err).ToNot(HaveOccurred())
+			out, in, err := deriveSecurityAssociations(local, remote.PublicKey())
+			Expect(err).ToNot(HaveOccurred())
+
+			_, podCIDR, _ := net.ParseCIDR("10.200.0.0/16")
+			_, externalCIDR, _ := net.ParseCIDR("10.201.0.0/16")
+			peer = &tunnelPeer{
+				localIP: net.ParseIP("192.168.0.1"), localPort: 5871,
+				remoteIP: net.ParseIP("192.168.0.2"), remotePort: 30000,
+				cidrs: []*net.IPNet{podCIDR, externalCIDR},
+				out:   out, in: in,
+			}
+		})
+
+		AfterEach(func() {
+			Expect(netNamespace.Close()).To(Succeed())
+			Expect(liqonetns.DeleteNetns(netnsName)).To(Succeed())
+		})
+
+		// withHandle executes the given function with a netlink handle bound to the test network namespace.
+		withHandle := func(fn func(h *netlink.Handle)) {
+			Expect(netNamespace.Do(func(ns.NetNS) error {
+				defer GinkgoRecover()
+				h, err := netlink.NewHandle()
+				Expect(err).ToNot(HaveOccurred())
+				defer h.Close()
+				fn(h)
+				return nil
+			})).To(Succeed())
+		}
+
+		// configure installs the xfrm configuration, skipping the test if ESP is not supported by the running kernel.
+		configure := func(h *netlink.Handle) {
+			err := configurePeer(h, peer)
+			if errors.Is(err, unix.ENOSYS) || errors.Is(err, unix.EPROTONOSUPPORT) {
+				Skip(fmt.Sprintf("xfrm not supported by the running kernel: %v", err))
+			}
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		It("should install and remove the states and policies towards the peer", func() {
+			withHandle(func(h *netlink.Handle) {
+				configure(h)
+
+				states, err := h.XfrmStateList(netlink.FAMILY_ALL)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(states).To(HaveLen(2))
+				for i := range states {
+					Expect(states[i].Ifid).To(Equal(ifID))
+					Expect(states[i].Aead.Name).To(Equal(aeadAlgorithm))
+					Expect(states[i].Encap).ToNot(BeNil())
+				}
+
+				policies, err := h.XfrmPolicyList(netlink.FAMILY_ALL)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(policies).To(HaveLen(4))
+
+				Expect(unconfigurePeer(h, peer)).To(Succeed())
+				Expect(h.XfrmStateList(netlink.FAMILY_ALL)).To(BeEmpty())
+				Expect(h.XfrmPolicyList(netlink.FAMILY_ALL)).To(BeEmpty())
+			})
+		})
+
+		It("should switch the outbound traffic to the staged configuration", func() {
+			withHandle(func(h *netlink.Handle) {
+				configure(h)
+
+				local, err := GeneratePrivateKey()
+				Expect(err).ToNot(HaveOccurred())
+				remote, err := GeneratePrivateKey()
+				Expect(err).ToNot(HaveOccurred())
+				next := *peer
+				next.out, next.in, err = deriveSecurityAssociations(local, remote.PublicKey())
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(installInboundState(h, &next)).To(Succeed())
+				Expect(h.XfrmStateList(netlink.FAMILY_ALL)).To(HaveLen(3))
+				Expect(inboundStateUsed(h, &next)).To(BeFalse())
+
+				Expect(switchPeer(h, peer, &next)).To(Succeed())
+				states, err := h.XfrmStateList(netlink.FAMILY_ALL)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(states).To(HaveLen(2))
+				for i := range states {
+					Expect(states[i].Spi).To(BeElementOf(next.out.spi, next.in.spi))
+				}
+
+				policies, err := h.XfrmPolicyList(netlink.FAMILY_ALL)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(policies).To(HaveLen(4))
+				for i := range policies {
+					if policies[i].Dir == netlink.XFRM_DIR_OUT {
+						Expect(policies[i].Tmpls[0].Spi).To(Equal(next.out.spi))
+					}
+				}
+			})
+		})
+
+		It("should flush the states and policies bound to the tunnel interface", func() {
+			withHandle(func(h *netlink.Handle) {
+				configure(h)
+				Expect(flush(h)).To(Succeed())
+				Expect(h.XfrmStateList(netlink.FAMILY_ALL)).To(BeEmpty())
+				Expect(h.XfrmPolicyList(netlink.FAMILY_ALL)).To(BeEmpty())
+			})
+		})
+	})
+})
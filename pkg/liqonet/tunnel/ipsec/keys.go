@@ -0,0 +1,102 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// keyLen is the length of the AES-256 key used by the security associations.
+	keyLen = 32
+	// saltLen is the length of the salt required by the rfc4106 AEAD construction.
+	saltLen = 4
+	// spiLen is the length of the security parameter index.
+	spiLen = 4
+	// derivationLabel is the label used to bind the derived material to its purpose.
+	derivationLabel = "liqo.io ipsec sa"
+)
+
+// securityAssociation contains the parameters of a unidirectional security association.
+type securityAssociation struct {
+	spi int
+	// key contains the AES key followed by the salt.
+	key []byte
+}
+
+// GeneratePrivateKey generates a new P-256 private key, used to negotiate the security associations.
+func GeneratePrivateKey() (*ecdh.PrivateKey, error) {
+	return ecdh.P256().GenerateKey(rand.Reader)
+}
+
+// EncodePublicKey returns the string representation of the given public key.
+func EncodePublicKey(key *ecdh.PublicKey) string {
+	return base64.StdEncoding.EncodeToString(key.Bytes())
+}
+
+// ParsePublicKey parses the string representation of a P-256 public key.
+func ParsePublicKey(key string) (*ecdh.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode public key: %w", err)
+	}
+	pub, err := ecdh.P256().NewPublicKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	return pub, nil
+}
+
+// deriveSecurityAssociations derives the outbound and inbound security associations towards the given peer.
+// Both peers derive the same material, since the outbound association of one side is the inbound of the other.
+func deriveSecurityAssociations(local *ecdh.PrivateKey, remote *ecdh.PublicKey) (out, in *securityAssociation, err error) {
+	secret, err := local.ECDH(remote)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compute the shared secret: %w", err)
+	}
+
+	if out, err = deriveSecurityAssociation(secret, local.PublicKey(), remote); err != nil {
+		return nil, nil, err
+	}
+	if in, err = deriveSecurityAssociation(secret, remote, local.PublicKey()); err != nil {
+		return nil, nil, err
+	}
+	return out, in, nil
+}
+
+// deriveSecurityAssociation derives, through HKDF-SHA256, the parameters of the association from src to dst.
+func deriveSecurityAssociation(secret []byte, src, dst *ecdh.PublicKey) (*securityAssociation, error) {
+	info := append([]byte(derivationLabel), src.Bytes()...)
+	info = append(info, dst.Bytes()...)
+
+	material := make([]byte, spiLen+keyLen+saltLen)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, nil, info), material); err != nil {
+		return nil, fmt.Errorf("failed to derive the security association: %w", err)
+	}
+
+	return &securityAssociation{
+		// The most significant bit is set to stay clear of the SPI values reserved by IANA (1-255).
+		spi: int(binary.BigEndian.Uint32(material[:spiLen]) | 1<<31),
+		key: material[spiLen:],
+	}, nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Keys", func() {
+	Describe("testing the public key encoding", func() {
+		It("should correctly parse an encoded key", func() {
+			priv, err := GeneratePrivateKey()
+			Expect(err).ToNot(HaveOccurred())
+			pub, err := ParsePublicKey(EncodePublicKey(priv.PublicKey()))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pub.Equal(priv.PublicKey())).To(BeTrue())
+		})
+
+		It("should fail if the key is not base64 encoded", func() {
+			_, err := ParsePublicKey("not-a-valid-key!")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail if the key is not a valid P-256 point", func() {
+			_, err := ParsePublicKey("Zm9vYmFy")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("testing deriveSecurityAssociations", func() {
+		var (
+			outA, inA, outB, inB *securityAssociation
+		)
+
+		BeforeEach(func() {
+			privA, err := GeneratePrivateKey()
+			Expect(err).ToNot(HaveOccurred())
+			privB, err := GeneratePrivateKey()
+			Expect(err).ToNot(HaveOccurred())
+
+			outA, inA, err = deriveSecurityAssociations(privA, privB.PublicKey())
+			Expect(err).ToNot(HaveOccurred())
+			outB, inB, err = deriveSecurityAssociations(privB, privA.PublicKey())
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should match the outbound association of a peer with the inbound one of the other", func() {
+			Expect(outA).To(Equal(inB))
+			Expect(outB).To(Equal(inA))
+		})
+
+		It("should derive different associations for the two directions", func() {
+			Expect(outA.spi).ToNot(Equal(inA.spi))
+			Expect(outA.key).ToNot(Equal(inA.key))
+		})
+
+		It("should derive keys of the correct length", func() {
+			Expect(outA.key).To(HaveLen(keyLen + saltLen))
+		})
+
+		It("should derive SPIs outside of the reserved range", func() {
+			Expect(outA.spi).To(BeNumerically(">", 255))
+			Expect(inA.spi).To(BeNumerically(">", 255))
+		})
+	})
+})
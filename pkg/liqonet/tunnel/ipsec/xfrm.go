@@ -0,0 +1,223 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// ifID is the identifier binding the XFRM states and policies to the liqo tunnel interface.
+	ifID = 0x6c71
+	// aeadAlgorithm is the FIPS approved AEAD algorithm used to protect the traffic.
+	aeadAlgorithm = "rfc4106(gcm(aes))"
+	// aeadICVLen is the length (in bits) of the integrity check value.
+	aeadICVLen = 128
+	// replayWindow is the size (in packets) of the anti-replay window.
+	replayWindow = 128
+)
+
+// tunnelPeer contains the parameters of the tunnel towards a remote cluster.
+type tunnelPeer struct {
+	localIP    net.IP
+	localPort  int
+	remoteIP   net.IP
+	remotePort int
+	cidrs      []*net.IPNet
+	out, in    *securityAssociation
+}
+
+// states returns the outbound and inbound XFRM states towards the given peer.
+func (p *tunnelPeer) states() (out, in *netlink.XfrmState) {
+	out = forgeState(p.localIP, p.remoteIP, p.localPort, p.remotePort, p.out)
+	in = forgeState(p.remoteIP, p.localIP, p.remotePort, p.localPort, p.in)
+	return out, in
+}
+
+// policies returns the outbound and inbound XFRM policies towards the given peer.
+func (p *tunnelPeer) policies() []*netlink.XfrmPolicy {
+	var wildcard *net.IPNet
+	if p.remoteIP.To4() != nil {
+		wildcard = &net.IPNet{IP: net.IPv4zero, Mask: net.CIDRMask(0, 32)}
+	} else {
+		wildcard = &net.IPNet{IP: net.IPv6zero, Mask: net.CIDRMask(0, 128)}
+	}
+
+	// The inbound templates do not pin the SPI, so that the traffic protected by an inbound state staged in view of a key
+	// renewal is accepted as well, while the current one is still installed.
+	policies := make([]*netlink.XfrmPolicy, 0, 2*len(p.cidrs))
+	for _, cidr := range p.cidrs {
+		policies = append(policies,
+			forgePolicy(wildcard, cidr, netlink.XFRM_DIR_OUT, p.localIP, p.remoteIP, p.out.spi),
+			forgePolicy(cidr, wildcard, netlink.XFRM_DIR_IN, p.remoteIP, p.localIP, 0))
+	}
+	return policies
+}
+
+func forgeState(src, dst net.IP, srcPort, dstPort int, sa *securityAssociation) *netlink.XfrmState {
+	return &netlink.XfrmState{
+		Src:          src,
+		Dst:          dst,
+		Proto:        netlink.XFRM_PROTO_ESP,
+		Mode:         netlink.XFRM_MODE_TUNNEL,
+		Spi:          sa.spi,
+		Ifid:         ifID,
+		ReplayWindow: replayWindow,
+		ESN:          true,
+		Aead: &netlink.XfrmStateAlgo{
+			Name:   aeadAlgorithm,
+			Key:    sa.key,
+			ICVLen: aeadICVLen,
+		},
+		// ESP packets are encapsulated in UDP, to traverse NATs and reuse the gateway service.
+		Encap: &netlink.XfrmStateEncap{
+			Type:    netlink.XFRM_ENCAP_ESPINUDP,
+			SrcPort: srcPort,
+			DstPort: dstPort,
+		},
+	}
+}
+
+func forgePolicy(src, dst *net.IPNet, dir netlink.Dir, tmplSrc, tmplDst net.IP, spi int) *netlink.XfrmPolicy {
+	return &netlink.XfrmPolicy{
+		Src:  src,
+		Dst:  dst,
+		Dir:  dir,
+		Ifid: ifID,
+		Tmpls: []netlink.XfrmPolicyTmpl{{
+			Src:   tmplSrc,
+			Dst:   tmplDst,
+			Proto: netlink.XFRM_PROTO_ESP,
+			Mode:  netlink.XFRM_MODE_TUNNEL,
+			Spi:   spi,
+		}},
+	}
+}
+
+// configurePeer installs the XFRM states and policies towards the given peer.
+func configurePeer(h *netlink.Handle, p *tunnelPeer) error {
+	out, in := p.states()
+	for _, state := range []*netlink.XfrmState{out, in} {
+		if err := h.XfrmStateAdd(state); err != nil {
+			return fmt.Errorf("failed to add xfrm state (spi: 0x%x): %w", state.Spi, err)
+		}
+	}
+	for _, policy := range p.policies() {
+		if err := h.XfrmPolicyUpdate(policy); err != nil {
+			return fmt.Errorf("failed to add xfrm policy (src: %s, dst: %s): %w", policy.Src, policy.Dst, err)
+		}
+	}
+	return nil
+}
+
+// unconfigurePeer removes the XFRM states and policies towards the given peer, ignoring the ones already absent.
+func unconfigurePeer(h *netlink.Handle, p *tunnelPeer) error {
+	for _, policy := range p.policies() {
+		if err := h.XfrmPolicyDel(policy); err != nil && !errors.Is(err, unix.ENOENT) {
+			return fmt.Errorf("failed to delete xfrm policy (src: %s, dst: %s): %w", policy.Src, policy.Dst, err)
+		}
+	}
+	out, in := p.states()
+	for _, state := range []*netlink.XfrmState{out, in} {
+		if err := h.XfrmStateDel(state); err != nil && !errors.Is(err, unix.ESRCH) {
+			return fmt.Errorf("failed to delete xfrm state (spi: 0x%x): %w", state.Spi, err)
+		}
+	}
+	return nil
+}
+
+// installInboundState installs the inbound XFRM state only towards the given peer.
+func installInboundState(h *netlink.Handle, p *tunnelPeer) error {
+	_, in := p.states()
+	if err := h.XfrmStateAdd(in); err != nil {
+		return fmt.Errorf("failed to add xfrm state (spi: 0x%x): %w", in.Spi, err)
+	}
+	return nil
+}
+
+// removeInboundState removes the inbound XFRM state towards the given peer, ignoring it if already absent.
+func removeInboundState(h *netlink.Handle, p *tunnelPeer) error {
+	_, in := p.states()
+	if err := h.XfrmStateDel(in); err != nil && !errors.Is(err, unix.ESRCH) {
+		return fmt.Errorf("failed to delete xfrm state (spi: 0x%x): %w", in.Spi, err)
+	}
+	return nil
+}
+
+// inboundStateUsed returns whether the inbound XFRM state towards the given peer already protected some traffic.
+func inboundStateUsed(h *netlink.Handle, p *tunnelPeer) (bool, error) {
+	_, in := p.states()
+	state, err := h.XfrmStateGet(in)
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve xfrm state (spi: 0x%x): %w", in.Spi, err)
+	}
+	return state.Statistics.Bytes > 0, nil
+}
+
+// switchPeer moves the outbound traffic towards a peer from the current to the next configuration, whose inbound state
+// is expected to be already installed, and then removes the states of the current one.
+func switchPeer(h *netlink.Handle, current, next *tunnelPeer) error {
+	out, _ := next.states()
+	if err := h.XfrmStateAdd(out); err != nil {
+		return fmt.Errorf("failed to add xfrm state (spi: 0x%x): %w", out.Spi, err)
+	}
+	for _, policy := range next.policies() {
+		if err := h.XfrmPolicyUpdate(policy); err != nil {
+			return fmt.Errorf("failed to update xfrm policy (src: %s, dst: %s): %w", policy.Src, policy.Dst, err)
+		}
+	}
+	out, in := current.states()
+	for _, state := range []*netlink.XfrmState{out, in} {
+		if err := h.XfrmStateDel(state); err != nil && !errors.Is(err, unix.ESRCH) {
+			return fmt.Errorf("failed to delete xfrm state (spi: 0x%x): %w", state.Spi, err)
+		}
+	}
+	return nil
+}
+
+// flush removes all the XFRM states and policies bound to the liqo tunnel interface.
+func flush(h *netlink.Handle) error {
+	policies, err := h.XfrmPolicyList(netlink.FAMILY_ALL)
+	if err != nil {
+		return fmt.Errorf("failed to list xfrm policies: %w", err)
+	}
+	for i := range policies {
+		if policies[i].Ifid != ifID {
+			continue
+		}
+		if err := h.XfrmPolicyDel(&policies[i]); err != nil && !errors.Is(err, unix.ENOENT) {
+			return fmt.Errorf("failed to delete xfrm policy (src: %s, dst: %s): %w", policies[i].Src, policies[i].Dst, err)
+		}
+	}
+
+	states, err := h.XfrmStateList(netlink.FAMILY_ALL)
+	if err != nil {
+		return fmt.Errorf("failed to list xfrm states: %w", err)
+	}
+	for i := range states {
+		if states[i].Ifid != ifID {
+			continue
+		}
+		if err := h.XfrmStateDel(&states[i]); err != nil && !errors.Is(err, unix.ESRCH) {
+			return fmt.Errorf("failed to delete xfrm state (spi: 0x%x): %w", states[i].Spi, err)
+		}
+	}
+	return nil
+}
@@ -30,6 +30,26 @@ var (
 	PeerIsConnected *prometheus.Desc
 	// MetricsLabels is the labels that are used for the metrics.
 	MetricsLabels []string
+
+	// ConnectionQualityLabels is the labels that are used for the connection quality metrics.
+	ConnectionQualityLabels = []string{"cluster_id", "cluster_name"}
+	// PeerRTT is the metric that observes the round-trip times towards a given peer, as measured by the connection checker.
+	PeerRTT = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "liqo_peer_rtt_seconds",
+		Help:    "Round-trip time of the pings exchanged with a given peer in seconds.",
+		Buckets: prometheus.ExponentialBuckets(0.0005, 2, 14),
+	}, ConnectionQualityLabels)
+	// PeerJitter is the metric that observes the jitter towards a given peer, computed over the most recent pings.
+	PeerJitter = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "liqo_peer_jitter_seconds",
+		Help:    "Mean variation of the round-trip time of the most recent pings exchanged with a given peer in seconds.",
+		Buckets: prometheus.ExponentialBuckets(0.0001, 2, 14),
+	}, ConnectionQualityLabels)
+	// PeerPacketLoss is the metric that exposes the ratio of pings lost towards a given peer, computed over the most recent ones.
+	PeerPacketLoss = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "liqo_peer_packet_loss_ratio",
+		Help: "Ratio of the most recent pings sent to a given peer which have been lost.",
+	}, ConnectionQualityLabels)
 )
 
 // InitDefaultMetrics initializes the default metrics.
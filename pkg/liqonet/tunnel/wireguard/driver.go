@@ -23,10 +23,12 @@ import (
 	"os"
 	"os/exec"
 	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/containernetworking/plugins/pkg/ns"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/vishvananda/netlink"
 	"golang.org/x/sys/unix"
@@ -53,6 +55,7 @@ import (
 // Registering the driver as available.
 func init() {
 	tunnel.AddDriver(liqoconst.DriverName, NewDriver)
+	tunnel.AddDriver(liqoconst.WgTCPDriverName, NewTCPDriver)
 }
 
 var (
@@ -107,15 +110,48 @@ type Wireguard struct {
 	conf                       wgConfig
 	Connchecker                *conncheck.ConnChecker
 	Implementation             implementation
+	// keysMutex protects the configuration of the private key of the device, which is modified by the key rotation.
+	keysMutex sync.Mutex
+	// stagedPeers key is a clusterID, and the values are the peers configured with the next key of the remote cluster.
+	stagedPeers map[string]*stagedPeer
+	k8sClient   k8s.Interface
+	namespace   string
+	// transport carries the traffic over TCP connections, and it is nil in case the standard UDP transport is used.
+	transport *streamTransport
 }
 
 // NewDriver creates a new WireGuard driver.
 func NewDriver(k8sClient k8s.Interface, namespace string, config tunnel.Config) (tunnel.Driver, error) {
+	return newWireguard(k8sClient, namespace, config)
+}
+
+// NewTCPDriver creates a new WireGuard driver carrying the encrypted traffic over TCP connections (either raw or
+// WebSocket-based), possibly through an HTTP(S) proxy, to traverse the networks filtering UDP traffic.
+func NewTCPDriver(k8sClient k8s.Interface, namespace string, config tunnel.Config) (tunnel.Driver, error) {
+	// The sockets towards WireGuard and the remote gateways shall be created in the current (i.e., host) network namespace.
+	hostNetns, err := ns.GetCurrentNS()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the current network namespace: %w", err)
+	}
+	w, err := newWireguard(k8sClient, namespace, config)
+	if err != nil {
+		return nil, err
+	}
+	if w.transport, err = newStreamTransport(config.ListeningPort, hostNetns); err != nil {
+		return nil, fmt.Errorf("failed to setup %s transport: %w", liqoconst.WgTCPDriverName, err)
+	}
+	return w, nil
+}
+
+func newWireguard(k8sClient k8s.Interface, namespace string, config tunnel.Config) (*Wireguard, error) {
 	ctx, _ := signals.NotifyContextPosix(context.Background(), signals.ShutdownSignals...)
 	var err error
 	w := Wireguard{
 		connections:                make(map[string]*netv1alpha1.Connection),
 		connectedClusterIdentities: make(map[wgtypes.Key]*discv1alpha1.ClusterIdentity),
+		stagedPeers:                make(map[string]*stagedPeer),
+		k8sClient:                  k8sClient,
+		namespace:                  namespace,
 		conf: wgConfig{
 			port:     config.ListeningPort,
 			iFaceMTU: config.MTU,
@@ -164,6 +200,12 @@ func NewDriver(k8sClient k8s.Interface, namespace string, config tunnel.Config)
 
 	klog.Infof("created %s interface named %s with publicKey %s", liqoconst.DriverName, liqoconst.DeviceName, w.conf.pubKey.String())
 
+	if KeyRotationInterval > 0 {
+		go w.runKeyRotation(ctx)
+	}
+	// The keys of the remote clusters might be rotated even if the local rotation is disabled.
+	go wait.UntilWithContext(ctx, func(context.Context) { w.promoteStagedPeers() }, stagedPeersCheckInterval)
+
 	return &w, nil
 }
 
@@ -205,19 +247,47 @@ func (w *Wireguard) ConnectToEndpoint(tep *netv1alpha1.TunnelEndpoint, updateSta
 	if err != nil {
 		return newConnectionOnError(err.Error()), err
 	}
+	// the endpoint configured in WireGuard differs from the remote one when the traffic is carried over TCP.
+	peerEndpoint, err := w.getPeerEndpoint(tep, endpoint)
+	if err != nil {
+		return newConnectionOnError(err.Error()), err
+	}
 
 	// delete or update old peers for ClusterID.
 	w.connectionsMutex.RLock()
 	oldCon, found := w.connections[tep.Spec.ClusterIdentity.ClusterID]
 	w.connectionsMutex.RUnlock()
+	// parse the public key the remote cluster is going to adopt, if a key rotation is pending.
+	nextKey, err := getPendingKey(tep)
+	if err != nil {
+		return newConnectionOnError(err.Error()), err
+	}
+	identity := tep.Spec.ClusterIdentity
+
 	if found {
 		// check if the peer configuration is updated.
 		if stringAllowedIPs == oldCon.PeerConfiguration[liqoconst.WgAllowedIPs] &&
-			remoteKey.String() == oldCon.PeerConfiguration[liqoconst.PublicKey] &&
 			endpoint.IP.String() == oldCon.PeerConfiguration[liqoconst.WgEndpointIP] &&
 			strconv.Itoa(endpoint.Port) == oldCon.PeerConfiguration[liqoconst.ListeningPort] {
-			// Update connection status.
-			return &tep.Status.Connection, nil
+			// If only the public key changed (i.e., it has been rotated), the peer is switched to the new key
+			// without tearing down the connection.
+			if remoteKey.String() != oldCon.PeerConfiguration[liqoconst.PublicKey] {
+				w.connectionsMutex.Lock()
+				err = w.switchPeerKey(&identity, *remoteKey, peerEndpoint, allowedIPs)
+				oldCon = w.connections[identity.ClusterID]
+				w.connectionsMutex.Unlock()
+				if err != nil {
+					return newConnectionOnError(err.Error()), err
+				}
+			}
+			if err = w.stagePeerKey(&identity, nextKey, peerEndpoint, allowedIPs); err != nil {
+				return newConnectionOnError(err.Error()), err
+			}
+
+			// Update connection status, taking into account the possible switch of the public key.
+			con := tep.Status.Connection.DeepCopy()
+			con.PeerConfiguration = oldCon.DeepCopy().PeerConfiguration
+			return con, nil
 		}
 		// If the configuration has changed then remove the peer.
 		klog.V(4).Infof("updating peer configuration for cluster %s", tep.Spec.ClusterIdentity)
@@ -250,7 +320,7 @@ func (w *Wireguard) ConnectToEndpoint(tep *netv1alpha1.TunnelEndpoint, updateSta
 		PublicKey:         *remoteKey,
 		Remove:            false,
 		UpdateOnly:        false,
-		Endpoint:          endpoint,
+		Endpoint:          peerEndpoint,
 		ReplaceAllowedIPs: true,
 		AllowedIPs:        allowedIPs,
 	}}
@@ -282,6 +352,10 @@ func (w *Wireguard) ConnectToEndpoint(tep *netv1alpha1.TunnelEndpoint, updateSta
 	klog.Infof("%s -> starting conncheck sender", tep.Spec.ClusterIdentity)
 
 	go w.Connchecker.AddAndRunSender(tep.Spec.ClusterIdentity.ClusterID, pingIP, updateStatus)
+	if err = w.stagePeerKey(&identity, nextKey, peerEndpoint, allowedIPs); err != nil {
+		// The connection is established anyway, and the staging is retried at the next resync.
+		klog.Errorf("%s -> %v", &identity, err)
+	}
 
 	klog.V(4).Infof("Done connecting cluster peer %s@%s", tep.Spec.ClusterIdentity, endpoint.String())
 	return c, nil
@@ -291,7 +365,18 @@ func (w *Wireguard) ConnectToEndpoint(tep *netv1alpha1.TunnelEndpoint, updateSta
 func (w *Wireguard) DisconnectFromEndpoint(tep *netv1alpha1.TunnelEndpoint) error {
 	klog.V(4).Infof("Removing connection with cluster %s", tep.Spec.ClusterIdentity)
 
+	if err := w.stagePeerKey(&tep.Spec.ClusterIdentity, nil, nil, nil); err != nil {
+		return err
+	}
+
+	// The public key configured for the peer is retrieved from the current connection, as it might have been switched
+	// after the last update of the status.
 	s, found := tep.Status.Connection.PeerConfiguration[liqoconst.PublicKey]
+	w.connectionsMutex.RLock()
+	if con, ok := w.connections[tep.Spec.ClusterIdentity.ClusterID]; ok {
+		s, found = con.PeerConfiguration[liqoconst.PublicKey], true
+	}
+	w.connectionsMutex.RUnlock()
 	if !found {
 		klog.V(4).Infof("no tunnel configured for cluster %s, nothing to be removed", tep.Spec.ClusterIdentity)
 		return nil
@@ -316,13 +401,8 @@ func (w *Wireguard) DisconnectFromEndpoint(tep *netv1alpha1.TunnelEndpoint) erro
 		return fmt.Errorf("failed to remove WireGuard peer with cluster %s: %w", tep.Spec.ClusterIdentity, err)
 	}
 
-	remoteKey, err := getKey(tep)
-	if err == nil {
-		delete(w.connectedClusterIdentities, *remoteKey)
-		klog.V(4).Infof("Done removing WireGuard peer with cluster %s", tep.Spec.ClusterIdentity)
-	} else {
-		klog.Errorf("failed to get public key for cluster %s: %v", tep.Spec.ClusterIdentity, err)
-	}
+	delete(w.connectedClusterIdentities, key)
+	klog.V(4).Infof("Done removing WireGuard peer with cluster %s", tep.Spec.ClusterIdentity)
 
 	w.connectionsMutex.Lock()
 	delete(w.connections, tep.Spec.ClusterIdentity.ClusterID)
@@ -330,6 +410,10 @@ func (w *Wireguard) DisconnectFromEndpoint(tep *netv1alpha1.TunnelEndpoint) erro
 
 	w.Connchecker.DelAndStopSender(tep.Spec.ClusterIdentity.ClusterID)
 
+	if w.transport != nil {
+		w.transport.release(tep.Spec.ClusterIdentity.ClusterID)
+	}
+
 	return nil
 }
 
@@ -340,6 +424,9 @@ func (w *Wireguard) GetLink() netlink.Link {
 
 // Close remove the wireguard device from the host.
 func (w *Wireguard) Close() error {
+	if w.transport != nil {
+		w.transport.close()
+	}
 	// it removes the wireguard interface.
 	var err error
 	if link, err := netlink.LinkByName(liqoconst.DeviceName); err == nil {
@@ -421,18 +508,35 @@ func (w *Wireguard) setWGLink(ctx context.Context) error {
 // wireguard allowedIPs. They are returned as []net.IPNet and
 // as a string (to accommodate comparison/storing on TEP resource).
 func getAllowedIPs(tep *netv1alpha1.TunnelEndpoint) ([]net.IPNet, string, error) {
-	_, remotePodCIDR := liqonetutils.GetPodCIDRS(tep)
-	_, remoteExternalCIDR := liqonetutils.GetExternalCIDRS(tep)
-
-	_, podCIDR, err := net.ParseCIDR(remotePodCIDR)
-	if err != nil {
-		return nil, "", fmt.Errorf("unable to parse podCIDR %s for cluster %s: %w", remotePodCIDR, tep.Spec.ClusterIdentity, err)
+	var allowedIPs []net.IPNet
+	var cidrs []string
+	// In case of dual-stack peerings, the IPv6 networks of the remote cluster are allowed as well.
+	for _, t := range liqonetutils.GetTunnelEndpointsPerFamily(tep) {
+		_, remotePodCIDR := liqonetutils.GetPodCIDRS(t)
+		_, remoteExternalCIDR := liqonetutils.GetExternalCIDRS(t)
+
+		_, podCIDR, err := net.ParseCIDR(remotePodCIDR)
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to parse podCIDR %s for cluster %s: %w", remotePodCIDR, tep.Spec.ClusterIdentity, err)
+		}
+		_, externalCIDR, err := net.ParseCIDR(remoteExternalCIDR)
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to parse externalCIDR %s for cluster %s: %w", remoteExternalCIDR, tep.Spec.ClusterIdentity, err)
+		}
+		allowedIPs = append(allowedIPs, *podCIDR, *externalCIDR)
+		cidrs = append(cidrs, remotePodCIDR, remoteExternalCIDR)
 	}
-	_, externalCIDR, err := net.ParseCIDR(remoteExternalCIDR)
-	if err != nil {
-		return nil, "", fmt.Errorf("unable to parse externalCIDR %s for cluster %s: %w", remoteExternalCIDR, tep.Spec.ClusterIdentity, err)
+	// The networks reachable through the remote cluster, acting as a transit cluster, are allowed as well.
+	for i := range tep.Spec.RemoteTransitNetworks {
+		transitPodCIDR := liqonetutils.GetTransitPodCIDR(&tep.Spec.RemoteTransitNetworks[i])
+		_, podCIDR, err := net.ParseCIDR(transitPodCIDR)
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to parse transit podCIDR %s for cluster %s: %w", transitPodCIDR, tep.Spec.ClusterIdentity, err)
+		}
+		allowedIPs = append(allowedIPs, *podCIDR)
+		cidrs = append(cidrs, transitPodCIDR)
 	}
-	return []net.IPNet{*podCIDR, *externalCIDR}, fmt.Sprintf("%s, %s", remotePodCIDR, remoteExternalCIDR), nil
+	return allowedIPs, strings.Join(cidrs, ", "), nil
 }
 
 func getKey(tep *netv1alpha1.TunnelEndpoint) (*wgtypes.Key, error) {
@@ -445,10 +549,24 @@ func getKey(tep *netv1alpha1.TunnelEndpoint) (*wgtypes.Key, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse public key %s: %w", s, err)
 	}
-
+	// The next key, if any, is not returned even if its switch time is expired, as it is adopted only once a handshake
+	// with the corresponding staged peer completes, or the remote cluster publishes it as the current one.
 	return &key, nil
 }
 
+// getPeerEndpoint returns the endpoint to be configured for the WireGuard peer towards the given remote endpoint.
+// In case the traffic is carried over TCP, it is the address of the local relay towards the remote gateway.
+func (w *Wireguard) getPeerEndpoint(tep *netv1alpha1.TunnelEndpoint, endpoint *net.UDPAddr) (*net.UDPAddr, error) {
+	if w.transport == nil {
+		return endpoint, nil
+	}
+	transport, err := getTransport(tep.Spec.BackendConfig)
+	if err != nil {
+		return nil, err
+	}
+	return w.transport.relay(tep.Spec.ClusterIdentity.ClusterID, endpoint, transport)
+}
+
 func getEndpoint(tep *netv1alpha1.TunnelEndpoint, addrResolver ResolverFunc) (*net.UDPAddr, error) {
 	// Get tunnel port.
 	tunnelPort, err := getTunnelPortFromTep(tep)
@@ -518,7 +636,8 @@ func (w *Wireguard) setKeys(c k8s.Interface, namespace string) error {
 				Namespace: namespace,
 				Labels:    map[string]string{liqoconst.KeysLabel: liqoconst.DriverName},
 			},
-			StringData: map[string]string{liqoconst.PublicKey: pub.String(), liqoconst.WgPrivateKey: priv.String()},
+			StringData: map[string]string{liqoconst.PublicKey: pub.String(), liqoconst.WgPrivateKey: priv.String(),
+				liqoconst.WgKeyCreationTime: time.Now().Format(time.RFC3339)},
 		}
 		_, err = c.CoreV1().Secrets(namespace).Create(context.Background(), &pKey, metav1.CreateOptions{})
 		if err != nil {
@@ -526,25 +645,13 @@ func (w *Wireguard) setKeys(c k8s.Interface, namespace string) error {
 		}
 		return nil
 	}
-	// get the keys from the existing secret and set them.
-	privKey, found := s.Data[liqoconst.WgPrivateKey]
-	if !found {
-		return fmt.Errorf("no data with key '%s' found in secret %s", liqoconst.WgPrivateKey, liqoconst.WgKeysName)
-	}
-	priv, err = wgtypes.ParseKey(string(privKey))
+	// get the keys from the existing secret and set them, taking into account a possible pending rotation.
+	k, err := parseKeysSecret(s)
 	if err != nil {
-		return fmt.Errorf("an error occurred while parsing the private key for the wireguard driver :%w", err)
-	}
-	pubKey, found := s.Data[liqoconst.PublicKey]
-	if !found {
-		return fmt.Errorf("no data with key '%s' found in secret %s", liqoconst.PublicKey, liqoconst.WgKeysName)
-	}
-	pub, err = wgtypes.ParseKey(string(pubKey))
-	if err != nil {
-		return fmt.Errorf("an error occurred while parsing the public key for the wireguard driver :%w", err)
+		return err
 	}
-	w.conf.pubKey = pub
-	w.conf.priKey = priv
+	w.conf.priKey = k.current(time.Now())
+	w.conf.pubKey = w.conf.priKey.PublicKey()
 	return nil
 }
 
@@ -557,6 +664,8 @@ func (w *Wireguard) SetNewClient() error {
 		}
 		return fmt.Errorf("failed to open wgctl client: %w", err)
 	}
+	w.keysMutex.Lock()
+	defer w.keysMutex.Unlock()
 	w.client = c
 	return nil
 }
@@ -0,0 +1,352 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguard
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	discv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+)
+
+var (
+	// KeyRotationInterval is the interval after which the key pair of the wireguard driver is rotated (0 to disable the rotation).
+	KeyRotationInterval time.Duration
+	// KeyRotationGracePeriod is the time interval between the publication of a new key pair and its adoption,
+	// which shall allow the peers to receive the new public key, and configure it in parallel with the current one.
+	KeyRotationGracePeriod time.Duration
+	// keyRotationCheckInterval is the interval at which the key rotation status is checked.
+	keyRotationCheckInterval = 10 * time.Second
+	// stagedPeersCheckInterval is the interval at which the staged peers are checked for completed handshakes.
+	stagedPeersCheckInterval = time.Second
+)
+
+// keys represents the content of the secret containing the wireguard keys.
+type keys struct {
+	private wgtypes.Key
+	// next is the private key which replaces the current one at switchTime, if any.
+	next       *wgtypes.Key
+	switchTime time.Time
+	// creationTime is the time at which the current private key has been adopted.
+	creationTime time.Time
+}
+
+// current returns the private key which shall be used at the given time.
+func (k *keys) current(now time.Time) wgtypes.Key {
+	if k.next != nil && !now.Before(k.switchTime) {
+		return *k.next
+	}
+	return k.private
+}
+
+// parseKeysSecret parses the secret containing the wireguard keys.
+func parseKeysSecret(s *corev1.Secret) (*keys, error) {
+	parse := func(name string) (wgtypes.Key, error) {
+		data, found := s.Data[name]
+		if !found {
+			return wgtypes.Key{}, fmt.Errorf("no data with key '%s' found in secret %s", name, liqoconst.WgKeysName)
+		}
+		key, err := wgtypes.ParseKey(string(data))
+		if err != nil {
+			return wgtypes.Key{}, fmt.Errorf("an error occurred while parsing the %s for the wireguard driver: %w", name, err)
+		}
+		return key, nil
+	}
+
+	var k keys
+	var err error
+	if k.private, err = parse(liqoconst.WgPrivateKey); err != nil {
+		return nil, err
+	}
+	pub, err := parse(liqoconst.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	if pub != k.private.PublicKey() {
+		return nil, fmt.Errorf("the public key in secret %s does not match the private key", liqoconst.WgKeysName)
+	}
+
+	k.creationTime = s.CreationTimestamp.Time
+	if data, found := s.Data[liqoconst.WgKeyCreationTime]; found {
+		if k.creationTime, err = time.Parse(time.RFC3339, string(data)); err != nil {
+			return nil, fmt.Errorf("an error occurred while parsing the key creation time: %w", err)
+		}
+	}
+
+	if _, found := s.Data[liqoconst.WgNextPrivateKey]; found {
+		next, err := parse(liqoconst.WgNextPrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		if k.switchTime, err = time.Parse(time.RFC3339, string(s.Data[liqoconst.WgKeySwitchTime])); err != nil {
+			return nil, fmt.Errorf("an error occurred while parsing the key switch time: %w", err)
+		}
+		k.next = &next
+	}
+	return &k, nil
+}
+
+// runKeyRotation periodically rotates the key pair of the wireguard driver, until the given context is canceled.
+// A new key pair is first published through the secret (hence, through the NetworkConfigs), along with the time
+// at which it replaces the current one. In the meanwhile, the remote gateways configure a parallel peer with the new
+// public key, and move the traffic to it as soon as it completes a handshake (i.e., once the local gateway switched).
+// Hence, the switch does not depend on the clocks of the remote gateways being synchronized with the local one.
+func (w *Wireguard) runKeyRotation(ctx context.Context) {
+	klog.Infof("Wireguard key rotation enabled, with interval %s and grace period %s", KeyRotationInterval, KeyRotationGracePeriod)
+	// The first check is delayed, to let the driver complete its initialization (i.e., the device is moved to the gateway netns).
+	delay := keyRotationCheckInterval
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		delay = keyRotationCheckInterval
+		switchTime, err := w.rotateKeys(ctx, time.Now())
+		if err != nil {
+			klog.Errorf("an error occurred while rotating the wireguard keys: %v", err)
+		}
+		// Wake up exactly at the switch time, to adopt the new key without further delaying the peers waiting for it.
+		if until := time.Until(switchTime); !switchTime.IsZero() && until >= 0 && until < delay {
+			delay = until
+		}
+	}
+}
+
+// rotateKeys performs a single step of the key rotation process.
+// It returns the time at which the pending key pair, if any, is going to be adopted.
+func (w *Wireguard) rotateKeys(ctx context.Context, now time.Time) (time.Time, error) {
+	s, err := w.k8sClient.CoreV1().Secrets(w.namespace).Get(ctx, liqoconst.WgKeysName, metav1.GetOptions{})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to retrieve the secret with name %s: %w", liqoconst.WgKeysName, err)
+	}
+	k, err := parseKeysSecret(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// Make sure the device leverages the key expected at the current time (the secret might have been updated by other replicas).
+	if err := w.setPrivateKey(k.current(now)); err != nil {
+		return k.switchTime, err
+	}
+
+	var switchTime time.Time
+	switch {
+	case k.next != nil && !now.Before(k.switchTime):
+		// The switch time is expired: the next key becomes the current one.
+		s.Data[liqoconst.WgPrivateKey] = []byte(k.next.String())
+		s.Data[liqoconst.PublicKey] = []byte(k.next.PublicKey().String())
+		s.Data[liqoconst.WgKeyCreationTime] = []byte(k.switchTime.Format(time.RFC3339))
+		delete(s.Data, liqoconst.WgNextPrivateKey)
+		delete(s.Data, liqoconst.WgNextPublicKey)
+		delete(s.Data, liqoconst.WgKeySwitchTime)
+		klog.Infof("Wireguard key pair rotated, new public key %s", k.next.PublicKey())
+
+	case k.next == nil && now.Sub(k.creationTime) >= KeyRotationInterval:
+		// The current key is expired: a new key pair is generated and published.
+		next, err := wgtypes.GeneratePrivateKey()
+		if err != nil {
+			return time.Time{}, fmt.Errorf("error generating private key for wireguard backend: %w", err)
+		}
+		// The switch time is truncated to seconds, to be consistent with its textual representation.
+		switchTime = now.Add(KeyRotationGracePeriod).Truncate(time.Second)
+		s.Data[liqoconst.WgNextPrivateKey] = []byte(next.String())
+		s.Data[liqoconst.WgNextPublicKey] = []byte(next.PublicKey().String())
+		s.Data[liqoconst.WgKeySwitchTime] = []byte(switchTime.Format(time.RFC3339))
+		klog.Infof("Wireguard key pair generated, public key %s will be adopted at %s", next.PublicKey(), switchTime.Format(time.RFC3339))
+
+	default:
+		return k.switchTime, nil
+	}
+
+	if _, err := w.k8sClient.CoreV1().Secrets(w.namespace).Update(ctx, s, metav1.UpdateOptions{}); err != nil {
+		if apierrors.IsConflict(err) {
+			// The secret has been concurrently updated by another replica, the new content is processed at the next iteration.
+			klog.V(4).Infof("failed to update the secret with name %s: %v", liqoconst.WgKeysName, err)
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("failed to update the secret with name %s: %w", liqoconst.WgKeysName, err)
+	}
+	return switchTime, nil
+}
+
+// setPrivateKey configures the wireguard device with the given private key, if different from the current one.
+func (w *Wireguard) setPrivateKey(priv wgtypes.Key) error {
+	w.keysMutex.Lock()
+	defer w.keysMutex.Unlock()
+
+	if priv == w.conf.priKey {
+		return nil
+	}
+	if err := w.client.ConfigureDevice(liqoconst.DeviceName, wgtypes.Config{PrivateKey: &priv}); err != nil {
+		return fmt.Errorf("failed to configure the private key of the WireGuard device: %w", err)
+	}
+	w.conf.priKey = priv
+	w.conf.pubKey = priv.PublicKey()
+	klog.Infof("%s interface named %s configured with publicKey %s", liqoconst.DriverName, liqoconst.DeviceName, w.conf.pubKey)
+	return nil
+}
+
+// getPendingKey returns the public key which the remote cluster described by the given tep is going to adopt,
+// or nil if no rotation is pending.
+func getPendingKey(tep *netv1alpha1.TunnelEndpoint) (*wgtypes.Key, error) {
+	s, found := tep.Spec.BackendConfig[liqoconst.WgNextPublicKey]
+	if !found {
+		return nil, nil
+	}
+
+	key, err := wgtypes.ParseKey(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse next public key %s: %w", s, err)
+	}
+	return &key, nil
+}
+
+// stagedPeer represents a peer configured with the public key the remote cluster is going to adopt, in parallel
+// with the current one. It has no allowed IPs, hence it does not attract any traffic until its handshake completes.
+type stagedPeer struct {
+	identity   discv1alpha1.ClusterIdentity
+	key        wgtypes.Key
+	endpoint   *net.UDPAddr
+	allowedIPs []net.IPNet
+}
+
+// stagePeerKey configures a parallel peer with the given public key, which the remote cluster is going to adopt,
+// replacing any previously staged one. A nil key removes the staged peer, unless it has already been promoted.
+func (w *Wireguard) stagePeerKey(identity *discv1alpha1.ClusterIdentity, key *wgtypes.Key,
+	endpoint *net.UDPAddr, allowedIPs []net.IPNet) error {
+	w.connectionsMutex.Lock()
+	defer w.connectionsMutex.Unlock()
+
+	var currentKey string
+	if con, found := w.connections[identity.ClusterID]; found {
+		currentKey = con.PeerConfiguration[liqoconst.PublicKey]
+	}
+
+	staged, found := w.stagedPeers[identity.ClusterID]
+	if found && key != nil && staged.key == *key && staged.endpoint.String() == endpoint.String() {
+		// The staged peer is already up-to-date, except for the allowed IPs which are configured only once promoted.
+		staged.allowedIPs = allowedIPs
+		return nil
+	}
+	if found {
+		// The peer is removed, unless it has already been promoted (e.g., since the remote cluster published the new key as the current one).
+		if staged.key.String() != currentKey && (key == nil || staged.key != *key) {
+			err := w.client.ConfigureDevice(liqoconst.DeviceName, wgtypes.Config{
+				Peers: []wgtypes.PeerConfig{{PublicKey: staged.key, Remove: true}},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to remove staged peer with public key %s: %w", staged.key, err)
+			}
+			klog.Infof("%s -> removed staged peer with public key %s", identity, staged.key)
+		}
+		delete(w.stagedPeers, identity.ClusterID)
+	}
+	if key == nil || key.String() == currentKey {
+		return nil
+	}
+
+	err := w.client.ConfigureDevice(liqoconst.DeviceName, wgtypes.Config{
+		Peers: []wgtypes.PeerConfig{{PublicKey: *key, Endpoint: endpoint, ReplaceAllowedIPs: true}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to configure staged peer with public key %s: %w", key, err)
+	}
+	w.stagedPeers[identity.ClusterID] = &stagedPeer{identity: *identity, key: *key, endpoint: endpoint, allowedIPs: allowedIPs}
+	klog.Infof("%s -> staged peer with public key %s, waiting for its first handshake", identity, key)
+	return nil
+}
+
+// promoteStagedPeers moves the traffic towards the staged peers which completed a handshake, that is the remote
+// cluster already switched to the new key. Hence, the old peer is removed only once the new one is known to work.
+func (w *Wireguard) promoteStagedPeers() {
+	w.connectionsMutex.RLock()
+	pending := len(w.stagedPeers)
+	w.connectionsMutex.RUnlock()
+	if pending == 0 {
+		return
+	}
+
+	device, err := w.client.Device(liqoconst.DeviceName)
+	if err != nil {
+		klog.Errorf("Failed to retrieve the %s device: %v", liqoconst.DeviceName, err)
+		return
+	}
+	handshakes := make(map[wgtypes.Key]time.Time, len(device.Peers))
+	for i := range device.Peers {
+		handshakes[device.Peers[i].PublicKey] = device.Peers[i].LastHandshakeTime
+	}
+
+	w.connectionsMutex.Lock()
+	defer w.connectionsMutex.Unlock()
+	for clusterID, staged := range w.stagedPeers {
+		if handshakes[staged.key].IsZero() {
+			continue
+		}
+		if err := w.switchPeerKey(&staged.identity, staged.key, staged.endpoint, staged.allowedIPs); err != nil {
+			klog.Errorf("%s -> %v", &staged.identity, err)
+			continue
+		}
+		delete(w.stagedPeers, clusterID)
+	}
+}
+
+// switchPeerKey replaces the public key of the given peer, preserving the rest of its configuration. The old peer is
+// removed and the new one is added with a single device configuration, without stopping the connection checker.
+// The connectionsMutex shall be held.
+func (w *Wireguard) switchPeerKey(identity *discv1alpha1.ClusterIdentity, key wgtypes.Key, endpoint *net.UDPAddr, allowedIPs []net.IPNet) error {
+	con, found := w.connections[identity.ClusterID]
+	if !found {
+		return fmt.Errorf("no connection found towards cluster %s", identity)
+	}
+	oldKey, err := wgtypes.ParseKey(con.PeerConfiguration[liqoconst.PublicKey])
+	if err != nil {
+		return fmt.Errorf("failed to parse public key %s: %w", con.PeerConfiguration[liqoconst.PublicKey], err)
+	}
+	if oldKey == key {
+		return nil
+	}
+
+	err = w.client.ConfigureDevice(liqoconst.DeviceName, wgtypes.Config{
+		ReplacePeers: false,
+		Peers: []wgtypes.PeerConfig{
+			{PublicKey: oldKey, Remove: true},
+			{PublicKey: key, Endpoint: endpoint, ReplaceAllowedIPs: true, AllowedIPs: allowedIPs},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to switch public key of peer with cluster %s: %w", identity, err)
+	}
+
+	// The connection is replaced, rather than modified in place, as it might be concurrently read.
+	updated := con.DeepCopy()
+	updated.PeerConfiguration[liqoconst.PublicKey] = key.String()
+	w.connections[identity.ClusterID] = updated
+	delete(w.connectedClusterIdentities, oldKey)
+	w.connectedClusterIdentities[key] = identity
+	klog.Infof("%s -> switched to public key %s", identity, key)
+	return nil
+}
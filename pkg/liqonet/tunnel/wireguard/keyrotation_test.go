@@ -0,0 +1,215 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguard
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+)
+
+const namespace = "liqo"
+
+var _ = Describe("Key rotation", func() {
+	var (
+		current, next wgtypes.Key
+		now           time.Time
+	)
+
+	BeforeEach(func() {
+		var err error
+		current, err = wgtypes.GeneratePrivateKey()
+		Expect(err).ToNot(HaveOccurred())
+		next, err = wgtypes.GeneratePrivateKey()
+		Expect(err).ToNot(HaveOccurred())
+		now = time.Now().Truncate(time.Second)
+	})
+
+	forgeSecret := func(creationTime time.Time, pending bool, switchTime time.Time) *corev1.Secret {
+		s := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: liqoconst.WgKeysName, Namespace: namespace},
+			Data: map[string][]byte{
+				liqoconst.WgPrivateKey:      []byte(current.String()),
+				liqoconst.PublicKey:         []byte(current.PublicKey().String()),
+				liqoconst.WgKeyCreationTime: []byte(creationTime.Format(time.RFC3339)),
+			},
+		}
+		if pending {
+			s.Data[liqoconst.WgNextPrivateKey] = []byte(next.String())
+			s.Data[liqoconst.WgNextPublicKey] = []byte(next.PublicKey().String())
+			s.Data[liqoconst.WgKeySwitchTime] = []byte(switchTime.Format(time.RFC3339))
+		}
+		return s
+	}
+
+	Describe("parsing the secret containing the keys", func() {
+		It("should parse the current keys", func() {
+			k, err := parseKeysSecret(forgeSecret(now, false, time.Time{}))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(k.private).To(Equal(current))
+			Expect(k.next).To(BeNil())
+			Expect(k.creationTime).To(BeTemporally("==", now))
+			Expect(k.current(now)).To(Equal(current))
+		})
+
+		It("should parse the pending keys", func() {
+			k, err := parseKeysSecret(forgeSecret(now, true, now.Add(time.Minute)))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(k.next).To(PointTo(Equal(next)))
+			Expect(k.switchTime).To(BeTemporally("==", now.Add(time.Minute)))
+			Expect(k.current(now)).To(Equal(current))
+			Expect(k.current(now.Add(time.Minute))).To(Equal(next))
+		})
+
+		It("should fallback to the secret creation timestamp", func() {
+			s := forgeSecret(now, false, time.Time{})
+			delete(s.Data, liqoconst.WgKeyCreationTime)
+			s.CreationTimestamp = metav1.NewTime(now.Add(-time.Hour))
+			k, err := parseKeysSecret(s)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(k.creationTime).To(BeTemporally("==", now.Add(-time.Hour)))
+		})
+
+		It("should fail if the public key does not match the private one", func() {
+			s := forgeSecret(now, false, time.Time{})
+			s.Data[liqoconst.PublicKey] = []byte(next.PublicKey().String())
+			_, err := parseKeysSecret(s)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("rotating the keys", func() {
+		var (
+			w      *Wireguard
+			secret *corev1.Secret
+		)
+
+		JustBeforeEach(func() {
+			w = &Wireguard{k8sClient: fake.NewSimpleClientset(secret), namespace: namespace, conf: wgConfig{priKey: current}}
+		})
+
+		getSecret := func() *corev1.Secret {
+			s, err := w.k8sClient.CoreV1().Secrets(namespace).Get(context.Background(), liqoconst.WgKeysName, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			return s
+		}
+
+		BeforeEach(func() { KeyRotationInterval, KeyRotationGracePeriod = time.Hour, time.Minute })
+
+		When("the current key is not expired", func() {
+			BeforeEach(func() { secret = forgeSecret(now.Add(-30*time.Minute), false, time.Time{}) })
+
+			It("should leave the secret unmodified", func() {
+				switchTime, err := w.rotateKeys(context.Background(), now)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(switchTime.IsZero()).To(BeTrue())
+				Expect(getSecret().Data).To(Equal(secret.Data))
+			})
+		})
+
+		When("the current key is expired", func() {
+			BeforeEach(func() { secret = forgeSecret(now.Add(-2*time.Hour), false, time.Time{}) })
+
+			It("should publish a new key pair", func() {
+				switchTime, err := w.rotateKeys(context.Background(), now)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(switchTime).To(BeTemporally("==", now.Add(time.Minute)))
+
+				k, err := parseKeysSecret(getSecret())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(k.private).To(Equal(current))
+				Expect(k.next).ToNot(BeNil())
+				Expect(getSecret().Data).To(HaveKeyWithValue(liqoconst.WgNextPublicKey, []byte(k.next.PublicKey().String())))
+				Expect(k.switchTime).To(BeTemporally("==", now.Add(time.Minute)))
+				// The device still leverages the current key.
+				Expect(w.conf.priKey).To(Equal(current))
+			})
+		})
+
+		When("the switch time of the pending key is expired", func() {
+			BeforeEach(func() { secret = forgeSecret(now.Add(-2*time.Hour), true, now.Add(-time.Second)) })
+			// The device has already been configured with the new key (e.g., by another replica).
+			JustBeforeEach(func() { w.conf.priKey = next })
+
+			It("should promote the pending key pair", func() {
+				switchTime, err := w.rotateKeys(context.Background(), now)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(switchTime.IsZero()).To(BeTrue())
+
+				k, err := parseKeysSecret(getSecret())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(k.private).To(Equal(next))
+				Expect(k.next).To(BeNil())
+				Expect(k.creationTime).To(BeTemporally("==", now.Add(-time.Second)))
+				Expect(getSecret().Data).ToNot(HaveKey(liqoconst.WgNextPublicKey))
+			})
+		})
+	})
+
+	Describe("retrieving the public key of a peer", func() {
+		var tep *netv1alpha1.TunnelEndpoint
+
+		BeforeEach(func() {
+			tep = &netv1alpha1.TunnelEndpoint{Spec: netv1alpha1.TunnelEndpointSpec{BackendConfig: map[string]string{
+				liqoconst.PublicKey: current.PublicKey().String(),
+			}}}
+		})
+
+		When("no key rotation is pending", func() {
+			It("should return the current key", func() {
+				Expect(getKey(tep)).To(PointTo(Equal(current.PublicKey())))
+				key, err := getPendingKey(tep)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(key).To(BeNil())
+			})
+		})
+
+		When("a key rotation is pending", func() {
+			BeforeEach(func() {
+				tep.Spec.BackendConfig[liqoconst.WgNextPublicKey] = next.PublicKey().String()
+				tep.Spec.BackendConfig[liqoconst.WgKeySwitchTime] = time.Now().Add(time.Minute).Format(time.RFC3339)
+			})
+
+			It("should return the current key, and the pending one", func() {
+				Expect(getKey(tep)).To(PointTo(Equal(current.PublicKey())))
+				key, err := getPendingKey(tep)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(key).To(PointTo(Equal(next.PublicKey())))
+			})
+		})
+
+		When("the switch time of the pending key is expired", func() {
+			BeforeEach(func() {
+				tep.Spec.BackendConfig[liqoconst.WgNextPublicKey] = next.PublicKey().String()
+				tep.Spec.BackendConfig[liqoconst.WgKeySwitchTime] = time.Now().Add(-time.Minute).Format(time.RFC3339)
+			})
+
+			It("should still return the current key, as the next one is adopted only after a handshake", func() {
+				Expect(getKey(tep)).To(PointTo(Equal(current.PublicKey())))
+				Expect(getPendingKey(tep)).To(PointTo(Equal(next.PublicKey())))
+			})
+		})
+	})
+})
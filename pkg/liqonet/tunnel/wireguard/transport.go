@@ -0,0 +1,625 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguard
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"golang.org/x/net/websocket"
+	"k8s.io/klog/v2"
+
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+)
+
+// TunnelProxyURL is the URL of the HTTP(S) proxy leveraged to reach the remote gateways when the WireGuard traffic
+// is carried over TCP. If empty, the proxy is retrieved from the standard environment variables (e.g., HTTPS_PROXY).
+var TunnelProxyURL string
+
+const (
+	// webSocketPath is the HTTP path the WebSocket connections are established to.
+	webSocketPath = "/liqo/tunnel"
+	// transportDialTimeout is the maximum time to establish a connection towards a remote gateway.
+	transportDialTimeout = 10 * time.Second
+	// transportDialBackoff is the minimum interval between two attempts to connect to the same remote gateway.
+	transportDialBackoff = 5 * time.Second
+	// maxDatagramSize is the maximum size of the datagrams carried over the TCP connections.
+	maxDatagramSize = 65535
+)
+
+// datagramConn carries datagrams over a stream-oriented connection, preserving their boundaries.
+type datagramConn interface {
+	ReadDatagram() ([]byte, error)
+	WriteDatagram(b []byte) error
+	Close() error
+}
+
+// tcpDatagramConn carries datagrams over a raw TCP connection, prefixing each of them with its length (two bytes, big endian).
+type tcpDatagramConn struct {
+	net.Conn
+}
+
+// ReadDatagram reads the next datagram from the connection.
+func (c *tcpDatagramConn) ReadDatagram() ([]byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.Conn, header[:]); err != nil {
+		return nil, err
+	}
+	b := make([]byte, binary.BigEndian.Uint16(header[:]))
+	if _, err := io.ReadFull(c.Conn, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// WriteDatagram writes a datagram to the connection.
+func (c *tcpDatagramConn) WriteDatagram(b []byte) error {
+	if len(b) > maxDatagramSize {
+		return fmt.Errorf("datagram size %d exceeds the maximum of %d bytes", len(b), maxDatagramSize)
+	}
+	frame := make([]byte, len(b)+2)
+	binary.BigEndian.PutUint16(frame, uint16(len(b)))
+	copy(frame[2:], b)
+	_, err := c.Conn.Write(frame)
+	return err
+}
+
+// wsDatagramConn carries datagrams over a WebSocket connection, each of them as a binary message.
+type wsDatagramConn struct {
+	*websocket.Conn
+}
+
+// ReadDatagram reads the next datagram from the connection.
+func (c *wsDatagramConn) ReadDatagram() ([]byte, error) {
+	var b []byte
+	err := websocket.Message.Receive(c.Conn, &b)
+	return b, err
+}
+
+// WriteDatagram writes a datagram to the connection.
+func (c *wsDatagramConn) WriteDatagram(b []byte) error {
+	return websocket.Message.Send(c.Conn, b)
+}
+
+// bufferedConn is a net.Conn whose data is read through a buffered reader, which might already contain part of it.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// Read reads data from the connection.
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// connListener is a net.Listener returning the connections pushed to it, to serve them through an http.Server.
+type connListener struct {
+	conns chan net.Conn
+	addr  net.Addr
+	done  chan struct{}
+	once  sync.Once
+}
+
+// Accept waits for and returns the next connection.
+func (l *connListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close closes the listener.
+func (l *connListener) Close() error {
+	l.once.Do(func() { close(l.done) })
+	return nil
+}
+
+// Addr returns the listener's network address.
+func (l *connListener) Addr() net.Addr {
+	return l.addr
+}
+
+// streamTransport carries the WireGuard traffic over TCP connections, either raw or WebSocket-based, to traverse
+// networks filtering UDP traffic. On the one hand, it accepts the connections from the remote gateways, and relays
+// the received datagrams to the local WireGuard socket. On the other hand, it configures a local relay for each
+// remote cluster, which WireGuard is configured to send the traffic to, and forwards it to the remote gateway.
+type streamTransport struct {
+	// wgAddr is the address of the local WireGuard socket.
+	wgAddr    *net.UDPAddr
+	listener  net.Listener
+	wsServer  *http.Server
+	hostNetns ns.NetNS
+
+	// relays key is a clusterID.
+	relays      map[string]*streamRelay
+	relaysMutex sync.Mutex
+}
+
+// newStreamTransport creates a new stream transport, accepting the connections on the given TCP port and relaying
+// the datagrams to the local WireGuard socket listening on the same UDP port. The sockets towards WireGuard and the
+// remote gateways are created in the given network namespace, if not nil.
+func newStreamTransport(port int, hostNetns ns.NetNS) (*streamTransport, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on TCP port %d: %w", port, err)
+	}
+
+	t := &streamTransport{
+		wgAddr:    &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: port},
+		listener:  listener,
+		hostNetns: hostNetns,
+		relays:    make(map[string]*streamRelay),
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(webSocketPath, websocket.Server{
+		// The origin is not checked, since the connections are not established by browsers.
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			ws.PayloadType = websocket.BinaryFrame
+			t.relayToWireGuard(&wsDatagramConn{Conn: ws})
+		},
+	})
+	wsListener := &connListener{conns: make(chan net.Conn), addr: listener.Addr(), done: make(chan struct{})}
+	t.wsServer = &http.Server{Handler: mux, ReadHeaderTimeout: transportDialTimeout}
+
+	go func() {
+		if err := t.wsServer.Serve(wsListener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			klog.Errorf("WebSocket server terminated unexpectedly: %v", err)
+		}
+	}()
+	go t.accept(wsListener)
+
+	klog.Infof("Accepting the WireGuard traffic over TCP on port %d", port)
+	return t, nil
+}
+
+// accept accepts the connections from the remote gateways, and dispatches them depending on the transport.
+func (t *streamTransport) accept(wsListener *connListener) {
+	for {
+		conn, err := t.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			klog.Errorf("Failed to accept incoming connection: %v", err)
+			continue
+		}
+		go t.dispatch(conn, wsListener)
+	}
+}
+
+// dispatch detects whether the given connection is WebSocket-based, and relays it accordingly. The detection is not
+// ambiguous, since the first bytes of a raw connection are the length of the datagram followed by the type of the
+// WireGuard message, which can never match an HTTP request line.
+func (t *streamTransport) dispatch(conn net.Conn, wsListener *connListener) {
+	const httpMethod = "GET "
+
+	reader := bufio.NewReader(conn)
+	if err := conn.SetReadDeadline(time.Now().Add(transportDialTimeout)); err != nil {
+		klog.Errorf("Failed to configure connection from %s: %v", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+	prefix, err := reader.Peek(len(httpMethod))
+	if err != nil {
+		klog.V(4).Infof("Failed to read from connection from %s: %v", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+	if err := conn.SetReadDeadline(time.Time{}); err != nil {
+		klog.Errorf("Failed to configure connection from %s: %v", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+
+	buffered := &bufferedConn{Conn: conn, reader: reader}
+	if string(prefix) != httpMethod {
+		klog.V(4).Infof("Accepted TCP connection from %s", conn.RemoteAddr())
+		t.relayToWireGuard(&tcpDatagramConn{Conn: buffered})
+		return
+	}
+
+	klog.V(4).Infof("Accepted WebSocket connection from %s", conn.RemoteAddr())
+	select {
+	case wsListener.conns <- buffered:
+	case <-wsListener.done:
+		conn.Close()
+	}
+}
+
+// relayToWireGuard relays the datagrams received from the given connection to the local WireGuard socket,
+// and vice versa, until either fails. WireGuard automatically learns the address of the relay socket.
+func (t *streamTransport) relayToWireGuard(stream datagramConn) {
+	defer stream.Close()
+
+	udp, err := net.DialUDP("udp", nil, t.wgAddr)
+	if err != nil {
+		klog.Errorf("Failed to create socket towards WireGuard: %v", err)
+		return
+	}
+	defer udp.Close()
+
+	go func() {
+		buff := make([]byte, maxDatagramSize)
+		for {
+			n, err := udp.Read(buff)
+			if err != nil {
+				stream.Close()
+				return
+			}
+			if err := stream.WriteDatagram(buff[:n]); err != nil {
+				udp.Close()
+				return
+			}
+		}
+	}()
+
+	for {
+		b, err := stream.ReadDatagram()
+		if err != nil {
+			klog.V(4).Infof("Connection towards WireGuard terminated: %v", err)
+			return
+		}
+		if _, err := udp.Write(b); err != nil {
+			return
+		}
+	}
+}
+
+// relay returns the address of the local relay towards the given remote gateway, to be configured as the endpoint of
+// the WireGuard peer. The relay is created if not already present, while it is updated in case the remote endpoint or
+// the transport changed, preserving its address.
+func (t *streamTransport) relay(clusterID string, remote *net.UDPAddr, transport string) (*net.UDPAddr, error) {
+	t.relaysMutex.Lock()
+	defer t.relaysMutex.Unlock()
+
+	if r, found := t.relays[clusterID]; found {
+		r.update(remote.String(), transport)
+		return r.local.LocalAddr().(*net.UDPAddr), nil
+	}
+
+	var local *net.UDPConn
+	if err := t.inHostNetns(func() (err error) {
+		local, err = net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create relay socket: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &streamRelay{
+		parent: t,
+		remote: remote.String(),
+		kind:   transport,
+		local:  local,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	t.relays[clusterID] = r
+	go r.run()
+
+	klog.V(4).Infof("Created %s relay %s towards %s for cluster %s", transport, local.LocalAddr(), remote, clusterID)
+	return local.LocalAddr().(*net.UDPAddr), nil
+}
+
+// release closes the relay towards the given remote cluster, if present.
+func (t *streamTransport) release(clusterID string) {
+	t.relaysMutex.Lock()
+	defer t.relaysMutex.Unlock()
+
+	if r, found := t.relays[clusterID]; found {
+		r.close()
+		delete(t.relays, clusterID)
+	}
+}
+
+// close stops accepting new connections, and closes all the relays.
+func (t *streamTransport) close() {
+	if err := t.listener.Close(); err != nil {
+		klog.Errorf("Failed to close TCP listener: %v", err)
+	}
+	if err := t.wsServer.Close(); err != nil {
+		klog.Errorf("Failed to close WebSocket server: %v", err)
+	}
+
+	t.relaysMutex.Lock()
+	defer t.relaysMutex.Unlock()
+	for clusterID, r := range t.relays {
+		r.close()
+		delete(t.relays, clusterID)
+	}
+}
+
+// inHostNetns executes the given function in the network namespace the sockets are expected to be created in.
+func (t *streamTransport) inHostNetns(f func() error) error {
+	if t.hostNetns == nil {
+		return f()
+	}
+	return t.hostNetns.Do(func(ns.NetNS) error { return f() })
+}
+
+// streamRelay forwards the datagrams sent by WireGuard to its local socket towards a remote gateway, through a TCP
+// connection which is lazily established (i.e., upon the first datagram), and re-established in case of failures.
+type streamRelay struct {
+	parent *streamTransport
+	local  *net.UDPConn
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mutex    sync.Mutex
+	remote   string
+	kind     string
+	stream   datagramConn
+	lastDial time.Time
+}
+
+// run forwards the datagrams received from WireGuard towards the remote gateway, until the relay is closed.
+// Datagrams are dropped if the connection cannot be established, as WireGuard takes care of retransmissions.
+func (r *streamRelay) run() {
+	buff := make([]byte, maxDatagramSize)
+	for {
+		n, err := r.local.Read(buff)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			klog.Errorf("Failed to read from relay socket %s: %v", r.local.LocalAddr(), err)
+			continue
+		}
+
+		stream, err := r.getStream()
+		if err != nil {
+			klog.V(4).Infof("Dropping datagram, as the relay %s is not connected: %v", r.local.LocalAddr(), err)
+			continue
+		}
+		if err := stream.WriteDatagram(buff[:n]); err != nil {
+			klog.Warningf("Failed to forward datagram through relay %s: %v", r.local.LocalAddr(), err)
+			r.resetStream(stream)
+		}
+	}
+}
+
+// receive forwards the datagrams received from the given connection to the local WireGuard socket, until it fails.
+func (r *streamRelay) receive(stream datagramConn) {
+	for {
+		b, err := stream.ReadDatagram()
+		if err != nil {
+			klog.V(4).Infof("Connection of relay %s terminated: %v", r.local.LocalAddr(), err)
+			r.resetStream(stream)
+			return
+		}
+		if _, err := r.local.WriteToUDP(b, r.parent.wgAddr); err != nil {
+			klog.Warningf("Failed to forward datagram to WireGuard through relay %s: %v", r.local.LocalAddr(), err)
+		}
+	}
+}
+
+// getStream returns the connection towards the remote gateway, establishing it if necessary.
+func (r *streamRelay) getStream() (datagramConn, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.stream != nil {
+		return r.stream, nil
+	}
+	if time.Since(r.lastDial) < transportDialBackoff {
+		return nil, fmt.Errorf("waiting before reconnecting to %s", r.remote)
+	}
+	r.lastDial = time.Now()
+
+	var stream datagramConn
+	if err := r.parent.inHostNetns(func() (err error) {
+		stream, err = dialStream(r.ctx, r.remote, r.kind)
+		return err
+	}); err != nil {
+		klog.Warningf("Failed to connect to %s through %s: %v", r.remote, r.kind, err)
+		return nil, err
+	}
+
+	klog.Infof("Relay %s connected to %s through %s", r.local.LocalAddr(), r.remote, r.kind)
+	r.stream = stream
+	go r.receive(stream)
+	return stream, nil
+}
+
+// resetStream closes the given connection, so that a new one is established upon the next datagram.
+func (r *streamRelay) resetStream(stream datagramConn) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.stream == stream {
+		r.stream = nil
+	}
+	stream.Close()
+}
+
+// update modifies the remote endpoint and the transport of the relay, closing the current connection if they changed.
+func (r *streamRelay) update(remote, kind string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.remote == remote && r.kind == kind {
+		return
+	}
+	r.remote, r.kind, r.lastDial = remote, kind, time.Time{}
+	if r.stream != nil {
+		r.stream.Close()
+		r.stream = nil
+	}
+}
+
+// close closes the relay.
+func (r *streamRelay) close() {
+	r.cancel()
+	r.local.Close()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.stream != nil {
+		r.stream.Close()
+		r.stream = nil
+	}
+}
+
+// dialStream establishes a connection of the given transport towards the given remote gateway.
+func dialStream(ctx context.Context, remote, kind string) (datagramConn, error) {
+	conn, err := dialTCP(ctx, remote)
+	if err != nil {
+		return nil, err
+	}
+
+	if kind == liqoconst.WgTransportTCP {
+		return &tcpDatagramConn{Conn: conn}, nil
+	}
+
+	config, err := websocket.NewConfig(fmt.Sprintf("ws://%s%s", remote, webSocketPath), fmt.Sprintf("http://%s", remote))
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := conn.SetDeadline(time.Now().Add(transportDialTimeout)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	ws, err := websocket.NewClient(config, conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("WebSocket handshake failed: %w", err)
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		ws.Close()
+		return nil, err
+	}
+	ws.PayloadType = websocket.BinaryFrame
+	return &wsDatagramConn{Conn: ws}, nil
+}
+
+// dialTCP establishes a TCP connection towards the given address, possibly through the configured HTTP(S) proxy.
+func dialTCP(ctx context.Context, address string) (net.Conn, error) {
+	proxy, err := getProxyURL(address)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := net.Dialer{Timeout: transportDialTimeout}
+	if proxy == nil {
+		return dialer.DialContext(ctx, "tcp", address)
+	}
+
+	proxyAddress := proxy.Host
+	if proxy.Port() == "" {
+		port := "80"
+		if proxy.Scheme == "https" {
+			port = "443"
+		}
+		proxyAddress = net.JoinHostPort(proxy.Hostname(), port)
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", proxyAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to proxy %s: %w", proxyAddress, err)
+	}
+	if proxy.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: proxy.Hostname(), MinVersion: tls.VersionTLS12})
+		tctx, cancel := context.WithTimeout(ctx, transportDialTimeout)
+		defer cancel()
+		if err := tlsConn.HandshakeContext(tctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("TLS handshake with proxy %s failed: %w", proxyAddress, err)
+		}
+		conn = tlsConn
+	}
+
+	tunneled, err := connectThroughProxy(conn, proxy, address)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tunneled, nil
+}
+
+// connectThroughProxy requests the proxy the given connection is established to, to tunnel it towards the given address.
+func connectThroughProxy(conn net.Conn, proxy *url.URL, address string) (net.Conn, error) {
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: make(http.Header),
+	}
+	if proxy.User != nil {
+		password, _ := proxy.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxy.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if err := conn.SetDeadline(time.Now().Add(transportDialTimeout)); err != nil {
+		return nil, err
+	}
+	if err := req.Write(conn); err != nil {
+		return nil, fmt.Errorf("failed to send request to proxy %s: %w", proxy.Host, err)
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from proxy %s: %w", proxy.Host, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("proxy %s refused the connection to %s: %s", proxy.Host, address, resp.Status)
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+	return &bufferedConn{Conn: conn, reader: reader}, nil
+}
+
+// getProxyURL returns the URL of the proxy to be leveraged to reach the given address, or nil if it shall be reached directly.
+func getProxyURL(address string) (*url.URL, error) {
+	if TunnelProxyURL != "" {
+		proxy, err := url.Parse(TunnelProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %q: %w", TunnelProxyURL, err)
+		}
+		return proxy, nil
+	}
+	return http.ProxyFromEnvironment(&http.Request{URL: &url.URL{Scheme: "https", Host: address}})
+}
+
+// getTransport returns the transport to be leveraged to reach the remote gateway, given its back-end configuration.
+func getTransport(backendConfig map[string]string) (string, error) {
+	switch transport := backendConfig[liqoconst.WgTransport]; transport {
+	case "":
+		return liqoconst.WgTransportWebSocket, nil
+	case liqoconst.WgTransportTCP, liqoconst.WgTransportWebSocket:
+		return transport, nil
+	default:
+		return "", fmt.Errorf("unsupported transport %q", transport)
+	}
+}
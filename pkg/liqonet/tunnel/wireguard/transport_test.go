@@ -0,0 +1,191 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguard
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+)
+
+var _ = Describe("Stream transport", func() {
+	Describe("The tcpDatagramConn", func() {
+		It("should preserve the boundaries of the datagrams", func() {
+			local, remote := net.Pipe()
+			defer local.Close()
+			defer remote.Close()
+			sender, receiver := &tcpDatagramConn{Conn: local}, &tcpDatagramConn{Conn: remote}
+
+			go func() {
+				defer GinkgoRecover()
+				Expect(sender.WriteDatagram([]byte("foo"))).To(Succeed())
+				Expect(sender.WriteDatagram([]byte{})).To(Succeed())
+				Expect(sender.WriteDatagram([]byte("bar-baz"))).To(Succeed())
+			}()
+
+			for _, expected := range []string{"foo", "", "bar-baz"} {
+				b, err := receiver.ReadDatagram()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(b)).To(Equal(expected))
+			}
+		})
+
+		It("should refuse datagrams exceeding the maximum size", func() {
+			local, _ := net.Pipe()
+			defer local.Close()
+			Expect((&tcpDatagramConn{Conn: local}).WriteDatagram(make([]byte, maxDatagramSize+1))).ToNot(Succeed())
+		})
+	})
+
+	DescribeTable("The getTransport function",
+		func(backendConfig map[string]string, expected string, expectedErr bool) {
+			transport, err := getTransport(backendConfig)
+			if expectedErr {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+			Expect(err).ToNot(HaveOccurred())
+			Expect(transport).To(Equal(expected))
+		},
+		Entry("transport not set", map[string]string{}, liqoconst.WgTransportWebSocket, false),
+		Entry("tcp transport", map[string]string{liqoconst.WgTransport: liqoconst.WgTransportTCP}, liqoconst.WgTransportTCP, false),
+		Entry("websocket transport", map[string]string{liqoconst.WgTransport: liqoconst.WgTransportWebSocket}, liqoconst.WgTransportWebSocket, false),
+		Entry("invalid transport", map[string]string{liqoconst.WgTransport: "quic"}, "", true),
+	)
+
+	Describe("Relaying the traffic between two gateways", func() {
+		var (
+			// localWg and remoteWg mock the WireGuard sockets of the two gateways.
+			localWg, remoteWg       *net.UDPConn
+			localT, remoteT         *streamTransport
+			proxy                   *httptest.Server
+			proxied                 atomic.Int32
+			useProxy                bool
+			remoteEndpoint, relayEp *net.UDPAddr
+		)
+
+		newWgSocket := func() *net.UDPConn {
+			conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+			Expect(err).ToNot(HaveOccurred())
+			return conn
+		}
+
+		receive := func(conn *net.UDPConn) (string, *net.UDPAddr) {
+			buff := make([]byte, 1024)
+			Expect(conn.SetReadDeadline(time.Now().Add(5 * time.Second))).To(Succeed())
+			n, from, err := conn.ReadFromUDP(buff)
+			Expect(err).ToNot(HaveOccurred())
+			return string(buff[:n]), from
+		}
+
+		BeforeEach(func() {
+			useProxy = false
+			proxied.Store(0)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			localWg, remoteWg = newWgSocket(), newWgSocket()
+			localT, err = newStreamTransport(localWg.LocalAddr().(*net.UDPAddr).Port, nil)
+			Expect(err).ToNot(HaveOccurred())
+			remoteT, err = newStreamTransport(remoteWg.LocalAddr().(*net.UDPAddr).Port, nil)
+			Expect(err).ToNot(HaveOccurred())
+			remoteEndpoint = &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: remoteWg.LocalAddr().(*net.UDPAddr).Port}
+
+			if useProxy {
+				proxy = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					defer GinkgoRecover()
+					Expect(r.Method).To(Equal(http.MethodConnect))
+					upstream, err := net.Dial("tcp", r.Host)
+					Expect(err).ToNot(HaveOccurred())
+					proxied.Add(1)
+					w.WriteHeader(http.StatusOK)
+					conn, buff, err := w.(http.Hijacker).Hijack()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(buff.Reader.Buffered()).To(BeZero())
+					go func() { _, _ = io.Copy(upstream, conn); upstream.Close() }()
+					go func() { _, _ = io.Copy(conn, upstream); conn.Close() }()
+				}))
+				TunnelProxyURL = proxy.URL
+			}
+		})
+
+		AfterEach(func() {
+			localT.close()
+			remoteT.close()
+			localWg.Close()
+			remoteWg.Close()
+			if proxy != nil {
+				proxy.CloseClientConnections()
+				proxy.Close()
+				proxy = nil
+			}
+			TunnelProxyURL = ""
+		})
+
+		for _, transport := range []string{liqoconst.WgTransportTCP, liqoconst.WgTransportWebSocket} {
+			for _, withProxy := range []bool{false, true} {
+				transport, withProxy := transport, withProxy
+				When("using the "+transport+" transport"+map[bool]string{true: " through a proxy", false: ""}[withProxy], func() {
+					BeforeEach(func() { useProxy = withProxy })
+
+					JustBeforeEach(func() {
+						var err error
+						relayEp, err = localT.relay("remote-cluster", remoteEndpoint, transport)
+						Expect(err).ToNot(HaveOccurred())
+					})
+
+					It("should relay the datagrams in both directions", func() {
+						_, err := localWg.WriteToUDP([]byte("ping"), relayEp)
+						Expect(err).ToNot(HaveOccurred())
+
+						msg, from := receive(remoteWg)
+						Expect(msg).To(Equal("ping"))
+
+						_, err = remoteWg.WriteToUDP([]byte("pong"), from)
+						Expect(err).ToNot(HaveOccurred())
+
+						msg, from = receive(localWg)
+						Expect(msg).To(Equal("pong"))
+						Expect(from.Port).To(Equal(relayEp.Port))
+
+						if withProxy {
+							Expect(proxied.Load()).To(BeNumerically("==", 1))
+						}
+					})
+
+					It("should preserve the relay address when updated", func() {
+						ep, err := localT.relay("remote-cluster", remoteEndpoint, liqoconst.WgTransportTCP)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(ep).To(Equal(relayEp))
+					})
+
+					It("should close the relay when released", func() {
+						localT.release("remote-cluster")
+						Expect(localT.relays).ToNot(HaveKey("remote-cluster"))
+					})
+				})
+			}
+		}
+	})
+})
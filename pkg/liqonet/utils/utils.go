@@ -33,6 +33,7 @@ import (
 )
 
 // MapIPToNetwork creates a new IP address obtained by means of the old IP address and the new network.
+// Both IPv4 and IPv6 addresses are supported, as long as the old IP and the new network belong to the same family.
 func MapIPToNetwork(newNetwork, oldIP string) (newIP string, err error) {
 	if newNetwork == consts.DefaultCIDRValue {
 		return oldIP, nil
@@ -46,14 +47,20 @@ func MapIPToNetwork(newNetwork, oldIP string) (newIP string, err error) {
 	mask := network.Mask
 	// Get slice of bytes for newNetwork
 	// Type net.IP has underlying type []byte
-	parsedNewIP := ip.To4()
+	parsedNewIP := ip.To16()
 	// Get oldIP as slice of bytes
 	parsedOldIP := net.ParseIP(oldIP)
 	if parsedOldIP == nil {
 		return "", fmt.Errorf("cannot parse oldIP")
 	}
-	parsedOldIP = parsedOldIP.To4()
-	// Substitute the last 32-mask bits of newNetwork with bits taken by the old ip
+	if (ip.To4() == nil) != (parsedOldIP.To4() == nil) {
+		return "", fmt.Errorf("IP %s and network %s belong to different IP families", oldIP, newNetwork)
+	}
+	if ip.To4() != nil {
+		parsedNewIP = ip.To4()
+		parsedOldIP = parsedOldIP.To4()
+	}
+	// Substitute the last len(mask)-ones bits of newNetwork with bits taken by the old ip
 	for i := 0; i < len(mask); i++ {
 		// Step 1: NOT(mask[i]) = mask[i] ^ 0xff. They are the 'host' bits
 		// Step 2: BITWISE AND between the host bits and parsedOldIP[i] zeroes the network bits in parsedOldIP[i]
@@ -82,6 +89,17 @@ func GetPodIP() (net.IP, error) {
 	return net.ParseIP(ipAddress), nil
 }
 
+// GetPodIPv6 returns the IPv6 address of the pod, retrieved from the POD_IPS environment variable.
+// It returns nil if the pod has no IPv6 address (e.g., in single-stack IPv4 clusters).
+func GetPodIPv6() net.IP {
+	for _, address := range strings.Split(os.Getenv("POD_IPS"), ",") {
+		if ip := net.ParseIP(strings.TrimSpace(address)); ip != nil && ip.To4() == nil {
+			return ip
+		}
+	}
+	return nil
+}
+
 // GetPodNamespace gets the namespace of the pod passed as an environment variable.
 func GetPodNamespace() (string, error) {
 	namespace, isSet := os.LookupEnv("POD_NAMESPACE")
@@ -112,7 +130,7 @@ func GetMask(network string) uint8 {
 func SetMask(network string, mask uint8) string {
 	_, n, err := net.ParseCIDR(network)
 	utilruntime.Must(err)
-	newMask := net.CIDRMask(int(mask), 32)
+	newMask := net.CIDRMask(int(mask), 8*len(n.IP))
 	n.Mask = newMask
 	return n.String()
 }
@@ -162,6 +180,18 @@ func IsValidCIDR(cidr string) error {
 	return err
 }
 
+// IsIPv6 returns true if the received string is a valid IPv6 address.
+func IsIPv6(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	return err == nil && addr.Is6() && !addr.Is4In6()
+}
+
+// IsIPv6CIDR returns true if the received string is a valid IPv6 network, in CIDR notation.
+func IsIPv6CIDR(cidr string) bool {
+	prefix, err := netip.ParsePrefix(cidr)
+	return err == nil && prefix.Addr().Is6()
+}
+
 // GetFirstIP returns the first IP address of a network.
 func GetFirstIP(network string) (string, error) {
 	firstIP, _, err := net.ParseCIDR(network)
@@ -180,6 +210,72 @@ func GetTunnelIP(externalCIDR string) (string, error) {
 	return ipPrefix.Addr().Next().String(), nil
 }
 
+// GetIPv6TunnelEndpoint returns a copy of the given TunnelEndpoint where the IPv6 networks
+// replace the IPv4 ones, so that the same logic can be applied to configure both families.
+// The second return value is false if the TunnelEndpoint does not carry IPv6 networks (i.e., single-stack peering).
+func GetIPv6TunnelEndpoint(tep *netv1alpha1.TunnelEndpoint) (*netv1alpha1.TunnelEndpoint, bool) {
+	if tep.Spec.LocalPodCIDRv6 == "" || tep.Spec.RemotePodCIDRv6 == "" {
+		return nil, false
+	}
+	defaultIfEmpty := func(cidr string) string {
+		if cidr == "" {
+			return consts.DefaultCIDRValue
+		}
+		return cidr
+	}
+
+	tepv6 := tep.DeepCopy()
+	tepv6.Spec.LocalPodCIDR = tep.Spec.LocalPodCIDRv6
+	tepv6.Spec.LocalNATPodCIDR = defaultIfEmpty(tep.Spec.LocalNATPodCIDRv6)
+	tepv6.Spec.LocalExternalCIDR = tep.Spec.LocalExternalCIDRv6
+	tepv6.Spec.LocalNATExternalCIDR = defaultIfEmpty(tep.Spec.LocalNATExternalCIDRv6)
+	tepv6.Spec.RemotePodCIDR = tep.Spec.RemotePodCIDRv6
+	tepv6.Spec.RemoteNATPodCIDR = defaultIfEmpty(tep.Spec.RemoteNATPodCIDRv6)
+	tepv6.Spec.RemoteExternalCIDR = tep.Spec.RemoteExternalCIDRv6
+	tepv6.Spec.RemoteNATExternalCIDR = defaultIfEmpty(tep.Spec.RemoteNATExternalCIDRv6)
+	// Transit networks are supported for the IPv4 family only.
+	tepv6.Spec.RemoteTransitNetworks = nil
+	tepv6.Spec.LocalTransitNetworks = nil
+	return tepv6, true
+}
+
+// GetTransitPodCIDR returns the network used for the pods of a cluster reachable through a transit (hub) cluster
+// in the spoke cluster, which depends on whether it has been remapped or not.
+func GetTransitPodCIDR(tn *netv1alpha1.TransitNetworkMapping) string {
+	if tn.NATPodCIDR != "" && tn.NATPodCIDR != consts.DefaultCIDRValue {
+		return tn.NATPodCIDR
+	}
+	return tn.PodCIDR
+}
+
+// GetTransitTunnelEndpoints returns a copy of the given TunnelEndpoint for each network reachable through the remote
+// cluster (acting as a transit cluster), where the transit network replaces the remote PodCIDR, so that the same logic
+// can be applied to route it.
+func GetTransitTunnelEndpoints(tep *netv1alpha1.TunnelEndpoint) []*netv1alpha1.TunnelEndpoint {
+	teps := make([]*netv1alpha1.TunnelEndpoint, 0, len(tep.Spec.RemoteTransitNetworks))
+	for i := range tep.Spec.RemoteTransitNetworks {
+		tn := &tep.Spec.RemoteTransitNetworks[i]
+		transit := tep.DeepCopy()
+		transit.Spec.RemotePodCIDR = tn.PodCIDR
+		transit.Spec.RemoteNATPodCIDR = consts.DefaultCIDRValue
+		if tn.NATPodCIDR != "" {
+			transit.Spec.RemoteNATPodCIDR = tn.NATPodCIDR
+		}
+		transit.Spec.RemoteTransitNetworks = nil
+		transit.Spec.LocalTransitNetworks = nil
+		teps = append(teps, transit)
+	}
+	return teps
+}
+
+// GetTunnelEndpointsPerFamily returns the given TunnelEndpoint, followed by its IPv6 counterpart in case of dual-stack peerings.
+func GetTunnelEndpointsPerFamily(tep *netv1alpha1.TunnelEndpoint) []*netv1alpha1.TunnelEndpoint {
+	if tepv6, ok := GetIPv6TunnelEndpoint(tep); ok {
+		return []*netv1alpha1.TunnelEndpoint{tep, tepv6}
+	}
+	return []*netv1alpha1.TunnelEndpoint{tep}
+}
+
 // CheckTep checks validity of TunnelEndpoint resource fields.
 func CheckTep(tep *netv1alpha1.TunnelEndpoint) error {
 	if tep.Spec.ClusterIdentity.ClusterID == "" {
@@ -258,6 +354,20 @@ func GetOverlayIP(ip string) string {
 	return strings.Join([]string{consts.OverlayNetworkPrefix, tokens[1], tokens[2], tokens[3]}, ".")
 }
 
+// GetOverlayIPv6 maps the given IPv4 address in to the IPv6 overlay network, described by
+// consts.OverlayNetworkIPv6Prefix. It uses the overlay prefix and the last three octets of the original IP address.
+func GetOverlayIPv6(ip string) string {
+	addr := net.ParseIP(ip).To4()
+	// If the ip is malformed we prevent a panic, the subsequent calls
+	// that use the returned value will return an error.
+	if addr == nil {
+		return ""
+	}
+	overlay := net.ParseIP(consts.OverlayNetworkIPv6Prefix)
+	copy(overlay[net.IPv6len-3:], addr[1:])
+	return overlay.String()
+}
+
 // AddAnnotationToObj for a given object it adds the annotation with the given key and value.
 // It return a bool which is true when the annotations has been added or false if the
 // annotation is already present.
@@ -331,6 +441,11 @@ func FormatLatency(latency time.Duration) string {
 	return fmt.Sprintf("%dμs", latency.Microseconds())
 }
 
+// FormatPacketLoss returns a string representing the given packet loss ratio as a percentage.
+func FormatPacketLoss(ratio float64) string {
+	return fmt.Sprintf("%.1f%%", ratio*100)
+}
+
 // IsLocalNetworkConfig checks if the given network configuration is local.
 func IsLocalNetworkConfig(networkConfig *netv1alpha1.NetworkConfig) bool {
 	return networkConfig.Labels[consts.ReplicationRequestedLabel] == "true"
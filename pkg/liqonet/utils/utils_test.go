@@ -22,6 +22,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+	"github.com/liqotech/liqo/pkg/consts"
 	liqonetutils "github.com/liqotech/liqo/pkg/liqonet/utils"
 )
 
@@ -70,6 +72,30 @@ var _ = Describe("Liqonet", func() {
 		Entry("Mapping 10.2.128.128 to 10.0.126.0/25", "10.0.126.0/25", "10.2.128.128", "10.0.126.0", ""),
 		Entry("Using an invalid newPodCidr", "10.0..0/25", "10.2.128.128", "", "invalid CIDR address: 10.0..0/25"),
 		Entry("Using an invalid oldIp", "10.0.0.0/25", "10.2...128", "", "cannot parse oldIP"),
+		Entry("Mapping fd00:1::1:2 to fd00:2::/64", "fd00:2::/64", "fd00:1::1:2", "fd00:2::1:2", ""),
+		Entry("Mapping fd00:1::ab:1:2 to fd00:2::/112", "fd00:2::/112", "fd00:1::ab:1:2", "fd00:2::2", ""),
+		Entry("Mapping an IPv4 address to an IPv6 network", "fd00:2::/64", "10.2.1.3", "",
+			"IP 10.2.1.3 and network fd00:2::/64 belong to different IP families"),
+		Entry("Mapping an IPv6 address to an IPv4 network", "10.0.4.0/24", "fd00:1::1", "",
+			"IP fd00:1::1 and network 10.0.4.0/24 belong to different IP families"),
+	)
+
+	DescribeTable("SetMask",
+		func(network string, mask uint8, expected string) {
+			Expect(liqonetutils.SetMask(network, mask)).To(Equal(expected))
+		},
+		Entry("Setting mask 9 to 10.0.0.0/8", "10.0.0.0/8", uint8(9), "10.0.0.0/9"),
+		Entry("Setting mask 9 to fd00::/8", "fd00::/8", uint8(9), "fd00::/9"),
+		Entry("Setting mask 65 to fd00:1::/64", "fd00:1::/64", uint8(65), "fd00:1::/65"),
+	)
+
+	DescribeTable("IsIPv6CIDR",
+		func(network string, expected bool) {
+			Expect(liqonetutils.IsIPv6CIDR(network)).To(Equal(expected))
+		},
+		Entry("Passing an IPv4 network", "10.0.0.0/8", false),
+		Entry("Passing an IPv6 network", "fd00::/8", true),
+		Entry("Passing an invalid network", invalidValue, false),
 	)
 
 	DescribeTable("GetFirstIP",
@@ -103,6 +129,115 @@ var _ = Describe("Liqonet", func() {
 		})
 	})
 
+	Describe("testing getOverlayIPv6 function", func() {
+		Context("when input parameter is correct", func() {
+			It("should return a valid ip", func() {
+				Expect(liqonetutils.GetOverlayIPv6("10.200.1.1")).Should(Equal("fd00:240::c8:101"))
+			})
+		})
+
+		Context("when input parameter is not correct", func() {
+			It("should return an empty string", func() {
+				Expect(liqonetutils.GetOverlayIPv6("10.200.")).Should(Equal(""))
+			})
+		})
+	})
+
+	Describe("testing GetIPv6TunnelEndpoint function", func() {
+		var tep *netv1alpha1.TunnelEndpoint
+
+		BeforeEach(func() {
+			tep = &netv1alpha1.TunnelEndpoint{Spec: netv1alpha1.TunnelEndpointSpec{
+				LocalPodCIDR:          "10.0.0.0/16",
+				LocalNATPodCIDR:       consts.DefaultCIDRValue,
+				LocalExternalCIDR:     "10.1.0.0/16",
+				LocalNATExternalCIDR:  consts.DefaultCIDRValue,
+				RemotePodCIDR:         "10.0.0.0/16",
+				RemoteNATPodCIDR:      "10.2.0.0/16",
+				RemoteExternalCIDR:    "10.1.0.0/16",
+				RemoteNATExternalCIDR: "10.3.0.0/16",
+			}}
+		})
+
+		Context("when the tunnel endpoint does not carry IPv6 networks", func() {
+			It("should return false", func() {
+				_, ok := liqonetutils.GetIPv6TunnelEndpoint(tep)
+				Expect(ok).To(BeFalse())
+				Expect(liqonetutils.GetTunnelEndpointsPerFamily(tep)).To(ConsistOf(tep))
+			})
+		})
+
+		Context("when the tunnel endpoint carries IPv6 networks", func() {
+			BeforeEach(func() {
+				tep.Spec.LocalPodCIDRv6 = "fd00:10::/64"
+				tep.Spec.LocalExternalCIDRv6 = "fd00:11::/64"
+				tep.Spec.LocalNATExternalCIDRv6 = "fd00:12::/64"
+				tep.Spec.RemotePodCIDRv6 = "fd00:10::/64"
+				tep.Spec.RemoteNATPodCIDRv6 = "fd00:13::/64"
+				tep.Spec.RemoteExternalCIDRv6 = "fd00:11::/64"
+			})
+
+			It("should return a copy with the IPv6 networks in place of the IPv4 ones", func() {
+				tepv6, ok := liqonetutils.GetIPv6TunnelEndpoint(tep)
+				Expect(ok).To(BeTrue())
+				Expect(tepv6.Spec.LocalPodCIDR).To(Equal("fd00:10::/64"))
+				Expect(tepv6.Spec.LocalNATPodCIDR).To(Equal(consts.DefaultCIDRValue))
+				Expect(tepv6.Spec.LocalExternalCIDR).To(Equal("fd00:11::/64"))
+				Expect(tepv6.Spec.LocalNATExternalCIDR).To(Equal("fd00:12::/64"))
+				Expect(tepv6.Spec.RemotePodCIDR).To(Equal("fd00:10::/64"))
+				Expect(tepv6.Spec.RemoteNATPodCIDR).To(Equal("fd00:13::/64"))
+				Expect(tepv6.Spec.RemoteExternalCIDR).To(Equal("fd00:11::/64"))
+				Expect(tepv6.Spec.RemoteNATExternalCIDR).To(Equal(consts.DefaultCIDRValue))
+				Expect(tep.Spec.LocalPodCIDR).To(Equal("10.0.0.0/16"))
+				Expect(liqonetutils.GetTunnelEndpointsPerFamily(tep)).To(HaveLen(2))
+			})
+		})
+	})
+
+	Describe("testing GetTransitTunnelEndpoints function", func() {
+		var tep *netv1alpha1.TunnelEndpoint
+
+		BeforeEach(func() {
+			tep = &netv1alpha1.TunnelEndpoint{Spec: netv1alpha1.TunnelEndpointSpec{
+				LocalPodCIDR:          "10.0.0.0/16",
+				LocalNATPodCIDR:       consts.DefaultCIDRValue,
+				RemotePodCIDR:         "10.10.0.0/16",
+				RemoteNATPodCIDR:      consts.DefaultCIDRValue,
+				RemoteExternalCIDR:    "10.11.0.0/16",
+				RemoteNATExternalCIDR: consts.DefaultCIDRValue,
+				RemoteTransitNetworks: []netv1alpha1.TransitNetworkMapping{
+					{PodCIDR: "10.20.0.0/16", NATPodCIDR: consts.DefaultCIDRValue},
+					{PodCIDR: "10.0.0.0/16", NATPodCIDR: "10.30.0.0/16"},
+				},
+			}}
+		})
+
+		It("should return a copy for each transit network, with the transit network in place of the remote PodCIDR", func() {
+			teps := liqonetutils.GetTransitTunnelEndpoints(tep)
+			Expect(teps).To(HaveLen(2))
+			_, remotePodCIDR := liqonetutils.GetPodCIDRS(teps[0])
+			Expect(remotePodCIDR).To(Equal("10.20.0.0/16"))
+			_, remotePodCIDR = liqonetutils.GetPodCIDRS(teps[1])
+			Expect(remotePodCIDR).To(Equal("10.30.0.0/16"))
+			Expect(teps[1].Spec.RemoteExternalCIDR).To(Equal("10.11.0.0/16"))
+			Expect(teps[1].Spec.RemoteTransitNetworks).To(BeNil())
+			Expect(tep.Spec.RemotePodCIDR).To(Equal("10.10.0.0/16"))
+		})
+
+		It("should return the network used in the spoke cluster for each transit network", func() {
+			Expect(liqonetutils.GetTransitPodCIDR(&tep.Spec.RemoteTransitNetworks[0])).To(Equal("10.20.0.0/16"))
+			Expect(liqonetutils.GetTransitPodCIDR(&tep.Spec.RemoteTransitNetworks[1])).To(Equal("10.30.0.0/16"))
+		})
+
+		It("should not carry the transit networks on the IPv6 counterpart", func() {
+			tep.Spec.LocalPodCIDRv6 = "fd00:10::/64"
+			tep.Spec.RemotePodCIDRv6 = "fd00:20::/64"
+			tepv6, ok := liqonetutils.GetIPv6TunnelEndpoint(tep)
+			Expect(ok).To(BeTrue())
+			Expect(tepv6.Spec.RemoteTransitNetworks).To(BeNil())
+		})
+	})
+
 	Describe("testing AddAnnotationToObj function", func() {
 		Context("when annotations map is nil", func() {
 			It("should create the map and return true", func() {
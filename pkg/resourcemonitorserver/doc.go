@@ -0,0 +1,19 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package resourcemonitorserver contains a reference implementation of the resource_reader gRPC service,
+// which is consumed by the ExternalResourceMonitor. The resources are retrieved from a set of pluggable
+// sources (i.e., a subset of the cluster nodes, a static file and Prometheus queries), each one publishing
+// one or more resource pools.
+package resourcemonitorserver
@@ -0,0 +1,172 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcemonitorserver
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	resourcehelper "k8s.io/kubectl/pkg/util/resource"
+
+	"github.com/liqotech/liqo/pkg/consts"
+	resourcemonitors "github.com/liqotech/liqo/pkg/liqo-controller-manager/resource-request-controller/resource-monitors"
+	"github.com/liqotech/liqo/pkg/utils"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+)
+
+var _ Source = &NodeSource{}
+
+// NodeSource is a source publishing the resources available in the subset of
+// physical nodes matching a label selector (allocatable minus requested by running pods).
+type NodeSource struct {
+	notifierHolder
+
+	pool  Pool
+	nodes corev1listers.NodeLister
+	pods  corev1listers.PodLister
+}
+
+// NewNodeSource creates a new NodeSource, publishing the resources of the nodes matching the given selector as the given pool.
+// Virtual nodes are always excluded.
+func NewNodeSource(ctx context.Context, clientset kubernetes.Interface, selector labels.Selector,
+	pool Pool, resyncPeriod time.Duration) *NodeSource {
+	req, err := labels.NewRequirement(consts.TypeLabel, selection.NotEquals, []string{consts.TypeNode})
+	utilruntime.Must(err)
+	nodeSelector := selector.DeepCopySelector().Add(*req)
+
+	nodeFactory := informers.NewSharedInformerFactoryWithOptions(clientset, resyncPeriod,
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) { opts.LabelSelector = nodeSelector.String() }))
+	podFactory := informers.NewSharedInformerFactoryWithOptions(clientset, resyncPeriod,
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fields.OneTermEqualSelector("status.phase", string(corev1.PodRunning)).String()
+		}))
+
+	source := &NodeSource{
+		pool:  pool,
+		nodes: nodeFactory.Core().V1().Nodes().Lister(),
+		pods:  podFactory.Core().V1().Pods().Lister(),
+	}
+
+	_, err = nodeFactory.Core().V1().Nodes().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { source.notify(resourcemonitors.AllClusterIDs) },
+		UpdateFunc: source.onNodeUpdate,
+		DeleteFunc: func(interface{}) { source.notify(resourcemonitors.AllClusterIDs) },
+	})
+	utilruntime.Must(err)
+	_, err = podFactory.Core().V1().Pods().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: source.onPodEvent,
+		// We do not care about update events, since resources are immutable.
+		DeleteFunc: source.onPodEvent,
+	})
+	utilruntime.Must(err)
+
+	nodeFactory.Start(ctx.Done())
+	nodeFactory.WaitForCacheSync(ctx.Done())
+	podFactory.Start(ctx.Done())
+	podFactory.WaitForCacheSync(ctx.Done())
+
+	return source
+}
+
+// Register sets an update notifier.
+func (s *NodeSource) Register(_ context.Context, notifier resourcemonitors.ResourceUpdateNotifier) {
+	s.setNotifier(notifier)
+}
+
+// ReadResources returns the resources available in the selected nodes, including those currently used by the given cluster.
+func (s *NodeSource) ReadResources(_ context.Context, clusterID string) ([]*resourcemonitors.ResourceList, error) {
+	nodes, err := s.nodes.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	pods, err := s.pods.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	available := corev1.ResourceList{}
+	ready := make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
+		if !utils.IsNodeReady(node) {
+			continue
+		}
+		ready[node.Name] = struct{}{}
+		for name, quantity := range node.Status.Allocatable {
+			value := available[name]
+			value.Add(quantity)
+			available[name] = value
+		}
+	}
+
+	for _, pod := range pods {
+		if _, found := ready[pod.Spec.NodeName]; !found {
+			continue
+		}
+		// The resources used by the pods offloaded by the given cluster are still available to it.
+		if clusterID != resourcemonitors.AllClusterIDs && pod.Labels[forge.LiqoOriginClusterIDKey] == clusterID {
+			continue
+		}
+		requests, _ := resourcehelper.PodRequestsAndLimits(pod)
+		for name, quantity := range requests {
+			if value, found := available[name]; found {
+				value.Sub(quantity)
+				available[name] = value
+			}
+		}
+	}
+
+	clampToZero(available)
+	return []*resourcemonitors.ResourceList{s.pool.resourceList(available)}, nil
+}
+
+// RemoveClusterID is a no-op, since the source does not keep any per-cluster state.
+func (s *NodeSource) RemoveClusterID(context.Context, string) error {
+	return nil
+}
+
+func (s *NodeSource) onNodeUpdate(oldObj, newObj interface{}) {
+	oldNode, newNode := oldObj.(*corev1.Node), newObj.(*corev1.Node)
+	// Skip the periodic status updates not affecting the available resources.
+	if utils.IsNodeReady(oldNode) == utils.IsNodeReady(newNode) &&
+		equality.Semantic.DeepEqual(oldNode.Status.Allocatable, newNode.Status.Allocatable) {
+		return
+	}
+	s.notify(resourcemonitors.AllClusterIDs)
+}
+
+func (s *NodeSource) onPodEvent(obj interface{}) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		// Tombstone of a deleted pod.
+		s.notify(resourcemonitors.AllClusterIDs)
+		return
+	}
+	// Pods not scheduled on the selected nodes do not affect the available resources.
+	if _, err := s.nodes.Get(pod.Spec.NodeName); err != nil {
+		return
+	}
+	s.notify(resourcemonitors.AllClusterIDs)
+}
@@ -0,0 +1,88 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcemonitorserver
+
+import (
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	resourcemonitors "github.com/liqotech/liqo/pkg/liqo-controller-manager/resource-request-controller/resource-monitors"
+)
+
+// Pool identifies a resource pool published by a source.
+type Pool struct {
+	// Name is the name of the pool, propagated as the pool_name of the resource list.
+	Name string `json:"poolName,omitempty"`
+	// Prefix is the prefix of the pool, propagated as the pool_prefix of the resource list.
+	Prefix string `json:"poolPrefix,omitempty"`
+	// Labels are the labels to be added to the virtual node corresponding to the pool.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Taints are the taints to be added to the virtual node corresponding to the pool.
+	Taints []corev1.Taint `json:"taints,omitempty"`
+}
+
+// resourceList returns the resource list describing the given resources of the pool.
+func (p Pool) resourceList(resources corev1.ResourceList) *resourcemonitors.ResourceList {
+	list := &resourcemonitors.ResourceList{
+		Resources:  make(map[string]*resource.Quantity, len(resources)),
+		PoolName:   p.Name,
+		PoolPrefix: p.Prefix,
+		Labels:     p.Labels,
+	}
+	for name, quantity := range resources {
+		quantity := quantity.DeepCopy()
+		list.Resources[name.String()] = &quantity
+	}
+	for i := range p.Taints {
+		list.Taints = append(list.Taints, &resourcemonitors.Taint{
+			Key:    p.Taints[i].Key,
+			Value:  p.Taints[i].Value,
+			Effect: string(p.Taints[i].Effect),
+		})
+	}
+	return list
+}
+
+// notifierHolder stores the notifier a source is registered with, and allows to notify it in a thread-safe manner.
+type notifierHolder struct {
+	mutex    sync.RWMutex
+	notifier resourcemonitors.ResourceUpdateNotifier
+}
+
+func (h *notifierHolder) setNotifier(notifier resourcemonitors.ResourceUpdateNotifier) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.notifier = notifier
+}
+
+// notify signals a change in the resources offered to the given cluster, if a notifier is registered.
+func (h *notifierHolder) notify(clusterID string) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	if h.notifier != nil {
+		h.notifier.NotifyChange(clusterID)
+	}
+}
+
+// clampToZero replaces negative quantities with zero.
+func clampToZero(resources corev1.ResourceList) {
+	for name, quantity := range resources {
+		if quantity.Sign() < 0 {
+			resources[name] = *resource.NewQuantity(0, quantity.Format)
+		}
+	}
+}
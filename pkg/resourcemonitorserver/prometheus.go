@@ -0,0 +1,157 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcemonitorserver
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	resourcemonitors "github.com/liqotech/liqo/pkg/liqo-controller-manager/resource-request-controller/resource-monitors"
+)
+
+var _ Source = &PrometheusSource{}
+
+// PrometheusQuerier is the subset of the Prometheus API used by the PrometheusSource.
+type PrometheusQuerier interface {
+	Query(ctx context.Context, query string, ts time.Time, opts ...promv1.Option) (model.Value, promv1.Warnings, error)
+}
+
+// PrometheusSource is a source publishing a resource pool whose resources are periodically
+// retrieved through Prometheus queries (one for each resource, returning a scalar or a vector to be summed).
+type PrometheusSource struct {
+	notifierHolder
+
+	querier  PrometheusQuerier
+	queries  map[corev1.ResourceName]string
+	pool     Pool
+	interval time.Duration
+
+	mutex     sync.RWMutex
+	resources corev1.ResourceList
+}
+
+// NewPrometheusSource creates a new PrometheusSource, querying the Prometheus server at the given address.
+func NewPrometheusSource(address string, queries map[corev1.ResourceName]string, pool Pool,
+	interval time.Duration) (*PrometheusSource, error) {
+	client, err := api.NewClient(api.Config{Address: address})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the Prometheus client: %w", err)
+	}
+	return NewPrometheusSourceWithQuerier(promv1.NewAPI(client), queries, pool, interval), nil
+}
+
+// NewPrometheusSourceWithQuerier creates a new PrometheusSource, leveraging the given querier.
+func NewPrometheusSourceWithQuerier(querier PrometheusQuerier, queries map[corev1.ResourceName]string,
+	pool Pool, interval time.Duration) *PrometheusSource {
+	return &PrometheusSource{
+		querier:  querier,
+		queries:  queries,
+		pool:     pool,
+		interval: interval,
+	}
+}
+
+// Register sets an update notifier, and starts periodically executing the queries.
+func (s *PrometheusSource) Register(ctx context.Context, notifier resourcemonitors.ResourceUpdateNotifier) {
+	s.setNotifier(notifier)
+	go wait.UntilWithContext(ctx, func(ctx context.Context) {
+		changed, err := s.refresh(ctx)
+		if err != nil {
+			klog.Errorf("Failed to retrieve the resources from Prometheus, keeping the previous ones: %v", err)
+			return
+		}
+		if changed {
+			s.notify(resourcemonitors.AllClusterIDs)
+		}
+	}, s.interval)
+}
+
+// ReadResources returns the resources retrieved by the last successful execution of the queries.
+func (s *PrometheusSource) ReadResources(context.Context, string) ([]*resourcemonitors.ResourceList, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.resources == nil {
+		// The queries have not been executed yet.
+		return nil, nil
+	}
+	return []*resourcemonitors.ResourceList{s.pool.resourceList(s.resources)}, nil
+}
+
+// RemoveClusterID is a no-op, since the source does not keep any per-cluster state.
+func (s *PrometheusSource) RemoveClusterID(context.Context, string) error {
+	return nil
+}
+
+// refresh executes the queries, and returns whether the resources changed.
+func (s *PrometheusSource) refresh(ctx context.Context) (changed bool, err error) {
+	resources := corev1.ResourceList{}
+	for name, query := range s.queries {
+		value, warnings, err := s.querier.Query(ctx, query, time.Now())
+		if err != nil {
+			return false, fmt.Errorf("failed to query resource %q: %w", name, err)
+		}
+		for _, warning := range warnings {
+			klog.Warningf("Query for resource %q returned warning: %s", name, warning)
+		}
+
+		quantity, err := toQuantity(value)
+		if err != nil {
+			return false, fmt.Errorf("invalid result for resource %q: %w", name, err)
+		}
+		resources[name] = quantity
+	}
+	clampToZero(resources)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.resources != nil && equality.Semantic.DeepEqual(s.resources, resources) {
+		return false, nil
+	}
+	s.resources = resources
+	return true, nil
+}
+
+// toQuantity converts the result of a query into a quantity, summing the samples in case of vectors.
+func toQuantity(value model.Value) (resource.Quantity, error) {
+	var sum float64
+	switch result := value.(type) {
+	case *model.Scalar:
+		sum = float64(result.Value)
+	case model.Vector:
+		for _, sample := range result {
+			sum += float64(sample.Value)
+		}
+	default:
+		return resource.Quantity{}, fmt.Errorf("unsupported result type %q, expected scalar or vector", value.Type())
+	}
+
+	if math.IsNaN(sum) || math.IsInf(sum, 0) {
+		return resource.Quantity{}, fmt.Errorf("the result is not a finite number")
+	}
+	return *resource.NewMilliQuantity(int64(math.Round(sum*1000)), resource.DecimalSI), nil
+}
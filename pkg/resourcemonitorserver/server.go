@@ -0,0 +1,180 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcemonitorserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+	"k8s.io/klog/v2"
+
+	resourcemonitors "github.com/liqotech/liqo/pkg/liqo-controller-manager/resource-request-controller/resource-monitors"
+)
+
+// Source is a provider of one or more resource pools exposed by the Server.
+// Sources notify the Server whenever their resources change, through the notifier they are registered with.
+type Source = resourcemonitors.ResourceReader
+
+var _ resourcemonitors.ResourceReaderServer = &Server{}
+var _ resourcemonitors.ResourceUpdateNotifier = &Server{}
+
+// Server implements the resource_reader gRPC service, aggregating the resource pools of a set of sources.
+type Server struct {
+	resourcemonitors.UnimplementedResourceReaderServer
+
+	sources []Source
+
+	mutex       sync.Mutex
+	subscribers map[*subscriber]struct{}
+}
+
+// subscriber tracks the notifications pending for a given subscription.
+type subscriber struct {
+	mutex   sync.Mutex
+	pending map[string]struct{}
+	wake    chan struct{}
+}
+
+// New returns a new Server, exposing the resources of the given sources.
+func New(sources ...Source) *Server {
+	return &Server{
+		sources:     sources,
+		subscribers: map[*subscriber]struct{}{},
+	}
+}
+
+// Start registers the server as the notifier of the sources, and serves the gRPC service on the given address.
+// It blocks until the context is canceled.
+func (s *Server) Start(ctx context.Context, address string) error {
+	for _, source := range s.sources {
+		source.Register(ctx, s)
+	}
+
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+
+	server := grpc.NewServer()
+	resourcemonitors.RegisterResourceReaderServer(server, s)
+	go func() {
+		<-ctx.Done()
+		klog.Info("Stopping the resource monitor server")
+		server.GracefulStop()
+	}()
+
+	klog.Infof("Resource monitor server listening on %s", address)
+	if err := server.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return fmt.Errorf("failed to serve the resource monitor: %w", err)
+	}
+	return nil
+}
+
+// ReadResources returns the resource pools offered to the given cluster by all the sources.
+func (s *Server) ReadResources(ctx context.Context, identity *resourcemonitors.ClusterIdentity) (*resourcemonitors.PoolResourceList, error) {
+	response := &resourcemonitors.PoolResourceList{}
+	for _, source := range s.sources {
+		resources, err := source.ReadResources(ctx, identity.GetClusterID())
+		if err != nil {
+			klog.Errorf("Failed to read the resources for cluster %q: %v", identity.GetClusterID(), err)
+			return nil, err
+		}
+		response.ResourceLists = append(response.ResourceLists, resources...)
+	}
+	return response, nil
+}
+
+// RemoveCluster removes the given cluster from all the sources.
+func (s *Server) RemoveCluster(ctx context.Context, identity *resourcemonitors.ClusterIdentity) (*resourcemonitors.Empty, error) {
+	for _, source := range s.sources {
+		if err := source.RemoveClusterID(ctx, identity.GetClusterID()); err != nil {
+			klog.Errorf("Failed to remove cluster %q: %v", identity.GetClusterID(), err)
+			return nil, err
+		}
+	}
+	return &resourcemonitors.Empty{}, nil
+}
+
+// Subscribe streams the identifiers of the clusters whose resources changed, until the client disconnects.
+func (s *Server) Subscribe(_ *resourcemonitors.Empty, stream resourcemonitors.ResourceReader_SubscribeServer) error {
+	sub := &subscriber{pending: map[string]struct{}{}, wake: make(chan struct{}, 1)}
+
+	s.mutex.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.mutex.Unlock()
+	klog.V(4).Info("New subscription to resource updates")
+
+	defer func() {
+		s.mutex.Lock()
+		delete(s.subscribers, sub)
+		s.mutex.Unlock()
+		klog.V(4).Info("Subscription to resource updates terminated")
+	}()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-sub.wake:
+			for _, clusterID := range sub.drain() {
+				if err := stream.Send(&resourcemonitors.ClusterIdentity{ClusterID: clusterID}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// NotifyChange forwards the notification to all the current subscribers.
+func (s *Server) NotifyChange(clusterID string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for sub := range s.subscribers {
+		sub.enqueue(clusterID)
+	}
+}
+
+// enqueue adds a notification for the given cluster, coalescing it with the pending ones.
+func (s *subscriber) enqueue(clusterID string) {
+	s.mutex.Lock()
+	s.pending[clusterID] = struct{}{}
+	s.mutex.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// drain returns the pending notifications, collapsing them into a single one if all clusters are involved.
+func (s *subscriber) drain() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var clusterIDs []string
+	if _, found := s.pending[resourcemonitors.AllClusterIDs]; found {
+		clusterIDs = []string{resourcemonitors.AllClusterIDs}
+	} else {
+		for clusterID := range s.pending {
+			clusterIDs = append(clusterIDs, clusterID)
+		}
+	}
+	s.pending = map[string]struct{}{}
+	return clusterIDs
+}
@@ -0,0 +1,112 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcemonitorserver
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	resourcemonitors "github.com/liqotech/liqo/pkg/liqo-controller-manager/resource-request-controller/resource-monitors"
+)
+
+const serverAddress = "127.0.0.1:7001"
+
+// fakeSource is a source returning a fixed pool, and recording the removed clusters.
+type fakeSource struct {
+	notifierHolder
+	pool    Pool
+	removed []string
+}
+
+func (s *fakeSource) Register(_ context.Context, notifier resourcemonitors.ResourceUpdateNotifier) {
+	s.setNotifier(notifier)
+}
+
+func (s *fakeSource) ReadResources(context.Context, string) ([]*resourcemonitors.ResourceList, error) {
+	return []*resourcemonitors.ResourceList{s.pool.resourceList(corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")})}, nil
+}
+
+func (s *fakeSource) RemoveClusterID(_ context.Context, clusterID string) error {
+	s.removed = append(s.removed, clusterID)
+	return nil
+}
+
+var _ = Describe("Server", func() {
+	var (
+		ctx      context.Context
+		cancel   context.CancelFunc
+		first    *fakeSource
+		second   *fakeSource
+		monitor  *resourcemonitors.ExternalResourceMonitor
+		notifier *fakeNotifier
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		first = &fakeSource{pool: Pool{Name: "first"}}
+		second = &fakeSource{pool: Pool{Name: "second", Prefix: "prefix"}}
+
+		server := New(first, second)
+		go func() {
+			defer GinkgoRecover()
+			Expect(server.Start(ctx, serverAddress)).To(Succeed())
+		}()
+
+		var err error
+		monitor, err = resourcemonitors.NewExternalMonitor(ctx, serverAddress, 10*time.Second)
+		Expect(err).ToNot(HaveOccurred())
+		notifier = newNotifier()
+		monitor.Register(ctx, notifier)
+	})
+
+	AfterEach(func() { cancel() })
+
+	It("should aggregate the pools of all the sources", func() {
+		resources, err := monitor.ReadResources(ctx, "remote-cluster")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resources).To(HaveLen(2))
+		Expect(resources[0].PoolName).To(Equal("first"))
+		Expect(resources[1].PoolName).To(Equal("second"))
+		Expect(resources[1].PoolPrefix).To(Equal("prefix"))
+		Expect(resources[1].Resources["cpu"].Equal(resource.MustParse("1"))).To(BeTrue())
+	})
+
+	It("should remove the cluster from all the sources", func() {
+		Expect(monitor.RemoveClusterID(ctx, "remote-cluster")).To(Succeed())
+		Expect(first.removed).To(ConsistOf("remote-cluster"))
+		Expect(second.removed).To(ConsistOf("remote-cluster"))
+	})
+
+	It("should forward the notifications of the sources to the subscribers", func() {
+		// Notify until the subscription is established, since notifications are not buffered before.
+		Eventually(func() bool {
+			second.notify("remote-cluster")
+			select {
+			case clusterID := <-notifier.notifications:
+				return clusterID == "remote-cluster"
+			case <-time.After(50 * time.Millisecond):
+				return false
+			}
+		}).Should(BeTrue())
+
+		first.notify(resourcemonitors.AllClusterIDs)
+		Eventually(notifier.notifications).Should(Receive(Equal(resourcemonitors.AllClusterIDs)))
+	})
+})
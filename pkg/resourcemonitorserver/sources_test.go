@@ -0,0 +1,267 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcemonitorserver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/liqotech/liqo/pkg/consts"
+	resourcemonitors "github.com/liqotech/liqo/pkg/liqo-controller-manager/resource-request-controller/resource-monitors"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+)
+
+type fakeQuerier struct {
+	results map[string]model.Value
+	err     error
+}
+
+func (q *fakeQuerier) Query(_ context.Context, query string, _ time.Time, _ ...promv1.Option) (model.Value, promv1.Warnings, error) {
+	if q.err != nil {
+		return nil, nil, q.err
+	}
+	return q.results[query], nil, nil
+}
+
+type fakeNotifier struct{ notifications chan string }
+
+func (n *fakeNotifier) NotifyChange(clusterID string) { n.notifications <- clusterID }
+
+func newNotifier() *fakeNotifier { return &fakeNotifier{notifications: make(chan string, 100)} }
+
+func expectResources(list *resourcemonitors.ResourceList, resources corev1.ResourceList) {
+	Expect(list.Resources).To(HaveLen(len(resources)))
+	for name, expected := range resources {
+		Expect(list.Resources).To(HaveKey(name.String()))
+		Expect(list.Resources[name.String()].Cmp(expected)).To(BeZero(),
+			fmt.Sprintf("resource %s: expected %s, found %s", name, expected.String(), list.Resources[name.String()].String()))
+	}
+}
+
+var _ = Describe("Sources", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+
+	BeforeEach(func() { ctx, cancel = context.WithCancel(context.Background()) })
+	AfterEach(func() { cancel() })
+
+	Describe("The NodeSource", func() {
+		node := func(name, pool string, ready bool, virtual bool) *corev1.Node {
+			status := corev1.ConditionFalse
+			if ready {
+				status = corev1.ConditionTrue
+			}
+			n := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"pool": pool}},
+				Status: corev1.NodeStatus{
+					Allocatable: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("4"),
+						corev1.ResourceMemory: resource.MustParse("8Gi"),
+					},
+					Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+				},
+			}
+			if virtual {
+				n.Labels[consts.TypeLabel] = consts.TypeNode
+			}
+			return n
+		}
+
+		pod := func(name, nodeName, origin string) *corev1.Pod {
+			p := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{}},
+				Spec: corev1.PodSpec{
+					NodeName: nodeName,
+					Containers: []corev1.Container{{Name: "c", Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+					}}},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning},
+			}
+			if origin != "" {
+				p.Labels[forge.LiqoOriginClusterIDKey] = origin
+			}
+			return p
+		}
+
+		var source *NodeSource
+
+		BeforeEach(func() {
+			clientset := fake.NewSimpleClientset(
+				node("selected-1", "gpu", true, false),
+				node("selected-2", "gpu", true, false),
+				node("not-ready", "gpu", false, false),
+				node("virtual", "gpu", true, true),
+				node("other", "cpu", true, false),
+				pod("local", "selected-1", ""),
+				pod("offloaded", "selected-2", "remote-cluster"),
+				pod("elsewhere", "other", ""),
+			)
+			selector, err := labels.Parse("pool=gpu")
+			Expect(err).ToNot(HaveOccurred())
+			source = NewNodeSource(ctx, clientset, selector, Pool{Name: "gpu", Prefix: "gpu-"}, 0)
+		})
+
+		It("should publish the resources available in the ready selected nodes", func() {
+			resources, err := source.ReadResources(ctx, resourcemonitors.AllClusterIDs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resources).To(HaveLen(1))
+			Expect(resources[0].PoolName).To(Equal("gpu"))
+			Expect(resources[0].PoolPrefix).To(Equal("gpu-"))
+			expectResources(resources[0], corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("6"),
+				corev1.ResourceMemory: resource.MustParse("16Gi"),
+			})
+		})
+
+		It("should consider the resources used by the given cluster as available", func() {
+			resources, err := source.ReadResources(ctx, "remote-cluster")
+			Expect(err).ToNot(HaveOccurred())
+			expectResources(resources[0], corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("7"),
+				corev1.ResourceMemory: resource.MustParse("16Gi"),
+			})
+		})
+	})
+
+	Describe("The StaticSource", func() {
+		var path string
+
+		write := func(content string) {
+			Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			path = filepath.Join(GinkgoT().TempDir(), "pools.yaml")
+			write(`
+pools:
+- poolName: gpu
+  poolPrefix: gpu-
+  labels: {accelerator: nvidia}
+  taints: [{key: nvidia.com/gpu, effect: NoSchedule}]
+  resources: {cpu: "8", nvidia.com/gpu: "2"}
+- poolName: storage
+  resources: {memory: 64Gi}`)
+		})
+
+		It("should fail if the file is invalid", func() {
+			write("pools: invalid")
+			_, err := NewStaticSource(path, time.Hour)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should publish the pools and reload them upon changes", func() {
+			source, err := NewStaticSource(path, 10*time.Millisecond)
+			Expect(err).ToNot(HaveOccurred())
+
+			resources, err := source.ReadResources(ctx, "remote-cluster")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resources).To(HaveLen(2))
+			Expect(resources[0].PoolName).To(Equal("gpu"))
+			Expect(resources[0].Labels).To(Equal(map[string]string{"accelerator": "nvidia"}))
+			Expect(resources[0].Taints).To(HaveLen(1))
+			Expect(resources[0].Taints[0].Key).To(Equal("nvidia.com/gpu"))
+			Expect(resources[0].Taints[0].Effect).To(Equal(string(corev1.TaintEffectNoSchedule)))
+			expectResources(resources[0], corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("8"), "nvidia.com/gpu": resource.MustParse("2"),
+			})
+			Expect(resources[1].PoolName).To(Equal("storage"))
+			Expect(resources[1].Taints).To(BeEmpty())
+
+			notifier := newNotifier()
+			source.Register(ctx, notifier)
+			Consistently(notifier.notifications, 100*time.Millisecond).ShouldNot(Receive())
+
+			write(`pools: [{poolName: gpu, resources: {cpu: "4"}}]`)
+			Eventually(notifier.notifications).Should(Receive(Equal(resourcemonitors.AllClusterIDs)))
+			resources, err = source.ReadResources(ctx, "remote-cluster")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resources).To(HaveLen(1))
+			expectResources(resources[0], corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")})
+
+			// Invalid changes are ignored.
+			write("pools: invalid")
+			Consistently(notifier.notifications, 100*time.Millisecond).ShouldNot(Receive())
+			Expect(source.ReadResources(ctx, "remote-cluster")).To(HaveLen(1))
+		})
+	})
+
+	Describe("The PrometheusSource", func() {
+		var (
+			querier *fakeQuerier
+			source  *PrometheusSource
+		)
+
+		BeforeEach(func() {
+			querier = &fakeQuerier{results: map[string]model.Value{
+				"cpu":    &model.Scalar{Value: 2.5},
+				"memory": model.Vector{{Value: 1e9}, {Value: 2e9}},
+			}}
+			source = NewPrometheusSourceWithQuerier(querier, map[corev1.ResourceName]string{
+				corev1.ResourceCPU: "cpu", corev1.ResourceMemory: "memory",
+			}, Pool{Name: "prometheus"}, time.Hour)
+		})
+
+		It("should not publish anything before the queries are executed", func() {
+			Expect(source.ReadResources(ctx, "remote-cluster")).To(BeEmpty())
+		})
+
+		It("should publish the results of the queries", func() {
+			changed, err := source.refresh(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeTrue())
+
+			resources, err := source.ReadResources(ctx, "remote-cluster")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resources).To(HaveLen(1))
+			Expect(resources[0].PoolName).To(Equal("prometheus"))
+			expectResources(resources[0], corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2500m"),
+				corev1.ResourceMemory: resource.MustParse("3G"),
+			})
+
+			Expect(source.refresh(ctx)).To(BeFalse())
+		})
+
+		It("should keep the previous resources in case of errors", func() {
+			Expect(source.refresh(ctx)).To(BeTrue())
+			querier.err = fmt.Errorf("unreachable")
+			_, err := source.refresh(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(source.ReadResources(ctx, "remote-cluster")).To(HaveLen(1))
+		})
+
+		It("should reject unsupported results", func() {
+			querier.results["cpu"] = &model.String{Value: "foo"}
+			_, err := source.refresh(ctx)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
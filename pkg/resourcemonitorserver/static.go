@@ -0,0 +1,129 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcemonitorserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	resourcemonitors "github.com/liqotech/liqo/pkg/liqo-controller-manager/resource-request-controller/resource-monitors"
+)
+
+var _ Source = &StaticSource{}
+
+// StaticPool is a resource pool defined in a static file.
+type StaticPool struct {
+	Pool
+	// Resources are the resources offered by the pool.
+	Resources corev1.ResourceList `json:"resources"`
+}
+
+// StaticConfig is the content of the file read by the StaticSource.
+type StaticConfig struct {
+	// Pools are the resource pools offered to all the clusters.
+	Pools []StaticPool `json:"pools"`
+}
+
+// StaticSource is a source publishing the resource pools defined in a YAML file.
+// The file is periodically checked, and the clusters are notified in case its content changes.
+type StaticSource struct {
+	notifierHolder
+
+	path     string
+	interval time.Duration
+
+	mutex   sync.RWMutex
+	content []byte
+	pools   []StaticPool
+}
+
+// NewStaticSource creates a new StaticSource, reading the pools from the given file,
+// and checking it for changes with the given interval.
+func NewStaticSource(path string, interval time.Duration) (*StaticSource, error) {
+	source := &StaticSource{path: path, interval: interval}
+	if _, err := source.reload(); err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+// Register sets an update notifier, and starts watching the file for changes.
+func (s *StaticSource) Register(ctx context.Context, notifier resourcemonitors.ResourceUpdateNotifier) {
+	s.setNotifier(notifier)
+	go wait.UntilWithContext(ctx, func(context.Context) {
+		changed, err := s.reload()
+		if err != nil {
+			klog.Errorf("Failed to reload the resource pools, keeping the previous ones: %v", err)
+			return
+		}
+		if changed {
+			klog.Infof("Resource pools reloaded from %q", s.path)
+			s.notify(resourcemonitors.AllClusterIDs)
+		}
+	}, s.interval)
+}
+
+// ReadResources returns the resource pools defined in the file.
+func (s *StaticSource) ReadResources(context.Context, string) ([]*resourcemonitors.ResourceList, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	resources := make([]*resourcemonitors.ResourceList, 0, len(s.pools))
+	for i := range s.pools {
+		resources = append(resources, s.pools[i].resourceList(s.pools[i].Resources))
+	}
+	return resources, nil
+}
+
+// RemoveClusterID is a no-op, since the source does not keep any per-cluster state.
+func (s *StaticSource) RemoveClusterID(context.Context, string) error {
+	return nil
+}
+
+// reload reads the file, and returns whether its content changed.
+func (s *StaticSource) reload() (changed bool, err error) {
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read file %q: %w", s.path, err)
+	}
+
+	s.mutex.RLock()
+	unchanged := s.content != nil && bytes.Equal(content, s.content)
+	s.mutex.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	// The content is recorded even if invalid, to avoid reporting the same error multiple times.
+	s.content = content
+
+	var config StaticConfig
+	if err := yaml.UnmarshalStrict(content, &config); err != nil {
+		return false, fmt.Errorf("failed to parse file %q: %w", s.path, err)
+	}
+	s.pools = config.Pools
+	return true, nil
+}
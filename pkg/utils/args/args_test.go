@@ -208,6 +208,14 @@ var _ = Describe("ParseArguments", func() {
 			}),
 		)
 
+		It("should report whether the CIDR has been set", func() {
+			cl := CIDR{}
+			Expect(cl.IsSet()).To(BeFalse())
+			Expect(cl.Set("fd00::/64")).To(Succeed())
+			Expect(cl.IsSet()).To(BeTrue())
+			Expect(cl.String()).To(Equal("fd00::/64"))
+		})
+
 	})
 
 	Context("ClusterIdentity", func() {
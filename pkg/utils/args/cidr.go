@@ -37,6 +37,11 @@ func (c *CIDR) Set(str string) error {
 	return nil
 }
 
+// IsSet returns whether the CIDR has been set.
+func (c *CIDR) IsSet() bool {
+	return c.network.IP != nil
+}
+
 // Type returns the cidrList type.
 func (c *CIDR) Type() string {
 	return "cidr"
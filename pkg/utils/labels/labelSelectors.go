@@ -51,15 +51,7 @@ var (
 	}
 
 	// WireGuardSecretLabelSelector selector used to get the WireGuard secret.
-	WireGuardSecretLabelSelector = metav1.LabelSelector{
-		MatchExpressions: []metav1.LabelSelectorRequirement{
-			{
-				Key:      liqoconst.KeysLabel,
-				Operator: metav1.LabelSelectorOpIn,
-				Values:   []string{liqoconst.DriverName},
-			},
-		},
-	}
+	WireGuardSecretLabelSelector = TunnelKeysSecretLabelSelector(liqoconst.DriverName)
 
 	// ClusterIDConfigMapLabelSelector selector used to get the cluster id configmap.
 	ClusterIDConfigMapLabelSelector = metav1.LabelSelector{
@@ -106,6 +98,19 @@ var (
 	}
 )
 
+// TunnelKeysSecretLabelSelector returns the selector used to get the secret containing the keys of the given tunnel driver.
+func TunnelKeysSecretLabelSelector(driver string) metav1.LabelSelector {
+	return metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      liqoconst.KeysLabel,
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   []string{driver},
+			},
+		},
+	}
+}
+
 // LocalLabelSelector returns a label selector to match local resources.
 func LocalLabelSelector() labels.Selector {
 	req, err := labels.NewRequirement(liqoconst.ReplicationRequestedLabel, selection.Equals, []string{strconv.FormatBool(true)})
@@ -0,0 +1,74 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offloading
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	offv1alpha1 "github.com/liqotech/liqo/apis/offloading/v1alpha1"
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+)
+
+// Distribution returns the number of non-terminated pods of the given namespace executed in the local cluster and in
+// each remote cluster. The client is used to retrieve the virtual nodes, while the reader to list the pods, and shall
+// not be restricted to the offloaded ones (e.g., a cache of the pods labeled as admitted by the pod webhook). In case withPending is true, the pods not yet scheduled are additionally
+// accounted to the target they have been assigned to by the weighted spreading, if any.
+func Distribution(ctx context.Context, cl client.Client, reader client.Reader, namespace string,
+	withPending bool) (*offv1alpha1.OffloadingDistribution, error) {
+	var nodes corev1.NodeList
+	if err := cl.List(ctx, &nodes, client.MatchingLabels{liqoconst.TypeLabel: liqoconst.TypeNode}); err != nil {
+		return nil, fmt.Errorf("failed to retrieve virtual nodes: %w", err)
+	}
+
+	clusterIDs := make(map[string]string, len(nodes.Items))
+	for i := range nodes.Items {
+		clusterIDs[nodes.Items[i].Name] = nodes.Items[i].Labels[liqoconst.RemoteClusterID]
+	}
+
+	var pods corev1.PodList
+	if err := reader.List(ctx, &pods, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to retrieve pods: %w", err)
+	}
+
+	distribution := &offv1alpha1.OffloadingDistribution{Remote: map[string]int32{}}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed || !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		target := pod.Annotations[liqoconst.SpreadingTargetAnnotation]
+		switch {
+		case pod.Spec.NodeName != "":
+			if clusterID, offloaded := clusterIDs[pod.Spec.NodeName]; offloaded {
+				distribution.Remote[clusterID]++
+			} else {
+				distribution.Local++
+			}
+		case !withPending || target == "":
+			continue
+		case target == liqoconst.LocalSpreadingTarget:
+			distribution.Local++
+		default:
+			distribution.Remote[target]++
+		}
+	}
+
+	return distribution, nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offloading_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils/offloading"
+)
+
+var _ = Describe("The Distribution function", func() {
+	var cl client.Client
+
+	pod := func(name, nodeName, target string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "foo",
+				Annotations: map[string]string{liqoconst.SpreadingTargetAnnotation: target}},
+			Spec:   corev1.PodSpec{NodeName: nodeName},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	BeforeEach(func() {
+		terminating := pod("terminating", "local", "", corev1.PodRunning)
+		terminating.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		terminating.Finalizers = []string{"foo"}
+
+		cl = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "local"}},
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "vk-1", Labels: map[string]string{
+				liqoconst.TypeLabel: liqoconst.TypeNode, liqoconst.RemoteClusterID: "cluster-1",
+			}}},
+			pod("local", "local", liqoconst.LocalSpreadingTarget, corev1.PodRunning),
+			pod("remote", "vk-1", liqoconst.LocalSpreadingTarget, corev1.PodRunning),
+			pod("pending-local", "", liqoconst.LocalSpreadingTarget, corev1.PodPending),
+			pod("pending-remote", "", "cluster-1", corev1.PodPending),
+			pod("pending-unassigned", "", "", corev1.PodPending),
+			pod("completed", "vk-1", "", corev1.PodSucceeded),
+			terminating,
+		).Build()
+	})
+
+	It("should count the scheduled pods, based on the node they are bound to", func() {
+		distribution, err := offloading.Distribution(context.Background(), cl, cl, "foo", false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(distribution.Local).To(BeEquivalentTo(1))
+		Expect(distribution.Remote).To(Equal(map[string]int32{"cluster-1": 1}))
+	})
+
+	It("should additionally count the pending pods, based on the assigned target", func() {
+		distribution, err := offloading.Distribution(context.Background(), cl, cl, "foo", true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(distribution.Local).To(BeEquivalentTo(2))
+		Expect(distribution.Remote).To(Equal(map[string]int32{"cluster-1": 2}))
+	})
+})
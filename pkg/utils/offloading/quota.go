@@ -0,0 +1,140 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offloading
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	offv1alpha1 "github.com/liqotech/liqo/apis/offloading/v1alpha1"
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils/getters"
+	"github.com/liqotech/liqo/pkg/utils/pod"
+)
+
+// QuotaUsage returns the resources currently consumed in the remote clusters by the pods of the given namespace.
+// Resources are accounted based on the container limits, as done by the offerer-side resource enforcement, and
+// considering the non-terminated offloaded pods. Similarly to the ShadowPods being accounted by the offerer as soon as
+// admitted, the pods admitted by the pod webhook and not yet offloaded are additionally accounted to all the remote
+// clusters they may be offloaded to, as recorded by the corresponding annotation. The client shall be allowed to list
+// the pods labeled as offloaded, while the reader the ones labeled as admitted by the pod webhook.
+func QuotaUsage(ctx context.Context, cl client.Client, admitted client.Reader, namespace string) (*offv1alpha1.OffloadingQuotaUsage, error) {
+	var nodes corev1.NodeList
+	if err := cl.List(ctx, &nodes, client.MatchingLabels{liqoconst.TypeLabel: liqoconst.TypeNode}); err != nil {
+		return nil, fmt.Errorf("failed to retrieve virtual nodes: %w", err)
+	}
+
+	clusterIDs := make(map[string]string, len(nodes.Items))
+	for i := range nodes.Items {
+		clusterIDs[nodes.Items[i].Name] = nodes.Items[i].Labels[liqoconst.RemoteClusterID]
+	}
+
+	pods, err := getters.ListOffloadedPods(ctx, cl, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve pods: %w", err)
+	}
+
+	var pending corev1.PodList
+	if err := admitted.List(ctx, &pending, client.InNamespace(namespace),
+		client.MatchingLabels{liqoconst.AdmittedPodLabelKey: liqoconst.AdmittedPodLabelValue}); err != nil {
+		return nil, fmt.Errorf("failed to retrieve admitted pods: %w", err)
+	}
+
+	usage := &offv1alpha1.OffloadingQuotaUsage{Aggregate: corev1.ResourceList{}, PerCluster: map[string]corev1.ResourceList{}}
+	account := func(current *corev1.Pod, targets []string) error {
+		// Limits are not validated, as pods not specifying them are not admitted by the webhook if subject to a quota.
+		quota, err := pod.QuotaFromPodSpec(&current.Spec, false)
+		if err != nil {
+			return err
+		}
+		usage.Aggregate = quotav1.Add(usage.Aggregate, quota)
+		for _, clusterID := range targets {
+			usage.PerCluster[clusterID] = quotav1.Add(usage.PerCluster[clusterID], quota)
+		}
+		return nil
+	}
+
+	for i := range pods.Items {
+		current := &pods.Items[i]
+		clusterID, offloaded := clusterIDs[current.Spec.NodeName]
+		if !offloaded || current.Status.Phase == corev1.PodSucceeded || current.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if err := account(current, []string{clusterID}); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range pending.Items {
+		current := &pending.Items[i]
+		if current.Labels[liqoconst.LocalPodLabelKey] == liqoconst.LocalPodLabelValue || !current.DeletionTimestamp.IsZero() ||
+			current.Status.Phase == corev1.PodSucceeded || current.Status.Phase == corev1.PodFailed {
+			// Offloaded pods have already been accounted, while terminated ones do not consume resources.
+			continue
+		}
+
+		var targets []string
+		switch clusterID, offloaded := clusterIDs[current.Spec.NodeName]; {
+		case current.Spec.NodeName == "" && current.Annotations[liqoconst.QuotaTargetsAnnotation] != "":
+			targets = strings.Split(current.Annotations[liqoconst.QuotaTargetsAnnotation], ",")
+		case offloaded:
+			// The pod has already been bound to a virtual node, but not yet labeled as offloaded.
+			targets = []string{clusterID}
+		default:
+			continue
+		}
+
+		if err := account(current, targets); err != nil {
+			return nil, err
+		}
+	}
+
+	return usage, nil
+}
+
+// CheckQuota returns an error in case consuming the requested resources in addition to the used ones would exceed
+// the given limits. Only the resources subject to a limit are checked, and they are required to be part of the request.
+func CheckQuota(limits, used, requested corev1.ResourceList) error {
+	for _, name := range sortedNames(limits) {
+		request, found := requested[name]
+		if !found {
+			return fmt.Errorf("%s limit not set, but required by the offloading quota", name)
+		}
+
+		total := used[name].DeepCopy()
+		total.Add(request)
+		if limit := limits[name]; total.Cmp(limit) > 0 {
+			return fmt.Errorf("%s offloading quota exceeded - limit %s / used %s / requested %s",
+				name, limit.String(), used.Name(name, limit.Format).String(), request.String())
+		}
+	}
+	return nil
+}
+
+// sortedNames returns the names of the given resources, sorted alphabetically.
+func sortedNames(resources corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(resources))
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
@@ -0,0 +1,117 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offloading_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils/offloading"
+)
+
+var _ = Describe("Offloading quota functions", func() {
+	resources := func(cpu, memory string) corev1.ResourceList {
+		return corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(memory)}
+	}
+
+	Describe("The QuotaUsage function", func() {
+		node := func(name, clusterID string, virtual bool) *corev1.Node {
+			labels := map[string]string{liqoconst.RemoteClusterID: clusterID}
+			if virtual {
+				labels[liqoconst.TypeLabel] = liqoconst.TypeNode
+			}
+			return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+		}
+
+		pod := func(name, namespace, nodeName string, phase corev1.PodPhase, offloaded bool) *corev1.Pod {
+			labels := map[string]string{liqoconst.AdmittedPodLabelKey: liqoconst.AdmittedPodLabelValue}
+			if offloaded {
+				labels[liqoconst.LocalPodLabelKey] = liqoconst.LocalPodLabelValue
+			}
+			return &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+				Spec: corev1.PodSpec{NodeName: nodeName, Containers: []corev1.Container{
+					{Name: "foo", Resources: corev1.ResourceRequirements{Limits: resources("1", "1Gi")}},
+				}},
+				Status: corev1.PodStatus{Phase: phase},
+			}
+		}
+
+		It("should account the resources of the offloaded pods, per cluster and in aggregate", func() {
+			cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+				node("vk-1", "cluster-1", true), node("vk-2", "cluster-2", true), node("local", "", false),
+				pod("first", "foo", "vk-1", corev1.PodRunning, true),
+				pod("second", "foo", "vk-1", corev1.PodPending, true),
+				pod("third", "foo", "vk-2", corev1.PodRunning, true),
+				pod("terminated", "foo", "vk-2", corev1.PodSucceeded, true),
+				pod("local", "foo", "local", corev1.PodRunning, false),
+				pod("other-namespace", "bar", "vk-2", corev1.PodRunning, true),
+			).Build()
+
+			usage, err := offloading.QuotaUsage(context.Background(), cl, cl, "foo")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(quotav1.Equals(usage.Aggregate, resources("3", "3Gi"))).To(BeTrue())
+			Expect(usage.PerCluster).To(HaveLen(2))
+			Expect(quotav1.Equals(usage.PerCluster["cluster-1"], resources("2", "2Gi"))).To(BeTrue())
+			Expect(quotav1.Equals(usage.PerCluster["cluster-2"], resources("1", "1Gi"))).To(BeTrue())
+		})
+
+		It("should account the admitted pods not yet offloaded to all the clusters they may be offloaded to", func() {
+			pending := pod("pending", "foo", "", corev1.PodPending, false)
+			pending.Annotations = map[string]string{liqoconst.QuotaTargetsAnnotation: "cluster-1,cluster-2"}
+			cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+				node("vk-1", "cluster-1", true), node("vk-2", "cluster-2", true), node("local", "", false),
+				pod("first", "foo", "vk-1", corev1.PodRunning, true),
+				pod("bound", "foo", "vk-2", corev1.PodPending, false),
+				pod("local", "foo", "local", corev1.PodRunning, false),
+				pod("unassigned", "foo", "", corev1.PodPending, false),
+				pending,
+			).Build()
+
+			usage, err := offloading.QuotaUsage(context.Background(), cl, cl, "foo")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(quotav1.Equals(usage.Aggregate, resources("3", "3Gi"))).To(BeTrue())
+			Expect(usage.PerCluster).To(HaveLen(2))
+			Expect(quotav1.Equals(usage.PerCluster["cluster-1"], resources("2", "2Gi"))).To(BeTrue())
+			Expect(quotav1.Equals(usage.PerCluster["cluster-2"], resources("2", "2Gi"))).To(BeTrue())
+		})
+	})
+
+	Describe("The CheckQuota function", func() {
+		limits := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}
+
+		DescribeTable("tests table",
+			func(used, requested corev1.ResourceList, expected types.GomegaMatcher) {
+				Expect(offloading.CheckQuota(limits, used, requested)).To(expected)
+			},
+			Entry("within the quota", resources("2", "1Gi"), resources("2", "1Gi"), Succeed()),
+			Entry("nothing used yet", corev1.ResourceList{}, resources("4", "1Gi"), Succeed()),
+			Entry("exceeding the quota", resources("3", "1Gi"), resources("2", "1Gi"),
+				MatchError(ContainSubstring("cpu offloading quota exceeded"))),
+			Entry("limit not specified", corev1.ResourceList{}, corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				MatchError(ContainSubstring("cpu limit not set"))),
+		)
+	})
+})
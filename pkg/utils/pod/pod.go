@@ -15,10 +15,12 @@
 package pod
 
 import (
+	"fmt"
 	"reflect"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 	"k8s.io/utils/pointer"
 )
 
@@ -58,10 +60,12 @@ func IsPodSpecEqual(previous, updated *corev1.PodSpec) bool {
 	// * spec.initContainers[*].image
 	// * spec.activeDeadlineSeconds
 	// * spec.tolerations (only new entries can be added)
+	// * spec.ephemeralContainers (only new entries can be added)
 	return AreContainersEqual(previous.Containers, updated.Containers) &&
 		AreContainersEqual(previous.InitContainers, updated.InitContainers) &&
 		pointer.Int64Equal(previous.ActiveDeadlineSeconds, updated.ActiveDeadlineSeconds) &&
-		len(previous.Tolerations) == len(updated.Tolerations)
+		len(previous.Tolerations) == len(updated.Tolerations) &&
+		len(previous.EphemeralContainers) == len(updated.EphemeralContainers)
 }
 
 // CheckShadowPodUpdate returns whether updated equals previous, except for the fields that are allowed to be updated.
@@ -72,6 +76,7 @@ func CheckShadowPodUpdate(previous, updated *corev1.PodSpec) bool {
 	// * spec.initContainers[*].image
 	// * spec.activeDeadlineSeconds
 	// * spec.tolerations (only new entries can be added)
+	// * spec.ephemeralContainers (only new entries can be added)
 	for i := range updated.Containers {
 		updated.Containers[i].Image = previous.Containers[i].Image
 	}
@@ -80,9 +85,42 @@ func CheckShadowPodUpdate(previous, updated *corev1.PodSpec) bool {
 	}
 	updated.ActiveDeadlineSeconds = previous.ActiveDeadlineSeconds
 	updated.Tolerations = previous.Tolerations
+	if AreEphemeralContainersAppended(previous.EphemeralContainers, updated.EphemeralContainers) {
+		updated.EphemeralContainers = previous.EphemeralContainers
+	}
 	return reflect.DeepEqual(previous, updated)
 }
 
+// AreEphemeralContainersAppended returns whether the updated ephemeral containers list is obtained
+// from the previous one by appending new entries, as existing ephemeral containers cannot be modified.
+func AreEphemeralContainersAppended(previous, updated []corev1.EphemeralContainer) bool {
+	switch {
+	case len(previous) == 0:
+		return true
+	case len(previous) > len(updated):
+		return false
+	default:
+		return reflect.DeepEqual(previous, updated[:len(previous)])
+	}
+}
+
+// MissingEphemeralContainers returns the ephemeral containers in desired which are not present in current (matched by name).
+func MissingEphemeralContainers(current, desired []corev1.EphemeralContainer) []corev1.EphemeralContainer {
+	var missing []corev1.EphemeralContainer
+
+outer:
+	for i := range desired {
+		for j := range current {
+			if desired[i].Name == current[j].Name {
+				continue outer
+			}
+		}
+		missing = append(missing, desired[i])
+	}
+
+	return missing
+}
+
 // AreContainersEqual returns whether two container lists are equal according to the
 // fields that can be modified after start-up time (i.e. the image field).
 func AreContainersEqual(previous, updated []corev1.Container) bool {
@@ -136,3 +174,63 @@ func ServiceAccountName(pod *corev1.Pod) string {
 
 	return "default"
 }
+
+// QuotaFromPodSpec returns the amount of resources accounted for a pod with the given specifications, based on the
+// container limits: the sum of the limits of all containers, or the highest limit among the init containers, if greater.
+// In case validate is true, an error is returned if any container does not specify both CPU and memory limits.
+func QuotaFromPodSpec(spec *corev1.PodSpec, validate bool) (corev1.ResourceList, error) {
+	conResources := corev1.ResourceList{}
+	initConResources := corev1.ResourceList{}
+
+	// Calculating the sum of the resources of all containers
+	for i := range spec.Containers {
+		// This flags are used to check if each container in range has CPU and Memory limits defined
+		cpuFlag := false
+		memoryFlag := false
+		for key, value := range spec.Containers[i].Resources.Limits {
+			if key == corev1.ResourceCPU {
+				cpuFlag = true
+			}
+			if key == corev1.ResourceMemory {
+				memoryFlag = true
+			}
+			if prevResources, ok := conResources[key]; ok {
+				prevResources.Add(value)
+				conResources[key] = prevResources
+			} else {
+				conResources[key] = value.DeepCopy()
+			}
+		}
+		// If the container has no CPU or Memory limits defined and this kind of validation is required, an error is returned
+		if (!cpuFlag || !memoryFlag) && validate {
+			return nil, fmt.Errorf("CPU and/or memory limits not set for container %s", spec.Containers[i].Name)
+		}
+	}
+
+	// Calculating the max of each resource type between the init containers
+	for i := range spec.InitContainers {
+		// This flags are used to check if each container in range has CPU and Memory limits defined
+		cpuFlag := false
+		memoryFlag := false
+		for key, value := range spec.InitContainers[i].Resources.Limits {
+			if key == corev1.ResourceCPU {
+				cpuFlag = true
+			}
+			if key == corev1.ResourceMemory {
+				memoryFlag = true
+			}
+			if prevResources, ok := initConResources[key]; ok {
+				if prevResources.Value() < value.Value() {
+					initConResources[key] = value.DeepCopy()
+				}
+			} else {
+				initConResources[key] = value.DeepCopy()
+			}
+		}
+		// If the init container has no CPU or Memory limits defined and this kind of validation is required, an error is returned
+		if (!cpuFlag || !memoryFlag) && validate {
+			return nil, fmt.Errorf("CPU and/or memory limits not set for initContainer %s", spec.InitContainers[i].Name)
+		}
+	}
+	return quotav1.Max(conResources, initConResources), nil
+}
@@ -26,6 +26,9 @@ import (
 )
 
 var _ = Describe("Pod utility functions", func() {
+	ephemeral := func(name string) corev1.EphemeralContainer {
+		return corev1.EphemeralContainer{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: name, Image: "busybox"}}
+	}
 
 	Describe("The IsPodReady function", func() {
 		type IsPodReadyCase struct {
@@ -110,6 +113,76 @@ var _ = Describe("Pod utility functions", func() {
 				updated:  corev1.PodSpec{ActiveDeadlineSeconds: nil},
 				expected: BeFalse(),
 			}),
+			Entry("more ephemeral containers are present", TestCase{
+				previous: corev1.PodSpec{EphemeralContainers: []corev1.EphemeralContainer{ephemeral("foo")}},
+				updated:  corev1.PodSpec{EphemeralContainers: []corev1.EphemeralContainer{ephemeral("foo"), ephemeral("bar")}},
+				expected: BeFalse(),
+			}),
+		)
+	})
+
+	Describe("The CheckShadowPodUpdate function", func() {
+		type TestCase struct {
+			previous corev1.PodSpec
+			updated  corev1.PodSpec
+			expected types.GomegaMatcher
+		}
+
+		DescribeTable("tests table",
+			func(c TestCase) {
+				Expect(pod.CheckShadowPodUpdate(&c.previous, &c.updated)).To(c.expected)
+			},
+			Entry("both specs are empty", TestCase{expected: BeTrue()}),
+			Entry("the images are different", TestCase{
+				previous: corev1.PodSpec{Containers: []corev1.Container{{Name: "foo", Image: "bar"}}},
+				updated:  corev1.PodSpec{Containers: []corev1.Container{{Name: "foo", Image: "baz"}}},
+				expected: BeTrue(),
+			}),
+			Entry("the container names are different", TestCase{
+				previous: corev1.PodSpec{Containers: []corev1.Container{{Name: "foo", Image: "bar"}}},
+				updated:  corev1.PodSpec{Containers: []corev1.Container{{Name: "bar", Image: "bar"}}},
+				expected: BeFalse(),
+			}),
+			Entry("ephemeral containers are added", TestCase{
+				previous: corev1.PodSpec{EphemeralContainers: []corev1.EphemeralContainer{ephemeral("foo")}},
+				updated:  corev1.PodSpec{EphemeralContainers: []corev1.EphemeralContainer{ephemeral("foo"), ephemeral("bar")}},
+				expected: BeTrue(),
+			}),
+			Entry("ephemeral containers are removed", TestCase{
+				previous: corev1.PodSpec{EphemeralContainers: []corev1.EphemeralContainer{ephemeral("foo"), ephemeral("bar")}},
+				updated:  corev1.PodSpec{EphemeralContainers: []corev1.EphemeralContainer{ephemeral("foo")}},
+				expected: BeFalse(),
+			}),
+			Entry("ephemeral containers are modified", TestCase{
+				previous: corev1.PodSpec{EphemeralContainers: []corev1.EphemeralContainer{ephemeral("foo")}},
+				updated:  corev1.PodSpec{EphemeralContainers: []corev1.EphemeralContainer{ephemeral("bar")}},
+				expected: BeFalse(),
+			}),
+		)
+	})
+
+	Describe("The MissingEphemeralContainers function", func() {
+		type TestCase struct {
+			current  []corev1.EphemeralContainer
+			desired  []corev1.EphemeralContainer
+			expected types.GomegaMatcher
+		}
+
+		DescribeTable("tests table",
+			func(c TestCase) {
+				Expect(pod.MissingEphemeralContainers(c.current, c.desired)).To(c.expected)
+			},
+			Entry("both lists are nil", TestCase{expected: BeEmpty()}),
+			Entry("all desired containers are present", TestCase{
+				current:  []corev1.EphemeralContainer{ephemeral("foo"), ephemeral("bar")},
+				desired:  []corev1.EphemeralContainer{ephemeral("bar")},
+				expected: BeEmpty(),
+			}),
+			Entry("some desired containers are missing", TestCase{
+				current:  []corev1.EphemeralContainer{ephemeral("foo")},
+				desired:  []corev1.EphemeralContainer{ephemeral("foo"), ephemeral("bar"), ephemeral("baz")},
+				expected: Equal([]corev1.EphemeralContainer{ephemeral("bar"), ephemeral("baz")}),
+			}),
 		)
 	})
 
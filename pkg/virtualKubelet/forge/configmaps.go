@@ -25,8 +25,8 @@ const RootCAConfigMapName = "kube-root-ca.crt"
 // RemoteConfigMap forges the apply patch for the reflected configmap, given the local one.
 func RemoteConfigMap(local *corev1.ConfigMap, targetNamespace string, forgingOpts *ForgingOpts) *corev1apply.ConfigMapApplyConfiguration {
 	applyConfig := corev1apply.ConfigMap(RemoteConfigMapName(local.GetName()), targetNamespace).
-		WithLabels(FilterNotReflected(local.GetLabels(), forgingOpts.LabelsNotReflected)).WithLabels(ReflectionLabels()).
-		WithAnnotations(FilterNotReflected(local.GetAnnotations(), forgingOpts.AnnotationsNotReflected)).
+		WithLabels(forgingOpts.FilterLabels(local, local.GetLabels())).WithLabels(ReflectionLabels()).
+		WithAnnotations(forgingOpts.FilterAnnotations(local, local.GetAnnotations())).
 		WithBinaryData(local.BinaryData).
 		WithData(local.Data)
 
@@ -99,8 +99,8 @@ func RemoteShadowEndpointSlice(local *discoveryv1.EndpointSlice, remote *vkv1alp
 func RemoteEndpointSliceObjectMeta(local, remote *metav1.ObjectMeta, forgingOpts *ForgingOpts) metav1.ObjectMeta {
 	objectMeta := RemoteObjectMeta(local, remote)
 	objectMeta.SetLabels(labels.Merge(objectMeta.Labels, EndpointSliceLabels()))
-	objectMeta.SetLabels(FilterNotReflected(objectMeta.Labels, forgingOpts.LabelsNotReflected))
-	objectMeta.SetAnnotations(FilterNotReflected(objectMeta.Annotations, forgingOpts.AnnotationsNotReflected))
+	objectMeta.SetLabels(forgingOpts.FilterLabels(local, objectMeta.Labels))
+	objectMeta.SetAnnotations(forgingOpts.FilterAnnotations(local, objectMeta.Annotations))
 
 	return objectMeta
 }
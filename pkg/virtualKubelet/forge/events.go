@@ -60,6 +60,13 @@ func EventFailedStatusReflectionMsg(err error) string {
 	return fmt.Sprintf("Error reflecting object status back from cluster %q: %v", RemoteCluster.ClusterName, err)
 }
 
+// EventFailedStatusReflectionManagedByOthersMsg returns the message for the event when the incoming reflection
+// has been aborted because the local status is already managed by a different controller.
+func EventFailedStatusReflectionManagedByOthersMsg() string {
+	return fmt.Sprintf("Error reflecting object status back from cluster %q: local status already managed by a different controller",
+		RemoteCluster.ClusterName)
+}
+
 // EventFailedReflectionAlreadyExistsMsg returns the message for the event when the reflection
 // has been aborted because the remote object already exists.
 func EventFailedReflectionAlreadyExistsMsg() string {
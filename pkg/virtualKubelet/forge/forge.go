@@ -21,6 +21,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	vklisters "github.com/liqotech/liqo/pkg/client/listers/virtualkubelet/v1alpha1"
 )
 
 // ReflectionFieldManager -> The name associated with the fields modified by virtual kubelet reflection.
@@ -72,6 +73,11 @@ func ApplyOptions() metav1.ApplyOptions {
 type ForgingOpts struct {
 	LabelsNotReflected      []string
 	AnnotationsNotReflected []string
+
+	// ReflectionPolicies retrieves the ReflectionPolicies customizing the reflection on a per-namespace basis (if configured).
+	ReflectionPolicies vklisters.ReflectionPolicyLister
+	// resource is the name of the resource the options are scoped to, for the selection of the ReflectionPolicies.
+	resource string
 }
 
 // NewForgingOpts returns a new ForgingOpts instance.
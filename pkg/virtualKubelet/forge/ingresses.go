@@ -24,8 +24,8 @@ import (
 // RemoteIngress forges the apply patch for the reflected ingress, given the local one.
 func RemoteIngress(local *netv1.Ingress, targetNamespace string, forgingOpts *ForgingOpts) *netv1apply.IngressApplyConfiguration {
 	return netv1apply.Ingress(local.GetName(), targetNamespace).
-		WithLabels(FilterNotReflected(local.GetLabels(), forgingOpts.LabelsNotReflected)).WithLabels(ReflectionLabels()).
-		WithAnnotations(FilterNotReflected(FilterIngressAnnotations(local.GetAnnotations()), forgingOpts.AnnotationsNotReflected)).
+		WithLabels(forgingOpts.FilterLabels(local, local.GetLabels())).WithLabels(ReflectionLabels()).
+		WithAnnotations(forgingOpts.FilterAnnotations(local, FilterIngressAnnotations(local.GetAnnotations()))).
 		WithSpec(RemoteIngressSpec(local.Spec.DeepCopy()))
 }
 
@@ -15,11 +15,14 @@
 package forge
 
 import (
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	corev1apply "k8s.io/client-go/applyconfigurations/core/v1"
 
+	"github.com/liqotech/liqo/pkg/consts"
 	"github.com/liqotech/liqo/pkg/utils/maps"
 )
 
@@ -30,6 +33,8 @@ const (
 	LiqoDestinationClusterIDKey = "virtualkubelet.liqo.io/destination"
 	// LiqoOriginClusterNodeName is the name of the node on the origin cluster referenced by the virtual-kubelet.
 	LiqoOriginClusterNodeName = "virtualkubelet.liqo.io/nodename"
+
+	statusSubresource = "status"
 )
 
 // ReflectionLabels returns the labels assigned to the objects reflected from the local to the remote cluster.
@@ -57,6 +62,45 @@ func IsReflected(obj metav1.Object) bool {
 	return ReflectedLabelSelector().Matches(labels.Set(obj.GetLabels()))
 }
 
+// ShouldReflectStatus returns whether the status of the given local object shall be propagated back from the remote
+// cluster, according to the value of the corresponding annotation.
+func ShouldReflectStatus(local metav1.Object) bool {
+	value, found := local.GetAnnotations()[consts.ReflectStatusAnnotationKey]
+	if !found {
+		return false
+	}
+	return strings.EqualFold(value, "true") || value == RemoteCluster.ClusterID || value == RemoteCluster.ClusterName
+}
+
+// ShouldClearStatus returns whether the status previously propagated back to the given local object shall be cleared,
+// as its reflection is no longer requested (i.e., the annotation has been removed, or set to false). Conversely, the
+// status is preserved if the annotation refers to a different remote cluster, as enforced by the corresponding reflector.
+func ShouldClearStatus(local metav1.Object) bool {
+	value, found := local.GetAnnotations()[consts.ReflectStatusAnnotationKey]
+	return !found || strings.EqualFold(value, "false")
+}
+
+// IsStatusReflected returns whether (part of) the status of the given local object has been propagated back by the reflection.
+func IsStatusReflected(local metav1.Object) bool {
+	for _, entry := range local.GetManagedFields() {
+		if entry.Subresource == statusSubresource && entry.Manager == ReflectionFieldManager {
+			return true
+		}
+	}
+	return false
+}
+
+// IsStatusManagedByOthers returns whether (part of) the status of the given local object is managed by a different
+// controller (e.g., a cloud controller assigning the addresses to LoadBalancer services, or an ingress controller).
+func IsStatusManagedByOthers(local metav1.Object) bool {
+	for _, entry := range local.GetManagedFields() {
+		if entry.Subresource == statusSubresource && entry.Manager != ReflectionFieldManager {
+			return true
+		}
+	}
+	return false
+}
+
 // RemoteObjectMeta forges the local ObjectMeta for a reflected object.
 func RemoteObjectMeta(local, remote *metav1.ObjectMeta) metav1.ObjectMeta {
 	output := remote.DeepCopy()
@@ -24,6 +24,7 @@ import (
 	corev1apply "k8s.io/client-go/applyconfigurations/core/v1"
 	"k8s.io/utils/pointer"
 
+	"github.com/liqotech/liqo/pkg/consts"
 	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
 )
 
@@ -174,4 +175,50 @@ var _ = Describe("Meta forging", func() {
 			Expect(output.Name).To(PointTo(Equal("example-name")))
 		})
 	})
+
+	Describe("the ShouldReflectStatus function", func() {
+		DescribeTable("checking whether the status should be reflected",
+			func(annotations map[string]string, expected bool) {
+				object := metav1.ObjectMeta{Annotations: annotations}
+				Expect(forge.ShouldReflectStatus(&object)).To(BeIdenticalTo(expected))
+			},
+			Entry("when no annotation is specified", nil, false),
+			Entry("when the annotation is set to false", map[string]string{consts.ReflectStatusAnnotationKey: "false"}, false),
+			Entry("when the annotation is set to true", map[string]string{consts.ReflectStatusAnnotationKey: "true"}, true),
+			Entry("when the annotation is set to True", map[string]string{consts.ReflectStatusAnnotationKey: "True"}, true),
+			Entry("when the annotation is set to the remote cluster ID", map[string]string{consts.ReflectStatusAnnotationKey: RemoteClusterID}, true),
+			Entry("when the annotation is set to the remote cluster name", map[string]string{consts.ReflectStatusAnnotationKey: RemoteClusterName}, true),
+			Entry("when the annotation is set to a different cluster", map[string]string{consts.ReflectStatusAnnotationKey: LocalClusterID}, false),
+		)
+	})
+
+	Describe("the ShouldClearStatus function", func() {
+		DescribeTable("checking whether the status should be cleared",
+			func(annotations map[string]string, expected bool) {
+				object := metav1.ObjectMeta{Annotations: annotations}
+				Expect(forge.ShouldClearStatus(&object)).To(BeIdenticalTo(expected))
+			},
+			Entry("when no annotation is specified", nil, true),
+			Entry("when the annotation is set to false", map[string]string{consts.ReflectStatusAnnotationKey: "false"}, true),
+			Entry("when the annotation is set to true", map[string]string{consts.ReflectStatusAnnotationKey: "true"}, false),
+			Entry("when the annotation is set to a different cluster", map[string]string{consts.ReflectStatusAnnotationKey: LocalClusterID}, false),
+		)
+	})
+
+	Describe("the IsStatusReflected and IsStatusManagedByOthers functions", func() {
+		DescribeTable("checking the managers of the status",
+			func(entries []metav1.ManagedFieldsEntry, reflected, others bool) {
+				object := metav1.ObjectMeta{ManagedFields: entries}
+				Expect(forge.IsStatusReflected(&object)).To(BeIdenticalTo(reflected))
+				Expect(forge.IsStatusManagedByOthers(&object)).To(BeIdenticalTo(others))
+			},
+			Entry("when no managed fields are present", nil, false, false),
+			Entry("when the status is managed by the reflection",
+				[]metav1.ManagedFieldsEntry{{Manager: forge.ReflectionFieldManager, Subresource: "status"}}, true, false),
+			Entry("when the status is managed by a different controller",
+				[]metav1.ManagedFieldsEntry{{Manager: "cloud-controller", Subresource: "status"}}, false, true),
+			Entry("when only the main resource is managed by the reflection",
+				[]metav1.ManagedFieldsEntry{{Manager: forge.ReflectionFieldManager}, {Manager: "kubectl"}}, false, false),
+		)
+	})
 })
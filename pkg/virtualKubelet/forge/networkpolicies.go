@@ -0,0 +1,125 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forge
+
+import (
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1apply "k8s.io/client-go/applyconfigurations/meta/v1"
+	netv1apply "k8s.io/client-go/applyconfigurations/networking/v1"
+)
+
+// RemoteNetworkPolicy forges the apply patch for the reflected network policy, given the local one.
+// The peers of the local object are expected to be already translated to refer to the remote cluster address space.
+func RemoteNetworkPolicy(local *netv1.NetworkPolicy, targetNamespace string, forgingOpts *ForgingOpts) *netv1apply.NetworkPolicyApplyConfiguration {
+	return netv1apply.NetworkPolicy(local.GetName(), targetNamespace).
+		WithLabels(forgingOpts.FilterLabels(local, local.GetLabels())).WithLabels(ReflectionLabels()).
+		WithAnnotations(forgingOpts.FilterAnnotations(local, local.GetAnnotations())).
+		WithSpec(RemoteNetworkPolicySpec(local.Spec.DeepCopy()))
+}
+
+// RemoteNetworkPolicySpec forges the apply patch for the specs of the reflected network policy, given the local one.
+// It expects the local object to be a deepcopy, as it is mutated.
+func RemoteNetworkPolicySpec(local *netv1.NetworkPolicySpec) *netv1apply.NetworkPolicySpecApplyConfiguration {
+	return netv1apply.NetworkPolicySpec().
+		WithPodSelector(RemoteLabelSelector(&local.PodSelector)).
+		WithPolicyTypes(NetworkPolicyTypes(local)...).
+		WithIngress(RemoteNetworkPolicyIngressRules(local.Ingress)...).
+		WithEgress(RemoteNetworkPolicyEgressRules(local.Egress)...)
+}
+
+// NetworkPolicyTypes returns the policy types of the given network policy, defaulting them as performed by the API server
+// if not explicitly set (i.e., Ingress is always included, while Egress only in presence of egress rules).
+func NetworkPolicyTypes(spec *netv1.NetworkPolicySpec) []netv1.PolicyType {
+	if len(spec.PolicyTypes) > 0 {
+		return spec.PolicyTypes
+	}
+
+	types := []netv1.PolicyType{netv1.PolicyTypeIngress}
+	if len(spec.Egress) > 0 {
+		types = append(types, netv1.PolicyTypeEgress)
+	}
+	return types
+}
+
+// RemoteNetworkPolicyIngressRules forges the apply patch for the ingress rules of the reflected network policy, given the local ones.
+func RemoteNetworkPolicyIngressRules(local []netv1.NetworkPolicyIngressRule) []*netv1apply.NetworkPolicyIngressRuleApplyConfiguration {
+	remote := make([]*netv1apply.NetworkPolicyIngressRuleApplyConfiguration, len(local))
+	for i := range local {
+		remote[i] = netv1apply.NetworkPolicyIngressRule().
+			WithPorts(RemoteNetworkPolicyPorts(local[i].Ports)...).
+			WithFrom(RemoteNetworkPolicyPeers(local[i].From)...)
+	}
+	return remote
+}
+
+// RemoteNetworkPolicyEgressRules forges the apply patch for the egress rules of the reflected network policy, given the local ones.
+func RemoteNetworkPolicyEgressRules(local []netv1.NetworkPolicyEgressRule) []*netv1apply.NetworkPolicyEgressRuleApplyConfiguration {
+	remote := make([]*netv1apply.NetworkPolicyEgressRuleApplyConfiguration, len(local))
+	for i := range local {
+		remote[i] = netv1apply.NetworkPolicyEgressRule().
+			WithPorts(RemoteNetworkPolicyPorts(local[i].Ports)...).
+			WithTo(RemoteNetworkPolicyPeers(local[i].To)...)
+	}
+	return remote
+}
+
+// RemoteNetworkPolicyPorts forges the apply patch for the ports of a rule of the reflected network policy, given the local ones.
+func RemoteNetworkPolicyPorts(local []netv1.NetworkPolicyPort) []*netv1apply.NetworkPolicyPortApplyConfiguration {
+	remote := make([]*netv1apply.NetworkPolicyPortApplyConfiguration, len(local))
+	for i := range local {
+		remote[i] = netv1apply.NetworkPolicyPort()
+		if local[i].Protocol != nil {
+			remote[i].WithProtocol(*local[i].Protocol)
+		}
+		if local[i].Port != nil {
+			remote[i].WithPort(*local[i].Port)
+		}
+		if local[i].EndPort != nil {
+			remote[i].WithEndPort(*local[i].EndPort)
+		}
+	}
+	return remote
+}
+
+// RemoteNetworkPolicyPeers forges the apply patch for the peers of a rule of the reflected network policy, given the local ones.
+func RemoteNetworkPolicyPeers(local []netv1.NetworkPolicyPeer) []*netv1apply.NetworkPolicyPeerApplyConfiguration {
+	remote := make([]*netv1apply.NetworkPolicyPeerApplyConfiguration, len(local))
+	for i := range local {
+		remote[i] = netv1apply.NetworkPolicyPeer()
+		if local[i].PodSelector != nil {
+			remote[i].WithPodSelector(RemoteLabelSelector(local[i].PodSelector))
+		}
+		if local[i].NamespaceSelector != nil {
+			remote[i].WithNamespaceSelector(RemoteLabelSelector(local[i].NamespaceSelector))
+		}
+		if local[i].IPBlock != nil {
+			remote[i].WithIPBlock(netv1apply.IPBlock().WithCIDR(local[i].IPBlock.CIDR).WithExcept(local[i].IPBlock.Except...))
+		}
+	}
+	return remote
+}
+
+// RemoteLabelSelector forges the apply patch for a label selector, given the local one.
+func RemoteLabelSelector(local *metav1.LabelSelector) *metav1apply.LabelSelectorApplyConfiguration {
+	remote := metav1apply.LabelSelector().WithMatchLabels(local.MatchLabels)
+	for i := range local.MatchExpressions {
+		remote.WithMatchExpressions(metav1apply.LabelSelectorRequirement().
+			WithKey(local.MatchExpressions[i].Key).
+			WithOperator(local.MatchExpressions[i].Operator).
+			WithValues(local.MatchExpressions[i].Values...))
+	}
+	return remote
+}
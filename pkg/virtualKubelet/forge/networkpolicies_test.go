@@ -0,0 +1,144 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forge_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	metav1apply "k8s.io/client-go/applyconfigurations/meta/v1"
+	netv1apply "k8s.io/client-go/applyconfigurations/networking/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/liqotech/liqo/pkg/utils/testutil"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+)
+
+var _ = Describe("NetworkPolicies Forging", func() {
+	Describe("the RemoteNetworkPolicy function", func() {
+		var (
+			input       *netv1.NetworkPolicy
+			output      *netv1apply.NetworkPolicyApplyConfiguration
+			forgingOpts *forge.ForgingOpts
+		)
+
+		BeforeEach(func() {
+			protocol := corev1.ProtocolTCP
+			port := intstr.FromInt(8080)
+
+			input = &netv1.NetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "name", Namespace: "original",
+					Labels:      map[string]string{"foo": "bar", testutil.FakeNotReflectedLabelKey: "true"},
+					Annotations: map[string]string{"bar": "baz", testutil.FakeNotReflectedAnnotKey: "true"},
+				},
+				Spec: netv1.NetworkPolicySpec{
+					PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "server"}},
+					Ingress: []netv1.NetworkPolicyIngressRule{{
+						Ports: []netv1.NetworkPolicyPort{{Protocol: &protocol, Port: &port}},
+						From: []netv1.NetworkPolicyPeer{
+							{PodSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+								{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"client"}},
+							}}},
+							{IPBlock: &netv1.IPBlock{CIDR: "10.0.0.0/16", Except: []string{"10.0.1.0/24"}}},
+						},
+					}},
+					Egress: []netv1.NetworkPolicyEgressRule{{
+						To: []netv1.NetworkPolicyPeer{{IPBlock: &netv1.IPBlock{CIDR: "0.0.0.0/0"}}},
+					}},
+				},
+			}
+			forgingOpts = testutil.FakeForgingOpts()
+		})
+
+		JustBeforeEach(func() { output = forge.RemoteNetworkPolicy(input, "reflected", forgingOpts) })
+
+		It("should correctly set the name and namespace", func() {
+			Expect(output.Name).To(PointTo(Equal("name")))
+			Expect(output.Namespace).To(PointTo(Equal("reflected")))
+		})
+
+		It("should correctly set the labels", func() {
+			Expect(output.Labels).To(HaveKeyWithValue("foo", "bar"))
+			Expect(output.Labels).To(HaveKeyWithValue(forge.LiqoOriginClusterIDKey, LocalClusterID))
+			Expect(output.Labels).To(HaveKeyWithValue(forge.LiqoDestinationClusterIDKey, RemoteClusterID))
+			Expect(output.Labels).ToNot(HaveKey(testutil.FakeNotReflectedLabelKey))
+		})
+
+		It("should correctly set the annotations", func() {
+			Expect(output.Annotations).To(HaveKeyWithValue("bar", "baz"))
+			Expect(output.Annotations).ToNot(HaveKey(testutil.FakeNotReflectedAnnotKey))
+		})
+
+		It("should correctly set the pod selector", func() {
+			Expect(output.Spec.PodSelector.MatchLabels).To(HaveKeyWithValue("app", "server"))
+		})
+
+		It("should make the policy types explicit", func() {
+			Expect(output.Spec.PolicyTypes).To(ConsistOf(netv1.PolicyTypeIngress, netv1.PolicyTypeEgress))
+		})
+
+		It("should correctly set the ingress rules", func() {
+			Expect(output.Spec.Ingress).To(HaveLen(1))
+			Expect(output.Spec.Ingress[0].Ports).To(HaveLen(1))
+			Expect(output.Spec.Ingress[0].Ports[0].Protocol).To(PointTo(Equal(corev1.ProtocolTCP)))
+			Expect(output.Spec.Ingress[0].Ports[0].Port).To(PointTo(Equal(intstr.FromInt(8080))))
+			Expect(output.Spec.Ingress[0].Ports[0].EndPort).To(BeNil())
+			Expect(output.Spec.Ingress[0].From).To(HaveLen(2))
+			operator := metav1.LabelSelectorOpIn
+			Expect(output.Spec.Ingress[0].From[0].PodSelector.MatchExpressions).To(Equal([]metav1apply.LabelSelectorRequirementApplyConfiguration{{
+				Key: pointer.String("app"), Operator: &operator, Values: []string{"client"},
+			}}))
+			Expect(output.Spec.Ingress[0].From[0].NamespaceSelector).To(BeNil())
+			Expect(output.Spec.Ingress[0].From[1].IPBlock).To(PointTo(Equal(netv1apply.IPBlockApplyConfiguration{
+				CIDR: pointer.String("10.0.0.0/16"), Except: []string{"10.0.1.0/24"},
+			})))
+		})
+
+		It("should correctly set the egress rules", func() {
+			Expect(output.Spec.Egress).To(HaveLen(1))
+			Expect(output.Spec.Egress[0].Ports).To(BeEmpty())
+			Expect(output.Spec.Egress[0].To).To(HaveLen(1))
+			Expect(output.Spec.Egress[0].To[0].IPBlock.CIDR).To(PointTo(Equal("0.0.0.0/0")))
+		})
+	})
+
+	Describe("the NetworkPolicyTypes function", func() {
+		type testcase struct {
+			spec     netv1.NetworkPolicySpec
+			expected []netv1.PolicyType
+		}
+
+		DescribeTable("should return the correct policy types",
+			func(c testcase) { Expect(forge.NetworkPolicyTypes(&c.spec)).To(Equal(c.expected)) },
+			Entry("explicit policy types", testcase{
+				spec:     netv1.NetworkPolicySpec{PolicyTypes: []netv1.PolicyType{netv1.PolicyTypeEgress}},
+				expected: []netv1.PolicyType{netv1.PolicyTypeEgress},
+			}),
+			Entry("implicit policy types, without egress rules", testcase{
+				spec:     netv1.NetworkPolicySpec{},
+				expected: []netv1.PolicyType{netv1.PolicyTypeIngress},
+			}),
+			Entry("implicit policy types, with egress rules", testcase{
+				spec:     netv1.NetworkPolicySpec{Egress: []netv1.NetworkPolicyEgressRule{{}}},
+				expected: []netv1.PolicyType{netv1.PolicyTypeIngress, netv1.PolicyTypeEgress},
+			}),
+		)
+	})
+})
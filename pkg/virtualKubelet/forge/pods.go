@@ -31,6 +31,7 @@ import (
 	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
 	liqoconst "github.com/liqotech/liqo/pkg/consts"
 	"github.com/liqotech/liqo/pkg/utils/maps"
+	podutils "github.com/liqotech/liqo/pkg/utils/pod"
 )
 
 const (
@@ -155,17 +156,18 @@ func RemoteShadowPod(local *corev1.Pod, remote *vkv1alpha1.ShadowPod,
 		remote = &vkv1alpha1.ShadowPod{ObjectMeta: metav1.ObjectMeta{Name: local.GetName(), Namespace: targetNamespace}}
 	}
 
-	// Remove the label which identifies offloaded pods, as meaningful only locally.
+	// Remove the labels which identify offloaded and admitted pods, as meaningful only locally.
 	localMetaFiltered := local.ObjectMeta.DeepCopy()
 	if localMetaFiltered.GetLabels() == nil {
 		localMetaFiltered.Labels = map[string]string{}
 	}
 	delete(localMetaFiltered.GetLabels(), liqoconst.LocalPodLabelKey)
+	delete(localMetaFiltered.GetLabels(), liqoconst.AdmittedPodLabelKey)
 	localMetaFiltered.GetLabels()[LiqoOriginClusterNodeName] = LiqoNodeName
 
 	// Filter out the labels and annotations not to be reflected.
-	localMetaFiltered.SetLabels(FilterNotReflected(localMetaFiltered.GetLabels(), forgingOpts.LabelsNotReflected))
-	localMetaFiltered.SetAnnotations(FilterNotReflected(localMetaFiltered.GetAnnotations(), forgingOpts.AnnotationsNotReflected))
+	localMetaFiltered.SetLabels(forgingOpts.FilterLabels(local, localMetaFiltered.GetLabels()))
+	localMetaFiltered.SetAnnotations(forgingOpts.FilterAnnotations(local, localMetaFiltered.GetAnnotations()))
 
 	// Initialize the appropriate anti-affinity mutator if the corresponding annotation is present.
 	switch local.Annotations[liqoconst.PodAntiAffinityPresetKey] {
@@ -193,12 +195,16 @@ func RemotePodSpec(creation bool, local, remote *corev1.PodSpec, mutators ...Rem
 	// Do not mutate the pod specifications after it has been created, since it is likely the modification
 	// would be rejected by the API server, as only a very limited set of fields can be mutated.
 	// Additionally, such modification would not be currently propagated by the remote ShadowPod controller.
+	// The only exception are ephemeral containers (e.g., added by kubectl debug), which are propagated by the
+	// remote ShadowPod controller through the appropriate subresource.
 	if !creation {
+		remote.EphemeralContainers = local.EphemeralContainers
 		return *remote
 	}
 
 	remote.Containers = local.Containers
 	remote.InitContainers = local.InitContainers
+	remote.EphemeralContainers = local.EphemeralContainers
 
 	remote.Tolerations = RemoteTolerations(local.Tolerations)
 	remote.Volumes = local.Volumes
@@ -266,6 +272,17 @@ func APIServerSupportMutator(apiServerSupport APIServerSupportType, localAnnotat
 	}
 }
 
+// APIServerSupportMutators returns the mutators implementing the support to enable the given offloaded pod to interact back
+// with the local Kubernetes API server, as well as the propagation of its service account name to the remote cluster.
+func APIServerSupportMutators(local *corev1.Pod, apiServerSupport APIServerSupportType, saSecretRetriever SASecretRetriever,
+	kubernetesServiceIPRetriever KubernetesServiceIPGetter, homeAPIServerHost, homeAPIServerPort string) []RemotePodSpecMutator {
+	return []RemotePodSpecMutator{
+		APIServerSupportMutator(apiServerSupport, local.Annotations, podutils.ServiceAccountName(local),
+			saSecretRetriever, kubernetesServiceIPRetriever, homeAPIServerHost, homeAPIServerPort),
+		ServiceAccountMutator(apiServerSupport, local.Annotations),
+	}
+}
+
 // ServiceAccountMutator is a mutator which implements the support to propagate the service account name to the remote cluster.
 func ServiceAccountMutator(apiServerSupport APIServerSupportType, localAnnotations map[string]string) RemotePodSpecMutator {
 	return func(remote *corev1.PodSpec) {
@@ -50,7 +50,8 @@ var _ = Describe("Pod forging", func() {
 				ObjectMeta: metav1.ObjectMeta{Name: "remote-name", Namespace: "remote-namespace"},
 				Status: corev1.PodStatus{
 					Phase: corev1.PodRunning, PodIP: "remote-ip",
-					ContainerStatuses: []corev1.ContainerStatus{{Ready: true, RestartCount: 1}}},
+					ContainerStatuses:          []corev1.ContainerStatus{{Ready: true, RestartCount: 1}},
+					EphemeralContainerStatuses: []corev1.ContainerStatus{{Name: "debugger", Ready: false}}},
 			}
 		})
 
@@ -65,6 +66,7 @@ var _ = Describe("Pod forging", func() {
 			Expect(output.Status.ContainerStatuses).To(HaveLen(1))
 			Expect(output.Status.ContainerStatuses[0].Ready).To(BeTrue())
 			Expect(output.Status.ContainerStatuses[0].RestartCount).To(BeNumerically("==", 4))
+			Expect(output.Status.EphemeralContainerStatuses).To(ConsistOf(corev1.ContainerStatus{Name: "debugger", Ready: false}))
 		})
 	})
 
@@ -210,6 +212,22 @@ var _ = Describe("Pod forging", func() {
 			It("should not update the pod spec", func() {
 				Expect(output.Spec.Pod).To(Equal(corev1.PodSpec{}))
 			})
+
+			When("ephemeral containers have been added to the local pod", func() {
+				var ephemeral corev1.EphemeralContainer
+
+				BeforeEach(func() {
+					ephemeral = corev1.EphemeralContainer{
+						EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger", Image: "busybox"},
+						TargetContainerName:      "foo",
+					}
+					local.Spec.EphemeralContainers = []corev1.EphemeralContainer{ephemeral}
+				})
+
+				It("should propagate only the ephemeral containers", func() {
+					Expect(output.Spec.Pod).To(Equal(corev1.PodSpec{EphemeralContainers: []corev1.EphemeralContainer{ephemeral}}))
+				})
+			})
 		})
 	})
 
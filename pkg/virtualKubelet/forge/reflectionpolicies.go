@@ -0,0 +1,152 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forge
+
+import (
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
+	vklisters "github.com/liqotech/liqo/pkg/client/listers/virtualkubelet/v1alpha1"
+)
+
+// ForResource returns a copy of the ForgingOpts, scoped to the given resource for what concerns
+// the selection of the ReflectionPolicies (e.g., service, configmap, certificates.cert-manager.io).
+func (fo *ForgingOpts) ForResource(resource string) *ForgingOpts {
+	if fo == nil {
+		return nil
+	}
+
+	scoped := *fo
+	scoped.resource = strings.ToLower(resource)
+	return &scoped
+}
+
+// WithReflectionPolicies configures the lister used to retrieve the ReflectionPolicies.
+func (fo *ForgingOpts) WithReflectionPolicies(lister vklisters.ReflectionPolicyLister) *ForgingOpts {
+	fo.ReflectionPolicies = lister
+	return fo
+}
+
+// MatchingReflectionPolicies returns the ReflectionPolicies (sorted by name) applying to the given local object,
+// based on its namespace and labels, as well as on the resource the ForgingOpts are scoped to.
+func (fo *ForgingOpts) MatchingReflectionPolicies(local metav1.Object) []*vkv1alpha1.ReflectionPolicy {
+	if fo == nil || fo.ReflectionPolicies == nil {
+		return nil
+	}
+
+	policies, err := fo.ReflectionPolicies.ReflectionPolicies(local.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to retrieve the ReflectionPolicies in namespace %q: %v", local.GetNamespace(), err)
+		return nil
+	}
+
+	matching := make([]*vkv1alpha1.ReflectionPolicy, 0, len(policies))
+	for _, policy := range policies {
+		if !fo.policyAppliesToResource(policy) {
+			continue
+		}
+
+		selector := labels.Everything()
+		if policy.Spec.Selector != nil {
+			if selector, err = metav1.LabelSelectorAsSelector(policy.Spec.Selector); err != nil {
+				klog.Warningf("Ignoring ReflectionPolicy %q, as featuring an invalid selector: %v", klog.KObj(policy), err)
+				continue
+			}
+		}
+
+		if selector.Matches(labels.Set(local.GetLabels())) {
+			matching = append(matching, policy)
+		}
+	}
+
+	sort.Slice(matching, func(i, j int) bool { return matching[i].GetName() < matching[j].GetName() })
+	return matching
+}
+
+// policyAppliesToResource returns whether the given ReflectionPolicy applies to the resource the ForgingOpts are scoped to.
+func (fo *ForgingOpts) policyAppliesToResource(policy *vkv1alpha1.ReflectionPolicy) bool {
+	if len(policy.Spec.Resources) == 0 {
+		return true
+	}
+
+	for _, resource := range policy.Spec.Resources {
+		if strings.EqualFold(resource, fo.resource) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReflectionPolicyAction returns the action configured by the ReflectionPolicies applying to the given local object.
+// In case of multiple matching policies, the Skip action prevails over the Reflect one. An empty string is returned
+// in case no action is configured, hence delegating the choice to the reflection type.
+func (fo *ForgingOpts) ReflectionPolicyAction(local metav1.Object) vkv1alpha1.ReflectionPolicyAction {
+	var action vkv1alpha1.ReflectionPolicyAction
+	for _, policy := range fo.MatchingReflectionPolicies(local) {
+		switch policy.Spec.Action {
+		case vkv1alpha1.ReflectionPolicyActionSkip:
+			return vkv1alpha1.ReflectionPolicyActionSkip
+		case vkv1alpha1.ReflectionPolicyActionReflect:
+			action = vkv1alpha1.ReflectionPolicyActionReflect
+		}
+	}
+	return action
+}
+
+// FilterLabels returns the given labels of the local object filtered and renamed according to the global
+// configuration and the matching ReflectionPolicies, hence ready to be reflected to the remote cluster.
+func (fo *ForgingOpts) FilterLabels(local metav1.Object, m map[string]string) map[string]string {
+	return fo.filterMetadata(local, m, fo.LabelsNotReflected,
+		func(policy *vkv1alpha1.ReflectionPolicy) *vkv1alpha1.MetadataRules { return &policy.Spec.Labels })
+}
+
+// FilterAnnotations returns the given annotations of the local object filtered and renamed according to the global
+// configuration and the matching ReflectionPolicies, hence ready to be reflected to the remote cluster.
+func (fo *ForgingOpts) FilterAnnotations(local metav1.Object, m map[string]string) map[string]string {
+	return fo.filterMetadata(local, m, fo.AnnotationsNotReflected,
+		func(policy *vkv1alpha1.ReflectionPolicy) *vkv1alpha1.MetadataRules { return &policy.Spec.Annotations })
+}
+
+func (fo *ForgingOpts) filterMetadata(local metav1.Object, m map[string]string, notReflected []string,
+	rules func(*vkv1alpha1.ReflectionPolicy) *vkv1alpha1.MetadataRules) map[string]string {
+	policies := fo.MatchingReflectionPolicies(local)
+
+	skip := notReflected
+	for _, policy := range policies {
+		skip = append(skip[:len(skip):len(skip)], rules(policy).Skip...)
+	}
+	filtered := FilterNotReflected(m, skip)
+
+	// Rename the keys in a separate step, to prevent the renamed keys from being renamed again.
+	renamed := make(map[string]string, len(filtered))
+	for _, policy := range policies {
+		for _, rename := range rules(policy).Rename {
+			value, found := filtered[rename.From]
+			if _, done := renamed[rename.To]; !found || done || rename.To == "" {
+				continue
+			}
+
+			delete(filtered, rename.From)
+			renamed[rename.To] = value
+		}
+	}
+
+	return labels.Merge(filtered, renamed)
+}
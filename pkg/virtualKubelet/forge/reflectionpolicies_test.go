@@ -0,0 +1,136 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forge_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
+	vklisters "github.com/liqotech/liqo/pkg/client/listers/virtualkubelet/v1alpha1"
+	"github.com/liqotech/liqo/pkg/utils/testutil"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+)
+
+var _ = Describe("ReflectionPolicies", func() {
+	var (
+		indexer     cache.Indexer
+		forgingOpts *forge.ForgingOpts
+		local       *metav1.ObjectMeta
+	)
+
+	policy := func(name, namespace string, spec vkv1alpha1.ReflectionPolicySpec) *vkv1alpha1.ReflectionPolicy {
+		return &vkv1alpha1.ReflectionPolicy{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}, Spec: spec}
+	}
+
+	BeforeEach(func() {
+		indexer = cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		forgingOpts = testutil.FakeForgingOpts().WithReflectionPolicies(vklisters.NewReflectionPolicyLister(indexer)).ForResource("Service")
+		local = &metav1.ObjectMeta{Name: "name", Namespace: "namespace", Labels: map[string]string{"app": "foo"}}
+	})
+
+	Describe("the MatchingReflectionPolicies function", func() {
+		BeforeEach(func() {
+			Expect(indexer.Add(policy("zzz", "namespace", vkv1alpha1.ReflectionPolicySpec{}))).To(Succeed())
+			Expect(indexer.Add(policy("aaa", "namespace", vkv1alpha1.ReflectionPolicySpec{Resources: []string{"service"}}))).To(Succeed())
+			Expect(indexer.Add(policy("other-namespace", "other", vkv1alpha1.ReflectionPolicySpec{}))).To(Succeed())
+			Expect(indexer.Add(policy("other-resource", "namespace", vkv1alpha1.ReflectionPolicySpec{Resources: []string{"configmap"}}))).To(Succeed())
+			Expect(indexer.Add(policy("selected", "namespace", vkv1alpha1.ReflectionPolicySpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}}}))).To(Succeed())
+			Expect(indexer.Add(policy("not-selected", "namespace", vkv1alpha1.ReflectionPolicySpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "bar"}}}))).To(Succeed())
+		})
+
+		It("should return the matching policies, sorted by name", func() {
+			var names []string
+			for _, matching := range forgingOpts.MatchingReflectionPolicies(local) {
+				names = append(names, matching.GetName())
+			}
+			Expect(names).To(Equal([]string{"aaa", "selected", "zzz"}))
+		})
+
+		When("the ReflectionPolicies lister is not configured", func() {
+			BeforeEach(func() { forgingOpts = testutil.FakeForgingOpts() })
+			It("should return no policies", func() { Expect(forgingOpts.MatchingReflectionPolicies(local)).To(BeEmpty()) })
+		})
+	})
+
+	Describe("the ReflectionPolicyAction function", func() {
+		When("no policy configures the action", func() {
+			BeforeEach(func() {
+				Expect(indexer.Add(policy("foo", "namespace", vkv1alpha1.ReflectionPolicySpec{}))).To(Succeed())
+			})
+			It("should return an empty action", func() { Expect(forgingOpts.ReflectionPolicyAction(local)).To(BeEmpty()) })
+		})
+
+		When("a policy configures the reflect action", func() {
+			BeforeEach(func() {
+				Expect(indexer.Add(policy("foo", "namespace", vkv1alpha1.ReflectionPolicySpec{
+					Action: vkv1alpha1.ReflectionPolicyActionReflect}))).To(Succeed())
+			})
+			It("should return the reflect action", func() {
+				Expect(forgingOpts.ReflectionPolicyAction(local)).To(Equal(vkv1alpha1.ReflectionPolicyActionReflect))
+			})
+		})
+
+		When("policies configure conflicting actions", func() {
+			BeforeEach(func() {
+				Expect(indexer.Add(policy("foo", "namespace", vkv1alpha1.ReflectionPolicySpec{
+					Action: vkv1alpha1.ReflectionPolicyActionReflect}))).To(Succeed())
+				Expect(indexer.Add(policy("bar", "namespace", vkv1alpha1.ReflectionPolicySpec{
+					Action: vkv1alpha1.ReflectionPolicyActionSkip}))).To(Succeed())
+			})
+			It("should return the skip action", func() {
+				Expect(forgingOpts.ReflectionPolicyAction(local)).To(Equal(vkv1alpha1.ReflectionPolicyActionSkip))
+			})
+		})
+	})
+
+	Describe("the FilterLabels and FilterAnnotations functions", func() {
+		var input map[string]string
+
+		BeforeEach(func() {
+			input = map[string]string{
+				"foo": "bar", "skipped": "true", "renamed": "value",
+				testutil.FakeNotReflectedLabelKey: "true", testutil.FakeNotReflectedAnnotKey: "true",
+			}
+
+			rules := vkv1alpha1.MetadataRules{
+				Skip:   []string{"skipped"},
+				Rename: []vkv1alpha1.KeyRename{{From: "renamed", To: "remote.io/renamed"}},
+			}
+			Expect(indexer.Add(policy("foo", "namespace", vkv1alpha1.ReflectionPolicySpec{Labels: rules, Annotations: rules}))).To(Succeed())
+		})
+
+		It("should correctly filter and rename the labels", func() {
+			Expect(forgingOpts.FilterLabels(local, input)).To(Equal(map[string]string{
+				"foo": "bar", "remote.io/renamed": "value", testutil.FakeNotReflectedAnnotKey: "true",
+			}))
+		})
+
+		It("should correctly filter and rename the annotations", func() {
+			Expect(forgingOpts.FilterAnnotations(local, input)).To(Equal(map[string]string{
+				"foo": "bar", "remote.io/renamed": "value", testutil.FakeNotReflectedLabelKey: "true",
+			}))
+		})
+
+		It("should not mutate the input map", func() {
+			forgingOpts.FilterLabels(local, input)
+			Expect(input).To(HaveLen(5))
+		})
+	})
+})
@@ -63,8 +63,8 @@ func IsServiceAccountSecret(obj metav1.Object) bool {
 // RemoteSecret forges the apply patch for the reflected secret, given the local one.
 func RemoteSecret(local *corev1.Secret, targetNamespace string, forgingOpts *ForgingOpts) *corev1apply.SecretApplyConfiguration {
 	applyConfig := corev1apply.Secret(local.GetName(), targetNamespace).
-		WithLabels(FilterNotReflected(local.GetLabels(), forgingOpts.LabelsNotReflected)).WithLabels(ReflectionLabels()).
-		WithAnnotations(FilterNotReflected(local.GetAnnotations(), forgingOpts.AnnotationsNotReflected)).
+		WithLabels(forgingOpts.FilterLabels(local, local.GetLabels())).WithLabels(ReflectionLabels()).
+		WithAnnotations(forgingOpts.FilterAnnotations(local, local.GetAnnotations())).
 		WithData(local.Data).
 		WithType(local.Type)
 
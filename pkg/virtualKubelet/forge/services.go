@@ -28,8 +28,8 @@ const nodePortUnset = 0
 // RemoteService forges the apply patch for the reflected service, given the local one.
 func RemoteService(local *corev1.Service, targetNamespace string, forgingOpts *ForgingOpts) *corev1apply.ServiceApplyConfiguration {
 	return corev1apply.Service(local.GetName(), targetNamespace).
-		WithLabels(FilterNotReflected(local.GetLabels(), forgingOpts.LabelsNotReflected)).WithLabels(ReflectionLabels()).
-		WithAnnotations(FilterNotReflected(local.GetAnnotations(), forgingOpts.AnnotationsNotReflected)).
+		WithLabels(forgingOpts.FilterLabels(local, local.GetLabels())).WithLabels(ReflectionLabels()).
+		WithAnnotations(forgingOpts.FilterAnnotations(local, local.GetAnnotations())).
 		WithSpec(RemoteServiceSpec(local.Spec.DeepCopy(), getForceRemoteNodePort(local)))
 }
 
@@ -0,0 +1,97 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forge
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1apply "k8s.io/client-go/applyconfigurations/core/v1"
+)
+
+const (
+	// LiqoGoverningServiceKey is the key of a label identifying the remote services reflected
+	// as governing services of (at least) one StatefulSet.
+	LiqoGoverningServiceKey = "virtualkubelet.liqo.io/statefulset-governing"
+
+	// StatefulSetReflectionFieldManager -> The name associated with the fields modified by the StatefulSet reflection.
+	// A dedicated field manager is used, so that the governing services can be co-owned with the service reflection.
+	StatefulSetReflectionFieldManager = ReflectionFieldManager + "/statefulset"
+)
+
+// RemoteGoverningService forges the apply patch for the reflected governing service of a StatefulSet, given the local one.
+func RemoteGoverningService(local *corev1.Service, targetNamespace string, forgingOpts *ForgingOpts) *corev1apply.ServiceApplyConfiguration {
+	return RemoteService(local, targetNamespace, forgingOpts).
+		WithLabels(map[string]string{LiqoGoverningServiceKey: "true"})
+}
+
+// IsGoverningService returns whether the given remote service has been reflected as the governing service of a StatefulSet.
+func IsGoverningService(obj metav1.Object) bool {
+	return obj.GetLabels()[LiqoGoverningServiceKey] == "true"
+}
+
+// ReleasedGoverningService forges the apply patch relinquishing the fields of a remote governing service owned by the
+// StatefulSet reflection (e.g., the governing label), while leaving the others to the service reflection.
+func ReleasedGoverningService(name, targetNamespace string) *corev1apply.ServiceApplyConfiguration {
+	return corev1apply.Service(name, targetNamespace)
+}
+
+// IsServiceReflected returns whether the given remote governing service is also managed by the service reflection.
+func IsServiceReflected(obj metav1.Object) bool {
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Manager == ReflectionFieldManager && entry.Subresource == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsHeadlessService returns whether the given service is headless, hence suitable to provide per-pod DNS records.
+func IsHeadlessService(svc *corev1.Service) bool {
+	return svc.Spec.ClusterIP == corev1.ClusterIPNone
+}
+
+// StatefulSetClaimName returns the name of the PersistentVolumeClaim created by the StatefulSet controller
+// for the given volume claim template and pod ordinal.
+func StatefulSetClaimName(template string, sts *appsv1.StatefulSet, ordinal int32) string {
+	return fmt.Sprintf("%s-%s-%d", template, sts.GetName(), ordinal)
+}
+
+// StatefulSetOrdinals returns the list of ordinals of the pods expected to be managed by the given StatefulSet.
+func StatefulSetOrdinals(sts *appsv1.StatefulSet) []int32 {
+	var start, replicas int32 = 0, 1
+	if sts.Spec.Ordinals != nil {
+		start = sts.Spec.Ordinals.Start
+	}
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+
+	ordinals := make([]int32, 0, replicas)
+	for i := start; i < start+replicas; i++ {
+		ordinals = append(ordinals, i)
+	}
+	return ordinals
+}
+
+// StatefulSetApplyOptions returns the apply options configured for the StatefulSet reflection.
+func StatefulSetApplyOptions() metav1.ApplyOptions {
+	return metav1.ApplyOptions{
+		Force:        true,
+		FieldManager: StatefulSetReflectionFieldManager,
+	}
+}
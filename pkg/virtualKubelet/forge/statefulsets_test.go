@@ -0,0 +1,102 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forge_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1apply "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/liqotech/liqo/pkg/utils/testutil"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+)
+
+var _ = Describe("StatefulSets Forging", func() {
+	Describe("the RemoteGoverningService function", func() {
+		var (
+			input  *corev1.Service
+			output *corev1apply.ServiceApplyConfiguration
+		)
+
+		BeforeEach(func() {
+			input = &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "name", Namespace: "original", Labels: map[string]string{"foo": "bar"}},
+				Spec:       corev1.ServiceSpec{ClusterIP: corev1.ClusterIPNone, Selector: map[string]string{"app": "db"}},
+			}
+		})
+
+		JustBeforeEach(func() { output = forge.RemoteGoverningService(input, "reflected", testutil.FakeForgingOpts()) })
+
+		It("should correctly set the name and namespace", func() {
+			Expect(output.Name).To(PointTo(Equal("name")))
+			Expect(output.Namespace).To(PointTo(Equal("reflected")))
+		})
+		It("should correctly set the labels", func() {
+			Expect(output.Labels).To(HaveKeyWithValue("foo", "bar"))
+			Expect(output.Labels).To(HaveKeyWithValue(forge.LiqoOriginClusterIDKey, LocalClusterID))
+			Expect(output.Labels).To(HaveKeyWithValue(forge.LiqoDestinationClusterIDKey, RemoteClusterID))
+			Expect(output.Labels).To(HaveKeyWithValue(forge.LiqoGoverningServiceKey, "true"))
+		})
+		It("should preserve the headless nature of the service", func() {
+			Expect(output.Spec.ClusterIP).To(PointTo(Equal(corev1.ClusterIPNone)))
+			Expect(output.Spec.Selector).To(HaveKeyWithValue("app", "db"))
+		})
+	})
+
+	Describe("the IsGoverningService function", func() {
+		It("should return true if the label is present", func() {
+			obj := &metav1.ObjectMeta{Labels: map[string]string{forge.LiqoGoverningServiceKey: "true"}}
+			Expect(forge.IsGoverningService(obj)).To(BeTrue())
+		})
+		It("should return false if the label is absent", func() {
+			Expect(forge.IsGoverningService(&metav1.ObjectMeta{})).To(BeFalse())
+		})
+	})
+
+	Describe("the IsServiceReflected function", func() {
+		It("should return true if managed by the service reflection", func() {
+			obj := &metav1.ObjectMeta{ManagedFields: []metav1.ManagedFieldsEntry{
+				{Manager: forge.StatefulSetReflectionFieldManager}, {Manager: forge.ReflectionFieldManager}}}
+			Expect(forge.IsServiceReflected(obj)).To(BeTrue())
+		})
+		It("should return false if managed by the StatefulSet reflection only", func() {
+			obj := &metav1.ObjectMeta{ManagedFields: []metav1.ManagedFieldsEntry{{Manager: forge.StatefulSetReflectionFieldManager}}}
+			Expect(forge.IsServiceReflected(obj)).To(BeFalse())
+		})
+	})
+
+	Describe("the StatefulSetClaimName function", func() {
+		It("should return the name generated by the StatefulSet controller", func() {
+			sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "web"}}
+			Expect(forge.StatefulSetClaimName("data", sts, 2)).To(Equal("data-web-2"))
+		})
+	})
+
+	DescribeTable("the StatefulSetOrdinals function",
+		func(spec appsv1.StatefulSetSpec, expected []int32) {
+			Expect(forge.StatefulSetOrdinals(&appsv1.StatefulSet{Spec: spec})).To(Equal(expected))
+		},
+		Entry("replicas not set", appsv1.StatefulSetSpec{}, []int32{0}),
+		Entry("no replicas", appsv1.StatefulSetSpec{Replicas: pointer.Int32(0)}, []int32{}),
+		Entry("multiple replicas", appsv1.StatefulSetSpec{Replicas: pointer.Int32(3)}, []int32{0, 1, 2}),
+		Entry("custom start ordinal", appsv1.StatefulSetSpec{Replicas: pointer.Int32(2),
+			Ordinals: &appsv1.StatefulSetOrdinals{Start: 5}}, []int32{5, 6}),
+	)
+})
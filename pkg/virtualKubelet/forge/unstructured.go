@@ -0,0 +1,65 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forge
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// RemoteUnstructured forges the apply patch for the reflected custom resource, given the local one.
+// Only the fields identified by the given paths (dot-separated, relative to the object root) are propagated.
+func RemoteUnstructured(local *unstructured.Unstructured, targetNamespace string,
+	fields []string, forgingOpts *ForgingOpts) *unstructured.Unstructured {
+	remote := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	remote.SetAPIVersion(local.GetAPIVersion())
+	remote.SetKind(local.GetKind())
+	remote.SetName(local.GetName())
+	remote.SetNamespace(targetNamespace)
+	remote.SetLabels(labels.Merge(forgingOpts.FilterLabels(local, local.GetLabels()), ReflectionLabels()))
+	remote.SetAnnotations(forgingOpts.FilterAnnotations(local, local.GetAnnotations()))
+
+	copyUnstructuredFields(local, remote, fields)
+	return remote
+}
+
+// LocalUnstructuredStatus forges the local custom resource with the status fields retrieved from the remote one.
+// Fields identified by the given paths and not present in the remote object are removed from the local one.
+func LocalUnstructuredStatus(local, remote *unstructured.Unstructured, fields []string) *unstructured.Unstructured {
+	updated := local.DeepCopy()
+	for _, path := range fields {
+		unstructured.RemoveNestedField(updated.Object, strings.Split(path, ".")...)
+	}
+
+	copyUnstructuredFields(remote, updated, fields)
+	return updated
+}
+
+// copyUnstructuredFields copies the fields identified by the given paths from the source to the destination object.
+// Fields not present in the source object, or not reachable since an intermediate element is not a map, are skipped.
+func copyUnstructuredFields(src, dst *unstructured.Unstructured, fields []string) {
+	for _, path := range fields {
+		keys := strings.Split(path, ".")
+		value, found, err := unstructured.NestedFieldCopy(src.Object, keys...)
+		if err != nil || !found {
+			continue
+		}
+
+		// The error is ignored, as it can only occur in case of overlapping paths with incompatible types.
+		_ = unstructured.SetNestedField(dst.Object, value, keys...)
+	}
+}
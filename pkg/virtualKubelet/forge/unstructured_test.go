@@ -0,0 +1,133 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forge_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/liqotech/liqo/pkg/utils/testutil"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+)
+
+var _ = Describe("Unstructured Forging", func() {
+	var local, remote *unstructured.Unstructured
+
+	BeforeEach(func() {
+		local = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Example",
+			"metadata": map[string]interface{}{
+				"name": "name", "namespace": "original",
+				"labels":      map[string]interface{}{"foo": "bar", testutil.FakeNotReflectedLabelKey: "true"},
+				"annotations": map[string]interface{}{"bar": "baz", testutil.FakeNotReflectedAnnotKey: "true"},
+			},
+			"spec": map[string]interface{}{
+				"replicas": int64(3),
+				"template": map[string]interface{}{"image": "nginx"},
+				"local":    "value",
+			},
+			"status": map[string]interface{}{
+				"phase":      "Pending",
+				"conditions": []interface{}{"local"},
+			},
+		}}
+	})
+
+	Describe("the RemoteUnstructured function", func() {
+		var (
+			fields []string
+			output *unstructured.Unstructured
+		)
+
+		BeforeEach(func() { fields = []string{"spec.replicas", "spec.template", "spec.missing"} })
+		JustBeforeEach(func() { output = forge.RemoteUnstructured(local, "reflected", fields, testutil.FakeForgingOpts()) })
+
+		It("should correctly set the type meta", func() {
+			Expect(output.GetAPIVersion()).To(Equal("example.com/v1"))
+			Expect(output.GetKind()).To(Equal("Example"))
+		})
+
+		It("should correctly set the name and namespace", func() {
+			Expect(output.GetName()).To(Equal("name"))
+			Expect(output.GetNamespace()).To(Equal("reflected"))
+		})
+
+		It("should correctly set the labels", func() {
+			Expect(output.GetLabels()).To(HaveKeyWithValue("foo", "bar"))
+			Expect(output.GetLabels()).To(HaveKeyWithValue(forge.LiqoOriginClusterIDKey, LocalClusterID))
+			Expect(output.GetLabels()).To(HaveKeyWithValue(forge.LiqoDestinationClusterIDKey, RemoteClusterID))
+			Expect(output.GetLabels()).ToNot(HaveKey(testutil.FakeNotReflectedLabelKey))
+		})
+
+		It("should correctly set the annotations", func() {
+			Expect(output.GetAnnotations()).To(HaveKeyWithValue("bar", "baz"))
+			Expect(output.GetAnnotations()).ToNot(HaveKey(testutil.FakeNotReflectedAnnotKey))
+		})
+
+		It("should propagate only the selected fields", func() {
+			Expect(output.Object).To(HaveKeyWithValue("spec", map[string]interface{}{
+				"replicas": int64(3),
+				"template": map[string]interface{}{"image": "nginx"},
+			}))
+			Expect(output.Object).ToNot(HaveKey("status"))
+		})
+
+		When("the whole spec is selected", func() {
+			BeforeEach(func() { fields = []string{"spec"} })
+
+			It("should propagate the entire spec", func() {
+				Expect(output.Object).To(HaveKeyWithValue("spec", local.Object["spec"]))
+			})
+		})
+	})
+
+	Describe("the LocalUnstructuredStatus function", func() {
+		var (
+			fields []string
+			output *unstructured.Unstructured
+		)
+
+		BeforeEach(func() {
+			fields = []string{"status.conditions", "status.ready"}
+			remote = &unstructured.Unstructured{Object: map[string]interface{}{
+				"status": map[string]interface{}{
+					"phase": "Running",
+					"ready": true,
+				},
+			}}
+		})
+
+		JustBeforeEach(func() { output = forge.LocalUnstructuredStatus(local, remote, fields) })
+
+		It("should propagate the selected fields", func() {
+			Expect(output.Object).To(HaveKeyWithValue("status", HaveKeyWithValue("ready", true)))
+		})
+
+		It("should remove the selected fields not present in the remote object", func() {
+			Expect(output.Object).To(HaveKeyWithValue("status", Not(HaveKey("conditions"))))
+		})
+
+		It("should preserve the other fields", func() {
+			Expect(output.Object).To(HaveKeyWithValue("status", HaveKeyWithValue("phase", "Pending")))
+			Expect(output.Object).To(HaveKeyWithValue("spec", local.Object["spec"]))
+		})
+
+		It("should not mutate the original object", func() {
+			Expect(local.Object).To(HaveKeyWithValue("status", HaveKeyWithValue("conditions", []interface{}{"local"})))
+		})
+	})
+})
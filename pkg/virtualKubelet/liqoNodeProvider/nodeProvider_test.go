@@ -350,4 +350,37 @@ var _ = Describe("NodeProvider", func() {
 		Expect(ok).To(BeFalse())
 	})
 
+	It("Taints patch", func() {
+		client := kubernetes.NewForConfigOrDie(cluster.GetCfg())
+		getNode := func() *v1.Node {
+			node, err := client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			return node
+		}
+		initial := getNode().Spec.Taints
+
+		By("Add taints")
+		gpuTaint := v1.Taint{Key: "nvidia.com/gpu", Value: "true", Effect: v1.TaintEffectNoSchedule}
+		Expect(nodeProvider.patchTaints(ctx, []v1.Taint{gpuTaint})).To(Succeed())
+		Expect(getNode().Spec.Taints).To(ConsistOf(append(initial, gpuTaint)))
+		Expect(getNode().Annotations).To(HaveKey(consts.ManagedTaintsAnnotation))
+
+		By("Update taints")
+		gpuTaint.Value = "false"
+		Expect(nodeProvider.patchTaints(ctx, []v1.Taint{gpuTaint})).To(Succeed())
+		Expect(getNode().Spec.Taints).To(ConsistOf(append(initial, gpuTaint)))
+
+		By("Delete taints, after a restart")
+		restarted := NewLiqoNodeProvider(&InitConfig{
+			NodeName:        nodeName,
+			Namespace:       kubeletNamespace,
+			HomeConfig:      cluster.GetCfg(),
+			RemoteConfig:    cluster.GetCfg(),
+			RemoteClusterID: foreignClusterID,
+		})
+		Expect(restarted.patchTaints(ctx, nil)).To(Succeed())
+		Expect(getNode().Spec.Taints).To(ConsistOf(initial))
+		Expect(getNode().Annotations).ToNot(HaveKey(consts.ManagedTaintsAnnotation))
+	})
+
 })
@@ -16,12 +16,16 @@ package liqonodeprovider
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"reflect"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/klog/v2"
 
@@ -102,6 +106,11 @@ func (p *LiqoNodeProvider) updateFromVirtualNode(ctx context.Context,
 		return err
 	}
 
+	if err := p.patchTaints(ctx, virtualNode.Spec.Taints); err != nil {
+		klog.Error(err)
+		return err
+	}
+
 	if p.node.Status.Capacity == nil {
 		p.node.Status.Capacity = v1.ResourceList{}
 	}
@@ -186,3 +195,63 @@ func (p *LiqoNodeProvider) patchLabels(ctx context.Context, labels map[string]st
 	p.lastAppliedLabels = labels
 	return nil
 }
+
+// patchTaints replaces the taints previously applied to the node with the given ones,
+// while preserving the others (e.g., the default virtual node taint). The applied taints are
+// recorded in an annotation of the node itself, to correctly remove them also after a restart.
+func (p *LiqoNodeProvider) patchTaints(ctx context.Context, taints []v1.Taint) error {
+	node, err := p.localClient.CoreV1().Nodes().Get(ctx, p.node.Name, metav1.GetOptions{})
+	if err != nil {
+		klog.Errorf("Failed to retrieve node %q: %v", p.node.Name, err)
+		return err
+	}
+
+	var managed []v1.Taint
+	if value, found := node.GetAnnotations()[consts.ManagedTaintsAnnotation]; found {
+		if err := json.Unmarshal([]byte(value), &managed); err != nil {
+			// Proceed anyway, as the worst case is that some stale taint is not removed.
+			klog.Warningf("Failed to parse the taints managed on node %q: %v", p.node.Name, err)
+		}
+	}
+
+	nodeTaints := make([]v1.Taint, 0, len(node.Spec.Taints)+len(taints))
+	for i := range node.Spec.Taints {
+		if !containsTaint(managed, &node.Spec.Taints[i]) && !containsTaint(taints, &node.Spec.Taints[i]) {
+			nodeTaints = append(nodeTaints, node.Spec.Taints[i])
+		}
+	}
+	nodeTaints = append(nodeTaints, taints...)
+
+	// The annotation is removed altogether (i.e., set to null in the patch) when no taint is managed.
+	var annotation interface{}
+	if len(taints) > 0 {
+		encoded, err := json.Marshal(taints)
+		if err != nil {
+			return err
+		}
+		annotation = string(encoded)
+	}
+
+	if current, found := node.GetAnnotations()[consts.ManagedTaintsAnnotation]; equality.Semantic.DeepEqual(nodeTaints, node.Spec.Taints) &&
+		((!found && annotation == nil) || (found && annotation == current)) {
+		return nil
+	}
+
+	// The whole list of taints is replaced, hence the resource version guarantees it is computed from the latest one.
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"resourceVersion": node.ResourceVersion,
+			"annotations":     map[string]interface{}{consts.ManagedTaintsAnnotation: annotation},
+		},
+		"spec": map[string]interface{}{"taints": nodeTaints},
+	})
+	if err != nil {
+		return err
+	}
+
+	if p.node, err = p.localClient.CoreV1().Nodes().Patch(ctx, p.node.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		klog.Errorf("Failed to patch the taints of node %q: %v", p.node.Name, err)
+		return err
+	}
+	return nil
+}
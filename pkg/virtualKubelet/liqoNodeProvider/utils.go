@@ -73,3 +73,13 @@ func (p *LiqoNodeProvider) patchNode(ctx context.Context, changeFunc func(node *
 
 	return nil
 }
+
+// containsTaint returns whether the given list contains a taint matching the given one (i.e., with the same key and effect).
+func containsTaint(taints []v1.Taint, taint *v1.Taint) bool {
+	for i := range taints {
+		if taints[i].MatchTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
@@ -23,7 +23,9 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
@@ -37,6 +39,7 @@ import (
 	"github.com/liqotech/liqo/pkg/liqonet/ipam"
 	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
 	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/configuration"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/custom"
 	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/event"
 	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/exposition"
 	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/generic"
@@ -64,7 +67,8 @@ type InitConfig struct {
 	DisableIPReflection  bool
 	InformerResyncPeriod time.Duration
 
-	ReflectorsConfigs map[generic.ResourceReflected]*generic.ReflectorConfig
+	ReflectorsConfigs      map[generic.ResourceReflected]*generic.ReflectorConfig
+	CustomResourcesConfigs []custom.ResourceConfig
 
 	EnableAPIServerSupport     bool
 	EnableStorage              bool
@@ -90,9 +94,11 @@ func NewLiqoProvider(ctx context.Context, cfg *InitConfig, eb record.EventBroadc
 	forge.Init(cfg.LocalCluster, cfg.RemoteCluster, cfg.NodeName, cfg.NodeIP)
 	localClient := kubernetes.NewForConfigOrDie(cfg.LocalConfig)
 	localLiqoClient := liqoclient.NewForConfigOrDie(cfg.LocalConfig)
+	localDynamicClient := dynamic.NewForConfigOrDie(cfg.LocalConfig)
 
 	remoteClient := kubernetes.NewForConfigOrDie(cfg.RemoteConfig)
 	remoteLiqoClient := liqoclient.NewForConfigOrDie(cfg.RemoteConfig)
+	remoteDynamicClient := dynamic.NewForConfigOrDie(cfg.RemoteConfig)
 	remoteMetricsClient := metrics.NewForConfigOrDie(cfg.RemoteConfig).MetricsV1beta1().PodMetricses
 
 	var ipamClient ipam.IpamClient
@@ -129,7 +135,7 @@ func NewLiqoProvider(ctx context.Context, cfg *InitConfig, eb record.EventBroadc
 	}
 
 	podreflector := workload.NewPodReflector(cfg.RemoteConfig, remoteMetricsClient, ipamClient, &podReflectorConfig, cfg.ReflectorsConfigs[generic.Pod])
-	reflectionManager := manager.New(localClient, remoteClient, localLiqoClient, remoteLiqoClient,
+	reflectionManager := manager.New(localClient, remoteClient, localLiqoClient, remoteLiqoClient, localDynamicClient, remoteDynamicClient,
 		cfg.InformerResyncPeriod, eb, cfg.LabelsNotReflected, cfg.AnnotationsNotReflected).
 		With(podreflector).
 		With(exposition.NewServiceReflector(cfg.ReflectorsConfigs[generic.Service])).
@@ -140,12 +146,23 @@ func NewLiqoProvider(ctx context.Context, cfg *InitConfig, eb record.EventBroadc
 		With(storage.NewPersistentVolumeClaimReflector(cfg.VirtualStorageClassName, cfg.RemoteRealStorageClassName,
 			cfg.EnableStorage, cfg.ReflectorsConfigs[generic.PersistentVolumeClaim])).
 		With(event.NewEventReflector(cfg.ReflectorsConfigs[generic.Event])).
+		With(workload.NewStatefulSetReflector(cfg.ReflectorsConfigs[generic.StatefulSet])).
+		With(exposition.NewNetworkPolicyReflector(ipamClient, cfg.ReflectorsConfigs[generic.NetworkPolicy])).
 		WithNamespaceHandler(namespacemap.NewHandler(localLiqoClient, cfg.Namespace, cfg.InformerResyncPeriod))
 
 	if !cfg.DisableIPReflection {
 		reflectionManager.With(exposition.NewEndpointSliceReflector(ipamClient, cfg.ReflectorsConfigs[generic.EndpointSlice]))
 	}
 
+	for i := range cfg.CustomResourcesConfigs {
+		// Skip the resources not available in either cluster, as the corresponding informers would never sync.
+		if err := ensureResourceAvailability(cfg.CustomResourcesConfigs[i].Resource, localClient, remoteClient); err != nil {
+			klog.Errorf("Skipping reflection of custom resource %v: %v", cfg.CustomResourcesConfigs[i].Resource, err)
+			continue
+		}
+		reflectionManager.With(custom.NewCustomResourceReflector(&cfg.CustomResourcesConfigs[i]))
+	}
+
 	reflectionManager.Start(ctx)
 
 	return &LiqoProvider{
@@ -174,6 +191,30 @@ func isSATokenAPISupport(localClient kubernetes.Interface) (bool, error) {
 	return false, nil
 }
 
+// ensureResourceAvailability checks whether the given resource is served by both the local and the remote cluster.
+func ensureResourceAvailability(gvr schema.GroupVersionResource, clients ...kubernetes.Interface) error {
+	for _, client := range clients {
+		resources, err := client.Discovery().ServerResourcesForGroupVersion(gvr.GroupVersion().String())
+		if err != nil {
+			return fmt.Errorf("failed to retrieve the resources of group version %q: %w", gvr.GroupVersion(), err)
+		}
+
+		found := false
+		for i := range resources.APIResources {
+			if resources.APIResources[i].Name == gvr.Resource && resources.APIResources[i].Namespaced {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("namespaced resource %q not found", gvr.GroupResource())
+		}
+	}
+
+	return nil
+}
+
 // Resync force the resync of all informers contained in the reflection manager.
 func (p *LiqoProvider) Resync() error {
 	return p.reflectionManager.Resync()
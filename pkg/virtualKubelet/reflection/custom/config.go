@@ -0,0 +1,178 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package custom
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+const (
+	// DefaultWorkers is the default number of workers for each custom resource reflector.
+	DefaultWorkers = 3
+	// DefaultType is the default type of reflection for each custom resource reflector.
+	DefaultType = consts.DenyList
+	// DefaultSpecField is the path of the field propagated to the remote cluster, if not otherwise specified.
+	DefaultSpecField = "spec"
+
+	sectionSeparator = ";"
+	fieldSeparator   = ","
+
+	sectionSpec    = "spec"
+	sectionStatus  = "status"
+	sectionType    = "type"
+	sectionWorkers = "workers"
+)
+
+var versionRegex = regexp.MustCompile(`^v[1-9][0-9]*((alpha|beta)[1-9][0-9]*)?$`)
+
+// ResourceConfig contains the configuration parameters concerning the reflection of a given custom resource.
+type ResourceConfig struct {
+	// Resource identifies the reflected custom resource.
+	Resource schema.GroupVersionResource
+	// SpecFields are the paths (dot-separated, relative to the object root) of the fields propagated to the remote cluster.
+	SpecFields []string
+	// StatusFields are the paths (dot-separated, relative to the object root) of the fields propagated back from the remote cluster.
+	StatusFields []string
+	// Type is the type of reflection.
+	Type consts.ReflectionType
+	// NumWorkers is the number of workers for the reflector.
+	NumWorkers uint
+}
+
+// ParseResourceConfig parses a ResourceConfig from its string representation, in the form:
+// "<resource>.<version>.<group>[;spec=<path>,...][;status=<path>,...][;type=<type>][;workers=<workers>]".
+func ParseResourceConfig(str string) (*ResourceConfig, error) {
+	sections := strings.Split(str, sectionSeparator)
+
+	gvr, _ := schema.ParseResourceArg(strings.TrimSpace(sections[0]))
+	if gvr == nil || gvr.Group == "" || !versionRegex.MatchString(gvr.Version) {
+		return nil, fmt.Errorf("invalid resource %q, expected format <resource>.<version>.<group>", sections[0])
+	}
+
+	config := &ResourceConfig{Resource: *gvr, SpecFields: []string{DefaultSpecField}, Type: DefaultType, NumWorkers: DefaultWorkers}
+	for _, section := range sections[1:] {
+		key, value, found := strings.Cut(strings.TrimSpace(section), "=")
+		if !found {
+			return nil, fmt.Errorf("invalid section %q for resource %q, expected format <key>=<value>", section, gvr)
+		}
+
+		var err error
+		switch key {
+		case sectionSpec:
+			config.SpecFields, err = parseFields(value, func(root string) bool {
+				return root != "apiVersion" && root != "kind" && root != "metadata" && root != sectionStatus
+			})
+		case sectionStatus:
+			config.StatusFields, err = parseFields(value, func(root string) bool { return root == sectionStatus })
+		case sectionType:
+			config.Type = consts.ReflectionType(value)
+			if config.Type != consts.DenyList && config.Type != consts.AllowList {
+				err = fmt.Errorf("reflection type %q is not valid. Ammitted values: %q, %q", value, consts.DenyList, consts.AllowList)
+			}
+		case sectionWorkers:
+			var workers uint64
+			workers, err = strconv.ParseUint(value, 10, 32)
+			config.NumWorkers = uint(workers)
+		default:
+			err = fmt.Errorf("unknown section %q", key)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid configuration for resource %q: %w", gvr, err)
+		}
+	}
+
+	return config, nil
+}
+
+// parseFields parses a list of comma-separated field paths, ensuring each one is accepted by the given root validator.
+func parseFields(value string, valid func(root string) bool) ([]string, error) {
+	fields := strings.Split(value, fieldSeparator)
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+		root, _, _ := strings.Cut(fields[i], ".")
+		if root == "" || strings.Contains(fields[i], "..") || strings.HasSuffix(fields[i], ".") || !valid(root) {
+			return nil, fmt.Errorf("field path %q is not valid", fields[i])
+		}
+	}
+	return fields, nil
+}
+
+// String returns the string representation of the ResourceConfig, which can be parsed through ParseResourceConfig.
+func (rc *ResourceConfig) String() string {
+	var builder strings.Builder
+	builder.WriteString(rc.Name())
+	builder.WriteString(fmt.Sprintf("%s%s=%s", sectionSeparator, sectionSpec, strings.Join(rc.SpecFields, fieldSeparator)))
+	if len(rc.StatusFields) > 0 {
+		builder.WriteString(fmt.Sprintf("%s%s=%s", sectionSeparator, sectionStatus, strings.Join(rc.StatusFields, fieldSeparator)))
+	}
+	builder.WriteString(fmt.Sprintf("%s%s=%s", sectionSeparator, sectionType, rc.Type))
+	builder.WriteString(fmt.Sprintf("%s%s=%d", sectionSeparator, sectionWorkers, rc.NumWorkers))
+	return builder.String()
+}
+
+// Name returns the fully qualified name of the reflected resource, in the form <resource>.<version>.<group>.
+func (rc *ResourceConfig) Name() string {
+	return fmt.Sprintf("%s.%s.%s", rc.Resource.Resource, rc.Resource.Version, rc.Resource.Group)
+}
+
+// ResourceConfigList implements the flag.Value interface and allows to parse a list of ResourceConfigs,
+// specifying the flag once for each custom resource to be reflected.
+type ResourceConfigList struct {
+	Configs []ResourceConfig
+}
+
+// String returns the stringified list.
+func (rcl ResourceConfigList) String() string {
+	return strings.Join(rcl.Strings(), " ")
+}
+
+// Set parses the provided string as a ResourceConfig, and appends it to the list.
+func (rcl *ResourceConfigList) Set(str string) error {
+	config, err := ParseResourceConfig(str)
+	if err != nil {
+		return err
+	}
+
+	for i := range rcl.Configs {
+		if rcl.Configs[i].Resource.GroupResource() == config.Resource.GroupResource() {
+			return fmt.Errorf("resource %q configured multiple times", config.Resource.GroupResource())
+		}
+	}
+
+	rcl.Configs = append(rcl.Configs, *config)
+	return nil
+}
+
+// Type returns the resourceConfigList type.
+func (rcl ResourceConfigList) Type() string {
+	return "resourceConfigList"
+}
+
+// Strings returns the string representation of each ResourceConfig in the list.
+func (rcl ResourceConfigList) Strings() []string {
+	configs := make([]string, len(rcl.Configs))
+	for i := range rcl.Configs {
+		configs[i] = rcl.Configs[i].String()
+	}
+	return configs
+}
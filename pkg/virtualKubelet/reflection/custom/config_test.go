@@ -0,0 +1,107 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package custom_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/custom"
+)
+
+var _ = Describe("Custom resources reflection configuration", func() {
+	gvr := schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+
+	Describe("the ParseResourceConfig function", func() {
+		DescribeTable("parsing valid configurations",
+			func(input string, expected custom.ResourceConfig) {
+				config, err := custom.ParseResourceConfig(input)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(*config).To(Equal(expected))
+			},
+			Entry("with the resource only", "certificates.v1.cert-manager.io", custom.ResourceConfig{
+				Resource: gvr, SpecFields: []string{"spec"}, Type: consts.DenyList, NumWorkers: custom.DefaultWorkers,
+			}),
+			Entry("with the spec fields", "certificates.v1.cert-manager.io;spec=spec.dnsNames,spec.secretName", custom.ResourceConfig{
+				Resource: gvr, SpecFields: []string{"spec.dnsNames", "spec.secretName"}, Type: consts.DenyList, NumWorkers: custom.DefaultWorkers,
+			}),
+			Entry("with the status fields", "certificates.v1.cert-manager.io;status=status.conditions,status.notAfter", custom.ResourceConfig{
+				Resource: gvr, SpecFields: []string{"spec"}, StatusFields: []string{"status.conditions", "status.notAfter"},
+				Type: consts.DenyList, NumWorkers: custom.DefaultWorkers,
+			}),
+			Entry("with all the sections", "certificates.v1.cert-manager.io;spec=spec;status=status;type=AllowList;workers=5", custom.ResourceConfig{
+				Resource: gvr, SpecFields: []string{"spec"}, StatusFields: []string{"status"}, Type: consts.AllowList, NumWorkers: 5,
+			}),
+		)
+
+		DescribeTable("parsing invalid configurations",
+			func(input string) {
+				_, err := custom.ParseResourceConfig(input)
+				Expect(err).To(HaveOccurred())
+			},
+			Entry("with an empty string", ""),
+			Entry("with the group missing", "certificates.v1"),
+			Entry("with the version missing", "certificates.cert-manager.io"),
+			Entry("with a malformed section", "certificates.v1.cert-manager.io;spec"),
+			Entry("with an unknown section", "certificates.v1.cert-manager.io;foo=bar"),
+			Entry("with a spec field referring to the metadata", "certificates.v1.cert-manager.io;spec=metadata.labels"),
+			Entry("with a spec field referring to the status", "certificates.v1.cert-manager.io;spec=status.conditions"),
+			Entry("with an empty spec field", "certificates.v1.cert-manager.io;spec=spec,"),
+			Entry("with a malformed spec field", "certificates.v1.cert-manager.io;spec=spec..foo"),
+			Entry("with a status field not referring to the status", "certificates.v1.cert-manager.io;status=spec.foo"),
+			Entry("with an invalid reflection type", "certificates.v1.cert-manager.io;type=CustomLiqo"),
+			Entry("with an invalid number of workers", "certificates.v1.cert-manager.io;workers=-1"),
+		)
+	})
+
+	Describe("the ResourceConfig String function", func() {
+		It("should produce a representation which can be parsed back", func() {
+			input := custom.ResourceConfig{
+				Resource: gvr, SpecFields: []string{"spec.dnsNames", "spec.secretName"}, StatusFields: []string{"status.conditions"},
+				Type: consts.AllowList, NumWorkers: 2,
+			}
+
+			config, err := custom.ParseResourceConfig(input.String())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*config).To(Equal(input))
+		})
+	})
+
+	Describe("the ResourceConfigList Set function", func() {
+		var list custom.ResourceConfigList
+
+		BeforeEach(func() { list = custom.ResourceConfigList{} })
+
+		It("should append the parsed configurations", func() {
+			Expect(list.Set("certificates.v1.cert-manager.io")).To(Succeed())
+			Expect(list.Set("issuers.v1.cert-manager.io;workers=1")).To(Succeed())
+			Expect(list.Configs).To(HaveLen(2))
+			Expect(list.Strings()).To(HaveLen(2))
+		})
+
+		It("should fail in case of invalid configurations", func() {
+			Expect(list.Set("certificates")).ToNot(Succeed())
+			Expect(list.Configs).To(BeEmpty())
+		})
+
+		It("should fail in case the same resource is configured multiple times", func() {
+			Expect(list.Set("certificates.v1.cert-manager.io")).To(Succeed())
+			Expect(list.Set("certificates.v1beta1.cert-manager.io")).ToNot(Succeed())
+			Expect(list.Configs).To(HaveLen(1))
+		})
+	})
+})
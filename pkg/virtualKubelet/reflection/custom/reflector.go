@@ -0,0 +1,246 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package custom
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/trace"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils/virtualkubelet"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/generic"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/manager"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/options"
+)
+
+// NamespacedCustomResourceReflector manages the reflection of a given custom resource.
+type NamespacedCustomResourceReflector struct {
+	generic.NamespacedReflector
+
+	config *ResourceConfig
+
+	localSynced  cache.InformerSynced
+	remoteSynced cache.InformerSynced
+
+	localResources        unstructuredLister
+	remoteResources       unstructuredLister
+	localResourcesClient  dynamic.ResourceInterface
+	remoteResourcesClient dynamic.ResourceInterface
+}
+
+// NewCustomResourceReflector builds a reflector for the custom resource identified by the given configuration.
+func NewCustomResourceReflector(config *ResourceConfig) manager.Reflector {
+	return generic.NewReflector(config.Resource.GroupResource().String(), NewNamespacedCustomResourceReflector(config),
+		generic.WithoutFallback(), config.NumWorkers, config.Type, generic.ConcurrencyModeLeader)
+}
+
+// NewNamespacedCustomResourceReflector returns a function generating NamespacedCustomResourceReflector instances.
+func NewNamespacedCustomResourceReflector(config *ResourceConfig) func(*options.NamespacedOpts) manager.NamespacedReflector {
+	return func(opts *options.NamespacedOpts) manager.NamespacedReflector {
+		local := opts.LocalDynamicFactory.ForResource(config.Resource)
+		remote := opts.RemoteDynamicFactory.ForResource(config.Resource)
+
+		// Using opts.LocalNamespace for both event handlers so that the object will be put in the same workqueue
+		// no matter the cluster, hence it will be processed by the handle function in the same way.
+		_, err := local.Informer().AddEventHandler(opts.HandlerFactory(generic.NamespacedKeyer(opts.LocalNamespace)))
+		utilruntime.Must(err)
+		_, err = remote.Informer().AddEventHandler(opts.HandlerFactory(generic.NamespacedKeyer(opts.LocalNamespace)))
+		utilruntime.Must(err)
+
+		return &NamespacedCustomResourceReflector{
+			NamespacedReflector:   generic.NewNamespacedReflector(opts, config.Resource.GroupResource().String()),
+			config:                config,
+			localSynced:           local.Informer().HasSynced,
+			remoteSynced:          remote.Informer().HasSynced,
+			localResources:        unstructuredLister{local.Lister().ByNamespace(opts.LocalNamespace)},
+			remoteResources:       unstructuredLister{remote.Lister().ByNamespace(opts.RemoteNamespace)},
+			localResourcesClient:  opts.LocalDynamicClient.Resource(config.Resource).Namespace(opts.LocalNamespace),
+			remoteResourcesClient: opts.RemoteDynamicClient.Resource(config.Resource).Namespace(opts.RemoteNamespace),
+		}
+	}
+}
+
+// Ready returns whether the NamespacedCustomResourceReflector is completely initialized. Since the namespace readiness
+// gate does not wait for the custom resource informers (which might never sync if the CRD is missing, hence blocking
+// the other reflectors), the reflector additionally requires its own local and remote informers to have synced.
+func (ncr *NamespacedCustomResourceReflector) Ready() bool {
+	return ncr.NamespacedReflector.Ready() && ncr.localSynced() && ncr.remoteSynced()
+}
+
+// Handle is responsible for reconciling the given object and ensuring it is correctly reflected.
+func (ncr *NamespacedCustomResourceReflector) Handle(ctx context.Context, name string) error {
+	tracer := trace.FromContext(ctx)
+	resource := ncr.config.Resource.GroupResource()
+
+	// Retrieve the local and remote objects (only not found errors can occur).
+	klog.V(4).Infof("Handling reflection of local %v %q (remote: %q)", resource, ncr.LocalRef(name), ncr.RemoteRef(name))
+
+	local, lerr := ncr.localResources.Get(name)
+	utilruntime.Must(client.IgnoreNotFound(lerr))
+	remote, rerr := ncr.remoteResources.Get(name)
+	utilruntime.Must(client.IgnoreNotFound(rerr))
+	tracer.Step("Retrieved the local and remote objects")
+
+	// Abort the reflection if the remote object is not managed by us, as we do not want to mutate others' objects.
+	if rerr == nil && !forge.IsReflected(remote) {
+		if lerr == nil { // Do not output the warning event in case the event was triggered by the remote object (i.e., the local one does not exists).
+			klog.Infof("Skipping reflection of local %v %q as remote already exists and is not managed by us", resource, ncr.LocalRef(name))
+			ncr.Event(local, corev1.EventTypeWarning, forge.EventFailedReflection, forge.EventFailedReflectionAlreadyExistsMsg())
+		}
+		return nil
+	}
+
+	// Abort the reflection if the local object has the "skip-reflection" annotation.
+	if !kerrors.IsNotFound(lerr) {
+		skipReflection, err := ncr.ShouldSkipReflection(local)
+		if err != nil {
+			klog.Errorf("Failed to check whether local %v %q should be reflected: %v", resource, ncr.LocalRef(name), err)
+			return err
+		}
+		if skipReflection {
+			if ncr.GetReflectionType() == consts.DenyList {
+				klog.Infof("Skipping reflection of local %v %q as marked with the skip annotation", resource, ncr.LocalRef(name))
+			} else { // AllowList
+				klog.Infof("Skipping reflection of local %v %q as not marked with the allow annotation", resource, ncr.LocalRef(name))
+			}
+			ncr.Event(local, corev1.EventTypeNormal, forge.EventReflectionDisabled, forge.EventObjectReflectionDisabledMsg(ncr.GetReflectionType()))
+			if kerrors.IsNotFound(rerr) { // The remote object does not already exist, hence no further action is required.
+				return nil
+			}
+
+			// Otherwise, let pretend the local object does not exist, so that the remote one gets deleted.
+			lerr = kerrors.NewNotFound(resource, local.GetName())
+		}
+	}
+
+	tracer.Step("Performed the sanity checks")
+
+	if kerrors.IsNotFound(lerr) {
+		defer tracer.Step("Ensured the absence of the remote object")
+		if !kerrors.IsNotFound(rerr) {
+			klog.V(4).Infof("Deleting remote %v %q, since local %q does no longer exist", resource, ncr.RemoteRef(name), ncr.LocalRef(name))
+			return ncr.DeleteRemote(ctx, resourceDeleter{ncr.remoteResourcesClient}, resource.String(), name, remote.GetUID())
+		}
+
+		klog.V(4).Infof("Local %v %q and remote %v %q both vanished", resource, ncr.LocalRef(name), resource, ncr.RemoteRef(name))
+		return nil
+	}
+
+	// Forge the mutation to be applied to the remote cluster.
+	mutation := forge.RemoteUnstructured(local, ncr.RemoteNamespace(), ncr.config.SpecFields, ncr.ForgingOpts)
+	tracer.Step("Remote mutation created")
+
+	remote, err := ncr.remoteResourcesClient.Apply(ctx, name, mutation, forge.ApplyOptions())
+	if err != nil {
+		klog.Errorf("Failed to enforce remote %v %q (local: %q): %v", resource, ncr.RemoteRef(name), ncr.LocalRef(name), err)
+		ncr.Event(local, corev1.EventTypeWarning, forge.EventFailedReflection, forge.EventFailedReflectionMsg(err))
+		return err
+	}
+
+	klog.Infof("Remote %v %q successfully enforced (local: %q)", resource, ncr.RemoteRef(name), ncr.LocalRef(name))
+	ncr.Event(local, corev1.EventTypeNormal, forge.EventSuccessfulReflection, forge.EventSuccessfulReflectionMsg())
+	tracer.Step("Enforced the correctness of the remote object")
+
+	defer tracer.Step("Enforced the correctness of the local status")
+	return ncr.EnforceLocalStatus(ctx, local, remote)
+}
+
+// EnforceLocalStatus propagates the configured status fields of the remote object back to the local one.
+func (ncr *NamespacedCustomResourceReflector) EnforceLocalStatus(ctx context.Context, local, remote *unstructured.Unstructured) error {
+	if len(ncr.config.StatusFields) == 0 {
+		return nil
+	}
+
+	resource := ncr.config.Resource.GroupResource()
+	updated := forge.LocalUnstructuredStatus(local, remote, ncr.config.StatusFields)
+	if equality.Semantic.DeepEqual(local.Object, updated.Object) {
+		klog.V(4).Infof("Skipping status update of local %v %q, as already synced", resource, ncr.LocalRef(local.GetName()))
+		return nil
+	}
+
+	if _, err := ncr.localResourcesClient.UpdateStatus(ctx, updated, metav1.UpdateOptions{FieldManager: forge.ReflectionFieldManager}); err != nil {
+		klog.Errorf("Failed to update the status of local %v %q (remote: %q): %v",
+			resource, ncr.LocalRef(local.GetName()), ncr.RemoteRef(local.GetName()), err)
+		if !kerrors.IsConflict(err) {
+			ncr.Event(local, corev1.EventTypeWarning, forge.EventFailedReflection, forge.EventFailedStatusReflectionMsg(err))
+		}
+		return err
+	}
+
+	klog.Infof("Status of local %v %q successfully updated (remote: %q)", resource, ncr.LocalRef(local.GetName()), ncr.RemoteRef(local.GetName()))
+	ncr.Event(local, corev1.EventTypeNormal, forge.EventSuccessfulReflection, forge.EventSuccessfulStatusReflectionMsg())
+	return nil
+}
+
+// List returns the list of objects.
+func (ncr *NamespacedCustomResourceReflector) List() ([]interface{}, error) {
+	return virtualkubelet.List[virtualkubelet.Lister[*unstructured.Unstructured], *unstructured.Unstructured](
+		ncr.localResources,
+		ncr.remoteResources,
+	)
+}
+
+// unstructuredLister wraps a GenericNamespaceLister, to return unstructured objects.
+type unstructuredLister struct {
+	lister cache.GenericNamespaceLister
+}
+
+// List lists all objects matching the given selector.
+func (ul unstructuredLister) List(selector labels.Selector) ([]*unstructured.Unstructured, error) {
+	objects, err := ul.lister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*unstructured.Unstructured, 0, len(objects))
+	for _, object := range objects {
+		if obj, ok := object.(*unstructured.Unstructured); ok {
+			list = append(list, obj)
+		}
+	}
+	return list, nil
+}
+
+// Get retrieves the object with the given name.
+func (ul unstructuredLister) Get(name string) (*unstructured.Unstructured, error) {
+	object, err := ul.lister.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return object.(*unstructured.Unstructured), nil
+}
+
+// resourceDeleter wraps a dynamic ResourceInterface, to implement the generic.ResourceDeleter interface.
+type resourceDeleter struct {
+	client dynamic.ResourceInterface
+}
+
+// Delete deletes the object with the given name.
+func (rd resourceDeleter) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return rd.client.Delete(ctx, name, opts)
+}
@@ -0,0 +1,76 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package custom_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/custom"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/manager"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/options"
+)
+
+var _ = Describe("Custom resources reflection", func() {
+	Describe("the readiness of the NamespacedCustomResourceReflector", func() {
+		const (
+			LocalNamespace  = "local-namespace"
+			RemoteNamespace = "remote-namespace"
+		)
+
+		var (
+			ctx       context.Context
+			cancel    context.CancelFunc
+			factory   dynamicinformer.DynamicSharedInformerFactory
+			reflector manager.NamespacedReflector
+		)
+
+		BeforeEach(func() {
+			ctx, cancel = context.WithCancel(context.Background())
+			DeferCleanup(cancel)
+
+			gvr := schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+			client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{gvr: "CertificateList"})
+			factory = dynamicinformer.NewDynamicSharedInformerFactory(client, 10*time.Hour)
+
+			config := custom.ResourceConfig{Resource: gvr, SpecFields: []string{"spec"}, Type: consts.DenyList, NumWorkers: 1}
+			reflector = custom.NewNamespacedCustomResourceReflector(&config)(options.NewNamespaced().
+				WithLocal(LocalNamespace, nil, nil).WithDynamicLocal(client, factory).
+				WithRemote(RemoteNamespace, nil, nil).WithDynamicRemote(client, factory).
+				WithHandlerFactory(func(options.Keyer, ...options.EventFilter) cache.ResourceEventHandler {
+					return cache.ResourceEventHandlerFuncs{}
+				}).WithReadinessFunc(func() bool { return true }).WithEventBroadcaster(record.NewBroadcaster()))
+		})
+
+		It("should not be ready until the custom resource informers have synced", func() {
+			Expect(reflector.Ready()).To(BeFalse())
+
+			factory.Start(ctx.Done())
+			factory.WaitForCacheSync(ctx.Done())
+			Expect(reflector.Ready()).To(BeTrue())
+		})
+	})
+})
@@ -19,7 +19,9 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	netv1clients "k8s.io/client-go/kubernetes/typed/networking/v1"
 	netv1listers "k8s.io/client-go/listers/networking/v1"
@@ -48,6 +50,7 @@ type NamespacedIngressReflector struct {
 
 	localIngresses        netv1listers.IngressNamespaceLister
 	remoteIngresses       netv1listers.IngressNamespaceLister
+	localIngressesClient  netv1clients.IngressInterface
 	remoteIngressesClient netv1clients.IngressInterface
 }
 
@@ -71,6 +74,7 @@ func NewNamespacedIngressReflector(opts *options.NamespacedOpts) manager.Namespa
 		NamespacedReflector:   generic.NewNamespacedReflector(opts, IngressReflectorName),
 		localIngresses:        local.Lister().Ingresses(opts.LocalNamespace),
 		remoteIngresses:       remote.Lister().Ingresses(opts.RemoteNamespace),
+		localIngressesClient:  opts.LocalClient.NetworkingV1().Ingresses(opts.LocalNamespace),
 		remoteIngressesClient: opts.RemoteClient.NetworkingV1().Ingresses(opts.RemoteNamespace),
 	}
 }
@@ -137,8 +141,8 @@ func (nir *NamespacedIngressReflector) Handle(ctx context.Context, name string)
 	mutation := forge.RemoteIngress(local, nir.RemoteNamespace(), nir.ForgingOpts)
 	tracer.Step("Remote mutation created")
 
-	defer tracer.Step("Enforced the correctness of the remote object")
-	if _, err := nir.remoteIngressesClient.Apply(ctx, mutation, forge.ApplyOptions()); err != nil {
+	remote, err := nir.remoteIngressesClient.Apply(ctx, mutation, forge.ApplyOptions())
+	if err != nil {
 		klog.Errorf("Failed to enforce remote Ingress %q (local: %q): %v", nir.RemoteRef(name), nir.LocalRef(name), err)
 		nir.Event(local, corev1.EventTypeWarning, forge.EventFailedReflection, forge.EventFailedReflectionMsg(err))
 		return err
@@ -146,7 +150,49 @@ func (nir *NamespacedIngressReflector) Handle(ctx context.Context, name string)
 
 	klog.Infof("Remote Ingress %q successfully enforced (local: %q)", nir.RemoteRef(name), nir.LocalRef(name))
 	nir.Event(local, corev1.EventTypeNormal, forge.EventSuccessfulReflection, forge.EventSuccessfulReflectionMsg())
+	tracer.Step("Enforced the correctness of the remote object")
 
+	defer tracer.Step("Enforced the correctness of the local status")
+	return nir.EnforceLocalStatus(ctx, local, remote)
+}
+
+// EnforceLocalStatus propagates the load balancer status of the remote ingress back to the local one,
+// in case the status reflection has been requested. The status previously propagated is cleared once
+// the reflection is no longer requested.
+func (nir *NamespacedIngressReflector) EnforceLocalStatus(ctx context.Context, local, remote *netv1.Ingress) error {
+	var desired *netv1.IngressLoadBalancerStatus
+	switch {
+	case forge.ShouldReflectStatus(local):
+		// Do not fight with other controllers (e.g., a local ingress controller) assigning the addresses to the ingress.
+		if forge.IsStatusManagedByOthers(local) {
+			klog.Warningf("Skipping status reflection of local Ingress %q, as already managed by a different controller", nir.LocalRef(local.GetName()))
+			nir.Event(local, corev1.EventTypeWarning, forge.EventFailedReflection, forge.EventFailedStatusReflectionManagedByOthersMsg())
+			return nil
+		}
+		desired = &remote.Status.LoadBalancer
+	case forge.IsStatusReflected(local) && forge.ShouldClearStatus(local):
+		desired = &netv1.IngressLoadBalancerStatus{}
+	default:
+		return nil
+	}
+
+	if equality.Semantic.DeepEqual(local.Status.LoadBalancer, *desired) {
+		klog.V(4).Infof("Skipping status update of local Ingress %q, as already synced", nir.LocalRef(local.GetName()))
+		return nil
+	}
+
+	updated := local.DeepCopy()
+	updated.Status.LoadBalancer = *desired.DeepCopy()
+	if _, err := nir.localIngressesClient.UpdateStatus(ctx, updated, metav1.UpdateOptions{FieldManager: forge.ReflectionFieldManager}); err != nil {
+		klog.Errorf("Failed to update the status of local Ingress %q (remote: %q): %v", nir.LocalRef(local.GetName()), nir.RemoteRef(local.GetName()), err)
+		if !kerrors.IsConflict(err) {
+			nir.Event(local, corev1.EventTypeWarning, forge.EventFailedReflection, forge.EventFailedStatusReflectionMsg(err))
+		}
+		return err
+	}
+
+	klog.Infof("Status of local Ingress %q successfully updated (remote: %q)", nir.LocalRef(local.GetName()), nir.RemoteRef(local.GetName()))
+	nir.Event(local, corev1.EventTypeNormal, forge.EventSuccessfulReflection, forge.EventSuccessfulStatusReflectionMsg())
 	return nil
 }
 
@@ -204,6 +204,44 @@ var _ = Describe("Ingress Reflection Tests", func() {
 			})
 		})
 
+		When("the local object does exist, and has the reflect status annotation", func() {
+			BeforeEach(func() {
+				local.SetAnnotations(map[string]string{consts.ReflectStatusAnnotationKey: RemoteClusterID})
+				ForgeIngressSpec(&local)
+				CreateIngress(&local)
+
+				remote.SetLabels(forge.ReflectionLabels())
+				ForgeIngressSpec(&remote)
+				created := CreateIngress(&remote)
+				created.Status.LoadBalancer.Ingress = []netv1.IngressLoadBalancerIngress{{IP: "1.1.1.1"}}
+				_, err = client.NetworkingV1().Ingresses(RemoteNamespace).UpdateStatus(ctx, created, metav1.UpdateOptions{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+			It("the load balancer status should have been propagated to the local object", func() {
+				localAfter := GetIngress(LocalNamespace)
+				Expect(localAfter.Status.LoadBalancer.Ingress).To(ConsistOf(netv1.IngressLoadBalancerIngress{IP: "1.1.1.1"}))
+			})
+		})
+
+		When("the local object does exist, and the reflect status annotation has been removed", func() {
+			BeforeEach(func() {
+				ForgeIngressSpec(&local)
+				created := CreateIngress(&local)
+				created.Status.LoadBalancer.Ingress = []netv1.IngressLoadBalancerIngress{{IP: "1.1.1.1"}}
+				_, err = client.NetworkingV1().Ingresses(LocalNamespace).UpdateStatus(ctx, created,
+					metav1.UpdateOptions{FieldManager: forge.ReflectionFieldManager})
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+			It("the load balancer status previously reflected should have been cleared", func() {
+				localAfter := GetIngress(LocalNamespace)
+				Expect(localAfter.Status.LoadBalancer.Ingress).To(BeEmpty())
+			})
+		})
+
 		When("the local object does exist, but has the skip annotation", func() {
 			BeforeEach(func() {
 				local.SetAnnotations(map[string]string{consts.SkipReflectionAnnotationKey: "whatever"})
@@ -0,0 +1,533 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exposition
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	netv1clients "k8s.io/client-go/kubernetes/typed/networking/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	netv1listers "k8s.io/client-go/listers/networking/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/trace"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqonet/ipam"
+	"github.com/liqotech/liqo/pkg/utils/virtualkubelet"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/generic"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/manager"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/options"
+)
+
+var _ manager.NamespacedReflector = (*NamespacedNetworkPolicyReflector)(nil)
+var _ manager.FallbackReflector = (*FallbackNetworkPolicyReflector)(nil)
+
+const (
+	// NetworkPolicyReflectorName -> The name associated with the NetworkPolicy reflector.
+	NetworkPolicyReflectorName = "NetworkPolicy"
+)
+
+// NetworkPolicyReflector manages the NetworkPolicy reflection.
+type NetworkPolicyReflector struct {
+	manager.Reflector
+
+	localPods            corev1listers.PodLister
+	localNamespaces      corev1listers.NamespaceLister
+	localNetworkPolicies netv1listers.NetworkPolicyLister
+
+	ipamclient ipam.IpamClient
+}
+
+// NamespacedNetworkPolicyReflector manages the NetworkPolicy reflection for a given pair of local and remote namespaces.
+// The peers of the local policies referring to the home cluster are translated into the address space used
+// by the remote cluster to reach it, so that the offloaded pods are subject to the same restrictions.
+type NamespacedNetworkPolicyReflector struct {
+	generic.NamespacedReflector
+
+	localPods                   corev1listers.PodLister
+	localNamespaces             corev1listers.NamespaceLister
+	localNetworkPolicies        netv1listers.NetworkPolicyNamespaceLister
+	remoteNetworkPolicies       netv1listers.NetworkPolicyNamespaceLister
+	remoteNetworkPoliciesClient netv1clients.NetworkPolicyInterface
+
+	ipamclient ipam.IpamClient
+}
+
+// FallbackNetworkPolicyReflector handles the events of the local pods and namespaces, re-enqueueing the NetworkPolicies
+// whose peers might select them. The NetworkPolicies living outside the managed namespaces are not reflected.
+type FallbackNetworkPolicyReflector struct {
+	ready func() bool
+}
+
+// NewNetworkPolicyReflector returns a new NetworkPolicyReflector instance.
+func NewNetworkPolicyReflector(ipamclient ipam.IpamClient, reflectorConfig *generic.ReflectorConfig) manager.Reflector {
+	reflector := &NetworkPolicyReflector{ipamclient: ipamclient}
+	reflector.Reflector = generic.NewReflector(NetworkPolicyReflectorName, reflector.NewNamespaced,
+		reflector.NewFallback, reflectorConfig.NumWorkers, reflectorConfig.Type, generic.ConcurrencyModeLeader)
+	return reflector
+}
+
+// NewNamespaced returns a new NamespacedNetworkPolicyReflector instance.
+func (npr *NetworkPolicyReflector) NewNamespaced(opts *options.NamespacedOpts) manager.NamespacedReflector {
+	local := opts.LocalFactory.Networking().V1().NetworkPolicies()
+	remote := opts.RemoteFactory.Networking().V1().NetworkPolicies()
+
+	_, err := local.Informer().AddEventHandler(opts.HandlerFactory(generic.NamespacedKeyer(opts.LocalNamespace)))
+	utilruntime.Must(err)
+	_, err = remote.Informer().AddEventHandler(opts.HandlerFactory(generic.NamespacedKeyer(opts.LocalNamespace)))
+	utilruntime.Must(err)
+
+	return &NamespacedNetworkPolicyReflector{
+		NamespacedReflector:         generic.NewNamespacedReflector(opts, NetworkPolicyReflectorName),
+		localPods:                   npr.localPods,
+		localNamespaces:             npr.localNamespaces,
+		localNetworkPolicies:        local.Lister().NetworkPolicies(opts.LocalNamespace),
+		remoteNetworkPolicies:       remote.Lister().NetworkPolicies(opts.RemoteNamespace),
+		remoteNetworkPoliciesClient: opts.RemoteClient.NetworkingV1().NetworkPolicies(opts.RemoteNamespace),
+		ipamclient:                  npr.ipamclient,
+	}
+}
+
+// NewFallback returns a new FallbackReflector instance. The local pods and namespaces are retrieved across all namespaces,
+// so that the peers of the reflected NetworkPolicies can be resolved from the cache and kept up-to-date when they change.
+func (npr *NetworkPolicyReflector) NewFallback(opts *options.ReflectorOpts) manager.FallbackReflector {
+	pods := opts.LocalFactory.Core().V1().Pods()
+	namespaces := opts.LocalFactory.Core().V1().Namespaces()
+	policies := opts.LocalFactory.Networking().V1().NetworkPolicies()
+
+	npr.localPods = pods.Lister()
+	npr.localNamespaces = namespaces.Lister()
+	npr.localNetworkPolicies = policies.Lister()
+
+	_, err := pods.Informer().AddEventHandler(opts.HandlerFactory(npr.PodToNetworkPoliciesKeyer))
+	utilruntime.Must(err)
+	_, err = namespaces.Informer().AddEventHandler(opts.HandlerFactory(npr.NamespaceToNetworkPoliciesKeyer))
+	utilruntime.Must(err)
+
+	return &FallbackNetworkPolicyReflector{ready: opts.Ready}
+}
+
+// PodToNetworkPoliciesKeyer returns the keys of the local NetworkPolicies with at least one peer possibly selecting
+// the given pod, either in the same namespace or through a namespace selector matching the one of the pod.
+func (npr *NetworkPolicyReflector) PodToNetworkPoliciesKeyer(metadata metav1.Object) []types.NamespacedName {
+	namespace, err := npr.localNamespaces.Get(metadata.GetNamespace())
+	if err != nil {
+		// The namespace is being deleted, or not yet cached: the corresponding event will trigger the reconciliation.
+		klog.V(4).Infof("Failed to retrieve namespace %q of pod %q: %v", metadata.GetNamespace(), klog.KObj(metadata), err)
+		namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: metadata.GetNamespace()}}
+	}
+
+	return npr.networkPoliciesKeys(func(policy *netv1.NetworkPolicy, peer *netv1.NetworkPolicyPeer) bool {
+		if peer.NamespaceSelector == nil {
+			return peer.PodSelector != nil && policy.GetNamespace() == metadata.GetNamespace()
+		}
+		selector, err := metav1.LabelSelectorAsSelector(peer.NamespaceSelector)
+		return err == nil && selector.Matches(labels.Set(namespace.GetLabels()))
+	})
+}
+
+// NamespaceToNetworkPoliciesKeyer returns the keys of the local NetworkPolicies with at least one namespace selector peer,
+// as the pods of the given namespace might start or stop being selected depending on its labels.
+func (npr *NetworkPolicyReflector) NamespaceToNetworkPoliciesKeyer(_ metav1.Object) []types.NamespacedName {
+	return npr.networkPoliciesKeys(func(_ *netv1.NetworkPolicy, peer *netv1.NetworkPolicyPeer) bool {
+		return peer.NamespaceSelector != nil
+	})
+}
+
+// networkPoliciesKeys returns the keys of the local NetworkPolicies with at least one peer satisfying the given predicate.
+func (npr *NetworkPolicyReflector) networkPoliciesKeys(predicate func(*netv1.NetworkPolicy, *netv1.NetworkPolicyPeer) bool) []types.NamespacedName {
+	policies, err := npr.localNetworkPolicies.List(labels.Everything())
+	utilruntime.Must(err)
+
+	var keys []types.NamespacedName
+	keyer := generic.BasicKeyer()
+	for _, policy := range policies {
+		for _, peer := range networkPolicyPeers(&policy.Spec) {
+			if predicate(policy, peer) {
+				keys = append(keys, keyer(policy)...)
+				break
+			}
+		}
+	}
+
+	return keys
+}
+
+// networkPolicyPeers returns the peers of both the ingress and the egress rules of the given networkpolicy spec.
+func networkPolicyPeers(spec *netv1.NetworkPolicySpec) []*netv1.NetworkPolicyPeer {
+	var peers []*netv1.NetworkPolicyPeer
+	for i := range spec.Ingress {
+		for j := range spec.Ingress[i].From {
+			peers = append(peers, &spec.Ingress[i].From[j])
+		}
+	}
+	for i := range spec.Egress {
+		for j := range spec.Egress[i].To {
+			peers = append(peers, &spec.Egress[i].To[j])
+		}
+	}
+	return peers
+}
+
+// Handle reconciles networkpolicy objects.
+func (npr *NamespacedNetworkPolicyReflector) Handle(ctx context.Context, name string) error {
+	tracer := trace.FromContext(ctx)
+
+	// Retrieve the local and remote objects (only not found errors can occur).
+	klog.V(4).Infof("Handling reflection of local NetworkPolicy %q (remote: %q)", npr.LocalRef(name), npr.RemoteRef(name))
+	local, lerr := npr.localNetworkPolicies.Get(name)
+	utilruntime.Must(client.IgnoreNotFound(lerr))
+	remote, rerr := npr.remoteNetworkPolicies.Get(name)
+	utilruntime.Must(client.IgnoreNotFound(rerr))
+	tracer.Step("Retrieved the local and remote objects")
+
+	// Abort the reflection if the remote object is not managed by us, as we do not want to mutate others' objects.
+	if rerr == nil && !forge.IsReflected(remote) {
+		if lerr == nil { // Do not output the warning event in case the event was triggered by the remote object (i.e., the local one does not exists).
+			klog.Infof("Skipping reflection of local NetworkPolicy %q as remote already exists and is not managed by us", npr.LocalRef(name))
+			npr.Event(local, corev1.EventTypeWarning, forge.EventFailedReflection, forge.EventFailedReflectionAlreadyExistsMsg())
+		}
+		return nil
+	}
+
+	// Abort the reflection if the local object has the "skip-reflection" annotation.
+	if !kerrors.IsNotFound(lerr) {
+		skipReflection, err := npr.ShouldSkipReflection(local)
+		if err != nil {
+			klog.Errorf("Failed to check whether local NetworkPolicy %q should be reflected: %v", npr.LocalRef(name), err)
+			return err
+		}
+		if skipReflection {
+			if npr.GetReflectionType() == consts.DenyList {
+				klog.Infof("Skipping reflection of local NetworkPolicy %q as marked with the skip annotation", npr.LocalRef(name))
+			} else { // AllowList
+				klog.Infof("Skipping reflection of local NetworkPolicy %q as not marked with the allow annotation", npr.LocalRef(name))
+			}
+			npr.Event(local, corev1.EventTypeNormal, forge.EventReflectionDisabled, forge.EventObjectReflectionDisabledMsg(npr.GetReflectionType()))
+			if kerrors.IsNotFound(rerr) { // The remote object does not already exist, hence no further action is required.
+				return nil
+			}
+
+			// Otherwise, let pretend the local object does not exist, so that the remote one gets deleted.
+			lerr = kerrors.NewNotFound(netv1.Resource("networkpolicy"), local.GetName())
+		}
+	}
+
+	tracer.Step("Performed the sanity checks")
+
+	// The local networkpolicy does no longer exist. Ensure it is also absent from the remote cluster.
+	if kerrors.IsNotFound(lerr) {
+		defer tracer.Step("Ensured the absence of the remote object")
+		if !kerrors.IsNotFound(rerr) {
+			klog.V(4).Infof("Deleting remote NetworkPolicy %q, since local %q does no longer exist", npr.RemoteRef(name), npr.LocalRef(name))
+			return npr.DeleteRemote(ctx, npr.remoteNetworkPoliciesClient, NetworkPolicyReflectorName, name, remote.GetUID())
+		}
+
+		klog.V(4).Infof("Local NetworkPolicy %q and remote NetworkPolicy %q both vanished", npr.LocalRef(name), npr.RemoteRef(name))
+		return nil
+	}
+
+	// Translate the peers of the local networkpolicy, so that they refer to the remote cluster address space.
+	translated := local.DeepCopy()
+	if err := npr.TranslateNetworkPolicySpec(ctx, &translated.Spec); err != nil {
+		klog.Errorf("Failed to translate the peers of local NetworkPolicy %q: %v", npr.LocalRef(name), err)
+		npr.Event(local, corev1.EventTypeWarning, forge.EventFailedReflection, forge.EventFailedReflectionMsg(err))
+		return err
+	}
+	tracer.Step("Translated the networkpolicy peers")
+
+	// Forge the mutation to be applied to the remote cluster.
+	mutation := forge.RemoteNetworkPolicy(translated, npr.RemoteNamespace(), npr.ForgingOpts)
+	tracer.Step("Remote mutation created")
+
+	defer tracer.Step("Enforced the correctness of the remote object")
+	if _, err := npr.remoteNetworkPoliciesClient.Apply(ctx, mutation, forge.ApplyOptions()); err != nil {
+		klog.Errorf("Failed to enforce remote NetworkPolicy %q (local: %q): %v", npr.RemoteRef(name), npr.LocalRef(name), err)
+		npr.Event(local, corev1.EventTypeWarning, forge.EventFailedReflection, forge.EventFailedReflectionMsg(err))
+		return err
+	}
+
+	klog.Infof("Remote NetworkPolicy %q successfully enforced (local: %q)", npr.RemoteRef(name), npr.LocalRef(name))
+	npr.Event(local, corev1.EventTypeNormal, forge.EventSuccessfulReflection, forge.EventSuccessfulReflectionMsg())
+
+	return nil
+}
+
+// TranslateNetworkPolicySpec translates in place the peers of the given networkpolicy spec, so that they refer to the
+// remote cluster address space. The policy types are made explicit, and the rules whose peers all vanished during the
+// translation are removed, as they would otherwise be interpreted as allowing all traffic.
+func (npr *NamespacedNetworkPolicyReflector) TranslateNetworkPolicySpec(ctx context.Context, spec *netv1.NetworkPolicySpec) error {
+	spec.PolicyTypes = forge.NetworkPolicyTypes(spec)
+
+	ingress := make([]netv1.NetworkPolicyIngressRule, 0, len(spec.Ingress))
+	for i := range spec.Ingress {
+		peers, err := npr.TranslatePeers(ctx, spec.Ingress[i].From)
+		if err != nil {
+			return err
+		}
+		if len(spec.Ingress[i].From) > 0 && len(peers) == 0 {
+			continue
+		}
+		ingress = append(ingress, netv1.NetworkPolicyIngressRule{Ports: spec.Ingress[i].Ports, From: peers})
+	}
+	spec.Ingress = ingress
+
+	egress := make([]netv1.NetworkPolicyEgressRule, 0, len(spec.Egress))
+	for i := range spec.Egress {
+		peers, err := npr.TranslatePeers(ctx, spec.Egress[i].To)
+		if err != nil {
+			return err
+		}
+		if len(spec.Egress[i].To) > 0 && len(peers) == 0 {
+			continue
+		}
+		egress = append(egress, netv1.NetworkPolicyEgressRule{Ports: spec.Egress[i].Ports, To: peers})
+	}
+	spec.Egress = egress
+
+	return nil
+}
+
+// TranslatePeers translates the given set of networkpolicy peers, so that they refer to the remote cluster address space:
+//   - ipBlocks entirely contained in the local pod CIDR are remapped to the network used by the remote cluster to reach it;
+//   - pod selectors are preserved, to match the offloaded pods (which carry the same labels), and complemented with the
+//     translated addresses of the matching local pods;
+//   - namespace selectors, which refer to the namespaces of the local cluster, are replaced by the translated addresses
+//     of the matching local pods.
+func (npr *NamespacedNetworkPolicyReflector) TranslatePeers(ctx context.Context, peers []netv1.NetworkPolicyPeer) ([]netv1.NetworkPolicyPeer, error) {
+	var translated []netv1.NetworkPolicyPeer
+	for i := range peers {
+		switch {
+		case peers[i].IPBlock != nil:
+			block, err := npr.TranslateIPBlock(ctx, peers[i].IPBlock)
+			if err != nil {
+				return nil, err
+			}
+			translated = append(translated, netv1.NetworkPolicyPeer{IPBlock: block})
+
+		case peers[i].NamespaceSelector != nil:
+			pods, err := npr.selectPodsAcrossNamespaces(peers[i].NamespaceSelector, peers[i].PodSelector)
+			if err != nil {
+				return nil, err
+			}
+			blocks, err := npr.podsIPBlocks(ctx, pods)
+			if err != nil {
+				return nil, err
+			}
+			translated = append(translated, blocks...)
+
+		case peers[i].PodSelector != nil:
+			selector, err := metav1.LabelSelectorAsSelector(peers[i].PodSelector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pod selector: %w", err)
+			}
+			pods, err := npr.localPods.Pods(npr.LocalNamespace()).List(selector)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list local pods: %w", err)
+			}
+			blocks, err := npr.podsIPBlocks(ctx, pods)
+			if err != nil {
+				return nil, err
+			}
+			translated = append(translated, netv1.NetworkPolicyPeer{PodSelector: peers[i].PodSelector.DeepCopy()})
+			translated = append(translated, blocks...)
+		}
+	}
+
+	return translated, nil
+}
+
+// TranslateIPBlock translates the given ipBlock (and the corresponding exceptions), remapping the CIDRs entirely contained
+// in the local pod CIDR to the network used by the remote cluster to reach it. The other CIDRs are preserved, as referring
+// to destinations outside the cluster, which are reached directly by the remote cluster.
+func (npr *NamespacedNetworkPolicyReflector) TranslateIPBlock(ctx context.Context, block *netv1.IPBlock) (*netv1.IPBlock, error) {
+	cidr, err := npr.translateCIDR(ctx, block.CIDR)
+	if err != nil {
+		return nil, err
+	}
+
+	translated := &netv1.IPBlock{CIDR: cidr}
+	for _, except := range block.Except {
+		cidr, err := npr.translateCIDR(ctx, except)
+		if err != nil {
+			return nil, err
+		}
+		translated.Except = append(translated.Except, cidr)
+	}
+	return translated, nil
+}
+
+func (npr *NamespacedNetworkPolicyReflector) translateCIDR(ctx context.Context, cidr string) (string, error) {
+	if npr.ipamclient == nil {
+		// If the IPAM is not enabled we just use the original CIDR.
+		return cidr, nil
+	}
+
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return "", fmt.Errorf("invalid CIDR %q: %w", cidr, err)
+	}
+
+	// Compute the last address of the network, to verify whether it is entirely contained in the pod CIDR.
+	last := make(net.IP, len(network.IP))
+	for i := range network.IP {
+		last[i] = network.IP[i] | ^network.Mask[i]
+	}
+
+	for _, ip := range []net.IP{network.IP, last} {
+		response, err := npr.ipamclient.BelongsToPodCIDR(ctx, &ipam.BelongsRequest{Ip: ip.String()})
+		if err != nil {
+			return "", fmt.Errorf("failed to check whether CIDR %q belongs to the pod CIDR: %w", cidr, err)
+		}
+		if !response.GetBelongs() {
+			return cidr, nil
+		}
+	}
+
+	// Addresses belonging to the pod CIDR are remapped preserving the host part, hence it is enough to translate the network address.
+	response, err := npr.ipamclient.MapEndpointIP(ctx, &ipam.MapRequest{ClusterID: forge.RemoteCluster.ClusterID, Ip: network.IP.String()})
+	if err != nil {
+		return "", fmt.Errorf("failed to translate CIDR %q: %w", cidr, err)
+	}
+
+	ones, _ := network.Mask.Size()
+	translated := fmt.Sprintf("%s/%d", response.GetIp(), ones)
+	klog.V(6).Infof("Translated local CIDR %v to remote %v", cidr, translated)
+	return translated, nil
+}
+
+// selectPodsAcrossNamespaces returns the local pods matching the given pod selector (if any), in the namespaces matching the given selector.
+func (npr *NamespacedNetworkPolicyReflector) selectPodsAcrossNamespaces(namespaceSelector, podSelector *metav1.LabelSelector) ([]*corev1.Pod, error) {
+	nsSelector, err := metav1.LabelSelectorAsSelector(namespaceSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace selector: %w", err)
+	}
+
+	// A nil pod selector selects all pods in the matching namespaces.
+	podsSelector, err := metav1.LabelSelectorAsSelector(podSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pod selector: %w", err)
+	}
+	if podSelector == nil {
+		podsSelector = labels.Everything()
+	}
+
+	namespaces, err := npr.localNamespaces.List(nsSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list local namespaces: %w", err)
+	}
+
+	var pods []*corev1.Pod
+	for _, namespace := range namespaces {
+		list, err := npr.localPods.Pods(namespace.GetName()).List(podsSelector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list local pods in namespace %q: %w", namespace.GetName(), err)
+		}
+		pods = append(pods, list...)
+	}
+	return pods, nil
+}
+
+// podsIPBlocks returns the peers corresponding to the translated addresses of the given pods. Pods not belonging to the
+// local pod CIDR (e.g., offloaded or host network ones) are skipped, as not reachable through the local pod CIDR remapping.
+func (npr *NamespacedNetworkPolicyReflector) podsIPBlocks(ctx context.Context, pods []*corev1.Pod) ([]netv1.NetworkPolicyPeer, error) {
+	cidrs := map[string]struct{}{}
+	for _, pod := range pods {
+		for _, podIP := range pod.Status.PodIPs {
+			ip := net.ParseIP(podIP.IP)
+			if ip == nil {
+				continue
+			}
+
+			translation := podIP.IP
+			if npr.ipamclient != nil {
+				belongs, err := npr.ipamclient.BelongsToPodCIDR(ctx, &ipam.BelongsRequest{Ip: podIP.IP})
+				if err != nil {
+					return nil, fmt.Errorf("failed to check whether IP %v of pod %q belongs to the pod CIDR: %w", podIP.IP, klog.KObj(pod), err)
+				}
+				if !belongs.GetBelongs() {
+					continue
+				}
+
+				response, err := npr.ipamclient.MapEndpointIP(ctx, &ipam.MapRequest{ClusterID: forge.RemoteCluster.ClusterID, Ip: podIP.IP})
+				if err != nil {
+					return nil, fmt.Errorf("failed to translate IP %v of pod %q: %w", podIP.IP, klog.KObj(pod), err)
+				}
+				translation = response.GetIp()
+			}
+
+			bits := net.IPv4len * 8
+			if ip.To4() == nil {
+				bits = net.IPv6len * 8
+			}
+			cidrs[fmt.Sprintf("%s/%d", translation, bits)] = struct{}{}
+		}
+	}
+
+	// Sort the resulting CIDRs, to guarantee a deterministic output.
+	sorted := make([]string, 0, len(cidrs))
+	for cidr := range cidrs {
+		sorted = append(sorted, cidr)
+	}
+	sort.Strings(sorted)
+
+	peers := make([]netv1.NetworkPolicyPeer, 0, len(sorted))
+	for _, cidr := range sorted {
+		peers = append(peers, netv1.NetworkPolicyPeer{IPBlock: &netv1.IPBlock{CIDR: cidr}})
+	}
+	return peers, nil
+}
+
+// List returns the list of networkpolicy objects to be reflected.
+func (npr *NamespacedNetworkPolicyReflector) List() ([]interface{}, error) {
+	return virtualkubelet.List[virtualkubelet.Lister[*netv1.NetworkPolicy], *netv1.NetworkPolicy](
+		npr.localNetworkPolicies,
+		npr.remoteNetworkPolicies,
+	)
+}
+
+// Handle operates as fallback to reconcile networkpolicy objects not managed by namespaced handlers.
+func (fnpr *FallbackNetworkPolicyReflector) Handle(_ context.Context, key types.NamespacedName) error {
+	// No operation needs to be performed here, as the NetworkPolicies outside the managed namespaces are not reflected.
+	klog.V(4).Infof("Skipping reflection of local NetworkPolicy %q, as its namespace is not offloaded", key)
+	return nil
+}
+
+// Keys returns a set of keys to be enqueued for fallback processing for the given namespace pair.
+func (fnpr *FallbackNetworkPolicyReflector) Keys(_, _ string) []types.NamespacedName {
+	return nil
+}
+
+// Ready returns whether the FallbackReflector is completely initialized.
+func (fnpr *FallbackNetworkPolicyReflector) Ready() bool {
+	return fnpr.ready()
+}
+
+// List returns the list of objects.
+func (fnpr *FallbackNetworkPolicyReflector) List() ([]interface{}, error) {
+	return nil, nil
+}
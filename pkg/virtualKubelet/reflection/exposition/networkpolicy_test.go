@@ -0,0 +1,309 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exposition_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/trace"
+
+	"github.com/liqotech/liqo/cmd/virtual-kubelet/root"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqonet/ipam/fake"
+	. "github.com/liqotech/liqo/pkg/utils/testutil"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/exposition"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/generic"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/options"
+)
+
+var _ = Describe("NetworkPolicy Reflection Tests", func() {
+	Describe("the NewNetworkPolicyReflector function", func() {
+		It("should not return a nil reflector", func() {
+			reflectorConfig := generic.ReflectorConfig{
+				NumWorkers: 1,
+				Type:       root.DefaultReflectorsTypes[generic.NetworkPolicy],
+			}
+			Expect(exposition.NewNetworkPolicyReflector(nil, &reflectorConfig)).ToNot(BeNil())
+		})
+	})
+
+	Describe("networkpolicy handling", func() {
+		const (
+			NetworkPolicyName = "name"
+			PodName           = "pod"
+		)
+
+		var (
+			reflector      *exposition.NamespacedNetworkPolicyReflector
+			reflectionType consts.ReflectionType
+			ipam           *fake.IPAMClient
+
+			local, remote netv1.NetworkPolicy
+			err           error
+		)
+
+		GetNetworkPolicy := func(namespace string) *netv1.NetworkPolicy {
+			np, errnp := client.NetworkingV1().NetworkPolicies(namespace).Get(ctx, NetworkPolicyName, metav1.GetOptions{})
+			Expect(errnp).ToNot(HaveOccurred())
+			return np
+		}
+
+		CreateNetworkPolicy := func(np *netv1.NetworkPolicy) *netv1.NetworkPolicy {
+			np, errnp := client.NetworkingV1().NetworkPolicies(np.GetNamespace()).Create(ctx, np, metav1.CreateOptions{})
+			Expect(errnp).ToNot(HaveOccurred())
+			return np
+		}
+
+		ForgeNetworkPolicySpec := func(np *netv1.NetworkPolicy) *netv1.NetworkPolicy {
+			np.Spec.PodSelector = metav1.LabelSelector{MatchLabels: map[string]string{"app": "server"}}
+			np.Spec.Ingress = []netv1.NetworkPolicyIngressRule{{
+				From: []netv1.NetworkPolicyPeer{
+					{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "client"}}},
+					{IPBlock: &netv1.IPBlock{CIDR: "10.0.0.0/24", Except: []string{"10.0.0.128/25"}}},
+				},
+			}}
+			return np
+		}
+
+		WhenBodyRemoteShouldNotExist := func(createRemote bool) func() {
+			return func() {
+				BeforeEach(func() {
+					if createRemote {
+						remote.SetLabels(forge.ReflectionLabels())
+						ForgeNetworkPolicySpec(&remote)
+						CreateNetworkPolicy(&remote)
+					}
+				})
+
+				It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+				It("the remote object should not be present", func() {
+					_, err = client.NetworkingV1().NetworkPolicies(RemoteNamespace).Get(ctx, NetworkPolicyName, metav1.GetOptions{})
+					Expect(err).To(BeNotFound())
+				})
+			}
+		}
+
+		BeforeEach(func() {
+			local = netv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: NetworkPolicyName, Namespace: LocalNamespace}}
+			remote = netv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: NetworkPolicyName, Namespace: RemoteNamespace}}
+			reflectionType = root.DefaultReflectorsTypes[generic.NetworkPolicy]
+			ipam = fake.NewIPAMClient("192.168.200.0/24", "192.168.100.0/24", true)
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: PodName, Namespace: LocalNamespace, Labels: map[string]string{"app": "client"}},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "foo", Image: "foo"}}},
+			}
+			pod, err = client.CoreV1().Pods(LocalNamespace).Create(ctx, pod, metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			pod.Status.PodIP = "10.0.0.42"
+			pod.Status.PodIPs = []corev1.PodIP{{IP: "10.0.0.42"}}
+			_, err = client.CoreV1().Pods(LocalNamespace).UpdateStatus(ctx, pod, metav1.UpdateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(client.NetworkingV1().NetworkPolicies(LocalNamespace).Delete(ctx, NetworkPolicyName, metav1.DeleteOptions{})).To(
+				Or(BeNil(), WithTransform(kerrors.IsNotFound, BeTrue())))
+			Expect(client.NetworkingV1().NetworkPolicies(RemoteNamespace).Delete(ctx, NetworkPolicyName, metav1.DeleteOptions{})).To(
+				Or(BeNil(), WithTransform(kerrors.IsNotFound, BeTrue())))
+			Expect(client.CoreV1().Pods(LocalNamespace).Delete(ctx, PodName, *metav1.NewDeleteOptions(0))).To(
+				Or(BeNil(), WithTransform(kerrors.IsNotFound, BeTrue())))
+		})
+
+		JustBeforeEach(func() {
+			factory := informers.NewSharedInformerFactory(client, 10*time.Hour)
+			reflectorConfig := generic.ReflectorConfig{NumWorkers: 0, Type: reflectionType}
+			rfl := exposition.NewNetworkPolicyReflector(ipam, &reflectorConfig).(*exposition.NetworkPolicyReflector)
+			rfl.NewFallback(options.New(client, factory.Core().V1().Pods()).WithLocalFactory(factory).WithHandlerFactory(FakeEventHandler))
+			reflector = rfl.NewNamespaced(options.NewNamespaced().
+				WithLocal(LocalNamespace, client, factory).
+				WithRemote(RemoteNamespace, client, factory).
+				WithHandlerFactory(FakeEventHandler).
+				WithEventBroadcaster(record.NewBroadcaster()).
+				WithReflectionType(reflectionType).
+				WithForgingOpts(FakeForgingOpts())).(*exposition.NamespacedNetworkPolicyReflector)
+
+			factory.Start(ctx.Done())
+			factory.WaitForCacheSync(ctx.Done())
+
+			err = reflector.Handle(trace.ContextWithTrace(ctx, trace.New("NetworkPolicy")), NetworkPolicyName)
+		})
+
+		When("the local object does not exist", func() {
+			When("the remote object does not exist", WhenBodyRemoteShouldNotExist(false))
+			When("the remote object does exist", WhenBodyRemoteShouldNotExist(true))
+		})
+
+		When("the local object does exist", func() {
+			BeforeEach(func() {
+				local.SetLabels(map[string]string{"foo": "bar", FakeNotReflectedLabelKey: "true"})
+				local.SetAnnotations(map[string]string{"bar": "baz", FakeNotReflectedAnnotKey: "true"})
+				ForgeNetworkPolicySpec(&local)
+				CreateNetworkPolicy(&local)
+			})
+
+			It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+			It("the metadata should have been correctly replicated to the remote object", func() {
+				remoteAfter := GetNetworkPolicy(RemoteNamespace)
+				Expect(remoteAfter.Labels).To(HaveKeyWithValue(forge.LiqoOriginClusterIDKey, LocalClusterID))
+				Expect(remoteAfter.Labels).To(HaveKeyWithValue(forge.LiqoDestinationClusterIDKey, RemoteClusterID))
+				Expect(remoteAfter.Labels).To(HaveKeyWithValue("foo", "bar"))
+				Expect(remoteAfter.Labels).ToNot(HaveKey(FakeNotReflectedLabelKey))
+				Expect(remoteAfter.Annotations).To(HaveKeyWithValue("bar", "baz"))
+				Expect(remoteAfter.Annotations).ToNot(HaveKey(FakeNotReflectedAnnotKey))
+			})
+			It("the spec should have been correctly translated and replicated to the remote object", func() {
+				remoteAfter := GetNetworkPolicy(RemoteNamespace)
+				Expect(remoteAfter.Spec.PodSelector.MatchLabels).To(HaveKeyWithValue("app", "server"))
+				Expect(remoteAfter.Spec.PolicyTypes).To(ConsistOf(netv1.PolicyTypeIngress))
+				Expect(remoteAfter.Spec.Ingress).To(HaveLen(1))
+				Expect(remoteAfter.Spec.Ingress[0].From).To(ConsistOf(
+					netv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "client"}}},
+					netv1.NetworkPolicyPeer{IPBlock: &netv1.IPBlock{CIDR: "192.168.200.42/32"}},
+					netv1.NetworkPolicyPeer{IPBlock: &netv1.IPBlock{CIDR: "192.168.200.0/24", Except: []string{"192.168.200.128/25"}}},
+				))
+			})
+		})
+
+		When("the local object does exist, but has the skip annotation", func() {
+			BeforeEach(func() {
+				local.SetAnnotations(map[string]string{consts.SkipReflectionAnnotationKey: "whatever"})
+				ForgeNetworkPolicySpec(&local)
+				CreateNetworkPolicy(&local)
+			})
+
+			When("the remote object does not exist", WhenBodyRemoteShouldNotExist(false))
+			When("the remote object does exist", WhenBodyRemoteShouldNotExist(true))
+		})
+
+		When("the reflection type is AllowList", func() {
+			BeforeEach(func() {
+				reflectionType = consts.AllowList
+			})
+
+			When("the local object does exist, but does not have the allow annotation", func() {
+				BeforeEach(func() {
+					ForgeNetworkPolicySpec(&local)
+					CreateNetworkPolicy(&local)
+				})
+
+				When("the remote object does not exist", WhenBodyRemoteShouldNotExist(false))
+				When("the remote object does exist", WhenBodyRemoteShouldNotExist(true))
+			})
+		})
+
+		When("translating a rule whose peers do not match any pod", func() {
+			var spec netv1.NetworkPolicySpec
+
+			BeforeEach(func() {
+				spec = netv1.NetworkPolicySpec{
+					Egress: []netv1.NetworkPolicyEgressRule{{
+						To: []netv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"not": "existing"}}}},
+					}},
+				}
+			})
+
+			It("should remove the rule, while preserving the policy type", func() {
+				Expect(reflector.TranslateNetworkPolicySpec(ctx, &spec)).To(Succeed())
+				Expect(spec.PolicyTypes).To(ConsistOf(netv1.PolicyTypeIngress, netv1.PolicyTypeEgress))
+				Expect(spec.Egress).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("the keyers of the fallback reflector", func() {
+		const (
+			SamePolicyName  = "same-namespace"
+			CrossPolicyName = "cross-namespace"
+			BlockPolicyName = "ip-block"
+		)
+
+		var (
+			reflector *exposition.NetworkPolicyReflector
+			metadata  metav1.Object
+		)
+
+		CreateNetworkPolicy := func(name string, peer netv1.NetworkPolicyPeer) {
+			np := &netv1.NetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: LocalNamespace},
+				Spec:       netv1.NetworkPolicySpec{Egress: []netv1.NetworkPolicyEgressRule{{To: []netv1.NetworkPolicyPeer{peer}}}},
+			}
+			_, errnp := client.NetworkingV1().NetworkPolicies(LocalNamespace).Create(ctx, np, metav1.CreateOptions{})
+			Expect(errnp).ToNot(HaveOccurred())
+		}
+
+		Key := func(name string) types.NamespacedName {
+			return types.NamespacedName{Namespace: LocalNamespace, Name: name}
+		}
+
+		BeforeEach(func() {
+			CreateNetworkPolicy(SamePolicyName, netv1.NetworkPolicyPeer{
+				PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "client"}}})
+			CreateNetworkPolicy(CrossPolicyName, netv1.NetworkPolicyPeer{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: RemoteNamespace}}})
+			CreateNetworkPolicy(BlockPolicyName, netv1.NetworkPolicyPeer{IPBlock: &netv1.IPBlock{CIDR: "10.0.0.0/24"}})
+		})
+
+		AfterEach(func() {
+			for _, name := range []string{SamePolicyName, CrossPolicyName, BlockPolicyName} {
+				Expect(client.NetworkingV1().NetworkPolicies(LocalNamespace).Delete(ctx, name, metav1.DeleteOptions{})).To(Succeed())
+			}
+		})
+
+		JustBeforeEach(func() {
+			factory := informers.NewSharedInformerFactory(client, 10*time.Hour)
+			reflectorConfig := generic.ReflectorConfig{NumWorkers: 0, Type: root.DefaultReflectorsTypes[generic.NetworkPolicy]}
+			reflector = exposition.NewNetworkPolicyReflector(nil, &reflectorConfig).(*exposition.NetworkPolicyReflector)
+			reflector.NewFallback(options.New(client, factory.Core().V1().Pods()).WithLocalFactory(factory).WithHandlerFactory(FakeEventHandler))
+
+			factory.Start(ctx.Done())
+			factory.WaitForCacheSync(ctx.Done())
+		})
+
+		When("a pod in the same namespace of the policies changes", func() {
+			BeforeEach(func() { metadata = &metav1.ObjectMeta{Name: "pod", Namespace: LocalNamespace} })
+
+			It("should enqueue the policies with a pod selector peer", func() {
+				Expect(reflector.PodToNetworkPoliciesKeyer(metadata)).To(ConsistOf(Key(SamePolicyName)))
+			})
+		})
+
+		When("a pod in a namespace selected by a policy changes", func() {
+			BeforeEach(func() { metadata = &metav1.ObjectMeta{Name: "pod", Namespace: RemoteNamespace} })
+
+			It("should enqueue the policies with a namespace selector peer matching the namespace", func() {
+				Expect(reflector.PodToNetworkPoliciesKeyer(metadata)).To(ConsistOf(Key(CrossPolicyName)))
+			})
+		})
+
+		When("a namespace changes", func() {
+			BeforeEach(func() { metadata = &metav1.ObjectMeta{Name: RemoteNamespace} })
+
+			It("should enqueue the policies with a namespace selector peer", func() {
+				Expect(reflector.NamespaceToNetworkPoliciesKeyer(metadata)).To(ConsistOf(Key(CrossPolicyName)))
+			})
+		})
+	})
+})
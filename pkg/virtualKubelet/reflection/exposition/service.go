@@ -18,7 +18,9 @@ import (
 	"context"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	corev1clients "k8s.io/client-go/kubernetes/typed/core/v1"
 	corev1listers "k8s.io/client-go/listers/core/v1"
@@ -50,6 +52,7 @@ type NamespacedServiceReflector struct {
 
 	localServices        corev1listers.ServiceNamespaceLister
 	remoteServices       corev1listers.ServiceNamespaceLister
+	localServicesClient  corev1clients.ServiceInterface
 	remoteServicesClient corev1clients.ServiceInterface
 }
 
@@ -73,6 +76,7 @@ func NewNamespacedServiceReflector(opts *options.NamespacedOpts) manager.Namespa
 		NamespacedReflector:  generic.NewNamespacedReflector(opts, ServiceReflectorName),
 		localServices:        local.Lister().Services(opts.LocalNamespace),
 		remoteServices:       remote.Lister().Services(opts.RemoteNamespace),
+		localServicesClient:  opts.LocalClient.CoreV1().Services(opts.LocalNamespace),
 		remoteServicesClient: opts.RemoteClient.CoreV1().Services(opts.RemoteNamespace),
 	}
 }
@@ -122,6 +126,12 @@ func (nsr *NamespacedServiceReflector) Handle(ctx context.Context, name string)
 				return nil
 			}
 
+			// The remote object is managed by the StatefulSet reflection, as it is a governing service. Hence, leave it there.
+			if forge.IsGoverningService(remote) {
+				klog.V(4).Infof("Preserving remote Service %q, as governing service of reflected StatefulSets", nsr.RemoteRef(name))
+				return nil
+			}
+
 			// Otherwise, let pretend the local object does not exist, so that the remote one gets deleted.
 			lerr = kerrors.NewNotFound(corev1.Resource("service"), local.GetName())
 		}
@@ -145,8 +155,8 @@ func (nsr *NamespacedServiceReflector) Handle(ctx context.Context, name string)
 	mutation := forge.RemoteService(local, nsr.RemoteNamespace(), nsr.ForgingOpts)
 	tracer.Step("Remote mutation created")
 
-	defer tracer.Step("Enforced the correctness of the remote object")
-	if _, err := nsr.remoteServicesClient.Apply(ctx, mutation, forge.ApplyOptions()); err != nil {
+	remote, err := nsr.remoteServicesClient.Apply(ctx, mutation, forge.ApplyOptions())
+	if err != nil {
 		klog.Errorf("Failed to enforce remote Service %q (local: %q): %v", nsr.RemoteRef(name), nsr.LocalRef(name), err)
 		nsr.Event(local, corev1.EventTypeWarning, forge.EventFailedReflection, forge.EventFailedReflectionMsg(err))
 		return err
@@ -154,7 +164,49 @@ func (nsr *NamespacedServiceReflector) Handle(ctx context.Context, name string)
 
 	klog.Infof("Remote Service %q successfully enforced (local: %q)", nsr.RemoteRef(name), nsr.LocalRef(name))
 	nsr.Event(local, corev1.EventTypeNormal, forge.EventSuccessfulReflection, forge.EventSuccessfulReflectionMsg())
+	tracer.Step("Enforced the correctness of the remote object")
+
+	defer tracer.Step("Enforced the correctness of the local status")
+	return nsr.EnforceLocalStatus(ctx, local, remote)
+}
+
+// EnforceLocalStatus propagates the load balancer status of the remote service back to the local one,
+// in case the local service is of type LoadBalancer and the status reflection has been requested. The status
+// previously propagated is cleared once the reflection is no longer requested, or the service type changes.
+func (nsr *NamespacedServiceReflector) EnforceLocalStatus(ctx context.Context, local, remote *corev1.Service) error {
+	var desired *corev1.LoadBalancerStatus
+	switch {
+	case local.Spec.Type == corev1.ServiceTypeLoadBalancer && forge.ShouldReflectStatus(local):
+		// Do not fight with other controllers (e.g., a local cloud controller) assigning the addresses to the service.
+		if forge.IsStatusManagedByOthers(local) {
+			klog.Warningf("Skipping status reflection of local Service %q, as already managed by a different controller", nsr.LocalRef(local.GetName()))
+			nsr.Event(local, corev1.EventTypeWarning, forge.EventFailedReflection, forge.EventFailedStatusReflectionManagedByOthersMsg())
+			return nil
+		}
+		desired = &remote.Status.LoadBalancer
+	case forge.IsStatusReflected(local) && (local.Spec.Type != corev1.ServiceTypeLoadBalancer || forge.ShouldClearStatus(local)):
+		desired = &corev1.LoadBalancerStatus{}
+	default:
+		return nil
+	}
+
+	if equality.Semantic.DeepEqual(local.Status.LoadBalancer, *desired) {
+		klog.V(4).Infof("Skipping status update of local Service %q, as already synced", nsr.LocalRef(local.GetName()))
+		return nil
+	}
+
+	updated := local.DeepCopy()
+	updated.Status.LoadBalancer = *desired.DeepCopy()
+	if _, err := nsr.localServicesClient.UpdateStatus(ctx, updated, metav1.UpdateOptions{FieldManager: forge.ReflectionFieldManager}); err != nil {
+		klog.Errorf("Failed to update the status of local Service %q (remote: %q): %v", nsr.LocalRef(local.GetName()), nsr.RemoteRef(local.GetName()), err)
+		if !kerrors.IsConflict(err) {
+			nsr.Event(local, corev1.EventTypeWarning, forge.EventFailedReflection, forge.EventFailedStatusReflectionMsg(err))
+		}
+		return err
+	}
 
+	klog.Infof("Status of local Service %q successfully updated (remote: %q)", nsr.LocalRef(local.GetName()), nsr.RemoteRef(local.GetName()))
+	nsr.Event(local, corev1.EventTypeNormal, forge.EventSuccessfulReflection, forge.EventSuccessfulStatusReflectionMsg())
 	return nil
 }
 
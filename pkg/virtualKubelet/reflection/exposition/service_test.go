@@ -195,6 +195,76 @@ var _ = Describe("Service Reflection Tests", func() {
 			})
 		})
 
+		When("the local object does exist, and has the reflect status annotation", func() {
+			BeforeEach(func() {
+				local.SetAnnotations(map[string]string{consts.ReflectStatusAnnotationKey: "true"})
+				local.Spec = corev1.ServiceSpec{
+					Type:  corev1.ServiceTypeLoadBalancer,
+					Ports: []corev1.ServicePort{{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080)}},
+				}
+				CreateService(&local)
+
+				remote.SetLabels(forge.ReflectionLabels())
+				remote.Spec = local.Spec
+				created := CreateService(&remote)
+				created.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "1.1.1.1"}}
+				_, err = client.CoreV1().Services(RemoteNamespace).UpdateStatus(ctx, created, metav1.UpdateOptions{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+			It("the load balancer status should have been propagated to the local object", func() {
+				localAfter := GetService(LocalNamespace)
+				Expect(localAfter.Status.LoadBalancer.Ingress).To(ConsistOf(corev1.LoadBalancerIngress{IP: "1.1.1.1"}))
+			})
+		})
+
+		When("the local object does exist, and its status is managed by a different controller", func() {
+			BeforeEach(func() {
+				local.SetAnnotations(map[string]string{consts.ReflectStatusAnnotationKey: "true"})
+				local.Spec = corev1.ServiceSpec{
+					Type:  corev1.ServiceTypeLoadBalancer,
+					Ports: []corev1.ServicePort{{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080)}},
+				}
+				created := CreateService(&local)
+				created.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "2.2.2.2"}}
+				_, err = client.CoreV1().Services(LocalNamespace).UpdateStatus(ctx, created, metav1.UpdateOptions{FieldManager: "cloud-controller"})
+				Expect(err).ToNot(HaveOccurred())
+
+				remote.SetLabels(forge.ReflectionLabels())
+				remote.Spec = local.Spec
+				created = CreateService(&remote)
+				created.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "1.1.1.1"}}
+				_, err = client.CoreV1().Services(RemoteNamespace).UpdateStatus(ctx, created, metav1.UpdateOptions{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+			It("the load balancer status of the local object should have been preserved", func() {
+				localAfter := GetService(LocalNamespace)
+				Expect(localAfter.Status.LoadBalancer.Ingress).To(ConsistOf(corev1.LoadBalancerIngress{IP: "2.2.2.2"}))
+			})
+		})
+
+		When("the local object does exist, and the reflect status annotation has been removed", func() {
+			BeforeEach(func() {
+				local.Spec = corev1.ServiceSpec{
+					Type:  corev1.ServiceTypeLoadBalancer,
+					Ports: []corev1.ServicePort{{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080)}},
+				}
+				created := CreateService(&local)
+				created.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "1.1.1.1"}}
+				_, err = client.CoreV1().Services(LocalNamespace).UpdateStatus(ctx, created, metav1.UpdateOptions{FieldManager: forge.ReflectionFieldManager})
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+			It("the load balancer status previously reflected should have been cleared", func() {
+				localAfter := GetService(LocalNamespace)
+				Expect(localAfter.Status.LoadBalancer.Ingress).To(BeEmpty())
+			})
+		})
+
 		When("the local object does exist, but has the skip annotation", func() {
 			BeforeEach(func() {
 				local.SetAnnotations(map[string]string{consts.SkipReflectionAnnotationKey: "whatever"})
@@ -16,6 +16,7 @@ package fake
 
 import (
 	"context"
+	"sync"
 
 	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/options"
 )
@@ -28,6 +29,9 @@ type Reflector struct {
 	NamespaceStopped   map[string]string
 	NamespaceReady     map[string]func() bool
 	isLeaderRestricted bool
+
+	resyncMutex       sync.Mutex
+	namespaceResynced map[string]int
 }
 
 func (r *Reflector) String() string { return "fakeReflector" }
@@ -38,6 +42,7 @@ func NewReflector(isLeaderRestricted bool) *Reflector {
 		NamespaceStarted:   make(map[string]*options.NamespacedOpts),
 		NamespaceStopped:   make(map[string]string),
 		isLeaderRestricted: isLeaderRestricted,
+		namespaceResynced:  make(map[string]int),
 	}
 }
 
@@ -66,3 +71,18 @@ func (r *Reflector) IsLeaderRestricted() bool {
 func (r *Reflector) Resync() error {
 	return nil
 }
+
+// ResyncNamespace counts the resyncs of the given namespace.
+func (r *Reflector) ResyncNamespace(local string) error {
+	r.resyncMutex.Lock()
+	defer r.resyncMutex.Unlock()
+	r.namespaceResynced[local]++
+	return nil
+}
+
+// NamespaceResynced returns the number of resyncs of the given namespace.
+func (r *Reflector) NamespaceResynced(local string) int {
+	r.resyncMutex.Lock()
+	defer r.resyncMutex.Unlock()
+	return r.namespaceResynced[local]
+}
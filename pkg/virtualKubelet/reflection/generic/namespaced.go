@@ -27,6 +27,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 
+	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
 	"github.com/liqotech/liqo/pkg/consts"
 	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
 	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/options"
@@ -56,7 +57,7 @@ func NewNamespacedReflector(opts *options.NamespacedOpts, name string) Namespace
 	return NamespacedReflector{
 		EventRecorder: opts.EventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "liqo-" + strings.ToLower(name) + "-reflection"}),
 		local:         opts.LocalNamespace, remote: opts.RemoteNamespace, ready: opts.Ready,
-		reflectionType: opts.ReflectionType, ForgingOpts: opts.ForgingOpts,
+		reflectionType: opts.ReflectionType, ForgingOpts: opts.ForgingOpts.ForResource(name),
 	}
 }
 
@@ -110,6 +111,8 @@ func (gnr *NamespacedReflector) DeleteLocal(ctx context.Context, deleter Resourc
 }
 
 // ShouldSkipReflection returns whether the reflection of the given object should be skipped.
+// The explicit annotations on the object take precedence over the matching ReflectionPolicies,
+// which in turn take precedence over the reflection type configured for the given resource.
 func (gnr *NamespacedReflector) ShouldSkipReflection(obj metav1.Object) (bool, error) {
 	switch gnr.reflectionType {
 	case consts.AllowList:
@@ -117,13 +120,19 @@ func (gnr *NamespacedReflector) ShouldSkipReflection(obj metav1.Object) (bool, e
 		if ok && strings.EqualFold(value, "false") {
 			return true, nil
 		}
-		return !ok, nil
+		if !ok {
+			return gnr.ForgingOpts.ReflectionPolicyAction(obj) != vkv1alpha1.ReflectionPolicyActionReflect, nil
+		}
+		return false, nil
 	case consts.DenyList:
 		value, ok := obj.GetAnnotations()[consts.SkipReflectionAnnotationKey]
 		if ok && strings.EqualFold(value, "false") {
 			return false, nil
 		}
-		return ok, nil
+		if !ok {
+			return gnr.ForgingOpts.ReflectionPolicyAction(obj) == vkv1alpha1.ReflectionPolicyActionSkip, nil
+		}
+		return true, nil
 	default:
 		return true, fmt.Errorf("ReflectionType value %q not supported", gnr.reflectionType)
 	}
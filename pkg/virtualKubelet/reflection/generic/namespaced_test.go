@@ -24,9 +24,12 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
 	corev1clients "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 
+	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
+	vklisters "github.com/liqotech/liqo/pkg/client/listers/virtualkubelet/v1alpha1"
 	"github.com/liqotech/liqo/pkg/consts"
 	. "github.com/liqotech/liqo/pkg/utils/testutil"
 	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
@@ -69,7 +72,7 @@ var _ = Describe("NamespacedReflector tests", func() {
 			Expect(nsrfl.remote).To(BeIdenticalTo(remoteNamespace))
 			Expect(nsrfl.ready).ToNot(BeNil())
 			Expect(nsrfl.reflectionType).To(BeIdenticalTo(reflectionType))
-			Expect(nsrfl.ForgingOpts).To(BeIdenticalTo(forgingOpts))
+			Expect(nsrfl.ForgingOpts).To(Equal(forgingOpts.ForResource(name)))
 		})
 
 		Context("the readiness property", func() {
@@ -98,6 +101,43 @@ var _ = Describe("NamespacedReflector tests", func() {
 			})
 		})
 
+		Context("the ShouldSkipReflection function", func() {
+			var indexer cache.Indexer
+
+			BeforeEach(func() {
+				indexer = cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+				forgingOpts = &forge.ForgingOpts{ReflectionPolicies: vklisters.NewReflectionPolicyLister(indexer)}
+			})
+
+			DescribeTable("should return the expected outcome",
+				func(rtype consts.ReflectionType, action vkv1alpha1.ReflectionPolicyAction, annotations map[string]string, expected bool) {
+					nsrfl.reflectionType = rtype
+					Expect(indexer.Add(&vkv1alpha1.ReflectionPolicy{
+						ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: localNamespace},
+						Spec: vkv1alpha1.ReflectionPolicySpec{
+							Resources: []string{name},
+							Selector:  &metav1.LabelSelector{MatchLabels: map[string]string{"app": "bar"}},
+							Action:    action,
+						},
+					})).To(Succeed())
+
+					skip, err := nsrfl.ShouldSkipReflection(&metav1.ObjectMeta{
+						Name: name, Namespace: localNamespace, Labels: map[string]string{"app": "bar"}, Annotations: annotations,
+					})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(skip).To(Equal(expected))
+				},
+				Entry("deny list, no action", consts.DenyList, vkv1alpha1.ReflectionPolicyAction(""), nil, false),
+				Entry("deny list, skip action", consts.DenyList, vkv1alpha1.ReflectionPolicyActionSkip, nil, true),
+				Entry("deny list, skip action, explicit annotation", consts.DenyList, vkv1alpha1.ReflectionPolicyActionSkip,
+					map[string]string{consts.SkipReflectionAnnotationKey: "false"}, false),
+				Entry("allow list, no action", consts.AllowList, vkv1alpha1.ReflectionPolicyAction(""), nil, true),
+				Entry("allow list, reflect action", consts.AllowList, vkv1alpha1.ReflectionPolicyActionReflect, nil, false),
+				Entry("allow list, reflect action, explicit annotation", consts.AllowList, vkv1alpha1.ReflectionPolicyActionReflect,
+					map[string]string{consts.AllowReflectionAnnotationKey: "false"}, true),
+			)
+		})
+
 		Context("remote resource deletion", func() {
 			var (
 				ctx     context.Context
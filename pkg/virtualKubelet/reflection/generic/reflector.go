@@ -99,13 +99,17 @@ const (
 	ServiceAccount        ResourceReflected = "serviceaccount"
 	PersistentVolumeClaim ResourceReflected = "persistentvolumeclaim"
 	Event                 ResourceReflected = "event"
+	StatefulSet           ResourceReflected = "statefulset"
+	NetworkPolicy         ResourceReflected = "networkpolicy"
 )
 
 // Reflectors is the list of all resources that can be reflected.
-var Reflectors = []ResourceReflected{Pod, Service, EndpointSlice, Ingress, ConfigMap, Secret, ServiceAccount, PersistentVolumeClaim, Event}
+var Reflectors = []ResourceReflected{Pod, Service, EndpointSlice, Ingress, ConfigMap, Secret, ServiceAccount, PersistentVolumeClaim, Event,
+	StatefulSet, NetworkPolicy}
 
 // ReflectorsCustomizableType is the list of resources for which the reflection type can be customized.
-var ReflectorsCustomizableType = []ResourceReflected{Service, EndpointSlice, Ingress, ConfigMap, Secret, Event}
+var ReflectorsCustomizableType = []ResourceReflected{Service, EndpointSlice, Ingress, ConfigMap, Secret, Event, StatefulSet,
+	NetworkPolicy}
 
 // ReflectorConfig contains configuration parameters of the reflector.
 type ReflectorConfig struct {
@@ -389,6 +393,24 @@ func (gr *reflector) Resync() error {
 	return nil
 }
 
+// ResyncNamespace triggers a resync of the namespaced reflector associated with the given local namespace (if any).
+func (gr *reflector) ResyncNamespace(local string) error {
+	reflector, found := gr.namespace(local)
+	if !found {
+		return nil
+	}
+
+	objs, err := reflector.List()
+	if err != nil {
+		return err
+	}
+	klog.V(4).Infof("Resynced %v reflector for local namespace %q", gr.name, local)
+	for i := range objs {
+		gr.workqueue.Add(objs[i])
+	}
+	return nil
+}
+
 // BasicKeyer returns a keyer retrieving the name and namespace from the object metadata.
 func BasicKeyer() func(metadata metav1.Object) []types.NamespacedName {
 	return func(metadata metav1.Object) []types.NamespacedName {
@@ -443,6 +465,11 @@ func (dr *dummyreflector) Resync() error {
 	return nil
 }
 
+// ResyncNamespace triggers the resync of the given namespace (no-op).
+func (dr *dummyreflector) ResyncNamespace(_ string) error {
+	return nil
+}
+
 // EnqueueAfter returns an error to convey that the current key should be reenqueued after a given duration.
 func EnqueueAfter(interval time.Duration) error {
 	return enqueueAfterError{duration: interval}
@@ -56,6 +56,8 @@ type Reflector interface {
 	StopNamespace(local, remote string)
 	// Resync triggers a resync of the reflector.
 	Resync() error
+	// ResyncNamespace triggers a resync of the reflector for the given local namespace.
+	ResyncNamespace(local string) error
 }
 
 // NamespacedReflector implements the reflection between a local and a remote namespace.
@@ -22,8 +22,12 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/trace"
@@ -37,6 +41,9 @@ import (
 
 var _ Manager = (*manager)(nil)
 
+// dynamicCacheSyncTimeout is the time after which a warning is output if the custom resource caches have not yet synced.
+const dynamicCacheSyncTimeout = time.Minute
+
 // manager is an object managing the reflection of objects between the local and the remote cluster.
 type manager struct {
 	sync.Mutex
@@ -45,11 +52,18 @@ type manager struct {
 	remote           kubernetes.Interface
 	localLiqo        liqoclient.Interface
 	remoteLiqo       liqoclient.Interface
+	localDynamic     dynamic.Interface
+	remoteDynamic    dynamic.Interface
 	resync           time.Duration
 	eventBroadcaster record.EventBroadcaster
 
 	reflectors              []Reflector
 	localPodInformerFactory informers.SharedInformerFactory
+	// localInformerFactory is the cluster-wide factory retrieving the local objects across all namespaces (e.g., to resolve
+	// the NetworkPolicy peers). Only the informers requested by the reflectors when started are actually instantiated.
+	localInformerFactory informers.SharedInformerFactory
+	// localPolicyInformerFactory is the cluster-wide factory retrieving the ReflectionPolicies.
+	localPolicyInformerFactory liqoinformers.SharedInformerFactory
 
 	namespaceHandler NamespaceHandler
 
@@ -60,29 +74,38 @@ type manager struct {
 }
 
 // New returns a new manager to start the reflection towards a remote cluster.
-func New(local, remote kubernetes.Interface, localLiqo, remoteLiqo liqoclient.Interface, resync time.Duration,
-	eb record.EventBroadcaster, labelsNotReflected, annotationsNotReflected []string) Manager {
+func New(local, remote kubernetes.Interface, localLiqo, remoteLiqo liqoclient.Interface, localDynamic, remoteDynamic dynamic.Interface,
+	resync time.Duration, eb record.EventBroadcaster, labelsNotReflected, annotationsNotReflected []string) Manager {
 	// Configure the field selector to retrieve only the pods scheduled on the current virtual node.
 	localPodTweakListOptions := func(opts *metav1.ListOptions) {
 		opts.FieldSelector = fields.OneTermEqualSelector("spec.nodeName", forge.LiqoNodeName).String()
 	}
 
+	// The ReflectionPolicies are retrieved from all namespaces through a single informer, and read dynamically by the reflectors.
+	localPolicyInformerFactory := liqoinformers.NewSharedInformerFactory(localLiqo, resync)
+	forgingOpts := forge.NewForgingOpts(labelsNotReflected, annotationsNotReflected)
+	forgingOpts.WithReflectionPolicies(localPolicyInformerFactory.Virtualkubelet().V1alpha1().ReflectionPolicies().Lister())
+
 	return &manager{
 		local:            local,
 		remote:           remote,
 		localLiqo:        localLiqo,
 		remoteLiqo:       remoteLiqo,
+		localDynamic:     localDynamic,
+		remoteDynamic:    remoteDynamic,
 		resync:           resync,
 		eventBroadcaster: eb,
 
 		reflectors: make([]Reflector, 0),
 		localPodInformerFactory: informers.NewSharedInformerFactoryWithOptions(local, resync,
 			informers.WithTweakListOptions(localPodTweakListOptions)),
+		localInformerFactory:       informers.NewSharedInformerFactory(local, resync),
+		localPolicyInformerFactory: localPolicyInformerFactory,
 
 		started: false,
 		stop:    make(map[string]context.CancelFunc),
 
-		forgingOpts: forge.NewForgingOpts(labelsNotReflected, annotationsNotReflected),
+		forgingOpts: forgingOpts,
 	}
 }
 
@@ -114,14 +137,23 @@ func (m *manager) Start(ctx context.Context) {
 	klog.Info("Starting the reflection manager...")
 	ready := false
 	for _, reflector := range m.reflectors {
-		opts := options.New(m.local, m.localPodInformerFactory.Core().V1().Pods()).
+		opts := options.New(m.local, m.localPodInformerFactory.Core().V1().Pods()).WithLocalFactory(m.localInformerFactory).
 			WithReadinessFunc(func() bool { return ready }).WithEventBroadcaster(m.eventBroadcaster)
 		reflector.Start(ctx, opts)
 	}
 
+	// Re-enqueue the objects of the namespace of a ReflectionPolicy upon changes, as they might start or stop being selected.
+	_, err := m.localPolicyInformerFactory.Virtualkubelet().V1alpha1().ReflectionPolicies().Informer().
+		AddEventHandler(m.reflectionPoliciesHandler())
+	utilruntime.Must(err)
+
 	// This is a no-op in case no informers/listers have been retrieved.
 	m.localPodInformerFactory.Start(ctx.Done())
 	m.localPodInformerFactory.WaitForCacheSync(ctx.Done())
+	m.localInformerFactory.Start(ctx.Done())
+	m.localInformerFactory.WaitForCacheSync(ctx.Done())
+	m.localPolicyInformerFactory.Start(ctx.Done())
+	m.localPolicyInformerFactory.WaitForCacheSync(ctx.Done())
 
 	m.started = true
 
@@ -143,6 +175,29 @@ func (m *manager) Start(ctx context.Context) {
 	}()
 }
 
+// reflectionPoliciesHandler returns the handler triggering the resync of the namespace of the given ReflectionPolicy in
+// every reflector. All the objects of the namespace are re-enqueued, as the ones no longer selected must be processed as well.
+func (m *manager) reflectionPoliciesHandler() cache.ResourceEventHandler {
+	resync := func(obj interface{}) {
+		key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+		utilruntime.Must(err)
+		namespace, _, err := cache.SplitMetaNamespaceKey(key)
+		utilruntime.Must(err)
+
+		for _, reflector := range m.reflectors {
+			if err := reflector.ResyncNamespace(namespace); err != nil {
+				klog.Errorf("Error while resyncing the %s reflector for local namespace %q: %s", reflector, namespace, err)
+			}
+		}
+	}
+
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    resync,
+		UpdateFunc: func(_, obj interface{}) { resync(obj) },
+		DeleteFunc: resync,
+	}
+}
+
 // StartNamespace starts the reflection for a given namespace.
 func (m *manager) StartNamespace(local, remote string) {
 	m.Lock()
@@ -165,17 +220,20 @@ func (m *manager) StartNamespace(local, remote string) {
 	// The local informer factories, which select all resources in the given namespace.
 	localFactory := informers.NewSharedInformerFactoryWithOptions(m.local, m.resync, informers.WithNamespace(local))
 	localLiqoFactory := liqoinformers.NewSharedInformerFactoryWithOptions(m.localLiqo, m.resync, liqoinformers.WithNamespace(local))
+	localDynamicFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(m.localDynamic, m.resync, local, nil)
 
 	// The remote informer factories, which select all resources in the given namespace.
 	// We do not filter the resources by label selector, to be able to abort reflection in case the remote object already exists.
 	remoteFactory := informers.NewSharedInformerFactoryWithOptions(m.remote, m.resync, informers.WithNamespace(remote))
 	remoteLiqoFactory := liqoinformers.NewSharedInformerFactoryWithOptions(m.remoteLiqo, m.resync, liqoinformers.WithNamespace(remote))
+	remoteDynamicFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(m.remoteDynamic, m.resync, remote, nil)
 
 	ready := false
 	for _, reflector := range m.reflectors {
 		opts := options.NewNamespaced().
 			WithLocal(local, m.local, localFactory).WithLiqoLocal(m.localLiqo, localLiqoFactory).
 			WithRemote(remote, m.remote, remoteFactory).WithLiqoRemote(m.remoteLiqo, remoteLiqoFactory).
+			WithDynamicLocal(m.localDynamic, localDynamicFactory).WithDynamicRemote(m.remoteDynamic, remoteDynamicFactory).
 			WithReadinessFunc(func() bool { return ready }).WithEventBroadcaster(m.eventBroadcaster).
 			WithForgingOpts(&m.forgingOpts)
 		reflector.StartNamespace(opts)
@@ -191,6 +249,12 @@ func (m *manager) StartNamespace(local, remote string) {
 		localLiqoFactory.Start(ctx.Done())
 		remoteFactory.Start(ctx.Done())
 		remoteLiqoFactory.Start(ctx.Done())
+		localDynamicFactory.Start(ctx.Done())
+		remoteDynamicFactory.Start(ctx.Done())
+
+		// The dynamic factories (i.e., the custom resources) are excluded from the readiness gate, as they might never sync
+		// in case the corresponding CRD is missing, and each custom resource reflector waits for its own informers to sync.
+		go waitForDynamicCacheSync(ctx, local, localDynamicFactory, remoteDynamicFactory)
 
 		localFactory.WaitForCacheSync(ctx.Done())
 		localLiqoFactory.WaitForCacheSync(ctx.Done())
@@ -211,6 +275,22 @@ func (m *manager) StartNamespace(local, remote string) {
 	}()
 }
 
+// waitForDynamicCacheSync waits for the caches of the given dynamic factories to sync, warning about the ones which
+// did not complete within the timeout (e.g., because the corresponding CRD is not installed in either cluster).
+func waitForDynamicCacheSync(ctx context.Context, local string, factories ...dynamicinformer.DynamicSharedInformerFactory) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, dynamicCacheSyncTimeout)
+	defer cancel()
+
+	for _, factory := range factories {
+		for gvr, synced := range factory.WaitForCacheSync(timeoutCtx.Done()) {
+			if !synced && ctx.Err() == nil {
+				klog.Warningf("Failed to sync the %v cache for local namespace %q within %v: is the corresponding CRD installed?",
+					gvr.GroupResource(), local, dynamicCacheSyncTimeout)
+			}
+		}
+	}
+}
+
 // StopNamespace stops the reflection for a given namespace.
 func (m *manager) StopNamespace(local, remote string) {
 	m.Lock()
@@ -20,10 +20,15 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/record"
 
+	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
 	liqoclient "github.com/liqotech/liqo/pkg/client/clientset/versioned"
 	liqoclientfake "github.com/liqotech/liqo/pkg/client/clientset/versioned/fake"
 	reflectionfake "github.com/liqotech/liqo/pkg/virtualKubelet/reflection/generic/fake"
@@ -41,6 +46,8 @@ var _ = Describe("Manager tests", func() {
 		remoteClient            kubernetes.Interface
 		localLiqoClient         liqoclient.Interface
 		remoteLiqoClient        liqoclient.Interface
+		localDynamicClient      dynamic.Interface
+		remoteDynamicClient     dynamic.Interface
 		broadcaster             record.EventBroadcaster
 		labelsNotReflected      []string
 		annotationsNotReflected []string
@@ -55,12 +62,15 @@ var _ = Describe("Manager tests", func() {
 		remoteClient = fake.NewSimpleClientset()
 		localLiqoClient = liqoclientfake.NewSimpleClientset()
 		remoteLiqoClient = liqoclientfake.NewSimpleClientset()
+		localDynamicClient = dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+		remoteDynamicClient = dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
 		broadcaster = record.NewBroadcaster()
 	})
 	AfterEach(func() { cancel() })
 
 	JustBeforeEach(func() {
-		mgr = New(localClient, remoteClient, localLiqoClient, remoteLiqoClient, 1*time.Hour, broadcaster, labelsNotReflected, annotationsNotReflected)
+		mgr = New(localClient, remoteClient, localLiqoClient, remoteLiqoClient, localDynamicClient, remoteDynamicClient,
+			1*time.Hour, broadcaster, labelsNotReflected, annotationsNotReflected)
 	})
 
 	Context("a new manager is created", func() {
@@ -70,6 +80,8 @@ var _ = Describe("Manager tests", func() {
 			Expect(mgr.(*manager).remote).To(Equal(remoteClient))
 			Expect(mgr.(*manager).localLiqo).To(Equal(localLiqoClient))
 			Expect(mgr.(*manager).remoteLiqo).To(Equal(remoteLiqoClient))
+			Expect(mgr.(*manager).localDynamic).To(Equal(localDynamicClient))
+			Expect(mgr.(*manager).remoteDynamic).To(Equal(remoteDynamicClient))
 			Expect(mgr.(*manager).resync).To(Equal(1 * time.Hour))
 			Expect(mgr.(*manager).eventBroadcaster).To(Equal(broadcaster))
 
@@ -77,11 +89,13 @@ var _ = Describe("Manager tests", func() {
 
 			Expect(mgr.(*manager).reflectors).ToNot(BeNil())
 			Expect(mgr.(*manager).localPodInformerFactory).ToNot(BeNil())
+			Expect(mgr.(*manager).localPolicyInformerFactory).ToNot(BeNil())
 
 			Expect(mgr.(*manager).started).To(BeFalse())
 			Expect(mgr.(*manager).stop).ToNot(BeNil())
 
 			Expect(mgr.(*manager).forgingOpts).ToNot(BeNil())
+			Expect(mgr.(*manager).forgingOpts.ReflectionPolicies).ToNot(BeNil())
 		})
 
 		Context("a NamespaceMapEventHandler is registered", func() {
@@ -163,6 +177,18 @@ var _ = Describe("Manager tests", func() {
 						Eventually(reflector.NamespaceStarted[localNamespace].Ready).Should(BeTrue())
 					})
 
+					Context("a ReflectionPolicy is created in the namespace", func() {
+						JustBeforeEach(func() {
+							policy := &vkv1alpha1.ReflectionPolicy{ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: localNamespace}}
+							_, err := localLiqoClient.VirtualkubeletV1alpha1().ReflectionPolicies(localNamespace).Create(ctx, policy, metav1.CreateOptions{})
+							Expect(err).ToNot(HaveOccurred())
+						})
+
+						It("should trigger the resync of the namespace in the registered reflector", func() {
+							Eventually(func() int { return reflector.NamespaceResynced(localNamespace) }).Should(BeNumerically(">", 0))
+						})
+					})
+
 					Context("the same namespace is stopped", func() {
 						JustBeforeEach(func() { mgr.StopNamespace(localNamespace, remoteNamespace) })
 
@@ -18,6 +18,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
 	corev1informers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
@@ -40,6 +42,8 @@ type EventFilter func(watch.EventType) bool
 type ReflectorOpts struct {
 	LocalClient      kubernetes.Interface
 	LocalPodInformer corev1informers.PodInformer
+	// LocalFactory is the cluster-wide informer factory retrieving the local objects across all namespaces.
+	LocalFactory     informers.SharedInformerFactory
 	EventBroadcaster record.EventBroadcaster
 
 	HandlerFactory func(Keyer, ...EventFilter) cache.ResourceEventHandler
@@ -58,6 +62,12 @@ func (ro *ReflectorOpts) WithHandlerFactory(handler func(Keyer, ...EventFilter)
 	return ro
 }
 
+// WithLocalFactory configures the cluster-wide local informer factory of the ReflectorOpts.
+func (ro *ReflectorOpts) WithLocalFactory(factory informers.SharedInformerFactory) *ReflectorOpts {
+	ro.LocalFactory = factory
+	return ro
+}
+
 // WithReadinessFunc configures the readiness function of the ReflectorOpts.
 func (ro *ReflectorOpts) WithReadinessFunc(ready func() bool) *ReflectorOpts {
 	ro.Ready = ready
@@ -75,15 +85,19 @@ type NamespacedOpts struct {
 	LocalNamespace  string
 	RemoteNamespace string
 
-	LocalClient      kubernetes.Interface
-	RemoteClient     kubernetes.Interface
-	LocalLiqoClient  liqoclient.Interface
-	RemoteLiqoClient liqoclient.Interface
+	LocalClient         kubernetes.Interface
+	RemoteClient        kubernetes.Interface
+	LocalLiqoClient     liqoclient.Interface
+	RemoteLiqoClient    liqoclient.Interface
+	LocalDynamicClient  dynamic.Interface
+	RemoteDynamicClient dynamic.Interface
 
-	LocalFactory      informers.SharedInformerFactory
-	RemoteFactory     informers.SharedInformerFactory
-	LocalLiqoFactory  liqoinformers.SharedInformerFactory
-	RemoteLiqoFactory liqoinformers.SharedInformerFactory
+	LocalFactory         informers.SharedInformerFactory
+	RemoteFactory        informers.SharedInformerFactory
+	LocalLiqoFactory     liqoinformers.SharedInformerFactory
+	RemoteLiqoFactory    liqoinformers.SharedInformerFactory
+	LocalDynamicFactory  dynamicinformer.DynamicSharedInformerFactory
+	RemoteDynamicFactory dynamicinformer.DynamicSharedInformerFactory
 
 	EventBroadcaster record.EventBroadcaster
 
@@ -114,6 +128,13 @@ func (ro *NamespacedOpts) WithLiqoLocal(client liqoclient.Interface, factory liq
 	return ro
 }
 
+// WithDynamicLocal configures the local dynamic client and informer factory parameters of the NamespacedOpts.
+func (ro *NamespacedOpts) WithDynamicLocal(client dynamic.Interface, factory dynamicinformer.DynamicSharedInformerFactory) *NamespacedOpts {
+	ro.LocalDynamicClient = client
+	ro.LocalDynamicFactory = factory
+	return ro
+}
+
 // WithRemote configures the remote parameters of the NamespacedOpts.
 func (ro *NamespacedOpts) WithRemote(namespace string, client kubernetes.Interface, factory informers.SharedInformerFactory) *NamespacedOpts {
 	ro.RemoteNamespace = namespace
@@ -129,6 +150,13 @@ func (ro *NamespacedOpts) WithLiqoRemote(client liqoclient.Interface, factory li
 	return ro
 }
 
+// WithDynamicRemote configures the remote dynamic client and informer factory parameters of the NamespacedOpts.
+func (ro *NamespacedOpts) WithDynamicRemote(client dynamic.Interface, factory dynamicinformer.DynamicSharedInformerFactory) *NamespacedOpts {
+	ro.RemoteDynamicClient = client
+	ro.RemoteDynamicFactory = factory
+	return ro
+}
+
 // WithHandlerFactory configures the handler factory of the NamespacedOpts.
 func (ro *NamespacedOpts) WithHandlerFactory(handler func(Keyer, ...EventFilter) cache.ResourceEventHandler) *NamespacedOpts {
 	ro.HandlerFactory = handler
@@ -326,9 +326,8 @@ func (npr *NamespacedPodReflector) ForgeShadowPod(ctx context.Context, local *co
 
 	// Forge the target shadowpod object.
 	target := forge.RemoteShadowPod(local, shadow, npr.RemoteNamespace(), forgingOpts,
-		forge.APIServerSupportMutator(npr.config.APIServerSupport, local.Annotations, pod.ServiceAccountName(local),
-			saSecretRetriever, ipGetter, npr.config.HomeAPIServerHost, npr.config.HomeAPIServerPort),
-		forge.ServiceAccountMutator(npr.config.APIServerSupport, local.Annotations))
+		forge.APIServerSupportMutators(local, npr.config.APIServerSupport, saSecretRetriever, ipGetter,
+			npr.config.HomeAPIServerHost, npr.config.HomeAPIServerPort)...)
 
 	// Check whether an error occurred during secret name retrieval.
 	if saerr != nil {
@@ -0,0 +1,292 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	corev1clients "k8s.io/client-go/kubernetes/typed/core/v1"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/trace"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/generic"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/manager"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/options"
+)
+
+var _ manager.NamespacedReflector = (*NamespacedStatefulSetReflector)(nil)
+
+const (
+	// StatefulSetReflectorName -> The name associated with the StatefulSet reflector.
+	StatefulSetReflectorName = "StatefulSet"
+
+	// EventFailedClaimBinding -> the reason for the event when a claim of a StatefulSet pod is not bound on the remote cluster.
+	EventFailedClaimBinding = "FailedClaimBinding"
+
+	// annSelectedNode is the annotation set by the scheduler on PersistentVolumeClaims to identify the target node.
+	annSelectedNode = "volume.kubernetes.io/selected-node"
+)
+
+// NamespacedStatefulSetReflector manages the reflection of the identity of the pods belonging to StatefulSets,
+// for a given pair of local and remote namespaces. In particular, it ensures that the governing (headless) services
+// are present in the remote cluster, so that the offloaded pods can be reached through their stable hostnames,
+// and reports the PersistentVolumeClaims bound to the virtual node which have not been provisioned remotely (without
+// enforcing their binding, which is up to the virtual storage class provisioner).
+// Items are keyed by the name of the governing service, as multiple StatefulSets may share the same one.
+type NamespacedStatefulSetReflector struct {
+	generic.NamespacedReflector
+
+	localStatefulSets            appsv1listers.StatefulSetNamespaceLister
+	localServices                corev1listers.ServiceNamespaceLister
+	remoteServices               corev1listers.ServiceNamespaceLister
+	remoteServicesClient         corev1clients.ServiceInterface
+	localPersistentVolumeClaims  corev1listers.PersistentVolumeClaimNamespaceLister
+	remotePersistentVolumeClaims corev1listers.PersistentVolumeClaimNamespaceLister
+	localClaimsClient            corev1clients.PersistentVolumeClaimInterface
+}
+
+// NewStatefulSetReflector returns a new StatefulSetReflector instance.
+func NewStatefulSetReflector(reflectorConfig *generic.ReflectorConfig) manager.Reflector {
+	return generic.NewReflector(StatefulSetReflectorName, NewNamespacedStatefulSetReflector, generic.WithoutFallback(),
+		reflectorConfig.NumWorkers, reflectorConfig.Type, generic.ConcurrencyModeLeader)
+}
+
+// GoverningServiceKeyer returns a keyer associated with the given namespace, which maps
+// StatefulSets to the name of the corresponding governing service.
+func GoverningServiceKeyer(namespace string) func(metadata metav1.Object) []types.NamespacedName {
+	return func(metadata metav1.Object) []types.NamespacedName {
+		sts, ok := metadata.(*appsv1.StatefulSet)
+		if !ok || sts.Spec.ServiceName == "" {
+			return nil
+		}
+		return []types.NamespacedName{{Namespace: namespace, Name: sts.Spec.ServiceName}}
+	}
+}
+
+// NewNamespacedStatefulSetReflector returns a new NamespacedStatefulSetReflector instance.
+func NewNamespacedStatefulSetReflector(opts *options.NamespacedOpts) manager.NamespacedReflector {
+	localStatefulSets := opts.LocalFactory.Apps().V1().StatefulSets()
+	localServices := opts.LocalFactory.Core().V1().Services()
+	remoteServices := opts.RemoteFactory.Core().V1().Services()
+	localPVCs := opts.LocalFactory.Core().V1().PersistentVolumeClaims()
+	remotePVCs := opts.RemoteFactory.Core().V1().PersistentVolumeClaims()
+
+	// Using opts.LocalNamespace for all event handlers so that the object will be put in the same workqueue
+	// no matter the cluster, hence it will be processed by the handle function in the same way.
+	_, err := localStatefulSets.Informer().AddEventHandler(opts.HandlerFactory(GoverningServiceKeyer(opts.LocalNamespace)))
+	utilruntime.Must(err)
+	_, err = localServices.Informer().AddEventHandler(opts.HandlerFactory(generic.NamespacedKeyer(opts.LocalNamespace)))
+	utilruntime.Must(err)
+	_, err = remoteServices.Informer().AddEventHandler(opts.HandlerFactory(generic.NamespacedKeyer(opts.LocalNamespace)))
+	utilruntime.Must(err)
+
+	return &NamespacedStatefulSetReflector{
+		NamespacedReflector:          generic.NewNamespacedReflector(opts, StatefulSetReflectorName),
+		localStatefulSets:            localStatefulSets.Lister().StatefulSets(opts.LocalNamespace),
+		localServices:                localServices.Lister().Services(opts.LocalNamespace),
+		remoteServices:               remoteServices.Lister().Services(opts.RemoteNamespace),
+		remoteServicesClient:         opts.RemoteClient.CoreV1().Services(opts.RemoteNamespace),
+		localPersistentVolumeClaims:  localPVCs.Lister().PersistentVolumeClaims(opts.LocalNamespace),
+		remotePersistentVolumeClaims: remotePVCs.Lister().PersistentVolumeClaims(opts.RemoteNamespace),
+		localClaimsClient:            opts.LocalClient.CoreV1().PersistentVolumeClaims(opts.LocalNamespace),
+	}
+}
+
+// Handle reconciles the governing service with the given name, and the StatefulSets referring to it.
+func (nsr *NamespacedStatefulSetReflector) Handle(ctx context.Context, name string) error {
+	tracer := trace.FromContext(ctx)
+
+	// Retrieve the local and remote objects (only not found errors can occur).
+	klog.V(4).Infof("Handling reflection of local governing Service %q (remote: %q)", nsr.LocalRef(name), nsr.RemoteRef(name))
+	governed, err := nsr.governedStatefulSets(name)
+	if err != nil {
+		klog.Errorf("Failed to retrieve the StatefulSets governed by local Service %q: %v", nsr.LocalRef(name), err)
+		return err
+	}
+	local, lerr := nsr.localServices.Get(name)
+	utilruntime.Must(client.IgnoreNotFound(lerr))
+	remote, rerr := nsr.remoteServices.Get(name)
+	utilruntime.Must(client.IgnoreNotFound(rerr))
+	tracer.Step("Retrieved the local and remote objects")
+
+	// Abort the reflection if the remote object is not managed by us, as we do not want to mutate others' objects.
+	if rerr == nil && !forge.IsReflected(remote) {
+		if lerr == nil && len(governed) > 0 {
+			klog.Infof("Skipping reflection of local governing Service %q as remote already exists and is not managed by us", nsr.LocalRef(name))
+			nsr.Event(local, corev1.EventTypeWarning, forge.EventFailedReflection, forge.EventFailedReflectionAlreadyExistsMsg())
+		}
+		return nil
+	}
+	tracer.Step("Performed the sanity checks")
+
+	// The service does no longer govern any StatefulSet (or it is not suitable to do that).
+	// Ensure it is absent from the remote cluster, in case it had been reflected as governing service.
+	if kerrors.IsNotFound(lerr) || len(governed) == 0 || !forge.IsHeadlessService(local) {
+		defer tracer.Step("Ensured the absence of the remote object")
+		if !kerrors.IsNotFound(rerr) && forge.IsGoverningService(remote) && forge.IsServiceReflected(remote) {
+			// The remote object is reflected by the service reflection as well. Hence, relinquish only
+			// the fields owned by the StatefulSet reflection, and leave the object to the former.
+			klog.V(4).Infof("Releasing remote governing Service %q, since local %q does no longer govern any StatefulSet",
+				nsr.RemoteRef(name), nsr.LocalRef(name))
+			if _, err := nsr.remoteServicesClient.Apply(ctx, forge.ReleasedGoverningService(name, nsr.RemoteNamespace()),
+				forge.StatefulSetApplyOptions()); err != nil {
+				klog.Errorf("Failed to release remote governing Service %q (local: %q): %v", nsr.RemoteRef(name), nsr.LocalRef(name), err)
+				return err
+			}
+			return nil
+		}
+
+		if !kerrors.IsNotFound(rerr) && forge.IsGoverningService(remote) {
+			klog.V(4).Infof("Deleting remote governing Service %q, since local %q does no longer govern any StatefulSet",
+				nsr.RemoteRef(name), nsr.LocalRef(name))
+			return nsr.DeleteRemote(ctx, nsr.remoteServicesClient, StatefulSetReflectorName, name, remote.GetUID())
+		}
+
+		klog.V(4).Infof("Local Service %q does not govern any reflected StatefulSet", nsr.LocalRef(name))
+		return nil
+	}
+
+	// Forge the mutation to be applied to the remote cluster.
+	mutation := forge.RemoteGoverningService(local, nsr.RemoteNamespace(), nsr.ForgingOpts)
+	tracer.Step("Remote mutation created")
+
+	if _, err := nsr.remoteServicesClient.Apply(ctx, mutation, forge.StatefulSetApplyOptions()); err != nil {
+		klog.Errorf("Failed to enforce remote governing Service %q (local: %q): %v", nsr.RemoteRef(name), nsr.LocalRef(name), err)
+		nsr.Event(local, corev1.EventTypeWarning, forge.EventFailedReflection, forge.EventFailedReflectionMsg(err))
+		return err
+	}
+	tracer.Step("Enforced the correctness of the remote object")
+	klog.Infof("Remote governing Service %q successfully enforced (local: %q)", nsr.RemoteRef(name), nsr.LocalRef(name))
+
+	for _, sts := range governed {
+		if err := nsr.checkClaims(ctx, sts); err != nil {
+			klog.Errorf("Failed to verify the claims of local StatefulSet %q: %v", nsr.LocalRef(sts.GetName()), err)
+			return err
+		}
+	}
+	tracer.Step("Verified the claims of the governed StatefulSets")
+
+	return nil
+}
+
+// governedStatefulSets returns the StatefulSets referring to the given governing service, and enabled for reflection.
+func (nsr *NamespacedStatefulSetReflector) governedStatefulSets(service string) ([]*appsv1.StatefulSet, error) {
+	statefulsets, err := nsr.localStatefulSets.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	var governed []*appsv1.StatefulSet
+	for _, sts := range statefulsets {
+		if sts.Spec.ServiceName != service {
+			continue
+		}
+
+		skipReflection, err := nsr.ShouldSkipReflection(sts)
+		if err != nil {
+			return nil, err
+		}
+		if skipReflection {
+			klog.V(4).Infof("Skipping reflection of local StatefulSet %q as disabled by the reflection policy", nsr.LocalRef(sts.GetName()))
+			continue
+		}
+		governed = append(governed, sts)
+	}
+	return governed, nil
+}
+
+// checkClaims verifies that the claims of the given StatefulSet scheduled on the current virtual node have been
+// provisioned in the remote cluster, and generates a warning event otherwise. To avoid repeating it at every resync,
+// the event is generated once per claim, which is annotated accordingly until the remote counterpart appears.
+func (nsr *NamespacedStatefulSetReflector) checkClaims(ctx context.Context, sts *appsv1.StatefulSet) error {
+	for i := range sts.Spec.VolumeClaimTemplates {
+		for _, ordinal := range forge.StatefulSetOrdinals(sts) {
+			name := forge.StatefulSetClaimName(sts.Spec.VolumeClaimTemplates[i].GetName(), sts, ordinal)
+			local, err := nsr.localPersistentVolumeClaims.Get(name)
+			if err != nil || local.GetAnnotations()[annSelectedNode] != forge.LiqoNodeName {
+				// The claim does not exist yet, or it is not bound to the current virtual node.
+				continue
+			}
+
+			_, reported := local.GetAnnotations()[consts.RemoteClaimNotProvisionedAnnotation]
+			_, err = nsr.remotePersistentVolumeClaims.Get(name)
+			switch {
+			case kerrors.IsNotFound(err) && !reported:
+				msg := fmt.Sprintf("PersistentVolumeClaim %q of pod with ordinal %d not yet provisioned in cluster %q",
+					name, ordinal, forge.RemoteCluster.ClusterName)
+				klog.Warningf("%s (StatefulSet: %q)", msg, nsr.LocalRef(sts.GetName()))
+				if err := nsr.annotateClaim(ctx, name, forge.RemoteCluster.ClusterID); err != nil {
+					return err
+				}
+				nsr.Event(sts, corev1.EventTypeWarning, EventFailedClaimBinding, msg)
+			case err == nil && reported:
+				klog.V(4).Infof("PersistentVolumeClaim %q provisioned in the remote cluster (StatefulSet: %q)", name, nsr.LocalRef(sts.GetName()))
+				if err := nsr.annotateClaim(ctx, name, nil); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// annotateClaim sets the given value for the annotation recording that the local claim has been reported
+// as not provisioned in the remote cluster. A nil value removes the annotation.
+func (nsr *NamespacedStatefulSetReflector) annotateClaim(ctx context.Context, name string, value interface{}) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{consts.RemoteClaimNotProvisionedAnnotation: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := nsr.localClaimsClient.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to annotate local PersistentVolumeClaim %q: %w", nsr.LocalRef(name), err)
+	}
+	return nil
+}
+
+// List returns the list of governing services to be reflected.
+func (nsr *NamespacedStatefulSetReflector) List() ([]interface{}, error) {
+	statefulsets, err := nsr.localStatefulSets.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	keyer := GoverningServiceKeyer(nsr.LocalNamespace())
+	list := make([]interface{}, 0, len(statefulsets))
+	for _, sts := range statefulsets {
+		for _, key := range keyer(sts) {
+			list = append(list, key)
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,244 @@
+// Copyright 2019-2023 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/trace"
+
+	"github.com/liqotech/liqo/cmd/virtual-kubelet/root"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils/testutil"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/generic"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/manager"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/options"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/workload"
+)
+
+var _ = Describe("StatefulSet Reflection Tests", func() {
+	Describe("the NewStatefulSetReflector function", func() {
+		It("should not return a nil reflector", func() {
+			reflectorConfig := generic.ReflectorConfig{
+				NumWorkers: 1,
+				Type:       root.DefaultReflectorsTypes[generic.StatefulSet],
+			}
+			Expect(workload.NewStatefulSetReflector(&reflectorConfig)).ToNot(BeNil())
+		})
+	})
+
+	Describe("the GoverningServiceKeyer function", func() {
+		It("should return the key of the governing service", func() {
+			sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "foo"},
+				Spec: appsv1.StatefulSetSpec{ServiceName: "nginx"}}
+			Expect(workload.GoverningServiceKeyer(LocalNamespace)(sts)).To(ConsistOf(
+				types.NamespacedName{Namespace: LocalNamespace, Name: "nginx"}))
+		})
+		It("should return no keys if the governing service is not set", func() {
+			sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "foo"}}
+			Expect(workload.GoverningServiceKeyer(LocalNamespace)(sts)).To(BeEmpty())
+		})
+	})
+
+	Describe("governing service handling", func() {
+		const (
+			ServiceName     = "nginx"
+			StatefulSetName = "web"
+		)
+
+		var (
+			reflector      manager.NamespacedReflector
+			reflectionType consts.ReflectionType
+			client         *fake.Clientset
+
+			local, remote corev1.Service
+			sts           appsv1.StatefulSet
+			applied       bool
+			err           error
+		)
+
+		BeforeEach(func() {
+			local = corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: ServiceName, Namespace: LocalNamespace},
+				Spec: corev1.ServiceSpec{ClusterIP: corev1.ClusterIPNone}}
+			remote = corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: ServiceName, Namespace: RemoteNamespace}}
+			sts = appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: StatefulSetName, Namespace: LocalNamespace},
+				Spec: appsv1.StatefulSetSpec{ServiceName: ServiceName}}
+			reflectionType = root.DefaultReflectorsTypes[generic.StatefulSet]
+			client = fake.NewSimpleClientset()
+			applied = false
+		})
+
+		JustBeforeEach(func() {
+			// Server side apply is not supported by the fake client, hence we intercept the corresponding patch.
+			client.PrependReactor("patch", "services", func(action testing.Action) (bool, runtime.Object, error) {
+				applied = true
+				return true, &corev1.Service{}, nil
+			})
+
+			factory := informers.NewSharedInformerFactory(client, 10*time.Hour)
+			reflector = workload.NewNamespacedStatefulSetReflector(options.NewNamespaced().
+				WithLocal(LocalNamespace, client, factory).
+				WithRemote(RemoteNamespace, client, factory).
+				WithHandlerFactory(FakeEventHandler).
+				WithEventBroadcaster(record.NewBroadcaster()).
+				WithReflectionType(reflectionType).
+				WithForgingOpts(testutil.FakeForgingOpts()))
+
+			factory.Start(ctx.Done())
+			factory.WaitForCacheSync(ctx.Done())
+
+			err = reflector.Handle(trace.ContextWithTrace(ctx, trace.New("StatefulSet")), ServiceName)
+		})
+
+		CreateObject := func(obj runtime.Object) {
+			Expect(client.Tracker().Add(obj)).To(Succeed())
+		}
+
+		RemoteExists := func() bool {
+			_, errsvc := client.CoreV1().Services(RemoteNamespace).Get(ctx, ServiceName, metav1.GetOptions{})
+			return errsvc == nil
+		}
+
+		When("the local service governs a StatefulSet", func() {
+			BeforeEach(func() {
+				CreateObject(&local)
+				CreateObject(&sts)
+			})
+
+			It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+			It("should enforce the remote governing service", func() { Expect(applied).To(BeTrue()) })
+
+			When("the StatefulSet has the skip annotation", func() {
+				BeforeEach(func() {
+					sts.SetAnnotations(map[string]string{consts.SkipReflectionAnnotationKey: "whatever"})
+					Expect(client.Tracker().Update(appsv1.SchemeGroupVersion.WithResource("statefulsets"), &sts, LocalNamespace)).To(Succeed())
+				})
+
+				It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+				It("should not enforce the remote governing service", func() { Expect(applied).To(BeFalse()) })
+			})
+
+			When("the StatefulSet has a claim bound to the virtual node", func() {
+				var claim corev1.PersistentVolumeClaim
+
+				BeforeEach(func() {
+					sts.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "data"}}}
+					Expect(client.Tracker().Update(appsv1.SchemeGroupVersion.WithResource("statefulsets"), &sts, LocalNamespace)).To(Succeed())
+					claim = corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data-web-0", Namespace: LocalNamespace,
+						Annotations: map[string]string{"volume.kubernetes.io/selected-node": LiqoNodeName}}}
+				})
+
+				GetClaimAnnotations := func() map[string]string {
+					pvc, errpvc := client.CoreV1().PersistentVolumeClaims(LocalNamespace).Get(ctx, claim.GetName(), metav1.GetOptions{})
+					Expect(errpvc).ToNot(HaveOccurred())
+					return pvc.GetAnnotations()
+				}
+
+				When("the claim has not been provisioned remotely", func() {
+					BeforeEach(func() { CreateObject(&claim) })
+
+					It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+					It("should mark the claim as reported", func() {
+						Expect(GetClaimAnnotations()).To(HaveKeyWithValue(consts.RemoteClaimNotProvisionedAnnotation, forge.RemoteCluster.ClusterID))
+					})
+				})
+
+				When("the claim has been provisioned remotely, after being reported", func() {
+					BeforeEach(func() {
+						claim.Annotations[consts.RemoteClaimNotProvisionedAnnotation] = forge.RemoteCluster.ClusterID
+						CreateObject(&claim)
+						CreateObject(&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: claim.GetName(), Namespace: RemoteNamespace}})
+					})
+
+					It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+					It("should remove the reported mark from the claim", func() {
+						Expect(GetClaimAnnotations()).ToNot(HaveKey(consts.RemoteClaimNotProvisionedAnnotation))
+					})
+				})
+			})
+
+			When("the remote object already exists, but is not managed by the reflection", func() {
+				BeforeEach(func() { CreateObject(&remote) })
+
+				It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+				It("should not enforce the remote governing service", func() { Expect(applied).To(BeFalse()) })
+				It("should not delete the remote object", func() { Expect(RemoteExists()).To(BeTrue()) })
+			})
+		})
+
+		When("the local service is not headless", func() {
+			BeforeEach(func() {
+				local.Spec.ClusterIP = ""
+				CreateObject(&local)
+				CreateObject(&sts)
+			})
+
+			It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+			It("should not enforce the remote governing service", func() { Expect(applied).To(BeFalse()) })
+		})
+
+		When("the local service does not govern any StatefulSet", func() {
+			BeforeEach(func() { CreateObject(&local) })
+
+			When("the remote object has been reflected as governing service", func() {
+				BeforeEach(func() {
+					remote.SetLabels(labels.Merge(forge.ReflectionLabels(), map[string]string{forge.LiqoGoverningServiceKey: "true"}))
+					CreateObject(&remote)
+				})
+
+				It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+				It("should delete the remote object", func() { Expect(RemoteExists()).To(BeFalse()) })
+			})
+
+			When("the remote object has been reflected as governing service, and by the service reflection", func() {
+				BeforeEach(func() {
+					remote.SetLabels(labels.Merge(forge.ReflectionLabels(), map[string]string{forge.LiqoGoverningServiceKey: "true"}))
+					remote.SetManagedFields([]metav1.ManagedFieldsEntry{
+						{Manager: forge.ReflectionFieldManager, Operation: metav1.ManagedFieldsOperationApply},
+						{Manager: forge.StatefulSetReflectionFieldManager, Operation: metav1.ManagedFieldsOperationApply},
+					})
+					CreateObject(&remote)
+				})
+
+				It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+				It("should release the fields of the remote object", func() { Expect(applied).To(BeTrue()) })
+				It("should not delete the remote object", func() { Expect(RemoteExists()).To(BeTrue()) })
+			})
+
+			When("the remote object has been reflected by the service reflection", func() {
+				BeforeEach(func() {
+					remote.SetLabels(forge.ReflectionLabels())
+					CreateObject(&remote)
+				})
+
+				It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+				It("should not delete the remote object", func() { Expect(RemoteExists()).To(BeTrue()) })
+			})
+		})
+	})
+})
@@ -75,6 +75,10 @@ func forgeVKContainers(
 		args = append(args, stringifyArgument(string(AnnotationsNotReflected), strings.Join(opts.AnnotationsNotReflected, ",")))
 	}
 
+	for _, config := range opts.CustomResourcesReflection {
+		args = append(args, stringifyArgument(string(CustomResourceReflection), config))
+	}
+
 	if extraAnnotations := opts.NodeExtraAnnotations.StringMap; len(extraAnnotations) != 0 {
 		args = append(args, stringifyArgument(string(NodeExtraAnnotations), opts.NodeExtraAnnotations.String()))
 	}
@@ -23,23 +23,24 @@ import (
 // VirtualKubeletOpts defines the custom options associated with the virtual kubelet deployment forging.
 type VirtualKubeletOpts struct {
 	// ContainerImage contains the virtual kubelet image name and tag.
-	ContainerImage          string
-	ExtraAnnotations        map[string]string
-	ExtraLabels             map[string]string
-	ExtraArgs               []string
-	NodeExtraAnnotations    argsutils.StringMap
-	NodeExtraLabels         argsutils.StringMap
-	RequestsCPU             resource.Quantity
-	LimitsCPU               resource.Quantity
-	RequestsRAM             resource.Quantity
-	LimitsRAM               resource.Quantity
-	IpamEndpoint            string
-	MetricsEnabled          bool
-	MetricsAddress          string
-	ReflectorsWorkers       map[string]*uint
-	ReflectorsType          map[string]*string
-	LabelsNotReflected      []string
-	AnnotationsNotReflected []string
+	ContainerImage            string
+	ExtraAnnotations          map[string]string
+	ExtraLabels               map[string]string
+	ExtraArgs                 []string
+	NodeExtraAnnotations      argsutils.StringMap
+	NodeExtraLabels           argsutils.StringMap
+	RequestsCPU               resource.Quantity
+	LimitsCPU                 resource.Quantity
+	RequestsRAM               resource.Quantity
+	LimitsRAM                 resource.Quantity
+	IpamEndpoint              string
+	MetricsEnabled            bool
+	MetricsAddress            string
+	ReflectorsWorkers         map[string]*uint
+	ReflectorsType            map[string]*string
+	LabelsNotReflected        []string
+	AnnotationsNotReflected   []string
+	CustomResourcesReflection []string
 }
 
 // VirtualKubeletOptsFlag defines the custom options flags associated with the virtual kubelet deployment forging.
@@ -83,4 +84,6 @@ const (
 	LabelsNotReflected VirtualKubeletOptsFlag = "--labels-not-reflected"
 	// AnnotationsNotReflected is the flag used to specify the annotations not reflected.
 	AnnotationsNotReflected VirtualKubeletOptsFlag = "--annotations-not-reflected"
+	// CustomResourceReflection is the flag used to specify the configuration of a custom resource to be reflected.
+	CustomResourceReflection VirtualKubeletOptsFlag = "--custom-resource-reflection"
 )